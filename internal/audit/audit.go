@@ -0,0 +1,105 @@
+// Package audit keeps an append-only record of the packages brewsync installs
+// and uninstalls, for reviewing what changed on shared or managed machines.
+// Unlike the history log it records every package with its outcome and the
+// command that started it, and the file is never rewritten or pruned.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+// Results recorded for an operation
+const (
+	ResultOK     = "ok"
+	ResultFailed = "failed"
+)
+
+// Entry is one install or uninstall, written as a line of JSON
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Machine string    `json:"machine,omitempty"`
+	Command string    `json:"command"`
+	Action  string    `json:"action"`
+	Package string    `json:"package"`
+	Result  string    `json:"result"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Logger appends entries to an audit file
+type Logger struct {
+	path    string
+	machine string
+	command string
+}
+
+// NewLogger creates a logger writing to path. machine and command are
+// recorded with every entry.
+func NewLogger(path, machine, command string) *Logger {
+	return &Logger{path: path, machine: machine, command: command}
+}
+
+// Record appends the outcome of one operation. It has the signature of an
+// installer.OperationHook; write failures are logged and never stop an install.
+func (l *Logger) Record(action string, pkg brewfile.Package, err error) {
+	entry := Entry{
+		Time:    time.Now(),
+		Machine: l.machine,
+		Command: l.command,
+		Action:  action,
+		Package: pkg.ID(),
+		Result:  ResultOK,
+	}
+	if err != nil {
+		entry.Result = ResultFailed
+		entry.Error = err.Error()
+	}
+	if err := l.write(entry); err != nil {
+		debug.Log("audit: %v", err)
+	}
+}
+
+// write appends entry to the audit file
+func (l *Logger) write(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Attach makes mgr record its operations in the audit log when audit.enabled
+// is set. command describes what started them (e.g. "brewsync sync --apply").
+func Attach(mgr *installer.Manager, cfg *config.Config, command string) {
+	if cfg == nil || !cfg.Audit.Enabled {
+		return
+	}
+	mgr.SetHook(NewLogger(config.AuditPath(), cfg.CurrentMachine, command).Record)
+}
+
+// CommandLine returns the brewsync command line of the running process
+func CommandLine() string {
+	return strings.TrimSpace("brewsync " + strings.Join(os.Args[1:], " "))
+}
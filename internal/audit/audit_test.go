@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+// readEntries parses the audit file at path
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestAttach_RecordsInstallAndUninstall(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv(config.HomeEnvVar, state)
+
+	// brew installs anything but can't uninstall htop
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "uninstall" ] && [ "$3" = "htop" ]; then echo "Error: htop is required by btop" >&2; exit 1; fi
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir)
+
+	jq := brewfile.NewPackage(brewfile.TypeBrew, "jq")
+	htop := brewfile.NewPackage(brewfile.TypeBrew, "htop")
+
+	// Disabled: nothing is written
+	mgr := installer.NewManager()
+	Attach(mgr, &config.Config{CurrentMachine: "mini"}, "brewsync import --yes")
+	require.NoError(t, mgr.Install(jq))
+	assert.NoFileExists(t, config.AuditPath())
+
+	cfg := &config.Config{CurrentMachine: "mini", Audit: config.AuditConfig{Enabled: true}}
+	mgr = installer.NewManager()
+	Attach(mgr, cfg, "brewsync import --yes")
+	require.NoError(t, mgr.Install(jq))
+
+	mgr = installer.NewManager()
+	Attach(mgr, cfg, "brewsync sync --apply")
+	require.NoError(t, mgr.Uninstall(jq))
+	require.Error(t, mgr.Uninstall(htop))
+
+	assert.Equal(t, filepath.Join(state, "audit.log"), config.AuditPath())
+	entries := readEntries(t, config.AuditPath())
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "mini", entries[0].Machine)
+	assert.Equal(t, "brewsync import --yes", entries[0].Command)
+	assert.Equal(t, installer.ActionInstall, entries[0].Action)
+	assert.Equal(t, "brew:jq", entries[0].Package)
+	assert.Equal(t, ResultOK, entries[0].Result)
+	assert.False(t, entries[0].Time.IsZero())
+
+	assert.Equal(t, "brewsync sync --apply", entries[1].Command)
+	assert.Equal(t, installer.ActionUninstall, entries[1].Action)
+	assert.Equal(t, ResultOK, entries[1].Result)
+	assert.Empty(t, entries[1].Error)
+
+	assert.Equal(t, "brew:htop", entries[2].Package)
+	assert.Equal(t, ResultFailed, entries[2].Result)
+	assert.Contains(t, entries[2].Error, "htop is required by btop")
+}
@@ -0,0 +1,19 @@
+// Package brewfiletest provides package fixtures for tests
+package brewfiletest
+
+import (
+	"fmt"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// ManyPackages returns n packages named pkg-0000, pkg-0001, ... spread over
+// the brew, cask and vscode types
+func ManyPackages(n int) brewfile.Packages {
+	types := []brewfile.PackageType{brewfile.TypeBrew, brewfile.TypeCask, brewfile.TypeVSCode}
+	pkgs := make(brewfile.Packages, n)
+	for i := range pkgs {
+		pkgs[i] = brewfile.NewPackage(types[i%len(types)], fmt.Sprintf("pkg-%04d", i))
+	}
+	return pkgs
+}
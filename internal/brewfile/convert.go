@@ -0,0 +1,69 @@
+package brewfile
+
+import (
+	"bufio"
+	"strings"
+)
+
+// BundleTypes are the package types plain 'brew bundle' understands.
+// Converting a brewsync Brewfile to bundle format drops every other type
+// (cursor, antigravity, go), so those conversions are lossy.
+var BundleTypes = []PackageType{TypeTap, TypeBrew, TypeCask, TypeMas, TypeVSCode}
+
+// IsBundleType returns true if 'brew bundle' can install packages of this type
+func IsBundleType(t PackageType) bool {
+	for _, bt := range BundleTypes {
+		if bt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ToBundle splits packages into those a plain 'brew bundle' Brewfile can hold
+// and those that would be dropped (brewsync extension types)
+func ToBundle(pkgs Packages) (kept, dropped Packages) {
+	for _, pkg := range pkgs {
+		if IsBundleType(pkg.Type) {
+			kept = append(kept, pkg)
+		} else {
+			dropped = append(dropped, pkg)
+		}
+	}
+	return kept, dropped
+}
+
+// FormatBundle returns a plain 'brew bundle'-compatible Brewfile for the packages
+// along with the packages that were dropped
+func FormatBundle(pkgs Packages) (string, Packages) {
+	kept, dropped := ToBundle(pkgs)
+	return NewWriter(kept).Format(), dropped
+}
+
+// ParseBundle parses a plain 'brew bundle' Brewfile.
+// It returns the packages brewsync understands and the directive lines it
+// could not convert (e.g. whalebrew, cask_args), so callers can report them.
+func ParseBundle(content string) (Packages, []string, error) {
+	pkgs, err := ParseContent(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parser := NewParser()
+	var unsupported []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, ok := parser.parseLine(line); !ok {
+			unsupported = append(unsupported, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return pkgs, unsupported, nil
+}
@@ -0,0 +1,100 @@
+package brewfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const brewsyncBrewfile = `tap "homebrew/bundle"
+
+# Distributed revision control system
+brew "git"
+brew "postgresql@16", restart_service: true
+
+cask "firefox"
+
+mas "Xcode", id: 497799835
+
+vscode "golang.go"
+
+# cursor (brewsync extension)
+cursor "esbenp.prettier-vscode"
+
+# antigravity (brewsync extension)
+antigravity "ms-python.python"
+
+# go (brewsync extension)
+go "golang.org/x/tools/gopls"
+`
+
+func TestToBundle(t *testing.T) {
+	pkgs, err := ParseContent(brewsyncBrewfile)
+	require.NoError(t, err)
+
+	kept, dropped := ToBundle(pkgs)
+	assert.Equal(t, []string{"homebrew/bundle", "git", "postgresql@16", "firefox", "Xcode", "golang.go"}, kept.Names())
+	assert.Equal(t, []string{"esbenp.prettier-vscode", "ms-python.python", "golang.org/x/tools/gopls"}, dropped.Names())
+}
+
+func TestFormatBundle_RoundTrip(t *testing.T) {
+	pkgs, err := ParseContent(brewsyncBrewfile)
+	require.NoError(t, err)
+
+	content, dropped := FormatBundle(pkgs)
+	assert.Len(t, dropped, 3)
+	assert.NotContains(t, content, "brewsync extension")
+	assert.NotContains(t, content, "cursor ")
+	assert.NotContains(t, content, "go ")
+
+	// Plain bundle output parses back to exactly the supported subset
+	parsed, unsupported, err := ParseBundle(content)
+	require.NoError(t, err)
+	assert.Empty(t, unsupported)
+
+	kept, _ := ToBundle(pkgs)
+	assert.True(t, Diff(kept, parsed).IsEmpty())
+
+	byID := make(map[string]Package)
+	for _, p := range parsed {
+		byID[p.ID()] = p
+	}
+	assert.Equal(t, "Distributed revision control system", byID["brew:git"].Description)
+	assert.Equal(t, "true", byID["brew:postgresql@16"].Options["restart_service"])
+	assert.Equal(t, "497799835", byID["mas:Xcode"].Options["id"])
+
+	// Converting again is stable
+	again, dropped := FormatBundle(parsed)
+	assert.Empty(t, dropped)
+	assert.Equal(t, content, again)
+}
+
+func TestParseBundle_Unsupported(t *testing.T) {
+	content := `cask_args appdir: "~/Applications"
+tap "homebrew/cask-fonts"
+brew "jq"
+whalebrew "whalebrew/wget"
+# a comment
+cask "font-fira-code"
+`
+	pkgs, unsupported, err := ParseBundle(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"homebrew/cask-fonts", "jq", "font-fira-code"}, pkgs.Names())
+	assert.Equal(t, []string{`cask_args appdir: "~/Applications"`, `whalebrew "whalebrew/wget"`}, unsupported)
+
+	// A bundle file converts to brewsync format without loss for supported lines
+	reparsed, err := ParseContent(NewWriter(pkgs).Format())
+	require.NoError(t, err)
+	assert.True(t, Diff(pkgs, reparsed).IsEmpty())
+}
+
+func TestIsBundleType(t *testing.T) {
+	for _, pt := range []PackageType{TypeTap, TypeBrew, TypeCask, TypeMas, TypeVSCode} {
+		assert.True(t, IsBundleType(pt), pt)
+	}
+	for _, pt := range []PackageType{TypeCursor, TypeAntigravity, TypeGo} {
+		assert.False(t, IsBundleType(pt), pt)
+	}
+}
@@ -0,0 +1,92 @@
+package brewfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CountHistoryFileName is the package count history stored next to each Brewfile
+const CountHistoryFileName = ".brewsync-counts"
+
+// MaxCountSnapshots bounds the count history; older snapshots are dropped
+const MaxCountSnapshots = 30
+
+// CountSnapshot is the total package count recorded by one dump
+type CountSnapshot struct {
+	At    time.Time `yaml:"at"`
+	Total int       `yaml:"total"`
+}
+
+// CountHistoryPath returns the count history file path for the given Brewfile
+func CountHistoryPath(brewfilePath string) string {
+	return filepath.Join(filepath.Dir(brewfilePath), CountHistoryFileName)
+}
+
+// LoadCountHistory loads count snapshots, oldest first. A missing file is an empty history.
+func LoadCountHistory(path string) ([]CountSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var history []CountSnapshot
+	if err := yaml.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// AppendCountSnapshot appends a snapshot to the history file, keeping only
+// the newest MaxCountSnapshots entries
+func AppendCountSnapshot(path string, snapshot CountSnapshot) error {
+	history, err := LoadCountHistory(path)
+	if err != nil {
+		// Start over rather than fail the dump on a corrupt history
+		history = nil
+	}
+
+	history = append(history, snapshot)
+	if len(history) > MaxCountSnapshots {
+		history = history[len(history)-MaxCountSnapshots:]
+	}
+
+	data, err := yaml.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// CountTotals returns the totals of a history in order (for sparklines)
+func CountTotals(history []CountSnapshot) []int {
+	totals := make([]int, len(history))
+	for i, snapshot := range history {
+		totals[i] = snapshot.Total
+	}
+	return totals
+}
+
+// CountChangeSince returns how much the total changed since the given time:
+// the latest total minus the last total recorded before since (or the oldest
+// snapshot if all are newer)
+func CountChangeSince(history []CountSnapshot, since time.Time) int {
+	if len(history) == 0 {
+		return 0
+	}
+
+	baseline := history[0].Total
+	for _, snapshot := range history {
+		if snapshot.At.After(since) {
+			break
+		}
+		baseline = snapshot.Total
+	}
+	return history[len(history)-1].Total - baseline
+}
@@ -0,0 +1,70 @@
+package brewfile
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendCountSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CountHistoryFileName)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history, err := LoadCountHistory(path)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, AppendCountSnapshot(path, CountSnapshot{At: start.Add(time.Duration(i) * time.Hour), Total: 100 + i}))
+	}
+
+	history, err = LoadCountHistory(path)
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 101, 102}, CountTotals(history))
+	assert.True(t, history[0].At.Equal(start))
+}
+
+func TestAppendCountSnapshot_Bounded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CountHistoryFileName)
+
+	for i := 0; i < MaxCountSnapshots+5; i++ {
+		require.NoError(t, AppendCountSnapshot(path, CountSnapshot{At: time.Now(), Total: i}))
+	}
+
+	history, err := LoadCountHistory(path)
+	require.NoError(t, err)
+	require.Len(t, history, MaxCountSnapshots)
+	assert.Equal(t, 5, history[0].Total, "oldest snapshots are dropped")
+	assert.Equal(t, MaxCountSnapshots+4, history[len(history)-1].Total)
+}
+
+func TestUpdateMetadata_AppendsCountSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	metaPath := filepath.Join(dir, MetadataFileName)
+	pkgs := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeCask, "firefox")}
+
+	require.NoError(t, UpdateMetadata(metaPath, "mini", pkgs, "dev"))
+	require.NoError(t, UpdateMetadata(metaPath, "mini", append(pkgs, NewPackage(TypeBrew, "jq")), "dev"))
+
+	history, err := LoadCountHistory(CountHistoryPath(filepath.Join(dir, "Brewfile")))
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, CountTotals(history))
+}
+
+func TestCountChangeSince(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	history := []CountSnapshot{
+		{At: now.AddDate(0, 0, -20), Total: 120},
+		{At: now.AddDate(0, 0, -10), Total: 134},
+		{At: now.AddDate(0, 0, -5), Total: 136},
+		{At: now.AddDate(0, 0, -1), Total: 142},
+	}
+
+	assert.Equal(t, 8, CountChangeSince(history, now.AddDate(0, 0, -7)), "change this week")
+	assert.Equal(t, 22, CountChangeSince(history, now.AddDate(0, 0, -30)), "all snapshots newer: oldest is the baseline")
+	assert.Equal(t, 0, CountChangeSince(history, now), "nothing since")
+	assert.Equal(t, 0, CountChangeSince(nil, now))
+}
@@ -1,6 +1,13 @@
 package brewfile
 
-import "strconv"
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// archSuffixes are name fragments used by packages that only exist for one architecture
+var archSuffixes = []string{"-arm64", "-aarch64", "-apple-silicon", "-silicon", "-intel", "-x86_64", "-x86", "-amd64"}
 
 // DiffResult contains the results of comparing two package lists
 type DiffResult struct {
@@ -10,6 +17,9 @@ type DiffResult struct {
 	Removals Packages
 	// Common are packages in both
 	Common Packages
+	// Outdated are common packages installed here with a newer version
+	// available; set by MarkOutdated
+	Outdated []Outdated
 }
 
 // IsEmpty returns true if there are no differences
@@ -31,6 +41,12 @@ func (d *DiffResult) RemovalsByType() map[PackageType][]Package {
 // source: the packages we want to have (e.g., from another machine)
 // current: the packages we currently have
 func Diff(source, current Packages) *DiffResult {
+	return DiffWithAliases(source, current, nil)
+}
+
+// DiffWithAliases computes differences treating aliased editor extensions
+// (config extension_aliases, old ID -> new ID) as the same package
+func DiffWithAliases(source, current Packages, aliases map[string]string) *DiffResult {
 	result := &DiffResult{
 		Additions: make(Packages, 0),
 		Removals:  make(Packages, 0),
@@ -40,20 +56,20 @@ func Diff(source, current Packages) *DiffResult {
 	// Build a map of current packages for quick lookup
 	currentMap := make(map[string]Package)
 	for _, pkg := range current {
-		key := packageKey(pkg)
+		key := pkg.Key(aliases)
 		currentMap[key] = pkg
 	}
 
 	// Build a map of source packages
 	sourceMap := make(map[string]Package)
 	for _, pkg := range source {
-		key := packageKey(pkg)
+		key := pkg.Key(aliases)
 		sourceMap[key] = pkg
 	}
 
 	// Find additions (in source but not in current)
 	for _, pkg := range source {
-		key := packageKey(pkg)
+		key := pkg.Key(aliases)
 		if _, exists := currentMap[key]; !exists {
 			result.Additions = append(result.Additions, pkg)
 		} else {
@@ -63,7 +79,7 @@ func Diff(source, current Packages) *DiffResult {
 
 	// Find removals (in current but not in source)
 	for _, pkg := range current {
-		key := packageKey(pkg)
+		key := pkg.Key(aliases)
 		if _, exists := sourceMap[key]; !exists {
 			result.Removals = append(result.Removals, pkg)
 		}
@@ -74,11 +90,103 @@ func Diff(source, current Packages) *DiffResult {
 
 // DiffByType computes differences filtered to specific package types
 func DiffByType(source, current Packages, types []PackageType) *DiffResult {
+	return DiffByTypeWithAliases(source, current, types, nil)
+}
+
+// DiffByTypeWithAliases is DiffByType with extension aliases (see DiffWithAliases)
+func DiffByTypeWithAliases(source, current Packages, types []PackageType, aliases map[string]string) *DiffResult {
 	// Filter both lists to only include specified types
 	filteredSource := source.Filter(types...)
 	filteredCurrent := current.Filter(types...)
 
-	return Diff(filteredSource, filteredCurrent)
+	return DiffWithAliases(filteredSource, filteredCurrent, aliases)
+}
+
+// MultiDiffResult is a diff of the current packages against several sources at once.
+// Additions are the union of what any source has that current lacks; Removals are
+// packages that no source has.
+type MultiDiffResult struct {
+	*DiffResult
+	// Sources maps each addition's key to the sources that contain it, in order
+	Sources map[string][]string
+
+	aliases map[string]string
+}
+
+// DiffSources computes pending changes against the union of several sources,
+// recording which source(s) each addition comes from
+func DiffSources(sources map[string]Packages, current Packages, aliases map[string]string) *MultiDiffResult {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	currentKeys := make(map[string]bool)
+	for _, pkg := range current {
+		currentKeys[pkg.Key(aliases)] = true
+	}
+
+	result := &MultiDiffResult{
+		DiffResult: &DiffResult{
+			Additions: make(Packages, 0),
+			Removals:  make(Packages, 0),
+			Common:    make(Packages, 0),
+		},
+		Sources: make(map[string][]string),
+		aliases: aliases,
+	}
+
+	sourceKeys := make(map[string]bool)
+	for _, name := range names {
+		for _, pkg := range sources[name] {
+			key := pkg.Key(aliases)
+			if currentKeys[key] {
+				if !sourceKeys[key] {
+					result.Common = append(result.Common, pkg)
+				}
+			} else {
+				if _, seen := result.Sources[key]; !seen {
+					result.Additions = append(result.Additions, pkg)
+				}
+				if !containsString(result.Sources[key], name) {
+					result.Sources[key] = append(result.Sources[key], name)
+				}
+			}
+			sourceKeys[key] = true
+		}
+	}
+
+	for _, pkg := range current {
+		if !sourceKeys[pkg.Key(aliases)] {
+			result.Removals = append(result.Removals, pkg)
+		}
+	}
+
+	return result
+}
+
+// AdditionsBySource groups additions by the source they come from. Pass a filtered
+// subset of Additions (e.g. after removing ignored packages) to group only those.
+// A package present on several sources is listed under each of them.
+func (m *MultiDiffResult) AdditionsBySource(additions Packages) map[string]Packages {
+	result := make(map[string]Packages)
+	for _, pkg := range additions {
+		for _, source := range m.Sources[pkg.Key(m.aliases)] {
+			result[source] = append(result[source], pkg)
+		}
+	}
+	return result
+}
+
+// containsString checks if a slice contains a string
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
 
 // packageKey returns a unique key for a package based on type and name
@@ -104,6 +212,293 @@ func (d *DiffResult) FilterMachineSpecific(machinePackages map[string]bool) *Dif
 	}
 }
 
+// FilterPinned removes pinned packages from removals (pinned packages may still be added).
+// It returns the filtered result and the pinned packages that were protected.
+func (d *DiffResult) FilterPinned(pinned map[string]bool) (*DiffResult, Packages) {
+	var protected Packages
+	for _, pkg := range d.Removals {
+		if pinned[packageKey(pkg)] {
+			protected = append(protected, pkg)
+		}
+	}
+
+	return &DiffResult{
+		Additions: d.Additions,
+		Removals:  filterByKey(d.Removals, pinned),
+		Common:    d.Common,
+	}, protected
+}
+
+// IsArchSpecific returns true if the package name indicates a single-architecture build
+// (e.g. "foo-intel", "bar-arm64")
+func IsArchSpecific(pkg Package) bool {
+	return ArchFromName(pkg) != ""
+}
+
+// ArchFromName returns the architecture ("arm64" or "amd64", as in
+// runtime.GOARCH) a package name says it is built for, or "" if it names none
+func ArchFromName(pkg Package) string {
+	name := strings.ToLower(pkg.Name)
+	for _, suffix := range archSuffixes {
+		if strings.HasSuffix(name, suffix) || strings.Contains(name, suffix+"-") {
+			switch suffix {
+			case "-arm64", "-aarch64", "-apple-silicon", "-silicon":
+				return "arm64"
+			default:
+				return "amd64"
+			}
+		}
+	}
+	return ""
+}
+
+// FilterArchSpecific removes architecture-specific packages from additions and removals.
+// It returns the filtered result and the packages that were removed.
+func (d *DiffResult) FilterArchSpecific() (*DiffResult, Packages) {
+	var skipped Packages
+	keep := func(pkgs Packages) Packages {
+		var result Packages
+		for _, pkg := range pkgs {
+			if IsArchSpecific(pkg) {
+				skipped = append(skipped, pkg)
+				continue
+			}
+			result = append(result, pkg)
+		}
+		return result
+	}
+
+	return &DiffResult{
+		Additions: keep(d.Additions),
+		Removals:  keep(d.Removals),
+		Common:    d.Common,
+	}, skipped
+}
+
+// FilterInstalled keeps only removals that are present in the installed set, so the
+// removal list reflects what could actually be uninstalled. It returns the filtered
+// result and the removals that are listed in the Brewfile but not installed.
+func (d *DiffResult) FilterInstalled(installed Packages) (*DiffResult, Packages) {
+	present := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		present[packageKey(pkg)] = true
+	}
+
+	var removals, missing Packages
+	for _, pkg := range d.Removals {
+		if present[packageKey(pkg)] {
+			removals = append(removals, pkg)
+		} else {
+			missing = append(missing, pkg)
+		}
+	}
+
+	return &DiffResult{
+		Additions: d.Additions,
+		Removals:  removals,
+		Common:    d.Common,
+	}, missing
+}
+
+// FilterInstalledAdditions drops additions that are already installed, for when
+// the Brewfile lags behind the machine (e.g. after an interrupted import). It
+// returns the filtered result and the additions that were already installed.
+func (d *DiffResult) FilterInstalledAdditions(installed Packages) (*DiffResult, Packages) {
+	present := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		present[packageKey(pkg)] = true
+	}
+
+	var additions, done Packages
+	for _, pkg := range d.Additions {
+		if present[packageKey(pkg)] {
+			done = append(done, pkg)
+		} else {
+			additions = append(additions, pkg)
+		}
+	}
+
+	return &DiffResult{
+		Additions: additions,
+		Removals:  d.Removals,
+		Common:    d.Common,
+	}, done
+}
+
+// TapChange is a formula or cask both sides have under the same name, but from
+// different taps (e.g. ripgrep from homebrew/core vs a fork tap)
+type TapChange struct {
+	// Source is the entry in the source Brewfile
+	Source Package
+	// Current is the entry in the current Brewfile
+	Current Package
+}
+
+// From returns the current tap, "core" for homebrew/core and homebrew/cask
+func (c TapChange) From() string {
+	return tapLabel(c.Current)
+}
+
+// To returns the source tap, "core" for homebrew/core and homebrew/cask
+func (c TapChange) To() string {
+	return tapLabel(c.Source)
+}
+
+// String describes the change, e.g. "tap changed: core → user/tap"
+func (c TapChange) String() string {
+	return "tap changed: " + c.From() + " → " + c.To()
+}
+
+// defaultTaps are the taps used for unqualified formula and cask names
+var defaultTaps = map[PackageType]string{
+	TypeBrew: "homebrew/core",
+	TypeCask: "homebrew/cask",
+}
+
+// tapLabel returns the package's tap, or "core" for the default tap of its type
+func tapLabel(pkg Package) string {
+	if pkg.Tap == "" || pkg.Tap == defaultTaps[pkg.Type] {
+		return "core"
+	}
+	return pkg.Tap
+}
+
+// SplitTapChanges pairs additions and removals of the same formula or cask that
+// differ only in their tap, which a plain diff reports as one package added and
+// another removed. Pairs from different taps are returned as tap changes; pairs
+// that name the same tap two ways ("ripgrep" and "homebrew/core/ripgrep") are
+// moved to Common.
+func (d *DiffResult) SplitTapChanges() (*DiffResult, []TapChange) {
+	baseKey := func(pkg Package) string {
+		return string(pkg.Type) + ":" + pkg.BaseName()
+	}
+
+	removalIdx := make(map[string]int)
+	for i, pkg := range d.Removals {
+		if _, ok := defaultTaps[pkg.Type]; ok {
+			removalIdx[baseKey(pkg)] = i
+		}
+	}
+
+	result := &DiffResult{Common: append(Packages{}, d.Common...)}
+	var changes []TapChange
+	paired := make(map[int]bool)
+
+	for _, pkg := range d.Additions {
+		i, ok := removalIdx[baseKey(pkg)]
+		if !ok || paired[i] {
+			result.Additions = append(result.Additions, pkg)
+			continue
+		}
+		paired[i] = true
+
+		current := d.Removals[i]
+		if tapLabel(pkg) == tapLabel(current) {
+			result.Common = append(result.Common, pkg)
+		} else {
+			changes = append(changes, TapChange{Source: pkg, Current: current})
+		}
+	}
+
+	for i, pkg := range d.Removals {
+		if !paired[i] {
+			result.Removals = append(result.Removals, pkg)
+		}
+	}
+
+	return result, changes
+}
+
+// VersionChange is an editor extension both sides have, pinned to different
+// versions (or pinned on one side only)
+type VersionChange struct {
+	// Source is the entry in the source Brewfile
+	Source Package
+	// Current is the entry in the current Brewfile
+	Current Package
+}
+
+// From returns the current version, "unpinned" if there is none
+func (c VersionChange) From() string {
+	return versionLabel(c.Current)
+}
+
+// To returns the source version, "unpinned" if there is none
+func (c VersionChange) To() string {
+	return versionLabel(c.Source)
+}
+
+// String describes the change, e.g. "version changed: 1.2.3 → 1.3.0"
+func (c VersionChange) String() string {
+	return "version changed: " + c.From() + " → " + c.To()
+}
+
+// versionLabel returns the package's pinned version, or "unpinned"
+func versionLabel(pkg Package) string {
+	if pkg.Version == "" {
+		return "unpinned"
+	}
+	return pkg.Version
+}
+
+// VersionChanges returns the editor extensions source and current both have
+// whose pinned versions differ. Extensions compare by key, so aliases apply
+// as in DiffWithAliases.
+func VersionChanges(source, current Packages, aliases map[string]string) []VersionChange {
+	currentMap := make(map[string]Package)
+	for _, pkg := range current {
+		if pkg.IsEditorExtension() {
+			currentMap[pkg.Key(aliases)] = pkg
+		}
+	}
+
+	var changes []VersionChange
+	for _, pkg := range source {
+		if !pkg.IsEditorExtension() {
+			continue
+		}
+		if cur, ok := currentMap[pkg.Key(aliases)]; ok && cur.Version != pkg.Version {
+			changes = append(changes, VersionChange{Source: pkg, Current: cur})
+		}
+	}
+	return changes
+}
+
+// Outdated is an installed formula or cask with a newer version available
+type Outdated struct {
+	Package Package
+	// Installed is the installed version
+	Installed string
+	// Latest is the newest version available
+	Latest string
+}
+
+// String describes the upgrade, e.g. "outdated: 2.40.0 → 2.41.0"
+func (o Outdated) String() string {
+	return "outdated: " + o.Installed + " → " + o.Latest
+}
+
+// MarkOutdated sets Outdated to the common packages found in outdated. Names
+// are matched without their tap, as Homebrew may report either form.
+func (d *DiffResult) MarkOutdated(outdated []Outdated) {
+	baseKey := func(pkg Package) string {
+		return string(pkg.Type) + ":" + pkg.BaseName()
+	}
+
+	byKey := make(map[string]Outdated, len(outdated))
+	for _, o := range outdated {
+		byKey[baseKey(o.Package)] = o
+	}
+
+	d.Outdated = nil
+	for _, pkg := range d.Common {
+		if o, ok := byKey[baseKey(pkg)]; ok {
+			o.Package = pkg
+			d.Outdated = append(d.Outdated, o)
+		}
+	}
+}
+
 // filterByKey filters out packages whose keys are in the excluded map
 func filterByKey(pkgs Packages, excluded map[string]bool) Packages {
 	var result Packages
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDiff_NoChanges(t *testing.T) {
@@ -228,6 +229,147 @@ func TestDiffResult_FilterMachineSpecific(t *testing.T) {
 	assert.Len(t, filtered.Removals, 1)  // bat
 }
 
+func TestDiffResult_FilterPinned(t *testing.T) {
+	diff := &DiffResult{
+		Additions: Packages{
+			NewPackage(TypeBrew, "postgresql@14"),
+		},
+		Removals: Packages{
+			NewPackage(TypeBrew, "bat"),
+			NewPackage(TypeBrew, "node@18"),
+			NewPackage(TypeCask, "docker"),
+		},
+	}
+
+	pinned := map[string]bool{
+		"brew:node@18":       true,
+		"cask:docker":        true,
+		"brew:postgresql@14": true,
+	}
+
+	filtered, protected := diff.FilterPinned(pinned)
+
+	assert.Equal(t, []string{"postgresql@14"}, filtered.Additions.Names()) // pins never block installs
+	assert.Equal(t, []string{"bat"}, filtered.Removals.Names())
+	assert.Equal(t, []string{"node@18", "docker"}, protected.Names())
+}
+
+func TestDiffResult_FilterInstalled(t *testing.T) {
+	diff := &DiffResult{
+		Additions: Packages{
+			NewPackage(TypeBrew, "ripgrep"),
+		},
+		Removals: Packages{
+			NewPackage(TypeBrew, "bat"),
+			NewPackage(TypeBrew, "wget"), // in the Brewfile but already uninstalled
+			NewPackage(TypeCask, "docker"),
+		},
+	}
+
+	installed := Packages{
+		NewPackage(TypeBrew, "bat"),
+		NewPackage(TypeCask, "docker"),
+		NewPackage(TypeBrew, "wget2"),
+	}
+
+	filtered, missing := diff.FilterInstalled(installed)
+
+	assert.Equal(t, []string{"ripgrep"}, filtered.Additions.Names()) // additions untouched
+	assert.Equal(t, []string{"bat", "docker"}, filtered.Removals.Names())
+	assert.Equal(t, []string{"wget"}, missing.Names())
+}
+
+func TestDiffWithAliases(t *testing.T) {
+	source := Packages{
+		NewPackage(TypeVSCode, "golang.go"),
+		NewPackage(TypeCursor, "golang.go"),
+		NewPackage(TypeVSCode, "eamodio.gitlens"),
+	}
+	current := Packages{
+		NewPackage(TypeVSCode, "ms-vscode.go"),
+		NewPackage(TypeCursor, "ms-vscode.go"),
+		NewPackage(TypeBrew, "ms-vscode.go"), // not an extension, never aliased
+	}
+
+	t.Run("without aliases", func(t *testing.T) {
+		diff := Diff(source, current)
+		assert.Len(t, diff.Additions, 3)
+		assert.Len(t, diff.Removals, 3)
+	})
+
+	t.Run("alias collapses old and new IDs", func(t *testing.T) {
+		aliases := map[string]string{"MS-VSCode.Go": "golang.go"} // case-insensitive
+		diff := DiffWithAliases(source, current, aliases)
+
+		require.Len(t, diff.Additions, 1)
+		assert.Equal(t, "vscode:eamodio.gitlens", diff.Additions[0].ID())
+		require.Len(t, diff.Removals, 1)
+		assert.Equal(t, "brew:ms-vscode.go", diff.Removals[0].ID())
+		assert.Len(t, diff.Common, 2)
+	})
+
+	t.Run("by type", func(t *testing.T) {
+		diff := DiffByTypeWithAliases(source, current, []PackageType{TypeVSCode}, map[string]string{"ms-vscode.go": "golang.go"})
+		assert.Len(t, diff.Additions, 1)
+		assert.Empty(t, diff.Removals)
+	})
+}
+
+func TestDiffSources(t *testing.T) {
+	current := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeBrew, "old-tool"),
+		NewPackage(TypeCask, "slack"),
+	}
+	sources := map[string]Packages{
+		"work": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "kubectl"),
+			NewPackage(TypeCask, "slack"),
+			NewPackage(TypeCask, "docker"),
+		},
+		"home": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "ffmpeg"),
+			NewPackage(TypeCask, "docker"),
+		},
+	}
+
+	diff := DiffSources(sources, current, nil)
+
+	assert.ElementsMatch(t, []string{"cask:docker", "brew:ffmpeg", "brew:kubectl"}, ids(diff.Additions))
+	require.Len(t, diff.Removals, 1)
+	assert.Equal(t, "brew:old-tool", diff.Removals[0].ID())
+	assert.ElementsMatch(t, []string{"brew:git", "cask:slack"}, ids(diff.Common))
+
+	// Provenance is recorded per addition, sources in name order
+	assert.Equal(t, []string{"work"}, diff.Sources["brew:kubectl"])
+	assert.Equal(t, []string{"home"}, diff.Sources["brew:ffmpeg"])
+	assert.Equal(t, []string{"home", "work"}, diff.Sources["cask:docker"])
+
+	bySource := diff.AdditionsBySource(diff.Additions)
+	assert.ElementsMatch(t, []string{"brew:ffmpeg", "cask:docker"}, ids(bySource["home"]))
+	assert.ElementsMatch(t, []string{"brew:kubectl", "cask:docker"}, ids(bySource["work"]))
+
+	// Grouping a filtered subset only groups that subset
+	filtered := diff.FilterIgnored(map[string]bool{"cask:docker": true})
+	bySource = diff.AdditionsBySource(filtered.Additions)
+	assert.Equal(t, []string{"brew:ffmpeg"}, ids(bySource["home"]))
+	assert.Equal(t, []string{"brew:kubectl"}, ids(bySource["work"]))
+}
+
+func TestDiffSources_SingleSourceMatchesDiff(t *testing.T) {
+	source := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "jq")}
+	current := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "wget")}
+
+	multi := DiffSources(map[string]Packages{"work": source}, current, nil)
+	single := Diff(source, current)
+
+	assert.Equal(t, single.Additions, multi.Additions)
+	assert.Equal(t, single.Removals, multi.Removals)
+	assert.Equal(t, single.Common, multi.Common)
+}
+
 func TestDiffResult_Summary(t *testing.T) {
 	t.Run("no differences", func(t *testing.T) {
 		diff := &DiffResult{}
@@ -262,3 +404,192 @@ func TestDiffResult_Summary(t *testing.T) {
 		assert.Contains(t, summary, "removal")
 	})
 }
+
+func TestIsArchSpecific(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"docker", false},
+		{"intellij-idea", false},
+		{"zoom-arm64", true},
+		{"foo-intel", true},
+		{"bar-x86_64-tools", true},
+		{"BAZ-Apple-Silicon", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsArchSpecific(NewPackage(TypeCask, tt.name)))
+		})
+	}
+}
+
+func TestDiffResult_FilterArchSpecific(t *testing.T) {
+	// Source is arm64, current is amd64
+	source := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeCask, "zoom-arm64"),
+		NewPackage(TypeCask, "firefox"),
+	}
+	current := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeCask, "zoom-intel"),
+	}
+
+	diff := Diff(source, current)
+	filtered, skipped := diff.FilterArchSpecific()
+
+	assert.Len(t, filtered.Additions, 1)
+	assert.Equal(t, "firefox", filtered.Additions[0].Name)
+	assert.Empty(t, filtered.Removals)
+	assert.Equal(t, diff.Common, filtered.Common)
+	assert.ElementsMatch(t, []string{"zoom-arm64", "zoom-intel"}, skipped.Names())
+}
+
+func TestDiffResult_FilterInstalledAdditions(t *testing.T) {
+	diff := &DiffResult{
+		Additions: Packages{
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "user/tap/ripgrep"),
+			NewPackage(TypeCask, "firefox"),
+		},
+		Removals: Packages{NewPackage(TypeBrew, "htop")},
+	}
+	installed := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeBrew, "ripgrep"), // core, not the tap's
+		NewPackage(TypeBrew, "htop"),
+	}
+
+	filtered, done := diff.FilterInstalledAdditions(installed)
+	assert.Equal(t, []string{"brew:user/tap/ripgrep", "cask:firefox"}, filtered.Additions.IDs())
+	assert.Equal(t, []string{"brew:git"}, done.IDs())
+	assert.Equal(t, diff.Removals, filtered.Removals)
+}
+
+func TestDiffResult_SplitTapChanges(t *testing.T) {
+	source := Packages{
+		NewPackage(TypeBrew, "user/tap/ripgrep"),   // fork tap on source
+		NewPackage(TypeBrew, "fd"),                 // core on source
+		NewPackage(TypeBrew, "homebrew/core/jq"),   // same tap, spelled out
+		NewPackage(TypeCask, "user/tap/firefox"),   // fork cask
+		NewPackage(TypeBrew, "wget"),               // plain addition
+		NewPackage(TypeVSCode, "user/tap/ripgrep"), // not a formula
+	}
+	current := Packages{
+		NewPackage(TypeBrew, "ripgrep"),
+		NewPackage(TypeBrew, "other/tap/fd"),
+		NewPackage(TypeBrew, "jq"),
+		NewPackage(TypeCask, "firefox"),
+		NewPackage(TypeBrew, "htop"), // plain removal
+	}
+
+	diff := Diff(source, current)
+	require.Len(t, diff.Additions, 6)
+	require.Len(t, diff.Removals, 5)
+
+	split, changes := diff.SplitTapChanges()
+
+	assert.Equal(t, []string{"wget", "user/tap/ripgrep"}, split.Additions.Names())
+	assert.Equal(t, TypeVSCode, split.Additions[1].Type)
+	assert.Equal(t, []string{"htop"}, split.Removals.Names())
+	assert.Equal(t, []string{"homebrew/core/jq"}, split.Common.Names())
+
+	require.Len(t, changes, 3)
+	assert.Equal(t, "ripgrep", changes[0].Source.BaseName())
+	assert.Equal(t, "tap changed: core → user/tap", changes[0].String())
+	assert.Equal(t, "tap changed: other/tap → core", changes[1].String())
+	assert.Equal(t, TypeCask, changes[2].Source.Type)
+	assert.Equal(t, "core", changes[2].From())
+	assert.Equal(t, "user/tap", changes[2].To())
+
+	// The original diff is left untouched
+	assert.Len(t, diff.Additions, 6)
+	assert.Empty(t, diff.Common)
+}
+
+func TestVersionChanges(t *testing.T) {
+	source := Packages{
+		{Type: TypeVSCode, Name: "golang.go", Version: "0.42.0"},
+		{Type: TypeVSCode, Name: "ms-python.python", Version: "2024.1.0"},
+		{Type: TypeCursor, Name: "vscodevim.vim"},
+		{Type: TypeVSCode, Name: "esbenp.prettier-vscode", Version: "10.0.0"},
+		NewPackage(TypeBrew, "git"),
+	}
+	current := Packages{
+		{Type: TypeVSCode, Name: "golang.go", Version: "0.41.4"},
+		{Type: TypeVSCode, Name: "ms-python.python", Version: "2024.1.0"},
+		{Type: TypeCursor, Name: "vscodevim.vim", Version: "1.29.0"},
+		NewPackage(TypeBrew, "git"),
+	}
+
+	// Version differences are not additions or removals
+	diff := Diff(source, current)
+	assert.Equal(t, []string{"esbenp.prettier-vscode"}, diff.Additions.Names())
+	assert.Empty(t, diff.Removals)
+
+	changes := VersionChanges(source, current, nil)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "golang.go", changes[0].Source.Name)
+	assert.Equal(t, "version changed: 0.41.4 → 0.42.0", changes[0].String())
+	assert.Equal(t, TypeCursor, changes[1].Source.Type)
+	assert.Equal(t, "1.29.0", changes[1].From())
+	assert.Equal(t, "unpinned", changes[1].To())
+}
+
+func TestDiffResult_MarkOutdated(t *testing.T) {
+	source := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "user/tap/tool"), NewPackage(TypeCask, "firefox"), NewPackage(TypeBrew, "jq")}
+	current := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "user/tap/tool"), NewPackage(TypeCask, "firefox"), NewPackage(TypeBrew, "wget")}
+	diff := Diff(source, current)
+
+	diff.MarkOutdated([]Outdated{
+		{Package: NewPackage(TypeBrew, "git"), Installed: "2.40.0", Latest: "2.41.0"},
+		{Package: NewPackage(TypeBrew, "tool"), Installed: "1.0", Latest: "1.1"},
+		{Package: NewPackage(TypeBrew, "firefox"), Installed: "1", Latest: "2"},
+		{Package: NewPackage(TypeBrew, "wget"), Installed: "1.21", Latest: "1.24"},
+	})
+
+	// Only packages on both sides count; the type must match and the tap needn't
+	require.Len(t, diff.Outdated, 2)
+	assert.Equal(t, "git", diff.Outdated[0].Package.Name)
+	assert.Equal(t, "outdated: 2.40.0 → 2.41.0", diff.Outdated[0].String())
+	assert.Equal(t, "user/tap/tool", diff.Outdated[1].Package.Name)
+	assert.Equal(t, []string{"jq"}, diff.Additions.Names())
+}
+
+func TestPackage_BaseName(t *testing.T) {
+	assert.Equal(t, "ripgrep", NewPackage(TypeBrew, "user/tap/ripgrep").BaseName())
+	assert.Equal(t, "ripgrep", NewPackage(TypeBrew, "ripgrep").BaseName())
+	assert.Equal(t, "golang.go", NewPackage(TypeVSCode, "golang.go").BaseName())
+}
+
+func TestDiff_SameNameAcrossTypes(t *testing.T) {
+	source := Packages{
+		NewPackage(TypeBrew, "docker"),
+		NewPackage(TypeCask, "user/tap/docker"),
+	}
+	current := Packages{
+		NewPackage(TypeCask, "docker"),
+	}
+
+	diff := Diff(source, current)
+	assert.Equal(t, []string{"brew:docker", "cask:user/tap/docker"}, diff.Additions.IDs())
+	assert.Equal(t, []string{"cask:docker"}, diff.Removals.IDs())
+
+	// Ignoring the cask doesn't hide the formula
+	filtered := diff.FilterIgnored(map[string]bool{"cask:user/tap/docker": true, "cask:docker": true})
+	assert.Equal(t, []string{"brew:docker"}, filtered.Additions.IDs())
+	assert.Empty(t, filtered.Removals)
+
+	// Only the cask moved taps; the formula is not paired with it
+	rest, changes := diff.SplitTapChanges()
+	require.Len(t, changes, 1)
+	assert.Equal(t, "cask:user/tap/docker", changes[0].Source.ID())
+	assert.Equal(t, []string{"brew:docker"}, rest.Additions.IDs())
+	assert.Empty(t, rest.Removals)
+
+	assert.True(t, current.Contains("cask:docker"))
+	assert.False(t, current.Contains("brew:docker"))
+	assert.Len(t, current.AddUnique(NewPackage(TypeBrew, "docker")), 2)
+}
@@ -0,0 +1,141 @@
+package brewfile
+
+import "sort"
+
+// SingleMachinePackages returns packages present on exactly one machine, grouped by
+// that machine. These are candidates for machine_specific: syncing from any machine
+// that lacks them would otherwise offer them for removal.
+// At least two machines are needed for a meaningful comparison; with fewer, nil is returned.
+// Packages are compared by Key(aliases), so aliased editor extensions count as one.
+func SingleMachinePackages(machines map[string]Packages, aliases map[string]string) map[string]Packages {
+	if len(machines) < 2 {
+		return nil
+	}
+
+	owners := packageOwners(machines, aliases)
+	result := make(map[string]Packages)
+	for machine, pkgs := range machines {
+		seen := make(map[string]bool)
+		for _, pkg := range pkgs {
+			key := pkg.Key(aliases)
+			if len(owners[key]) == 1 && !seen[key] {
+				seen[key] = true
+				result[machine] = append(result[machine], pkg)
+			}
+		}
+		sort.Slice(result[machine], func(i, j int) bool {
+			return result[machine][i].ID() < result[machine][j].ID()
+		})
+	}
+
+	return result
+}
+
+// Overlap is a package and the machines whose Brewfiles list it
+type Overlap struct {
+	Package Package
+	// Machines are the machines with the package, sorted by name
+	Machines []string
+}
+
+// Overlaps returns every package the machines have along with which machines
+// have it, the most widely shared first and then by ID. Packages compare by
+// Key(aliases) as in SingleMachinePackages; an aliased extension is reported
+// under the entry of the first machine by name.
+func Overlaps(machines map[string]Packages, aliases map[string]string) []Overlap {
+	names := make([]string, 0, len(machines))
+	for name := range machines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	owners := packageOwners(machines, aliases)
+	var result []Overlap
+	seen := make(map[string]bool)
+	for _, name := range names {
+		for _, pkg := range machines[name] {
+			key := pkg.Key(aliases)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			have := make([]string, 0, len(owners[key]))
+			for machine := range owners[key] {
+				have = append(have, machine)
+			}
+			sort.Strings(have)
+			result = append(result, Overlap{Package: pkg, Machines: have})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if len(result[i].Machines) != len(result[j].Machines) {
+			return len(result[i].Machines) > len(result[j].Machines)
+		}
+		return result[i].Package.ID() < result[j].Package.ID()
+	})
+	return result
+}
+
+// packageOwners returns the machines each package key appears on
+func packageOwners(machines map[string]Packages, aliases map[string]string) map[string]map[string]bool {
+	owners := make(map[string]map[string]bool)
+	for machine, pkgs := range machines {
+		for _, pkg := range pkgs {
+			key := pkg.Key(aliases)
+			if owners[key] == nil {
+				owners[key] = make(map[string]bool)
+			}
+			owners[key][machine] = true
+		}
+	}
+	return owners
+}
+
+// Presence is a package and, for every machine compared, whether that
+// machine's Brewfile lists it
+type Presence struct {
+	Package Package
+	// On has an entry for each machine compared, true if it has the package
+	On map[string]bool
+}
+
+// Missing returns the machines that don't have the package, sorted by name
+func (p Presence) Missing() []string {
+	var missing []string
+	for machine, has := range p.On {
+		if !has {
+			missing = append(missing, machine)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// DiffAll compares every machine's packages at once: it returns each package
+// any machine has, sorted by ID, with which machines have it
+func DiffAll(machines map[string]Packages) []Presence {
+	return DiffAllWithAliases(machines, nil)
+}
+
+// DiffAllWithAliases is DiffAll with extension aliases (see DiffWithAliases)
+func DiffAllWithAliases(machines map[string]Packages, aliases map[string]string) []Presence {
+	overlaps := Overlaps(machines, aliases)
+	result := make([]Presence, 0, len(overlaps))
+	for _, o := range overlaps {
+		on := make(map[string]bool, len(machines))
+		for machine := range machines {
+			on[machine] = false
+		}
+		for _, machine := range o.Machines {
+			on[machine] = true
+		}
+		result = append(result, Presence{Package: o.Package, On: on})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Package.ID() < result[j].Package.ID()
+	})
+	return result
+}
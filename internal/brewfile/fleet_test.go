@@ -0,0 +1,146 @@
+package brewfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleMachinePackages(t *testing.T) {
+	machines := map[string]Packages{
+		"mini": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "postgresql@16"),
+			NewPackage(TypeCask, "orbstack"),
+			NewPackage(TypeVSCode, "ms-vscode.go"),
+		},
+		"air": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "ollama"),
+			NewPackage(TypeCask, "orbstack"),
+			NewPackage(TypeVSCode, "golang.go"),
+		},
+		"studio": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeCask, "ollama"), // same name, different type
+		},
+	}
+
+	t.Run("without aliases", func(t *testing.T) {
+		result := SingleMachinePackages(machines, nil)
+		assert.Equal(t, []string{"brew:postgresql@16", "vscode:ms-vscode.go"}, ids(result["mini"]))
+		assert.Equal(t, []string{"brew:ollama", "vscode:golang.go"}, ids(result["air"]))
+		assert.Equal(t, []string{"cask:ollama"}, ids(result["studio"]))
+	})
+
+	t.Run("aliased extensions are shared", func(t *testing.T) {
+		result := SingleMachinePackages(machines, map[string]string{"ms-vscode.go": "golang.go"})
+		assert.Equal(t, []string{"brew:postgresql@16"}, ids(result["mini"]))
+		assert.Equal(t, []string{"brew:ollama"}, ids(result["air"]))
+	})
+
+	t.Run("needs two machines", func(t *testing.T) {
+		assert.Nil(t, SingleMachinePackages(map[string]Packages{"mini": machines["mini"]}, nil))
+	})
+}
+
+func TestOverlaps(t *testing.T) {
+	machines := map[string]Packages{
+		"mini": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "jq"),
+			NewPackage(TypeCask, "orbstack"),
+			NewPackage(TypeVSCode, "ms-vscode.go"),
+		},
+		"air": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeBrew, "jq"),
+			NewPackage(TypeBrew, "ollama"),
+			NewPackage(TypeVSCode, "golang.go"),
+		},
+		"studio": {
+			NewPackage(TypeBrew, "git"),
+			NewPackage(TypeCask, "ollama"), // same name, different type
+			NewPackage(TypeCask, "orbstack"),
+			NewPackage(TypeVSCode, "golang.go"),
+		},
+	}
+
+	summarize := func(overlaps []Overlap) map[string][]string {
+		result := make(map[string][]string)
+		for _, o := range overlaps {
+			result[o.Package.ID()] = o.Machines
+		}
+		return result
+	}
+
+	t.Run("without aliases", func(t *testing.T) {
+		overlaps := Overlaps(machines, nil)
+		assert.Equal(t, map[string][]string{
+			"brew:git":            {"air", "mini", "studio"},
+			"brew:jq":             {"air", "mini"},
+			"cask:orbstack":       {"mini", "studio"},
+			"vscode:golang.go":    {"air", "studio"},
+			"brew:ollama":         {"air"},
+			"cask:ollama":         {"studio"},
+			"vscode:ms-vscode.go": {"mini"},
+		}, summarize(overlaps))
+
+		// Most widely shared first, then by ID
+		var order []string
+		for _, o := range overlaps {
+			order = append(order, o.Package.ID())
+		}
+		assert.Equal(t, []string{
+			"brew:git",
+			"brew:jq", "cask:orbstack", "vscode:golang.go",
+			"brew:ollama", "cask:ollama", "vscode:ms-vscode.go",
+		}, order)
+	})
+
+	t.Run("aliased extensions are shared", func(t *testing.T) {
+		overlaps := Overlaps(machines, map[string]string{"ms-vscode.go": "golang.go"})
+		result := summarize(overlaps)
+		assert.Equal(t, []string{"air", "mini", "studio"}, result["vscode:golang.go"])
+		assert.NotContains(t, result, "vscode:ms-vscode.go")
+		assert.Len(t, overlaps, 6)
+	})
+}
+
+func TestDiffAll(t *testing.T) {
+	machines := map[string]Packages{
+		"mini":   {NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "jq"), NewPackage(TypeCask, "orbstack")},
+		"air":    {NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "jq"), NewPackage(TypeBrew, "ollama")},
+		"studio": {NewPackage(TypeBrew, "git"), NewPackage(TypeCask, "orbstack")},
+		"empty":  {},
+	}
+
+	result := DiffAll(machines)
+	var order []string
+	for _, p := range result {
+		order = append(order, p.Package.ID())
+	}
+	assert.Equal(t, []string{"brew:git", "brew:jq", "brew:ollama", "cask:orbstack"}, order)
+
+	jq := result[1]
+	assert.Equal(t, map[string]bool{"mini": true, "air": true, "studio": false, "empty": false}, jq.On)
+	assert.Equal(t, []string{"empty", "studio"}, jq.Missing())
+	assert.Equal(t, []string{"empty"}, result[0].Missing())
+
+	t.Run("aliased extensions are one row", func(t *testing.T) {
+		result := DiffAllWithAliases(map[string]Packages{
+			"mini": {NewPackage(TypeVSCode, "ms-vscode.go")},
+			"air":  {NewPackage(TypeVSCode, "golang.go")},
+		}, map[string]string{"ms-vscode.go": "golang.go"})
+		assert.Len(t, result, 1)
+		assert.Empty(t, result[0].Missing())
+	})
+}
+
+func ids(pkgs Packages) []string {
+	result := make([]string, len(pkgs))
+	for i, p := range pkgs {
+		result[i] = p.ID()
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package brewfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IconStyle selects how package types are drawn in output
+type IconStyle string
+
+const (
+	IconsEmoji    IconStyle = "emoji"
+	IconsNerdFont IconStyle = "nerdfont" // Needs a Nerd Font in the terminal
+	IconsASCII    IconStyle = "ascii"    // For terminals that show emoji as boxes
+)
+
+// IconStyles returns all icon styles, the default first
+func IconStyles() []IconStyle {
+	return []IconStyle{IconsEmoji, IconsNerdFont, IconsASCII}
+}
+
+// typeIcons holds one icon per package type for each style
+var typeIcons = map[IconStyle]map[PackageType]string{
+	IconsEmoji: {
+		TypeTap:         "🚰",
+		TypeBrew:        "🍺",
+		TypeCask:        "📦",
+		TypeVSCode:      "💻",
+		TypeCursor:      "✏️",
+		TypeAntigravity: "🚀",
+		TypeGo:          "🔷",
+		TypeNpm:         "🟥",
+		TypePipx:        "🐍",
+		TypeMas:         "🍎",
+	},
+	IconsNerdFont: {
+		TypeTap:         "\uf126",     // nf-fa-code_fork
+		TypeBrew:        "\uf0fc",     // nf-fa-beer
+		TypeCask:        "\uf487",     // nf-oct-package
+		TypeVSCode:      "\U000f0a1e", // nf-md-microsoft_visual_studio_code
+		TypeCursor:      "\uf246",     // nf-fa-i_cursor
+		TypeAntigravity: "\uf135",     // nf-fa-rocket
+		TypeGo:          "\ue627",     // nf-seti-go
+		TypeNpm:         "\ue71e",     // nf-dev-npm
+		TypePipx:        "\ue73c",     // nf-dev-python
+		TypeMas:         "\uf179",     // nf-fa-apple
+	},
+	IconsASCII: {
+		TypeTap:         "[tap]",
+		TypeBrew:        "[brw]",
+		TypeCask:        "[csk]",
+		TypeVSCode:      "[vsc]",
+		TypeCursor:      "[cur]",
+		TypeAntigravity: "[agy]",
+		TypeGo:          "[go]",
+		TypeNpm:         "[npm]",
+		TypePipx:        "[pipx]",
+		TypeMas:         "[mas]",
+	},
+}
+
+// iconStyle is the style Meta uses, set from output.icons
+var iconStyle = IconsEmoji
+
+// ParseIconStyle parses an output.icons value; empty means emoji
+func ParseIconStyle(s string) (IconStyle, error) {
+	if s == "" {
+		return IconsEmoji, nil
+	}
+	style := IconStyle(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := typeIcons[style]; !ok {
+		return "", fmt.Errorf("unknown icon style %q (valid: emoji, nerdfont, ascii)", s)
+	}
+	return style, nil
+}
+
+// SetIconStyle selects the icons Meta returns. An unknown style leaves the
+// current one in place and returns an error.
+func SetIconStyle(s string) error {
+	style, err := ParseIconStyle(s)
+	if err != nil {
+		return err
+	}
+	iconStyle = style
+	return nil
+}
+
+// TypeMeta is how a package type is presented
+type TypeMeta struct {
+	Type PackageType
+	Icon string
+}
+
+// Meta returns the presentation of t in the selected icon style
+func Meta(t PackageType) TypeMeta {
+	return TypeMeta{Type: t, Icon: Icon(iconStyle, t)}
+}
+
+// Icon returns t's icon in style, "•" for unknown types
+func Icon(style IconStyle, t PackageType) string {
+	if icon, ok := typeIcons[style][t]; ok {
+		return icon
+	}
+	return "•"
+}
@@ -0,0 +1,45 @@
+package brewfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIconStyles_CompleteSets(t *testing.T) {
+	for _, style := range IconStyles() {
+		seen := make(map[string]bool)
+		for _, pt := range AllTypes() {
+			icon := Icon(style, pt)
+			assert.NotEmpty(t, icon, "%s icon for %s", style, pt)
+			assert.NotEqual(t, "•", icon, "%s has no icon for %s", style, pt)
+			assert.False(t, seen[icon], "%s reuses %q", style, icon)
+			seen[icon] = true
+		}
+		assert.Len(t, typeIcons[style], len(AllTypes()), "%s has icons for unknown types", style)
+	}
+
+	for _, pt := range AllTypes() {
+		for _, r := range Icon(IconsASCII, pt) {
+			assert.Less(t, r, rune(128), "ascii icon for %s", pt)
+		}
+	}
+}
+
+func TestSetIconStyle(t *testing.T) {
+	t.Cleanup(func() { iconStyle = IconsEmoji })
+
+	assert.Equal(t, "🍺", Meta(TypeBrew).Icon)
+
+	require.NoError(t, SetIconStyle("ascii"))
+	assert.Equal(t, TypeMeta{Type: TypeBrew, Icon: "[brw]"}, Meta(TypeBrew))
+
+	// Unknown styles keep the current one
+	assert.Error(t, SetIconStyle("wingdings"))
+	assert.Equal(t, "[brw]", Meta(TypeBrew).Icon)
+
+	require.NoError(t, SetIconStyle(""))
+	assert.Equal(t, "🍺", Meta(TypeBrew).Icon)
+	assert.Equal(t, "•", Meta(PackageType("other")).Icon)
+}
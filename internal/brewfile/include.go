@@ -0,0 +1,145 @@
+package brewfile
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IncludeDirective starts a comment line that pulls another Brewfile into
+// this one, e.g. "# brewsync:include ../base/Brewfile". brew bundle sees a
+// comment; brewsync parses the included file's packages as part of this one.
+const IncludeDirective = "# brewsync:include"
+
+// IncludeCycleError is returned when Brewfiles include each other
+type IncludeCycleError struct {
+	Chain []string // Files in include order, ending with the one included again
+}
+
+func (e *IncludeCycleError) Error() string {
+	return "brewfile include cycle: " + strings.Join(e.Chain, " -> ")
+}
+
+// Match: # brewsync:include path
+var includePattern = regexp.MustCompile(`^#\s*brewsync:include\s+(\S.*)$`)
+
+// includeTarget returns the path named by an include directive line
+func includeTarget(line string) (string, bool) {
+	matches := includePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return "", false
+	}
+	return strings.TrimSpace(matches[1]), true
+}
+
+// resolveInclude returns the absolute path of an include target: relative
+// paths are relative to the directory of the including file, and ~ is the
+// home directory
+func resolveInclude(from, target string) string {
+	if target == "~" || strings.HasPrefix(target, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			target = filepath.Join(home, target[1:])
+		}
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(from), target)
+	}
+	return filepath.Clean(target)
+}
+
+// ReadIncludes returns the include directive lines of the Brewfile at path,
+// in order. A missing file has no includes.
+func ReadIncludes(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var includes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if target, ok := includeTarget(scanner.Text()); ok {
+			includes = append(includes, IncludeDirective+" "+target)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Brewfile: %w", err)
+	}
+	return includes, nil
+}
+
+// parseFileWithIncludes parses the Brewfile at path and merges in the
+// packages of the files it includes. chain holds the files being parsed
+// further up, to detect cycles. Entries in the including file win over
+// included ones with the same ID.
+func (p *Parser) parseFileWithIncludes(path string, chain []string) (Packages, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+	for i, parent := range chain {
+		if parent == abs {
+			cycle := append(append([]string{}, chain[i:]...), abs)
+			return nil, &IncludeCycleError{Chain: cycle}
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if len(chain) > 0 {
+			return nil, fmt.Errorf("failed to open included Brewfile %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	packages, err := p.ParseReader(file)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	includes, err := ReadIncludes(path)
+	if err != nil {
+		return nil, err
+	}
+	chain = append(chain, abs)
+	for _, line := range includes {
+		target, _ := includeTarget(line)
+		included, err := p.parseFileWithIncludes(resolveInclude(abs, target), chain)
+		if err != nil {
+			return nil, err
+		}
+		packages = packages.AddUnique(included...)
+	}
+	return packages, nil
+}
+
+// IncludedPackages returns the packages the given include directive lines
+// of the Brewfile at path pull in
+func IncludedPackages(path string, includes []string) (Packages, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+
+	p := NewParser()
+	var packages Packages
+	for _, line := range includes {
+		target, ok := includeTarget(line)
+		if !ok {
+			continue
+		}
+		included, err := p.parseFileWithIncludes(resolveInclude(abs, target), []string{abs})
+		if err != nil {
+			return nil, err
+		}
+		packages = packages.AddUnique(included...)
+	}
+	return packages, nil
+}
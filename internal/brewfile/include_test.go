@@ -0,0 +1,125 @@
+package brewfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBrewfile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestParse_Includes(t *testing.T) {
+	dir := t.TempDir()
+	writeBrewfile(t, filepath.Join(dir, "common", "Brewfile"), "brew \"curl\"\n")
+	writeBrewfile(t, filepath.Join(dir, "base", "Brewfile"), `# brewsync:include ../common/Brewfile
+# Distributed revision control system
+brew "git"
+cask "firefox"
+`)
+	mini := filepath.Join(dir, "mini", "Brewfile")
+	writeBrewfile(t, mini, `# brewsync:include ../base/Brewfile
+# Lightweight JSON processor
+brew "jq"
+brew "git", link: true
+`)
+
+	pkgs, err := Parse(mini)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"brew:jq", "brew:git", "cask:firefox", "brew:curl"}, pkgs.IDs())
+
+	// The directive is not taken as a description, included ones are kept
+	for _, pkg := range pkgs {
+		switch pkg.ID() {
+		case "brew:jq":
+			assert.Equal(t, "Lightweight JSON processor", pkg.Description)
+		case "brew:git":
+			// The including file's entry wins
+			assert.Equal(t, "true", pkg.Options["link"])
+		}
+	}
+
+	// Parsing content without a path ignores includes
+	pkgs, err = ParseContent("# brewsync:include ../base/Brewfile\nbrew \"jq\"\n")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	assert.Empty(t, pkgs[0].Description)
+}
+
+func TestParse_IncludeMissing(t *testing.T) {
+	mini := filepath.Join(t.TempDir(), "Brewfile")
+	writeBrewfile(t, mini, "# brewsync:include base/Brewfile\nbrew \"jq\"\n")
+
+	_, err := Parse(mini)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join("base", "Brewfile"))
+}
+
+func TestParse_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a", "Brewfile")
+	b := filepath.Join(dir, "b", "Brewfile")
+	writeBrewfile(t, a, "# brewsync:include ../b/Brewfile\nbrew \"git\"\n")
+	writeBrewfile(t, b, "# brewsync:include ../a/Brewfile\nbrew \"jq\"\n")
+
+	_, err := Parse(a)
+	var cycle *IncludeCycleError
+	require.True(t, errors.As(err, &cycle), "got %v", err)
+	assert.Equal(t, []string{a, b, a}, cycle.Chain)
+
+	// A file including itself is a cycle too
+	self := filepath.Join(dir, "self", "Brewfile")
+	writeBrewfile(t, self, "# brewsync:include Brewfile\n")
+	_, err = Parse(self)
+	assert.True(t, errors.As(err, &cycle))
+}
+
+func TestWriter_PreservesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeBrewfile(t, filepath.Join(dir, "base", "Brewfile"), "brew \"git\"\ncask \"firefox\"\n")
+	mini := filepath.Join(dir, "mini", "Brewfile")
+	writeBrewfile(t, mini, "# brewsync:include ../base/Brewfile\nbrew \"jq\"\n")
+
+	// A dump writes every installed package; the base ones are left out
+	installed := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeBrew, "jq"),
+		NewPackage(TypeBrew, "wget"),
+		NewPackage(TypeCask, "firefox"),
+	}
+	require.NoError(t, NewWriter(installed).Write(mini))
+
+	data, err := os.ReadFile(mini)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# brewsync:include ../base/Brewfile\n")
+	assert.Contains(t, content, `brew "jq"`)
+	assert.Contains(t, content, `brew "wget"`)
+	assert.NotContains(t, content, `brew "git"`)
+	assert.NotContains(t, content, "firefox")
+
+	// Parsing the result gives back the full set
+	pkgs, err := Parse(mini)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, installed.IDs(), pkgs.IDs())
+}
+
+func TestReadHeader_StopsAtInclude(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Brewfile")
+	writeBrewfile(t, path, "# Brewfile for mini\n"+headerMarker+"\n# brewsync:include ../base/Brewfile\nbrew \"jq\"\n")
+
+	header, err := ReadHeader(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"# Brewfile for mini", headerMarker}, header)
+
+	includes, err := ReadIncludes(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"# brewsync:include ../base/Brewfile"}, includes)
+}
@@ -0,0 +1,147 @@
+package brewfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// sectionCommentPattern matches the section comments Format writes above
+// brewsync's own package types, which are not kept as hand-written comments
+var sectionCommentPattern = regexp.MustCompile(`^# \w+ \(brewsync extension\)$`)
+
+// Layout is the hand-arranged shape of an existing Brewfile: the order of its
+// packages and the comment lines and blank lines above each one. A Writer with
+// PreserveComments set writes packages back the same way.
+type Layout struct {
+	entries map[string]layoutEntry
+}
+
+// layoutEntry is where a package sits in the Brewfile and what precedes it
+type layoutEntry struct {
+	pos      int      // Position among the Brewfile's packages
+	comments []string // Comment lines above the package; "" is a blank line between them
+	blank    bool     // A blank line separates the package and its comments from the entry before
+}
+
+// ReadLayout returns the layout of the Brewfile at path, leaving out the
+// brewsync header. A missing file has no layout (nil).
+func ReadLayout(path string) (*Layout, error) {
+	header, err := ReadHeader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseLayout(file, len(header))
+}
+
+// ParseLayout reads the layout of Brewfile content, skipping the first
+// headerLines lines (the brewsync header, see ReadHeader). Comments above a
+// global directive or include, and the section comments Format writes, belong
+// to no package and are dropped.
+func ParseLayout(r io.Reader, headerLines int) (*Layout, error) {
+	layout := &Layout{entries: make(map[string]layoutEntry)}
+	parser := NewParser()
+
+	var comments []string
+	blank := false
+	reset := func() {
+		comments = nil
+		blank = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if headerLines > 0 {
+			headerLines--
+			continue
+		}
+
+		switch {
+		case line == "":
+			if len(comments) == 0 {
+				blank = true
+			} else if comments[len(comments)-1] != "" {
+				comments = append(comments, "")
+			}
+			continue
+		case sectionCommentPattern.MatchString(line):
+			reset()
+			blank = true
+			continue
+		case strings.HasPrefix(line, "#"):
+			if _, ok := includeTarget(line); ok {
+				reset()
+				continue
+			}
+			comments = append(comments, line)
+			continue
+		}
+
+		pkg, ok := parser.parseLine(line)
+		if ok {
+			if _, seen := layout.entries[pkg.ID()]; !seen {
+				layout.entries[pkg.ID()] = layoutEntry{
+					pos:      len(layout.entries),
+					comments: comments,
+					blank:    blank,
+				}
+			}
+		}
+		reset()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Brewfile: %w", err)
+	}
+	return layout, nil
+}
+
+// entry returns the layout of pkg, if the Brewfile had it
+func (l *Layout) entry(pkg Package) (layoutEntry, bool) {
+	if l == nil {
+		return layoutEntry{}, false
+	}
+	e, ok := l.entries[pkg.ID()]
+	return e, ok
+}
+
+// less orders packages of one type: those the Brewfile had keep its order,
+// followed by new ones by name
+func (l *Layout) less(a, b Package) bool {
+	ea, okA := l.entry(a)
+	eb, okB := l.entry(b)
+	switch {
+	case okA && okB:
+		return ea.pos < eb.pos
+	case okA != okB:
+		return okA
+	default:
+		return a.Name < b.Name
+	}
+}
+
+// commentsFor returns the comment lines to write above pkg. The comment right
+// above a package was read as its description (see Parser.ParseReader), so it
+// is left out when it matches the description Format writes there. A changed
+// description can't be told apart from a hand-written note and is kept.
+func (e layoutEntry) commentsFor(pkg Package) []string {
+	comments := e.comments
+	if n := len(comments); n > 0 && pkg.Description != "" && comments[n-1] == "# "+pkg.Description {
+		comments = comments[:n-1]
+	}
+	return comments
+}
@@ -2,11 +2,16 @@ package brewfile
 
 import (
 	"os"
+	"path/filepath"
+	"runtime"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// MetadataFileName is the name of the metadata file stored next to each Brewfile
+const MetadataFileName = ".brewsync-meta"
+
 // Metadata represents the .brewsync-meta file
 type Metadata struct {
 	Machine         string         `yaml:"machine"`
@@ -14,6 +19,7 @@ type Metadata struct {
 	LastSync        LastSyncInfo   `yaml:"last_sync,omitempty"`
 	PackageCounts   map[string]int `yaml:"package_counts,omitempty"`
 	MacOSVersion    string         `yaml:"macos_version,omitempty"`
+	Arch            string         `yaml:"arch,omitempty"` // CPU architecture at last dump (arm64, amd64)
 	BrewsyncVersion string         `yaml:"brewsync_version,omitempty"`
 }
 
@@ -23,6 +29,12 @@ type LastSyncInfo struct {
 	At      time.Time `yaml:"at"`
 	Added   int       `yaml:"added"`
 	Removed int       `yaml:"removed"`
+	Applied []string  `yaml:"applied,omitempty"` // IDs of packages installed or removed by syncs from From since the last dump
+}
+
+// MetadataPath returns the metadata file path for the given Brewfile
+func MetadataPath(brewfilePath string) string {
+	return filepath.Join(filepath.Dir(brewfilePath), MetadataFileName)
 }
 
 // LoadMetadata loads metadata from the given path
@@ -51,11 +63,13 @@ func SaveMetadata(path string, meta *Metadata) error {
 }
 
 // UpdateMetadata updates the metadata file with new dump information
+// and appends the package total to the count history
 func UpdateMetadata(path string, machine string, packages Packages, version string) error {
 	meta := &Metadata{
 		Machine:         machine,
 		LastDump:        time.Now(),
 		PackageCounts:   make(map[string]int),
+		Arch:            runtime.GOARCH,
 		BrewsyncVersion: version,
 	}
 
@@ -67,27 +81,111 @@ func UpdateMetadata(path string, machine string, packages Packages, version stri
 	}
 
 	// Count packages by type
-	for _, pkg := range packages {
-		meta.PackageCounts[string(pkg.Type)]++
+	meta.PackageCounts = packages.Stats().Counts()
+
+	if err := SaveMetadata(path, meta); err != nil {
+		return err
 	}
 
-	return SaveMetadata(path, meta)
+	// Keep a short count history for the dashboard trend
+	return AppendCountSnapshot(filepath.Join(filepath.Dir(path), CountHistoryFileName),
+		CountSnapshot{At: meta.LastDump, Total: len(packages)})
 }
 
-// UpdateSyncMetadata updates the metadata file with sync information
-func UpdateSyncMetadata(path string, fromMachine string, added, removed int) error {
+// UpdateSyncMetadata updates the metadata file with sync information.
+// installed and removed are the packages the sync actually applied; they are
+// added to those applied by earlier syncs from the same machine since the last
+// dump, which the Brewfile doesn't reflect yet either.
+func UpdateSyncMetadata(path string, fromMachine string, installed, removed Packages) error {
 	meta, err := LoadMetadata(path)
 	if err != nil {
 		// Create new metadata if file doesn't exist
 		meta = &Metadata{}
 	}
 
+	var applied []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			applied = append(applied, id)
+		}
+	}
+	if meta.LastSync.From == fromMachine && meta.LastSync.At.After(meta.LastDump) {
+		for _, id := range meta.LastSync.Applied {
+			add(id)
+		}
+	}
+	for _, pkg := range installed {
+		add(pkg.ID())
+	}
+	for _, pkg := range removed {
+		add(pkg.ID())
+	}
+
 	meta.LastSync = LastSyncInfo{
 		From:    fromMachine,
 		At:      time.Now(),
-		Added:   added,
-		Removed: removed,
+		Added:   len(installed),
+		Removed: len(removed),
+		Applied: applied,
 	}
 
 	return SaveMetadata(path, meta)
 }
+
+// SyncResolved returns the IDs of packages applied by the last sync from source
+// that the Brewfile doesn't reflect yet (the sync ran after the last dump).
+// These are no longer pending even though the Brewfiles still differ.
+func (m *Metadata) SyncResolved(source string) map[string]bool {
+	resolved := make(map[string]bool)
+	if m == nil || m.LastSync.From != source || !m.LastSync.At.After(m.LastDump) {
+		return resolved
+	}
+	for _, id := range m.LastSync.Applied {
+		resolved[id] = true
+	}
+	return resolved
+}
+
+// EditedSinceDump reports whether the Brewfile was modified after the last
+// dump recorded in its metadata, typically by hand. A missing Brewfile or
+// metadata, or metadata without a dump, counts as not edited.
+func EditedSinceDump(brewfilePath string) (bool, error) {
+	meta, err := LoadMetadata(MetadataPath(brewfilePath))
+	if err != nil || meta.LastDump.IsZero() {
+		return false, nil
+	}
+	info, err := os.Stat(brewfilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return info.ModTime().After(meta.LastDump), nil
+}
+
+// ArchInfo describes the recorded architectures of two machines being compared
+type ArchInfo struct {
+	Source  string
+	Current string
+}
+
+// Mismatch returns true if both architectures are known and differ
+func (a ArchInfo) Mismatch() bool {
+	return a.Source != "" && a.Current != "" && a.Source != a.Current
+}
+
+// CompareArch loads the recorded architectures for two Brewfiles from their metadata.
+// Unknown architectures (missing metadata or arch field) are left empty.
+func CompareArch(sourceBrewfile, currentBrewfile string) ArchInfo {
+	var info ArchInfo
+	if meta, err := LoadMetadata(MetadataPath(sourceBrewfile)); err == nil {
+		info.Source = meta.Arch
+	}
+	if meta, err := LoadMetadata(MetadataPath(currentBrewfile)); err == nil {
+		info.Current = meta.Arch
+	}
+	return info
+}
@@ -0,0 +1,157 @@
+package brewfile
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMachineMeta(t *testing.T, root, machine, arch string) string {
+	t.Helper()
+	dir := filepath.Join(root, "_brew_"+machine)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	brewfilePath := filepath.Join(dir, "Brewfile")
+	if arch != "" {
+		require.NoError(t, SaveMetadata(MetadataPath(brewfilePath), &Metadata{Machine: machine, Arch: arch}))
+	}
+	return brewfilePath
+}
+
+func TestMetadataPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/dotfiles/_brew_mini", ".brewsync-meta"), MetadataPath("/dotfiles/_brew_mini/Brewfile"))
+}
+
+func TestUpdateMetadata_RecordsArch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), MetadataFileName)
+	pkgs := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeCask, "firefox")}
+
+	require.NoError(t, UpdateMetadata(path, "mini", pkgs, "dev"))
+
+	meta, err := LoadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, runtime.GOARCH, meta.Arch)
+	assert.Equal(t, 1, meta.PackageCounts["brew"])
+	assert.Equal(t, 1, meta.PackageCounts["cask"])
+}
+
+func TestCompareArch(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceArch string
+		curArch    string
+		mismatch   bool
+	}{
+		{"different arches", "arm64", "amd64", true},
+		{"same arch", "arm64", "arm64", false},
+		{"source unknown", "", "amd64", false},
+		{"both unknown", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			source := writeMachineMeta(t, root, "air", tt.sourceArch)
+			current := writeMachineMeta(t, root, "mini", tt.curArch)
+
+			info := CompareArch(source, current)
+			assert.Equal(t, tt.sourceArch, info.Source)
+			assert.Equal(t, tt.curArch, info.Current)
+			assert.Equal(t, tt.mismatch, info.Mismatch())
+		})
+	}
+}
+
+func TestUpdateSyncMetadata_ResolvesPending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), MetadataFileName)
+
+	source := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "jq"), NewPackage(TypeCask, "firefox")}
+	current := Packages{NewPackage(TypeBrew, "git"), NewPackage(TypeBrew, "wget")}
+	require.NoError(t, UpdateMetadata(path, "air", current, "dev"))
+
+	// Sync installs jq and removes wget; firefox failed to install
+	installed := Packages{NewPackage(TypeBrew, "jq")}
+	removed := Packages{NewPackage(TypeBrew, "wget")}
+	require.NoError(t, UpdateSyncMetadata(path, "mini", installed, removed))
+
+	meta, err := LoadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mini", meta.LastSync.From)
+	assert.Equal(t, 1, meta.LastSync.Added)
+	assert.Equal(t, 1, meta.LastSync.Removed)
+	assert.Equal(t, []string{"brew:jq", "brew:wget"}, meta.LastSync.Applied)
+	assert.False(t, meta.LastSync.At.IsZero())
+	assert.Equal(t, "air", meta.Machine, "dump info is preserved")
+
+	// Brewfile not re-dumped yet: only the failed package is still pending
+	diff := Diff(source, current)
+	resolved := meta.SyncResolved("mini")
+	assert.Equal(t, []string{"firefox"}, diff.Additions.Exclude(resolved).Names())
+	assert.Empty(t, diff.Removals.Exclude(resolved))
+
+	// Resolved packages only apply to the source that was synced from
+	assert.Empty(t, meta.SyncResolved("studio"))
+
+	// Applying again before a dump keeps what the first sync applied
+	require.NoError(t, UpdateSyncMetadata(path, "mini", Packages{NewPackage(TypeCask, "firefox")}, nil))
+	meta, err = LoadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:jq", "brew:wget", "cask:firefox"}, meta.LastSync.Applied)
+	assert.Equal(t, 1, meta.LastSync.Added)
+	resolved = meta.SyncResolved("mini")
+	assert.Empty(t, diff.Additions.Exclude(resolved))
+	assert.Empty(t, diff.Removals.Exclude(resolved))
+
+	// A sync from another machine starts over
+	require.NoError(t, UpdateSyncMetadata(path, "studio", installed, nil))
+	meta, err = LoadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:jq"}, meta.LastSync.Applied)
+
+	// A dump after the sync makes the Brewfile authoritative again
+	require.NoError(t, UpdateMetadata(path, "air", current, "dev"))
+	meta, err = LoadMetadata(path)
+	require.NoError(t, err)
+	assert.Empty(t, meta.SyncResolved("studio"))
+	assert.Equal(t, 1, meta.LastSync.Added, "last sync survives a dump")
+
+	// and the next sync's applied packages start from scratch
+	require.NoError(t, UpdateSyncMetadata(path, "studio", nil, removed))
+	meta, err = LoadMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:wget"}, meta.LastSync.Applied)
+}
+
+func TestEditedSinceDump(t *testing.T) {
+	dir := t.TempDir()
+	brewfilePath := filepath.Join(dir, "Brewfile")
+
+	// Nothing to compare yet
+	edited, err := EditedSinceDump(brewfilePath)
+	require.NoError(t, err)
+	assert.False(t, edited)
+
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"git\"\n"), 0644))
+	edited, err = EditedSinceDump(brewfilePath)
+	require.NoError(t, err)
+	assert.False(t, edited, "no metadata")
+
+	lastDump := time.Now().Add(-time.Hour)
+	require.NoError(t, SaveMetadata(MetadataPath(brewfilePath), &Metadata{Machine: "mini", LastDump: lastDump}))
+
+	// Written by the dump, before the metadata
+	require.NoError(t, os.Chtimes(brewfilePath, lastDump.Add(-time.Second), lastDump.Add(-time.Second)))
+	edited, err = EditedSinceDump(brewfilePath)
+	require.NoError(t, err)
+	assert.False(t, edited)
+
+	// Edited by hand afterwards
+	require.NoError(t, os.Chtimes(brewfilePath, lastDump.Add(time.Minute), lastDump.Add(time.Minute)))
+	edited, err = EditedSinceDump(brewfilePath)
+	require.NoError(t, err)
+	assert.True(t, edited)
+}
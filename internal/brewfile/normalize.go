@@ -0,0 +1,113 @@
+package brewfile
+
+import (
+	"regexp"
+	"strings"
+)
+
+// hashRocketPattern matches an option written in the old Ruby hash syntax
+var hashRocketPattern = regexp.MustCompile(`:(\w+)\s*=>\s*`)
+
+// normalizeLine rewrites a Brewfile line into the form current 'brew bundle
+// dump' writes, since older brew versions and hand-written Brewfiles differ:
+//
+//   - single-quoted strings ('git') become double-quoted
+//   - old hash-rocket options (:restart_service => true) become restart_service: true
+//   - a trailing comment (brew "git" # Distributed revision control) is split off
+//
+// It returns the line and the trailing comment without its "#". Lines already
+// in the current form come back unchanged.
+func normalizeLine(line string) (string, string) {
+	var sb strings.Builder
+	comment := ""
+	var open byte // the quote character of the string being read, or 0
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case open != 0 && c == '\\' && i+1 < len(line):
+			// Escapes carry over; in a single-quoted string only \' and \\ are
+			// escapes, and \' needs no escape once double-quoted
+			if open == '\'' && line[i+1] == '\'' {
+				sb.WriteByte('\'')
+			} else {
+				sb.WriteByte(c)
+				sb.WriteByte(line[i+1])
+			}
+			i++
+		case open != 0 && c == open:
+			sb.WriteByte('"')
+			open = 0
+		case open == '\'' && c == '"':
+			sb.WriteString(`\"`)
+		case open != 0:
+			sb.WriteByte(c)
+		case c == '"' || c == '\'':
+			sb.WriteByte('"')
+			open = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			comment = strings.TrimSpace(line[i+1:])
+			i = len(line)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	normalized := strings.TrimSpace(sb.String())
+	if strings.Contains(normalized, "=>") {
+		normalized = hashRocketPattern.ReplaceAllString(normalized, "$1: ")
+	}
+	return normalized, comment
+}
+
+// unquote reverses the escaping of a double-quoted Brewfile string
+func unquote(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// quote escapes s for use as a double-quoted Brewfile string
+func quote(s string) string {
+	if !strings.ContainsAny(s, `"\`) {
+		return s
+	}
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// splitOptions splits an option list ("link: true, args: ["a", "b"]") at the
+// commas between options, leaving those inside strings, arrays and hashes
+func splitOptions(s string) []string {
+	var parts []string
+	depth := 0
+	inString := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		parts = append(parts, rest)
+	}
+	return parts
+}
@@ -0,0 +1,50 @@
+package brewfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    string
+		comment string
+	}{
+		{`brew "git"`, `brew "git"`, ""},
+		{`brew 'git'`, `brew "git"`, ""},
+		{`mas 'Say "Hi"', id: 1`, `mas "Say \"Hi\"", id: 1`, ""},
+		{`mas 'It\'s', id: 1`, `mas "It's", id: 1`, ""},
+		{`brew "postgresql", :restart_service => true`, `brew "postgresql", restart_service: true`, ""},
+		{`brew "git" # version control`, `brew "git"`, "version control"},
+		{`cask "c#-tools"`, `cask "c#-tools"`, ""},
+		{`brew "git", args: ["a # b"]   # note`, `brew "git", args: ["a # b"]`, "note"},
+	}
+
+	for _, tt := range tests {
+		got, comment := normalizeLine(tt.line)
+		assert.Equal(t, tt.want, got, tt.line)
+		assert.Equal(t, tt.comment, comment, tt.line)
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	for _, s := range []string{"Xcode", `Say "Hi"`, `back\slash`} {
+		assert.Equal(t, s, unquote(quote(s)))
+	}
+
+	packages, err := ParseContent(`mas "Say \"Hi\"", id: 42` + "\n")
+	require.NoError(t, err)
+	require.Len(t, packages, 1)
+	assert.Equal(t, `Say "Hi"`, packages[0].Name)
+	assert.Equal(t, "mas \"Say \\\"Hi\\\"\", id: 42\n", NewWriter(packages).Format())
+}
+
+func TestSplitOptions(t *testing.T) {
+	assert.Equal(t, []string{"link: true", `args: ["a", "b"]`, `conflicts_with: ["x"]`},
+		splitOptions(`link: true, args: ["a", "b"], conflicts_with: ["x"]`))
+	assert.Equal(t, []string{`args: { appdir: "~/A, B" }`}, splitOptions(`args: { appdir: "~/A, B" }`))
+	assert.Nil(t, splitOptions(""))
+}
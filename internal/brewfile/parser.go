@@ -3,6 +3,7 @@ package brewfile
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
@@ -16,43 +17,52 @@ func NewParser() *Parser {
 	return &Parser{}
 }
 
-// Patterns for parsing Brewfile lines
+// quotedName matches a double-quoted name, which may contain escaped quotes
+const quotedName = `"((?:[^"\\]|\\.)+)"`
+
+// Patterns for parsing Brewfile lines (after normalizeLine)
 var (
 	// Match: tap "name" or tap "name", args
-	tapPattern = regexp.MustCompile(`^tap\s+"([^"]+)"(?:\s*,\s*(.+))?`)
+	tapPattern = regexp.MustCompile(`^tap\s+` + quotedName + `(?:\s*,\s*(.+))?`)
+	// Match the custom clone URL leading a tap's args: "url" or "url", options
+	tapURLPattern = regexp.MustCompile(`^` + quotedName + `\s*(?:,\s*(.*))?$`)
 	// Match: brew "name" or brew "name", options
-	brewPattern = regexp.MustCompile(`^brew\s+"([^"]+)"(?:\s*,\s*(.+))?`)
+	brewPattern = regexp.MustCompile(`^brew\s+` + quotedName + `(?:\s*,\s*(.+))?`)
 	// Match: cask "name" or cask "name", options
-	caskPattern = regexp.MustCompile(`^cask\s+"([^"]+)"(?:\s*,\s*(.+))?`)
+	caskPattern = regexp.MustCompile(`^cask\s+` + quotedName + `(?:\s*,\s*(.+))?`)
 	// Match: mas "name", id: 123
-	masPattern = regexp.MustCompile(`^mas\s+"([^"]+)"(?:\s*,\s*(.+))?`)
-	// Match: vscode "name"
-	vscodePattern = regexp.MustCompile(`^vscode\s+"([^"]+)"`)
+	masPattern = regexp.MustCompile(`^mas\s+` + quotedName + `(?:\s*,\s*(.+))?`)
+	// Match: vscode "name" or vscode "name@version"
+	vscodePattern = regexp.MustCompile(`^vscode\s+` + quotedName)
 	// Match: cursor "name" (BrewSync extension)
-	cursorPattern = regexp.MustCompile(`^cursor\s+"([^"]+)"`)
+	cursorPattern = regexp.MustCompile(`^cursor\s+` + quotedName)
 	// Match: antigravity "name" (BrewSync extension)
-	antigravityPattern = regexp.MustCompile(`^antigravity\s+"([^"]+)"`)
+	antigravityPattern = regexp.MustCompile(`^antigravity\s+` + quotedName)
 	// Match: go "name" (BrewSync extension)
-	goPattern = regexp.MustCompile(`^go\s+"([^"]+)"`)
-	// Match options like: link: true, args: ["--foo"]
-	optionPattern = regexp.MustCompile(`(\w+):\s*(.+?)(?:,\s*|$)`)
+	goPattern = regexp.MustCompile(`^go\s+` + quotedName)
+	// Match: npm "name" (BrewSync extension)
+	npmPattern = regexp.MustCompile(`^npm\s+` + quotedName)
+	// Match: pipx "name" (BrewSync extension)
+	pipxPattern = regexp.MustCompile(`^pipx\s+` + quotedName)
+	// Match global directives that apply to the whole Brewfile, e.g. cask_args appdir: "~/Applications"
+	directivePattern = regexp.MustCompile(`^cask_args\b`)
+	// Match one option like: link: true, args: ["--foo"]
+	optionPattern = regexp.MustCompile(`^(\w+):\s*(.+)$`)
 )
 
-// ParseFile parses a Brewfile from the given path
+// ParseFile parses a Brewfile from the given path, including the packages of
+// any Brewfiles it pulls in with IncludeDirective
 func (p *Parser) ParseFile(path string) (Packages, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
+	return p.parseFileWithIncludes(path, nil)
+}
 
+// ParseReader parses Brewfile content from a reader (e.g. stdin)
+func (p *Parser) ParseReader(r io.Reader) (Packages, error) {
 	var packages Packages
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+	scanner := bufio.NewScanner(r)
 	var lastComment string // Track comment from previous line
 
 	for scanner.Scan() {
-		lineNum++
 		line := strings.TrimSpace(scanner.Text())
 
 		// Skip empty lines
@@ -60,6 +70,12 @@ func (p *Parser) ParseFile(path string) (Packages, error) {
 			continue
 		}
 
+		// Includes are resolved by ParseFile, not descriptions
+		if _, ok := includeTarget(line); ok {
+			lastComment = ""
+			continue
+		}
+
 		// Capture comments as potential descriptions
 		if strings.HasPrefix(line, "#") {
 			// Extract comment text (remove leading # and whitespace)
@@ -84,64 +100,68 @@ func (p *Parser) ParseFile(path string) (Packages, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+		return nil, fmt.Errorf("error reading Brewfile: %w", err)
 	}
 
 	return packages, nil
 }
 
-// ParseString parses Brewfile content from a string
-func (p *Parser) ParseString(content string) (Packages, error) {
-	var packages Packages
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	var lastComment string // Track comment from previous line
-
+// ParsePreamble returns the global directive lines (such as cask_args) from
+// Brewfile content. Directives are not packages and are ignored by ParseReader.
+func (p *Parser) ParsePreamble(r io.Reader) ([]string, error) {
+	var preamble []string
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines
-		if line == "" {
-			continue
+		if directivePattern.MatchString(line) {
+			preamble = append(preamble, line)
 		}
+	}
 
-		// Capture comments as potential descriptions
-		if strings.HasPrefix(line, "#") {
-			// Extract comment text (remove leading # and whitespace)
-			lastComment = strings.TrimSpace(strings.TrimPrefix(line, "#"))
-			continue
-		}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Brewfile: %w", err)
+	}
 
-		pkg, ok := p.parseLine(line)
-		if !ok {
-			lastComment = "" // Reset if we skip a line
-			continue
-		}
+	return preamble, nil
+}
 
-		// Attach the last comment as description if available
-		if lastComment != "" {
-			pkg.Description = lastComment
-			lastComment = "" // Reset after using
-		}
+// ParseString parses Brewfile content from a string
+func (p *Parser) ParseString(content string) (Packages, error) {
+	return p.ParseReader(strings.NewReader(content))
+}
 
-		packages = append(packages, pkg)
+// parseLine parses a single Brewfile line. A trailing comment becomes the
+// package's description.
+func (p *Parser) parseLine(line string) (Package, bool) {
+	line, comment := normalizeLine(line)
+	pkg, ok := p.matchLine(line)
+	if ok && comment != "" {
+		pkg.Description = comment
 	}
-
-	return packages, nil
+	return pkg, ok
 }
 
-// parseLine parses a single Brewfile line
-func (p *Parser) parseLine(line string) (Package, bool) {
+// matchLine parses a normalized Brewfile line
+func (p *Parser) matchLine(line string) (Package, bool) {
 	// Try each pattern in order
 	if matches := tapPattern.FindStringSubmatch(line); matches != nil {
-		pkg := NewPackage(TypeTap, matches[1])
-		if len(matches) > 2 && matches[2] != "" {
-			pkg = p.parseOptions(matches[2], pkg)
+		pkg := NewPackage(TypeTap, unquote(matches[1]))
+		args := ""
+		if len(matches) > 2 {
+			args = matches[2]
+		}
+		if url := tapURLPattern.FindStringSubmatch(args); url != nil {
+			pkg.URL = unquote(url[1])
+			args = url[2]
+		}
+		if args != "" {
+			pkg = p.parseOptions(args, pkg)
 		}
 		return pkg, true
 	}
 
 	if matches := brewPattern.FindStringSubmatch(line); matches != nil {
-		pkg := NewPackage(TypeBrew, matches[1])
+		pkg := NewPackage(TypeBrew, unquote(matches[1]))
 		if len(matches) > 2 && matches[2] != "" {
 			pkg = p.parseOptions(matches[2], pkg)
 		}
@@ -149,7 +169,7 @@ func (p *Parser) parseLine(line string) (Package, bool) {
 	}
 
 	if matches := caskPattern.FindStringSubmatch(line); matches != nil {
-		pkg := NewPackage(TypeCask, matches[1])
+		pkg := NewPackage(TypeCask, unquote(matches[1]))
 		if len(matches) > 2 && matches[2] != "" {
 			pkg = p.parseOptions(matches[2], pkg)
 		}
@@ -157,7 +177,7 @@ func (p *Parser) parseLine(line string) (Package, bool) {
 	}
 
 	if matches := masPattern.FindStringSubmatch(line); matches != nil {
-		pkg := NewPackage(TypeMas, matches[1])
+		pkg := NewPackage(TypeMas, unquote(matches[1]))
 		if len(matches) > 2 && matches[2] != "" {
 			pkg = p.parseOptions(matches[2], pkg)
 		}
@@ -165,39 +185,51 @@ func (p *Parser) parseLine(line string) (Package, bool) {
 	}
 
 	if matches := vscodePattern.FindStringSubmatch(line); matches != nil {
-		return NewPackage(TypeVSCode, matches[1]), true
+		return newExtension(TypeVSCode, unquote(matches[1])), true
 	}
 
 	if matches := cursorPattern.FindStringSubmatch(line); matches != nil {
-		return NewPackage(TypeCursor, matches[1]), true
+		return newExtension(TypeCursor, unquote(matches[1])), true
 	}
 
 	if matches := antigravityPattern.FindStringSubmatch(line); matches != nil {
-		return NewPackage(TypeAntigravity, matches[1]), true
+		return newExtension(TypeAntigravity, unquote(matches[1])), true
 	}
 
 	if matches := goPattern.FindStringSubmatch(line); matches != nil {
-		return NewPackage(TypeGo, matches[1]), true
+		return NewPackage(TypeGo, unquote(matches[1])), true
+	}
+
+	if matches := npmPattern.FindStringSubmatch(line); matches != nil {
+		return NewPackage(TypeNpm, unquote(matches[1])), true
+	}
+
+	if matches := pipxPattern.FindStringSubmatch(line); matches != nil {
+		return NewPackage(TypePipx, unquote(matches[1])), true
 	}
 
 	return Package{}, false
 }
 
+// newExtension creates an editor extension from its spec, which may pin a
+// version ("pub.ext@1.2.3")
+func newExtension(t PackageType, spec string) Package {
+	name, version := SplitExtensionVersion(spec)
+	pkg := NewPackage(t, name)
+	pkg.Version = version
+	return pkg
+}
+
 // parseOptions parses option string like "link: true, args: [\"--foo\"]"
 func (p *Parser) parseOptions(optStr string, pkg Package) Package {
 	if pkg.Options == nil {
 		pkg.Options = make(map[string]string)
 	}
-	// Handle simple key: value patterns
-	matches := optionPattern.FindAllStringSubmatch(optStr, -1)
-	for _, match := range matches {
-		if len(match) >= 3 {
-			key := match[1]
-			value := strings.TrimSpace(match[2])
-			// Remove trailing comma if present
-			value = strings.TrimSuffix(value, ",")
+	// Split at top-level commas so array values keep their elements
+	for _, opt := range splitOptions(optStr) {
+		if match := optionPattern.FindStringSubmatch(opt); match != nil {
 			// Store the option as string
-			pkg.Options[key] = p.parseValueAsString(value)
+			pkg.Options[match[1]] = p.parseValueAsString(strings.TrimSpace(match[2]))
 		}
 	}
 	return pkg
@@ -219,3 +251,62 @@ func Parse(path string) (Packages, error) {
 func ParseContent(content string) (Packages, error) {
 	return NewParser().ParseString(content)
 }
+
+// ParseReader is a convenience function to parse Brewfile content from a reader
+func ParseReader(r io.Reader) (Packages, error) {
+	return NewParser().ParseReader(r)
+}
+
+// ReadPreamble returns the global directive lines from the Brewfile at path.
+// A missing file has no preamble.
+func ReadPreamble(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return NewParser().ParsePreamble(file)
+}
+
+// ReadHeader returns the brewsync header (see Header) at the top of the Brewfile
+// at path: the leading comment lines, if they include the brewsync marker.
+// A missing file, or one without the header, returns nil.
+func ReadHeader(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var lines []string
+	found := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		if _, ok := includeTarget(line); ok {
+			break
+		}
+		lines = append(lines, line)
+		if strings.HasPrefix(line, headerMarker) {
+			found = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading Brewfile: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return lines, nil
+}
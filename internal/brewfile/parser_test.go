@@ -3,6 +3,7 @@ package brewfile
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,23 @@ tap "homebrew/cask-fonts"
 	assert.Equal(t, "charmbracelet/tap", packages[1].Name)
 }
 
+func TestParser_ParseString_TapURL(t *testing.T) {
+	content := `
+tap "company/private", "git@github.com:company/homebrew-private.git"
+tap "user/tools", "https://example.com/tools.git", force_auto_update: true
+tap "homebrew/bundle"
+`
+	packages, err := NewParser().ParseString(content)
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+
+	assert.Equal(t, "company/private", packages[0].Name)
+	assert.Equal(t, "git@github.com:company/homebrew-private.git", packages[0].URL)
+	assert.Equal(t, "https://example.com/tools.git", packages[1].URL)
+	assert.Equal(t, "true", packages[1].Options["force_auto_update"])
+	assert.Empty(t, packages[2].URL)
+}
+
 func TestParser_ParseString_Brews(t *testing.T) {
 	content := `
 brew "git"
@@ -99,6 +117,25 @@ vscode "vscodevim.vim"
 	assert.Equal(t, "golang.go", packages[0].Name)
 }
 
+func TestParser_ParseString_ExtensionVersions(t *testing.T) {
+	content := `
+vscode "golang.go@0.42.0"
+cursor "ms-python.python"
+antigravity "vscodevim.vim@1.29.0"
+`
+	packages, err := NewParser().ParseString(content)
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+
+	assert.Equal(t, "golang.go", packages[0].Name)
+	assert.Equal(t, "0.42.0", packages[0].Version)
+	assert.Equal(t, "vscode:golang.go", packages[0].ID())
+	assert.Equal(t, "ms-python.python", packages[1].Name)
+	assert.Empty(t, packages[1].Version)
+	assert.Equal(t, TypeAntigravity, packages[2].Type)
+	assert.Equal(t, "1.29.0", packages[2].Version)
+}
+
 func TestParser_ParseString_Cursor(t *testing.T) {
 	content := `
 # BrewSync extension: Cursor extensions
@@ -132,6 +169,39 @@ go "github.com/go-delve/delve/cmd/dlv"
 	assert.Equal(t, "golang.org/x/tools/gopls", packages[0].Name)
 }
 
+func TestParser_ParseString_Npm(t *testing.T) {
+	content := `
+# npm (brewsync extension)
+npm "prettier"
+npm "@angular/cli"
+`
+	packages, err := NewParser().ParseString(content)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"npm:prettier", "npm:@angular/cli"}, packages.IDs())
+
+	// Written back out, the entries parse to the same packages
+	again, err := NewParser().ParseString(NewWriter(packages).Format())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, packages.IDs(), again.IDs())
+}
+
+func TestParser_ParseString_Pipx(t *testing.T) {
+	content := `
+# pipx (brewsync extension)
+pipx "black"
+pipx "poetry"
+`
+	packages, err := NewParser().ParseString(content)
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+	assert.Equal(t, TypePipx, packages[0].Type)
+	assert.Equal(t, "black", packages[0].Name)
+
+	again, err := NewParser().ParseString(NewWriter(packages).Format())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, packages.IDs(), again.IDs())
+}
+
 func TestParser_ParseString_Comments(t *testing.T) {
 	content := `
 # This is a comment
@@ -241,6 +311,19 @@ func TestParseContent_Convenience(t *testing.T) {
 	assert.Len(t, packages, 1)
 }
 
+func TestParseReader(t *testing.T) {
+	content := `tap "homebrew/cask"
+# Distributed revision control system
+brew "git"
+cask "firefox"
+`
+	packages, err := ParseReader(strings.NewReader(content))
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+	assert.Equal(t, "git", packages[1].Name)
+	assert.Equal(t, "Distributed revision control system", packages[1].Description)
+}
+
 func TestParser_ParseOptions_Link(t *testing.T) {
 	content := `brew "libpq", link: true`
 	packages, err := ParseContent(content)
@@ -321,3 +404,138 @@ cask "raycast"`
 		assert.Empty(t, pkg.Description)
 	}
 }
+
+func TestParser_GlobalDirectives(t *testing.T) {
+	content := `cask_args appdir: "~/Applications", require_sha: true
+tap "homebrew/bundle"
+brew "postgresql@16", restart_service: :changed
+cask "firefox"
+`
+	pkgs, err := ParseContent(content)
+	require.NoError(t, err)
+
+	// cask_args is metadata, not a package (and not a cask)
+	require.Len(t, pkgs, 3)
+	assert.Equal(t, []string{"firefox"}, pkgs.Filter(TypeCask).Names())
+	assert.Equal(t, ":changed", pkgs[1].Options["restart_service"])
+
+	preamble, err := NewParser().ParsePreamble(strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, []string{`cask_args appdir: "~/Applications", require_sha: true`}, preamble)
+}
+
+func TestReadPreamble_MissingFile(t *testing.T) {
+	preamble, err := ReadPreamble(filepath.Join(t.TempDir(), "Brewfile"))
+	require.NoError(t, err)
+	assert.Empty(t, preamble)
+}
+
+// TestParse_BundleDumpFixtures parses Brewfiles in the formats different brew
+// versions' 'brew bundle dump' (and hand editing) produce
+func TestParse_BundleDumpFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		ids     []string
+		check   func(t *testing.T, pkgs map[string]Package)
+	}{
+		{
+			// Hash-rocket options and single quotes from older and hand-edited files
+			fixture: "brew-2.x.Brewfile",
+			ids: []string{
+				"tap:homebrew/bundle", "tap:homebrew/cask", "tap:homebrew/core", "tap:denji/nginx",
+				"brew:git", "brew:wget", "brew:mysql@5.7", "brew:postgresql", "brew:denji/nginx/nginx-full",
+				"cask:google-chrome", "cask:firefox",
+				"mas:Xcode", "mas:1Password 7 - Password Manager",
+			},
+			check: func(t *testing.T, pkgs map[string]Package) {
+				assert.Equal(t, map[string]string{"restart_service": "true", "link": "true"}, pkgs["brew:mysql@5.7"].Options)
+				assert.Equal(t, "true", pkgs["brew:postgresql"].Options["restart_service"])
+				assert.Equal(t, `["with-rtmp-module", "with-debug"]`, pkgs["brew:denji/nginx/nginx-full"].Options["args"])
+				assert.Equal(t, `{ appdir: "~/Applications" }`, pkgs["cask:firefox"].Options["args"])
+				assert.Equal(t, "1333542190", pkgs["mas:1Password 7 - Password Manager"].Options["id"])
+			},
+		},
+		{
+			// --describe comments, symbol options and cask_args
+			fixture: "brew-3.x.Brewfile",
+			ids: []string{
+				"tap:homebrew/bundle", "tap:homebrew/cask-fonts",
+				"brew:git", "brew:jq", "brew:postgresql@14",
+				"cask:firefox", "cask:font-fira-code",
+				"mas:Things 3", "mas:Magnet - Window Manager", "mas:Keynote",
+			},
+			check: func(t *testing.T, pkgs map[string]Package) {
+				assert.Equal(t, "Distributed revision control system", pkgs["brew:git"].Description)
+				assert.Equal(t, ":changed", pkgs["brew:postgresql@14"].Options["restart_service"])
+				assert.Equal(t, "Web browser", pkgs["cask:firefox"].Description)
+				assert.Empty(t, pkgs["cask:font-fira-code"].Description)
+				assert.Equal(t, "441258766", pkgs["mas:Magnet - Window Manager"].Options["id"])
+			},
+		},
+		{
+			// Tap URLs, tap-qualified formulae, vscode and go entries; cargo isn't supported
+			fixture: "brew-4.x.Brewfile",
+			ids: []string{
+				"tap:homebrew/services", "tap:oven-sh/bun", "tap:company/private",
+				"brew:oven-sh/bun/bun", "brew:git", "brew:python@3.12",
+				"cask:ghostty", "cask:docker",
+				"mas:Xcode",
+				"vscode:golang.go", "vscode:ms-python.python",
+				"go:golang.org/x/tools/gopls",
+			},
+			check: func(t *testing.T, pkgs map[string]Package) {
+				assert.Equal(t, "git@github.com:company/homebrew-private.git", pkgs["tap:company/private"].URL)
+				assert.Equal(t, "oven-sh/bun", pkgs["brew:oven-sh/bun/bun"].Tap)
+				assert.Equal(t, "false", pkgs["brew:python@3.12"].Options["link"])
+				assert.Equal(t, "true", pkgs["cask:docker"].Options["greedy"])
+			},
+		},
+		{
+			// Single quotes, inline comments, indentation and Ruby conditionals
+			fixture: "handwritten.Brewfile",
+			ids: []string{
+				"tap:homebrew/bundle",
+				"brew:git", "brew:htop", "brew:tmux",
+				"cask:iterm2",
+				"mas:Slack for Desktop",
+			},
+			check: func(t *testing.T, pkgs map[string]Package) {
+				assert.Equal(t, "Shared packages", pkgs["tap:homebrew/bundle"].Description)
+				assert.Equal(t, "version control", pkgs["brew:git"].Description)
+				assert.Equal(t, `["--HEAD"]`, pkgs["brew:htop"].Options["args"])
+				assert.Equal(t, "terminal", pkgs["cask:iterm2"].Description)
+				assert.Equal(t, "803453959", pkgs["mas:Slack for Desktop"].Options["id"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			packages, err := Parse(filepath.Join("testdata", "bundle", tt.fixture))
+			require.NoError(t, err)
+			assert.Equal(t, tt.ids, packages.IDs())
+
+			byID := make(map[string]Package, len(packages))
+			for _, pkg := range packages {
+				byID[pkg.ID()] = pkg
+			}
+			tt.check(t, byID)
+
+			// Writing the parsed packages back and re-reading them loses nothing
+			reparsed, err := ParseContent(NewWriter(packages).Format())
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.ids, reparsed.IDs())
+			for _, pkg := range reparsed {
+				assert.Equal(t, byID[pkg.ID()].Options, pkg.Options, pkg.ID())
+			}
+		})
+	}
+}
+
+func TestParser_ParseString_CRLF(t *testing.T) {
+	packages, err := ParseContent("tap \"homebrew/bundle\"\r\n# Distributed revision control system\r\nbrew \"git\", link: true\r\n")
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+	assert.Equal(t, "Distributed revision control system", packages[1].Description)
+	assert.Equal(t, "true", packages[1].Options["link"])
+}
@@ -2,7 +2,10 @@ package brewfile
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
 // PackageType represents the type of package
@@ -16,6 +19,8 @@ const (
 	TypeCursor      PackageType = "cursor"
 	TypeAntigravity PackageType = "antigravity"
 	TypeGo          PackageType = "go"
+	TypeNpm         PackageType = "npm"
+	TypePipx        PackageType = "pipx"
 	TypeMas         PackageType = "mas"
 )
 
@@ -29,10 +34,24 @@ func AllTypes() []PackageType {
 		TypeCursor,
 		TypeAntigravity,
 		TypeGo,
+		TypeNpm,
+		TypePipx,
 		TypeMas,
 	}
 }
 
+// EnabledTypes returns the package types, in AllTypes order, for which
+// enabled reports true (such as config's CategoryEnabled)
+func EnabledTypes(enabled func(category string) bool) []PackageType {
+	var types []PackageType
+	for _, t := range AllTypes() {
+		if enabled(string(t)) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
 // ParsePackageType parses a string into a PackageType
 func ParsePackageType(s string) (PackageType, error) {
 	switch strings.ToLower(s) {
@@ -50,6 +69,10 @@ func ParsePackageType(s string) (PackageType, error) {
 		return TypeAntigravity, nil
 	case "go":
 		return TypeGo, nil
+	case "npm":
+		return TypeNpm, nil
+	case "pipx":
+		return TypePipx, nil
 	case "mas":
 		return TypeMas, nil
 	default:
@@ -57,6 +80,66 @@ func ParsePackageType(s string) (PackageType, error) {
 	}
 }
 
+// CategoryAliases maps group names to the package types they expand to.
+// Aliases can be used anywhere a list of categories is accepted (e.g. --only editors).
+var CategoryAliases = map[string][]PackageType{
+	"editors": {TypeVSCode, TypeCursor, TypeAntigravity},
+	"cli":     {TypeBrew, TypeTap, TypeGo},
+	"apps":    {TypeCask, TypeMas},
+}
+
+// ParseCategories parses category names into package types.
+// Each value may be a comma-separated list and may contain type names or
+// aliases (optionally prefixed with '@'). Duplicates are removed, order is kept.
+func ParseCategories(values ...string) ([]PackageType, error) {
+	var result []PackageType
+	seen := make(map[PackageType]bool)
+	add := func(t PackageType) {
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			if types, ok := CategoryAliases[strings.TrimPrefix(name, "@")]; ok {
+				for _, t := range types {
+					add(t)
+				}
+				continue
+			}
+
+			t, err := ParsePackageType(name)
+			if err != nil {
+				return nil, fmt.Errorf("unknown category: %s (valid: %s)", name, validCategoryNames())
+			}
+			add(t)
+		}
+	}
+
+	return result, nil
+}
+
+// validCategoryNames returns all type names and aliases for error messages
+func validCategoryNames() string {
+	var names []string
+	for _, t := range AllTypes() {
+		names = append(names, string(t))
+	}
+	aliases := make([]string, 0, len(CategoryAliases))
+	for alias := range CategoryAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return strings.Join(append(names, aliases...), ", ")
+}
+
 // Package represents a single package entry
 type Package struct {
 	Type        PackageType       `json:"type" yaml:"type"`
@@ -64,14 +147,50 @@ type Package struct {
 	FullName    string            `json:"full_name,omitempty" yaml:"full_name,omitempty"` // For mas: app name
 	Options     map[string]string `json:"options,omitempty" yaml:"options,omitempty"`     // link: true, id: 123, etc.
 	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Tap         string            `json:"tap,omitempty" yaml:"tap,omitempty"`                  // For tap-qualified brew/cask names (user/tap/formula): "user/tap"
+	InstalledAt time.Time         `json:"installed_at,omitzero" yaml:"installed_at,omitempty"` // Local install time, when known (brew and cask only)
+	Version     string            `json:"version,omitempty" yaml:"version,omitempty"`          // Pinned editor extension version (vscode "pub.ext@1.2.3")
+	URL         string            `json:"url,omitempty" yaml:"url,omitempty"`                  // Custom clone URL of a tap (tap "user/repo", "git@host:user/repo.git")
 }
 
 // NewPackage creates a new package
 func NewPackage(t PackageType, name string) Package {
-	return Package{
+	pkg := Package{
 		Type: t,
 		Name: name,
 	}
+	if t == TypeBrew || t == TypeCask {
+		pkg.Tap = TapOf(name)
+	}
+	return pkg
+}
+
+// TapOf returns the tap embedded in a tap-qualified formula or cask name
+// ("user/tap/formula" -> "user/tap"), or "" for plain names
+func TapOf(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// BaseName returns the formula or cask name without its tap prefix
+// ("user/tap/ripgrep" -> "ripgrep")
+func (p Package) BaseName() string {
+	if p.Tap != "" && strings.HasPrefix(p.Name, p.Tap+"/") {
+		return strings.TrimPrefix(p.Name, p.Tap+"/")
+	}
+	return p.Name
+}
+
+// QualifiedName returns the name brew should install: the name as written,
+// prefixed with Tap if it isn't already tap-qualified
+func (p Package) QualifiedName() string {
+	if p.Tap != "" && !strings.HasPrefix(p.Name, p.Tap+"/") {
+		return p.Tap + "/" + p.Name
+	}
+	return p.Name
 }
 
 // WithOption adds an option to the package
@@ -88,6 +207,68 @@ func (p Package) ID() string {
 	return fmt.Sprintf("%s:%s", p.Type, p.Name)
 }
 
+// SplitExtensionVersion splits an editor extension spec as the Brewfile and
+// 'code --install-extension' take it ("pub.ext@1.2.3") into the extension ID
+// and the pinned version. Unpinned specs have no version.
+func SplitExtensionVersion(spec string) (name, version string) {
+	i := strings.LastIndex(spec, "@")
+	if i <= 0 || i == len(spec)-1 {
+		return spec, ""
+	}
+	return spec[:i], spec[i+1:]
+}
+
+// VersionedName returns an editor extension's ID with its pinned version
+// ("pub.ext@1.2.3"), or just the name when it has none
+func (p Package) VersionedName() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + "@" + p.Version
+}
+
+// IsEditorExtension returns true for VS Code-compatible editor extensions
+func (p Package) IsEditorExtension() bool {
+	return p.Type == TypeVSCode || p.Type == TypeCursor || p.Type == TypeAntigravity
+}
+
+// Key returns the key used to compare packages across machines.
+// For editor extensions, aliases (old ID -> new ID, case-insensitive) map
+// extensions that moved publishers to their current ID so both compare equal.
+// Taps with a custom URL include it, so the same tap cloned from elsewhere
+// shows up as a difference.
+func (p Package) Key(aliases map[string]string) string {
+	if p.Type == TypeTap && p.URL != "" {
+		return p.ID() + " " + p.URL
+	}
+	if p.IsEditorExtension() && len(aliases) > 0 {
+		if target, ok := resolveAlias(p.Name, aliases); ok {
+			return string(p.Type) + ":" + target
+		}
+	}
+	return p.ID()
+}
+
+// resolveAlias looks up an extension ID in an alias map, ignoring case
+func resolveAlias(name string, aliases map[string]string) (string, bool) {
+	if target, ok := aliases[name]; ok {
+		return target, true
+	}
+	for from, target := range aliases {
+		if strings.EqualFold(from, name) {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// KnownExtensionMoves lists editor extensions known to have moved publishers (old ID -> new ID).
+// doctor suggests adding these to extension_aliases when an old ID is found.
+var KnownExtensionMoves = map[string]string{
+	"ms-vscode.go":     "golang.go",
+	"ms-vscode.csharp": "ms-dotnettools.csharp",
+}
+
 // String returns a human-readable representation
 func (p Package) String() string {
 	if p.FullName != "" {
@@ -99,6 +280,17 @@ func (p Package) String() string {
 // Packages is a collection of packages
 type Packages []Package
 
+// WithoutVersions returns the packages with extension versions cleared,
+// for Brewfiles that shouldn't pin them
+func (ps Packages) WithoutVersions() Packages {
+	result := make(Packages, len(ps))
+	for i, p := range ps {
+		p.Version = ""
+		result[i] = p
+	}
+	return result
+}
+
 // ByType groups packages by their type
 func (ps Packages) ByType() map[PackageType][]Package {
 	result := make(map[PackageType][]Package)
@@ -108,6 +300,38 @@ func (ps Packages) ByType() map[PackageType][]Package {
 	return result
 }
 
+// Stats holds package counts, computed once by Packages.Stats
+type Stats struct {
+	Total  int
+	ByType map[PackageType]int
+}
+
+// Stats counts the packages in total and per type
+func (ps Packages) Stats() Stats {
+	stats := Stats{Total: len(ps), ByType: make(map[PackageType]int)}
+	for _, p := range ps {
+		stats.ByType[p.Type]++
+	}
+	return stats
+}
+
+// Count returns the number of packages of type t (0 if there are none)
+func (s Stats) Count(t PackageType) int {
+	return s.ByType[t]
+}
+
+// Counts returns the non-zero per-type counts keyed by type name, as stored
+// in metadata and JSON output
+func (s Stats) Counts() map[string]int {
+	counts := make(map[string]int, len(s.ByType))
+	for t, n := range s.ByType {
+		if n > 0 {
+			counts[string(t)] = n
+		}
+	}
+	return counts
+}
+
 // Filter returns packages matching the given types
 func (ps Packages) Filter(types ...PackageType) Packages {
 	if len(types) == 0 {
@@ -128,6 +352,51 @@ func (ps Packages) Filter(types ...PackageType) Packages {
 	return result
 }
 
+// KeepTypes returns packages with their entries of the given types replaced by
+// those in existing (e.g. to leave a category alone whose installer failed to
+// list what is installed)
+func (ps Packages) KeepTypes(existing Packages, types ...PackageType) Packages {
+	if len(types) == 0 {
+		return ps
+	}
+
+	kept := existing.Filter(types...)
+	result := make(Packages, 0, len(ps)+len(kept))
+	for _, p := range ps {
+		if !slices.Contains(types, p.Type) {
+			result = append(result, p)
+		}
+	}
+	return append(result, kept...)
+}
+
+// Exclude returns packages whose IDs are not in the excluded set
+// (e.g. upgrade candidates without pinned packages)
+func (ps Packages) Exclude(excluded map[string]bool) Packages {
+	var result Packages
+	for _, p := range ps {
+		if !excluded[p.ID()] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// InstalledSince splits packages by install time: those installed after
+// cutoff, and those without a known install time (taps, extensions and
+// anything not looked up). Packages installed before cutoff are dropped.
+func (ps Packages) InstalledSince(cutoff time.Time) (recent, undated Packages) {
+	for _, p := range ps {
+		switch {
+		case p.InstalledAt.IsZero():
+			undated = append(undated, p)
+		case p.InstalledAt.After(cutoff):
+			recent = append(recent, p)
+		}
+	}
+	return recent, undated
+}
+
 // Names returns just the names of packages
 func (ps Packages) Names() []string {
 	names := make([]string, len(ps))
@@ -137,6 +406,16 @@ func (ps Packages) Names() []string {
 	return names
 }
 
+// IDs returns the "type:name" IDs of packages, which unlike names stay
+// distinct for a formula and a cask of the same name
+func (ps Packages) IDs() []string {
+	ids := make([]string, len(ps))
+	for i, p := range ps {
+		ids[i] = p.ID()
+	}
+	return ids
+}
+
 // Contains checks if a package with the given ID exists
 func (ps Packages) Contains(id string) bool {
 	for _, p := range ps {
@@ -2,14 +2,22 @@ package brewfile
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func TestEnabledTypes(t *testing.T) {
+	enabled := map[string]bool{"mas": true, "brew": true, "npm": true}
+	types := EnabledTypes(func(category string) bool { return enabled[category] })
+	assert.Equal(t, []PackageType{TypeBrew, TypeNpm, TypeMas}, types)
+}
+
 func TestAllTypes(t *testing.T) {
 	types := AllTypes()
 
-	assert.Len(t, types, 8)
+	assert.Len(t, types, 10)
 	assert.Contains(t, types, TypeTap)
 	assert.Contains(t, types, TypeBrew)
 	assert.Contains(t, types, TypeCask)
@@ -17,6 +25,8 @@ func TestAllTypes(t *testing.T) {
 	assert.Contains(t, types, TypeCursor)
 	assert.Contains(t, types, TypeAntigravity)
 	assert.Contains(t, types, TypeGo)
+	assert.Contains(t, types, TypeNpm)
+	assert.Contains(t, types, TypePipx)
 	assert.Contains(t, types, TypeMas)
 }
 
@@ -35,6 +45,8 @@ func TestParsePackageType(t *testing.T) {
 		{"vscode", TypeVSCode, false},
 		{"cursor", TypeCursor, false},
 		{"go", TypeGo, false},
+		{"npm", TypeNpm, false},
+		{"pipx", TypePipx, false},
 		{"mas", TypeMas, false},
 		{"invalid", "", true},
 		{"", "", true},
@@ -128,6 +140,36 @@ func TestPackages_ByType(t *testing.T) {
 	assert.Len(t, byType[TypeVSCode], 0)
 }
 
+func TestPackages_Stats(t *testing.T) {
+	packages := Packages{
+		NewPackage(TypeTap, "homebrew/core"),
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeBrew, "fzf"),
+		NewPackage(TypeCask, "raycast"),
+		NewPackage(TypeCask, "git"),
+		NewPackage(TypeVSCode, "golang.go"),
+		NewPackage(TypeMas, "Xcode"),
+	}
+
+	stats := packages.Stats()
+	assert.Equal(t, len(packages), stats.Total)
+
+	byType := packages.ByType()
+	for _, typ := range AllTypes() {
+		assert.Equal(t, len(byType[typ]), stats.Count(typ), "count for %s", typ)
+	}
+	assert.Equal(t, 0, stats.Count(TypeGo))
+	assert.Equal(t, 0, stats.Count(TypeCursor))
+
+	// Counts only lists types that have packages
+	assert.Equal(t, map[string]int{"tap": 1, "brew": 2, "cask": 2, "vscode": 1, "mas": 1}, stats.Counts())
+
+	empty := Packages{}.Stats()
+	assert.Equal(t, 0, empty.Total)
+	assert.Equal(t, 0, empty.Count(TypeBrew))
+	assert.Empty(t, empty.Counts())
+}
+
 func TestPackages_Filter(t *testing.T) {
 	packages := Packages{
 		NewPackage(TypeBrew, "git"),
@@ -157,6 +199,54 @@ func TestPackages_Filter(t *testing.T) {
 	})
 }
 
+func TestPackage_Key(t *testing.T) {
+	aliases := map[string]string{"ms-vscode.go": "golang.go"}
+
+	assert.Equal(t, "vscode:golang.go", NewPackage(TypeVSCode, "ms-vscode.go").Key(aliases))
+	assert.Equal(t, "antigravity:golang.go", NewPackage(TypeAntigravity, "MS-VSCODE.GO").Key(aliases))
+	assert.Equal(t, "vscode:golang.go", NewPackage(TypeVSCode, "golang.go").Key(aliases))
+	assert.Equal(t, "brew:ms-vscode.go", NewPackage(TypeBrew, "ms-vscode.go").Key(aliases))
+	assert.Equal(t, "vscode:ms-vscode.go", NewPackage(TypeVSCode, "ms-vscode.go").Key(nil))
+
+	tap := NewPackage(TypeTap, "company/private")
+	assert.Equal(t, "tap:company/private", tap.Key(nil))
+	tap.URL = "git@github.com:company/homebrew-private.git"
+	assert.Equal(t, "tap:company/private git@github.com:company/homebrew-private.git", tap.Key(nil))
+}
+
+func TestPackages_KeepTypes(t *testing.T) {
+	collected := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeVSCode, "golang.go"),
+	}
+	existing := Packages{
+		NewPackage(TypeBrew, "wget"),
+		NewPackage(TypeMas, "Xcode"),
+		NewPackage(TypeVSCode, "old.extension"),
+	}
+
+	assert.Equal(t, collected, collected.KeepTypes(existing))
+	assert.Equal(t, []string{"brew:git", "vscode:golang.go", "mas:Xcode"}, collected.KeepTypes(existing, TypeMas).IDs())
+	assert.Equal(t, []string{"brew:git", "vscode:old.extension"}, collected.KeepTypes(existing, TypeVSCode).IDs())
+}
+
+func TestPackages_Exclude(t *testing.T) {
+	// Upgrade candidates minus pinned packages
+	outdated := Packages{
+		NewPackage(TypeBrew, "git"),
+		NewPackage(TypeBrew, "node@18"),
+		NewPackage(TypeCask, "node@18"),
+	}
+
+	result := outdated.Exclude(map[string]bool{"brew:node@18": true})
+	assert.Len(t, result, 2)
+	assert.True(t, result.Contains("brew:git"))
+	assert.True(t, result.Contains("cask:node@18"))
+	assert.False(t, result.Contains("brew:node@18"))
+
+	assert.Len(t, outdated.Exclude(nil), 3)
+}
+
 func TestPackages_Names(t *testing.T) {
 	packages := Packages{
 		NewPackage(TypeBrew, "git"),
@@ -240,3 +330,103 @@ func TestPackages_MergeUnique(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCategories(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    []string
+		expected []PackageType
+	}{
+		{"single type", []string{"brew"}, []PackageType{TypeBrew}},
+		{"comma-separated", []string{"brew,cask"}, []PackageType{TypeBrew, TypeCask}},
+		{"slice values", []string{"brew", " cask "}, []PackageType{TypeBrew, TypeCask}},
+		{"editors alias", []string{"editors"}, []PackageType{TypeVSCode, TypeCursor, TypeAntigravity}},
+		{"cli alias", []string{"cli"}, []PackageType{TypeBrew, TypeTap, TypeGo}},
+		{"apps alias", []string{"apps"}, []PackageType{TypeCask, TypeMas}},
+		{"at-prefixed alias", []string{"@apps"}, []PackageType{TypeCask, TypeMas}},
+		{"alias mixed with types", []string{"editors,mas"}, []PackageType{TypeVSCode, TypeCursor, TypeAntigravity, TypeMas}},
+		{"duplicates removed", []string{"brew,cli"}, []PackageType{TypeBrew, TypeTap, TypeGo}},
+		{"case insensitive", []string{"Editors,BREW"}, []PackageType{TypeVSCode, TypeCursor, TypeAntigravity, TypeBrew}},
+		{"agy shorthand", []string{"agy"}, []PackageType{TypeAntigravity}},
+		{"empty entries skipped", []string{"brew,,"}, []PackageType{TypeBrew}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseCategories(tc.input...)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestParseCategories_Unknown(t *testing.T) {
+	_, err := ParseCategories("brew,homebrew")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "homebrew")
+	assert.Contains(t, err.Error(), "editors")
+}
+
+func TestTapQualifiedNames(t *testing.T) {
+	content := `tap "user/tap"
+brew "user/tap/formula"
+brew "formula"
+cask "user/tap/app"
+`
+	pkgs, err := ParseContent(content)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 4)
+
+	// Parse keeps the qualified name and records the tap
+	assert.Equal(t, "", pkgs[0].Tap)
+	assert.Equal(t, "user/tap/formula", pkgs[1].Name)
+	assert.Equal(t, "user/tap", pkgs[1].Tap)
+	assert.Equal(t, "", pkgs[2].Tap)
+	assert.Equal(t, "user/tap", pkgs[3].Tap)
+
+	// Write emits the qualified name unchanged
+	out := NewWriter(pkgs).Format()
+	assert.Contains(t, out, `brew "user/tap/formula"`)
+	assert.Contains(t, out, `brew "formula"`)
+	assert.Contains(t, out, `cask "user/tap/app"`)
+
+	// Diff keeps formula and user/tap/formula apart
+	diff := Diff(Packages{pkgs[1]}, Packages{pkgs[2]})
+	require.Len(t, diff.Additions, 1)
+	require.Len(t, diff.Removals, 1)
+	assert.Equal(t, "brew:user/tap/formula", diff.Additions[0].ID())
+	assert.Equal(t, "brew:formula", diff.Removals[0].ID())
+}
+
+func TestPackage_QualifiedName(t *testing.T) {
+	assert.Equal(t, "user/tap/formula", NewPackage(TypeBrew, "user/tap/formula").QualifiedName())
+	assert.Equal(t, "jq", NewPackage(TypeBrew, "jq").QualifiedName())
+	assert.Equal(t, "user/tap/formula", Package{Type: TypeBrew, Name: "formula", Tap: "user/tap"}.QualifiedName())
+
+	// Only brew and cask names carry taps
+	assert.Equal(t, "", NewPackage(TypeGo, "github.com/x/y").Tap)
+	assert.Equal(t, "", NewPackage(TypeTap, "user/tap").Tap)
+}
+
+func TestPackages_InstalledSince(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(t PackageType, name string, age time.Duration) Package {
+		pkg := NewPackage(t, name)
+		pkg.InstalledAt = now.Add(-age)
+		return pkg
+	}
+	pkgs := Packages{
+		at(TypeBrew, "old", 30*24*time.Hour),
+		at(TypeBrew, "new", 2*24*time.Hour),
+		at(TypeCask, "fresh", time.Hour),
+		NewPackage(TypeTap, "user/tap"),
+		NewPackage(TypeVSCode, "golang.go"),
+	}
+
+	recent, undated := pkgs.InstalledSince(now.AddDate(0, 0, -7))
+	assert.Equal(t, []string{"brew:new", "cask:fresh"}, recent.IDs())
+	assert.Equal(t, []string{"tap:user/tap", "vscode:golang.go"}, undated.IDs())
+
+	recent, _ = pkgs.InstalledSince(now)
+	assert.Empty(t, recent)
+}
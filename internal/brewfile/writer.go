@@ -5,11 +5,20 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 )
 
+// headerMarker identifies the comment block brewsync writes at the top of a Brewfile
+const headerMarker = "# Generated by brewsync"
+
 // Writer writes packages to Brewfile format
 type Writer struct {
 	packages Packages
+	header   []string
+	preamble []string
+	includes []string
+	preserve bool
+	layout   *Layout
 }
 
 // NewWriter creates a new Brewfile writer
@@ -17,8 +26,102 @@ func NewWriter(packages Packages) *Writer {
 	return &Writer{packages: packages}
 }
 
-// Write writes the Brewfile to the given path
+// WithPreamble sets global directive lines (e.g. cask_args) written before any package
+func (w *Writer) WithPreamble(lines []string) *Writer {
+	w.preamble = lines
+	return w
+}
+
+// WithIncludes sets the include directive lines (see IncludeDirective) written
+// before the packages
+func (w *Writer) WithIncludes(lines []string) *Writer {
+	w.includes = lines
+	return w
+}
+
+// PreserveComments makes Write keep the hand-written comments and blank lines
+// of the existing Brewfile, re-attached to the packages they precede, and its
+// package order within each type (see Layout)
+func (w *Writer) PreserveComments(enabled bool) *Writer {
+	w.preserve = enabled
+	return w
+}
+
+// WithHeader sets a comment block written at the very top of the Brewfile (see Header)
+func (w *Writer) WithHeader(lines []string) *Writer {
+	w.header = lines
+	return w
+}
+
+// Header returns the comment block identifying a Brewfile dumped for machine at t
+func Header(machine string, t time.Time) []string {
+	return []string{
+		fmt.Sprintf("# Brewfile for %s", machine),
+		headerMarker + "; 'brewsync dump' rewrites this file",
+		"# Last dumped: " + t.Format(time.RFC3339),
+	}
+}
+
+// Write writes the Brewfile to the given path. Unless a preamble was set, the
+// global directives of the existing file are kept so dumps don't drop them.
+//
+// An existing brewsync header is replaced by the one set with WithHeader, or
+// kept if none was set. A header is only added to a new Brewfile, so files that
+// predate it (or had it removed by hand) stay as they are.
+//
+// Include directives are kept the same way, and packages the included files
+// already list are left out: a machine's Brewfile only holds its own entries.
+//
+// With PreserveComments, the existing file's layout is read before it is
+// overwritten.
 func (w *Writer) Write(path string) error {
+	existing, err := ReadHeader(path)
+	if err != nil {
+		return err
+	}
+	if w.header == nil {
+		w.header = existing
+	} else if existing == nil {
+		if _, err := os.Stat(path); err == nil {
+			w.header = nil
+		}
+	}
+
+	if w.preamble == nil {
+		preamble, err := ReadPreamble(path)
+		if err != nil {
+			return err
+		}
+		w.preamble = preamble
+	}
+
+	if w.includes == nil {
+		includes, err := ReadIncludes(path)
+		if err != nil {
+			return err
+		}
+		w.includes = includes
+	}
+	if len(w.includes) > 0 {
+		included, err := IncludedPackages(path, w.includes)
+		if err != nil {
+			return err
+		}
+		excluded := make(map[string]bool, len(included))
+		for _, pkg := range included {
+			excluded[pkg.ID()] = true
+		}
+		w.packages = w.packages.Exclude(excluded)
+	}
+
+	if w.preserve {
+		layout, err := ReadLayout(path)
+		if err != nil {
+			return err
+		}
+		w.layout = layout
+	}
+
 	content := w.Format()
 	return os.WriteFile(path, []byte(content), 0644)
 }
@@ -27,11 +130,32 @@ func (w *Writer) Write(path string) error {
 func (w *Writer) Format() string {
 	var sb strings.Builder
 
+	for _, line := range w.header {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if len(w.header) > 0 && (len(w.preamble) > 0 || len(w.includes) > 0) {
+		sb.WriteString("\n")
+	}
+
+	for _, line := range w.preamble {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if len(w.preamble) > 0 && len(w.includes) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, line := range w.includes {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
 	// Group packages by type
 	byType := w.packages.ByType()
 
 	// Write in specific order
-	typeOrder := []PackageType{TypeTap, TypeBrew, TypeCask, TypeMas, TypeVSCode, TypeCursor, TypeAntigravity, TypeGo}
+	typeOrder := []PackageType{TypeTap, TypeBrew, TypeCask, TypeMas, TypeVSCode, TypeCursor, TypeAntigravity, TypeGo, TypeNpm, TypePipx}
 
 	for _, t := range typeOrder {
 		pkgs, ok := byType[t]
@@ -39,19 +163,30 @@ func (w *Writer) Format() string {
 			continue
 		}
 
-		// Sort packages by name
+		// Sort packages by name, or keep the preserved layout's order
 		sort.Slice(pkgs, func(i, j int) bool {
-			return pkgs[i].Name < pkgs[j].Name
+			return w.layout.less(pkgs[i], pkgs[j])
 		})
 
 		// Add section comment for non-standard types
-		if t == TypeCursor || t == TypeAntigravity || t == TypeGo {
+		if t == TypeCursor || t == TypeAntigravity || t == TypeGo || t == TypeNpm || t == TypePipx {
 			sb.WriteString(fmt.Sprintf("\n# %s (brewsync extension)\n", t))
 		} else if sb.Len() > 0 {
 			sb.WriteString("\n")
 		}
 
-		for _, p := range pkgs {
+		for i, p := range pkgs {
+			// Re-attach preserved comments; the first package of a type
+			// already follows a blank line
+			if entry, ok := w.layout.entry(p); ok {
+				if entry.blank && i > 0 {
+					sb.WriteString("\n")
+				}
+				for _, line := range entry.commentsFor(p) {
+					sb.WriteString(line)
+					sb.WriteString("\n")
+				}
+			}
 			// Add description as a comment if available
 			if p.Description != "" {
 				sb.WriteString(fmt.Sprintf("# %s\n", p.Description))
@@ -68,7 +203,14 @@ func (w *Writer) Format() string {
 func formatPackage(p Package) string {
 	switch p.Type {
 	case TypeTap:
-		return fmt.Sprintf(`tap "%s"`, p.Name)
+		line := fmt.Sprintf(`tap "%s"`, p.Name)
+		if p.URL != "" {
+			line += fmt.Sprintf(`, "%s"`, p.URL)
+		}
+		if len(p.Options) > 0 {
+			line += ", " + formatOptions(p.Options)
+		}
+		return line
 
 	case TypeBrew:
 		if len(p.Options) > 0 {
@@ -89,22 +231,28 @@ func formatPackage(p Package) string {
 			if name == "" {
 				name = p.Name
 			}
-			return fmt.Sprintf(`mas "%s", id: %s`, name, id)
+			return fmt.Sprintf(`mas "%s", id: %s`, quote(name), id)
 		}
-		return fmt.Sprintf(`mas "%s"`, p.Name)
+		return fmt.Sprintf(`mas "%s"`, quote(p.Name))
 
 	case TypeVSCode:
-		return fmt.Sprintf(`vscode "%s"`, p.Name)
+		return fmt.Sprintf(`vscode "%s"`, p.VersionedName())
 
 	case TypeCursor:
-		return fmt.Sprintf(`cursor "%s"`, p.Name)
+		return fmt.Sprintf(`cursor "%s"`, p.VersionedName())
 
 	case TypeAntigravity:
-		return fmt.Sprintf(`antigravity "%s"`, p.Name)
+		return fmt.Sprintf(`antigravity "%s"`, p.VersionedName())
 
 	case TypeGo:
 		return fmt.Sprintf(`go "%s"`, p.Name)
 
+	case TypeNpm:
+		return fmt.Sprintf(`npm "%s"`, p.Name)
+
+	case TypePipx:
+		return fmt.Sprintf(`pipx "%s"`, p.Name)
+
 	default:
 		return fmt.Sprintf(`# unknown type: %s "%s"`, p.Type, p.Name)
 	}
@@ -120,6 +268,9 @@ func formatOptions(opts map[string]string) string {
 		} else if strings.HasPrefix(v, ":") {
 			// Symbol value
 			parts = append(parts, fmt.Sprintf("%s: %s", k, v))
+		} else if strings.HasPrefix(v, "[") || strings.HasPrefix(v, "{") {
+			// Array or hash value, written as read
+			parts = append(parts, fmt.Sprintf("%s: %s", k, v))
 		} else if _, err := fmt.Sscanf(v, "%d", new(int)); err == nil {
 			// Numeric value
 			parts = append(parts, fmt.Sprintf("%s: %s", k, v))
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -93,6 +94,28 @@ func TestWriter_Format(t *testing.T) {
 		assert.Contains(t, content, `go "golang.org/x/tools/gopls"`)
 	})
 
+	t.Run("npm package with comment", func(t *testing.T) {
+		writer := NewWriter(Packages{
+			NewPackage(TypeNpm, "prettier"),
+			NewPackage(TypeGo, "golang.org/x/tools/gopls"),
+		})
+		content := writer.Format()
+		assert.Contains(t, content, "# npm (brewsync extension)")
+		assert.Contains(t, content, `npm "prettier"`)
+		assert.Less(t, strings.Index(content, "go "), strings.Index(content, "npm "))
+	})
+
+	t.Run("pipx package with comment", func(t *testing.T) {
+		writer := NewWriter(Packages{
+			NewPackage(TypePipx, "black"),
+			NewPackage(TypeNpm, "prettier"),
+		})
+		content := writer.Format()
+		assert.Contains(t, content, "# pipx (brewsync extension)")
+		assert.Contains(t, content, `pipx "black"`)
+		assert.Less(t, strings.Index(content, "npm "), strings.Index(content, "pipx "))
+	})
+
 	t.Run("sorted by name within type", func(t *testing.T) {
 		writer := NewWriter(Packages{
 			NewPackage(TypeBrew, "zsh"),
@@ -154,6 +177,24 @@ func TestWriter_Write(t *testing.T) {
 	assert.Contains(t, string(content), `cask "raycast"`)
 }
 
+func TestWriter_FormatExtensionVersions(t *testing.T) {
+	content := "vscode \"golang.go@0.42.0\"\nvscode \"ms-python.python\"\n"
+	packages, err := NewParser().ParseString(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, content, NewWriter(packages).Format())
+	assert.Equal(t, "vscode \"golang.go\"\nvscode \"ms-python.python\"\n",
+		NewWriter(packages.WithoutVersions()).Format())
+}
+
+func TestWriter_FormatTapURL(t *testing.T) {
+	content := "tap \"company/private\", \"git@github.com:company/homebrew-private.git\"\ntap \"homebrew/bundle\"\n"
+	packages, err := NewParser().ParseString(content)
+	require.NoError(t, err)
+
+	assert.Equal(t, content, NewWriter(packages).Format())
+}
+
 func TestWriter_WriteCreatesDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 	nestedPath := filepath.Join(tmpDir, "subdir", "Brewfile")
@@ -312,3 +353,166 @@ func TestWriter_FormatComplexBrewfile(t *testing.T) {
 	assert.Contains(t, content, "# go (brewsync extension)")
 	assert.Contains(t, content, `go "golang.org/x/tools/gopls"`)
 }
+
+func TestWriter_WriteKeepsPreamble(t *testing.T) {
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte(`brew "wget"
+cask_args appdir: "~/Applications"
+cask "firefox"
+`), 0644))
+
+	// A dump rewrites the file from installed packages only
+	require.NoError(t, NewWriter(Packages{NewPackage(TypeCask, "raycast")}).Write(brewfilePath))
+
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "cask_args appdir: \"~/Applications\"\n\ncask \"raycast\"\n", string(data))
+
+	pkgs, err := Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"raycast"}, pkgs.Names())
+
+	// An explicit preamble replaces the file's
+	require.NoError(t, NewWriter(Packages{NewPackage(TypeBrew, "git")}).WithPreamble([]string{}).Write(brewfilePath))
+	data, err = os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "brew \"git\"\n", string(data))
+}
+
+func TestWriter_WriteHeader(t *testing.T) {
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	// The first dump creates the file with the header
+	require.NoError(t, NewWriter(Packages{NewPackage(TypeBrew, "git")}).WithHeader(Header("mini", first)).Write(brewfilePath))
+
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Brewfile for mini\n"+
+		"# Generated by brewsync; 'brewsync dump' rewrites this file\n"+
+		"# Last dumped: 2026-01-02T03:04:05Z\n"+
+		"\nbrew \"git\"\n", string(data))
+
+	header, err := ReadHeader(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, Header("mini", first), header)
+
+	// A re-dump updates the header instead of adding a second one
+	require.NoError(t, NewWriter(Packages{NewPackage(TypeBrew, "git")}).WithHeader(Header("mini", second)).Write(brewfilePath))
+
+	data, err = os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "# Generated by brewsync"))
+	assert.Contains(t, string(data), "# Last dumped: 2026-01-03T03:04:05Z\n")
+
+	// Writing without a header keeps the existing one
+	require.NoError(t, NewWriter(Packages{NewPackage(TypeBrew, "wget")}).Write(brewfilePath))
+	header, err = ReadHeader(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, Header("mini", second), header)
+}
+
+func TestWriter_WriteHeaderSkipsExistingBrewfile(t *testing.T) {
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("# Distributed revision control system\nbrew \"git\"\n"), 0644))
+
+	require.NoError(t, NewWriter(Packages{NewPackage(TypeBrew, "git")}).WithHeader(Header("mini", time.Now())).Write(brewfilePath))
+
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "brew \"git\"\n", string(data))
+}
+
+func TestWriter_FormatHeaderAndPreamble(t *testing.T) {
+	content := NewWriter(Packages{NewPackage(TypeCask, "firefox")}).
+		WithHeader([]string{"# Brewfile for mini"}).
+		WithPreamble([]string{`cask_args appdir: "~/Applications"`}).
+		Format()
+
+	assert.Equal(t, "# Brewfile for mini\n\ncask_args appdir: \"~/Applications\"\n\ncask \"firefox\"\n", content)
+}
+
+func TestWriter_WritePreserveComments(t *testing.T) {
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	existing := `# Brewfile for mini
+# Generated by brewsync; 'brewsync dump' rewrites this file
+# Last dumped: 2026-01-02T03:04:05Z
+
+cask_args appdir: "~/Applications"
+
+tap "homebrew/bundle"
+
+# dev tools
+# Distributed revision control system
+brew "git"
+# Lightweight JSON processor
+brew "jq"
+
+# media
+
+brew "ffmpeg"
+brew "old"
+
+# go (brewsync extension)
+go "golang.org/x/tools/gopls"
+`
+	require.NoError(t, os.WriteFile(brewfilePath, []byte(existing), 0644))
+
+	pkg := func(t PackageType, name, desc string) Package {
+		p := NewPackage(t, name)
+		p.Description = desc
+		return p
+	}
+	packages := Packages{
+		pkg(TypeBrew, "wget", "Internet file retriever"),
+		pkg(TypeBrew, "jq", "Lightweight JSON processor"),
+		pkg(TypeBrew, "git", "Distributed revision control system"),
+		pkg(TypeBrew, "ffmpeg", "Play, record, convert, and stream audio and video"),
+		pkg(TypeTap, "homebrew/bundle", ""),
+		pkg(TypeGo, "golang.org/x/tools/gopls", ""),
+	}
+
+	require.NoError(t, NewWriter(packages).PreserveComments(true).Write(brewfilePath))
+
+	want := `# Brewfile for mini
+# Generated by brewsync; 'brewsync dump' rewrites this file
+# Last dumped: 2026-01-02T03:04:05Z
+
+cask_args appdir: "~/Applications"
+
+tap "homebrew/bundle"
+
+# dev tools
+# Distributed revision control system
+brew "git"
+# Lightweight JSON processor
+brew "jq"
+
+# media
+
+# Play, record, convert, and stream audio and video
+brew "ffmpeg"
+# Internet file retriever
+brew "wget"
+
+# go (brewsync extension)
+go "golang.org/x/tools/gopls"
+`
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+
+	// Writing again leaves the file as it is
+	require.NoError(t, NewWriter(packages).PreserveComments(true).Write(brewfilePath))
+	data, err = os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+
+	// Without it, hand-written comments are dropped and packages sorted
+	require.NoError(t, NewWriter(packages).Write(brewfilePath))
+	data, err = os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "# dev tools")
+	assert.Contains(t, string(data), "brew \"ffmpeg\"\n# Distributed revision control system\nbrew \"git\"\n")
+}
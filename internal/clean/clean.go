@@ -0,0 +1,284 @@
+package clean
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default retention settings
+const (
+	DefaultKeepBackups = 3
+	DefaultCacheTTL    = 7 * 24 * time.Hour
+	DefaultLogMaxAge   = 30 * 24 * time.Hour
+	DefaultTempMinAge  = time.Hour // Don't remove temp files a running dump may still use
+)
+
+// Subdirectories of the config dir that brewsync manages
+const (
+	CacheDirName = "cache"
+	LogsDirName  = "logs"
+)
+
+// Options controls which files are considered stale
+type Options struct {
+	ConfigDir    string        // brewsync config dir (~/.config/brewsync)
+	BrewfileDirs []string      // Directories containing Brewfiles (for orphaned temp files)
+	Protected    []string      // Paths that must never be removed (config, ignore file, Brewfiles, ...)
+	KeepBackups  int           // Number of most recent backups to keep per file
+	CacheTTL     time.Duration // Cache entries older than this are expired
+	LogMaxAge    time.Duration // Session logs older than this are removed
+	TempMinAge   time.Duration // Temp files younger than this are left alone
+	Now          time.Time     // Reference time (zero means time.Now())
+}
+
+// DefaultOptions returns options with default retention for the given config dir
+func DefaultOptions(configDir string) Options {
+	return Options{
+		ConfigDir:   configDir,
+		KeepBackups: DefaultKeepBackups,
+		CacheTTL:    DefaultCacheTTL,
+		LogMaxAge:   DefaultLogMaxAge,
+		TempMinAge:  DefaultTempMinAge,
+	}
+}
+
+// Candidate is a file selected for removal
+type Candidate struct {
+	Path   string
+	Size   int64
+	Reason string
+}
+
+// Scan finds stale brewsync-managed files without removing anything
+func Scan(opts Options) ([]Candidate, error) {
+	if opts.ConfigDir == "" {
+		return nil, fmt.Errorf("config dir not set")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	protected := make(map[string]bool)
+	for _, p := range opts.Protected {
+		protected[filepath.Clean(p)] = true
+	}
+
+	var candidates []Candidate
+	seen := make(map[string]bool)
+	add := func(path string, info os.FileInfo, reason string) {
+		path = filepath.Clean(path)
+		if seen[path] || protected[path] || isBrewsyncFile(path) {
+			return
+		}
+		seen[path] = true
+		candidates = append(candidates, Candidate{Path: path, Size: info.Size(), Reason: reason})
+	}
+
+	// Old backups beyond keep count
+	backups, err := scanBackups(opts.ConfigDir, opts.KeepBackups)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range backups {
+		add(b.path, b.info, "old backup")
+	}
+
+	// Expired caches
+	err = walkFiles(filepath.Join(opts.ConfigDir, CacheDirName), func(path string, info os.FileInfo) {
+		if opts.Now.Sub(info.ModTime()) > opts.CacheTTL {
+			add(path, info, "expired cache")
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Old session logs
+	err = walkFiles(filepath.Join(opts.ConfigDir, LogsDirName), func(path string, info os.FileInfo) {
+		if opts.Now.Sub(info.ModTime()) > opts.LogMaxAge {
+			add(path, info, "old session log")
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Orphaned temp files in the config dir and next to Brewfiles
+	tempDirs := append([]string{opts.ConfigDir}, opts.BrewfileDirs...)
+	for _, dir := range tempDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !isTempFile(entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if opts.Now.Sub(info.ModTime()) > opts.TempMinAge {
+				add(filepath.Join(dir, entry.Name()), info, "orphaned temp file")
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Path < candidates[j].Path
+	})
+
+	return candidates, nil
+}
+
+// Remove deletes the given candidates and returns the number of bytes reclaimed
+func Remove(candidates []Candidate) (int64, error) {
+	var reclaimed int64
+	var errs []string
+	for _, c := range candidates {
+		if err := os.Remove(c.Path); err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+		reclaimed += c.Size
+	}
+
+	if len(errs) > 0 {
+		return reclaimed, fmt.Errorf("failed to remove %d file(s): %s", len(errs), strings.Join(errs, "; "))
+	}
+	return reclaimed, nil
+}
+
+// TotalSize returns the combined size of the candidates
+func TotalSize(candidates []Candidate) int64 {
+	var total int64
+	for _, c := range candidates {
+		total += c.Size
+	}
+	return total
+}
+
+// FormatSize formats a byte count for display
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+type backupFile struct {
+	path string
+	info os.FileInfo
+}
+
+// scanBackups returns backups in dir beyond the keep count, oldest first.
+// Backups are grouped by the file they back up (the name before ".bak").
+func scanBackups(dir string, keep int) ([]backupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	groups := make(map[string][]backupFile)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		base, ok := backupBase(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		groups[base] = append(groups[base], backupFile{path: filepath.Join(dir, entry.Name()), info: info})
+	}
+
+	var stale []backupFile
+	for _, files := range groups {
+		// Newest first
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].info.ModTime().After(files[j].info.ModTime())
+		})
+		if len(files) > keep {
+			stale = append(stale, files[keep:]...)
+		}
+	}
+
+	return stale, nil
+}
+
+// backupBase returns the name of the file a backup belongs to
+// (e.g. "config.yaml.bak.20240101" -> "config.yaml")
+func backupBase(name string) (string, bool) {
+	idx := strings.Index(name, ".bak")
+	if idx <= 0 {
+		return "", false
+	}
+	rest := name[idx+len(".bak"):]
+	if rest != "" && rest[0] != '.' && rest[0] != '-' {
+		return "", false
+	}
+	return name[:idx], true
+}
+
+// bundleTempSuffix ends the temp file a dump has 'brew bundle dump' write next
+// to the Brewfile, the only temp file brewsync leaves behind when interrupted
+const bundleTempSuffix = ".brewbundle.tmp"
+
+// isTempFile returns true for temp files brewsync creates. Other temp files,
+// such as the user's own in a dotfiles repository, are left alone.
+func isTempFile(name string) bool {
+	return strings.HasSuffix(name, bundleTempSuffix)
+}
+
+// isBrewsyncFile returns true for files that are never stale regardless of rules
+func isBrewsyncFile(path string) bool {
+	name := filepath.Base(path)
+	if isTempFile(name) {
+		return false
+	}
+	if _, ok := backupBase(name); ok {
+		return false
+	}
+	switch name {
+	case "config.yaml", "ignore.yaml", "history.log", ".brewsync-meta":
+		return true
+	}
+	return strings.HasPrefix(name, "Brewfile")
+}
+
+// walkFiles calls fn for every regular file under dir (missing dir is not an error)
+func walkFiles(dir string, fn func(path string, info os.FileInfo)) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			fn(path, info)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,189 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func writeFile(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+	mtime := testNow.Add(-age)
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
+
+// setupFakeConfigDir populates a config dir and a dotfiles dir with a mix of live and stale files
+func setupFakeConfigDir(t *testing.T) (configDir, brewDir string) {
+	t.Helper()
+	root := t.TempDir()
+	configDir = filepath.Join(root, "config")
+	brewDir = filepath.Join(root, "dotfiles", "_brew_mini")
+
+	day := 24 * time.Hour
+
+	// Live files that must never be touched
+	writeFile(t, filepath.Join(configDir, "config.yaml"), 10, 90*day)
+	writeFile(t, filepath.Join(configDir, "ignore.yaml"), 10, 90*day)
+	writeFile(t, filepath.Join(configDir, "history.log"), 10, 90*day)
+	writeFile(t, filepath.Join(configDir, "profiles", "core.yaml"), 10, 90*day)
+	writeFile(t, filepath.Join(brewDir, "Brewfile"), 10, 90*day)
+	writeFile(t, filepath.Join(brewDir, ".brewsync-meta"), 10, 90*day)
+
+	// Backups: 5 of config.yaml (keep newest 3), 1 of ignore.yaml
+	for i := 1; i <= 5; i++ {
+		writeFile(t, filepath.Join(configDir, "config.yaml.bak."+string(rune('0'+i))), 100, time.Duration(i)*day)
+	}
+	writeFile(t, filepath.Join(configDir, "ignore.yaml.bak"), 100, 50*day)
+
+	// Caches: one fresh, one expired
+	writeFile(t, filepath.Join(configDir, "cache", "mas.json"), 200, 1*day)
+	writeFile(t, filepath.Join(configDir, "cache", "descriptions.json"), 300, 10*day)
+
+	// Session logs: one recent, one old
+	writeFile(t, filepath.Join(configDir, "logs", "session-new.log"), 400, 2*day)
+	writeFile(t, filepath.Join(configDir, "logs", "session-old.log"), 500, 45*day)
+
+	// Temp files: orphaned and in-progress
+	writeFile(t, filepath.Join(brewDir, "Brewfile.brewbundle.tmp"), 600, 3*time.Hour)
+	writeFile(t, filepath.Join(configDir, "config.yaml.tmp"), 700, 5*time.Minute)
+
+	return configDir, brewDir
+}
+
+func TestScan(t *testing.T) {
+	configDir, brewDir := setupFakeConfigDir(t)
+	// The user's own temp file next to the Brewfile isn't brewsync's
+	writeFile(t, filepath.Join(brewDir, "notes.tmp"), 800, 3*time.Hour)
+
+	opts := DefaultOptions(configDir)
+	opts.BrewfileDirs = []string{brewDir}
+	opts.Now = testNow
+
+	candidates, err := Scan(opts)
+	require.NoError(t, err)
+
+	var paths []string
+	reasons := make(map[string]string)
+	for _, c := range candidates {
+		rel, _ := filepath.Rel(filepath.Dir(configDir), c.Path)
+		paths = append(paths, rel)
+		reasons[rel] = c.Reason
+	}
+
+	expected := []string{
+		filepath.Join("config", "cache", "descriptions.json"),
+		filepath.Join("config", "config.yaml.bak.4"),
+		filepath.Join("config", "config.yaml.bak.5"),
+		filepath.Join("config", "logs", "session-old.log"),
+		filepath.Join("dotfiles", "_brew_mini", "Brewfile.brewbundle.tmp"),
+	}
+	assert.ElementsMatch(t, expected, paths)
+	assert.Equal(t, "old backup", reasons[filepath.Join("config", "config.yaml.bak.4")])
+	assert.Equal(t, "expired cache", reasons[filepath.Join("config", "cache", "descriptions.json")])
+	assert.Equal(t, "old session log", reasons[filepath.Join("config", "logs", "session-old.log")])
+	assert.Equal(t, "orphaned temp file", reasons[filepath.Join("dotfiles", "_brew_mini", "Brewfile.brewbundle.tmp")])
+	assert.Equal(t, int64(300+100+100+500+600), TotalSize(candidates))
+}
+
+func TestScan_Protected(t *testing.T) {
+	configDir, brewDir := setupFakeConfigDir(t)
+	protectedPath := filepath.Join(configDir, "logs", "session-old.log")
+
+	opts := DefaultOptions(configDir)
+	opts.BrewfileDirs = []string{brewDir}
+	opts.Protected = []string{protectedPath}
+	opts.Now = testNow
+
+	candidates, err := Scan(opts)
+	require.NoError(t, err)
+	for _, c := range candidates {
+		assert.NotEqual(t, protectedPath, c.Path)
+	}
+}
+
+func TestScan_MissingConfigDir(t *testing.T) {
+	opts := DefaultOptions(filepath.Join(t.TempDir(), "missing"))
+	candidates, err := Scan(opts)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
+func TestRemove(t *testing.T) {
+	configDir, brewDir := setupFakeConfigDir(t)
+
+	opts := DefaultOptions(configDir)
+	opts.BrewfileDirs = []string{brewDir}
+	opts.Now = testNow
+
+	candidates, err := Scan(opts)
+	require.NoError(t, err)
+
+	reclaimed, err := Remove(candidates)
+	require.NoError(t, err)
+	assert.Equal(t, TotalSize(candidates), reclaimed)
+
+	for _, c := range candidates {
+		_, err := os.Stat(c.Path)
+		assert.True(t, os.IsNotExist(err), "%s should be removed", c.Path)
+	}
+
+	// Live files and recent state untouched
+	for _, path := range []string{
+		filepath.Join(configDir, "config.yaml"),
+		filepath.Join(configDir, "ignore.yaml"),
+		filepath.Join(configDir, "history.log"),
+		filepath.Join(configDir, "profiles", "core.yaml"),
+		filepath.Join(configDir, "config.yaml.bak.1"),
+		filepath.Join(configDir, "ignore.yaml.bak"),
+		filepath.Join(configDir, "cache", "mas.json"),
+		filepath.Join(configDir, "logs", "session-new.log"),
+		filepath.Join(configDir, "config.yaml.tmp"),
+		filepath.Join(brewDir, "Brewfile"),
+		filepath.Join(brewDir, ".brewsync-meta"),
+	} {
+		_, err := os.Stat(path)
+		assert.NoError(t, err, "%s should be kept", path)
+	}
+
+	// Second scan finds nothing
+	candidates, err = Scan(opts)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
+func TestBackupBase(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		ok   bool
+	}{
+		{"config.yaml.bak", "config.yaml", true},
+		{"config.yaml.bak.20240101", "config.yaml", true},
+		{"Brewfile.bak-1", "Brewfile", true},
+		{"config.yaml", "", false},
+		{".bak", "", false},
+		{"notes.baking", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, ok := backupBase(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.base, base)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KB", FormatSize(1536))
+	assert.Equal(t, "2.0 MB", FormatSize(2*1024*1024))
+}
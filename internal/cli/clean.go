@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/clean"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+var (
+	cleanKeepBackups int
+	cleanOrphans     bool
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove stale brewsync state files",
+	Long: `Remove stale files brewsync leaves behind in its config directory
+and next to Brewfiles:
+
+  - backups beyond the keep count (newest are kept)
+  - expired caches (older than 7 days)
+  - old session logs (older than 30 days)
+  - orphaned temp files from interrupted dumps
+
+Brewfiles, metadata, config.yaml, ignore.yaml, history and profiles are
+never touched.
+
+With --orphans, clean instead offers to uninstall packages installed on this
+machine that its Brewfile doesn't list, such as tools installed ad hoc that
+were never dumped. Ignored, pinned and machine-specific packages are kept, as
+sync keeps them, and formulae installed only as dependencies aren't offered.
+Packages ignored in the selection are added to this machine's ignore list.
+
+Examples:
+  brewsync clean --dry-run         # Show what would be removed
+  brewsync clean                   # Remove stale files
+  brewsync clean --keep-backups 1  # Keep only the newest backup
+  brewsync clean --orphans --dry-run  # List packages missing from the Brewfile
+  brewsync clean --orphans         # Pick orphaned packages to uninstall`,
+	RunE: runClean,
+}
+
+func init() {
+	cleanCmd.Flags().IntVar(&cleanKeepBackups, "keep-backups", clean.DefaultKeepBackups, "number of backups to keep per file")
+	cleanCmd.Flags().BoolVar(&cleanOrphans, "orphans", false, "uninstall packages installed here but not in this machine's Brewfile")
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	if cleanOrphans {
+		return runCleanOrphans()
+	}
+
+	opts := clean.DefaultOptions(config.StateDir())
+	opts.KeepBackups = cleanKeepBackups
+	opts.Protected = append(opts.Protected, config.IgnorePath())
+	if path, err := config.ConfigPath(); err == nil {
+		opts.Protected = append(opts.Protected, path)
+	}
+	if path, err := config.HistoryPath(); err == nil {
+		opts.Protected = append(opts.Protected, path)
+	}
+
+	// Brewfile directories may hold orphaned temp files (config is optional here)
+	if config.Exists() {
+		if cfg, err := config.Load(); err == nil {
+			for _, m := range cfg.Machines {
+				if m.Brewfile == "" {
+					continue
+				}
+				opts.BrewfileDirs = append(opts.BrewfileDirs, filepath.Dir(m.Brewfile))
+				opts.Protected = append(opts.Protected, m.Brewfile)
+			}
+		}
+	}
+
+	candidates, err := clean.Scan(opts)
+	if err != nil {
+		return fmt.Errorf("failed to scan for stale files: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		printInfo("Nothing to clean")
+		return nil
+	}
+
+	for _, c := range candidates {
+		printInfo("  %s %s", styleDim.Render(fmt.Sprintf("%-20s", c.Reason)), c.Path)
+	}
+
+	total := clean.TotalSize(candidates)
+	if dryRun {
+		printInfo("\n[dry-run] Would remove %d file(s), reclaiming %s", len(candidates), clean.FormatSize(total))
+		return nil
+	}
+
+	reclaimed, err := clean.Remove(candidates)
+	printInfo("\n%s Removed stale files, reclaimed %s", styleSuccess.Render("✓"), clean.FormatSize(reclaimed))
+	return err
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/history"
+	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/internal/sync"
+	"github.com/asamgx/brewsync/internal/tui/selection"
+)
+
+// listInstalledPackages lists what is installed on this Mac for comparison
+// with its Brewfile; a variable so tests can stub it
+var listInstalledPackages = func() (brewfile.Packages, error) {
+	return installer.NewManager().ListTopLevel()
+}
+
+// runCleanOrphans offers to uninstall packages installed on this machine that
+// its Brewfile doesn't list: the reverse of import. Ignored, pinned and
+// machine-specific packages are left alone, as sync leaves them.
+func runCleanOrphans() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	currentMachine := cfg.CurrentMachine
+	machine, ok := cfg.Machines[currentMachine]
+	if currentMachine == "" || !ok {
+		return fmt.Errorf("could not detect current machine; run 'brewsync config init' first")
+	}
+
+	printVerbose("Reading Brewfile: %s", machine.Brewfile)
+	tracked, err := brewfile.Parse(machine.Brewfile)
+	if err != nil {
+		return fmt.Errorf("failed to parse Brewfile: %w", err)
+	}
+
+	printInfo("Listing installed packages...")
+	installed, err := listInstalledPackages()
+	if err != nil {
+		return fmt.Errorf("failed to list installed packages: %w", err)
+	}
+
+	plan := findOrphans(cfg, tracked, installed)
+	if n := len(plan.Protected) + len(plan.IgnoredRemovals); n > 0 {
+		printVerbose("Keeping %d ignored, pinned or machine-specific package(s)", n)
+	}
+	orphans := plan.Removals
+	if len(orphans) == 0 {
+		printInfo("No orphaned packages: everything installed is in %s's Brewfile", currentMachine)
+		return nil
+	}
+
+	printInfo("Found %d package(s) installed but not in the Brewfile", len(orphans))
+
+	if dryRun {
+		fmt.Println("\nWould uninstall:")
+		for _, pkg := range orphans {
+			fmt.Printf("  %s:%s\n", pkg.Type, pkg.Name)
+		}
+		return nil
+	}
+
+	toRemove := orphans
+	if !assumeYes {
+		model := selection.New(fmt.Sprintf("Clean %s - Select packages to uninstall", currentMachine), orphans)
+		finalModel, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+		if err != nil {
+			return fmt.Errorf("TUI error: %w", err)
+		}
+
+		m := finalModel.(selection.Model)
+		if m.Cancelled() {
+			printInfo("Clean cancelled")
+			return nil
+		}
+		toRemove = m.Selected()
+		ignoreFromSelection(currentMachine, m)
+	}
+
+	if len(toRemove) == 0 {
+		printInfo("No packages selected for removal")
+		return nil
+	}
+
+	if !assumeYes {
+		authenticateSudo(toRemove)
+	}
+
+	var removes installTally
+	printInfo("Uninstalling %d packages...", len(toRemove))
+	newInstallManager(cfg).UninstallMany(toRemove, func(pkg brewfile.Package, i, total int, err error) {
+		removes.record(err)
+		history.LogUninstall(currentMachine, pkg.ID(), err == nil)
+		if err != nil {
+			printError("[%d/%d] Failed to remove %s:%s: %s", i, total, pkg.Type, pkg.Name, failureReason(err))
+		} else {
+			printInfo("[%d/%d] Removed %s:%s", i, total, pkg.Type, pkg.Name)
+		}
+	})
+
+	printInfo("Clean complete: -%d removed, %d failed", removes.succeeded, removes.failures())
+	if hint := sudoHint(removes.needsSudo); hint != "" {
+		printWarning("%s", hint)
+	}
+	return nil
+}
+
+// findOrphans plans the removal of installed packages the Brewfile lacks, as
+// sync would when syncing to the machine's own Brewfile. Only the enabled
+// categories are compared, since the Brewfile holds nothing of the others.
+func findOrphans(cfg *config.Config, tracked, installed brewfile.Packages) *sync.Plan {
+	var categories []brewfile.PackageType
+	for _, c := range cfg.EffectiveCategories() {
+		categories = append(categories, brewfile.PackageType(c))
+	}
+	return sync.Compute(cfg, tracked, installed, sync.Options{Categories: categories})
+}
+
+// ignoreFromSelection adds the categories and packages ignored in the
+// selection to the machine's ignore list, as import does
+func ignoreFromSelection(machine string, m selection.Model) {
+	for _, category := range m.IgnoredCategories() {
+		if err := config.AddCategoryIgnore(machine, category, false); err != nil {
+			printWarning("Failed to ignore category %s: %v", category, err)
+		}
+	}
+	var ids []string
+	for _, pkg := range m.Ignored() {
+		if err := config.AddPackageIgnore(machine, pkg.ID(), false); err != nil {
+			printWarning("Failed to ignore %s: %v", pkg.Name, err)
+			continue
+		}
+		ids = append(ids, pkg.ID())
+	}
+	if len(ids) > 0 {
+		printInfo("Ignored %s", strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestRunCleanOrphans_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	brewfilePath := filepath.Join(dir, "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("tap \"homebrew/bundle\"\nbrew \"git\"\ncask \"firefox\"\n"), 0644))
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+machines:
+  mini:
+    brewfile: `+brewfilePath+`
+default_categories: [tap, brew, cask]
+pinned: ["brew:node"]
+machine_specific:
+  mini:
+    cask: [zoom]
+`), 0644))
+	ignoreFile := filepath.Join(dir, "ignore.yaml")
+	require.NoError(t, os.WriteFile(ignoreFile, []byte("machines:\n  mini:\n    packages:\n      brew: [htop]\n"), 0644))
+	config.SetConfigPath(configFile)
+	config.SetIgnorePath(ignoreFile)
+
+	origList := listInstalledPackages
+	listInstalledPackages = func() (brewfile.Packages, error) {
+		return brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeTap, "homebrew/bundle"),
+			brewfile.NewPackage(brewfile.TypeBrew, "git"),
+			brewfile.NewPackage(brewfile.TypeBrew, "wget"),
+			brewfile.NewPackage(brewfile.TypeBrew, "node"),
+			brewfile.NewPackage(brewfile.TypeBrew, "htop"),
+			brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+			brewfile.NewPackage(brewfile.TypeCask, "zoom"),
+			brewfile.NewPackage(brewfile.TypeCask, "vlc"),
+			brewfile.NewPackage(brewfile.TypeNpm, "prettier"),
+		}, nil
+	}
+	dryRun, cleanOrphans = true, true
+	t.Cleanup(func() {
+		config.SetConfigPath("")
+		config.SetIgnorePath("")
+		listInstalledPackages = origList
+		dryRun, cleanOrphans = false, false
+	})
+
+	// Ignored, pinned, machine-specific and disabled categories stay
+	out := captureStdout(t, func() { require.NoError(t, runClean(cleanCmd, nil)) })
+	assert.Contains(t, out, "Found 2 package(s) installed but not in the Brewfile\n")
+	assert.Contains(t, out, "\nWould uninstall:\n  brew:wget\n  cask:vlc\n")
+}
@@ -3,7 +3,6 @@ package cli
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -24,7 +23,8 @@ Subcommands:
   edit         Open config file in editor
   path         Show config file path
   init         Initialize configuration (interactive)
-  add-machine  Add a new machine configuration`,
+  add-machine  Add a new machine configuration
+  migrate      Rewrite an older config file in the current schema`,
 }
 
 var configShowCmd = &cobra.Command{
@@ -74,6 +74,14 @@ var (
 	addMachineDescription string
 )
 
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite an older config file in the current schema",
+	Long: `Older config files are upgraded in memory every time they are loaded.
+This reports what was upgraded and saves the config so the file is current.`,
+	RunE: runConfigMigrate,
+}
+
 var configAddMachineCmd = &cobra.Command{
 	Use:   "add-machine [name]",
 	Short: "Add a new machine configuration",
@@ -98,6 +106,7 @@ func init() {
 	configCmd.AddCommand(configPathCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configAddMachineCmd)
+	configCmd.AddCommand(configMigrateCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
@@ -117,6 +126,39 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	if !config.Exists() {
+		return fmt.Errorf("config file does not exist; run 'brewsync config init' first")
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	changes := cfg.Migrations()
+	if len(changes) == 0 {
+		printInfo("Config is already at schema version %d", cfg.SchemaVersion)
+		return nil
+	}
+
+	printInfo("Upgrading config to schema version %d:", config.CurrentSchemaVersion)
+	for _, change := range changes {
+		printInfo("  %s", change)
+	}
+
+	if dryRun {
+		printInfo("[dry-run] Would save the upgraded config")
+		return nil
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	printInfo("Config saved")
+	return nil
+}
+
 func runConfigEdit(cmd *cobra.Command, args []string) error {
 	path, err := config.ConfigPath()
 	if err != nil {
@@ -127,20 +169,7 @@ func runConfigEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("config file does not exist; run 'brewsync config init' first")
 	}
 
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-	if editor == "" {
-		editor = "vi"
-	}
-
-	editorCmd := exec.Command(editor, path)
-	editorCmd.Stdin = os.Stdin
-	editorCmd.Stdout = os.Stdout
-	editorCmd.Stderr = os.Stderr
-
-	return editorCmd.Run()
+	return openInEditor(path)
 }
 
 func runConfigPath(cmd *cobra.Command, args []string) error {
@@ -304,6 +333,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 
 	// Create initial config with all default settings
 	initialConfig := map[string]interface{}{
+		"schema_version": config.CurrentSchemaVersion,
 		"machines": map[string]interface{}{
 			machineName: map[string]interface{}{
 				"hostname":    machineHostname,
@@ -324,6 +354,7 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		},
 		"dump": map[string]interface{}{
 			"use_brew_bundle": true,
+			"header":          true,
 		},
 		"machine_specific": map[string]interface{}{},
 		"output": map[string]interface{}{
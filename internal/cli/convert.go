@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+var (
+	convertTo     string
+	convertFrom   string
+	convertOutput string
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [path]",
+	Short: "Convert between brewsync and plain brew bundle Brewfiles",
+	Long: `Convert a Brewfile between brewsync format and a plain 'brew bundle' Brewfile,
+e.g. to share your setup with people who don't use brewsync.
+
+Without a path, the current machine's Brewfile is used. Output goes to
+stdout unless --output is given.
+
+--to bundle is lossy: cursor, antigravity and go entries are brewsync
+extensions that 'brew bundle' doesn't understand and are dropped.
+tap, brew, cask, mas and vscode entries are kept.
+
+--from bundle ingests a plain Brewfile. Directives brewsync doesn't track
+(e.g. cask_args, whalebrew) are dropped and reported.
+
+Examples:
+  brewsync convert --to bundle                       # Current Brewfile as plain bundle
+  brewsync convert --to bundle -o ~/Brewfile.share   # Write to a file
+  brewsync convert --from bundle ~/colleague/Brewfile -o Brewfile`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConvert,
+}
+
+func init() {
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "convert brewsync Brewfile to format: bundle")
+	convertCmd.Flags().StringVar(&convertFrom, "from", "", "convert from format to brewsync: bundle")
+	convertCmd.Flags().StringVarP(&convertOutput, "output", "o", "", "write result to file instead of stdout")
+	convertCmd.MarkFlagsMutuallyExclusive("to", "from")
+	convertCmd.MarkFlagsOneRequired("to", "from")
+	rootCmd.AddCommand(convertCmd)
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	format := convertTo
+	if format == "" {
+		format = convertFrom
+	}
+	if format != "bundle" {
+		return fmt.Errorf("unsupported format '%s'; supported formats: bundle", format)
+	}
+
+	path, err := convertInputPath(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read Brewfile: %w", err)
+	}
+
+	var content string
+	if convertTo != "" {
+		pkgs, err := brewfile.ParseContent(string(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse Brewfile: %w", err)
+		}
+
+		var dropped brewfile.Packages
+		content, dropped = brewfile.FormatBundle(pkgs)
+		if len(dropped) > 0 {
+			ids := make([]string, len(dropped))
+			for i, pkg := range dropped {
+				ids[i] = pkg.ID()
+			}
+			printWarning("Dropped %d package(s) not supported by brew bundle: %s", len(dropped), strings.Join(ids, ", "))
+		}
+	} else {
+		pkgs, unsupported, err := brewfile.ParseBundle(string(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse Brewfile: %w", err)
+		}
+
+		content = brewfile.NewWriter(pkgs).Format()
+		if len(unsupported) > 0 {
+			printWarning("Dropped %d line(s) brewsync doesn't track: %s", len(unsupported), strings.Join(unsupported, "; "))
+		}
+	}
+
+	if convertOutput == "" {
+		fmt.Print(content)
+		return nil
+	}
+
+	if dryRun {
+		printInfo("[dry-run] Would write converted Brewfile to %s", convertOutput)
+		return nil
+	}
+
+	if err := os.WriteFile(convertOutput, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", convertOutput, err)
+	}
+	printInfo("Wrote %s", convertOutput)
+	return nil
+}
+
+// convertInputPath returns the Brewfile to convert (argument or current machine's Brewfile)
+func convertInputPath(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	machine, ok := cfg.GetCurrentMachine()
+	if !ok {
+		return "", fmt.Errorf("no path given and current machine not detected")
+	}
+	return machine.Brewfile, nil
+}
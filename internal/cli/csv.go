@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// CSV output shares the data gathered for the table and JSON formats; only the
+// encoding differs. encoding/csv quotes names and descriptions containing
+// commas, quotes or newlines.
+
+// outputListCSV writes one type,name,description row per package
+func outputListCSV(packages brewfile.Packages) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"type", "name", "description"})
+	for _, pkg := range packages {
+		_ = w.Write([]string{string(pkg.Type), pkg.Name, pkg.Description})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// outputDiffCSV writes one change,type,name,detail row per pending change.
+// change is add, remove, tap_changed, version_changed or outdated; detail is
+// "from → to" for tap and version changes, "installed → latest" for outdated
+// packages and "not found" for additions Homebrew can't find.
+func outputDiffCSV(diff *brewfile.DiffResult, tapChanges []brewfile.TapChange, versionChanges []brewfile.VersionChange, unavailable brewfile.Packages) error {
+	notFound := make(map[string]bool, len(unavailable))
+	for _, pkg := range unavailable {
+		notFound[pkg.ID()] = true
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"change", "type", "name", "detail"})
+	for _, c := range tapChanges {
+		_ = w.Write([]string{"tap_changed", string(c.Source.Type), c.Source.BaseName(), c.From() + " → " + c.To()})
+	}
+	for _, c := range versionChanges {
+		_ = w.Write([]string{"version_changed", string(c.Source.Type), c.Source.Name, c.From() + " → " + c.To()})
+	}
+	for _, o := range diff.Outdated {
+		_ = w.Write([]string{"outdated", string(o.Package.Type), o.Package.Name, o.Installed + " → " + o.Latest})
+	}
+	for _, pkg := range diff.Additions {
+		detail := ""
+		if notFound[pkg.ID()] {
+			detail = "not found"
+		}
+		_ = w.Write([]string{"add", string(pkg.Type), pkg.Name, detail})
+	}
+	for _, pkg := range diff.Removals {
+		_ = w.Write([]string{"remove", string(pkg.Type), pkg.Name, ""})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// outputStatusCSV writes a machine,type,packages,additions,removals summary
+// row for every type the machine has or has pending. diff may be nil when
+// there is no source to compare against.
+func outputStatusCSV(machine string, packages brewfile.Packages, diff *brewfile.DiffResult) error {
+	stats := packages.Stats()
+	var additions, removals brewfile.Stats
+	if diff != nil {
+		additions = diff.Additions.Stats()
+		removals = diff.Removals.Stats()
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"machine", "type", "packages", "additions", "removals"})
+	for _, t := range brewfile.AllTypes() {
+		count, added, removed := stats.Count(t), additions.Count(t), removals.Count(t)
+		if count == 0 && added == 0 && removed == 0 {
+			continue
+		}
+		_ = w.Write([]string{machine, string(t), strconv.Itoa(count), strconv.Itoa(added), strconv.Itoa(removed)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// outputOverlapsCSV writes one type,name,machine_count,machines row per
+// package, most widely shared first; machines are separated by semicolons
+func outputOverlapsCSV(groups []overlapGroup) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{"type", "name", "machine_count", "machines"})
+	for _, g := range groups {
+		for _, pkg := range g.Packages {
+			_ = w.Write([]string{pkg.Type, pkg.Name, strconv.Itoa(g.Count), strings.Join(pkg.Machines, ";")})
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"encoding/csv"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func readCSV(t *testing.T, out string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestOutputListCSV(t *testing.T) {
+	git := brewfile.NewPackage(brewfile.TypeBrew, "git")
+	git.Description = "Distributed revision control system"
+	quoted := brewfile.NewPackage(brewfile.TypeCask, "firefox")
+	quoted.Description = `Web browser, "fast" and free`
+	ext := brewfile.NewPackage(brewfile.TypeVSCode, "golang.go")
+
+	out := captureStdout(t, func() {
+		require.NoError(t, outputListCSV(brewfile.Packages{git, quoted, ext}))
+	})
+
+	assert.Contains(t, out, `"Web browser, ""fast"" and free"`)
+	assert.Equal(t, [][]string{
+		{"type", "name", "description"},
+		{"brew", "git", "Distributed revision control system"},
+		{"cask", "firefox", `Web browser, "fast" and free`},
+		{"vscode", "golang.go", ""},
+	}, readCSV(t, out))
+}
+
+func TestOutputDiffCSV(t *testing.T) {
+	diff := &brewfile.DiffResult{
+		Additions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "ripgrep")},
+		Removals:  brewfile.Packages{brewfile.NewPackage(brewfile.TypeGo, "golang.org/x/tools/cmd/stringer,v2")},
+	}
+	changes := []brewfile.TapChange{{
+		Source:  brewfile.NewPackage(brewfile.TypeBrew, "user/tap/jq"),
+		Current: brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+	}}
+	versions := []brewfile.VersionChange{{
+		Source:  brewfile.Package{Type: brewfile.TypeCursor, Name: "golang.go", Version: "0.42.0"},
+		Current: brewfile.NewPackage(brewfile.TypeCursor, "golang.go"),
+	}}
+
+	out := captureStdout(t, func() { require.NoError(t, outputDiffCSV(diff, changes, versions, nil)) })
+
+	assert.Equal(t, [][]string{
+		{"change", "type", "name", "detail"},
+		{"tap_changed", "brew", "jq", "core → user/tap"},
+		{"version_changed", "cursor", "golang.go", "unpinned → 0.42.0"},
+		{"add", "brew", "ripgrep", ""},
+		{"remove", "go", "golang.org/x/tools/cmd/stringer,v2", ""},
+	}, readCSV(t, out))
+}
+
+func TestOutputStatusCSV(t *testing.T) {
+	packages := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+	}
+
+	t.Run("with pending changes", func(t *testing.T) {
+		diff := &brewfile.DiffResult{
+			Additions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeMas, "Xcode")},
+			Removals:  brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "jq")},
+		}
+		out := captureStdout(t, func() { require.NoError(t, outputStatusCSV("work, laptop", packages, diff)) })
+
+		assert.Equal(t, [][]string{
+			{"machine", "type", "packages", "additions", "removals"},
+			{"work, laptop", "brew", "2", "0", "1"},
+			{"work, laptop", "cask", "1", "0", "0"},
+			{"work, laptop", "mas", "0", "1", "0"},
+		}, readCSV(t, out))
+	})
+
+	t.Run("without a source", func(t *testing.T) {
+		out := captureStdout(t, func() { require.NoError(t, outputStatusCSV("mini", packages, nil)) })
+
+		records := readCSV(t, out)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"mini", "brew", "2", "0", "0"}, records[1])
+	})
+}
+
+func TestRunStatus_CSVWithoutBrewfile(t *testing.T) {
+	writeFleet(t, map[string]string{"mini": ""})
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(cfg.Machines["mini"].Brewfile))
+	statusFormat = "csv"
+	t.Cleanup(func() { statusFormat = "table" })
+
+	out := captureStdout(t, func() { require.NoError(t, runStatus(statusCmd, nil)) })
+	assert.Equal(t, []string{"machine", "type", "packages", "additions", "removals"}, readCSV(t, out)[0])
+}
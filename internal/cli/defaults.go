@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// commandKey names cmd the way the defaults config does: its path below the
+// root command, e.g. "import" or "profile install"
+func commandKey(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) < 2 {
+		return ""
+	}
+	return strings.Join(path[1:], " ")
+}
+
+// applyFlagDefaults sets the flags of cmd that were not given on the command
+// line to the values configured for it under defaults. Unknown flags and
+// values a flag doesn't accept are errors, so typos don't go unnoticed.
+func applyFlagDefaults(cmd *cobra.Command, defaults config.FlagDefaults) error {
+	key := commandKey(cmd)
+	values := defaults[key]
+	if key == "" || len(values) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("defaults.%s: unknown flag --%s", key, name)
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := setFlagDefault(flag, values[name]); err != nil {
+			return fmt.Errorf("defaults.%s: invalid value for --%s: %w", key, name, err)
+		}
+	}
+	return nil
+}
+
+// setFlagDefault sets flag from a config value. Lists become comma-separated
+// values, as they would be typed on the command line. The flag stays unchanged
+// so commands can still tell it wasn't given explicitly.
+func setFlagDefault(flag *pflag.Flag, value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		s = strings.Join(parts, ",")
+	case nil:
+		return fmt.Errorf("missing value")
+	default:
+		s = fmt.Sprint(v)
+	}
+	return flag.Value.Set(s)
+}
+
+// unknownDefaultCommands returns the commands named under defaults that
+// brewsync doesn't have
+func unknownDefaultCommands(root *cobra.Command, defaults config.FlagDefaults) []string {
+	var unknown []string
+	for key := range defaults {
+		cmd, _, err := root.Find(strings.Fields(key))
+		if err != nil || cmd == root || commandKey(cmd) != key {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
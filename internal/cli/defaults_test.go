@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// newDefaultsTestCommands returns a root command with an import subcommand
+// and a nested profile install command
+func newDefaultsTestCommands() (root, imp, install *cobra.Command) {
+	root = &cobra.Command{Use: "brewsync"}
+	root.PersistentFlags().Bool("yes", false, "")
+	imp = &cobra.Command{Use: "import", Run: func(*cobra.Command, []string) {}}
+	imp.Flags().Bool("include-machine-specific", false, "")
+	imp.Flags().String("only", "", "")
+	imp.Flags().StringSlice("skip", nil, "")
+	profile := &cobra.Command{Use: "profile"}
+	install = &cobra.Command{Use: "install", Run: func(*cobra.Command, []string) {}}
+	install.Flags().Int("jobs", 1, "")
+	profile.AddCommand(install)
+	root.AddCommand(imp, profile)
+	return root, imp, install
+}
+
+func TestApplyFlagDefaults(t *testing.T) {
+	defaults := config.FlagDefaults{
+		"import": {
+			"include-machine-specific": true,
+			"only":                     "brew",
+			"skip":                     []interface{}{"mas", "go"},
+			"yes":                      true,
+		},
+		"profile install": {"jobs": 4},
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		_, imp, install := newDefaultsTestCommands()
+		require.NoError(t, imp.ParseFlags(nil))
+		require.NoError(t, applyFlagDefaults(imp, defaults))
+
+		include, _ := imp.Flags().GetBool("include-machine-specific")
+		assert.True(t, include)
+		only, _ := imp.Flags().GetString("only")
+		assert.Equal(t, "brew", only)
+		skip, _ := imp.Flags().GetStringSlice("skip")
+		assert.Equal(t, []string{"mas", "go"}, skip)
+		yes, _ := imp.Flags().GetBool("yes")
+		assert.True(t, yes, "inherited flags take defaults too")
+		assert.False(t, imp.Flags().Changed("only"))
+
+		require.NoError(t, install.ParseFlags(nil))
+		require.NoError(t, applyFlagDefaults(install, defaults))
+		jobs, _ := install.Flags().GetInt("jobs")
+		assert.Equal(t, 4, jobs)
+	})
+
+	t.Run("command line wins", func(t *testing.T) {
+		_, imp, _ := newDefaultsTestCommands()
+		require.NoError(t, imp.ParseFlags([]string{"--only", "cask", "--include-machine-specific=false"}))
+		require.NoError(t, applyFlagDefaults(imp, defaults))
+
+		only, _ := imp.Flags().GetString("only")
+		assert.Equal(t, "cask", only)
+		include, _ := imp.Flags().GetBool("include-machine-specific")
+		assert.False(t, include)
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		_, imp, _ := newDefaultsTestCommands()
+		require.NoError(t, imp.ParseFlags(nil))
+		err := applyFlagDefaults(imp, config.FlagDefaults{"import": {"include-machine-specfic": true}})
+		assert.EqualError(t, err, "defaults.import: unknown flag --include-machine-specfic")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, _, install := newDefaultsTestCommands()
+		require.NoError(t, install.ParseFlags(nil))
+		err := applyFlagDefaults(install, config.FlagDefaults{"profile install": {"jobs": "many"}})
+		assert.ErrorContains(t, err, "defaults.profile install: invalid value for --jobs")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		root, _, _ := newDefaultsTestCommands()
+		unknown := unknownDefaultCommands(root, config.FlagDefaults{"import": nil, "profile install": nil, "imprt": nil, "profile nope": nil})
+		assert.Equal(t, []string{"imprt", "profile nope"}, unknown)
+	})
+}
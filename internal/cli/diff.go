@@ -4,19 +4,41 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
 )
 
 var (
-	diffFrom   string
-	diffOnly   []string
-	diffFormat string
+	diffFrom     string
+	diffTo       string
+	diffOnly     []string
+	diffFormat   string
+	diffSkipArch bool
+	diffSince    string
+
+	diffOnlyInstalled bool
+	diffCheckAvail    bool
+	diffOnlyAdds      bool
+	diffOnlyRemoves   bool
+
+	diffIgnoreAllAdditions bool
+	diffIgnoreAllRemovals  bool
+	diffIgnoreScope        string
+)
+
+// Values of diff --ignore-scope: whose ignore list marks packages as ignored
+const (
+	ignoreScopeFrom = "from"
+	ignoreScopeTo   = "to"
+	ignoreScopeNone = "none"
 )
 
 var diffCmd = &cobra.Command{
@@ -25,20 +47,65 @@ var diffCmd = &cobra.Command{
 	Long: `Show differences between the current machine and a source machine.
 
 Without arguments, compares with the default source machine.
-Use --from to specify a different source machine.
+Use --from to specify a different source machine, and --to to compare it
+with another machine instead of this one.
 
 Examples:
   brewsync diff                  # Compare with default source
   brewsync diff --from air       # Compare with specific machine
+  brewsync diff --from air --to studio  # Compare two other machines
   brewsync diff --only brew,cask # Filter to specific types
-  brewsync diff --format json    # Output as JSON`,
+  brewsync diff --format json    # Output as JSON
+  brewsync diff --format csv     # One row per change, for spreadsheets
+  brewsync diff --only-adds      # Only show what would be installed
+
+If the machines' recorded architectures differ (arm64 vs amd64), a warning is
+shown. Use --skip-arch-specific to hide packages that only exist for one arch.
+
+Removals come from the current Brewfile, which may list packages that were
+already uninstalled by hand. Use --only-installed to check removals against
+what is actually installed right now.
+
+Formulae and casks both machines have are checked with 'brew outdated' when
+Homebrew is installed, and those with a newer version available are listed
+as outdated.
+
+Use --since 7d to only show removals installed here in the last week, i.e.
+what you added on this machine that the source doesn't have yet. Install
+times come from Homebrew, so only formulae and casks can be dated; additions
+are not installed here and are shown as usual.
+
+--ignore-all-additions and --ignore-all-removals add every shown addition or
+removal to the current machine's ignore list after printing the diff, so
+later imports and syncs leave them alone. They ask first unless --yes is
+given (which JSON and CSV output require).
+
+Packages on an ignore list are marked (ignored). --ignore-scope picks whose
+list that is: to (the machine compared against, the default), from (the
+source machine) or none. --ignore-all-additions and --ignore-all-removals
+add to the same machine's list.
+
+--only-installed and --since look at what is installed here, so they only
+work when --to is this machine.`,
 	RunE: runDiff,
 }
 
 func init() {
 	diffCmd.Flags().StringVar(&diffFrom, "from", "", "source machine to compare with")
-	diffCmd.Flags().StringSliceVar(&diffOnly, "only", nil, "only include these package types")
-	diffCmd.Flags().StringVar(&diffFormat, "format", "table", "output format: table, json")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "machine to compare against (default: this machine)")
+	diffCmd.Flags().StringVar(&diffIgnoreScope, "ignore-scope", ignoreScopeTo, "whose ignore list the diff respects: to, from, none")
+	diffCmd.Flags().StringSliceVar(&diffOnly, "only", nil, "only include these package types (or aliases: editors, cli, apps)")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "table", "output format: table, json, csv")
+	diffCmd.Flags().BoolVar(&noPager, "no-pager", false, "don't page long output through $PAGER (or output.pager)")
+	diffCmd.Flags().BoolVar(&diffSkipArch, "skip-arch-specific", false, "hide architecture-specific packages when machines differ in arch")
+	diffCmd.Flags().StringVar(&diffSince, "since", "", "only show removals installed after this age or date (e.g. 7d, 2026-01-31)")
+	diffCmd.Flags().BoolVar(&diffOnlyInstalled, "only-installed", false, "only show removals that are currently installed")
+	diffCmd.Flags().BoolVar(&diffCheckAvail, "check-available", false, "mark additions Homebrew can't find (renamed or removed)")
+	diffCmd.Flags().BoolVar(&diffOnlyAdds, "only-adds", false, "only show additions (packages to install)")
+	diffCmd.Flags().BoolVar(&diffOnlyRemoves, "only-removes", false, "only show removals (packages not in source)")
+	diffCmd.Flags().BoolVar(&diffIgnoreAllAdditions, "ignore-all-additions", false, "add all shown additions to the --ignore-scope machine's ignore list")
+	diffCmd.Flags().BoolVar(&diffIgnoreAllRemovals, "ignore-all-removals", false, "add all shown removals to the --ignore-scope machine's ignore list")
+	diffCmd.MarkFlagsMutuallyExclusive("only-adds", "only-removes")
 	rootCmd.AddCommand(diffCmd)
 }
 
@@ -57,11 +124,15 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no source machine specified and no default_source in config")
 	}
 
-	// Get current machine
-	currentMachine := cfg.CurrentMachine
+	// Get the machine to compare against, this one unless --to is given
+	currentMachine := diffTo
+	if currentMachine == "" {
+		currentMachine = cfg.CurrentMachine
+	}
 	if currentMachine == "" {
 		return fmt.Errorf("current machine not detected; run 'brewsync config init'")
 	}
+	local := currentMachine == cfg.CurrentMachine
 
 	// Can't diff with self
 	if source == currentMachine {
@@ -77,10 +148,33 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	// Get current machine config
 	current, ok := cfg.Machines[currentMachine]
 	if !ok {
+		if !local {
+			return fmt.Errorf("target machine '%s' not found in config", currentMachine)
+		}
 		return fmt.Errorf("current machine '%s' not found in config", currentMachine)
 	}
 
-	printInfo("Comparing %s -> %s", source, currentMachine)
+	// Installed state is only known for this machine
+	if !local && (diffOnlyInstalled || diffSince != "") {
+		return fmt.Errorf("--only-installed and --since check what is installed here and can't be used with --to %s", currentMachine)
+	}
+
+	ignoreMachine, err := ignoreScopeMachine(diffIgnoreScope, source, currentMachine)
+	if err != nil {
+		return err
+	}
+
+	// Keep machine-readable output clean
+	ignoring := diffIgnoreAllAdditions || diffIgnoreAllRemovals
+	if ignoring && ignoreMachine == "" {
+		return fmt.Errorf("--ignore-all-additions/--ignore-all-removals need an ignore list; they can't be used with --ignore-scope none")
+	}
+	if ignoring && !assumeYes && (diffFormat == "json" || diffFormat == "csv") {
+		return fmt.Errorf("--ignore-all-additions/--ignore-all-removals with --format %s need --yes", diffFormat)
+	}
+	if diffFormat != "json" && diffFormat != "csv" {
+		printInfo("Comparing %s -> %s", source, currentMachine)
+	}
 
 	// Parse source Brewfile
 	printVerbose("Parsing source Brewfile: %s", sourceMachine.Brewfile)
@@ -104,49 +198,278 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	// Compute diff
 	var diff *brewfile.DiffResult
 	if len(diffOnly) > 0 {
-		types := parsePackageTypes(diffOnly)
-		diff = brewfile.DiffByType(sourcePackages, currentPackages, types)
+		types, err := brewfile.ParseCategories(diffOnly...)
+		if err != nil {
+			return err
+		}
+		diff = brewfile.DiffByTypeWithAliases(sourcePackages, currentPackages, types, cfg.ExtensionAliases)
 	} else {
-		diff = brewfile.Diff(sourcePackages, currentPackages)
+		diff = brewfile.DiffWithAliases(sourcePackages, currentPackages, cfg.ExtensionAliases)
 	}
 
-	// Output results
+	// Compare recorded architectures; arch-specific packages are expected to differ
+	arch := brewfile.CompareArch(sourceMachine.Brewfile, current.Brewfile)
+	var archSkipped brewfile.Packages
+	if arch.Mismatch() && diffSkipArch {
+		diff, archSkipped = diff.FilterArchSpecific()
+	}
+
+	// Cross-check removals against the live installed state
+	var notInstalled brewfile.Packages
+	if diffOnlyInstalled {
+		installed, err := installer.NewManager().ListAll()
+		if err != nil {
+			return fmt.Errorf("failed to list installed packages: %w", err)
+		}
+		diff, notInstalled = diff.FilterInstalled(installed)
+	}
+
+	// Keep only removals installed here after the cutoff
+	var undated brewfile.Packages
+	if diffSince != "" {
+		cutoff, err := parseSince(diffSince, time.Now())
+		if err != nil {
+			return err
+		}
+		var recent brewfile.Packages
+		recent, undated, err = installedSince(diff.Removals, cutoff)
+		if err != nil {
+			return err
+		}
+		diff = &brewfile.DiffResult{Additions: diff.Additions, Removals: recent, Common: diff.Common}
+	}
+
+	// Same formula from a different tap is a change, not an add plus a remove.
+	// Focused views keep both sides as they are.
+	var tapChanges []brewfile.TapChange
+	var versionChanges []brewfile.VersionChange
+	if !diffOnlyAdds && !diffOnlyRemoves {
+		diff, tapChanges = diff.SplitTapChanges()
+		versionChanges = brewfile.VersionChanges(sourcePackages, currentPackages, cfg.ExtensionAliases)
+		if len(diffOnly) > 0 {
+			versionChanges = filterVersionChanges(versionChanges, diffOnly)
+		}
+	}
+
+	// Focus on one side if requested; the summary then counts only that side
+	diff = focusDiff(diff, diffOnlyAdds, diffOnlyRemoves)
+
+	// Packages both sides have that Homebrew can upgrade here. A failed
+	// lookup only warns, as the rest of the diff doesn't depend on it.
+	if local && !diffOnlyAdds && !diffOnlyRemoves {
+		if err := installer.NewBrewInstaller().MarkOutdated(diff); err != nil {
+			printWarning("Could not check for outdated packages: %v", err)
+		}
+	}
+
+	// Mark additions that would fail to install because brew can't find them
+	var unavailable brewfile.Packages
+	if diffCheckAvail {
+		unavailable, err = installer.NewBrewInstaller().Unavailable(diff.Additions)
+		if err != nil {
+			return fmt.Errorf("failed to check package availability: %w", err)
+		}
+	}
+
+	// Output results, paged if long
+	err = withPager(cfg, diffFormat, func() error {
+		return outputDiff(diff, tapChanges, versionChanges, arch, archSkipped, notInstalled, unavailable, undated, source, currentMachine)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Batch-ignore what was just shown
+	if !ignoring {
+		return nil
+	}
+	if diffFormat == "json" || diffFormat == "csv" {
+		// Stdout carries the diff; report on stderr so it stays parseable
+		jsonResult = true
+		defer func() { jsonResult = false }()
+	}
+	var toIgnore brewfile.Packages
+	if diffIgnoreAllAdditions {
+		toIgnore = append(toIgnore, diff.Additions...)
+	}
+	if diffIgnoreAllRemovals {
+		toIgnore = append(toIgnore, diff.Removals...)
+	}
+	toIgnore = notIgnored(cfg, ignoreMachine, toIgnore)
+	if len(toIgnore) == 0 {
+		printInfo("Nothing new to ignore")
+		return nil
+	}
+	if !assumeYes {
+		fmt.Printf("Add %d package(s) to %s's ignore list? [y/N] ", len(toIgnore), ignoreMachine)
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			printInfo("Nothing ignored")
+			return nil
+		}
+	}
+	if err := ignorePackages(ignoreMachine, toIgnore); err != nil {
+		return err
+	}
+	printInfo("Ignored %d package(s) on %s: %s", len(toIgnore), ignoreMachine, strings.Join(toIgnore.IDs(), ", "))
+	return nil
+}
+
+// outputDiff prints the diff in the --format chosen
+func outputDiff(diff *brewfile.DiffResult, tapChanges []brewfile.TapChange, versionChanges []brewfile.VersionChange, arch brewfile.ArchInfo, archSkipped, notInstalled, unavailable, undated brewfile.Packages, source, currentMachine string) error {
 	switch diffFormat {
 	case "json":
-		return outputDiffJSON(diff)
+		return outputDiffJSON(diff, tapChanges, versionChanges, arch, archSkipped, notInstalled, unavailable)
+	case "csv":
+		return outputDiffCSV(diff, tapChanges, versionChanges, unavailable)
+	default:
+		if len(undated) > 0 {
+			printInfo("Hiding %d removal(s) without install times", len(undated))
+		}
+		if len(unavailable) > 0 {
+			printWarning("%d addition(s) not found in Homebrew (renamed or removed?) and would fail to install: %s", len(unavailable), strings.Join(unavailable.IDs(), ", "))
+		}
+		if len(notInstalled) > 0 {
+			printInfo("Hiding %d removal(s) not currently installed: %s", len(notInstalled), strings.Join(notInstalled.IDs(), ", "))
+		}
+		if arch.Mismatch() {
+			printWarning("%s is %s but %s is %s; some differences may be architecture-specific", source, arch.Source, currentMachine, arch.Current)
+			if len(archSkipped) > 0 {
+				printInfo("Hiding %d architecture-specific package(s): %s", len(archSkipped), strings.Join(archSkipped.IDs(), ", "))
+			} else if !diffSkipArch {
+				printInfo("Use --skip-arch-specific to hide architecture-specific packages")
+			}
+		}
+		return outputDiffTable(diff, tapChanges, versionChanges, unavailable, source, currentMachine)
+	}
+}
+
+// ignoreScopeMachine returns the machine whose ignore list a diff from source
+// to target respects under the --ignore-scope value, or "" for none
+func ignoreScopeMachine(scope, source, target string) (string, error) {
+	switch scope {
+	case "", ignoreScopeTo:
+		return target, nil
+	case ignoreScopeFrom:
+		return source, nil
+	case ignoreScopeNone:
+		return "", nil
 	default:
-		return outputDiffTable(diff, source, currentMachine)
+		return "", fmt.Errorf("unknown --ignore-scope %q (use to, from or none)", scope)
 	}
 }
 
-func parsePackageTypes(types []string) []brewfile.PackageType {
-	var result []brewfile.PackageType
-	for _, t := range types {
-		switch strings.ToLower(strings.TrimSpace(t)) {
-		case "tap":
-			result = append(result, brewfile.TypeTap)
-		case "brew":
-			result = append(result, brewfile.TypeBrew)
-		case "cask":
-			result = append(result, brewfile.TypeCask)
-		case "vscode":
-			result = append(result, brewfile.TypeVSCode)
-		case "cursor":
-			result = append(result, brewfile.TypeCursor)
-		case "go":
-			result = append(result, brewfile.TypeGo)
-		case "mas":
-			result = append(result, brewfile.TypeMas)
+// notIgnored returns the packages not yet ignored on machine, by package or category
+func notIgnored(cfg *config.Config, machine string, pkgs brewfile.Packages) brewfile.Packages {
+	var result brewfile.Packages
+	for _, pkg := range pkgs {
+		if !cfg.IsCategoryIgnored(machine, string(pkg.Type)) && !cfg.IsPackageIgnored(machine, pkg.ID()) {
+			result = append(result, pkg)
 		}
 	}
 	return result
 }
 
-func outputDiffJSON(diff *brewfile.DiffResult) error {
+// ignorePackages adds pkgs to machine's ignore list
+func ignorePackages(machine string, pkgs brewfile.Packages) error {
+	for _, pkg := range pkgs {
+		if err := config.AddPackageIgnore(machine, pkg.ID(), false); err != nil {
+			return fmt.Errorf("failed to ignore %s: %w", pkg.ID(), err)
+		}
+	}
+	return nil
+}
+
+// filterVersionChanges keeps the version changes of the --only categories
+func filterVersionChanges(changes []brewfile.VersionChange, only []string) []brewfile.VersionChange {
+	types, err := brewfile.ParseCategories(only...)
+	if err != nil {
+		return changes
+	}
+	var result []brewfile.VersionChange
+	for _, c := range changes {
+		if slices.Contains(types, c.Source.Type) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// focusDiff drops the removals (onlyAdds) or additions (onlyRemoves) from a diff
+func focusDiff(diff *brewfile.DiffResult, onlyAdds, onlyRemoves bool) *brewfile.DiffResult {
+	switch {
+	case onlyAdds:
+		return &brewfile.DiffResult{Additions: diff.Additions, Common: diff.Common}
+	case onlyRemoves:
+		return &brewfile.DiffResult{Removals: diff.Removals, Common: diff.Common}
+	default:
+		return diff
+	}
+}
+
+func outputDiffJSON(diff *brewfile.DiffResult, tapChanges []brewfile.TapChange, versionChanges []brewfile.VersionChange, arch brewfile.ArchInfo, archSkipped, notInstalled, unavailable brewfile.Packages) error {
 	output := map[string]interface{}{
-		"additions": packageNames(diff.Additions),
-		"removals":  packageNames(diff.Removals),
-		"common":    len(diff.Common),
+		"common": len(diff.Common),
+	}
+	if len(tapChanges) > 0 {
+		changed := make([]map[string]string, len(tapChanges))
+		for i, c := range tapChanges {
+			changed[i] = map[string]string{
+				"type": string(c.Source.Type),
+				"name": c.Source.BaseName(),
+				"from": c.From(),
+				"to":   c.To(),
+			}
+		}
+		output["tap_changed"] = changed
+	}
+	if len(versionChanges) > 0 {
+		changed := make([]map[string]string, len(versionChanges))
+		for i, c := range versionChanges {
+			changed[i] = map[string]string{
+				"type": string(c.Source.Type),
+				"name": c.Source.Name,
+				"from": c.From(),
+				"to":   c.To(),
+			}
+		}
+		output["version_changed"] = changed
+	}
+	if !diffOnlyAdds && !diffOnlyRemoves {
+		outdated := make([]map[string]string, len(diff.Outdated))
+		for i, o := range diff.Outdated {
+			outdated[i] = map[string]string{
+				"type":      string(o.Package.Type),
+				"name":      o.Package.Name,
+				"installed": o.Installed,
+				"latest":    o.Latest,
+			}
+		}
+		output["outdated"] = outdated
+	}
+	if !diffOnlyRemoves {
+		output["additions"] = packageNames(diff.Additions)
+		if diffCheckAvail {
+			output["unavailable"] = packageNames(unavailable)
+		}
+	}
+	if !diffOnlyAdds {
+		output["removals"] = packageNames(diff.Removals)
+		if cfg, err := config.Get(); err == nil && len(cfg.Pinned) > 0 {
+			_, pinned := diff.FilterPinned(cfg.PinnedSet())
+			output["pinned"] = packageNames(pinned)
+		}
+		if diffOnlyInstalled {
+			output["not_installed"] = packageNames(notInstalled)
+		}
+	}
+	if arch.Mismatch() {
+		output["arch_mismatch"] = map[string]interface{}{
+			"source":  arch.Source,
+			"current": arch.Current,
+			"skipped": packageNames(archSkipped),
+		}
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -163,7 +486,7 @@ func packageNames(pkgs brewfile.Packages) map[string][]string {
 	return result
 }
 
-func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
+func outputDiffTable(diff *brewfile.DiffResult, tapChanges []brewfile.TapChange, versionChanges []brewfile.VersionChange, unavailable brewfile.Packages, source, current string) error {
 	cfg, _ := config.Get()
 
 	const tableWidth = 80
@@ -183,7 +506,7 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 	fmt.Println(headerBox.Render(headerText))
 	fmt.Println()
 
-	if diff.IsEmpty() {
+	if diff.IsEmpty() && len(tapChanges) == 0 && len(versionChanges) == 0 && len(diff.Outdated) == 0 {
 		// No differences box
 		noDiffBox := lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -199,9 +522,15 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 			Bold(true).
 			Render("✓")
 
+		noDiffText := "No differences found - machines are in sync!"
+		if diffOnlyAdds {
+			noDiffText = "Nothing to install - current has everything in source"
+		} else if diffOnlyRemoves {
+			noDiffText = "Nothing to remove - current has nothing extra"
+		}
 		msg := lipgloss.NewStyle().
 			Foreground(catGreen).
-			Render("No differences found - machines are in sync!")
+			Render(noDiffText)
 
 		content := lipgloss.JoinHorizontal(lipgloss.Left, successIcon, " ", msg)
 		fmt.Println(noDiffBox.Render(content))
@@ -209,18 +538,45 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 		return nil
 	}
 
-	// Get ignored packages for current machine
+	// Get ignored packages for the --ignore-scope machine, the target by default
 	ignoredIDs := make(map[string]bool)
-	for _, id := range cfg.GetIgnoredPackages(current) {
-		ignoredIDs[id] = true
+	if machine, _ := ignoreScopeMachine(diffIgnoreScope, source, current); machine != "" {
+		for _, id := range cfg.GetIgnoredPackages(machine) {
+			ignoredIDs[id] = true
+		}
+	}
+
+	// Pinned packages are never removed by sync
+	pinnedIDs := cfg.PinnedSet()
+
+	// Additions brew can't find
+	unavailableIDs := make(map[string]bool, len(unavailable))
+	for _, pkg := range unavailable {
+		unavailableIDs[pkg.ID()] = true
 	}
 
 	// Column width (split the table in half with some margin)
 	colWidth := (tableWidth - 6) / 2 // 6 = padding + divider
+	singleColumn := diffOnlyAdds || diffOnlyRemoves
+	if singleColumn {
+		colWidth = tableWidth - 6
+	}
 
 	// Group packages by type
 	additionsByType := diff.Additions.ByType()
 	removalsByType := diff.Removals.ByType()
+	tapChangesByType := make(map[brewfile.PackageType][]brewfile.TapChange)
+	for _, c := range tapChanges {
+		tapChangesByType[c.Source.Type] = append(tapChangesByType[c.Source.Type], c)
+	}
+	versionChangesByType := make(map[brewfile.PackageType][]brewfile.VersionChange)
+	for _, c := range versionChanges {
+		versionChangesByType[c.Source.Type] = append(versionChangesByType[c.Source.Type], c)
+	}
+	outdatedByType := make(map[brewfile.PackageType][]brewfile.Outdated)
+	for _, o := range diff.Outdated {
+		outdatedByType[o.Package.Type] = append(outdatedByType[o.Package.Type], o)
+	}
 
 	typeOrder := []brewfile.PackageType{
 		brewfile.TypeTap,
@@ -230,22 +586,23 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	// Type icons/colors
-	typeInfo := map[brewfile.PackageType]struct {
-		icon  string
-		color lipgloss.Color
-	}{
-		brewfile.TypeTap:         {"🚰", catTeal},
-		brewfile.TypeBrew:        {"🍺", catYellow},
-		brewfile.TypeCask:        {"📦", catPeach},
-		brewfile.TypeVSCode:      {"💻", catBlue},
-		brewfile.TypeCursor:      {"✏️ ", catMauve},
-		brewfile.TypeAntigravity: {"🚀", catPink},
-		brewfile.TypeGo:          {"🔷", catSapphire},
-		brewfile.TypeMas:         {"🍎", catRed},
+	// Type colors
+	typeColors := map[brewfile.PackageType]lipgloss.Color{
+		brewfile.TypeTap:         catTeal,
+		brewfile.TypeBrew:        catYellow,
+		brewfile.TypeCask:        catPeach,
+		brewfile.TypeVSCode:      catBlue,
+		brewfile.TypeCursor:      catMauve,
+		brewfile.TypeAntigravity: catPink,
+		brewfile.TypeGo:          catSapphire,
+		brewfile.TypeNpm:         catMaroon,
+		brewfile.TypePipx:        catGreen,
+		brewfile.TypeMas:         catRed,
 	}
 
 	var allRows []string
@@ -254,19 +611,22 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 	for _, pkgType := range typeOrder {
 		additions := additionsByType[pkgType]
 		removals := removalsByType[pkgType]
+		changed := tapChangesByType[pkgType]
+		versioned := versionChangesByType[pkgType]
+		outdated := outdatedByType[pkgType]
 
 		// Skip if no changes for this type
-		if len(additions) == 0 && len(removals) == 0 {
+		if len(additions) == 0 && len(removals) == 0 && len(changed) == 0 && len(versioned) == 0 && len(outdated) == 0 {
 			continue
 		}
 
-		info := typeInfo[pkgType]
+		color := typeColors[pkgType]
 
 		// Category header (spans both columns)
 		categoryHeader := lipgloss.NewStyle().
-			Foreground(info.color).
+			Foreground(color).
 			Bold(true).
-			Render(fmt.Sprintf("%s %s", info.icon, pkgType))
+			Render(fmt.Sprintf("%s %s", brewfile.Meta(pkgType).Icon, pkgType))
 
 		allRows = append(allRows, categoryHeader)
 
@@ -302,6 +662,12 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 						Italic(true).
 						Render("(ignored)")
 					leftLines = append(leftLines, fmt.Sprintf("  %s %s %s", prefix, pkgName, ignoredTag))
+				} else if unavailableIDs[pkg.ID()] {
+					notFoundTag := lipgloss.NewStyle().
+						Foreground(catRed).
+						Italic(true).
+						Render("(not found)")
+					leftLines = append(leftLines, fmt.Sprintf("  %s %s %s", prefix, pkgName, notFoundTag))
 				} else {
 					leftLines = append(leftLines, fmt.Sprintf("  %s %s", prefix, pkgName))
 				}
@@ -334,12 +700,27 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 						Italic(true).
 						Render("(ignored)")
 					rightLines = append(rightLines, fmt.Sprintf("  %s %s %s", prefix, pkgName, ignoredTag))
+				} else if pinnedIDs[pkg.ID()] {
+					pinnedTag := lipgloss.NewStyle().
+						Foreground(catYellow).
+						Italic(true).
+						Render("(pinned)")
+					rightLines = append(rightLines, fmt.Sprintf("  %s %s %s", prefix, pkgName, pinnedTag))
 				} else {
 					rightLines = append(rightLines, fmt.Sprintf("  %s %s", prefix, pkgName))
 				}
 			}
 		}
 
+		// With --only-adds/--only-removes the other side is empty; render one column
+		if singleColumn {
+			for _, line := range append(leftLines, rightLines...) {
+				allRows = append(allRows, lipgloss.NewStyle().Width(colWidth).Render(line))
+			}
+			allRows = append(allRows, "")
+			continue
+		}
+
 		// Equalize line counts
 		maxLines := len(leftLines)
 		if len(rightLines) > maxLines {
@@ -372,6 +753,63 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 			allRows = append(allRows, row)
 		}
 
+		// Tap changes span both columns below the additions and removals
+		if len(changed) > 0 {
+			allRows = append(allRows, lipgloss.NewStyle().
+				Foreground(catPeach).
+				Bold(true).
+				Render(fmt.Sprintf("🔀 Tap Changed (%d)", len(changed))))
+
+			for _, c := range changed {
+				prefix := lipgloss.NewStyle().
+					Foreground(catPeach).
+					Bold(true).
+					Render("~")
+				detail := lipgloss.NewStyle().
+					Foreground(catOverlay1).
+					Render(c.String())
+				allRows = append(allRows, fmt.Sprintf("  %s %s  %s", prefix, c.Source.BaseName(), detail))
+			}
+		}
+
+		// Version changes of editor extensions likewise
+		if len(versioned) > 0 {
+			allRows = append(allRows, lipgloss.NewStyle().
+				Foreground(catSapphire).
+				Bold(true).
+				Render(fmt.Sprintf("🔖 Version Changed (%d)", len(versioned))))
+
+			for _, c := range versioned {
+				prefix := lipgloss.NewStyle().
+					Foreground(catSapphire).
+					Bold(true).
+					Render("~")
+				detail := lipgloss.NewStyle().
+					Foreground(catOverlay1).
+					Render(c.String())
+				allRows = append(allRows, fmt.Sprintf("  %s %s  %s", prefix, c.Source.Name, detail))
+			}
+		}
+
+		// And packages with an upgrade available here
+		if len(outdated) > 0 {
+			allRows = append(allRows, lipgloss.NewStyle().
+				Foreground(catLavender).
+				Bold(true).
+				Render(fmt.Sprintf("⬆ Outdated (%d)", len(outdated))))
+
+			for _, o := range outdated {
+				prefix := lipgloss.NewStyle().
+					Foreground(catLavender).
+					Bold(true).
+					Render("~")
+				detail := lipgloss.NewStyle().
+					Foreground(catOverlay1).
+					Render(o.String())
+				allRows = append(allRows, fmt.Sprintf("  %s %s  %s", prefix, o.Package.Name, detail))
+			}
+		}
+
 		// Add spacing between categories
 		allRows = append(allRows, "")
 	}
@@ -397,6 +835,23 @@ func outputDiffTable(diff *brewfile.DiffResult, source, current string) error {
 		Foreground(catBlue)
 
 	summaryText := diff.Summary()
+	var changeTexts []string
+	if len(tapChanges) > 0 {
+		changeTexts = append(changeTexts, fmt.Sprintf("%d tap changed", len(tapChanges)))
+	}
+	if len(versionChanges) > 0 {
+		changeTexts = append(changeTexts, fmt.Sprintf("%d version changed", len(versionChanges)))
+	}
+	if len(diff.Outdated) > 0 {
+		changeTexts = append(changeTexts, fmt.Sprintf("%d outdated", len(diff.Outdated)))
+	}
+	if len(changeTexts) > 0 {
+		if diff.IsEmpty() {
+			summaryText = strings.Join(changeTexts, ", ")
+		} else {
+			summaryText += ", " + strings.Join(changeTexts, ", ")
+		}
+	}
 	fmt.Println(summaryBox.Render(summaryText))
 	fmt.Println()
 
@@ -415,22 +870,23 @@ func formatPackagesByType(pkgs brewfile.Packages, prefix string, prefixColor lip
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	// Type icons/colors
-	typeInfo := map[brewfile.PackageType]struct {
-		icon  string
-		color lipgloss.Color
-	}{
-		brewfile.TypeTap:         {"🚰", catTeal},
-		brewfile.TypeBrew:        {"🍺", catYellow},
-		brewfile.TypeCask:        {"📦", catPeach},
-		brewfile.TypeVSCode:      {"💻", catBlue},
-		brewfile.TypeCursor:      {"✏️ ", catMauve},
-		brewfile.TypeAntigravity: {"🚀", catPink},
-		brewfile.TypeGo:          {"🔷", catSapphire},
-		brewfile.TypeMas:         {"🍎", catRed},
+	// Type colors
+	typeColors := map[brewfile.PackageType]lipgloss.Color{
+		brewfile.TypeTap:         catTeal,
+		brewfile.TypeBrew:        catYellow,
+		brewfile.TypeCask:        catPeach,
+		brewfile.TypeVSCode:      catBlue,
+		brewfile.TypeCursor:      catMauve,
+		brewfile.TypeAntigravity: catPink,
+		brewfile.TypeGo:          catSapphire,
+		brewfile.TypeNpm:         catMaroon,
+		brewfile.TypePipx:        catGreen,
+		brewfile.TypeMas:         catRed,
 	}
 
 	for _, t := range typeOrder {
@@ -439,13 +895,13 @@ func formatPackagesByType(pkgs brewfile.Packages, prefix string, prefixColor lip
 			continue
 		}
 
-		info := typeInfo[t]
+		color := typeColors[t]
 
 		// Type header
 		typeHeader := lipgloss.NewStyle().
-			Foreground(info.color).
+			Foreground(color).
 			Bold(true).
-			Render(fmt.Sprintf("%s %s (%d)", info.icon, t, len(typePkgs)))
+			Render(fmt.Sprintf("%s %s (%d)", brewfile.Meta(t).Icon, t, len(typePkgs)))
 
 		lines = append(lines, typeHeader)
 
@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+func TestDiffOutput_OnlyOneSide(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	full := &brewfile.DiffResult{
+		Additions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "ripgrep")},
+		Removals:  brewfile.Packages{brewfile.NewPackage(brewfile.TypeCask, "zoom")},
+	}
+
+	setSides := func(t *testing.T, adds, removes bool) {
+		diffOnlyAdds, diffOnlyRemoves = adds, removes
+		t.Cleanup(func() { diffOnlyAdds, diffOnlyRemoves = false, false })
+	}
+
+	t.Run("only adds", func(t *testing.T) {
+		setSides(t, true, false)
+		diff := focusDiff(full, diffOnlyAdds, diffOnlyRemoves)
+		assert.Equal(t, "1 addition", diff.Summary())
+
+		table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, nil, nil, nil, "air", "mini")) })
+		assert.Contains(t, table, "To Install (1)")
+		assert.Contains(t, table, "ripgrep")
+		assert.NotContains(t, table, "To Remove")
+		assert.NotContains(t, table, "zoom")
+
+		var out map[string]interface{}
+		jsonOut := captureStdout(t, func() { require.NoError(t, outputDiffJSON(diff, nil, nil, brewfile.ArchInfo{}, nil, nil, nil)) })
+		require.NoError(t, json.Unmarshal([]byte(jsonOut), &out))
+		assert.Contains(t, out, "additions")
+		assert.NotContains(t, out, "removals")
+	})
+
+	t.Run("only removes", func(t *testing.T) {
+		setSides(t, false, true)
+		diff := focusDiff(full, diffOnlyAdds, diffOnlyRemoves)
+		assert.Equal(t, "1 removal", diff.Summary())
+
+		table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, nil, nil, nil, "air", "mini")) })
+		assert.Contains(t, table, "To Remove (1)")
+		assert.Contains(t, table, "zoom")
+		assert.NotContains(t, table, "To Install")
+		assert.NotContains(t, table, "ripgrep")
+
+		var out map[string]interface{}
+		jsonOut := captureStdout(t, func() { require.NoError(t, outputDiffJSON(diff, nil, nil, brewfile.ArchInfo{}, nil, nil, nil)) })
+		require.NoError(t, json.Unmarshal([]byte(jsonOut), &out))
+		assert.NotContains(t, out, "additions")
+		assert.Contains(t, out, "removals")
+	})
+
+	t.Run("nothing left on the requested side", func(t *testing.T) {
+		setSides(t, true, false)
+		diff := focusDiff(&brewfile.DiffResult{Removals: full.Removals}, diffOnlyAdds, diffOnlyRemoves)
+
+		table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, nil, nil, nil, "air", "mini")) })
+		assert.Contains(t, table, "Nothing to install")
+	})
+}
+
+func TestDiffOutput_TapChanges(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	diff, changes := brewfile.Diff(
+		brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "user/tap/ripgrep")},
+		brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "ripgrep")},
+	).SplitTapChanges()
+	require.True(t, diff.IsEmpty())
+
+	table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, changes, nil, nil, "air", "mini")) })
+	assert.Contains(t, table, "Tap Changed (1)")
+	assert.Contains(t, table, "ripgrep  tap changed: core → user/tap")
+	assert.Contains(t, table, "1 tap changed")
+	assert.NotContains(t, table, "No differences found")
+
+	var out struct {
+		TapChanged []map[string]string `json:"tap_changed"`
+	}
+	jsonOut := captureStdout(t, func() { require.NoError(t, outputDiffJSON(diff, changes, nil, brewfile.ArchInfo{}, nil, nil, nil)) })
+	require.NoError(t, json.Unmarshal([]byte(jsonOut), &out))
+	assert.Equal(t, []map[string]string{{"type": "brew", "name": "ripgrep", "from": "core", "to": "user/tap"}}, out.TapChanged)
+}
+
+func TestDiffOutput_VersionChanges(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	source := brewfile.Packages{{Type: brewfile.TypeVSCode, Name: "golang.go", Version: "0.42.0"}}
+	current := brewfile.Packages{{Type: brewfile.TypeVSCode, Name: "golang.go", Version: "0.41.4"}}
+	diff := brewfile.Diff(source, current)
+	require.True(t, diff.IsEmpty())
+	changes := brewfile.VersionChanges(source, current, nil)
+
+	table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, nil, changes, nil, "air", "mini")) })
+	assert.Contains(t, table, "Version Changed (1)")
+	assert.Contains(t, table, "golang.go  version changed: 0.41.4 → 0.42.0")
+	assert.Contains(t, table, "1 version changed")
+	assert.NotContains(t, table, "No differences found")
+
+	var out struct {
+		VersionChanged []map[string]string `json:"version_changed"`
+	}
+	jsonOut := captureStdout(t, func() { require.NoError(t, outputDiffJSON(diff, nil, changes, brewfile.ArchInfo{}, nil, nil, nil)) })
+	require.NoError(t, json.Unmarshal([]byte(jsonOut), &out))
+	assert.Equal(t, []map[string]string{{"type": "vscode", "name": "golang.go", "from": "0.41.4", "to": "0.42.0"}}, out.VersionChanged)
+}
+
+func TestDiffOutput_Outdated(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1" = outdated ] && echo '{"formulae":[{"name":"git","installed_versions":["2.40.0"],"current_version":"2.41.0"}],"casks":[]}'
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	pkgs := brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git"), brewfile.NewPackage(brewfile.TypeBrew, "jq")}
+	diff := brewfile.Diff(pkgs, pkgs)
+	require.NoError(t, installer.NewBrewInstaller().MarkOutdated(diff))
+	require.Len(t, diff.Outdated, 1)
+
+	table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, nil, nil, nil, "air", "mini")) })
+	assert.Contains(t, table, "Outdated (1)")
+	assert.Contains(t, table, "git  outdated: 2.40.0 → 2.41.0")
+	assert.Contains(t, table, "1 outdated")
+	assert.NotContains(t, table, "No differences found")
+
+	var out struct {
+		Outdated []map[string]string `json:"outdated"`
+	}
+	jsonOut := captureStdout(t, func() { require.NoError(t, outputDiffJSON(diff, nil, nil, brewfile.ArchInfo{}, nil, nil, nil)) })
+	require.NoError(t, json.Unmarshal([]byte(jsonOut), &out))
+	assert.Equal(t, []map[string]string{{"type": "brew", "name": "git", "installed": "2.40.0", "latest": "2.41.0"}}, out.Outdated)
+
+	csvOut := captureStdout(t, func() { require.NoError(t, outputDiffCSV(diff, nil, nil, nil)) })
+	assert.Equal(t, []string{"outdated", "brew", "git", "2.40.0 → 2.41.0"}, readCSV(t, csvOut)[1])
+
+	// The key is present even when nothing is outdated
+	diff.Outdated = nil
+	jsonOut = captureStdout(t, func() { require.NoError(t, outputDiffJSON(diff, nil, nil, brewfile.ArchInfo{}, nil, nil, nil)) })
+	assert.Contains(t, jsonOut, `"outdated": []`)
+}
+
+func TestDiffOutput_Unavailable(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+	diffCheckAvail = true
+	t.Cleanup(func() { diffCheckAvail = false })
+
+	bogus := brewfile.NewPackage(brewfile.TypeBrew, "bogus-formula")
+	diff := &brewfile.DiffResult{Additions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "ripgrep"), bogus}}
+	unavailable := brewfile.Packages{bogus}
+
+	table := captureStdout(t, func() { require.NoError(t, outputDiffTable(diff, nil, nil, unavailable, "air", "mini")) })
+	assert.Contains(t, table, "bogus-formula (not found)")
+	assert.NotContains(t, table, "ripgrep (not found")
+
+	var out struct {
+		Additions   map[string][]string `json:"additions"`
+		Unavailable map[string][]string `json:"unavailable"`
+	}
+	jsonOut := captureStdout(t, func() {
+		require.NoError(t, outputDiffJSON(diff, nil, nil, brewfile.ArchInfo{}, nil, nil, unavailable))
+	})
+	require.NoError(t, json.Unmarshal([]byte(jsonOut), &out))
+	assert.Equal(t, []string{"bogus-formula"}, out.Unavailable["brew"])
+	assert.Equal(t, []string{"ripgrep", "bogus-formula"}, out.Additions["brew"])
+
+	csvOut := captureStdout(t, func() { require.NoError(t, outputDiffCSV(diff, nil, nil, unavailable)) })
+	assert.Equal(t, [][]string{
+		{"change", "type", "name", "detail"},
+		{"add", "brew", "ripgrep", ""},
+		{"add", "brew", "bogus-formula", "not found"},
+	}, readCSV(t, csvOut))
+}
+
+func TestDiffIgnoreAll(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignore.yaml"), []byte(`
+global:
+  categories: [mas]
+machines:
+  mini:
+    packages:
+      brew: [htop]
+`), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	diff := &brewfile.DiffResult{
+		Additions: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeBrew, "ripgrep"),
+			brewfile.NewPackage(brewfile.TypeBrew, "htop"),
+			brewfile.NewPackage(brewfile.TypeMas, "Xcode"),
+			brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		},
+		Removals: brewfile.Packages{brewfile.NewPackage(brewfile.TypeCask, "zoom")},
+	}
+
+	// Already-ignored packages and categories are skipped
+	toIgnore := notIgnored(cfg, "mini", diff.Additions)
+	assert.Equal(t, []string{"brew:ripgrep", "cask:firefox"}, toIgnore.IDs())
+	require.NoError(t, ignorePackages("mini", toIgnore))
+
+	ignoreFile, err := config.LoadIgnoreFile()
+	require.NoError(t, err)
+	mini := ignoreFile.Machines["mini"].Packages
+	assert.Equal(t, []string{"htop", "ripgrep"}, mini.Brew.Names())
+	assert.Equal(t, []string{"firefox"}, mini.Cask.Names())
+	assert.Empty(t, ignoreFile.Global.Packages.Brew)
+}
+
+func TestDiffIgnoreAll_JSONStdout(t *testing.T) {
+	writeFleet(t, map[string]string{
+		"mini":   "brew \"git\"\n",
+		"air":    "brew \"git\"\nbrew \"jq\"\n",
+		"studio": "brew \"git\"\n",
+	})
+	t.Cleanup(func() {
+		diffFrom, diffTo, diffFormat, diffIgnoreAllAdditions, assumeYes, noPager = "", "", "table", false, false, false
+	})
+	diffFrom, diffTo, diffFormat, diffIgnoreAllAdditions, assumeYes, noPager = "air", "studio", "json", true, true, true
+
+	// Only the diff reaches stdout, the ignore summary goes to stderr
+	out := captureStdout(t, func() { require.NoError(t, runDiff(diffCmd, nil)) })
+	assert.True(t, json.Valid([]byte(out)), out)
+	assert.NotContains(t, out, "Ignored")
+	assert.False(t, jsonResult)
+}
+
+func TestDiffIgnoreScope(t *testing.T) {
+	dir := t.TempDir()
+	brewfiles := map[string]string{
+		"mini":   "brew \"git\"\n",
+		"air":    "brew \"git\"\nbrew \"jq\"\nbrew \"ripgrep\"\n",
+		"studio": "brew \"git\"\nbrew \"htop\"\n",
+	}
+	cfg := "current_machine: mini\nmachines:\n"
+	for name, content := range brewfiles {
+		path := filepath.Join(dir, "Brewfile."+name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		cfg += "  " + name + ":\n    brewfile: " + path + "\n"
+	}
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(cfg), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignore.yaml"), []byte(`
+machines:
+  air:
+    packages:
+      brew: [htop]
+  studio:
+    packages:
+      brew: [jq]
+`), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() {
+		config.SetConfigPath("")
+		diffFrom, diffTo, diffIgnoreScope, diffSince, noPager = "", "", ignoreScopeTo, "", false
+	})
+	diffFrom, diffTo, noPager = "air", "studio", true
+
+	tests := []struct {
+		scope   string
+		ignored []string
+	}{
+		{scope: ignoreScopeTo, ignored: []string{"jq"}},
+		{scope: ignoreScopeFrom, ignored: []string{"htop"}},
+		{scope: ignoreScopeNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			diffIgnoreScope = tt.scope
+			out := captureStdout(t, func() { require.NoError(t, runDiff(diffCmd, nil)) })
+			assert.Contains(t, out, "air → studio")
+			for _, name := range []string{"jq", "ripgrep", "htop"} {
+				if slices.Contains(tt.ignored, name) {
+					assert.Contains(t, out, name+" (ignored)")
+				} else {
+					assert.Contains(t, out, name)
+					assert.NotContains(t, out, name+" (ignored)")
+				}
+			}
+		})
+	}
+
+	t.Run("unknown scope", func(t *testing.T) {
+		diffIgnoreScope = "everyone"
+		assert.ErrorContains(t, runDiff(diffCmd, nil), "unknown --ignore-scope")
+	})
+
+	t.Run("installed state needs this machine", func(t *testing.T) {
+		diffIgnoreScope, diffSince = ignoreScopeTo, "7d"
+		assert.ErrorContains(t, runDiff(diffCmd, nil), "--to studio")
+	})
+}
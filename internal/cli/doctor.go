@@ -3,13 +3,19 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 
+	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/exec"
+	"github.com/asamgx/brewsync/internal/installer"
 	"github.com/asamgx/brewsync/pkg/version"
 )
 
@@ -23,12 +29,30 @@ Validates:
   - Config file exists and is valid
   - Ignore file exists
   - Current machine is detected
+  - Machine hostnames are unique
   - Brewfile paths exist
-  - Required CLI tools are available (brew, code, cursor, antigravity, mas, go)`,
+  - Editor extensions that moved publishers are aliased
+  - Casks in the current Brewfile support this Mac's architecture
+  - Formulae and casks in the current Brewfile aren't deprecated or disabled (--online)
+  - Required CLI tools are available (brew, code, cursor, antigravity, mas, go)
+
+The cask architecture check goes by cask names (e.g. "foo-arm64") unless
+--online is given, which asks 'brew info' for each cask's arch requirement
+(brew may download its cask index to answer). --online also looks up which
+formulae and casks Homebrew has deprecated or disabled; results are cached
+for a day. With --fix, doctor then offers to replace each one with Homebrew's
+suggested replacement, or to remove it from the Brewfile (--yes accepts all).`,
 	RunE: runDoctor,
 }
 
+var (
+	doctorOnline bool
+	doctorFix    bool
+)
+
 func init() {
+	doctorCmd.Flags().BoolVar(&doctorOnline, "online", false, "look up cask architectures and deprecations with 'brew info' (may use the network)")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "offer to replace or remove deprecated and disabled packages (with --online)")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -62,9 +86,15 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Check config schema
+	results = append(results, checkConfigSchema(cfg))
+
 	// Check current machine
 	results = append(results, checkCurrentMachine(cfg))
 
+	// Check machine hostnames
+	results = append(results, checkHostnames(cfg))
+
 	// Check Brewfile paths
 	results = append(results, checkBrewfilePaths(cfg)...)
 
@@ -73,10 +103,31 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		results = append(results, checkDefaultSource(cfg))
 	}
 
+	// Check for extensions that moved publishers
+	results = append(results, checkExtensionMoves(cfg)...)
+
+	// Check casks built for another architecture
+	results = append(results, checkCaskArch(cfg, doctorOnline)...)
+
+	// Check for deprecated and disabled formulae and casks
+	deprecationChecks, deprecated := checkDeprecated(cfg, doctorOnline)
+	results = append(results, deprecationChecks...)
+
 	// Check CLI tools
 	results = append(results, checkCLITools()...)
 
 	printResults(results)
+
+	if doctorFix && len(deprecated) > 0 {
+		machine, _ := cfg.GetCurrentMachine()
+		changed, err := fixDeprecated(cfg, machine.Brewfile, deprecated, confirmDeprecationFix)
+		if err != nil {
+			return err
+		}
+		if changed > 0 {
+			printInfo("Updated %d package(s) in %s", changed, machine.Brewfile)
+		}
+	}
 	return nil
 }
 
@@ -150,6 +201,30 @@ func checkCurrentMachine(cfg *config.Config) checkResult {
 	}
 }
 
+// checkHostnames reports hostnames shared by several machines, which make
+// current_machine: auto ambiguous
+func checkHostnames(cfg *config.Config) checkResult {
+	duplicates := config.DuplicateHostnames(cfg.Machines)
+	if len(duplicates) == 0 {
+		return checkResult{
+			name:    "Machine hostnames",
+			ok:      true,
+			message: "Unique",
+		}
+	}
+
+	var shared []string
+	for hostname, names := range duplicates {
+		shared = append(shared, fmt.Sprintf("%q (%s)", hostname, strings.Join(names, ", ")))
+	}
+	sort.Strings(shared)
+	return checkResult{
+		name:    "Machine hostnames",
+		ok:      false,
+		message: fmt.Sprintf("Shared by several machines: %s; auto-detection picks the first alphabetically", strings.Join(shared, "; ")),
+	}
+}
+
 func checkBrewfilePaths(cfg *config.Config) []checkResult {
 	var results []checkResult
 
@@ -190,6 +265,259 @@ func checkBrewfilePaths(cfg *config.Config) []checkResult {
 	return results
 }
 
+// checkConfigSchema reports a config that was upgraded from an older schema in memory
+func checkConfigSchema(cfg *config.Config) checkResult {
+	if changes := cfg.Migrations(); len(changes) > 0 {
+		return checkResult{
+			name:    "Config schema",
+			ok:      false,
+			message: fmt.Sprintf("Older config upgraded in memory (%s); run 'brewsync config migrate' to save it", strings.Join(changes, "; ")),
+		}
+	}
+	return checkResult{
+		name:    "Config schema",
+		ok:      true,
+		message: fmt.Sprintf("Version %d", cfg.SchemaVersion),
+	}
+}
+
+// checkCaskArch reports casks in the current Brewfile that can't be installed
+// on this Mac's architecture. With online, brew info supplies the arch
+// requirements; otherwise only names like "foo-intel" are recognized.
+func checkCaskArch(cfg *config.Config, online bool) []checkResult {
+	machine, ok := cfg.GetCurrentMachine()
+	if !ok {
+		return nil
+	}
+	pkgs, err := brewfile.Parse(machine.Brewfile)
+	if err != nil {
+		return nil
+	}
+	casks := pkgs.Filter(brewfile.TypeCask)
+
+	var supported map[string][]string
+	if online && len(casks) > 0 {
+		brew := installer.NewBrewInstaller()
+		if brew.IsAvailable() {
+			if supported, err = brew.CaskArches(casks.Names()); err != nil {
+				return []checkResult{{
+					name:    "Cask architecture",
+					ok:      false,
+					message: fmt.Sprintf("Could not query cask architectures: %v", err),
+				}}
+			}
+		}
+	}
+
+	return caskArchResults(casks, runtime.GOARCH, supported)
+}
+
+// caskArchResults checks casks against arch. supported maps cask names to the
+// architectures brew limits them to; casks it doesn't list fall back to
+// brewfile.ArchFromName.
+func caskArchResults(casks brewfile.Packages, arch string, supported map[string][]string) []checkResult {
+	var results []checkResult
+	for _, cask := range casks {
+		arches, ok := supported[cask.Name]
+		if !ok {
+			if nameArch := brewfile.ArchFromName(cask); nameArch != "" {
+				arches = []string{nameArch}
+			}
+		}
+		if len(arches) == 0 || slices.Contains(arches, arch) {
+			continue
+		}
+		results = append(results, checkResult{
+			name: fmt.Sprintf("Cask (%s)", cask.Name),
+			ok:   false,
+			message: fmt.Sprintf("Only for %s, but this Mac is %s; remove it from the Brewfile or run 'brewsync ignore add %s'",
+				strings.Join(arches, "/"), arch, cask.ID()),
+		})
+	}
+
+	if len(results) == 0 {
+		return []checkResult{{
+			name:    "Cask architecture",
+			ok:      true,
+			message: fmt.Sprintf("%d cask(s) support %s", len(casks), arch),
+		}}
+	}
+	return results
+}
+
+// checkDeprecated reports formulae and casks in the current Brewfile that
+// Homebrew has deprecated or disabled. It needs brew info, so it only runs
+// with online. The flagged packages are returned for --fix.
+func checkDeprecated(cfg *config.Config, online bool) ([]checkResult, map[string]installer.Deprecation) {
+	if !online {
+		return nil, nil
+	}
+	machine, ok := cfg.GetCurrentMachine()
+	if !ok {
+		return nil, nil
+	}
+	pkgs, err := brewfile.Parse(machine.Brewfile)
+	if err != nil {
+		return nil, nil
+	}
+	pkgs = pkgs.Filter(brewfile.TypeBrew, brewfile.TypeCask)
+
+	brew := installer.NewBrewInstaller()
+	if len(pkgs) == 0 || !brew.IsAvailable() {
+		return nil, nil
+	}
+	found, err := brew.Deprecations(pkgs, filepath.Join(config.CacheDir(), installer.DeprecationCacheFile))
+	if err != nil {
+		return []checkResult{{
+			name:    "Deprecated packages",
+			ok:      false,
+			message: fmt.Sprintf("Could not query deprecations: %v", err),
+		}}, nil
+	}
+	return deprecationResults(pkgs, found), found
+}
+
+// deprecationResults describes each deprecated or disabled package in pkgs,
+// or returns a single passing check when there are none
+func deprecationResults(pkgs brewfile.Packages, found map[string]installer.Deprecation) []checkResult {
+	var results []checkResult
+	for _, pkg := range pkgs {
+		d, ok := found[pkg.ID()]
+		if !ok {
+			continue
+		}
+		message := "Homebrew has " + d.Status() + " it"
+		if d.Date != "" {
+			message += " (" + d.Date + ")"
+		}
+		if d.Reason != "" {
+			message += ": " + strings.ReplaceAll(d.Reason, "_", " ")
+		}
+		if d.Replacement != "" {
+			message += fmt.Sprintf("; use %s:%s instead", d.ReplacementType, d.Replacement)
+		}
+		label := "Deprecated"
+		if d.Disabled {
+			label = "Disabled"
+		}
+		results = append(results, checkResult{
+			name:    fmt.Sprintf("%s (%s)", label, pkg.Name),
+			ok:      false,
+			message: message,
+		})
+	}
+
+	if len(results) == 0 {
+		return []checkResult{{
+			name:    "Deprecated packages",
+			ok:      true,
+			message: fmt.Sprintf("None of %d formula(e)/cask(s) are deprecated", len(pkgs)),
+		}}
+	}
+	return results
+}
+
+// fixDeprecated rewrites the Brewfile at path, replacing each deprecated or
+// disabled package that has a suggested replacement and removing the others,
+// as far as confirm agrees. It writes the Brewfile as dump does and, unless
+// it was modified after the last dump, records the write in the dump metadata
+// so the next dump doesn't take the fix for a hand edit. A Brewfile modified
+// after the last dump is only rewritten if confirm agrees to that too. It
+// returns how many packages were changed.
+func fixDeprecated(cfg *config.Config, path string, found map[string]installer.Deprecation, confirm func(question string) bool) (int, error) {
+	pkgs, err := brewfile.Parse(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse Brewfile: %w", err)
+	}
+
+	var kept, replacements brewfile.Packages
+	changed := 0
+	for _, pkg := range pkgs {
+		d, ok := found[pkg.ID()]
+		if !ok {
+			kept = append(kept, pkg)
+			continue
+		}
+		if d.Replacement != "" {
+			replacement := brewfile.NewPackage(d.ReplacementType, d.Replacement)
+			if confirm(fmt.Sprintf("Replace %s (%s) with %s?", pkg.ID(), d.Status(), replacement.ID())) {
+				replacements = append(replacements, replacement)
+				changed++
+				continue
+			}
+		} else if confirm(fmt.Sprintf("Remove %s (%s) from the Brewfile?", pkg.ID(), d.Status())) {
+			changed++
+			continue
+		}
+		kept = append(kept, pkg)
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+	edited, err := brewfile.EditedSinceDump(path)
+	if err != nil {
+		printVerbose("Could not check %s for manual edits: %v", path, err)
+	}
+	if edited && !confirm(fmt.Sprintf("%s was modified after the last dump; rewrite it anyway?", path)) {
+		return 0, nil
+	}
+	recordDump := err == nil && !edited
+
+	fixed := kept.AddUnique(replacements...)
+	if err := newDumpWriter(cfg, fixed).Write(path); err != nil {
+		return 0, fmt.Errorf("failed to write Brewfile: %w", err)
+	}
+	if recordDump {
+		if err := brewfile.UpdateMetadata(brewfile.MetadataPath(path), cfg.CurrentMachine, fixed, version.Version); err != nil {
+			printWarning("Failed to update metadata: %v", err)
+		}
+	}
+	return changed, nil
+}
+
+// confirmDeprecationFix asks a yes/no question for --fix (--yes answers yes)
+func confirmDeprecationFix(question string) bool {
+	if assumeYes {
+		return true
+	}
+	fmt.Printf("%s [y/N] ", question)
+	var response string
+	fmt.Scanln(&response)
+	return response == "y" || response == "Y"
+}
+
+// checkExtensionMoves hints at editor extensions known to have moved publishers
+// that aren't covered by extension_aliases (they would show as add/remove pairs in diffs)
+func checkExtensionMoves(cfg *config.Config) []checkResult {
+	var results []checkResult
+	seen := make(map[string]bool)
+
+	for name, machine := range cfg.Machines {
+		pkgs, err := brewfile.Parse(machine.Brewfile)
+		if err != nil {
+			continue
+		}
+		for _, pkg := range pkgs {
+			if !pkg.IsEditorExtension() {
+				continue
+			}
+			newID, moved := brewfile.KnownExtensionMoves[strings.ToLower(pkg.Name)]
+			if !moved || seen[pkg.ID()] || pkg.Key(cfg.ExtensionAliases) != pkg.ID() {
+				continue
+			}
+			seen[pkg.ID()] = true
+			results = append(results, checkResult{
+				name:    fmt.Sprintf("Extension (%s)", pkg.Name),
+				ok:      false,
+				message: fmt.Sprintf("Moved to %s (found on %s); add 'extension_aliases: {%s: %s}' to config", newID, name, pkg.Name, newID),
+			})
+		}
+	}
+
+	return results
+}
+
 func checkDefaultSource(cfg *config.Config) checkResult {
 	if _, ok := cfg.Machines[cfg.DefaultSource]; !ok {
 		return checkResult{
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+func TestCaskArchResults(t *testing.T) {
+	casks := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeCask, "intel-only"),
+		brewfile.NewPackage(brewfile.TypeCask, "user/tap/silicon-app"),
+		brewfile.NewPackage(brewfile.TypeCask, "tool-arm64"),
+	}
+
+	t.Run("names only", func(t *testing.T) {
+		results := caskArchResults(casks, "amd64", nil)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Cask (tool-arm64)", results[0].name)
+		assert.False(t, results[0].ok)
+		assert.Contains(t, results[0].message, "Only for arm64, but this Mac is amd64")
+		assert.Contains(t, results[0].message, "brewsync ignore add cask:tool-arm64")
+	})
+
+	t.Run("brew info", func(t *testing.T) {
+		supported, err := installer.ParseCaskArches([]byte(`{"casks": [
+			{"token": "intel-only", "full_token": "intel-only", "depends_on": {"arch": [{"type": "intel", "bits": 64}]}},
+			{"token": "silicon-app", "full_token": "user/tap/silicon-app", "depends_on": {"arch": [{"type": "arm", "bits": 64}]}}
+		]}`))
+		require.NoError(t, err)
+
+		var names []string
+		for _, r := range caskArchResults(casks, "arm64", supported) {
+			names = append(names, r.name)
+		}
+		assert.Equal(t, []string{"Cask (intel-only)"}, names)
+
+		names = nil
+		for _, r := range caskArchResults(casks, "amd64", supported) {
+			names = append(names, r.name)
+		}
+		assert.Equal(t, []string{"Cask (user/tap/silicon-app)", "Cask (tool-arm64)"}, names)
+	})
+
+	t.Run("all supported", func(t *testing.T) {
+		results := caskArchResults(casks[:1], "arm64", nil)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].ok)
+		assert.Equal(t, "1 cask(s) support arm64", results[0].message)
+	})
+}
+
+func TestDeprecationResults(t *testing.T) {
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "youtube-dl"),
+		brewfile.NewPackage(brewfile.TypeCask, "old-app"),
+	}
+	found := map[string]installer.Deprecation{
+		"brew:youtube-dl": {Date: "2024-10-05", Reason: "repo_archived", Replacement: "yt-dlp", ReplacementType: brewfile.TypeBrew},
+		"cask:old-app":    {Disabled: true, Reason: "discontinued"},
+	}
+
+	results := deprecationResults(pkgs, found)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Deprecated (youtube-dl)", results[0].name)
+	assert.Equal(t, "Homebrew has deprecated it (2024-10-05): repo archived; use brew:yt-dlp instead", results[0].message)
+	assert.Equal(t, "Disabled (old-app)", results[1].name)
+	assert.Equal(t, "Homebrew has disabled it: discontinued", results[1].message)
+
+	results = deprecationResults(pkgs[:1], nil)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].ok)
+}
+
+func TestFixDeprecated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(path, []byte(`brew "git" # version control
+brew "youtube-dl"
+brew "yt-dlp"
+cask "old-app"
+cask "keep-me"
+`), 0644))
+	found := map[string]installer.Deprecation{
+		"brew:youtube-dl": {Replacement: "yt-dlp", ReplacementType: brewfile.TypeBrew},
+		"cask:old-app":    {Disabled: true},
+		"cask:keep-me":    {},
+	}
+
+	cfg := &config.Config{CurrentMachine: "mini"}
+	cfg.Dump.PreserveComments = true
+
+	var asked []string
+	changed, err := fixDeprecated(cfg, path, found, func(question string) bool {
+		asked = append(asked, question)
+		return !strings.Contains(question, "keep-me")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, changed)
+	assert.Equal(t, []string{
+		"Replace brew:youtube-dl (deprecated) with brew:yt-dlp?",
+		"Remove cask:old-app (disabled) from the Brewfile?",
+		"Remove cask:keep-me (deprecated) from the Brewfile?",
+	}, asked)
+
+	pkgs, err := brewfile.Parse(path)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"brew:git", "brew:yt-dlp", "cask:keep-me"}, pkgs.IDs())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# version control")
+
+	// The fix is recorded like a dump, so the next dump doesn't see hand edits
+	edited, err := brewfile.EditedSinceDump(path)
+	require.NoError(t, err)
+	assert.False(t, edited)
+	meta, err := brewfile.LoadMetadata(brewfile.MetadataPath(path))
+	require.NoError(t, err)
+	assert.Equal(t, "mini", meta.Machine)
+
+	// Declining everything leaves the file alone
+	changed, err = fixDeprecated(cfg, path, found, func(string) bool { return false })
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+}
+
+func TestFixDeprecated_EditedSinceDump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Brewfile")
+	content := "brew \"git\"\n# hand-written note\nbrew \"youtube-dl\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	require.NoError(t, brewfile.SaveMetadata(brewfile.MetadataPath(path), &brewfile.Metadata{LastDump: time.Now().Add(-time.Hour)}))
+	found := map[string]installer.Deprecation{"brew:youtube-dl": {}}
+
+	// Declining to rewrite the hand-edited Brewfile leaves it alone
+	var asked []string
+	changed, err := fixDeprecated(&config.Config{CurrentMachine: "mini"}, path, found, func(question string) bool {
+		asked = append(asked, question)
+		return !strings.Contains(question, "modified after the last dump")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, changed)
+	assert.Equal(t, []string{
+		"Remove brew:youtube-dl (deprecated) from the Brewfile?",
+		path + " was modified after the last dump; rewrite it anyway?",
+	}, asked)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,14 +15,34 @@ import (
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
-	"github.com/asamgx/brewsync/internal/exec"
+	"github.com/asamgx/brewsync/internal/dump"
+	"github.com/asamgx/brewsync/internal/git"
+	"github.com/asamgx/brewsync/internal/hooks"
 	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/pkg/version"
 )
 
 var (
-	dumpCommit  bool
-	dumpPush    bool
-	dumpMessage string
+	dumpCommit   bool
+	dumpPush     bool
+	dumpMessage  string
+	dumpStdin    bool
+	dumpBrewOnly bool
+	dumpAppend   bool
+	dumpForce    bool
+
+	dumpPreserveComments bool
+
+	// dumpInput is where --stdin reads the brew bundle dump from
+	dumpInput io.Reader = os.Stdin
+
+	// dumpConfirmInput answers the wrong-machine and hand-edit confirmations;
+	// dumpInteractive reports whether it can be prompted. Both are replaced in tests.
+	dumpConfirmInput io.Reader = os.Stdin
+	dumpInteractive            = stdinIsTerminal
+
+	// localHostname reports this Mac's hostname; replaced in tests
+	localHostname = config.GetLocalHostname
 )
 
 var dumpCmd = &cobra.Command{
@@ -34,9 +55,42 @@ to the machine's Brewfile. This includes:
 - Cursor extensions
 - Antigravity extensions
 - Go tools
+- npm global packages
+- pipx apps
 - Mac App Store apps
 
-The Brewfile location is determined from the config for the current machine.`,
+The Brewfile location is determined from the config for the current machine.
+
+With --stdin, the Homebrew taps, formulae and casks are read from an existing
+'brew bundle dump' on stdin instead of running brew. The other installers are
+still collected and merged in, unless --brew-only is given.
+
+With --append, packages already in the Brewfile are kept even if they are no
+longer installed, so dump only ever adds entries. The Brewfile then no longer
+mirrors this machine exactly: removals have to be made by hand (or with a
+normal dump).
+
+If the machine has a hostname configured and it doesn't match this Mac (for
+example because MACHINE or current_machine points elsewhere), dump asks before
+overwriting that machine's Brewfile. Pass --force or --yes to dump anyway, which
+is required when not running interactively.
+
+If an installer fails to list its packages (mas not signed in, an editor CLI
+crashing), the Brewfile entries of that category are kept rather than removed,
+with a warning. Pass --force to write the category as collected.
+
+Dump normally rewrites the Brewfile in its own layout: packages sorted by name
+within each type, each under its description. With --preserve-comments (or
+dump.preserve_comments), comments and blank lines you added are kept above the
+packages they precede, and packages keep their order; new ones are added at the
+end of their type.
+
+Examples:
+  brewsync dump
+  brewsync dump --append
+  brewsync dump --preserve-comments
+  brew bundle dump --file=- --describe | brewsync dump --stdin
+  brew bundle dump --file=- | brewsync dump --stdin --brew-only`,
 	RunE: runDump,
 }
 
@@ -44,16 +98,23 @@ func init() {
 	dumpCmd.Flags().BoolVar(&dumpCommit, "commit", false, "commit changes after dump")
 	dumpCmd.Flags().BoolVar(&dumpPush, "push", false, "commit and push changes")
 	dumpCmd.Flags().StringVarP(&dumpMessage, "message", "m", "", "custom commit message")
+	dumpCmd.Flags().BoolVar(&dumpStdin, "stdin", false, "read Homebrew packages from a 'brew bundle dump' on stdin instead of running brew")
+	dumpCmd.Flags().BoolVar(&dumpBrewOnly, "brew-only", false, "with --stdin, skip collecting non-Homebrew packages")
+	dumpCmd.Flags().BoolVar(&dumpAppend, "append", false, "keep Brewfile entries that are no longer installed (only add packages)")
+	dumpCmd.Flags().BoolVar(&dumpPreserveComments, "preserve-comments", false, "keep the Brewfile's hand-written comments, blank lines and order (or dump.preserve_comments)")
+	dumpCmd.Flags().BoolVar(&dumpForce, "force", false, "dump even if this Mac's hostname doesn't match the machine's, or an installer failed to list its packages")
 }
 
 // dumpModel is the Bubble Tea model for the dump progress UI
 type dumpModel struct {
 	spinner        spinner.Model
 	step           string
+	output         string // latest line from the running command
 	completed      []string
 	done           bool
 	err            error
 	packages       brewfile.Packages
+	failures       dump.Failures
 	packagesByType map[brewfile.PackageType]int
 }
 
@@ -63,8 +124,14 @@ type dumpStepMsg struct {
 	countInfo string
 }
 
+// dumpOutputMsg carries a line of live output from brew bundle dump
+type dumpOutputMsg struct {
+	line string
+}
+
 type dumpCompleteMsg struct {
 	packages brewfile.Packages
+	failures dump.Failures
 }
 
 type dumpErrorMsg struct {
@@ -96,6 +163,7 @@ func (m dumpModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case dumpStepMsg:
 		m.step = msg.step
+		m.output = ""
 		if msg.packages != nil {
 			m.packages = append(m.packages, msg.packages...)
 		}
@@ -104,9 +172,16 @@ func (m dumpModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case dumpOutputMsg:
+		if line := strings.TrimSpace(msg.line); line != "" {
+			m.output = line
+		}
+		return m, nil
+
 	case dumpCompleteMsg:
 		m.done = true
 		m.packages = msg.packages
+		m.failures = msg.failures
 		return m, tea.Quit
 
 	case dumpErrorMsg:
@@ -137,6 +212,9 @@ func (m dumpModel) View() string {
 	// Show current step with spinner
 	if m.step != "" {
 		s.WriteString(fmt.Sprintf("%s %s\n", m.spinner.View(), m.step))
+		if m.output != "" {
+			s.WriteString(styleDim.Render("  "+m.output) + "\n")
+		}
 	}
 
 	// Show completed steps
@@ -165,23 +243,225 @@ func runDump(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no Brewfile path configured for machine %s", cfg.CurrentMachine)
 	}
 
+	// Without brew the dump would replace the Brewfile with an empty one
+	if !dumpStdin {
+		if err := installer.NewBrewInstaller().CheckAvailable(brewfile.EnabledTypes(cfg.CategoryEnabled)...); err != nil {
+			return fmt.Errorf("%w; %s was left unchanged", err, brewfilePath)
+		}
+	}
+
+	// Don't overwrite another machine's Brewfile by mistake
+	if !dryRun {
+		proceed, err := confirmDumpMachine(cfg.CurrentMachine, machine)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			printInfo("Dump cancelled")
+			return nil
+		}
+
+		// --append keeps what is in the Brewfile, so only a full dump loses hand edits
+		if !dumpAppend {
+			if proceed, err = confirmHandEdits(cfg, brewfilePath); err != nil {
+				return err
+			}
+			if !proceed {
+				printInfo("Dump cancelled")
+				return nil
+			}
+		}
+	}
+
 	// Ensure directory exists
 	dir := filepath.Dir(brewfilePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	// If quiet mode, run without animation
-	if quiet {
-		return runDumpQuiet(cfg, machine, brewfilePath)
+	if dryRun {
+		printHookPlan(cfg, brewfilePath, hooks.PreDump, hooks.PostDump)
+	} else if err := runHooks(cfg, brewfilePath, hooks.PreDump); err != nil {
+		return fmt.Errorf("%w; dump aborted and %s left unchanged", err, brewfilePath)
+	}
+
+	// Read Homebrew packages from stdin, or run without animation in quiet
+	// mode and when stdout carries a JSON result
+	if cmd != nil && dumpStdin {
+		err = runDumpStdin(cfg, dumpInput, brewfilePath, dumpBrewOnly)
+	} else if quiet || jsonResult {
+		err = runDumpQuiet(cfg, machine, brewfilePath)
+	} else {
+		// Run with animation
+		err = runDumpAnimated(cfg, machine, brewfilePath)
+	}
+	if err == nil && !dryRun {
+		runPostHooks(cfg, brewfilePath, hooks.PostDump)
+	}
+
+	// Auto-dumps (cmd == nil) are covered by the notification of the command that triggered them
+	if cmd != nil && !dryRun {
+		if err != nil {
+			notifyFinished(cfg, fmt.Sprintf("Dump failed: %v", err))
+		} else {
+			notifyFinished(cfg, fmt.Sprintf("Dump complete: wrote Brewfile for %s", cfg.CurrentMachine))
+		}
+	}
+
+	return err
+}
+
+// autoDumpsAfterApply reports whether sync and import dump the Brewfile after
+// changing packages: --auto-dump, or auto_dump.enabled with after_install
+func autoDumpsAfterApply(cfg *config.Config) bool {
+	return autoDump || (cfg.AutoDump.Enabled && cfg.AutoDump.AfterInstall)
+}
+
+// autoDumpAfterApply dumps the Brewfile after sync or import changed the
+// installed packages, when --auto-dump is given or auto_dump.enabled and
+// auto_dump.after_install are set. Committing and pushing follow the
+// auto_dump settings. A failed dump is only a warning: the changes were applied.
+func autoDumpAfterApply(cfg *config.Config, machine string) {
+	if !autoDumpsAfterApply(cfg) {
+		return
+	}
+	printInfo("Auto-dumping Brewfile...")
+
+	// Set flags for commit/push based on config
+	oldCommit, oldPush, oldMessage := dumpCommit, dumpPush, dumpMessage
+	defer func() { dumpCommit, dumpPush, dumpMessage = oldCommit, oldPush, oldMessage }()
+
+	dumpCommit = cfg.AutoDump.Commit
+	dumpPush = cfg.AutoDump.Push
+	dumpMessage = config.CommitMessage(cfg.AutoDump.CommitMessage, machine)
+
+	if err := runDump(nil, []string{}); err != nil {
+		printWarning("Auto-dump failed: %v", err)
+	}
+}
+
+// confirmDumpMachine checks that this Mac is the machine being dumped. When
+// the machine's configured hostname differs from the live one it asks before
+// continuing, or fails when there is no terminal to ask on; --force and --yes
+// skip the question. Machines without a hostname are not checked.
+func confirmDumpMachine(name string, machine config.Machine) (bool, error) {
+	if machine.Hostname == "" {
+		return true, nil
+	}
+	live, err := localHostname()
+	if err != nil || live == "" || live == machine.Hostname {
+		return true, nil
+	}
+
+	mismatch := fmt.Sprintf("machine '%s' is configured for hostname %q, but this Mac is %q", name, machine.Hostname, live)
+	if dumpForce || assumeYes {
+		printWarning("Dumping anyway: %s", mismatch)
+		return true, nil
+	}
+	if dumpStdin || !dumpInteractive() {
+		return false, fmt.Errorf("refusing to overwrite %s: %s; pass --force to dump anyway", machine.Brewfile, mismatch)
+	}
+
+	fmt.Printf("⚠ %s.\nOverwrite %s with this Mac's packages? [y/N] ", mismatch, machine.Brewfile)
+	var response string
+	fmt.Fscanln(dumpConfirmInput, &response)
+	return response == "y" || response == "Y", nil
+}
+
+// confirmHandEdits checks whether the Brewfile was changed after the last
+// dump, which the dump would overwrite. It asks before continuing and can show
+// what differs from the installed packages first; without a terminal, or with
+// --force or --yes, it only warns.
+func confirmHandEdits(cfg *config.Config, brewfilePath string) (bool, error) {
+	edited, err := brewfile.EditedSinceDump(brewfilePath)
+	if err != nil {
+		printVerbose("Could not check %s for manual edits: %v", brewfilePath, err)
+		return true, nil
+	}
+	if !edited {
+		return true, nil
+	}
+
+	warning := fmt.Sprintf("%s was modified after the last dump; dumping will overwrite any manual changes", brewfilePath)
+	if dumpForce || assumeYes || dumpStdin || !dumpInteractive() {
+		printWarning("%s", warning)
+		return true, nil
+	}
+
+	fmt.Printf("⚠ %s.\n", warning)
+	for {
+		fmt.Printf("Overwrite it? [y/N/d=show diff] ")
+		var response string
+		fmt.Fscanln(dumpConfirmInput, &response)
+		switch response {
+		case "y", "Y":
+			return true, nil
+		case "d", "D":
+			if err := printHandEdits(os.Stdout, cfg, brewfilePath); err != nil {
+				return false, err
+			}
+		default:
+			return false, nil
+		}
+	}
+}
+
+// printHandEdits shows how the Brewfile differs from the packages a dump
+// would write
+func printHandEdits(w io.Writer, cfg *config.Config, brewfilePath string) error {
+	current, err := brewfile.Parse(brewfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", brewfilePath, err)
+	}
+	live, err := collectDumpPackages(cfg, brewfilePath)
+	if err != nil {
+		return err
+	}
+
+	diff := brewfile.DiffWithAliases(current, live, cfg.ExtensionAliases)
+	if diff.IsEmpty() {
+		fmt.Fprintln(w, "\nThe Brewfile lists exactly the installed packages.")
+		fmt.Fprintln(w)
+		return nil
+	}
+	if len(diff.Additions) > 0 {
+		fmt.Fprintf(w, "\n%s ONLY IN BREWFILE (dropped by the dump: %d)\n", colorRed("▶"), len(diff.Additions))
+		for _, pkg := range diff.Additions {
+			fmt.Fprintf(w, "  - %s\n", pkg.ID())
+		}
 	}
+	if len(diff.Removals) > 0 {
+		fmt.Fprintf(w, "\n%s INSTALLED, NOT IN BREWFILE (added by the dump: %d)\n", colorGreen("▶"), len(diff.Removals))
+		for _, pkg := range diff.Removals {
+			fmt.Fprintf(w, "  + %s\n", pkg.ID())
+		}
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
 
-	// Run with animation
-	return runDumpAnimated(cfg, machine, brewfilePath)
+// collectDumpPackages returns the packages a dump writes to the Brewfile at
+// brewfilePath: those installed, with the entries of categories that failed to
+// list kept and, with --append, the existing entries
+func collectDumpPackages(cfg *config.Config, brewfilePath string) (brewfile.Packages, error) {
+	allPackages, failures, err := collectAllPackages(cfg, brewfilePath)
+	if err != nil {
+		return nil, err
+	}
+	if allPackages, err = keepFailedCategories(brewfilePath, allPackages, failures); err != nil {
+		return nil, err
+	}
+	return appendDumpPackages(brewfilePath, allPackages)
 }
 
 func runDumpQuiet(cfg *config.Config, machine config.Machine, brewfilePath string) error {
-	allPackages, err := collectAllPackages(cfg, brewfilePath)
+	allPackages, err := collectDumpPackages(cfg, brewfilePath)
 	if err != nil {
 		return err
 	}
@@ -193,12 +473,77 @@ func runDumpQuiet(cfg *config.Config, machine config.Machine, brewfilePath strin
 	}
 
 	// Write Brewfile
-	writer := brewfile.NewWriter(allPackages)
+	writer := newDumpWriter(cfg, allPackages)
 	if err := writer.Write(brewfilePath); err != nil {
 		return fmt.Errorf("failed to write Brewfile: %w", err)
 	}
 
+	// Record dump metadata (counts, arch) next to the Brewfile
+	if err := brewfile.UpdateMetadata(brewfile.MetadataPath(brewfilePath), cfg.CurrentMachine, allPackages, version.Version); err != nil {
+		printWarning("Failed to update metadata: %v", err)
+	}
+
 	printInfo("Wrote %d packages to %s", len(allPackages), brewfilePath)
+
+	// Handle commit and push
+	if dumpCommit || dumpPush {
+		if err := handleGitCommitAndPush(cfg, brewfilePath); err != nil {
+			printWarning("Git commit/push failed: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runDumpStdin writes the Brewfile from a 'brew bundle dump' read from r,
+// merged with the non-Homebrew installers unless brewOnly is set
+func runDumpStdin(cfg *config.Config, r io.Reader, brewfilePath string, brewOnly bool) error {
+	pkgs, err := brewfile.ParseReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to parse stdin: %w", err)
+	}
+
+	// Only Homebrew entries come from the bundle dump; everything else is collected below
+	allPackages := pkgs.Filter(brewfile.TypeTap, brewfile.TypeBrew, brewfile.TypeCask).Filter(brewfile.EnabledTypes(cfg.CategoryEnabled)...)
+	if len(allPackages) == 0 {
+		printWarning("No Homebrew packages found on stdin")
+	}
+
+	if !brewOnly {
+		var failures dump.Failures
+		allPackages, failures = dump.CollectExtra(cfg, allPackages)
+		if allPackages, err = keepFailedCategories(brewfilePath, allPackages, failures); err != nil {
+			return err
+		}
+	}
+	if allPackages, err = appendDumpPackages(brewfilePath, allPackages); err != nil {
+		return err
+	}
+
+	if dryRun {
+		printInfo("Dry run - would write %d packages to %s", len(allPackages), brewfilePath)
+		return nil
+	}
+
+	if err := newDumpWriter(cfg, allPackages).Write(brewfilePath); err != nil {
+		return fmt.Errorf("failed to write Brewfile: %w", err)
+	}
+
+	// Record dump metadata (counts, arch) next to the Brewfile
+	if err := brewfile.UpdateMetadata(brewfile.MetadataPath(brewfilePath), cfg.CurrentMachine, allPackages, version.Version); err != nil {
+		printWarning("Failed to update metadata: %v", err)
+	}
+
+	printInfo("Wrote %d packages to %s", len(allPackages), brewfilePath)
+
+	if dumpCommit || dumpPush {
+		if err := handleGitCommitAndPush(cfg, brewfilePath); err != nil {
+			printWarning("Git commit/push failed: %v", err)
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -208,12 +553,12 @@ func runDumpAnimated(cfg *config.Config, machine config.Machine, brewfilePath st
 
 	// Run collection in background
 	go func() {
-		allPackages, err := collectAllPackagesAnimated(cfg, brewfilePath, p)
+		allPackages, failures, err := collectAllPackagesAnimated(cfg, brewfilePath, p)
 		if err != nil {
 			p.Send(dumpErrorMsg{err: err})
 			return
 		}
-		p.Send(dumpCompleteMsg{packages: allPackages})
+		p.Send(dumpCompleteMsg{packages: allPackages, failures: failures})
 	}()
 
 	// Run UI
@@ -228,7 +573,13 @@ func runDumpAnimated(cfg *config.Config, machine config.Machine, brewfilePath st
 		return model.err
 	}
 
-	allPackages := model.packages
+	allPackages, err := keepFailedCategories(brewfilePath, model.packages, model.failures)
+	if err != nil {
+		return err
+	}
+	if allPackages, err = appendDumpPackages(brewfilePath, allPackages); err != nil {
+		return err
+	}
 
 	// Dry run
 	if dryRun {
@@ -237,11 +588,16 @@ func runDumpAnimated(cfg *config.Config, machine config.Machine, brewfilePath st
 	}
 
 	// Write Brewfile
-	writer := brewfile.NewWriter(allPackages)
+	writer := newDumpWriter(cfg, allPackages)
 	if err := writer.Write(brewfilePath); err != nil {
 		return fmt.Errorf("failed to write Brewfile: %w", err)
 	}
 
+	// Record dump metadata (counts, arch) next to the Brewfile
+	if err := brewfile.UpdateMetadata(brewfile.MetadataPath(brewfilePath), cfg.CurrentMachine, allPackages, version.Version); err != nil {
+		printWarning("Failed to update metadata: %v", err)
+	}
+
 	// Print pretty summary
 	printDumpSummary(cfg.CurrentMachine, brewfilePath, allPackages, false)
 
@@ -256,69 +612,80 @@ func runDumpAnimated(cfg *config.Config, machine config.Machine, brewfilePath st
 	return nil
 }
 
-func collectAllPackages(cfg *config.Config, brewfilePath string) (brewfile.Packages, error) {
-	var allPackages brewfile.Packages
-	brewInst := installer.NewBrewInstaller()
-
-	// Use brew bundle dump if configured (default), otherwise collect manually
-	if cfg.Dump.UseBrewBundle && brewInst.IsAvailable() {
-		// Create temp file for brew bundle dump
-		tmpFile := brewfilePath + ".brewbundle.tmp"
-		if err := brewInst.DumpToFile(tmpFile); err == nil {
-			// Parse the brew bundle output (includes taps, formulae, casks with descriptions)
-			if brewPkgs, err := brewfile.Parse(tmpFile); err == nil {
-				allPackages = append(allPackages, brewPkgs...)
-			}
-			os.Remove(tmpFile)
-		}
-	} else if brewInst.IsAvailable() {
-		// Manual collection
-		if taps, err := brewInst.ListTaps(); err == nil {
-			allPackages = append(allPackages, taps...)
-		}
-		if formulae, err := brewInst.ListFormulae(); err == nil {
-			allPackages = append(allPackages, formulae...)
-		}
-		if casks, err := brewInst.ListCasks(); err == nil {
-			allPackages = append(allPackages, casks...)
-		}
+// appendDumpPackages returns live unchanged, or with --append the entries of the
+// existing Brewfile at path followed by the live packages it doesn't list yet
+func appendDumpPackages(path string, live brewfile.Packages) (brewfile.Packages, error) {
+	if !dumpAppend {
+		return live, nil
 	}
-
-	// Collect extensions
-	if vscodeInst := installer.NewVSCodeInstaller(); vscodeInst.IsAvailable() {
-		if extensions, err := vscodeInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return live, nil
 	}
 
-	if cursorInst := installer.NewCursorInstaller(); cursorInst.IsAvailable() {
-		if extensions, err := cursorInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
+	existing, err := brewfile.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse existing Brewfile: %w", err)
 	}
+	merged := existing.AddUnique(live...)
+	printVerbose("Appending %d new packages to %d existing entries", len(merged)-len(existing), len(existing))
+	return merged, nil
+}
 
-	if antigravityInst := installer.NewAntigravityInstaller(); antigravityInst.IsAvailable() {
-		if extensions, err := antigravityInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
+// newDumpWriter returns the Brewfile writer for a dump, with the header if
+// enabled, keeping hand-written comments with --preserve-comments
+func newDumpWriter(cfg *config.Config, packages brewfile.Packages) *brewfile.Writer {
+	writer := brewfile.NewWriter(packages).PreserveComments(dumpPreserveComments || cfg.Dump.PreserveComments)
+	if cfg.Dump.Header {
+		writer.WithHeader(brewfile.Header(cfg.CurrentMachine, time.Now()))
 	}
+	return writer
+}
 
-	if goInst := installer.NewGoToolsInstaller(); goInst.IsAvailable() {
-		if tools, err := goInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(tools...)
-		}
+func collectAllPackages(cfg *config.Config, brewfilePath string) (brewfile.Packages, dump.Failures, error) {
+	var allPackages brewfile.Packages
+	brewInst := installer.NewBrewInstaller()
+
+	// Use brew bundle dump if configured (default), otherwise collect manually
+	brewPkgs, fellBack, err := brewInst.CollectPackages(cfg.UseBrewBundle(cfg.CurrentMachine), brewfilePath+".brewbundle.tmp")
+	if err != nil {
+		return nil, nil, err
+	}
+	if fellBack {
+		printWarning("'brew bundle' is not available; collected Homebrew packages with 'brew list'")
 	}
+	brewPkgs = brewPkgs.Filter(brewfile.EnabledTypes(cfg.CategoryEnabled)...)
+	if installer.NeedsDescriptions(cfg, fellBack) {
+		brewInst.FillDescriptions(brewPkgs, installer.DescriptionCachePath())
+	}
+	allPackages = append(allPackages, brewPkgs...)
 
-	if masInst := installer.NewMasInstaller(); masInst.IsAvailable() {
-		if apps, err := masInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(apps...)
+	allPackages, failures := dump.CollectExtra(cfg, allPackages)
+	return allPackages, failures, nil
+}
+
+// keepFailedCategories keeps the existing Brewfile entries of each category
+// whose installer failed to list its packages (see dump.KeepFailed) and
+// warns about each failure. With --force the failed categories are written
+// as collected, i.e. empty.
+func keepFailedCategories(path string, pkgs brewfile.Packages, failures dump.Failures) (brewfile.Packages, error) {
+	pkgs, failed, err := dump.KeepFailed(path, pkgs, failures, dumpForce)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range failed {
+		switch {
+		case f.Existing == 0:
+			printWarning("Could not list %s packages: %v", f.Type, f.Err)
+		case f.Kept:
+			printWarning("Could not list %s packages: %v; kept its %d existing Brewfile entries (pass --force to remove them)", f.Type, f.Err, f.Existing)
+		default:
+			printWarning("Could not list %s packages: %v; removing its %d Brewfile entries (--force)", f.Type, f.Err, f.Existing)
 		}
 	}
-
-	return allPackages, nil
+	return pkgs, nil
 }
 
-func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.Program) (brewfile.Packages, error) {
+func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.Program) (brewfile.Packages, dump.Failures, error) {
 	var allPackages brewfile.Packages
 	brewInst := installer.NewBrewInstaller()
 
@@ -326,45 +693,39 @@ func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.
 	p.Send(dumpStepMsg{step: "Collecting Homebrew packages..."})
 	time.Sleep(100 * time.Millisecond) // Brief pause for UI update
 
-	if cfg.Dump.UseBrewBundle && brewInst.IsAvailable() {
-		tmpFile := brewfilePath + ".brewbundle.tmp"
-		if err := brewInst.DumpToFile(tmpFile); err == nil {
-			if brewPkgs, err := brewfile.Parse(tmpFile); err == nil {
-				allPackages = append(allPackages, brewPkgs...)
-				byType := brewPkgs.ByType()
-				taps := len(byType[brewfile.TypeTap])
-				formulae := len(byType[brewfile.TypeBrew])
-				casks := len(byType[brewfile.TypeCask])
-				info := fmt.Sprintf("Homebrew: %d packages (taps: %d, formulae: %d, casks: %d)",
-					len(brewPkgs), taps, formulae, casks)
-				p.Send(dumpStepMsg{countInfo: info})
-			}
-			os.Remove(tmpFile)
-		}
-	} else if brewInst.IsAvailable() {
-		var brewCount int
-		if taps, err := brewInst.ListTaps(); err == nil {
-			allPackages = append(allPackages, taps...)
-			brewCount += len(taps)
-		}
-		if formulae, err := brewInst.ListFormulae(); err == nil {
-			allPackages = append(allPackages, formulae...)
-			brewCount += len(formulae)
-		}
-		if casks, err := brewInst.ListCasks(); err == nil {
-			allPackages = append(allPackages, casks...)
-			brewCount += len(casks)
-		}
-		if brewCount > 0 {
-			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("Homebrew: %d packages", brewCount)})
-		}
+	// Stream brew bundle's output so a long dump visibly makes progress
+	brewPkgs, fellBack, err := brewInst.CollectPackagesWithProgress(cfg.UseBrewBundle(cfg.CurrentMachine), brewfilePath+".brewbundle.tmp", func(line string) {
+		p.Send(dumpOutputMsg{line: line})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if fellBack {
+		p.Send(dumpStepMsg{countInfo: "⚠ 'brew bundle' not available; using 'brew list'"})
+	}
+	brewPkgs = brewPkgs.Filter(brewfile.EnabledTypes(cfg.CategoryEnabled)...)
+	if installer.NeedsDescriptions(cfg, fellBack) {
+		p.Send(dumpStepMsg{step: "Looking up package descriptions..."})
+		brewInst.FillDescriptions(brewPkgs, installer.DescriptionCachePath())
 	}
+	if len(brewPkgs) > 0 {
+		allPackages = append(allPackages, brewPkgs...)
+		stats := brewPkgs.Stats()
+		info := fmt.Sprintf("Homebrew: %d packages (taps: %d, formulae: %d, casks: %d)",
+			stats.Total, stats.Count(brewfile.TypeTap), stats.Count(brewfile.TypeBrew), stats.Count(brewfile.TypeCask))
+		p.Send(dumpStepMsg{countInfo: info})
+	}
+
+	failures := make(dump.Failures)
 
 	// VSCode extensions
-	if vscodeInst := installer.NewVSCodeInstaller(); vscodeInst.IsAvailable() {
+	if vscodeInst := installer.NewVSCodeInstaller(); cfg.CategoryEnabled(string(brewfile.TypeVSCode)) && vscodeInst.IsAvailable() {
 		p.Send(dumpStepMsg{step: "Collecting VSCode extensions..."})
 		time.Sleep(100 * time.Millisecond)
-		if extensions, err := vscodeInst.List(); err == nil {
+		if extensions, err := vscodeInst.List(); err != nil {
+			failures[brewfile.TypeVSCode] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ VSCode: %v", err)})
+		} else {
 			beforeCount := len(allPackages)
 			allPackages = allPackages.AddUnique(extensions...)
 			addedCount := len(allPackages) - beforeCount
@@ -373,10 +734,13 @@ func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.
 	}
 
 	// Cursor extensions
-	if cursorInst := installer.NewCursorInstaller(); cursorInst.IsAvailable() {
+	if cursorInst := installer.NewCursorInstaller(); cfg.CategoryEnabled(string(brewfile.TypeCursor)) && cursorInst.IsAvailable() {
 		p.Send(dumpStepMsg{step: "Collecting Cursor extensions..."})
 		time.Sleep(100 * time.Millisecond)
-		if extensions, err := cursorInst.List(); err == nil {
+		if extensions, err := cursorInst.List(); err != nil {
+			failures[brewfile.TypeCursor] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ Cursor: %v", err)})
+		} else {
 			beforeCount := len(allPackages)
 			allPackages = allPackages.AddUnique(extensions...)
 			addedCount := len(allPackages) - beforeCount
@@ -385,10 +749,13 @@ func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.
 	}
 
 	// Antigravity extensions
-	if antigravityInst := installer.NewAntigravityInstaller(); antigravityInst.IsAvailable() {
+	if antigravityInst := installer.NewAntigravityInstaller(); cfg.CategoryEnabled(string(brewfile.TypeAntigravity)) && antigravityInst.IsAvailable() {
 		p.Send(dumpStepMsg{step: "Collecting Antigravity extensions..."})
 		time.Sleep(100 * time.Millisecond)
-		if extensions, err := antigravityInst.List(); err == nil {
+		if extensions, err := antigravityInst.List(); err != nil {
+			failures[brewfile.TypeAntigravity] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ Antigravity: %v", err)})
+		} else {
 			beforeCount := len(allPackages)
 			allPackages = allPackages.AddUnique(extensions...)
 			addedCount := len(allPackages) - beforeCount
@@ -397,10 +764,13 @@ func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.
 	}
 
 	// Go tools
-	if goInst := installer.NewGoToolsInstaller(); goInst.IsAvailable() {
+	if goInst := installer.NewGoToolsInstaller(); cfg.CategoryEnabled(string(brewfile.TypeGo)) && goInst.IsAvailable() {
 		p.Send(dumpStepMsg{step: "Collecting Go tools..."})
 		time.Sleep(100 * time.Millisecond)
-		if tools, err := goInst.List(); err == nil {
+		if tools, err := goInst.List(); err != nil {
+			failures[brewfile.TypeGo] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ Go: %v", err)})
+		} else {
 			beforeCount := len(allPackages)
 			allPackages = allPackages.AddUnique(tools...)
 			addedCount := len(allPackages) - beforeCount
@@ -408,11 +778,44 @@ func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.
 		}
 	}
 
+	// npm global packages
+	if npmInst := installer.NewNpmInstaller(); cfg.CategoryEnabled(string(brewfile.TypeNpm)) && npmInst.IsAvailable() {
+		p.Send(dumpStepMsg{step: "Collecting npm packages..."})
+		time.Sleep(100 * time.Millisecond)
+		if npmPkgs, err := npmInst.List(); err != nil {
+			failures[brewfile.TypeNpm] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ npm: %v", err)})
+		} else {
+			beforeCount := len(allPackages)
+			allPackages = allPackages.AddUnique(npmPkgs...)
+			addedCount := len(allPackages) - beforeCount
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("npm: %d packages (%d new)", len(npmPkgs), addedCount)})
+		}
+	}
+
+	// pipx apps
+	if pipxInst := installer.NewPipxInstaller(); cfg.CategoryEnabled(string(brewfile.TypePipx)) && pipxInst.IsAvailable() {
+		p.Send(dumpStepMsg{step: "Collecting pipx apps..."})
+		time.Sleep(100 * time.Millisecond)
+		if apps, err := pipxInst.List(); err != nil {
+			failures[brewfile.TypePipx] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ pipx: %v", err)})
+		} else {
+			beforeCount := len(allPackages)
+			allPackages = allPackages.AddUnique(apps...)
+			addedCount := len(allPackages) - beforeCount
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("pipx: %d apps (%d new)", len(apps), addedCount)})
+		}
+	}
+
 	// Mac App Store apps
-	if masInst := installer.NewMasInstaller(); masInst.IsAvailable() {
+	if masInst := installer.NewMasInstaller(); cfg.CategoryEnabled(string(brewfile.TypeMas)) && masInst.IsAvailable() {
 		p.Send(dumpStepMsg{step: "Collecting Mac App Store apps..."})
 		time.Sleep(100 * time.Millisecond)
-		if apps, err := masInst.List(); err == nil {
+		if apps, err := masInst.List(); err != nil {
+			failures[brewfile.TypeMas] = err
+			p.Send(dumpStepMsg{countInfo: fmt.Sprintf("⚠ Mac App Store: %v", err)})
+		} else {
 			beforeCount := len(allPackages)
 			allPackages = allPackages.AddUnique(apps...)
 			addedCount := len(allPackages) - beforeCount
@@ -420,7 +823,7 @@ func collectAllPackagesAnimated(cfg *config.Config, brewfilePath string, p *tea.
 		}
 	}
 
-	return allPackages, nil
+	return dump.Versions(cfg, allPackages), failures, nil
 }
 
 func printDumpSummary(machineName, brewfilePath string, packages brewfile.Packages, isDryRun bool) {
@@ -448,7 +851,7 @@ func printDumpSummary(machineName, brewfilePath string, packages brewfile.Packag
 	allLines = append(allLines, separator, "")
 
 	// Package counts by type
-	byType := packages.ByType()
+	stats := packages.Stats()
 	typeOrder := []brewfile.PackageType{
 		brewfile.TypeTap,
 		brewfile.TypeBrew,
@@ -457,29 +860,30 @@ func printDumpSummary(machineName, brewfilePath string, packages brewfile.Packag
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	typeInfo := map[brewfile.PackageType]struct {
-		icon  string
-		color lipgloss.Color
-	}{
-		brewfile.TypeTap:         {"🚰", catTeal},
-		brewfile.TypeBrew:        {"🍺", catYellow},
-		brewfile.TypeCask:        {"📦", catPeach},
-		brewfile.TypeVSCode:      {"💻", catBlue},
-		brewfile.TypeCursor:      {"✏️ ", catMauve},
-		brewfile.TypeAntigravity: {"🚀", catPink},
-		brewfile.TypeGo:          {"🔷", catSapphire},
-		brewfile.TypeMas:         {"🍎", catRed},
+	typeColors := map[brewfile.PackageType]lipgloss.Color{
+		brewfile.TypeTap:         catTeal,
+		brewfile.TypeBrew:        catYellow,
+		brewfile.TypeCask:        catPeach,
+		brewfile.TypeVSCode:      catBlue,
+		brewfile.TypeCursor:      catMauve,
+		brewfile.TypeAntigravity: catPink,
+		brewfile.TypeGo:          catSapphire,
+		brewfile.TypeNpm:         catMaroon,
+		brewfile.TypePipx:        catGreen,
+		brewfile.TypeMas:         catRed,
 	}
 
 	for _, t := range typeOrder {
-		if pkgs, ok := byType[t]; ok && len(pkgs) > 0 {
-			info := typeInfo[t]
-			icon := lipgloss.NewStyle().Foreground(info.color).Render(info.icon)
+		if n := stats.Count(t); n > 0 {
+			color := typeColors[t]
+			icon := lipgloss.NewStyle().Foreground(color).Render(brewfile.Meta(t).Icon)
 			label := lipgloss.NewStyle().Foreground(catText).Bold(true).Render(string(t))
-			count := lipgloss.NewStyle().Foreground(catGreen).Render(fmt.Sprintf("%d", len(pkgs)))
+			count := lipgloss.NewStyle().Foreground(catGreen).Render(fmt.Sprintf("%d", n))
 			allLines = append(allLines, fmt.Sprintf("%s %s: %s", icon, label, count))
 		}
 	}
@@ -503,7 +907,7 @@ func printDumpSummary(machineName, brewfilePath string, packages brewfile.Packag
 	totalText := lipgloss.NewStyle().
 		Foreground(catGreen).
 		Bold(true).
-		Render(fmt.Sprintf("%s Total: %d packages", totalIcon, len(packages)))
+		Render(fmt.Sprintf("%s Total: %d packages", totalIcon, stats.Total))
 	allLines = append(allLines, totalText)
 
 	// Summary box
@@ -519,48 +923,27 @@ func printDumpSummary(machineName, brewfilePath string, packages brewfile.Packag
 }
 
 func handleGitCommitAndPush(cfg *config.Config, brewfilePath string) error {
-	runner := exec.NewRunner()
-	dir := filepath.Dir(brewfilePath)
-
-	// Check if it's a git repo
-	if _, err := runner.Run("git", "-C", dir, "rev-parse", "--git-dir"); err != nil {
-		return fmt.Errorf("not a git repository: %s", dir)
-	}
-
-	// Add the Brewfile
-	fileName := filepath.Base(brewfilePath)
-	if _, err := runner.Run("git", "-C", dir, "add", fileName); err != nil {
-		return fmt.Errorf("failed to git add: %w", err)
+	repo := git.NewRepo(filepath.Dir(brewfilePath))
+	if !repo.IsRepo() {
+		return fmt.Errorf("not a git repository: %s", repo.Dir())
 	}
 
-	// Check if there are changes to commit
-	status, err := runner.Run("git", "-C", dir, "status", "--porcelain", fileName)
+	commitMsg := config.CommitMessage(dumpMessage, cfg.CurrentMachine)
+	committed, err := repo.CommitFile(filepath.Base(brewfilePath), commitMsg)
 	if err != nil {
-		return fmt.Errorf("failed to check git status: %w", err)
+		return err
 	}
-
-	if strings.TrimSpace(status) == "" {
+	if !committed {
 		printInfo("No changes to commit")
 		return nil
 	}
-
-	// Prepare commit message
-	commitMsg := dumpMessage
-	if commitMsg == "" {
-		commitMsg = fmt.Sprintf("brewsync: update %s Brewfile", cfg.CurrentMachine)
-	}
-
-	// Commit
-	if _, err := runner.Run("git", "-C", dir, "commit", "-m", commitMsg); err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
-	}
 	printInfo("✓ Committed changes: %s", commitMsg)
 
 	// Push if requested
 	if dumpPush {
 		printInfo("Pushing to remote...")
-		if _, err := runner.Run("git", "-C", dir, "push"); err != nil {
-			return fmt.Errorf("failed to push: %w", err)
+		if err := repo.Push(); err != nil {
+			return err
 		}
 		printInfo("✓ Pushed to remote")
 	}
@@ -0,0 +1,404 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+func TestRunDumpStdin_BrewOnly(t *testing.T) {
+	stdin := strings.NewReader(`tap "homebrew/bundle"
+# Distributed revision control system
+brew "git"
+cask "firefox"
+vscode "golang.go"
+`)
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	cfg := &config.Config{CurrentMachine: "mini"}
+
+	require.NoError(t, runDumpStdin(cfg, stdin, brewfilePath, true))
+
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, `tap "homebrew/bundle"`)
+	assert.Contains(t, content, "# Distributed revision control system\nbrew \"git\"")
+	assert.Contains(t, content, `cask "firefox"`)
+	// Non-Homebrew entries on stdin are not taken from the bundle dump
+	assert.NotContains(t, content, "vscode")
+
+	meta, err := brewfile.LoadMetadata(brewfile.MetadataPath(brewfilePath))
+	require.NoError(t, err)
+	assert.Equal(t, "mini", meta.Machine)
+}
+
+func TestRunDumpStdin_Header(t *testing.T) {
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	cfg := &config.Config{CurrentMachine: "mini", Dump: config.DumpConfig{Header: true}}
+
+	require.NoError(t, runDumpStdin(cfg, strings.NewReader("brew \"git\"\n"), brewfilePath, true))
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Brewfile for mini\n# Generated by brewsync"))
+
+	// Dumping again doesn't duplicate the header
+	require.NoError(t, runDumpStdin(cfg, strings.NewReader("brew \"git\"\n"), brewfilePath, true))
+	data, err = os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "# Brewfile for mini"))
+	assert.Contains(t, string(data), `brew "git"`)
+}
+
+func TestRunDumpStdin_Append(t *testing.T) {
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"git\"\nbrew \"wget\"\n"), 0644))
+	cfg := &config.Config{CurrentMachine: "mini"}
+
+	dumpAppend = true
+	defer func() { dumpAppend = false }()
+
+	// wget is no longer installed, jq is new
+	require.NoError(t, runDumpStdin(cfg, strings.NewReader("brew \"git\"\nbrew \"jq\"\n"), brewfilePath, true))
+
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.True(t, pkgs.Contains("brew:wget"))
+	assert.True(t, pkgs.Contains("brew:jq"))
+	assert.Len(t, pkgs, 3)
+
+	// A full dump reflects the installed packages exactly
+	dumpAppend = false
+	require.NoError(t, runDumpStdin(cfg, strings.NewReader("brew \"git\"\nbrew \"jq\"\n"), brewfilePath, true))
+	pkgs, err = brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.False(t, pkgs.Contains("brew:wget"))
+}
+
+func TestRunDumpStdin_KeepsCategoryOnListError(t *testing.T) {
+	// mas is installed but can't list apps
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "mas"), []byte("#!/bin/sh\necho 'Error: not signed in' >&2\nexit 1\n"), 0755))
+	t.Setenv("PATH", binDir)
+
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	existing := "brew \"wget\"\nmas \"Xcode\", id: 497799835\nmas \"Keynote\", id: 409183694\n"
+	require.NoError(t, os.WriteFile(brewfilePath, []byte(existing), 0644))
+	cfg := &config.Config{CurrentMachine: "mini", DefaultCategories: []string{"brew", "mas"}}
+
+	// The failed category keeps its entries; the rest is dumped as usual
+	require.NoError(t, runDumpStdin(cfg, strings.NewReader("brew \"git\"\n"), brewfilePath, false))
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"brew:git", "mas:Xcode", "mas:Keynote"}, pkgs.IDs())
+
+	// --force writes what was collected
+	dumpForce = true
+	t.Cleanup(func() { dumpForce = false })
+	require.NoError(t, runDumpStdin(cfg, strings.NewReader("brew \"git\"\n"), brewfilePath, false))
+	pkgs, err = brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, pkgs.IDs())
+}
+
+func TestCollectAllPackages_UseBrewBundleOverride(t *testing.T) {
+	// brew bundle reports "bundled", brew list reports "listed"
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+  bundle) for arg; do case "$arg" in --file=*) echo 'brew "bundled"' > "${arg#--file=}" ;; esac; done ;;
+  list) [ "$2" = "--formula" ] && echo "listed" ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		global   bool
+		override *bool
+		want     string
+	}{
+		{"inherit bundle", true, nil, "brew:bundled"},
+		{"inherit list", false, nil, "brew:listed"},
+		{"override off", true, &no, "brew:listed"},
+		{"override on", false, &yes, "brew:bundled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				CurrentMachine:    "mini",
+				Machines:          map[string]config.Machine{"mini": {UseBrewBundle: tt.override}},
+				DefaultCategories: []string{"brew"},
+				Dump:              config.DumpConfig{UseBrewBundle: tt.global},
+			}
+
+			pkgs, _, err := collectAllPackages(cfg, filepath.Join(t.TempDir(), "Brewfile"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.want}, pkgs.IDs())
+		})
+	}
+}
+
+// setupDumpMachine writes a config whose current machine "mini" has hostname
+// "Mac-mini" and an existing Brewfile, and pretends this Mac is live
+func setupDumpMachine(t *testing.T, live string) string {
+	t.Helper()
+	dir := t.TempDir()
+	brewfilePath := filepath.Join(dir, "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"wget\"\n"), 0644))
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+machines:
+  mini:
+    hostname: Mac-mini
+    brewfile: `+brewfilePath+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	origHostname, origInteractive, origInput := localHostname, dumpInteractive, dumpConfirmInput
+	localHostname = func() (string, error) { return live, nil }
+	t.Cleanup(func() { localHostname, dumpInteractive, dumpConfirmInput = origHostname, origInteractive, origInput })
+
+	dumpStdin, dumpBrewOnly = true, true
+	t.Cleanup(func() { dumpStdin, dumpBrewOnly, dumpForce = false, false, false })
+	return brewfilePath
+}
+
+func TestRunDump_HostnameMismatchBlocksDump(t *testing.T) {
+	brewfilePath := setupDumpMachine(t, "MacBook-Air")
+	dumpInput = strings.NewReader("brew \"git\"\n")
+	defer func() { dumpInput = os.Stdin }()
+
+	err := runDump(dumpCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"Mac-mini"`)
+	assert.Contains(t, err.Error(), `"MacBook-Air"`)
+	assert.Contains(t, err.Error(), "--force")
+
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "brew \"wget\"\n", string(data), "Brewfile must not be overwritten")
+
+	// --force dumps anyway
+	dumpForce = true
+	dumpInput = strings.NewReader("brew \"git\"\n")
+	require.NoError(t, runDump(dumpCmd, nil))
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, pkgs.IDs())
+}
+
+func TestRunDump_MatchingHostnameDumps(t *testing.T) {
+	brewfilePath := setupDumpMachine(t, "Mac-mini")
+	dumpInput = strings.NewReader("brew \"git\"\n")
+	defer func() { dumpInput = os.Stdin }()
+
+	require.NoError(t, runDump(dumpCmd, nil))
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, pkgs.IDs())
+}
+
+func TestConfirmDumpMachine_Prompt(t *testing.T) {
+	setupDumpMachine(t, "MacBook-Air")
+	dumpStdin = false
+	dumpInteractive = func() bool { return true }
+	machine := config.Machine{Hostname: "Mac-mini", Brewfile: "/tmp/Brewfile"}
+
+	for answer, want := range map[string]bool{"y\n": true, "n\n": false, "\n": false} {
+		dumpConfirmInput = strings.NewReader(answer)
+		var proceed bool
+		captureStdout(t, func() {
+			var err error
+			proceed, err = confirmDumpMachine("mini", machine)
+			require.NoError(t, err)
+		})
+		assert.Equal(t, want, proceed, "answer %q", answer)
+	}
+
+	// Machines without a hostname are never questioned
+	proceed, err := confirmDumpMachine("mini", config.Machine{Brewfile: "/tmp/Brewfile"})
+	require.NoError(t, err)
+	assert.True(t, proceed)
+}
+
+func TestRunDump_NoBrew(t *testing.T) {
+	brewfilePath := setupDumpMachine(t, "Mac-mini")
+	dumpStdin = false
+	t.Setenv("PATH", t.TempDir()) // No brew
+
+	err := runDump(dumpCmd, nil)
+	require.ErrorIs(t, err, installer.ErrBrewNotFound)
+	assert.Contains(t, err.Error(), "https://brew.sh")
+	assert.Contains(t, err.Error(), brewfilePath+" was left unchanged")
+
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "brew \"wget\"\n", string(data), "Brewfile must not be overwritten with an empty one")
+}
+
+func TestRunDump_Hooks(t *testing.T) {
+	brewfilePath := setupDumpMachine(t, "Mac-mini")
+	dir := filepath.Dir(brewfilePath)
+	log := filepath.Join(dir, "hooks.log")
+	configFile := filepath.Join(dir, "config.yaml")
+	writeHooks := func(preDump string) {
+		data, err := os.ReadFile(configFile)
+		require.NoError(t, err)
+		data = []byte(strings.Split(string(data), "hooks:")[0] + `hooks:
+  pre_dump: '` + preDump + `'
+  post_dump: 'echo "post $BREWSYNC_MACHINE $BREWSYNC_BREWFILE" >> ` + log + `'
+`)
+		require.NoError(t, os.WriteFile(configFile, data, 0644))
+		config.SetConfigPath(configFile) // Reload
+	}
+	defer func() { dumpInput = os.Stdin }()
+
+	writeHooks(`echo "pre $BREWSYNC_MACHINE" >> ` + log)
+	dumpInput = strings.NewReader("brew \"git\"\n")
+	captureStdout(t, func() { require.NoError(t, runDump(dumpCmd, nil)) })
+
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "pre mini\npost mini "+brewfilePath+"\n", string(data))
+
+	// A failing pre_dump hook leaves the Brewfile alone and skips post_dump
+	require.NoError(t, os.Remove(log))
+	writeHooks("exit 1")
+	dumpInput = strings.NewReader("brew \"jq\"\n")
+	captureStdout(t, func() {
+		err = runDump(dumpCmd, nil)
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre_dump hook failed")
+
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, pkgs.IDs())
+	assert.NoFileExists(t, log)
+}
+
+func TestConfirmHandEdits(t *testing.T) {
+	// brew bundle reports only git installed
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+  bundle) for arg; do case "$arg" in --file=*) echo 'brew "git"' > "${arg#--file=}" ;; esac; done ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"git\"\nbrew \"wget\"\n"), 0644))
+	lastDump := time.Now().Add(-time.Hour)
+	require.NoError(t, brewfile.SaveMetadata(brewfile.MetadataPath(brewfilePath), &brewfile.Metadata{Machine: "mini", LastDump: lastDump}))
+	cfg := &config.Config{
+		CurrentMachine:    "mini",
+		DefaultCategories: []string{"brew"},
+		Dump:              config.DumpConfig{UseBrewBundle: true},
+	}
+
+	origInteractive, origInput := dumpInteractive, dumpConfirmInput
+	t.Cleanup(func() { dumpInteractive, dumpConfirmInput, dumpForce = origInteractive, origInput, false })
+	dumpInteractive = func() bool { return true }
+
+	confirm := func(answer string) (bool, string) {
+		dumpConfirmInput = strings.NewReader(answer)
+		var proceed bool
+		out := captureStdout(t, func() {
+			var err error
+			proceed, err = confirmHandEdits(cfg, brewfilePath)
+			require.NoError(t, err)
+		})
+		return proceed, out
+	}
+
+	// Older than the last dump: nothing to ask
+	require.NoError(t, os.Chtimes(brewfilePath, lastDump.Add(-time.Minute), lastDump.Add(-time.Minute)))
+	proceed, out := confirm("")
+	assert.True(t, proceed)
+	assert.Empty(t, out)
+
+	// Newer: warn and ask
+	require.NoError(t, os.Chtimes(brewfilePath, lastDump.Add(time.Minute), lastDump.Add(time.Minute)))
+	proceed, out = confirm("n\n")
+	assert.False(t, proceed)
+	assert.Contains(t, out, "modified after the last dump")
+
+	proceed, _ = confirm("y\n")
+	assert.True(t, proceed)
+
+	// d shows what the dump would drop before asking again
+	proceed, out = confirm("d\ny\n")
+	assert.True(t, proceed)
+	assert.Contains(t, out, "- brew:wget")
+	assert.NotContains(t, out, "brew:git")
+
+	// --force only warns
+	dumpForce = true
+	proceed, out = confirm("")
+	assert.True(t, proceed)
+	assert.NotContains(t, out, "Overwrite it?")
+}
+
+func TestRunDumpQuiet_CommitAndPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+  bundle) for arg; do case "$arg" in --file=*) printf 'brew "git"\nbrew "jq"\n' > "${arg#--file=}" ;; esac; done ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// A Brewfile repository cloned from a remote
+	root := t.TempDir()
+	remote, local := filepath.Join(root, "remote.git"), filepath.Join(root, "local")
+	runGit(t, root, "init", "--bare", "-b", "main", remote)
+	runGit(t, root, "clone", remote, local)
+	runGit(t, local, "checkout", "-b", "main")
+	runGit(t, local, "config", "user.name", "test")
+	runGit(t, local, "config", "user.email", "test@example.com")
+	brewfilePath := filepath.Join(local, "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"git\"\n"), 0644))
+	runGit(t, local, "add", ".")
+	runGit(t, local, "commit", "-m", "initial")
+	runGit(t, local, "push", "-u", "origin", "main")
+
+	cfg := &config.Config{
+		CurrentMachine:    "mini",
+		DefaultCategories: []string{"brew"},
+		Dump:              config.DumpConfig{UseBrewBundle: true},
+	}
+	t.Cleanup(func() { dumpCommit, dumpPush, dumpMessage = false, false, "" })
+	dumpCommit, dumpPush, dumpMessage = true, true, "dump {machine}"
+
+	captureStdout(t, func() {
+		require.NoError(t, runDumpQuiet(cfg, config.Machine{Brewfile: brewfilePath}, brewfilePath))
+	})
+
+	assert.Equal(t, "dump mini\n", runGit(t, local, "log", "-1", "--format=%s"))
+	assert.Equal(t, "dump mini\n", runGit(t, remote, "log", "-1", "--format=%s", "main"))
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git", "brew:jq"}, pkgs.IDs())
+}
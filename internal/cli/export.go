@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+var (
+	exportFormat  string
+	exportMachine string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a machine's packages as JSON, TOML or YAML",
+	Long: `Export the packages in a machine's Brewfile to stdout, grouped by type,
+for use by other tooling. Each package has its name and type, plus its
+description when the Brewfile has one.
+
+Examples:
+  brewsync export                        # Current machine as JSON
+  brewsync export --format toml          # As TOML
+  brewsync export --machine mini --format yaml > mini.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "output format: json, toml, yaml")
+	exportCmd.Flags().StringVar(&exportMachine, "machine", "", "machine to export (default: current machine)")
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportDocument is the exported inventory of one machine
+type exportDocument struct {
+	Machine  string                     `json:"machine" toml:"machine" yaml:"machine"`
+	Packages map[string][]exportPackage `json:"packages" toml:"packages" yaml:"packages"`
+}
+
+// exportPackage is one exported package
+type exportPackage struct {
+	Name        string `json:"name" toml:"name" yaml:"name"`
+	Type        string `json:"type" toml:"type" yaml:"type"`
+	Description string `json:"description,omitempty" toml:"description,omitempty" yaml:"description,omitempty"`
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	switch exportFormat {
+	case "json", "toml", "yaml":
+	default:
+		return fmt.Errorf("unknown format %q (use json, toml or yaml)", exportFormat)
+	}
+
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	machineName := exportMachine
+	if machineName == "" {
+		machineName = cfg.CurrentMachine
+	}
+	if machineName == "" {
+		return fmt.Errorf("no machine specified and current machine not detected")
+	}
+	machine, ok := cfg.Machines[machineName]
+	if !ok {
+		return fmt.Errorf("machine '%s' not found in config (configured: %s)", machineName, strings.Join(machineNames(cfg), ", "))
+	}
+
+	printVerbose("Reading Brewfile: %s", machine.Brewfile)
+	packages, err := brewfile.Parse(machine.Brewfile)
+	if err != nil {
+		return fmt.Errorf("failed to parse Brewfile: %w", err)
+	}
+
+	return writeExport(os.Stdout, exportFormat, newExportDocument(machineName, packages))
+}
+
+// newExportDocument groups packages by type, keeping Brewfile order within
+// each type
+func newExportDocument(machine string, packages brewfile.Packages) exportDocument {
+	doc := exportDocument{Machine: machine, Packages: make(map[string][]exportPackage)}
+	for _, pkg := range packages {
+		t := string(pkg.Type)
+		doc.Packages[t] = append(doc.Packages[t], exportPackage{
+			Name:        pkg.Name,
+			Type:        t,
+			Description: pkg.Description,
+		})
+	}
+	return doc
+}
+
+// writeExport encodes doc to w in format
+func writeExport(w io.Writer, format string, doc exportDocument) error {
+	var err error
+	switch format {
+	case "toml":
+		err = toml.NewEncoder(w).Encode(doc)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err = enc.Encode(doc); err == nil {
+			err = enc.Close()
+		}
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(doc)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", format, err)
+	}
+	return nil
+}
+
+// machineNames returns the configured machine names in sorted order
+func machineNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Machines))
+	for name := range cfg.Machines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
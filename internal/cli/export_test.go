@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestWriteExport(t *testing.T) {
+	git := brewfile.NewPackage(brewfile.TypeBrew, "git")
+	git.Description = "Distributed revision control system"
+	doc := newExportDocument("mini", brewfile.Packages{
+		git,
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+	})
+
+	want := exportDocument{Machine: "mini", Packages: map[string][]exportPackage{
+		"brew": {
+			{Name: "git", Type: "brew", Description: "Distributed revision control system"},
+			{Name: "jq", Type: "brew"},
+		},
+		"cask": {{Name: "firefox", Type: "cask"}},
+	}}
+	decoders := map[string]func([]byte, any) error{
+		"json": json.Unmarshal,
+		"toml": toml.Unmarshal,
+		"yaml": yaml.Unmarshal,
+	}
+	for format, decode := range decoders {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeExport(&buf, format, doc))
+			assert.NotContains(t, buf.String(), "description: \"\"", "missing descriptions are left out")
+
+			var got exportDocument
+			require.NoError(t, decode(buf.Bytes(), &got), buf.String())
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	dir := t.TempDir()
+	brewfilePath := filepath.Join(dir, "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("# Distributed revision control system\nbrew \"git\"\n"), 0644))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+machines:
+  mini:
+    brewfile: `+brewfilePath+`
+  air:
+    brewfile: `+filepath.Join(dir, "air")+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() {
+		config.SetConfigPath("")
+		exportFormat, exportMachine = "json", ""
+	})
+
+	out := captureStdout(t, func() { require.NoError(t, runExport(exportCmd, nil)) })
+	var got exportDocument
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, "mini", got.Machine, "defaults to the current machine")
+	assert.Equal(t, []exportPackage{{Name: "git", Type: "brew", Description: "Distributed revision control system"}}, got.Packages["brew"])
+
+	exportMachine = "laptop"
+	assert.EqualError(t, runExport(exportCmd, nil), "machine 'laptop' not found in config (configured: air, mini)")
+
+	exportMachine, exportFormat = "mini", "xml"
+	assert.ErrorContains(t, runExport(exportCmd, nil), `unknown format "xml"`)
+}
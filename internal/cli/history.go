@@ -2,9 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/history"
 )
 
@@ -19,13 +22,36 @@ var historyCmd = &cobra.Command{
 	Long: `View recent BrewSync operations.
 
 Shows a log of dump, import, sync, and other operations
-performed by BrewSync.`,
+performed by BrewSync. Each entry starts with its ID, which
+'brewsync history undo' takes to reverse a sync.`,
 	RunE: runHistory,
 }
 
+var historyUndoCmd = &cobra.Command{
+	Use:   "undo [id]",
+	Short: "Reverse a sync",
+	Long: `Reverse a sync recorded in the history: reinstall the packages it
+removed and uninstall the packages it installed.
+
+Without an ID, the most recent sync on this machine is undone. Only syncs
+run on the current machine can be undone, and only those recorded with their
+packages; older entries have counts only.
+
+Asks for confirmation unless --yes is given. Use --dry-run to only show
+what would change.
+
+Examples:
+  brewsync history undo       # Undo the last sync
+  brewsync history undo 42    # Undo sync #42
+  brewsync history undo --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistoryUndo,
+}
+
 func init() {
 	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 10, "number of entries to show")
 	historyCmd.Flags().BoolVar(&historyDetail, "detail", false, "show detailed information")
+	historyCmd.AddCommand(historyUndoCmd)
 	rootCmd.AddCommand(historyCmd)
 }
 
@@ -43,8 +69,208 @@ func runHistory(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Recent operations (showing %d):\n\n", len(entries))
 
 	for _, entry := range entries {
-		fmt.Println(entry.Format(historyDetail))
+		fmt.Printf("#%-4d %s\n", entry.ID, entry.Format(historyDetail))
+	}
+
+	return nil
+}
+
+func runHistoryUndo(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	currentMachine := cfg.CurrentMachine
+	machine, ok := cfg.Machines[currentMachine]
+	if currentMachine == "" || !ok {
+		return fmt.Errorf("could not detect current machine; run 'brewsync config init' first")
+	}
+
+	entry, err := syncToUndo(currentMachine, args)
+	if err != nil {
+		return err
+	}
+	changes, _ := entry.SyncChanges()
+	if len(changes.Added) == 0 && len(changes.Removed) == 0 {
+		printInfo("Sync #%d changed nothing; nothing to undo", entry.ID)
+		return nil
+	}
+
+	// Reverse the sync: what it removed goes back, what it installed goes.
+	// The Brewfiles of both machines still list most of the packages with
+	// the options, tap URLs and App Store IDs needed to reinstall them.
+	known := undoBrewfilePackages(cfg, machine, changes.Source)
+	toInstall, err := packagesFromIDs(changes.Removed, known)
+	if err != nil {
+		return fmt.Errorf("sync #%d: %w", entry.ID, err)
+	}
+	for _, pkg := range toInstall {
+		if pkg.Type == brewfile.TypeMas && pkg.Options["id"] == "" {
+			return fmt.Errorf("sync #%d: no App Store ID known for %s; reinstall it with 'mas install <id>' and undo the rest by hand", entry.ID, pkg.ID())
+		}
+	}
+	toRemove, err := packagesFromIDs(changes.Added, known)
+	if err != nil {
+		return fmt.Errorf("sync #%d: %w", entry.ID, err)
+	}
+
+	printInfo("Undoing sync #%d from %s (%s)", entry.ID, changes.Source, entry.Timestamp.Format("2006-01-02 15:04"))
+	if len(toInstall) > 0 {
+		fmt.Println("\nWould reinstall:")
+		for _, pkg := range toInstall {
+			fmt.Printf("  + %s\n", pkg.ID())
+		}
+	}
+	if len(toRemove) > 0 {
+		fmt.Println("\nWould uninstall:")
+		for _, pkg := range toRemove {
+			fmt.Printf("  - %s\n", pkg.ID())
+		}
+	}
+	fmt.Println()
+
+	if dryRun {
+		return nil
 	}
 
+	if !assumeYes {
+		fmt.Printf("Undo sync #%d? [y/N] ", entry.ID)
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			printInfo("Undo cancelled")
+			return nil
+		}
+		authenticateSudo(toInstall, toRemove)
+	}
+
+	mgr := newInstallManager(cfg)
+	var installs, removes installTally
+	var installedPkgs, removedPkgs brewfile.Packages
+	if len(toInstall) > 0 {
+		printInfo("Reinstalling %d packages...", len(toInstall))
+		mgr.InstallMany(toInstall, func(pkg brewfile.Package, i, total int, err error) {
+			installs.record(err)
+			if err != nil {
+				printError("[%d/%d] Failed to install %s:%s%s: %s", i, total, pkg.Type, pkg.Name, retryNote(mgr, pkg), failureReason(err))
+			} else {
+				printInfo("[%d/%d] Installed %s:%s%s", i, total, pkg.Type, pkg.Name, retryNote(mgr, pkg))
+				installedPkgs = append(installedPkgs, pkg)
+			}
+		})
+	}
+	if len(toRemove) > 0 {
+		printInfo("Removing %d packages...", len(toRemove))
+		mgr.UninstallMany(toRemove, func(pkg brewfile.Package, i, total int, err error) {
+			removes.record(err)
+			if err != nil {
+				printError("[%d/%d] Failed to remove %s:%s: %s", i, total, pkg.Type, pkg.Name, failureReason(err))
+			} else {
+				printInfo("[%d/%d] Removed %s:%s", i, total, pkg.Type, pkg.Name)
+				removedPkgs = append(removedPkgs, pkg)
+			}
+		})
+	}
+
+	printInfo("Undo complete: +%d reinstalled, -%d removed, %d failed",
+		installs.succeeded, removes.succeeded, installs.failures()+removes.failures())
+	if hint := sudoHint(installs.needsSudo + removes.needsSudo); hint != "" {
+		printWarning("%s", hint)
+	}
+
+	// The undo is a sync of its own, so it can be undone in turn
+	undoSource := fmt.Sprintf("undo #%d", entry.ID)
+	history.LogSync(currentMachine, undoSource, installedPkgs.IDs(), removedPkgs.IDs())
+	if err := brewfile.UpdateSyncMetadata(brewfile.MetadataPath(machine.Brewfile), undoSource, installedPkgs, removedPkgs); err != nil {
+		printWarning("Failed to update metadata: %v", err)
+	}
+	if len(installedPkgs) > 0 || len(removedPkgs) > 0 {
+		autoDumpAfterApply(cfg, currentMachine)
+	}
 	return nil
 }
+
+// syncToUndo returns the sync entry args names by ID, or the most recent sync
+// on machine without one. It must be a sync on machine that recorded its
+// packages.
+func syncToUndo(machine string, args []string) (history.Entry, error) {
+	if len(args) == 0 {
+		entries, err := history.Read(0)
+		if err != nil {
+			return history.Entry{}, fmt.Errorf("failed to read history: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.Operation == history.OpSync && entry.Machine == machine {
+				return checkUndoable(entry, machine)
+			}
+		}
+		return history.Entry{}, fmt.Errorf("no sync on %s in the history", machine)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return history.Entry{}, fmt.Errorf("invalid history ID %q", args[0])
+	}
+	entry, err := history.Find(id)
+	if err != nil {
+		return history.Entry{}, err
+	}
+	return checkUndoable(entry, machine)
+}
+
+// checkUndoable returns entry if it is a sync on machine with its packages
+// recorded
+func checkUndoable(entry history.Entry, machine string) (history.Entry, error) {
+	if entry.Operation != history.OpSync {
+		return history.Entry{}, fmt.Errorf("entry #%d is a %s, only syncs can be undone", entry.ID, entry.Operation)
+	}
+	if entry.Machine != machine {
+		return history.Entry{}, fmt.Errorf("sync #%d ran on %s; undo it there", entry.ID, entry.Machine)
+	}
+	if _, ok := entry.SyncChanges(); !ok {
+		return history.Entry{}, fmt.Errorf("sync #%d was recorded without its packages and can't be undone", entry.ID)
+	}
+	return entry, nil
+}
+
+// undoBrewfilePackages returns the packages in the Brewfiles of the current
+// machine and of source, the machine a sync ran from, by ID. The current
+// machine's entries come first; Brewfiles that can't be read are skipped.
+func undoBrewfilePackages(cfg *config.Config, current config.Machine, source string) map[string]brewfile.Package {
+	known := make(map[string]brewfile.Package)
+	brewfiles := []string{current.Brewfile}
+	if m, ok := cfg.Machines[source]; ok {
+		brewfiles = append(brewfiles, m.Brewfile)
+	}
+	for _, path := range brewfiles {
+		pkgs, err := brewfile.Parse(path)
+		if err != nil {
+			printVerbose("Skipping %s: %v", path, err)
+			continue
+		}
+		for _, pkg := range pkgs {
+			if _, ok := known[pkg.ID()]; !ok {
+				known[pkg.ID()] = pkg
+			}
+		}
+	}
+	return known
+}
+
+// packagesFromIDs returns the packages with the given "type:name" IDs, as
+// listed in known when there, else parsed from the ID alone
+func packagesFromIDs(ids []string, known map[string]brewfile.Package) (brewfile.Packages, error) {
+	var pkgs brewfile.Packages
+	for _, id := range ids {
+		if pkg, ok := known[id]; ok {
+			pkgs = append(pkgs, pkg)
+			continue
+		}
+		pkg, err := parsePackageArg(id)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
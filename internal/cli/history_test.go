@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/history"
+)
+
+func TestRunHistoryUndo(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.HomeEnvVar, dir)
+
+	// brew succeeds at everything and records what it was asked to do
+	calls := filepath.Join(dir, "calls")
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\nexit 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	brewfilePath := filepath.Join(dir, "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"git\"\n"), 0644))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\nmachines:\n  mini:\n    brewfile: "+brewfilePath+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	require.NoError(t, history.LogSync("mini", "air", []string{"brew:jq"}, []string{"brew:htop"}))
+	require.NoError(t, history.LogSync("studio", "air", []string{"brew:wget"}, nil))
+	require.NoError(t, history.LogInstall("mini", "brew:git", true))
+
+	t.Run("dry run", func(t *testing.T) {
+		dryRun = true
+		t.Cleanup(func() { dryRun = false })
+		out := captureStdout(t, func() { require.NoError(t, runHistoryUndo(historyUndoCmd, nil)) })
+		assert.Contains(t, out, "Would reinstall:\n  + brew:htop")
+		assert.Contains(t, out, "Would uninstall:\n  - brew:jq")
+		assert.NoFileExists(t, calls)
+	})
+
+	t.Run("not undoable", func(t *testing.T) {
+		assert.ErrorContains(t, runHistoryUndo(historyUndoCmd, []string{"2"}), "ran on studio")
+		assert.ErrorContains(t, runHistoryUndo(historyUndoCmd, []string{"3"}), "only syncs can be undone")
+		assert.ErrorContains(t, runHistoryUndo(historyUndoCmd, []string{"9"}), "no history entry #9")
+	})
+
+	t.Run("undo the last sync", func(t *testing.T) {
+		assumeYes, quiet = true, true
+		t.Cleanup(func() { assumeYes, quiet = false, false })
+		captureStdout(t, func() { require.NoError(t, runHistoryUndo(historyUndoCmd, []string{"1"})) })
+
+		data, err := os.ReadFile(calls)
+		require.NoError(t, err)
+		assert.Equal(t, "install --formula htop\nuninstall --formula jq\n", string(data))
+
+		// The undo is logged as a sync that can itself be undone
+		entries, err := history.Read(1)
+		require.NoError(t, err)
+		changes, ok := entries[0].SyncChanges()
+		require.True(t, ok)
+		assert.Equal(t, history.SyncChanges{Source: "undo #1", Added: []string{"brew:htop"}, Removed: []string{"brew:jq"}}, changes)
+	})
+}
+
+func TestRunHistoryUndo_BrewfileDetails(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.HomeEnvVar, dir)
+
+	// brew and mas succeed at everything and record what they were asked to do
+	calls := filepath.Join(dir, "calls")
+	binDir := t.TempDir()
+	for _, name := range []string{"brew", "mas"} {
+		script := "#!/bin/sh\necho \"" + name + " $@\" >> " + calls + "\nexit 0\n"
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755))
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// The sync removed them, but mini's Brewfile wasn't dumped since
+	miniBrewfile := filepath.Join(dir, "Brewfile.mini")
+	require.NoError(t, os.WriteFile(miniBrewfile, []byte(`tap "user/tools", "https://git.example.com/tools.git"
+mas "Xcode", id: 497799835
+`), 0644))
+	airBrewfile := filepath.Join(dir, "Brewfile.air")
+	require.NoError(t, os.WriteFile(airBrewfile, []byte("brew \"jq\"\n"), 0644))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+machines:
+  mini:
+    brewfile: `+miniBrewfile+`
+  air:
+    brewfile: `+airBrewfile+`
+`), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+	assumeYes, quiet = true, true
+	t.Cleanup(func() { assumeYes, quiet = false, false })
+
+	require.NoError(t, history.LogSync("mini", "air", []string{"brew:jq"}, []string{"tap:user/tools", "mas:Xcode"}))
+	require.NoError(t, history.LogSync("mini", "air", nil, []string{"mas:Keynote"}))
+
+	// A mas app neither Brewfile lists has no App Store ID to reinstall by
+	err := runHistoryUndo(historyUndoCmd, []string{"2"})
+	assert.ErrorContains(t, err, "no App Store ID known for mas:Keynote")
+	assert.NoFileExists(t, calls)
+
+	captureStdout(t, func() { require.NoError(t, runHistoryUndo(historyUndoCmd, []string{"1"})) })
+	data, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "brew tap --custom-remote user/tools https://git.example.com/tools.git\n")
+	assert.Contains(t, string(data), "mas install 497799835\n")
+	assert.Contains(t, string(data), "brew uninstall --formula jq\n")
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/exec"
+	"github.com/asamgx/brewsync/internal/hooks"
+)
+
+// printHookPlan lists the configured hooks a dry run would execute,
+// with their env placeholders expanded
+func printHookPlan(cfg *config.Config, brewfilePath string, names ...string) {
+	for _, hook := range hooks.Resolve(cfg.Hooks, hooks.Env(cfg.CurrentMachine, brewfilePath), names...) {
+		printInfo("[dry-run] Would run %s hook: %s", hook.Name, hook.Command)
+	}
+}
+
+// runHooks runs the configured hooks among names in order, showing their
+// output, and stops at the first one that fails
+func runHooks(cfg *config.Config, brewfilePath string, names ...string) error {
+	env := hooks.Env(cfg.CurrentMachine, brewfilePath)
+	for _, hook := range hooks.Resolve(cfg.Hooks, env, names...) {
+		printInfo("Running %s hook: %s", hook.Name, hook.Command)
+		err := hooks.Run(exec.Default, hook, env, func(line string) {
+			if !quiet {
+				fmt.Fprintf(infoWriter(), "  %s\n", line)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPostHooks runs hooks after an operation has finished, when a failure can
+// no longer stop it and is only a warning
+func runPostHooks(cfg *config.Config, brewfilePath string, names ...string) {
+	if err := runHooks(cfg, brewfilePath, names...); err != nil {
+		printWarning("%v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/hooks"
+)
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestPrintHookPlan_ListsWithoutRunning(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	cfg := &config.Config{
+		CurrentMachine: "mini",
+		Hooks: config.HooksConfig{
+			PreDump:  "touch " + marker,
+			PostDump: "echo dumped $BREWSYNC_MACHINE to $BREWSYNC_BREWFILE",
+		},
+	}
+
+	out := captureStdout(t, func() {
+		printHookPlan(cfg, "/dotfiles/Brewfile.mini", hooks.PreDump, hooks.PostDump)
+	})
+
+	assert.Contains(t, out, "[dry-run] Would run pre_dump hook: touch "+marker)
+	assert.Contains(t, out, "[dry-run] Would run post_dump hook: echo dumped mini to /dotfiles/Brewfile.mini")
+	_, err := os.Stat(marker)
+	assert.True(t, os.IsNotExist(err), "dry-run must not execute hooks")
+}
@@ -29,6 +29,7 @@ Subcommands:
 var (
 	ignoreMachine string
 	ignoreGlobal  bool
+	ignoreReason  string
 )
 
 // Category commands
@@ -75,7 +76,8 @@ Format: type:name
 Examples:
   brewsync ignore add cask:bluestacks              # Add to current machine
   brewsync ignore add brew:postgresql --global     # Add globally
-  brewsync ignore add vscode:ext --machine mini    # Add to specific machine`,
+  brewsync ignore add vscode:ext --machine mini    # Add to specific machine
+  brewsync ignore add cask:bluestacks --reason "work laptop only"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runIgnoreAdd,
 }
@@ -120,6 +122,7 @@ func init() {
 	// Package command flags
 	ignoreAddCmd.Flags().StringVar(&ignoreMachine, "machine", "", "add to specific machine's ignore list")
 	ignoreAddCmd.Flags().BoolVar(&ignoreGlobal, "global", false, "add to global ignore list")
+	ignoreAddCmd.Flags().StringVar(&ignoreReason, "reason", "", "note why the package is ignored (shown in 'ignore list')")
 	ignoreRemoveCmd.Flags().StringVar(&ignoreMachine, "machine", "", "remove from specific machine's ignore list")
 	ignoreRemoveCmd.Flags().BoolVar(&ignoreGlobal, "global", false, "remove from global ignore list")
 	ignoreListCmd.Flags().StringVar(&ignoreMachine, "machine", "", "show only for specific machine")
@@ -140,10 +143,10 @@ func runIgnoreCategoryAdd(cmd *cobra.Command, args []string) error {
 	validCategories := map[string]bool{
 		"tap": true, "brew": true, "cask": true,
 		"vscode": true, "cursor": true, "antigravity": true,
-		"go": true, "mas": true,
+		"go": true, "npm": true, "pipx": true, "mas": true,
 	}
 	if !validCategories[category] {
-		return fmt.Errorf("invalid category '%s'; valid categories: tap, brew, cask, vscode, cursor, antigravity, go, mas", category)
+		return fmt.Errorf("invalid category '%s'; valid categories: tap, brew, cask, vscode, cursor, antigravity, go, npm, pipx, mas", category)
 	}
 
 	// Determine machine
@@ -255,7 +258,7 @@ func runIgnoreAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := config.AddPackageIgnore(machine, pkgID, global); err != nil {
+	if err := config.AddPackageIgnoreWithReason(machine, pkgID, ignoreReason, global); err != nil {
 		return fmt.Errorf("failed to add package ignore: %w", err)
 	}
 
@@ -304,7 +307,7 @@ func runIgnoreList(cmd *cobra.Command, args []string) error {
 
 	// Show global ignores
 	if ignoreMachine == "" {
-		if len(ignoreFile.Global.Categories) > 0 || hasPackages(ignoreFile.Global.Packages) {
+		if len(ignoreFile.Global.Categories) > 0 || !ignoreFile.Global.Packages.IsEmpty() {
 			fmt.Println("Global ignores:")
 
 			if len(ignoreFile.Global.Categories) > 0 {
@@ -314,13 +317,7 @@ func runIgnoreList(cmd *cobra.Command, args []string) error {
 				}
 			}
 
-			pkgs := listPackages(ignoreFile.Global.Packages)
-			if len(pkgs) > 0 {
-				fmt.Println("  Packages:")
-				for _, pkg := range pkgs {
-					fmt.Printf("    - %s\n", pkg)
-				}
-			}
+			printIgnoredPackages(ignoreFile.Global.Packages)
 
 			hasEntries = true
 		}
@@ -332,7 +329,7 @@ func runIgnoreList(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		if len(ignoreConfig.Categories) > 0 || hasPackages(ignoreConfig.Packages) {
+		if len(ignoreConfig.Categories) > 0 || !ignoreConfig.Packages.IsEmpty() {
 			if hasEntries {
 				fmt.Println()
 			}
@@ -345,13 +342,7 @@ func runIgnoreList(cmd *cobra.Command, args []string) error {
 				}
 			}
 
-			pkgs := listPackages(ignoreConfig.Packages)
-			if len(pkgs) > 0 {
-				fmt.Println("  Packages:")
-				for _, pkg := range pkgs {
-					fmt.Printf("    - %s\n", pkg)
-				}
-			}
+			printIgnoredPackages(ignoreConfig.Packages)
 
 			hasEntries = true
 		}
@@ -383,39 +374,19 @@ func runIgnoreInit(cmd *cobra.Command, args []string) error {
 
 // Helper functions
 
-func hasPackages(list config.PackageIgnoreList) bool {
-	return len(list.Tap) > 0 || len(list.Brew) > 0 || len(list.Cask) > 0 ||
-		len(list.VSCode) > 0 || len(list.Cursor) > 0 || len(list.Antigravity) > 0 ||
-		len(list.Go) > 0 || len(list.Mas) > 0
-}
-
-func listPackages(list config.PackageIgnoreList) []string {
-	var pkgs []string
-
-	for _, name := range list.Tap {
-		pkgs = append(pkgs, "tap:"+name)
-	}
-	for _, name := range list.Brew {
-		pkgs = append(pkgs, "brew:"+name)
-	}
-	for _, name := range list.Cask {
-		pkgs = append(pkgs, "cask:"+name)
-	}
-	for _, name := range list.VSCode {
-		pkgs = append(pkgs, "vscode:"+name)
-	}
-	for _, name := range list.Cursor {
-		pkgs = append(pkgs, "cursor:"+name)
-	}
-	for _, name := range list.Antigravity {
-		pkgs = append(pkgs, "antigravity:"+name)
-	}
-	for _, name := range list.Go {
-		pkgs = append(pkgs, "go:"+name)
-	}
-	for _, name := range list.Mas {
-		pkgs = append(pkgs, "mas:"+name)
+// printIgnoredPackages prints the ignored packages of one scope with their reasons
+func printIgnoredPackages(list config.IgnoredPackages) {
+	pkgs := list.All()
+	if len(pkgs) == 0 {
+		return
 	}
 
-	return pkgs
+	fmt.Println("  Packages:")
+	for _, pkg := range pkgs {
+		if pkg.Reason != "" {
+			fmt.Printf("    - %s  # %s\n", pkg.ID, pkg.Reason)
+		} else {
+			fmt.Printf("    - %s\n", pkg.ID)
+		}
+	}
 }
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestPrintIgnoredPackages(t *testing.T) {
+	out := captureStdout(t, func() {
+		printIgnoredPackages(config.IgnoredPackages{
+			Brew: config.IgnoreEntries{{Name: "postgresql"}},
+			Cask: config.IgnoreEntries{{Name: "bluestacks", Reason: "work laptop only"}},
+		})
+	})
+
+	assert.Equal(t, "  Packages:\n    - brew:postgresql\n    - cask:bluestacks  # work laptop only\n", out)
+
+	assert.Empty(t, captureStdout(t, func() { printIgnoredPackages(config.IgnoredPackages{}) }))
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,7 +11,9 @@ import (
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/history"
+	"github.com/asamgx/brewsync/internal/hooks"
 	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/internal/notify"
 	"github.com/asamgx/brewsync/internal/tui/progress"
 	"github.com/asamgx/brewsync/internal/tui/selection"
 )
@@ -20,6 +23,11 @@ var (
 	importOnly                   string
 	importSkip                   string
 	importIncludeMachineSpecific bool
+	importReview                 bool
+	importForgetDeselected       bool
+	importFromStdin              bool
+	importFromFile               string
+	importFormat                 string
 )
 
 var importCmd = &cobra.Command{
@@ -35,22 +43,58 @@ Examples:
   brewsync import --from air           # From specific machine
   brewsync import --from mini,air      # Union of multiple machines
   brewsync import --only brew,cask     # Filter categories
+  brewsync import --only editors       # Alias for vscode,cursor,antigravity
   brewsync import --skip vscode        # Exclude categories
   brewsync import --yes                # Install all without prompts
-  brewsync import --dry-run            # Show what would be installed`,
+  brewsync import --review             # Edit the plan in $EDITOR, then install
+  brewsync import --dry-run            # Show what would be installed
+  brewsync import --yes --format json  # Machine-readable result for CI
+
+Packages can also be installed straight from a list, without a diff or
+selection, for scripts and pipelines:
+  echo "brew:jq" | brewsync import --from-stdin --yes   # type:name per line
+  brewsync import --from-file Brewfile.work --yes       # Brewfile ("-" for stdin)
+
+--format json writes the result to stdout once the import finishes: packages
+installed, failed (with errors) and skipped, and the duration. Progress goes
+to stderr. It needs --yes.`,
 	RunE: runImport,
 }
 
 func init() {
 	importCmd.Flags().StringVar(&importFrom, "from", "", "source machine(s) to import from (comma-separated)")
-	importCmd.Flags().StringVar(&importOnly, "only", "", "only import these package types (comma-separated)")
-	importCmd.Flags().StringVar(&importSkip, "skip", "", "skip these package types (comma-separated)")
+	importCmd.Flags().StringVar(&importOnly, "only", "", "only import these package types or aliases: editors, cli, apps (comma-separated)")
+	importCmd.Flags().StringVar(&importSkip, "skip", "", "skip these package types or aliases (comma-separated)")
 	importCmd.Flags().BoolVar(&importIncludeMachineSpecific, "include-machine-specific", false, "include machine-specific packages")
+	importCmd.Flags().BoolVar(&importForgetDeselected, "forget-deselected", false, "forget packages remembered as deselected for these sources (import.remember_deselected)")
+	importCmd.Flags().BoolVar(&importReview, "review", false, "review the plan in $EDITOR and install only the lines left uncommented")
+	importCmd.Flags().BoolVar(&autoDump, "auto-dump", false, "dump the Brewfile after installing (or auto_dump.after_install)")
+	importCmd.Flags().BoolVar(&checkBrew, "check-brew", false, "run 'brew doctor' first and abort if it finds errors (or install.check_brew)")
+	importCmd.Flags().IntVar(&installParallel, "parallel", 1, "install up to N packages at once; taps go first, casks and mas apps one by one (or install.parallel)")
+	importCmd.Flags().IntVar(&installRetries, "retries", 0, "retry each failed install up to N times, waiting longer each time (or install.retries)")
+	importCmd.Flags().BoolVar(&noQuarantine, "no-quarantine", false, "install casks with --no-quarantine (or install.cask_no_quarantine)")
+	importCmd.Flags().BoolVar(&importFromStdin, "from-stdin", false, "install the type:name packages listed on stdin, one per line")
+	importCmd.Flags().StringVar(&importFromFile, "from-file", "", "install the packages in a Brewfile (\"-\" reads it from stdin)")
+	importCmd.Flags().StringVar(&importFormat, "format", "table", "result format: table, json")
+	for _, flag := range []string{"from", "review", "only", "skip", "include-machine-specific", "forget-deselected"} {
+		importCmd.MarkFlagsMutuallyExclusive("from-stdin", flag)
+		importCmd.MarkFlagsMutuallyExclusive("from-file", flag)
+	}
+	importCmd.MarkFlagsMutuallyExclusive("from-stdin", "from-file")
 
 	rootCmd.AddCommand(importCmd)
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
+	retriesSet = cmd.Flags().Changed("retries")
+	parallelSet = cmd.Flags().Changed("parallel")
+	if importReview && assumeYes {
+		return fmt.Errorf("--review cannot be combined with --yes")
+	}
+	if err := checkResultFormat(importFormat); err != nil {
+		return err
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -63,6 +107,10 @@ func runImport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not detect current machine; run 'brewsync config init' first")
 	}
 
+	if importFromStdin || importFromFile != "" {
+		return runImportList(cmd, cfg)
+	}
+
 	// Determine source machines
 	sources := []string{cfg.DefaultSource}
 	if importFrom != "" {
@@ -82,8 +130,21 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Forget earlier deselections however the packages are picked this time
+	sourceKey := strings.Join(sources, ",")
+	if importForgetDeselected && !dryRun {
+		if err := config.ClearDeselected(sourceKey); err != nil {
+			printWarning("Failed to forget deselected packages: %v", err)
+		}
+	}
+
 	printInfo("Importing to %s from %s", currentMachine, strings.Join(sources, ", "))
 
+	var result *applyResult
+	if jsonResult {
+		result = newApplyResult("import", currentMachine, strings.Join(sources, ","))
+	}
+
 	// Load current machine's Brewfile
 	currentBrewfile := cfg.Machines[currentMachine].Brewfile
 	currentPkgs, err := brewfile.Parse(currentBrewfile)
@@ -114,23 +175,36 @@ func runImport(cmd *cobra.Command, args []string) error {
 	}
 
 	// Compute diff (what's in source but not in current)
-	diff := brewfile.Diff(sourcePkgs, currentPkgs)
+	diff := brewfile.DiffWithAliases(sourcePkgs, currentPkgs, cfg.ExtensionAliases)
+
+	// The Brewfile is only updated by a dump, so after an interrupted import it
+	// still lacks what was installed; skip those unless this run dumps anyway
+	if !autoDumpsAfterApply(cfg) {
+		if installed, err := installer.NewManager().ListAll(); err != nil {
+			printWarning("Could not check installed packages: %v", err)
+		} else {
+			var done brewfile.Packages
+			diff, done = diff.FilterInstalledAdditions(installed)
+			if len(done) > 0 {
+				printInfo("Skipping %d package(s) already installed: %s", len(done), strings.Join(done.IDs(), ", "))
+			}
+			result.skip(done)
+		}
+	}
 	missing := diff.Additions
 
 	if len(missing) == 0 {
 		printInfo("No new packages to import")
-		return nil
+		return writeResult(result)
 	}
 
 	// Filter by category
-	if importOnly != "" {
-		categories := parseCategories(importOnly)
-		missing = filterByCategories(missing, categories, true)
-	}
-	if importSkip != "" {
-		categories := parseCategories(importSkip)
-		missing = filterByCategories(missing, categories, false)
+	types, err := selectTypes(importOnly, importSkip)
+	if err != nil {
+		return err
 	}
+	missing = missing.Filter(types...)
+	candidates := missing
 
 	// Build ignored packages map (for marking in selection UI)
 	ignored := cfg.GetIgnoredPackages(currentMachine)
@@ -221,44 +295,70 @@ func runImport(cmd *cobra.Command, args []string) error {
 		packagesToCheck = missingForAutoMode
 	}
 
+	// Ignored and machine-specific packages are reported as skipped
+	if result != nil {
+		keep := make(map[string]bool, len(packagesToCheck))
+		for _, pkg := range packagesToCheck {
+			keep[pkg.ID()] = true
+		}
+		result.skip(candidates.Exclude(keep))
+	}
+
 	if len(packagesToCheck) == 0 {
 		printInfo("No new packages to import (after filters)")
-		return nil
+		return writeResult(result)
 	}
 
 	printInfo("Found %d packages to import", len(packagesToCheck))
 
+	mgr := newInstallManager(cfg)
+
 	// Dry run - just show what would be imported (excluding ignored)
 	if dryRun {
 		fmt.Println("\nWould import:")
+		var wouldImport brewfile.Packages
 		for _, pkg := range missing {
 			if !ignoredMap[pkg.ID()] {
 				fmt.Printf("  %s:%s\n", pkg.Type, pkg.Name)
+				wouldImport = append(wouldImport, pkg)
 			}
 		}
+		printCaskInstallCommands(cfg, mgr, wouldImport)
+		printHookPlan(cfg, currentBrewfile, hooks.PreInstall, hooks.PostInstall)
 		return nil
 	}
 
+	if err := checkBrewHealth(cfg, missing); err != nil {
+		return fmt.Errorf("%w; import aborted before installing", err)
+	}
+
 	// Interactive or auto mode
 	var toInstall brewfile.Packages
 
 	if assumeYes {
 		// Install all without prompts (already filtered)
 		toInstall = missingForAutoMode
+	} else if importReview {
+		// Edit the plan as text; ignored packages start commented out
+		toInstall, err = reviewPackages(missing, ignoredMap)
+		if err != nil {
+			return err
+		}
 	} else {
 		// Interactive selection - pass ALL packages including ignored
 		title := fmt.Sprintf("Import from %s - Select packages to install", strings.Join(sources, ", "))
 		model := selection.New(title, missing)
 		model.SetIgnored(ignoredMap)
 
-		// Pre-select all non-ignored by default
-		preselected := make(map[string]bool)
-		for _, pkg := range missing {
-			if !ignoredMap[pkg.ID()] {
-				preselected[pkg.ID()] = true
+		// Pre-select all non-ignored by default, except packages left
+		// unselected last time when import.remember_deselected is on
+		var remembered map[string]bool
+		if cfg.Import.RememberDeselected {
+			if remembered, err = config.DeselectedSet(sourceKey); err != nil {
+				printWarning("Failed to load deselected packages: %v", err)
 			}
 		}
-		model.SetSelected(preselected)
+		model.SetSelected(importPreselection(missing, ignoredMap, remembered))
 
 		p := tea.NewProgram(model, tea.WithAltScreen())
 		finalModel, err := p.Run()
@@ -274,6 +374,12 @@ func runImport(cmd *cobra.Command, args []string) error {
 
 		toInstall = m.Selected()
 
+		if cfg.Import.RememberDeselected {
+			if err := config.RememberDeselected(sourceKey, deselectedIDs(missing, toInstall, m.Ignored())); err != nil {
+				printWarning("Failed to remember deselected packages: %v", err)
+			}
+		}
+
 		// Handle newly ignored categories
 		ignoredCategories := m.IgnoredCategories()
 		if len(ignoredCategories) > 0 {
@@ -303,26 +409,15 @@ func runImport(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if err := runHooks(cfg, currentBrewfile, hooks.PreInstall); err != nil {
+		return fmt.Errorf("%w; import aborted before installing", err)
+	}
 	printInfo("Installing %d packages...", len(toInstall))
 
 	// Install packages
-	mgr := installer.NewManager()
-
+	var installedCount int
 	if assumeYes {
-		// Non-interactive progress
-		var installed, failed int
-		mgr.InstallMany(toInstall, func(pkg brewfile.Package, i, total int, err error) {
-			if err != nil {
-				printError("[%d/%d] Failed: %s:%s - %v", i, total, pkg.Type, pkg.Name, err)
-				failed++
-			} else {
-				printInfo("[%d/%d] Installed: %s:%s", i, total, pkg.Type, pkg.Name)
-				installed++
-			}
-		})
-
-		fmt.Println()
-		printInfo("Installed: %d, Failed: %d", installed, failed)
+		installedCount = installWithoutPrompts(cfg, mgr, toInstall, result)
 
 		// Log to history
 		var pkgNames []string
@@ -331,11 +426,14 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 		history.LogImport(currentMachine, strings.Join(sources, ","), pkgNames)
 	} else {
+		authenticateSudo(toInstall)
+
 		// Interactive progress UI with streaming support
 		title := "Installing packages"
 		progressModel := progress.NewWithOutput(title, toInstall, func(pkg brewfile.Package, onOutput func(line string)) error {
 			return mgr.InstallWithProgress(pkg, onOutput)
 		})
+		progressModel.SetParallel(parallel(cfg))
 
 		p := tea.NewProgram(progressModel, tea.WithAltScreen())
 		finalModel, err := p.Run()
@@ -344,7 +442,10 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 
 		m := finalModel.(progress.Model)
+		installedCount = m.Installed()
 		printInfo("Installed: %d, Failed: %d", m.Installed(), m.Failed())
+		printCaveats(os.Stdout, toInstall, mgr.Caveats())
+		notifyFinished(cfg, notify.Summary("Import", m.Installed(), m.Failed()))
 
 		// Log to history
 		var pkgNames []string
@@ -353,66 +454,67 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 		history.LogImport(currentMachine, strings.Join(sources, ","), pkgNames)
 	}
+	runPostHooks(cfg, currentBrewfile, hooks.PostInstall)
 
-	// Auto-dump if enabled and packages were installed
-	if len(toInstall) > 0 && cfg.AutoDump.Enabled && cfg.AutoDump.AfterInstall {
-		printInfo("Auto-dumping Brewfile...")
-
-		// Set flags for commit/push based on config
-		oldCommit := dumpCommit
-		oldPush := dumpPush
-		oldMessage := dumpMessage
-
-		dumpCommit = cfg.AutoDump.Commit
-		dumpPush = cfg.AutoDump.Push
-		if cfg.AutoDump.CommitMessage != "" {
-			dumpMessage = strings.ReplaceAll(cfg.AutoDump.CommitMessage, "{machine}", currentMachine)
-		}
-
-		err := runDump(nil, []string{})
+	// Refresh the Brewfile so it includes what was just applied
+	if installedCount > 0 {
+		autoDumpAfterApply(cfg, currentMachine)
+	}
 
-		// Restore flags
-		dumpCommit = oldCommit
-		dumpPush = oldPush
-		dumpMessage = oldMessage
+	return writeResult(result)
+}
 
+// installWithoutPrompts installs pkgs with line-by-line progress and returns
+// how many were installed. Each outcome is also added to result, if not nil.
+func installWithoutPrompts(cfg *config.Config, mgr *installer.Manager, pkgs brewfile.Packages, result *applyResult) int {
+	var tally installTally
+	mgr.InstallMany(pkgs, func(pkg brewfile.Package, i, total int, err error) {
+		tally.record(err)
+		result.record(installer.ActionInstall, pkg, err)
 		if err != nil {
-			printWarning("Auto-dump failed: %v", err)
+			printError("[%d/%d] Failed%s: %s:%s - %s", i, total, retryNote(mgr, pkg), pkg.Type, pkg.Name, failureReason(err))
+		} else {
+			printInfo("[%d/%d] Installed%s: %s:%s", i, total, retryNote(mgr, pkg), pkg.Type, pkg.Name)
 		}
-	}
+	})
 
-	return nil
+	fmt.Fprintln(infoWriter())
+	printInfo("Installed: %d, Failed: %d", tally.succeeded, tally.failures())
+	if hint := sudoHint(tally.needsSudo); hint != "" {
+		printWarning("%s", hint)
+	}
+	printCaveats(infoWriter(), pkgs, mgr.Caveats())
+	notifyFinished(cfg, notify.Summary("Import", tally.succeeded, tally.failures()))
+	return tally.succeeded
 }
 
-// parseCategories parses a comma-separated list of category names
-func parseCategories(s string) []brewfile.PackageType {
-	var result []brewfile.PackageType
-	for _, c := range strings.Split(s, ",") {
-		c = strings.TrimSpace(c)
-		if c != "" {
-			result = append(result, brewfile.PackageType(c))
+// importPreselection returns the IDs to preselect for import: every missing
+// package that is neither ignored nor remembered as deselected
+func importPreselection(missing brewfile.Packages, ignored, remembered map[string]bool) map[string]bool {
+	preselected := make(map[string]bool)
+	for _, pkg := range missing {
+		if !ignored[pkg.ID()] && !remembered[pkg.ID()] {
+			preselected[pkg.ID()] = true
 		}
 	}
-	return result
+	return preselected
 }
 
-// filterByCategories filters packages by category
-// If include is true, only include packages matching categories
-// If include is false, exclude packages matching categories
-func filterByCategories(pkgs brewfile.Packages, categories []brewfile.PackageType, include bool) brewfile.Packages {
-	categorySet := make(map[brewfile.PackageType]bool)
-	for _, c := range categories {
-		categorySet[c] = true
-	}
-
-	var result brewfile.Packages
-	for _, pkg := range pkgs {
-		inSet := categorySet[pkg.Type]
-		if include && inSet {
-			result = append(result, pkg)
-		} else if !include && !inSet {
-			result = append(result, pkg)
+// deselectedIDs returns the IDs of missing packages the user neither selected nor ignored
+func deselectedIDs(missing, selected, ignored brewfile.Packages) []string {
+	skip := make(map[string]bool, len(selected)+len(ignored))
+	for _, pkg := range selected {
+		skip[pkg.ID()] = true
+	}
+	for _, pkg := range ignored {
+		skip[pkg.ID()] = true
+	}
+
+	var ids []string
+	for _, pkg := range missing {
+		if !skip[pkg.ID()] {
+			ids = append(ids, pkg.ID())
 		}
 	}
-	return result
+	return ids
 }
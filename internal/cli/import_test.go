@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestImportPreselection_RemembersDeselected(t *testing.T) {
+	config.SetIgnorePath(filepath.Join(t.TempDir(), "ignore.yaml"))
+	t.Cleanup(func() { config.SetIgnorePath("") })
+
+	git := brewfile.NewPackage(brewfile.TypeBrew, "git")
+	htop := brewfile.NewPackage(brewfile.TypeBrew, "htop")
+	zoom := brewfile.NewPackage(brewfile.TypeCask, "zoom")
+	steam := brewfile.NewPackage(brewfile.TypeCask, "steam")
+	missing := brewfile.Packages{git, htop, zoom, steam}
+	ignored := map[string]bool{"cask:steam": true}
+
+	// First run: nothing remembered, everything but ignored packages is preselected
+	remembered, err := config.DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"brew:git": true, "brew:htop": true, "cask:zoom": true},
+		importPreselection(missing, ignored, remembered))
+
+	// The user unticks htop and zoom; ignored steam is not remembered
+	deselected := deselectedIDs(missing, brewfile.Packages{git}, brewfile.Packages{steam})
+	assert.Equal(t, []string{"brew:htop", "cask:zoom"}, deselected)
+	require.NoError(t, config.RememberDeselected("air", deselected))
+
+	// Next run starts with them unselected
+	remembered, err = config.DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"brew:git": true}, importPreselection(missing, ignored, remembered))
+
+	// Another source is unaffected
+	other, err := config.DeselectedSet("mini")
+	require.NoError(t, err)
+	assert.Len(t, importPreselection(missing, ignored, other), 3)
+
+	// Selecting zoom this time drops it from the memory
+	require.NoError(t, config.RememberDeselected("air", deselectedIDs(missing, brewfile.Packages{git, zoom}, brewfile.Packages{steam})))
+	remembered, err = config.DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"brew:htop": true}, remembered)
+
+	// --forget-deselected clears it
+	require.NoError(t, config.ClearDeselected("air"))
+	remembered, err = config.DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Len(t, importPreselection(missing, ignored, remembered), 3)
+}
+
+func TestRunImport_ForgetDeselectedWithYes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte("#!/bin/sh\nexit 0\n"), 0755))
+	t.Setenv("PATH", binDir)
+
+	writeFleet(t, map[string]string{"mini": "brew \"git\"\n", "air": "brew \"git\"\n"})
+	require.NoError(t, config.RememberDeselected("air", []string{"brew:jq"}))
+
+	importFrom, importForgetDeselected, assumeYes, quiet = "air", true, true, true
+	t.Cleanup(func() { importFrom, importForgetDeselected, assumeYes, quiet = "", false, false, false })
+
+	require.NoError(t, runImport(importCmd, nil))
+	remembered, err := config.DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Empty(t, remembered)
+}
+
+func TestRunImport_AutoDump(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+
+	// brew installs anything and 'brew bundle dump' reports git and jq installed
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+  bundle) for arg; do case "$arg" in --file=*) printf 'brew "git"\nbrew "jq"\n' > "${arg#--file=}" ;; esac; done ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	miniBrewfile := filepath.Join(dir, "mini", "Brewfile")
+	airBrewfile := filepath.Join(dir, "air", "Brewfile")
+	require.NoError(t, os.MkdirAll(filepath.Dir(miniBrewfile), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(airBrewfile), 0755))
+	require.NoError(t, os.WriteFile(miniBrewfile, []byte("brew \"git\"\n"), 0644))
+	require.NoError(t, os.WriteFile(airBrewfile, []byte("brew \"git\"\nbrew \"jq\"\n"), 0644))
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+default_source: air
+default_categories: [brew]
+machines:
+  mini:
+    brewfile: `+miniBrewfile+`
+  air:
+    brewfile: `+airBrewfile+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	assumeYes, quiet, autoDump = true, true, true
+	t.Cleanup(func() { assumeYes, quiet, autoDump = false, false, false })
+
+	require.NoError(t, runImport(importCmd, nil))
+
+	pkgs, err := brewfile.Parse(miniBrewfile)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"brew:git", "brew:jq"}, pkgs.IDs())
+
+	meta, err := brewfile.LoadMetadata(brewfile.MetadataPath(miniBrewfile))
+	require.NoError(t, err)
+	assert.Equal(t, "mini", meta.Machine)
+	assert.Equal(t, 2, meta.PackageCounts["brew"])
+}
+
+func TestRunImport_SkipsInstalled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	// An interrupted import already installed jq, but the Brewfile wasn't dumped
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$1 $2" in
+  "list --formula") printf 'git\njq\n' ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir)
+
+	miniBrewfile := filepath.Join(dir, "Brewfile.mini")
+	airBrewfile := filepath.Join(dir, "Brewfile.air")
+	require.NoError(t, os.WriteFile(miniBrewfile, []byte("brew \"git\"\n"), 0644))
+	require.NoError(t, os.WriteFile(airBrewfile, []byte("brew \"git\"\nbrew \"jq\"\nbrew \"ripgrep\"\n"), 0644))
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+default_source: air
+machines:
+  mini:
+    brewfile: `+miniBrewfile+`
+  air:
+    brewfile: `+airBrewfile+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	assumeYes, dryRun = true, true
+	t.Cleanup(func() { assumeYes, dryRun = false, false })
+
+	out := captureStdout(t, func() { require.NoError(t, runImport(importCmd, nil)) })
+	assert.Contains(t, out, "Skipping 1 package(s) already installed: brew:jq")
+	assert.Contains(t, out, "Found 1 packages to import")
+	plan := out[strings.Index(out, "Would import:"):]
+	assert.Contains(t, plan, "brew:ripgrep")
+	assert.NotContains(t, plan, "brew:jq")
+}
+
+func TestParsePackageList(t *testing.T) {
+	pkgs, err := parsePackageList(strings.NewReader("brew:jq\n\n# editors\n  cask:firefox  \nagy:golang.go\nbrew:jq\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:jq", "cask:firefox", "antigravity:golang.go"}, pkgs.IDs())
+
+	_, err = parsePackageList(strings.NewReader("brew:jq\ncargo:ripgrep\n"))
+	assert.EqualError(t, err, "line 2: unknown package type: cargo")
+
+	_, err = parsePackageList(strings.NewReader("jq\n"))
+	assert.ErrorContains(t, err, "line 1: invalid package ID format: jq")
+}
+
+func TestRunImport_FromStdin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	// brew records every call
+	binDir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\nexit 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	miniBrewfile := filepath.Join(dir, "Brewfile.mini")
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+machines:
+  mini:
+    brewfile: `+miniBrewfile+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	importFromStdin = true
+	t.Cleanup(func() { importFromStdin, assumeYes, quiet = false, false, false })
+	t.Cleanup(func() { importCmd.SetIn(nil) })
+
+	// Without --yes nothing is installed
+	importCmd.SetIn(strings.NewReader("brew:jq\n"))
+	assert.ErrorContains(t, runImport(importCmd, nil), "pass --yes")
+
+	// An invalid line installs nothing
+	assumeYes, quiet = true, true
+	importCmd.SetIn(strings.NewReader("brew:jq\nbogus:thing\n"))
+	assert.ErrorContains(t, runImport(importCmd, nil), "line 2: unknown package type: bogus")
+	assert.NoFileExists(t, calls)
+
+	importCmd.SetIn(strings.NewReader("brew:jq\ncask:firefox\n"))
+	require.NoError(t, runImport(importCmd, nil))
+	data, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Equal(t, "install --formula jq\ninstall --cask firefox\n", string(data))
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/history"
+	"github.com/asamgx/brewsync/internal/hooks"
+)
+
+// runImportList installs the packages given by --from-stdin or --from-file
+// directly, without a diff against a source machine or a selection
+func runImportList(cmd *cobra.Command, cfg *config.Config) error {
+	if !assumeYes && !dryRun {
+		return fmt.Errorf("installing from a list skips selection; pass --yes to install (or --dry-run to preview)")
+	}
+
+	pkgs, source, err := readImportList(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	currentMachine := cfg.CurrentMachine
+	var result *applyResult
+	if jsonResult {
+		result = newApplyResult("import", currentMachine, source)
+	}
+	if len(pkgs) == 0 {
+		printInfo("No packages to install")
+		return writeResult(result)
+	}
+
+	currentBrewfile := cfg.Machines[currentMachine].Brewfile
+	mgr := newInstallManager(cfg)
+
+	if dryRun {
+		fmt.Println("\nWould install:")
+		for _, pkg := range pkgs {
+			fmt.Printf("  %s:%s\n", pkg.Type, pkg.Name)
+		}
+		printCaskInstallCommands(cfg, mgr, pkgs)
+		printHookPlan(cfg, currentBrewfile, hooks.PreInstall, hooks.PostInstall)
+		return nil
+	}
+
+	if err := checkBrewHealth(cfg, pkgs); err != nil {
+		return fmt.Errorf("%w; import aborted before installing", err)
+	}
+	if err := runHooks(cfg, currentBrewfile, hooks.PreInstall); err != nil {
+		return fmt.Errorf("%w; import aborted before installing", err)
+	}
+	printInfo("Installing %d packages from %s...", len(pkgs), source)
+	installed := installWithoutPrompts(cfg, mgr, pkgs, result)
+	history.LogImport(currentMachine, source, pkgs.IDs())
+	runPostHooks(cfg, currentBrewfile, hooks.PostInstall)
+
+	if installed > 0 {
+		autoDumpAfterApply(cfg, currentMachine)
+	}
+	return writeResult(result)
+}
+
+// readImportList reads the packages named by --from-stdin or --from-file and
+// describes where they came from for the history log
+func readImportList(stdin io.Reader) (brewfile.Packages, string, error) {
+	if importFromStdin {
+		pkgs, err := parsePackageList(stdin)
+		return pkgs, "stdin", err
+	}
+
+	if importFromFile == "-" {
+		pkgs, err := brewfile.ParseReader(stdin)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse Brewfile from stdin: %w", err)
+		}
+		return pkgs, "stdin", nil
+	}
+
+	pkgs, err := brewfile.Parse(importFromFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s: %w", importFromFile, err)
+	}
+	return pkgs, importFromFile, nil
+}
+
+// parsePackageList parses a newline-delimited list of type:name package IDs.
+// Blank lines and # comments are skipped and duplicates are dropped; any
+// invalid line fails the whole list so nothing is installed by mistake.
+func parsePackageList(r io.Reader) (brewfile.Packages, error) {
+	var pkgs brewfile.Packages
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pkg, err := parsePackageArg(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if !seen[pkg.ID()] {
+			seen[pkg.ID()] = true
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read package list: %w", err)
+	}
+	return pkgs, nil
+}
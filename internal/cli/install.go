@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/audit"
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+var (
+	// noQuarantine is the --no-quarantine flag shared by import and sync
+	noQuarantine bool
+	// autoDump is the --auto-dump flag shared by import and sync
+	autoDump bool
+	// checkBrew is the --check-brew flag shared by import and sync
+	checkBrew bool
+	// installRetries is the --retries flag of import; retriesSet records
+	// whether it was given, since 0 overrides install.retries too
+	installRetries int
+	retriesSet     bool
+	// installParallel is the --parallel flag of import; parallelSet records
+	// whether it was given
+	installParallel int
+	parallelSet     bool
+)
+
+// brewDoctor runs 'brew doctor'; a variable so tests can stub it
+var brewDoctor = func() (installer.DoctorReport, error) {
+	return installer.NewBrewInstaller().Doctor()
+}
+
+// caskNoQuarantine reports whether casks should be installed with --no-quarantine
+func caskNoQuarantine(cfg *config.Config) bool {
+	return noQuarantine || cfg.Install.CaskNoQuarantine
+}
+
+// retries returns how often a failed install is retried: --retries if given,
+// otherwise install.retries
+func retries(cfg *config.Config) int {
+	if retriesSet {
+		return installRetries
+	}
+	return cfg.Install.Retries
+}
+
+// parallel returns how many packages are installed at once: --parallel if
+// given, otherwise install.parallel
+func parallel(cfg *config.Config) int {
+	if parallelSet {
+		return installParallel
+	}
+	return cfg.Install.Parallel
+}
+
+// retryNote describes the retries an install took (" after 2 retries"), or
+// returns "" if it took one attempt
+func retryNote(mgr *installer.Manager, pkg brewfile.Package) string {
+	switch n := mgr.Attempts(pkg) - 1; {
+	case n <= 0:
+		return ""
+	case n == 1:
+		return " after 1 retry"
+	default:
+		return fmt.Sprintf(" after %d retries", n)
+	}
+}
+
+// checkBrewHealth runs 'brew doctor' before changing Homebrew packages when
+// --check-brew or install.check_brew is set, and fails if it found errors that
+// would make the installs fail. Warnings are shown but don't stop the run.
+func checkBrewHealth(cfg *config.Config, lists ...brewfile.Packages) error {
+	if !checkBrew && !cfg.Install.CheckBrew {
+		return nil
+	}
+	if !touchesHomebrew(lists...) {
+		printVerbose("Skipping brew doctor: no Homebrew packages to change")
+		return nil
+	}
+
+	printInfo("Checking Homebrew with brew doctor...")
+	report, err := brewDoctor()
+	if err != nil {
+		return err
+	}
+	printInfo("brew doctor: %s", report.Summary())
+	for _, w := range report.Warnings {
+		printInfo("  warning: %s", w)
+	}
+	if !report.Healthy() {
+		for _, e := range report.Errors {
+			printError("brew doctor: %s", e)
+		}
+		return fmt.Errorf("brew doctor found %s", report.Summary())
+	}
+	return nil
+}
+
+// touchesHomebrew returns true if any of lists has a tap, formula or cask
+func touchesHomebrew(lists ...brewfile.Packages) bool {
+	for _, pkgs := range lists {
+		for _, pkg := range pkgs {
+			switch pkg.Type {
+			case brewfile.TypeTap, brewfile.TypeBrew, brewfile.TypeCask:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newInstallManager returns an installer.Manager configured from config and install flags
+func newInstallManager(cfg *config.Config) *installer.Manager {
+	mgr := installer.NewManager()
+	mgr.SetCaskNoQuarantine(caskNoQuarantine(cfg))
+	mgr.SetRetryPolicy(installer.Retries(retries(cfg)))
+	mgr.SetParallel(parallel(cfg))
+	audit.Attach(mgr, cfg, audit.CommandLine())
+	return mgr
+}
+
+// printCaskInstallCommands shows the brew commands casks would be installed with
+// when --no-quarantine is in effect, so previews reflect the real argv
+func printCaskInstallCommands(cfg *config.Config, mgr *installer.Manager, pkgs brewfile.Packages) {
+	if !caskNoQuarantine(cfg) {
+		return
+	}
+
+	casks := pkgs.Filter(brewfile.TypeCask)
+	if len(casks) == 0 {
+		return
+	}
+
+	fmt.Println("\nCasks would be installed with:")
+	for _, pkg := range casks {
+		fmt.Printf("  %s\n", mgr.InstallCommand(pkg))
+	}
+	fmt.Println()
+}
+
+// authenticateSudo asks for the administrator password once, before installing
+// casks known to need it. brew runs without a terminal, so sudo can't prompt
+// during the install itself; 'sudo -v' caches the credentials up front.
+func authenticateSudo(lists ...brewfile.Packages) {
+	var names []string
+	for _, pkgs := range lists {
+		for _, pkg := range pkgs {
+			if installer.NeedsSudo(pkg) {
+				names = append(names, pkg.Name)
+			}
+		}
+	}
+	if len(names) == 0 || dryRun {
+		return
+	}
+
+	printInfo("%s may ask for administrator rights; enter your password once:", strings.Join(names, ", "))
+	sudoCmd := exec.Command("sudo", "-v")
+	sudoCmd.Stdin = os.Stdin
+	sudoCmd.Stdout = os.Stdout
+	sudoCmd.Stderr = os.Stderr
+	if err := sudoCmd.Run(); err != nil {
+		printWarning("sudo authentication failed; these casks may fail to install: %v", err)
+	}
+}
+
+// installTally counts install/uninstall outcomes. Failures that only need sudo
+// are counted apart from other failures since running interactively fixes them.
+type installTally struct {
+	succeeded int
+	failed    int
+	needsSudo int
+}
+
+// record counts one outcome
+func (t *installTally) record(err error) {
+	switch {
+	case err == nil:
+		t.succeeded++
+	case errors.Is(err, installer.ErrSudoRequired):
+		t.needsSudo++
+	default:
+		t.failed++
+	}
+}
+
+// failures returns the number of packages that were not installed or removed
+func (t installTally) failures() int {
+	return t.failed + t.needsSudo
+}
+
+// sudoHint explains sudo failures, or returns "" when there were none
+func sudoHint(needsSudo int) string {
+	if needsSudo == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d package(s) need administrator rights; run again without --yes to enter your password", needsSudo)
+}
+
+// failureReason describes why a package failed, without brew's noise for sudo failures
+func failureReason(err error) string {
+	if errors.Is(err, installer.ErrSudoRequired) {
+		return installer.ErrSudoRequired.Error()
+	}
+	return err.Error()
+}
+
+// printCaveats lists the caveats brew printed while installing pkgs, grouped by
+// package in install order, so setup steps don't scroll away unnoticed
+func printCaveats(w io.Writer, pkgs brewfile.Packages, caveats map[string]string) {
+	if len(caveats) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s CAVEATS (%d)\n", colorYellow("▶"), len(caveats))
+	for _, pkg := range pkgs {
+		text, ok := caveats[pkg.ID()]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:%s\n", pkg.Type, pkg.Name)
+		for _, line := range strings.Split(text, "\n") {
+			if line == "" {
+				fmt.Fprintln(w)
+			} else {
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+func TestInstallTally_SudoFailures(t *testing.T) {
+	sudoErr := fmt.Errorf("%w: exit status 1: sudo: a terminal is required to read the password", installer.ErrSudoRequired)
+	otherErr := errors.New("exit status 1: Error: Download failed")
+
+	var tally installTally
+	tally.record(nil)
+	tally.record(sudoErr)
+	tally.record(otherErr)
+	tally.record(nil)
+
+	assert.Equal(t, 2, tally.succeeded)
+	assert.Equal(t, 1, tally.failed)
+	assert.Equal(t, 1, tally.needsSudo)
+	assert.Equal(t, 2, tally.failures())
+
+	assert.Equal(t, "requires sudo; run interactively", failureReason(sudoErr))
+	assert.Equal(t, otherErr.Error(), failureReason(otherErr))
+
+	assert.Equal(t, "1 package(s) need administrator rights; run again without --yes to enter your password", sudoHint(tally.needsSudo))
+	assert.Empty(t, sudoHint(0))
+}
+
+func TestPrintCaveats(t *testing.T) {
+	origNoColor := noColor
+	defer func() { noColor = origNoColor }()
+	noColor = true
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "postgresql@16"),
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeCask, "docker"),
+	}
+	caveats := map[string]string{
+		"cask:docker":        "Run Docker.app once to finish setup.",
+		"brew:postgresql@16": "To start postgresql@16:\n  brew services start postgresql@16\n\nOr run it manually.",
+	}
+
+	var buf bytes.Buffer
+	printCaveats(&buf, pkgs, caveats)
+	assert.Equal(t, `
+▶ CAVEATS (2)
+
+brew:postgresql@16
+  To start postgresql@16:
+    brew services start postgresql@16
+
+  Or run it manually.
+
+cask:docker
+  Run Docker.app once to finish setup.
+
+`, buf.String())
+
+	buf.Reset()
+	printCaveats(&buf, pkgs, nil)
+	assert.Empty(t, buf.String())
+}
+
+func TestCheckBrewHealth(t *testing.T) {
+	origCheck, origDoctor := checkBrew, brewDoctor
+	t.Cleanup(func() { checkBrew, brewDoctor = origCheck, origDoctor })
+
+	var report installer.DoctorReport
+	calls := 0
+	brewDoctor = func() (installer.DoctorReport, error) {
+		calls++
+		return report, nil
+	}
+
+	cfg := &config.Config{}
+	formulae := brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}
+	extensions := brewfile.Packages{brewfile.NewPackage(brewfile.TypeVSCode, "golang.go")}
+
+	// Off by default
+	checkBrew = false
+	assert.NoError(t, checkBrewHealth(cfg, formulae))
+	assert.Equal(t, 0, calls)
+
+	// Nothing for Homebrew to change
+	checkBrew = true
+	assert.NoError(t, checkBrewHealth(cfg, extensions))
+	assert.Equal(t, 0, calls)
+
+	report = installer.DoctorReport{Warnings: []string{"Unbrewed dylibs were found in /usr/local/lib."}}
+	assert.NoError(t, checkBrewHealth(cfg, extensions, formulae))
+	assert.Equal(t, 1, calls)
+
+	// install.check_brew turns it on too
+	checkBrew = false
+	cfg.Install.CheckBrew = true
+	report.Errors = []string{"The following directories are not writable by your user:"}
+	err := checkBrewHealth(cfg, formulae)
+	assert.EqualError(t, err, "brew doctor found 1 error, 1 warning")
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetries(t *testing.T) {
+	origRetries, origSet := installRetries, retriesSet
+	defer func() { installRetries, retriesSet = origRetries, origSet }()
+
+	cfg := &config.Config{Install: config.InstallConfig{Retries: 2}}
+	installRetries, retriesSet = 0, false
+	assert.Equal(t, 2, retries(cfg), "install.retries without --retries")
+
+	installRetries, retriesSet = 0, true
+	assert.Equal(t, 0, retries(cfg), "--retries 0 turns retries off")
+
+	installRetries = 5
+	assert.Equal(t, 5, retries(cfg))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -17,6 +18,9 @@ var (
 	listFrom   string
 	listOnly   []string
 	listFormat string
+	listSince  string
+
+	listAcrossMachines bool
 )
 
 var listCmd = &cobra.Command{
@@ -31,14 +35,29 @@ Examples:
   brewsync list                  # Current machine
   brewsync list --from mini      # Another machine
   brewsync list --only brew      # Filter by type
-  brewsync list --format json    # JSON output`,
+  brewsync list --format json    # JSON output
+  brewsync list --format csv     # type,name,description rows
+  brewsync list --since 7d       # What was installed this week
+  brewsync list --duplicates-across-machines  # Overlap between machines
+
+--since uses Homebrew's install times, so it only works for the current
+machine and only for formulae and casks; other types have no install time
+and are left out.
+
+--duplicates-across-machines reads every machine's Brewfile and groups the
+packages by how many machines list them. Packages on all machines are
+candidates for a shared base Brewfile pulled in with # brewsync:include;
+packages on only one are candidates for machine_specific.`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().StringVar(&listFrom, "from", "", "machine to list packages from")
-	listCmd.Flags().StringSliceVar(&listOnly, "only", nil, "only include these package types")
-	listCmd.Flags().StringVar(&listFormat, "format", "table", "output format: table, json")
+	listCmd.Flags().StringSliceVar(&listOnly, "only", nil, "only include these package types (or aliases: editors, cli, apps)")
+	listCmd.Flags().StringVar(&listFormat, "format", "table", "output format: table, json, csv")
+	listCmd.Flags().BoolVar(&noPager, "no-pager", false, "don't page long output through $PAGER (or output.pager)")
+	listCmd.Flags().StringVar(&listSince, "since", "", "only packages installed after this age or date (e.g. 7d, 2w, 2026-01-31)")
+	listCmd.Flags().BoolVar(&listAcrossMachines, "duplicates-across-machines", false, "group packages by how many machines have them")
 	rootCmd.AddCommand(listCmd)
 }
 
@@ -48,6 +67,10 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if listAcrossMachines {
+		return runListOverlaps(cfg)
+	}
+
 	// Determine which machine to list
 	machineName := listFrom
 	if machineName == "" {
@@ -77,24 +100,59 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	// Filter by type if specified
 	if len(listOnly) > 0 {
-		types := parsePackageTypes(listOnly)
+		types, err := brewfile.ParseCategories(listOnly...)
+		if err != nil {
+			return err
+		}
 		packages = packages.Filter(types...)
 	}
 
+	// Filter by install time if specified
+	if listSince != "" {
+		if machineName != cfg.CurrentMachine {
+			return fmt.Errorf("--since only works for the current machine (install times are local)")
+		}
+		cutoff, err := parseSince(listSince, time.Now())
+		if err != nil {
+			return err
+		}
+		recent, undated, err := installedSince(packages, cutoff)
+		if err != nil {
+			return err
+		}
+		if len(undated) > 0 && listFormat == "table" {
+			printInfo("Skipping %d package(s) without install times", len(undated))
+		}
+		packages = recent
+	}
+
 	// Output results
 	switch listFormat {
 	case "json":
-		return outputListJSON(packages, machineName)
+		return outputListJSON(packages, machineName, cfg.PinnedSet())
+	case "csv":
+		return outputListCSV(packages)
 	default:
-		return outputListTable(packages, machineName)
+		return withPager(cfg, listFormat, func() error {
+			return outputListTable(packages, machineName, cfg.PinnedSet())
+		})
 	}
 }
 
-func outputListJSON(packages brewfile.Packages, machine string) error {
+func outputListJSON(packages brewfile.Packages, machine string, pinned map[string]bool) error {
 	output := map[string]interface{}{
 		"machine":  machine,
 		"packages": packageNames(packages),
-		"counts":   packageCounts(packages),
+		"counts":   packages.Stats().Counts(),
+	}
+	if len(pinned) > 0 {
+		var pinnedPkgs brewfile.Packages
+		for _, pkg := range packages {
+			if pinned[pkg.ID()] {
+				pinnedPkgs = append(pinnedPkgs, pkg)
+			}
+		}
+		output["pinned"] = packageNames(pinnedPkgs)
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -102,15 +160,7 @@ func outputListJSON(packages brewfile.Packages, machine string) error {
 	return enc.Encode(output)
 }
 
-func packageCounts(pkgs brewfile.Packages) map[string]int {
-	counts := make(map[string]int)
-	for _, pkg := range pkgs {
-		counts[string(pkg.Type)]++
-	}
-	return counts
-}
-
-func outputListTable(packages brewfile.Packages, machine string) error {
+func outputListTable(packages brewfile.Packages, machine string, pinned map[string]bool) error {
 	if len(packages) == 0 {
 		printInfo("No packages found for %s", machine)
 		return nil
@@ -150,22 +200,23 @@ func outputListTable(packages brewfile.Packages, machine string) error {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	// Type icons/colors
-	typeInfo := map[brewfile.PackageType]struct {
-		icon  string
-		color lipgloss.Color
-	}{
-		brewfile.TypeTap:         {"🚰", catTeal},
-		brewfile.TypeBrew:        {"🍺", catYellow},
-		brewfile.TypeCask:        {"📦", catPeach},
-		brewfile.TypeVSCode:      {"💻", catBlue},
-		brewfile.TypeCursor:      {"✏️ ", catMauve},
-		brewfile.TypeAntigravity: {"🚀", catPink},
-		brewfile.TypeGo:          {"🔷", catSapphire},
-		brewfile.TypeMas:         {"🍎", catRed},
+	// Type colors
+	typeColors := map[brewfile.PackageType]lipgloss.Color{
+		brewfile.TypeTap:         catTeal,
+		brewfile.TypeBrew:        catYellow,
+		brewfile.TypeCask:        catPeach,
+		brewfile.TypeVSCode:      catBlue,
+		brewfile.TypeCursor:      catMauve,
+		brewfile.TypeAntigravity: catPink,
+		brewfile.TypeGo:          catSapphire,
+		brewfile.TypeNpm:         catMaroon,
+		brewfile.TypePipx:        catGreen,
+		brewfile.TypeMas:         catRed,
 	}
 
 	var allRows []string
@@ -176,13 +227,13 @@ func outputListTable(packages brewfile.Packages, machine string) error {
 			continue
 		}
 
-		info := typeInfo[t]
+		color := typeColors[t]
 
 		// Category header with icon
 		categoryHeader := lipgloss.NewStyle().
-			Foreground(info.color).
+			Foreground(color).
 			Bold(true).
-			Render(fmt.Sprintf("%s %s (%d)", info.icon, t, len(typePkgs)))
+			Render(fmt.Sprintf("%s %s (%d)", brewfile.Meta(t).Icon, t, len(typePkgs)))
 
 		allRows = append(allRows, categoryHeader)
 
@@ -201,6 +252,11 @@ func outputListTable(packages brewfile.Packages, machine string) error {
 			pkgName := lipgloss.NewStyle().
 				Foreground(catText).
 				Render(pkg.Name)
+			if pinned[pkg.ID()] {
+				pkgName += " " + lipgloss.NewStyle().
+					Foreground(catYellow).
+					Render("📌")
+			}
 
 			// Add description if available
 			var row string
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+var (
+	matrixMissingFrom string
+	matrixOnly        []string
+	matrixFormat      string
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Show which machines have each package",
+	Long: `Compare every machine's Brewfile at once and print a table with a row per
+package and a column per machine, marking which machines list it.
+
+Use --missing-from to show only packages some machine has but the given
+machine doesn't, e.g. the tools you forgot to add to your laptop.
+
+Examples:
+  brewsync matrix                     # Every package on every machine
+  brewsync matrix --missing-from air  # What air lacks
+  brewsync matrix --only cask         # Only casks
+  brewsync matrix --format json       # JSON output`,
+	RunE: runMatrix,
+}
+
+func init() {
+	matrixCmd.Flags().StringVar(&matrixMissingFrom, "missing-from", "", "only show packages this machine doesn't have")
+	matrixCmd.Flags().StringSliceVar(&matrixOnly, "only", nil, "only include these package types (or aliases: editors, cli, apps)")
+	matrixCmd.Flags().StringVar(&matrixFormat, "format", "table", "output format: table, json")
+	matrixCmd.Flags().BoolVar(&noPager, "no-pager", false, "don't page long output through $PAGER (or output.pager)")
+	rootCmd.AddCommand(matrixCmd)
+}
+
+// matrixRow is a package in the matrix JSON output
+type matrixRow struct {
+	Type string          `json:"type"`
+	Name string          `json:"name"`
+	On   map[string]bool `json:"on"`
+}
+
+func runMatrix(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if matrixFormat != "table" && matrixFormat != "json" {
+		return fmt.Errorf("unknown format %q (use table or json)", matrixFormat)
+	}
+	if matrixMissingFrom != "" {
+		if _, ok := cfg.Machines[matrixMissingFrom]; !ok {
+			return fmt.Errorf("machine '%s' not found in config", matrixMissingFrom)
+		}
+	}
+
+	machines, names, err := loadFleet(cfg, matrixOnly)
+	if err != nil {
+		return err
+	}
+	if _, ok := machines[matrixMissingFrom]; matrixMissingFrom != "" && !ok {
+		return fmt.Errorf("no Brewfile for machine '%s'", matrixMissingFrom)
+	}
+
+	rows := brewfile.DiffAllWithAliases(machines, cfg.ExtensionAliases)
+	if matrixMissingFrom != "" {
+		var missing []brewfile.Presence
+		for _, row := range rows {
+			if !row.On[matrixMissingFrom] {
+				missing = append(missing, row)
+			}
+		}
+		rows = missing
+	}
+
+	if matrixFormat == "json" {
+		out := make([]matrixRow, 0, len(rows))
+		for _, row := range rows {
+			out = append(out, matrixRow{Type: string(row.Package.Type), Name: row.Package.Name, On: row.On})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"machines": names,
+			"packages": out,
+		})
+	}
+
+	return withPager(cfg, matrixFormat, func() error {
+		outputMatrixTable(rows, names)
+		return nil
+	})
+}
+
+func outputMatrixTable(rows []brewfile.Presence, machines []string) {
+	if len(rows) == 0 {
+		if matrixMissingFrom != "" {
+			printInfo("%s has every package the other machines have", matrixMissingFrom)
+		} else {
+			printInfo("No packages found")
+		}
+		return
+	}
+
+	idWidth := len("Package")
+	for _, row := range rows {
+		idWidth = max(idWidth, lipgloss.Width(row.Package.ID()))
+	}
+
+	headerStyle := lipgloss.NewStyle().Foreground(catLavender).Bold(true)
+	haveStyle := lipgloss.NewStyle().Foreground(catGreen)
+	lackStyle := lipgloss.NewStyle().Foreground(catRed)
+	dimStyle := lipgloss.NewStyle().Foreground(catOverlay0)
+
+	// cell pads s, which displays as one column, to the width of a machine's name
+	cell := func(s string, machine string) string {
+		return s + strings.Repeat(" ", lipgloss.Width(machine)-1)
+	}
+
+	header := []string{fmt.Sprintf("%-*s", idWidth, "Package")}
+	for _, machine := range machines {
+		header = append(header, machine)
+	}
+	fmt.Println()
+	fmt.Println(headerStyle.Render(strings.Join(header, "  ")))
+	fmt.Println(dimStyle.Render(strings.Repeat("─", lipgloss.Width(strings.Join(header, "  ")))))
+
+	partial := 0
+	for _, row := range rows {
+		line := []string{fmt.Sprintf("%-*s", idWidth, row.Package.ID())}
+		for _, machine := range machines {
+			if row.On[machine] {
+				line = append(line, cell(haveStyle.Render("✓"), machine))
+			} else {
+				line = append(line, cell(lackStyle.Render("✗"), machine))
+			}
+		}
+		if len(row.Missing()) > 0 {
+			partial++
+		}
+		fmt.Println(strings.Join(line, "  "))
+	}
+
+	fmt.Println()
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%d packages, %d not on every machine", len(rows), partial)))
+	fmt.Println()
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMatrix(t *testing.T) {
+	writeFleet(t, map[string]string{
+		"mini":   "brew \"git\"\nbrew \"jq\"\ncask \"orbstack\"\n",
+		"air":    "brew \"git\"\nbrew \"ollama\"\n",
+		"studio": "brew \"git\"\ncask \"orbstack\"\n",
+	})
+	t.Cleanup(func() { matrixMissingFrom, matrixOnly, matrixFormat, noPager = "", nil, "table", false })
+	noPager = true
+
+	t.Run("json", func(t *testing.T) {
+		matrixFormat = "json"
+		out := captureStdout(t, func() { require.NoError(t, runMatrix(matrixCmd, nil)) })
+
+		var got struct {
+			Machines []string    `json:"machines"`
+			Packages []matrixRow `json:"packages"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &got))
+		assert.Equal(t, []string{"air", "mini", "studio"}, got.Machines)
+		require.Len(t, got.Packages, 4)
+		assert.Equal(t, matrixRow{Type: "brew", Name: "jq", On: map[string]bool{"air": false, "mini": true, "studio": false}}, got.Packages[1])
+	})
+
+	t.Run("missing from", func(t *testing.T) {
+		matrixFormat, matrixMissingFrom = "table", "air"
+		out := captureStdout(t, func() { require.NoError(t, runMatrix(matrixCmd, nil)) })
+		assert.Contains(t, out, "brew:jq")
+		assert.Contains(t, out, "cask:orbstack")
+		assert.NotContains(t, out, "brew:git")
+		assert.NotContains(t, out, "brew:ollama")
+		assert.Contains(t, out, "2 packages, 2 not on every machine")
+	})
+
+	t.Run("unknown machine", func(t *testing.T) {
+		matrixMissingFrom = "laptop"
+		assert.ErrorContains(t, runMatrix(matrixCmd, nil), "not found")
+	})
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// overlapGroup is the packages found on the same number of machines
+type overlapGroup struct {
+	Count    int                `json:"count"`
+	Packages []overlapGroupItem `json:"packages"`
+}
+
+// overlapGroupItem is a package in an overlap group
+type overlapGroupItem struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Machines []string `json:"machines"`
+}
+
+// loadFleet parses every machine's Brewfile, keeping only the package types or
+// aliases in only (all when empty), and returns the packages by machine with
+// the machine names sorted. Unreadable Brewfiles are skipped; comparing needs
+// at least two.
+func loadFleet(cfg *config.Config, only []string) (map[string]brewfile.Packages, []string, error) {
+	var types []brewfile.PackageType
+	if len(only) > 0 {
+		var err error
+		if types, err = brewfile.ParseCategories(only...); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	machines := make(map[string]brewfile.Packages)
+	for name, machine := range cfg.Machines {
+		pkgs, err := brewfile.Parse(machine.Brewfile)
+		if err != nil {
+			printVerbose("Skipping %s: %v", name, err)
+			continue
+		}
+		if len(types) > 0 {
+			pkgs = pkgs.Filter(types...)
+		}
+		machines[name] = pkgs
+	}
+	if len(machines) < 2 {
+		return nil, nil, fmt.Errorf("need Brewfiles for at least two machines to compare (found %d)", len(machines))
+	}
+
+	names := make([]string, 0, len(machines))
+	for name := range machines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return machines, names, nil
+}
+
+// runListOverlaps reports which packages every machine has and which only one
+// has, for deciding what belongs in a shared include
+func runListOverlaps(cfg *config.Config) error {
+	if listFrom != "" || listSince != "" {
+		return fmt.Errorf("--duplicates-across-machines compares every machine and can't be combined with --from or --since")
+	}
+
+	machines, names, err := loadFleet(cfg, listOnly)
+	if err != nil {
+		return err
+	}
+
+	groups := groupOverlaps(brewfile.Overlaps(machines, cfg.ExtensionAliases))
+	switch listFormat {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]interface{}{
+			"machines": names,
+			"groups":   groups,
+		})
+	case "csv":
+		return outputOverlapsCSV(groups)
+	default:
+		outputOverlapsTable(groups, names)
+		return nil
+	}
+}
+
+// groupOverlaps groups overlaps (most widely shared first) by machine count
+func groupOverlaps(overlaps []brewfile.Overlap) []overlapGroup {
+	groups := []overlapGroup{}
+	for _, o := range overlaps {
+		count := len(o.Machines)
+		if len(groups) == 0 || groups[len(groups)-1].Count != count {
+			groups = append(groups, overlapGroup{Count: count})
+		}
+		g := &groups[len(groups)-1]
+		g.Packages = append(g.Packages, overlapGroupItem{
+			Type:     string(o.Package.Type),
+			Name:     o.Package.Name,
+			Machines: o.Machines,
+		})
+	}
+	return groups
+}
+
+// overlapGroupTitle describes a group, e.g. "On all 3 machines"
+func overlapGroupTitle(count, total int) string {
+	switch {
+	case count == total:
+		return fmt.Sprintf("On all %d machines", total)
+	case count == 1:
+		return "On only 1 machine"
+	default:
+		return fmt.Sprintf("On %d of %d machines", count, total)
+	}
+}
+
+func outputOverlapsTable(groups []overlapGroup, machines []string) {
+	const tableWidth = 80
+
+	headerBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(catOverlay0).
+		Padding(0, 2).
+		Width(tableWidth).
+		Align(lipgloss.Center).
+		Foreground(catLavender).
+		Bold(true)
+	fmt.Println()
+	fmt.Println(headerBox.Render(fmt.Sprintf("Packages across %s", strings.Join(machines, ", "))))
+	fmt.Println()
+
+	var rows []string
+	for _, g := range groups {
+		color := catBlue
+		switch g.Count {
+		case len(machines):
+			color = catGreen
+		case 1:
+			color = catPeach
+		}
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(color).
+			Bold(true).
+			Render(fmt.Sprintf("%s (%d)", overlapGroupTitle(g.Count, len(machines)), len(g.Packages))))
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(catOverlay0).
+			Render(strings.Repeat("─", tableWidth-4)))
+
+		for _, pkg := range g.Packages {
+			row := fmt.Sprintf("  %s %s:%s", lipgloss.NewStyle().Foreground(catOverlay1).Render("•"), pkg.Type, pkg.Name)
+			// Everyone has the shared base; otherwise say who does
+			if g.Count < len(machines) {
+				row += " " + lipgloss.NewStyle().
+					Foreground(catSubtext0).
+					Italic(true).
+					Render("("+strings.Join(pkg.Machines, ", ")+")")
+			}
+			rows = append(rows, row)
+		}
+		rows = append(rows, "")
+	}
+	if len(groups) > 0 && groups[0].Count == len(machines) {
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(catSubtext0).
+			Render("Packages on all machines are candidates for a shared # brewsync:include file"))
+	}
+
+	contentBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(catOverlay0).
+		Padding(1, 2).
+		Width(tableWidth)
+	fmt.Println(contentBox.Render(strings.Join(rows, "\n")))
+	fmt.Println()
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// writeFleet writes a Brewfile per machine and a config listing them
+func writeFleet(t *testing.T, brewfiles map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := "current_machine: mini\nmachines:\n"
+	for name, content := range brewfiles {
+		path := filepath.Join(dir, "Brewfile."+name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		cfg += "  " + name + ":\n    brewfile: " + path + "\n"
+	}
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(cfg), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+}
+
+func TestRunList_DuplicatesAcrossMachines(t *testing.T) {
+	writeFleet(t, map[string]string{
+		"mini":   "brew \"git\"\nbrew \"jq\"\ncask \"orbstack\"\n",
+		"air":    "brew \"git\"\nbrew \"jq\"\nbrew \"ollama\"\n",
+		"studio": "brew \"git\"\ncask \"orbstack\"\ncask \"blender\"\n",
+	})
+	listAcrossMachines = true
+	t.Cleanup(func() { listAcrossMachines, listFormat, listOnly, listFrom = false, "table", nil, "" })
+
+	t.Run("json", func(t *testing.T) {
+		listFormat = "json"
+		out := captureStdout(t, func() { require.NoError(t, runList(listCmd, nil)) })
+
+		var got struct {
+			Machines []string       `json:"machines"`
+			Groups   []overlapGroup `json:"groups"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &got))
+		assert.Equal(t, []string{"air", "mini", "studio"}, got.Machines)
+		assert.Equal(t, []overlapGroup{
+			{Count: 3, Packages: []overlapGroupItem{{Type: "brew", Name: "git", Machines: []string{"air", "mini", "studio"}}}},
+			{Count: 2, Packages: []overlapGroupItem{
+				{Type: "brew", Name: "jq", Machines: []string{"air", "mini"}},
+				{Type: "cask", Name: "orbstack", Machines: []string{"mini", "studio"}},
+			}},
+			{Count: 1, Packages: []overlapGroupItem{
+				{Type: "brew", Name: "ollama", Machines: []string{"air"}},
+				{Type: "cask", Name: "blender", Machines: []string{"studio"}},
+			}},
+		}, got.Groups)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		listFormat = "table"
+		out := captureStdout(t, func() { require.NoError(t, runList(listCmd, nil)) })
+		assert.Contains(t, out, "On all 3 machines (1)")
+		assert.Contains(t, out, "On 2 of 3 machines (2)")
+		assert.Contains(t, out, "On only 1 machine (2)")
+		assert.Contains(t, out, "cask:blender (studio)")
+		assert.NotContains(t, out, "brew:git (")
+	})
+
+	t.Run("csv with --only", func(t *testing.T) {
+		listFormat, listOnly = "csv", []string{"cask"}
+		out := captureStdout(t, func() { require.NoError(t, runList(listCmd, nil)) })
+		assert.Equal(t, [][]string{
+			{"type", "name", "machine_count", "machines"},
+			{"cask", "orbstack", "2", "mini;studio"},
+			{"cask", "blender", "1", "studio"},
+		}, readCSV(t, out))
+		listOnly = nil
+	})
+
+	t.Run("not with --from", func(t *testing.T) {
+		listFrom = "air"
+		assert.ErrorContains(t, runList(listCmd, nil), "--from")
+		listFrom = ""
+	})
+}
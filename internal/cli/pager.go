@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// defaultPager keeps colors (-R) when $PAGER isn't set
+const defaultPager = "less -R"
+
+// noPager is the --no-pager flag shared by diff, status and list
+var noPager bool
+
+// stdoutIsTerminal reports whether stdout is an interactive terminal; a
+// variable so tests can stub it
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(os.Stdout.Fd())
+}
+
+// terminalHeight returns the number of rows of the terminal on stdout, or 0
+// if it is unknown; a variable so tests can stub it
+var terminalHeight = func() int {
+	_, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+// runPager shows output through the pager command; a variable so tests can
+// stub it
+var runPager = func(command string, output []byte) error {
+	args := strings.Fields(command)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(output)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// pagerCommand returns $PAGER, or less -R if it isn't set
+func pagerCommand() string {
+	if pager := strings.TrimSpace(os.Getenv("PAGER")); pager != "" {
+		return pager
+	}
+	return defaultPager
+}
+
+// usePager reports whether table output should go through the pager: only
+// for a terminal, never for json or csv, and not with --no-pager or
+// output.pager off
+func usePager(cfg *config.Config, format string) bool {
+	if noPager || (cfg != nil && !cfg.Output.Pager) {
+		return false
+	}
+	if format != "" && format != "table" {
+		return false
+	}
+	return stdoutIsTerminal()
+}
+
+// withPager runs print and, if usePager allows it, pages what it wrote to
+// stdout when it is taller than the terminal. Shorter output is written
+// straight out, as is everything if the pager can't be run.
+func withPager(cfg *config.Config, format string, print func() error) error {
+	if !usePager(cfg, format) {
+		return print()
+	}
+
+	output, err := captureOutput(print)
+	if err != nil {
+		os.Stdout.Write(output)
+		return err
+	}
+
+	height := terminalHeight()
+	if height <= 0 || bytes.Count(output, []byte("\n")) < height {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+
+	command := pagerCommand()
+	if err := runPager(command, output); err != nil {
+		printVerbose("Pager %q failed: %v", command, err)
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			_, err := os.Stdout.Write(output)
+			return err
+		}
+	}
+	return nil
+}
+
+// captureOutput runs print with stdout redirected and returns what it wrote
+func captureOutput(print func() error) ([]byte, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, r)
+		close(done)
+	}()
+
+	orig := os.Stdout
+	os.Stdout = w
+	printErr := print()
+	os.Stdout = orig
+	w.Close()
+	<-done
+	r.Close()
+
+	return buf.Bytes(), printErr
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestWithPager(t *testing.T) {
+	origTerminal, origHeight, origRun, origNoPager := stdoutIsTerminal, terminalHeight, runPager, noPager
+	t.Cleanup(func() {
+		stdoutIsTerminal, terminalHeight, runPager, noPager = origTerminal, origHeight, origRun, origNoPager
+	})
+	t.Setenv("PAGER", "")
+	terminalHeight = func() int { return 10 }
+
+	longOutput := func() error {
+		for i := 1; i <= 20; i++ {
+			fmt.Printf("line %d\n", i)
+		}
+		return nil
+	}
+	enabled := &config.Config{Output: config.OutputConfig{Pager: true}}
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		format   string
+		terminal bool
+		noPager  bool
+		print    func() error
+		paged    bool
+	}{
+		{name: "long output in a terminal", cfg: enabled, format: "table", terminal: true, print: longOutput, paged: true},
+		{name: "--no-pager", cfg: enabled, format: "table", terminal: true, noPager: true, print: longOutput},
+		{name: "not a terminal", cfg: enabled, format: "table", print: longOutput},
+		{name: "json", cfg: enabled, format: "json", terminal: true, print: longOutput},
+		{name: "output.pager off", cfg: &config.Config{}, format: "table", terminal: true, print: longOutput},
+		{name: "fits the terminal", cfg: enabled, format: "table", terminal: true, print: func() error {
+			fmt.Println("short")
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdoutIsTerminal = func() bool { return tt.terminal }
+			noPager = tt.noPager
+			var pagedWith string
+			var pagedOutput []byte
+			runPager = func(command string, output []byte) error {
+				pagedWith, pagedOutput = command, output
+				return nil
+			}
+
+			out := captureStdout(t, func() {
+				require.NoError(t, withPager(tt.cfg, tt.format, tt.print))
+			})
+
+			if tt.paged {
+				assert.Equal(t, "less -R", pagedWith)
+				assert.Equal(t, 20, strings.Count(string(pagedOutput), "\n"))
+				assert.Empty(t, out)
+			} else {
+				assert.Empty(t, pagedWith, "pager not run")
+				assert.NotEmpty(t, out, "printed directly")
+			}
+		})
+	}
+}
+
+func TestWithPager_UsesPagerEnv(t *testing.T) {
+	origTerminal, origHeight, origRun := stdoutIsTerminal, terminalHeight, runPager
+	t.Cleanup(func() { stdoutIsTerminal, terminalHeight, runPager = origTerminal, origHeight, origRun })
+	stdoutIsTerminal = func() bool { return true }
+	terminalHeight = func() int { return 1 }
+	t.Setenv("PAGER", "more")
+
+	var pagedWith string
+	runPager = func(command string, output []byte) error {
+		pagedWith = command
+		return nil
+	}
+	cfg := &config.Config{Output: config.OutputConfig{Pager: true}}
+	captureStdout(t, func() {
+		require.NoError(t, withPager(cfg, "table", func() error {
+			fmt.Println("one\ntwo")
+			return nil
+		}))
+	})
+	assert.Equal(t, "more", pagedWith)
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+var pinBrew bool
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [type:name]",
+	Short: "Pin a package so it is never removed or upgraded",
+	Long: `Pin a package so sync never offers it for removal or upgrade.
+Pinned packages are annotated in list and diff output.
+
+Pins are stored in config.yaml under 'pinned'. With --brew, formulae are
+also pinned with 'brew pin' so 'brew upgrade' skips them.
+
+Without arguments, lists pinned packages.
+
+Examples:
+  brewsync pin                         # List pinned packages
+  brewsync pin brew:node@18            # Pin a formula
+  brewsync pin brew:node@18 --brew     # Also run 'brew pin node@18'
+  brewsync pin cask:docker             # Pin a cask`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPin,
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin [type:name]",
+	Short: "Unpin a package",
+	Long: `Remove a package from the pinned list.
+
+Examples:
+  brewsync unpin brew:node@18          # Unpin a formula
+  brewsync unpin brew:node@18 --brew   # Also run 'brew unpin node@18'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUnpin,
+}
+
+func init() {
+	pinCmd.Flags().BoolVar(&pinBrew, "brew", false, "also pin formulae with 'brew pin'")
+	unpinCmd.Flags().BoolVar(&pinBrew, "brew", false, "also unpin formulae with 'brew unpin'")
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+}
+
+func runPin(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if len(args) == 0 {
+		if len(cfg.Pinned) == 0 {
+			fmt.Println("No packages are pinned.")
+			return nil
+		}
+		fmt.Println("Pinned packages:")
+		for _, id := range cfg.Pinned {
+			fmt.Printf("  %s\n", id)
+		}
+		return nil
+	}
+
+	pkg, err := parsePackageArg(args[0])
+	if err != nil {
+		return err
+	}
+
+	added, err := cfg.AddPin(pkg.ID())
+	if err != nil {
+		return err
+	}
+	if !added {
+		printInfo("%s is already pinned", pkg.ID())
+	} else {
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		printInfo("Pinned %s", pkg.ID())
+	}
+
+	if pinBrew && pkg.Type == brewfile.TypeBrew {
+		if err := installer.NewBrewInstaller().Pin(pkg); err != nil {
+			return fmt.Errorf("brew pin failed: %w", err)
+		}
+		printVerbose("Ran 'brew pin %s'", pkg.Name)
+	}
+
+	return nil
+}
+
+func runUnpin(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	pkg, err := parsePackageArg(args[0])
+	if err != nil {
+		return err
+	}
+
+	removed, err := cfg.RemovePin(pkg.ID())
+	if err != nil {
+		return err
+	}
+	if !removed {
+		printInfo("%s is not pinned", pkg.ID())
+	} else {
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		printInfo("Unpinned %s", pkg.ID())
+	}
+
+	if pinBrew && pkg.Type == brewfile.TypeBrew {
+		if err := installer.NewBrewInstaller().Unpin(pkg); err != nil {
+			return fmt.Errorf("brew unpin failed: %w", err)
+		}
+		printVerbose("Ran 'brew unpin %s'", pkg.Name)
+	}
+
+	return nil
+}
+
+// parsePackageArg parses a "type:name" argument into a package
+func parsePackageArg(arg string) (brewfile.Package, error) {
+	typeName, name, ok := strings.Cut(arg, ":")
+	if !ok || name == "" {
+		return brewfile.Package{}, fmt.Errorf("invalid package ID format: %s (expected type:name)", arg)
+	}
+	pkgType, err := brewfile.ParsePackageType(typeName)
+	if err != nil {
+		return brewfile.Package{}, err
+	}
+	return brewfile.NewPackage(pkgType, name), nil
+}
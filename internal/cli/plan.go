@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/sync"
+)
+
+var (
+	planAs     string
+	planFrom   string
+	planTarget string
+	planOnly   string
+	planSkip   string
+	planFormat string
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plan what setting up a new machine would install",
+	Long: `Show the import plan for a machine as if it were being set up now,
+without it being the current machine. Nothing is installed.
+
+The planned machine starts empty, or from its Brewfile if it is already in
+the config, or from --target. Global ignores and, for a configured machine,
+its own ignores apply; packages specific to other machines are left out.
+
+Examples:
+  brewsync plan --as studio --from mini,air      # Union of two machines
+  brewsync plan --as studio --only cli           # Only brew, tap and go
+  brewsync plan --as studio --target ./Brewfile  # Start from an existing file
+  brewsync plan --as studio --format json        # Machine-readable plan`,
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVar(&planAs, "as", "", "name of the machine to plan for (need not be configured)")
+	planCmd.Flags().StringVar(&planFrom, "from", "", "source machine(s) to plan from (comma-separated, default: default_source)")
+	planCmd.Flags().StringVar(&planTarget, "target", "", "Brewfile the planned machine starts from (default: its configured Brewfile, or empty)")
+	planCmd.Flags().StringVar(&planOnly, "only", "", "only plan these package types or aliases: editors, cli, apps (comma-separated)")
+	planCmd.Flags().StringVar(&planSkip, "skip", "", "skip these package types or aliases (comma-separated)")
+	planCmd.Flags().StringVar(&planFormat, "format", "table", "output format: table, json")
+	planCmd.MarkFlagRequired("as")
+	rootCmd.AddCommand(planCmd)
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sources := []string{cfg.DefaultSource}
+	if planFrom != "" {
+		sources = strings.Split(planFrom, ",")
+		for i := range sources {
+			sources[i] = strings.TrimSpace(sources[i])
+		}
+	}
+	if len(sources) == 1 && sources[0] == "" {
+		return fmt.Errorf("no source machine specified and no default_source in config")
+	}
+
+	opts, err := planOptions(planOnly, planSkip)
+	if err != nil {
+		return err
+	}
+
+	plan, err := sync.NewWhatIfPlan(cfg, planAs, sources, planTarget, opts)
+	if err != nil {
+		return err
+	}
+
+	if planFormat == "json" {
+		return outputPlanJSON(os.Stdout, plan)
+	}
+	outputPlanTable(os.Stdout, plan)
+	return nil
+}
+
+// planOptions turns --only and --skip into the categories to plan
+func planOptions(only, skip string) (sync.Options, error) {
+	types, err := selectTypes(only, skip)
+	return sync.Options{Categories: types}, err
+}
+
+// selectTypes turns --only and --skip (package types or aliases,
+// comma-separated) into the package types to work with; nil means all
+func selectTypes(only, skip string) ([]brewfile.PackageType, error) {
+	if only == "" && skip == "" {
+		return nil, nil
+	}
+
+	types := brewfile.AllTypes()
+	if only != "" {
+		parsed, err := brewfile.ParseCategories(only)
+		if err != nil {
+			return nil, err
+		}
+		types = parsed
+	}
+	if skip != "" {
+		skipped, err := brewfile.ParseCategories(skip)
+		if err != nil {
+			return nil, err
+		}
+		types = slices.DeleteFunc(types, func(t brewfile.PackageType) bool {
+			return slices.Contains(skipped, t)
+		})
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("--only and --skip leave no package types")
+	}
+	return types, nil
+}
+
+func outputPlanJSON(w io.Writer, plan *sync.Plan) error {
+	output := map[string]interface{}{
+		"machine": plan.Machine,
+		"sources": strings.Split(plan.Source, ","),
+		"install": packageNames(plan.Additions),
+		"counts":  plan.Additions.Stats().Counts(),
+		"ignored": packageNames(plan.IgnoredAdditions),
+	}
+	if plan.CurrentBrewfile != "" {
+		output["target"] = plan.CurrentBrewfile
+	}
+	if len(plan.OtherMachineSpecific) > 0 {
+		output["machine_specific"] = packageNames(plan.OtherMachineSpecific)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+func outputPlanTable(w io.Writer, plan *sync.Plan) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Plan for %s from %s\n", plan.Machine, strings.ReplaceAll(plan.Source, ",", ", "))
+	fmt.Fprintln(w, strings.Repeat("─", 50))
+
+	if len(plan.Additions) == 0 {
+		fmt.Fprintf(w, "\nNothing to install - %s already has everything\n\n", plan.Machine)
+		return
+	}
+
+	stats := plan.Additions.Stats()
+	fmt.Fprintf(w, "\n%s TO BE INSTALLED (+%d)\n", colorGreen("▶"), stats.Total)
+	grouped := groupByType(plan.Additions)
+	for _, t := range brewfile.AllTypes() {
+		if pkgs := grouped[t]; len(pkgs) > 0 {
+			fmt.Fprintf(w, "  %s (%d): %s\n", t, len(pkgs), strings.Join(getPkgNames(pkgs), ", "))
+		}
+	}
+
+	if len(plan.IgnoredAdditions) > 0 {
+		fmt.Fprintf(w, "\n%s IGNORED (skipped: %d)\n", colorYellow("▶"), len(plan.IgnoredAdditions))
+		grouped := groupByType(plan.IgnoredAdditions)
+		for _, t := range brewfile.AllTypes() {
+			if pkgs := grouped[t]; len(pkgs) > 0 {
+				fmt.Fprintf(w, "  %s: %s\n", t, strings.Join(getPkgNames(pkgs), ", "))
+			}
+		}
+	}
+
+	if len(plan.OtherMachineSpecific) > 0 {
+		fmt.Fprintf(w, "\n%s MACHINE-SPECIFIC (other machines only: %d)\n", colorYellow("▶"), len(plan.OtherMachineSpecific))
+		fmt.Fprintf(w, "  %s\n", strings.Join(plan.OtherMachineSpecific.IDs(), ", "))
+	}
+
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/sync"
+)
+
+func TestPlanOptions(t *testing.T) {
+	opts, err := planOptions("", "")
+	require.NoError(t, err)
+	assert.Empty(t, opts.Categories)
+
+	opts, err = planOptions("cli", "go")
+	require.NoError(t, err)
+	assert.Equal(t, []brewfile.PackageType{brewfile.TypeBrew, brewfile.TypeTap}, opts.Categories)
+
+	opts, err = planOptions("", "editors,mas")
+	require.NoError(t, err)
+	assert.NotContains(t, opts.Categories, brewfile.TypeVSCode)
+	assert.NotContains(t, opts.Categories, brewfile.TypeMas)
+	assert.Contains(t, opts.Categories, brewfile.TypeCask)
+
+	_, err = planOptions("brew", "brew")
+	assert.Error(t, err)
+}
+
+func TestOutputPlan(t *testing.T) {
+	plan := &sync.Plan{
+		Machine: "studio",
+		Source:  "mini,air",
+		Additions: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeBrew, "git"),
+			brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		},
+		IgnoredAdditions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeMas, "Xcode")},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, outputPlanJSON(&buf, plan))
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, "studio", out["machine"])
+	assert.Equal(t, []interface{}{"mini", "air"}, out["sources"])
+	assert.Equal(t, map[string]interface{}{"brew": float64(1), "cask": float64(1)}, out["counts"])
+	assert.NotContains(t, out, "target")
+
+	buf.Reset()
+	outputPlanTable(&buf, plan)
+	assert.Contains(t, buf.String(), "Plan for studio from mini, air")
+	assert.Contains(t, buf.String(), "TO BE INSTALLED (+2)")
+	assert.Contains(t, buf.String(), "brew (1): git")
+	assert.Contains(t, buf.String(), "IGNORED (skipped: 1)")
+}
@@ -2,13 +2,13 @@ package cli
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/asamgx/brewsync/internal/audit"
 	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/installer"
 	"github.com/asamgx/brewsync/internal/profile"
 )
@@ -185,6 +185,22 @@ func runProfileShow(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	if len(p.Packages.Npm) > 0 {
+		fmt.Printf("npm (%d):\n", len(p.Packages.Npm))
+		for _, name := range p.Packages.Npm {
+			fmt.Printf("  %s\n", name)
+		}
+		fmt.Println()
+	}
+
+	if len(p.Packages.Pipx) > 0 {
+		fmt.Printf("pipx (%d):\n", len(p.Packages.Pipx))
+		for _, name := range p.Packages.Pipx {
+			fmt.Printf("  %s\n", name)
+		}
+		fmt.Println()
+	}
+
 	if len(p.Packages.Mas) > 0 {
 		fmt.Printf("Mac App Store (%d):\n", len(p.Packages.Mas))
 		for _, name := range p.Packages.Mas {
@@ -233,6 +249,9 @@ func runProfileInstall(cmd *cobra.Command, args []string) error {
 
 	// Install packages
 	mgr := installer.NewManager()
+	if cfg, err := config.Get(); err == nil {
+		audit.Attach(mgr, cfg, audit.CommandLine())
+	}
 	var installed, failed int
 
 	mgr.InstallMany(packages, func(pkg brewfile.Package, i, total int, err error) {
@@ -287,20 +306,7 @@ func runProfileEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile '%s' does not exist; create it first with 'brewsync profile create %s'", name, name)
 	}
 
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-	if editor == "" {
-		editor = "vi"
-	}
-
-	editorCmd := exec.Command(editor, path)
-	editorCmd.Stdin = os.Stdin
-	editorCmd.Stdout = os.Stdout
-	editorCmd.Stderr = os.Stderr
-
-	return editorCmd.Run()
+	return openInEditor(path)
 }
 
 func runProfileDelete(cmd *cobra.Command, args []string) error {
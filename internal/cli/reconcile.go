@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/sync"
+)
+
+var (
+	reconcileFrom   string
+	reconcileOnly   string
+	reconcilePull   bool
+	reconcileSkipNA bool
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Pull, diff, sync and dump in one guided flow",
+	Long: `Bring this machine in line with a source machine in one go:
+
+  1. Pull the Brewfile repository (with --pull)
+  2. Show the plan: what sync would install and remove
+  3. Ask, then apply it
+  4. Dump the Brewfile, committing and pushing as auto_dump says
+     (with --auto-dump, or auto_dump.enabled and after_install)
+
+Each step behaves as in sync and dump: --yes skips the question, --dry-run
+stops after showing the plan, and pulling follows sync.pull_strategy.
+The dump only runs when packages were installed or removed.
+
+Examples:
+  brewsync reconcile                      # Plan, confirm, apply
+  brewsync reconcile --pull --auto-dump   # The full round trip
+  brewsync reconcile --from air --only cli
+  brewsync reconcile --pull --dry-run     # Pull and show the plan only`,
+	RunE: runReconcile,
+}
+
+func init() {
+	reconcileCmd.Flags().StringVar(&reconcileFrom, "from", "", "source machine to reconcile with")
+	reconcileCmd.Flags().StringVar(&reconcileOnly, "only", "", "only sync these package types or aliases: editors, cli, apps (comma-separated)")
+	reconcileCmd.Flags().BoolVar(&reconcilePull, "pull", false, "git pull the Brewfile repository first")
+	reconcileCmd.Flags().BoolVar(&reconcileSkipNA, "skip-unavailable", false, "leave out additions Homebrew can't find (renamed or removed)")
+	reconcileCmd.Flags().BoolVar(&autoDump, "auto-dump", false, "dump the Brewfile after applying changes (or auto_dump.after_install)")
+	reconcileCmd.Flags().BoolVar(&checkBrew, "check-brew", false, "run 'brew doctor' first and abort if it finds errors (or install.check_brew)")
+	reconcileCmd.Flags().BoolVar(&noQuarantine, "no-quarantine", false, "install casks with --no-quarantine (or install.cask_no_quarantine)")
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+// reconcileSteps numbers the steps reconcile announces as it goes
+type reconcileSteps struct {
+	n, total int
+}
+
+func (s *reconcileSteps) next(format string, args ...interface{}) {
+	s.n++
+	printInfo("Step %d/%d: %s", s.n, s.total, fmt.Sprintf(format, args...))
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	currentMachine := cfg.CurrentMachine
+	if currentMachine == "" {
+		return fmt.Errorf("could not detect current machine; run 'brewsync config init' first")
+	}
+
+	source, opts, err := resolveSyncSource(cfg, reconcileFrom, reconcileOnly)
+	if err != nil {
+		return err
+	}
+
+	dumping := autoDumpsAfterApply(cfg)
+	steps := reconcileSteps{total: 2}
+	if reconcilePull {
+		steps.total++
+	}
+	if dumping {
+		steps.total++
+	}
+
+	printInfo("Reconciling %s with %s", currentMachine, source)
+
+	if reconcilePull {
+		steps.next("pull")
+		if err := pullBrewfileRepo(cfg, cfg.Machines[source].Brewfile); err != nil {
+			return err
+		}
+	}
+
+	steps.next("plan")
+	plan, err := sync.NewPlan(cfg, source, opts)
+	if err != nil {
+		return err
+	}
+	if reconcileSkipNA {
+		if _, err := skipUnavailable(plan); err != nil {
+			return err
+		}
+	}
+	if plan.IsEmpty() {
+		printInfo("Already in sync - no changes needed")
+		return nil
+	}
+	printSyncPreview(plan)
+
+	steps.next("apply")
+	if dryRun {
+		printSyncDryRun(cfg, plan, "")
+		return nil
+	}
+	changed, err := applyPlan(cfg, plan, nil)
+	if err != nil {
+		return err
+	}
+
+	if !dumping {
+		if changed {
+			printInfo("Run 'brewsync dump' (or reconcile with --auto-dump) to update the Brewfile")
+		}
+		return nil
+	}
+	steps.next("dump")
+	if !changed {
+		printInfo("Nothing changed; Brewfile left as is")
+		return nil
+	}
+	autoDumpAfterApply(cfg, currentMachine)
+	return nil
+}
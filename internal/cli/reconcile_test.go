@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/history"
+)
+
+// runGit runs git in dir and returns its output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+func TestRunReconcile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.HomeEnvVar, filepath.Join(root, "state"))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "state"), 0755))
+
+	// A Brewfile repository where air has since gained jq upstream
+	remote, local, other := filepath.Join(root, "remote.git"), filepath.Join(root, "local"), filepath.Join(root, "other")
+	runGit(t, root, "init", "--bare", "-b", "main", remote)
+	runGit(t, root, "clone", remote, other)
+	runGit(t, other, "checkout", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.mini"), []byte("brew \"git\"\nbrew \"htop\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.air"), []byte("brew \"git\"\n"), 0644))
+	runGit(t, other, "add", ".")
+	runGit(t, other, "commit", "-m", "initial")
+	runGit(t, other, "push", "-u", "origin", "main")
+	runGit(t, root, "clone", remote, local)
+	runGit(t, local, "config", "user.name", "test")
+	runGit(t, local, "config", "user.email", "test@example.com")
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.air"), []byte("brew \"git\"\nbrew \"jq\"\n"), 0644))
+	runGit(t, other, "commit", "-am", "add jq on air")
+	runGit(t, other, "push")
+
+	// brew succeeds at everything, records what it was asked, and dumps git and jq
+	calls := filepath.Join(root, "calls")
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+echo "$@" >> ` + calls + `
+case "$1" in
+  bundle) for arg; do case "$arg" in --file=*) printf 'brew "git"\nbrew "jq"\n' > "${arg#--file=}" ;; esac; done ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	miniBrewfile := filepath.Join(local, "Brewfile.mini")
+	configFile := filepath.Join(root, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+default_source: air
+default_categories: [brew]
+machines:
+  mini:
+    brewfile: `+miniBrewfile+`
+  air:
+    brewfile: `+filepath.Join(local, "Brewfile.air")+`
+auto_dump:
+  commit: true
+  commit_message: "reconcile {machine}"
+`), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() {
+		config.SetConfigPath("")
+		reconcilePull, autoDump, assumeYes, dryRun, quiet = false, false, false, false, false
+	})
+	reconcilePull, autoDump, assumeYes = true, true, true
+
+	t.Run("dry run stops after the plan", func(t *testing.T) {
+		dryRun = true
+		out := captureStdout(t, func() { require.NoError(t, runReconcile(reconcileCmd, nil)) })
+		dryRun = false
+
+		pull := strings.Index(out, "Step 1/4: pull")
+		plan := strings.Index(out, "Step 2/4: plan")
+		apply := strings.Index(out, "Step 3/4: apply")
+		require.True(t, pull >= 0 && plan > pull && apply > plan, out)
+		assert.NotContains(t, out, "Step 4/4")
+		assert.Contains(t, out, "Dry-run mode - no changes made")
+		assert.NoFileExists(t, calls)
+	})
+
+	t.Run("pull, apply and dump", func(t *testing.T) {
+		quiet = true // dump without the animation
+		require.NoError(t, runReconcile(reconcileCmd, nil))
+
+		// jq is only in air's Brewfile after the pull; the dump comes last
+		data, err := os.ReadFile(calls)
+		require.NoError(t, err)
+		var steps []string
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if strings.HasPrefix(line, "install") || strings.HasPrefix(line, "uninstall") || strings.HasPrefix(line, "bundle dump") {
+				steps = append(steps, strings.Fields(line)[0]+" "+strings.Fields(line)[len(strings.Fields(line))-1])
+			}
+		}
+		require.Len(t, steps, 3, string(data))
+		assert.Equal(t, "install jq", steps[0])
+		assert.Equal(t, "uninstall htop", steps[1])
+		assert.True(t, strings.HasPrefix(steps[2], "bundle "), steps[2])
+
+		// The Brewfile was recaptured and committed, and the sync recorded
+		pkgs, err := brewfile.Parse(miniBrewfile)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"brew:git", "brew:jq"}, pkgs.IDs())
+		assert.Contains(t, runGit(t, local, "log", "-1", "--format=%s"), "reconcile mini")
+
+		entries, err := history.Read(1)
+		require.NoError(t, err)
+		changes, ok := entries[0].SyncChanges()
+		require.True(t, ok)
+		assert.Equal(t, history.SyncChanges{Source: "air", Added: []string{"brew:jq"}, Removed: []string{"brew:htop"}}, changes)
+	})
+}
+
+func TestResolveSyncSource(t *testing.T) {
+	cfg := &config.Config{
+		CurrentMachine: "mini",
+		DefaultSource:  "air",
+		Machines: map[string]config.Machine{
+			"mini": {Brewfile: "Brewfile.mini"},
+			"air":  {Brewfile: "Brewfile.air"},
+		},
+	}
+
+	source, opts, err := resolveSyncSource(cfg, "", "cask")
+	require.NoError(t, err)
+	assert.Equal(t, "air", source)
+	assert.Equal(t, []brewfile.PackageType{brewfile.TypeCask}, opts.Categories)
+
+	tests := []struct {
+		name, current, from, defaultSource, only, want string
+	}{
+		{"no source", "mini", "", "", "", "no source machine specified"},
+		{"self", "mini", "mini", "air", "", "cannot sync from current machine"},
+		{"unknown current", "studio", "", "air", "", "current machine 'studio' not found in config"},
+		{"unknown source", "mini", "studio", "air", "", "unknown source machine: studio"},
+		{"bad category", "mini", "", "air", "bogus", "bogus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.CurrentMachine, cfg.DefaultSource = tt.current, tt.defaultSource
+			_, _, err := resolveSyncSource(cfg, tt.from, tt.only)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
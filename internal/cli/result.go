@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+// jsonResult is set when import or sync write their result as JSON to
+// stdout; progress and summaries then go to stderr so stdout stays parseable
+var jsonResult bool
+
+// infoWriter returns where informational output goes
+func infoWriter() io.Writer {
+	if jsonResult {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// applyResult is the outcome of an import or sync, written by --format json
+type applyResult struct {
+	Command    string          `json:"command"`
+	Machine    string          `json:"machine"`
+	Source     string          `json:"source,omitempty"`
+	Installed  []string        `json:"installed"`
+	Removed    []string        `json:"removed"`
+	Failed     []failedPackage `json:"failed"`
+	Skipped    []string        `json:"skipped"`
+	Protected  []string        `json:"protected"`
+	DurationMS int64           `json:"duration_ms"`
+
+	started time.Time
+}
+
+// failedPackage is a package an import or sync could not install or remove
+type failedPackage struct {
+	Package string `json:"package"`
+	Action  string `json:"action"`
+	Error   string `json:"error"`
+}
+
+// newApplyResult starts timing a result for command
+func newApplyResult(command, machine, source string) *applyResult {
+	return &applyResult{
+		Command:   command,
+		Machine:   machine,
+		Source:    source,
+		Installed: []string{},
+		Removed:   []string{},
+		Failed:    []failedPackage{},
+		Skipped:   []string{},
+		Protected: []string{},
+		started:   time.Now(),
+	}
+}
+
+// record adds the outcome of installing or uninstalling pkg. It does nothing
+// on a nil result, so callers need not check whether JSON was requested.
+func (r *applyResult) record(action string, pkg brewfile.Package, err error) {
+	if r == nil {
+		return
+	}
+	switch {
+	case err != nil:
+		r.Failed = append(r.Failed, failedPackage{Package: pkg.ID(), Action: action, Error: failureReason(err)})
+	case action == installer.ActionUninstall:
+		r.Removed = append(r.Removed, pkg.ID())
+	default:
+		r.Installed = append(r.Installed, pkg.ID())
+	}
+}
+
+// skip records packages left out of the run
+func (r *applyResult) skip(pkgs brewfile.Packages) {
+	if r != nil {
+		r.Skipped = append(r.Skipped, pkgs.IDs()...)
+	}
+}
+
+// protect records packages kept even though the source lacks them
+func (r *applyResult) protect(pkgs brewfile.Packages) {
+	if r != nil {
+		r.Protected = append(r.Protected, pkgs.IDs()...)
+	}
+}
+
+// write finishes timing the result and encodes it to w
+func (r *applyResult) write(w io.Writer) error {
+	r.DurationMS = time.Since(r.started).Milliseconds()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// checkResultFormat validates a --format value for import or sync and turns
+// on JSON output. JSON mode can't ask for confirmation, so it needs --yes,
+// and it reports applied changes, so it can't be combined with --dry-run.
+func checkResultFormat(format string) error {
+	switch format {
+	case "", "table":
+		return nil
+	case "json":
+	default:
+		return fmt.Errorf("unknown format %q (use table or json)", format)
+	}
+	if dryRun {
+		return fmt.Errorf("--format json reports applied changes and cannot be combined with --dry-run")
+	}
+	if !assumeYes {
+		return fmt.Errorf("--format json cannot prompt for confirmation; pass --yes")
+	}
+	jsonResult = true
+	return nil
+}
+
+// writeResult writes result to stdout when JSON output was requested
+func writeResult(result *applyResult) error {
+	if result == nil {
+		return nil
+	}
+	return result.write(os.Stdout)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// setupResultTest writes a brew that fails to install "broken" and a config
+// syncing mini from air, returning mini's Brewfile
+func setupResultTest(t *testing.T, mini, air string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+  *install*broken*) echo "Error: No available formula with the name \"broken\"." >&2; exit 1 ;;
+esac
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "brew"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	miniBrewfile := filepath.Join(dir, "Brewfile.mini")
+	airBrewfile := filepath.Join(dir, "Brewfile.air")
+	require.NoError(t, os.WriteFile(miniBrewfile, []byte(mini), 0644))
+	require.NoError(t, os.WriteFile(airBrewfile, []byte(air), 0644))
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`current_machine: mini
+default_source: air
+pinned: ["brew:node"]
+machines:
+  mini:
+    brewfile: `+miniBrewfile+`
+  air:
+    brewfile: `+airBrewfile+"\n"), 0644))
+	config.SetConfigPath(configFile)
+	config.SetIgnorePath(filepath.Join(dir, "ignore.yaml"))
+	t.Cleanup(func() {
+		config.SetConfigPath("")
+		config.SetIgnorePath("")
+	})
+	require.NoError(t, config.AddPackageIgnore("mini", "brew:htop", false))
+
+	assumeYes = true
+	t.Cleanup(func() { assumeYes, jsonResult = false, false })
+	return miniBrewfile
+}
+
+func decodeResult(t *testing.T, out string) applyResult {
+	t.Helper()
+	var result applyResult
+	require.NoError(t, json.Unmarshal([]byte(out), &result), out)
+	return result
+}
+
+func TestRunSync_JSONResult(t *testing.T) {
+	setupResultTest(t,
+		"brew \"git\"\nbrew \"wget\"\nbrew \"node\"\n",
+		"brew \"git\"\nbrew \"jq\"\nbrew \"broken\"\nbrew \"htop\"\n")
+
+	syncApply, syncFormat = true, "json"
+	t.Cleanup(func() { syncApply, syncFormat = false, "table" })
+
+	out := captureStdout(t, func() { require.NoError(t, runSync(syncCmd, nil)) })
+	result := decodeResult(t, out)
+
+	assert.Equal(t, "sync", result.Command)
+	assert.Equal(t, "mini", result.Machine)
+	assert.Equal(t, "air", result.Source)
+	assert.Equal(t, []string{"brew:jq"}, result.Installed)
+	assert.Equal(t, []string{"brew:wget"}, result.Removed)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "brew:broken", result.Failed[0].Package)
+	assert.Equal(t, "install", result.Failed[0].Action)
+	assert.NotEmpty(t, result.Failed[0].Error)
+	assert.Equal(t, []string{"brew:htop"}, result.Skipped)
+	assert.Equal(t, []string{"brew:node"}, result.Protected)
+	assert.GreaterOrEqual(t, result.DurationMS, int64(0))
+
+	// Lists are always present so automation needn't check for null
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &raw))
+	for _, key := range []string{"installed", "removed", "failed", "skipped", "protected", "duration_ms"} {
+		assert.Contains(t, raw, key)
+	}
+}
+
+func TestRunSync_JSONResultNeedsApplyAndYes(t *testing.T) {
+	setupResultTest(t, "brew \"git\"\n", "brew \"git\"\nbrew \"jq\"\n")
+	syncFormat = "json"
+	t.Cleanup(func() { syncFormat = "table" })
+
+	assert.ErrorContains(t, runSync(syncCmd, nil), "--apply")
+
+	assumeYes, syncApply = false, true
+	t.Cleanup(func() { syncApply = false })
+	assert.ErrorContains(t, runSync(syncCmd, nil), "--yes")
+}
+
+func TestRunImport_JSONResult(t *testing.T) {
+	setupResultTest(t,
+		"brew \"git\"\n",
+		"brew \"git\"\nbrew \"jq\"\nbrew \"broken\"\nbrew \"htop\"\n")
+
+	importFormat = "json"
+	t.Cleanup(func() { importFormat = "table" })
+
+	out := captureStdout(t, func() { require.NoError(t, runImport(importCmd, nil)) })
+	result := decodeResult(t, out)
+
+	assert.Equal(t, "import", result.Command)
+	assert.Equal(t, "air", result.Source)
+	assert.Equal(t, []string{"brew:jq"}, result.Installed)
+	assert.Empty(t, result.Removed)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "brew:broken", result.Failed[0].Package)
+	assert.Equal(t, []string{"brew:htop"}, result.Skipped)
+}
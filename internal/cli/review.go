@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// reviewHeader explains the review file to the user
+const reviewHeader = `# brewsync import plan
+#
+# One package per line (type:name). Everything left uncommented is installed;
+# delete a line or comment it out with '#' to skip it. Ignored packages are
+# listed commented out. Save and close the editor to continue, or leave no
+# lines to cancel.
+`
+
+// reviewPackages writes pkgs to a temp file, opens it in the user's editor and
+// returns the packages still listed when the editor exits. Packages in ignored
+// start out commented. Lines that don't name a package from pkgs are reported
+// and skipped, so only planned packages can be installed.
+func reviewPackages(pkgs brewfile.Packages, ignored map[string]bool) (brewfile.Packages, error) {
+	file, err := os.CreateTemp("", "brewsync-import-*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create review file: %w", err)
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	_, err = file.WriteString(formatReview(pkgs, ignored))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write review file: %w", err)
+	}
+
+	if err := openInEditor(path); err != nil {
+		return nil, fmt.Errorf("editor failed: %w", err)
+	}
+
+	edited, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review file: %w", err)
+	}
+	defer edited.Close()
+
+	selected, unknown, err := parseReview(edited, pkgs)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range unknown {
+		printWarning("Skipping %q: not in the import plan", line)
+	}
+	return selected, nil
+}
+
+// formatReview renders the review file content
+func formatReview(pkgs brewfile.Packages, ignored map[string]bool) string {
+	var sb strings.Builder
+	sb.WriteString(reviewHeader)
+	sb.WriteString("\n")
+	for _, pkg := range pkgs {
+		if ignored[pkg.ID()] {
+			sb.WriteString(fmt.Sprintf("# %s (ignored)\n", pkg.ID()))
+		} else {
+			sb.WriteString(pkg.ID() + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// parseReview returns the packages from pkgs named by uncommented lines, in file
+// order and without duplicates, plus any lines that matched no package
+func parseReview(r io.Reader, pkgs brewfile.Packages) (brewfile.Packages, []string, error) {
+	byID := make(map[string]brewfile.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		byID[pkg.ID()] = pkg
+	}
+
+	var selected brewfile.Packages
+	var unknown []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pkg, ok := byID[line]
+		if !ok {
+			unknown = append(unknown, line)
+			continue
+		}
+		if !seen[line] {
+			seen[line] = true
+			selected = append(selected, pkg)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read review file: %w", err)
+	}
+	return selected, unknown, nil
+}
+
+// openInEditor opens path in $EDITOR (or $VISUAL, falling back to vi) and waits
+// for it to exit. The editor value may include arguments, e.g. "code --wait".
+func openInEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	fields := strings.Fields(editor)
+	editorCmd := exec.Command(fields[0], append(fields[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	return editorCmd.Run()
+}
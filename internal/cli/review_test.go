@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// fakeEditor installs a script as $EDITOR that runs sedExpr over the file it is given
+func fakeEditor(t *testing.T, sedExpr string) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "editor")
+	content := "#!/bin/sh\nsed '" + sedExpr + "' \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"
+	require.NoError(t, os.WriteFile(script, []byte(content), 0755))
+	t.Setenv("EDITOR", script)
+}
+
+func TestReviewPackages(t *testing.T) {
+	fakeEditor(t, `s/^cask:zoom/# cask:zoom/; s/^brew:wget/#brew:wget/`)
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "wget"),
+		brewfile.NewPackage(brewfile.TypeCask, "zoom"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "golang.go"),
+	}
+	ignored := map[string]bool{"vscode:golang.go": true}
+
+	selected, err := reviewPackages(pkgs, ignored)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git", "cask:firefox"}, packageIDs(selected))
+}
+
+func TestReviewPackages_UncommentIgnored(t *testing.T) {
+	fakeEditor(t, `s/^# vscode:golang.go (ignored)/vscode:golang.go/`)
+
+	pkgs := brewfile.Packages{brewfile.NewPackage(brewfile.TypeVSCode, "golang.go")}
+
+	selected, err := reviewPackages(pkgs, map[string]bool{"vscode:golang.go": true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vscode:golang.go"}, packageIDs(selected))
+}
+
+func TestReviewPackages_EditorFails(t *testing.T) {
+	t.Setenv("EDITOR", "false")
+
+	_, err := reviewPackages(brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "editor failed")
+}
+
+func TestParseReview(t *testing.T) {
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+	}
+	input := `# header
+cask:firefox
+
+  brew:git
+cask:firefox
+brew:unknown
+`
+
+	selected, unknown, err := parseReview(strings.NewReader(input), pkgs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cask:firefox", "brew:git"}, packageIDs(selected))
+	assert.Equal(t, []string{"brew:unknown"}, unknown)
+}
+
+func packageIDs(pkgs brewfile.Packages) []string {
+	var ids []string
+	for _, pkg := range pkgs {
+		ids = append(ids, pkg.ID())
+	}
+	return ids
+}
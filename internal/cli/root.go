@@ -1,27 +1,37 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/cobra"
 
+	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/exec"
+	"github.com/asamgx/brewsync/internal/notify"
 	"github.com/asamgx/brewsync/internal/tui/app"
 	"github.com/asamgx/brewsync/pkg/version"
 )
 
 var (
 	// Global flags
-	cfgFile   string
-	dryRun    bool
-	verbose   bool
-	quiet     bool
-	noColor   bool
-	assumeYes bool
+	cfgFile    string
+	dryRun     bool
+	verbose    bool
+	quiet      bool
+	noColor    bool
+	assumeYes  bool
+	notifyDone bool
+	traceTo    string
+
+	// closeTrace closes the --trace / BREWSYNC_TRACE log file, if any
+	closeTrace = func() error { return nil }
 )
 
 // Catppuccin Mocha color palette
@@ -79,6 +89,16 @@ Brewfile, and provides commands to import, sync, and diff packages
 between machines.`,
 	Version: version.Version,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Log every external command if requested
+		target := traceTo
+		if target == "" {
+			target = os.Getenv(exec.TraceEnvVar)
+		}
+		var err error
+		if closeTrace, err = exec.OpenTrace(target); err != nil {
+			return err
+		}
+
 		// Skip config loading for version command
 		if cmd.Name() == "version" || cmd.Name() == "help" {
 			return nil
@@ -89,8 +109,39 @@ between machines.`,
 			config.SetConfigPath(cfgFile)
 		}
 
+		// Offer to move state from ~/.config/brewsync after BREWSYNC_HOME or XDG_CONFIG_HOME changed;
+		// not while completing, nor when --config points elsewhere
+		if cfgFile == "" && !isCompletionCommand(cmd) {
+			if err := offerStateMigration(); err != nil {
+				return err
+			}
+		}
+
 		// Initialize config
-		return config.Init()
+		if err := config.Init(); err != nil {
+			return err
+		}
+
+		// Surface config problems that don't stop the command (doctor lists them itself)
+		if config.Exists() {
+			if cfg, err := config.Load(); err == nil {
+				if err := brewfile.SetIconStyle(cfg.Output.Icons); err != nil {
+					printWarning("output.icons: %v", err)
+				}
+				for _, name := range unknownDefaultCommands(cmd.Root(), cfg.Defaults) {
+					printWarning("defaults: unknown command %q", name)
+				}
+				if err := applyFlagDefaults(cmd, cfg.Defaults); err != nil {
+					return err
+				}
+				if cmd.Name() != "doctor" {
+					for _, warning := range cfg.Warnings() {
+						printWarning("%s", warning)
+					}
+				}
+			}
+		}
+		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Launch the full TUI when no subcommand is provided
@@ -110,17 +161,18 @@ func runMainTUI() error {
 	var cfg *config.Config
 	var err error
 
-	// Check if config exists
-	if config.Exists() {
-		debug.Log("runMainTUI: config exists, loading...")
-		cfg, err = config.Load()
-		if err != nil {
-			debug.Log("runMainTUI: config load error: %v", err)
-			return fmt.Errorf("failed to load config: %w", err)
-		}
-		debug.Log("runMainTUI: config loaded, current machine: %s", cfg.CurrentMachine)
-	} else {
+	// A missing config starts the setup wizard; an existing one without machines
+	// starts on the config screen instead (see app.New)
+	cfg, err = config.LoadExisting()
+	switch {
+	case errors.Is(err, config.ErrNotFound):
 		debug.Log("runMainTUI: config does not exist, will start setup wizard")
+		cfg = nil
+	case err != nil:
+		debug.Log("runMainTUI: config load error: %v", err)
+		return fmt.Errorf("failed to load config: %w", err)
+	default:
+		debug.Log("runMainTUI: config loaded, current machine: %s", cfg.CurrentMachine)
 	}
 
 	// Create and run the TUI
@@ -137,21 +189,76 @@ func runMainTUI() error {
 	return err
 }
 
+// isCompletionCommand reports whether cmd is cobra's shell completion, which
+// must print nothing but completions
+func isCompletionCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, "completion":
+			return true
+		}
+	}
+	return false
+}
+
+// promptIsTerminal reports whether a question can be asked on stderr and
+// answered on stdin; a variable so tests can stub it
+var promptIsTerminal = func() bool {
+	return term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stderr.Fd())
+}
+
+// offerStateMigration copies state from the legacy directory to the one set
+// by $BREWSYNC_HOME or $XDG_CONFIG_HOME when the new one is still empty. It
+// asks first on stderr, unless --yes is set; without a terminal it only
+// prints a hint. Stdout is left to the command.
+func offerStateMigration() error {
+	from, to, pending := config.PendingStateMigration()
+	if !pending {
+		return nil
+	}
+
+	if !assumeYes {
+		if !promptIsTerminal() {
+			printWarning("brewsync state is in %s but %s is empty; run interactively or with --yes to copy it", from, to)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "brewsync now keeps its state in %s, but it is empty.\nCopy config, ignores and history from %s? [Y/n] ", to, from)
+		var response string
+		fmt.Scanln(&response)
+		if response != "" && response != "y" && response != "Y" {
+			return nil
+		}
+	}
+
+	if err := config.MigrateState(from, to); err != nil {
+		return err
+	}
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "Copied brewsync state to %s; %s can be removed\n", to, from)
+	}
+	return nil
+}
+
 // Execute runs the root command
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	closeTrace()
+	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default ~/.config/brewsync/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default $BREWSYNC_HOME/config.yaml or ~/.config/brewsync/config.yaml, or $BREWSYNC_CONFIG)")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "preview without executing")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "detailed output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "minimal output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
 	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "skip confirmations")
+	rootCmd.PersistentFlags().BoolVar(&notifyDone, "notify", false, "ring the bell and show a notification when a long operation finishes")
+	rootCmd.PersistentFlags().StringVar(&traceTo, "trace", "", "log every external command to stderr, or to a file with --trace=<path> (or $BREWSYNC_TRACE)")
+	rootCmd.PersistentFlags().Lookup("trace").NoOptDefVal = "stderr"
 
 	// Add subcommands
 	rootCmd.AddCommand(dumpCmd)
@@ -160,14 +267,24 @@ func init() {
 // printInfo prints an info message (respects quiet flag)
 func printInfo(format string, args ...interface{}) {
 	if !quiet {
-		fmt.Printf(format+"\n", args...)
+		fmt.Fprintf(infoWriter(), format+"\n", args...)
+	}
+}
+
+// notifyFinished sends a completion notification if enabled by --notify or output.notify
+func notifyFinished(cfg *config.Config, message string) {
+	if !notifyDone && (cfg == nil || !cfg.Output.Notify) {
+		return
+	}
+	if err := notify.NewNotifier().Notify(message); err != nil {
+		printVerbose("Notification failed: %v", err)
 	}
 }
 
 // printVerbose prints a verbose message (respects verbose flag)
 func printVerbose(format string, args ...interface{}) {
 	if verbose && !quiet {
-		fmt.Printf(format+"\n", args...)
+		fmt.Fprintf(infoWriter(), format+"\n", args...)
 	}
 }
 
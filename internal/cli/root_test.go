@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// executeRoot runs brewsync with args and returns what it printed to stdout
+func executeRoot(t *testing.T, args ...string) string {
+	t.Helper()
+	t.Cleanup(func() {
+		cfgFile, listFormat = "", "table"
+		config.SetConfigPath("")
+		rootCmd.SetArgs(nil)
+	})
+	rootCmd.SetArgs(args)
+	return captureStdout(t, func() { require.NoError(t, rootCmd.Execute()) })
+}
+
+func TestConfigFlag(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.HomeEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	dir := t.TempDir()
+	brewfile := filepath.Join(dir, "Brewfile.mini")
+	require.NoError(t, os.WriteFile(brewfile, []byte("brew \"ripgrep\"\ncask \"orbstack\"\n"), 0644))
+	configFile := filepath.Join(dir, "alt.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\nmachines:\n  mini:\n    brewfile: "+brewfile+"\n"), 0644))
+
+	t.Run("list reads the machines from it", func(t *testing.T) {
+		out := executeRoot(t, "--config", configFile, "list", "--format", "csv")
+		assert.Equal(t, "type,name,description\nbrew,ripgrep,\ncask,orbstack,\n", out)
+	})
+
+	t.Run("ignore.yaml lives next to it", func(t *testing.T) {
+		out := executeRoot(t, "--config", configFile, "ignore", "path")
+		assert.Equal(t, filepath.Join(dir, "ignore.yaml"), strings.TrimSpace(out))
+	})
+
+	t.Run("the default config is left alone", func(t *testing.T) {
+		_, err := os.Stat(filepath.Join(home, ".config", "brewsync", "config.yaml"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestOfferStateMigration_LeavesStdoutAlone(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	legacy := filepath.Join(home, ".config", "brewsync")
+	newDir := filepath.Join(t.TempDir(), "brewsync")
+	t.Setenv(config.HomeEnvVar, newDir)
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("machines: {}\n"), 0644))
+
+	// A terminal would be asked, so every case below must skip the question
+	orig := promptIsTerminal
+	promptIsTerminal = func() bool { return true }
+	t.Cleanup(func() { promptIsTerminal = orig })
+
+	t.Run("shell completion", func(t *testing.T) {
+		out := executeRoot(t, "__complete", "ignore", "")
+		assert.NotContains(t, out, "brewsync now keeps its state")
+		assert.NoDirExists(t, newDir)
+	})
+
+	t.Run("explicit --config", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "alt.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("machines: {}\n"), 0644))
+		out := executeRoot(t, "--config", configFile, "ignore", "path")
+		assert.Equal(t, filepath.Join(filepath.Dir(configFile), "ignore.yaml"), strings.TrimSpace(out))
+		assert.NoDirExists(t, newDir)
+	})
+
+	t.Run("no terminal only hints", func(t *testing.T) {
+		promptIsTerminal = func() bool { return false }
+		out := executeRoot(t, "ignore", "path")
+		assert.Equal(t, filepath.Join(newDir, "ignore.yaml"), strings.TrimSpace(out))
+		assert.NoDirExists(t, newDir)
+	})
+
+	t.Run("--yes copies without a word on stdout", func(t *testing.T) {
+		t.Cleanup(func() { assumeYes = false })
+		out := executeRoot(t, "--yes", "ignore", "path")
+		assert.Equal(t, filepath.Join(newDir, "ignore.yaml"), strings.TrimSpace(out))
+		assert.FileExists(t, filepath.Join(newDir, "config.yaml"))
+	})
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+// parseSince turns a --since value into a cutoff time. It accepts an age
+// relative to now ("12h", "7d", "2w") or a date ("2026-01-31").
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if n := len(value); n > 1 {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			switch value[n-1] {
+			case 'd':
+				return now.AddDate(0, 0, -count), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*count), nil
+			}
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since value %q (use an age like 7d, 2w, 12h or a date like 2026-01-31)", value)
+}
+
+// installedSince looks up local install times for pkgs and keeps those
+// installed after cutoff. Packages brew has no install time for (taps,
+// extensions, Go tools, App Store apps) are returned as undated.
+func installedSince(pkgs brewfile.Packages, cutoff time.Time) (recent, undated brewfile.Packages, err error) {
+	if err := installer.NewBrewInstaller().FillInstallTimes(pkgs); err != nil {
+		return nil, nil, fmt.Errorf("failed to read install times: %w", err)
+	}
+	recent, undated = pkgs.InstalledSince(cutoff)
+	return recent, undated, nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2026-01-31", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			cutoff, err := parseSince(tc.input, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cutoff)
+		})
+	}
+
+	for _, bad := range []string{"", "d", "week", "-3d", "2026-13-01"} {
+		_, err := parseSince(bad, now)
+		assert.Error(t, err, bad)
+	}
+}
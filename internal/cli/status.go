@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,11 +26,28 @@ Displays:
   - Current machine identification
   - Package counts by type
   - Pending changes from default source (if configured)
-  - Last dump/sync times (from metadata)`,
+  - Last dump/sync times (from metadata)
+
+To check against several machines at once, list them under status_sources
+in config or use --all-sources. Pending additions are then grouped by the
+source they come from, and removals are packages no source has.
+
+Examples:
+  brewsync status                # Pending changes from default_source
+  brewsync status --all-sources  # In sync with every other machine?
+  brewsync status --format csv   # Per-type counts and pending changes`,
 	RunE: runStatus,
 }
 
+var (
+	statusAllSources bool
+	statusFormat     string
+)
+
 func init() {
+	statusCmd.Flags().BoolVar(&statusAllSources, "all-sources", false, "compare against every other configured machine")
+	statusCmd.Flags().StringVar(&statusFormat, "format", "table", "output format: table, csv")
+	statusCmd.Flags().BoolVar(&noPager, "no-pager", false, "don't page long output through $PAGER (or output.pager)")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -91,6 +110,15 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if statusFormat == "csv" {
+		packages, err := brewfile.Parse(machine.Brewfile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to parse Brewfile: %w", err)
+		}
+		diff, _, _ := statusPending(cfg, statusSourceNames(cfg, statusAllSources), packages)
+		return outputStatusCSV(currentMachine, packages, diff)
+	}
+
 	// Build all content in a single box
 	var allLines []string
 
@@ -123,8 +151,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		allLines = append(allLines, formatStatusLine("  ", "Hostname", machine.Hostname, catText))
 	}
 	allLines = append(allLines, formatStatusLine("  ", "Brewfile", machine.Brewfile, catSubtext0))
-	if cfg.DefaultSource != "" {
-		allLines = append(allLines, formatStatusLine("  ", "Source", cfg.DefaultSource, catText))
+	sourceNames := statusSourceNames(cfg, statusAllSources)
+	if len(sourceNames) > 0 {
+		allLines = append(allLines, formatStatusLine("  ", "Source", strings.Join(sourceNames, ", "), catText))
 	}
 
 	// Package stats section
@@ -201,27 +230,22 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Pending changes (if any) - excluding ignored items
-	if cfg.DefaultSource != "" && cfg.DefaultSource != currentMachine && packages != nil {
-		sourceMachine, ok := cfg.Machines[cfg.DefaultSource]
-		if ok {
-			sourcePackages, err := brewfile.Parse(sourceMachine.Brewfile)
-			if err == nil {
-				diff := brewfile.Diff(sourcePackages, packages)
-
-				// Filter out ignored packages and categories
-				diff = filterIgnoredFromDiff(diff, ignoredCategories, ignoredPkgs)
-
-				if !diff.IsEmpty() {
-					allLines = append(allLines, "")
-					pendingHeader := lipgloss.NewStyle().
-						Foreground(catYellow).
-						Bold(true).
-						Render(fmt.Sprintf("⚡ Pending from %s", cfg.DefaultSource))
-					allLines = append(allLines, pendingHeader)
-					allLines = append(allLines, "")
-					allLines = append(allLines, formatPendingDetailed(diff))
-				}
-			}
+	if diff, multi, loaded := statusPending(cfg, sourceNames, packages); diff != nil && !diff.IsEmpty() {
+		title := fmt.Sprintf("⚡ Pending from %s", strings.Join(sourceNames, ", "))
+		if loaded > 1 {
+			title = fmt.Sprintf("⚡ Pending from %d sources", loaded)
+		}
+		allLines = append(allLines, "")
+		pendingHeader := lipgloss.NewStyle().
+			Foreground(catYellow).
+			Bold(true).
+			Render(title)
+		allLines = append(allLines, pendingHeader)
+		allLines = append(allLines, "")
+		allLines = append(allLines, formatPendingDetailed(diff))
+		if loaded > 1 {
+			allLines = append(allLines, "")
+			allLines = append(allLines, formatPendingBySource(multi.AdditionsBySource(diff.Additions)))
 		}
 	}
 
@@ -232,15 +256,47 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		Padding(1, 2).
 		Width(tableWidth)
 
-	fmt.Println()
-	fmt.Println(statusBox.Render(strings.Join(allLines, "\n")))
-	fmt.Println()
+	return withPager(cfg, statusFormat, func() error {
+		fmt.Println()
+		fmt.Println(statusBox.Render(strings.Join(allLines, "\n")))
+		fmt.Println()
+		return nil
+	})
+}
+
+// statusPending diffs packages against the Brewfiles of sourceNames, leaving
+// out ignored packages and categories. It returns the filtered diff, the full
+// multi-source result and how many source Brewfiles could be read; the diff
+// is nil when there is nothing to compare against.
+func statusPending(cfg *config.Config, sourceNames []string, packages brewfile.Packages) (*brewfile.DiffResult, *brewfile.MultiDiffResult, int) {
+	if len(sourceNames) == 0 || packages == nil {
+		return nil, nil, 0
+	}
 
-	return nil
+	sources := make(map[string]brewfile.Packages)
+	for _, name := range sourceNames {
+		sourceMachine, ok := cfg.Machines[name]
+		if !ok {
+			continue
+		}
+		sourcePackages, err := brewfile.Parse(sourceMachine.Brewfile)
+		if err != nil {
+			printVerbose("Skipping source %s: %v", name, err)
+			continue
+		}
+		sources[name] = sourcePackages
+	}
+	if len(sources) == 0 {
+		return nil, nil, 0
+	}
+
+	multi := brewfile.DiffSources(sources, packages, cfg.ExtensionAliases)
+	diff := filterIgnoredFromDiff(multi.DiffResult, cfg.GetIgnoredCategories(cfg.CurrentMachine), cfg.GetIgnoredPackages(cfg.CurrentMachine))
+	return diff, multi, len(sources)
 }
 
 func printPackageCounts(packages brewfile.Packages) {
-	byType := packages.ByType()
+	stats := packages.Stats()
 	typeOrder := []brewfile.PackageType{
 		brewfile.TypeTap,
 		brewfile.TypeBrew,
@@ -248,12 +304,14 @@ func printPackageCounts(packages brewfile.Packages) {
 		brewfile.TypeVSCode,
 		brewfile.TypeCursor,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
 	var parts []string
 	for _, t := range typeOrder {
-		count := len(byType[t])
+		count := stats.Count(t)
 		if count > 0 {
 			parts = append(parts, fmt.Sprintf("%s: %d", t, count))
 		}
@@ -357,24 +415,22 @@ func formatStatusLine(icon, label, value string, color lipgloss.Color) string {
 }
 
 func formatPackageCountsCompact(packages brewfile.Packages) string {
-	byType := packages.ByType()
-
-	total := len(packages)
+	stats := packages.Stats()
 	var counts []string
 
-	if c := len(byType[brewfile.TypeBrew]); c > 0 {
+	if c := stats.Count(brewfile.TypeBrew); c > 0 {
 		counts = append(counts, fmt.Sprintf("%d brew", c))
 	}
-	if c := len(byType[brewfile.TypeCask]); c > 0 {
+	if c := stats.Count(brewfile.TypeCask); c > 0 {
 		counts = append(counts, fmt.Sprintf("%d cask", c))
 	}
-	if c := len(byType[brewfile.TypeVSCode]); c > 0 {
+	if c := stats.Count(brewfile.TypeVSCode); c > 0 {
 		counts = append(counts, fmt.Sprintf("%d vscode", c))
 	}
-	if c := len(byType[brewfile.TypeCursor]); c > 0 {
+	if c := stats.Count(brewfile.TypeCursor); c > 0 {
 		counts = append(counts, fmt.Sprintf("%d cursor", c))
 	}
-	if c := len(byType[brewfile.TypeAntigravity]); c > 0 {
+	if c := stats.Count(brewfile.TypeAntigravity); c > 0 {
 		counts = append(counts, fmt.Sprintf("%d antigravity", c))
 	}
 
@@ -382,7 +438,7 @@ func formatPackageCountsCompact(packages brewfile.Packages) string {
 		return "none"
 	}
 
-	return fmt.Sprintf("%d total (%s)", total, strings.Join(counts, ", "))
+	return fmt.Sprintf("%d total (%s)", stats.Total, strings.Join(counts, ", "))
 }
 
 func formatPendingCompact(diff *brewfile.DiffResult) string {
@@ -417,20 +473,11 @@ func formatPendingCompact(diff *brewfile.DiffResult) string {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	typeIcons := map[brewfile.PackageType]string{
-		brewfile.TypeTap:         "🚰",
-		brewfile.TypeBrew:        "🍺",
-		brewfile.TypeCask:        "📦",
-		brewfile.TypeVSCode:      "💻",
-		brewfile.TypeCursor:      "✏️",
-		brewfile.TypeAntigravity: "🚀",
-		brewfile.TypeGo:          "🔷",
-		brewfile.TypeMas:         "🍎",
-	}
-
 	addByType := diff.AdditionsByType()
 	remByType := diff.RemovalsByType()
 
@@ -442,7 +489,7 @@ func formatPendingCompact(diff *brewfile.DiffResult) string {
 			continue
 		}
 
-		icon := typeIcons[t]
+		icon := brewfile.Meta(t).Icon
 		var typeParts []string
 
 		if adds > 0 {
@@ -467,7 +514,7 @@ func formatPendingCompact(diff *brewfile.DiffResult) string {
 
 // formatPackageCountsDetailed formats package counts with individual lines per type
 func formatPackageCountsDetailed(packages brewfile.Packages) string {
-	byType := packages.ByType()
+	stats := packages.Stats()
 	typeOrder := []brewfile.PackageType{
 		brewfile.TypeTap,
 		brewfile.TypeBrew,
@@ -476,32 +523,32 @@ func formatPackageCountsDetailed(packages brewfile.Packages) string {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	typeInfo := map[brewfile.PackageType]struct {
-		icon  string
-		color lipgloss.Color
-	}{
-		brewfile.TypeTap:         {"🚰", catTeal},
-		brewfile.TypeBrew:        {"🍺", catYellow},
-		brewfile.TypeCask:        {"📦", catPeach},
-		brewfile.TypeVSCode:      {"💻", catBlue},
-		brewfile.TypeCursor:      {"✏️ ", catMauve},
-		brewfile.TypeAntigravity: {"🚀", catPink},
-		brewfile.TypeGo:          {"🔷", catSapphire},
-		brewfile.TypeMas:         {"🍎", catRed},
+	typeColors := map[brewfile.PackageType]lipgloss.Color{
+		brewfile.TypeTap:         catTeal,
+		brewfile.TypeBrew:        catYellow,
+		brewfile.TypeCask:        catPeach,
+		brewfile.TypeVSCode:      catBlue,
+		brewfile.TypeCursor:      catMauve,
+		brewfile.TypeAntigravity: catPink,
+		brewfile.TypeGo:          catSapphire,
+		brewfile.TypeNpm:         catMaroon,
+		brewfile.TypePipx:        catGreen,
+		brewfile.TypeMas:         catRed,
 	}
 
 	var lines []string
-	total := len(packages)
 
 	for _, t := range typeOrder {
-		if pkgs, ok := byType[t]; ok && len(pkgs) > 0 {
-			info := typeInfo[t]
-			icon := lipgloss.NewStyle().Foreground(info.color).Render(info.icon)
+		if n := stats.Count(t); n > 0 {
+			color := typeColors[t]
+			icon := lipgloss.NewStyle().Foreground(color).Render(brewfile.Meta(t).Icon)
 			label := lipgloss.NewStyle().Foreground(catText).Render(string(t))
-			count := lipgloss.NewStyle().Foreground(catGreen).Bold(true).Render(fmt.Sprintf("%d", len(pkgs)))
+			count := lipgloss.NewStyle().Foreground(catGreen).Bold(true).Render(fmt.Sprintf("%d", n))
 			lines = append(lines, fmt.Sprintf("  %s %s: %s", icon, label, count))
 		}
 	}
@@ -510,7 +557,7 @@ func formatPackageCountsDetailed(packages brewfile.Packages) string {
 	totalLine := lipgloss.NewStyle().
 		Foreground(catGreen).
 		Bold(true).
-		Render(fmt.Sprintf("  Total: %d packages", total))
+		Render(fmt.Sprintf("  Total: %d packages", stats.Total))
 	lines = append(lines, "", totalLine)
 
 	return strings.Join(lines, "\n")
@@ -552,20 +599,11 @@ func formatPendingDetailed(diff *brewfile.DiffResult) string {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	typeIcons := map[brewfile.PackageType]string{
-		brewfile.TypeTap:         "🚰",
-		brewfile.TypeBrew:        "🍺",
-		brewfile.TypeCask:        "📦",
-		brewfile.TypeVSCode:      "💻",
-		brewfile.TypeCursor:      "✏️",
-		brewfile.TypeAntigravity: "🚀",
-		brewfile.TypeGo:          "🔷",
-		brewfile.TypeMas:         "🍎",
-	}
-
 	addByType := diff.AdditionsByType()
 	remByType := diff.RemovalsByType()
 
@@ -577,7 +615,7 @@ func formatPendingDetailed(diff *brewfile.DiffResult) string {
 			continue
 		}
 
-		icon := typeIcons[t]
+		icon := brewfile.Meta(t).Icon
 		var typeParts []string
 
 		if adds > 0 {
@@ -600,6 +638,58 @@ func formatPendingDetailed(diff *brewfile.DiffResult) string {
 	return strings.Join(lines, "\n")
 }
 
+// statusSourceNames returns the machines status compares against: every other
+// machine with --all-sources, else status_sources, else default_source.
+// The current machine is never its own source.
+func statusSourceNames(cfg *config.Config, all bool) []string {
+	var candidates []string
+	switch {
+	case all:
+		for name := range cfg.Machines {
+			candidates = append(candidates, name)
+		}
+		sort.Strings(candidates)
+	case len(cfg.StatusSources) > 0:
+		candidates = cfg.StatusSources
+	case cfg.DefaultSource != "":
+		candidates = []string{cfg.DefaultSource}
+	}
+
+	var names []string
+	for _, name := range candidates {
+		if name != cfg.CurrentMachine {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// formatPendingBySource formats pending additions grouped by the source they come from
+func formatPendingBySource(bySource map[string]brewfile.Packages) string {
+	names := make([]string, 0, len(bySource))
+	for name := range bySource {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		pkgs := bySource[name]
+		label := lipgloss.NewStyle().Foreground(catMauve).Bold(true).Render(name)
+		count := lipgloss.NewStyle().Foreground(catGreen).Render(fmt.Sprintf("+%d", len(pkgs)))
+		lines = append(lines, fmt.Sprintf("  ▶ %s %s", label, count))
+
+		ids := make([]string, len(pkgs))
+		for i, pkg := range pkgs {
+			ids[i] = pkg.ID()
+		}
+		sort.Strings(ids)
+		lines = append(lines, lipgloss.NewStyle().Foreground(catSubtext0).Render("    "+strings.Join(ids, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // filterIgnoredFromDiff removes ignored categories and packages from diff results
 func filterIgnoredFromDiff(diff *brewfile.DiffResult, ignoredCategories, ignoredPackages []string) *brewfile.DiffResult {
 	// Create maps for quick lookup
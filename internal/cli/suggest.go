@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+var suggestMachine string
+
+var suggestMachineSpecificCmd = &cobra.Command{
+	Use:   "suggest-machine-specific",
+	Short: "Suggest packages to mark as machine-specific",
+	Long: `Compare all machines' Brewfiles and list packages present on exactly one
+machine. Syncing from a machine that lacks them would offer them for removal,
+so they are good candidates for machine_specific in config.
+
+Candidates already marked machine-specific are skipped. After listing, you
+are asked whether to add them to config (use --yes to skip the prompt,
+--dry-run to only list).
+
+Examples:
+  brewsync suggest-machine-specific                 # All machines
+  brewsync suggest-machine-specific --machine mini  # Only candidates for mini
+  brewsync suggest-machine-specific --yes           # Add without asking`,
+	RunE: runSuggestMachineSpecific,
+}
+
+func init() {
+	suggestMachineSpecificCmd.Flags().StringVar(&suggestMachine, "machine", "", "only suggest packages for this machine")
+	rootCmd.AddCommand(suggestMachineSpecificCmd)
+}
+
+func runSuggestMachineSpecific(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Get()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if suggestMachine != "" {
+		if _, ok := cfg.Machines[suggestMachine]; !ok {
+			return fmt.Errorf("machine '%s' not found in config", suggestMachine)
+		}
+	}
+
+	// Load every machine's Brewfile
+	machines := make(map[string]brewfile.Packages)
+	for name, machine := range cfg.Machines {
+		pkgs, err := brewfile.Parse(machine.Brewfile)
+		if err != nil {
+			printVerbose("Skipping %s: %v", name, err)
+			continue
+		}
+		machines[name] = pkgs
+	}
+	if len(machines) < 2 {
+		return fmt.Errorf("need Brewfiles for at least two machines to compare (found %d)", len(machines))
+	}
+
+	// Drop packages already marked machine-specific
+	existing := cfg.GetMachineSpecificPackages()
+	candidates := make(map[string]brewfile.Packages)
+	total := 0
+	for machine, pkgs := range brewfile.SingleMachinePackages(machines, cfg.ExtensionAliases) {
+		if suggestMachine != "" && machine != suggestMachine {
+			continue
+		}
+		marked := make(map[string]bool)
+		for _, id := range existing[machine] {
+			marked[id] = true
+		}
+		pkgs = pkgs.Exclude(marked)
+		if len(pkgs) > 0 {
+			candidates[machine] = pkgs
+			total += len(pkgs)
+		}
+	}
+
+	if total == 0 {
+		printInfo("No new machine-specific candidates found")
+		return nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	fmt.Printf("Packages found on only one machine (%d)\n", total)
+	fmt.Println(strings.Repeat("─", 50))
+	for _, name := range names {
+		fmt.Printf("\n%s %s (%d)\n", colorYellow("▶"), name, len(candidates[name]))
+		for pkgType, pkgs := range groupByType(candidates[name]) {
+			fmt.Printf("  %s: %s\n", pkgType, strings.Join(getPkgNames(pkgs), ", "))
+		}
+	}
+	fmt.Println()
+
+	if dryRun {
+		printInfo("Dry-run mode - config not changed")
+		return nil
+	}
+
+	if !assumeYes {
+		fmt.Printf("Add these to machine_specific in config? [y/N] ")
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			printInfo("No changes made")
+			return nil
+		}
+	}
+
+	for _, name := range names {
+		for _, pkg := range candidates[name] {
+			if err := cfg.AddMachineSpecific(name, pkg.ID()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	printInfo("Marked %d package(s) as machine-specific", total)
+	return nil
+}
@@ -2,14 +2,19 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/git"
 	"github.com/asamgx/brewsync/internal/history"
+	"github.com/asamgx/brewsync/internal/hooks"
 	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/internal/notify"
+	"github.com/asamgx/brewsync/internal/sync"
 )
 
 var (
@@ -17,6 +22,11 @@ var (
 	syncOnly    string
 	syncApply   bool
 	syncPreview bool
+	syncPull    bool
+	syncPlanOut string
+	syncPlanIn  string
+	syncSkipNA  bool
+	syncFormat  string
 )
 
 var syncCmd = &cobra.Command{
@@ -35,15 +45,45 @@ Examples:
   brewsync sync --apply            # Execute changes
   brewsync sync --from air         # Sync from specific machine
   brewsync sync --only brew        # Only sync brews
-  brewsync sync --apply --dry-run  # Preview even with --apply`,
+  brewsync sync --apply --dry-run  # Preview even with --apply
+  brewsync sync --pull             # Pull latest Brewfiles before syncing
+  brewsync sync --plan-file plan.json   # Save the plan for review
+  brewsync sync --apply-plan plan.json  # Apply a reviewed plan
+  brewsync sync --apply --yes --format json  # Machine-readable result for CI
+
+With --pull, uncommitted local changes in the Brewfile repository are handled
+according to sync.pull_strategy in config: "stash" (default) stashes them
+before pulling and restores them afterwards, "refuse" aborts the sync.
+
+--plan-file writes the plan as JSON without applying it. --apply-plan applies
+exactly the changes in such a file after checking they still apply: changes
+that no longer apply are skipped, and changes needed since the plan was made
+are reported but not applied.
+
+--format json writes the result to stdout once the sync finishes: packages
+installed, removed, failed (with errors), skipped and protected, and the
+duration. Progress goes to stderr. It needs --yes and --apply or --apply-plan.`,
 	RunE: runSync,
 }
 
 func init() {
 	syncCmd.Flags().StringVar(&syncFrom, "from", "", "source machine to sync from")
-	syncCmd.Flags().StringVar(&syncOnly, "only", "", "only sync these package types (comma-separated)")
+	syncCmd.Flags().StringVar(&syncOnly, "only", "", "only sync these package types or aliases: editors, cli, apps (comma-separated)")
 	syncCmd.Flags().BoolVar(&syncApply, "apply", false, "apply changes (default is preview only)")
 	syncCmd.Flags().BoolVar(&syncPreview, "preview", false, "show preview (default behavior)")
+	syncCmd.Flags().BoolVar(&syncPull, "pull", false, "git pull the Brewfile repository before syncing")
+	syncCmd.Flags().StringVar(&syncPlanOut, "plan-file", "", "write the plan to this JSON file instead of applying it")
+	syncCmd.Flags().StringVar(&syncPlanIn, "apply-plan", "", "apply the plan saved in this JSON file by --plan-file")
+	syncCmd.Flags().BoolVar(&syncSkipNA, "skip-unavailable", false, "leave out additions Homebrew can't find (renamed or removed)")
+	syncCmd.Flags().BoolVar(&autoDump, "auto-dump", false, "dump the Brewfile after applying changes (or auto_dump.after_install)")
+	syncCmd.Flags().BoolVar(&checkBrew, "check-brew", false, "run 'brew doctor' first and abort if it finds errors (or install.check_brew)")
+	syncCmd.Flags().BoolVar(&noQuarantine, "no-quarantine", false, "install casks with --no-quarantine (or install.cask_no_quarantine)")
+	syncCmd.Flags().StringVar(&syncFormat, "format", "table", "result format: table, json")
+
+	syncCmd.MarkFlagsMutuallyExclusive("plan-file", "apply-plan")
+	syncCmd.MarkFlagsMutuallyExclusive("plan-file", "apply")
+	syncCmd.MarkFlagsMutuallyExclusive("apply-plan", "from")
+	syncCmd.MarkFlagsMutuallyExclusive("apply-plan", "only")
 
 	rootCmd.AddCommand(syncCmd)
 }
@@ -61,144 +101,303 @@ func runSync(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not detect current machine; run 'brewsync config init' first")
 	}
 
-	// Determine source machine
-	source := cfg.DefaultSource
-	if syncFrom != "" {
-		source = syncFrom
+	if err := checkResultFormat(syncFormat); err != nil {
+		return err
+	}
+	if jsonResult && !syncApply && syncPlanIn == "" {
+		return fmt.Errorf("--format json needs --apply or --apply-plan")
 	}
 
-	if source == currentMachine {
-		return fmt.Errorf("cannot sync from current machine '%s'", source)
+	if syncPlanIn != "" {
+		return runApplyPlan(cfg, syncPlanIn)
 	}
 
-	if _, ok := cfg.Machines[source]; !ok {
-		return fmt.Errorf("unknown source machine: %s", source)
+	source, opts, err := resolveSyncSource(cfg, syncFrom, syncOnly)
+	if err != nil {
+		return err
 	}
 
 	printInfo("Syncing %s to match %s", currentMachine, source)
 
-	// Load both Brewfiles
-	currentBrewfile := cfg.Machines[currentMachine].Brewfile
-	currentPkgs, err := brewfile.Parse(currentBrewfile)
-	if err != nil {
-		currentPkgs = brewfile.Packages{}
+	// Pull latest Brewfiles if requested
+	if syncPull {
+		if err := pullBrewfileRepo(cfg, cfg.Machines[source].Brewfile); err != nil {
+			return err
+		}
 	}
 
-	sourceBrewfile := cfg.Machines[source].Brewfile
-	sourcePkgs, err := brewfile.Parse(sourceBrewfile)
+	plan, err := sync.NewPlan(cfg, source, opts)
 	if err != nil {
-		return fmt.Errorf("failed to parse source Brewfile: %w", err)
+		return err
 	}
 
-	// Compute diff
-	diff := brewfile.Diff(sourcePkgs, currentPkgs)
-	additions := diff.Additions
-	removals := diff.Removals
-
-	// Filter by category if specified
-	if syncOnly != "" {
-		categories := parseCategories(syncOnly)
-		additions = filterByCategories(additions, categories, true)
-		removals = filterByCategories(removals, categories, true)
+	var result *applyResult
+	if jsonResult {
+		result = newApplyResult("sync", currentMachine, source)
+		result.skip(plan.Ignored())
+		result.protect(plan.Protected)
 	}
 
-	// Filter ignored packages from additions
-	ignored := cfg.GetIgnoredPackages(currentMachine)
-	ignoredMap := make(map[string]bool)
-	for _, pkg := range ignored {
-		ignoredMap[pkg] = true
+	// Installing a formula or cask brew no longer knows would only fail mid-sync
+	if syncSkipNA {
+		unavailable, err := skipUnavailable(plan)
+		if err != nil {
+			return err
+		}
+		result.skip(unavailable)
 	}
 
-	var filteredAdditions brewfile.Packages
-	for _, pkg := range additions {
-		if !ignoredMap[pkg.ID()] {
-			filteredAdditions = append(filteredAdditions, pkg)
+	// Save the plan for review instead of applying it
+	if syncPlanOut != "" {
+		if err := sync.SavePlan(syncPlanOut, plan, opts); err != nil {
+			return err
+		}
+		if plan.IsEmpty() {
+			printInfo("Already in sync - no changes needed")
+		} else {
+			printSyncPreview(plan)
 		}
+		printInfo("Plan written to %s; review it and run 'brewsync sync --apply-plan %s'", syncPlanOut, syncPlanOut)
+		return nil
 	}
-	additions = filteredAdditions
 
-	// Get machine-specific packages for current machine (protected from removal)
-	machineSpecific := cfg.GetMachineSpecificPackages()
-	protectedPkgs := make(map[string]bool)
-	if specific, ok := machineSpecific[currentMachine]; ok {
-		for _, pkg := range specific {
-			protectedPkgs[pkg] = true
+	// Check if there's anything to do
+	if plan.IsEmpty() {
+		printInfo("Already in sync - no changes needed")
+		return writeResult(result)
+	}
+
+	printSyncPreview(plan)
+
+	// If preview mode or dry-run, stop here
+	if !syncApply || dryRun {
+		printSyncDryRun(cfg, plan, "Run 'brewsync sync --apply' to execute these changes")
+		return nil
+	}
+
+	return applySync(cfg, plan, result)
+}
+
+// resolveSyncSource returns the machine to sync the current one from, from
+// (or default_source when empty), and the plan options for the --only
+// categories, checking both machines are in the config
+func resolveSyncSource(cfg *config.Config, from, only string) (string, sync.Options, error) {
+	var opts sync.Options
+	source := cfg.DefaultSource
+	if from != "" {
+		source = from
+	}
+	if source == "" {
+		return "", opts, fmt.Errorf("no source machine specified and no default_source in config")
+	}
+	if source == cfg.CurrentMachine {
+		return "", opts, fmt.Errorf("cannot sync from current machine '%s'", source)
+	}
+	if _, ok := cfg.Machines[cfg.CurrentMachine]; !ok {
+		return "", opts, fmt.Errorf("current machine '%s' not found in config", cfg.CurrentMachine)
+	}
+	if _, ok := cfg.Machines[source]; !ok {
+		return "", opts, fmt.Errorf("unknown source machine: %s", source)
+	}
+
+	if only != "" {
+		categories, err := brewfile.ParseCategories(only)
+		if err != nil {
+			return "", opts, err
 		}
+		opts.Categories = categories
 	}
+	return source, opts, nil
+}
 
-	// Also mark ignored packages as protected from removal
-	for pkg := range ignoredMap {
-		protectedPkgs[pkg] = true
+// runApplyPlan applies a plan saved by --plan-file, skipping changes that no
+// longer apply and warning when the live state has moved on since
+func runApplyPlan(cfg *config.Config, path string) error {
+	saved, err := sync.LoadPlan(path)
+	if err != nil {
+		return err
 	}
 
-	// Filter protected packages from removals
-	var filteredRemovals brewfile.Packages
-	var protectedList brewfile.Packages
-	for _, pkg := range removals {
-		if protectedPkgs[pkg.ID()] {
-			protectedList = append(protectedList, pkg)
-		} else {
-			filteredRemovals = append(filteredRemovals, pkg)
+	printInfo("Applying plan from %s (%s → %s, made %s)", path, saved.Source, saved.Machine, saved.CreatedAt.Local().Format("2006-01-02 15:04"))
+
+	if syncPull {
+		if err := pullBrewfileRepo(cfg, saved.SourceBrewfile); err != nil {
+			return err
 		}
 	}
-	removals = filteredRemovals
 
-	// Check if there's anything to do
-	if len(additions) == 0 && len(removals) == 0 {
-		printInfo("Already in sync - no changes needed")
+	check, err := saved.Check(cfg)
+	if err != nil {
+		return err
+	}
+	if check.SourceChanged {
+		printWarning("%s's Brewfile has changed since the plan was made", saved.Source)
+	}
+	if check.CurrentChanged {
+		printWarning("%s's Brewfile has changed since the plan was made", saved.Machine)
+	}
+	if len(check.Done) > 0 {
+		printWarning("Skipping %d planned change(s) that no longer apply: %s", len(check.Done), strings.Join(check.Done.IDs(), ", "))
+	}
+	if len(check.Unplanned) > 0 {
+		printWarning("%d change(s) are needed that the plan does not include (not applied): %s", len(check.Unplanned), strings.Join(check.Unplanned.IDs(), ", "))
+	}
+	if check.Stale() {
+		printInfo("Run 'brewsync sync --plan-file' again for an up-to-date plan")
+	}
+
+	plan := check.Plan
+	var result *applyResult
+	if jsonResult {
+		result = newApplyResult("sync", saved.Machine, saved.Source)
+		result.skip(check.Done)
+		result.skip(check.Unplanned)
+		result.skip(plan.Ignored())
+		result.protect(plan.Protected)
+	}
+	if plan.IsEmpty() {
+		printInfo("Nothing left to apply")
+		return writeResult(result)
+	}
+
+	printSyncPreview(plan)
+
+	if dryRun {
+		printSyncDryRun(cfg, plan, "")
 		return nil
 	}
 
+	return applySync(cfg, plan, result)
+}
+
+// skipUnavailable drops the plan's additions that Homebrew can't find and
+// returns them
+func skipUnavailable(plan *sync.Plan) (brewfile.Packages, error) {
+	unavailable, err := installer.NewBrewInstaller().Unavailable(plan.Additions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check package availability: %w", err)
+	}
+	if len(unavailable) == 0 {
+		return nil, nil
+	}
+	printWarning("Skipping %d package(s) not found in Homebrew (renamed or removed?): %s", len(unavailable), strings.Join(unavailable.IDs(), ", "))
+	skip := make(map[string]bool, len(unavailable))
+	for _, pkg := range unavailable {
+		skip[pkg.ID()] = true
+	}
+	plan.Additions = plan.Additions.Exclude(skip)
+	return unavailable, nil
+}
+
+// printSyncPreview prints the changes a sync plan would make
+func printSyncPreview(plan *sync.Plan) {
+	additions := plan.Additions
+	removals := plan.Removals
+	w := infoWriter()
+
 	// Display preview
-	fmt.Println()
-	fmt.Printf("Sync Preview: %s → %s\n", source, currentMachine)
-	fmt.Println(strings.Repeat("─", 50))
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Sync Preview: %s → %s\n", plan.Source, plan.Machine)
+	fmt.Fprintln(w, strings.Repeat("─", 50))
 
 	if len(additions) > 0 {
-		fmt.Printf("\n%s TO BE INSTALLED (+%d)\n", colorGreen("▶"), len(additions))
+		fmt.Fprintf(w, "\n%s TO BE INSTALLED (+%d)\n", colorGreen("▶"), len(additions))
 		grouped := groupByType(additions)
 		for pkgType, pkgs := range grouped {
 			names := getPkgNames(pkgs)
 			if len(names) > 5 {
-				fmt.Printf("  %s: %s (+%d more)\n", pkgType, strings.Join(names[:5], ", "), len(names)-5)
+				fmt.Fprintf(w, "  %s: %s (+%d more)\n", pkgType, strings.Join(names[:5], ", "), len(names)-5)
 			} else {
-				fmt.Printf("  %s: %s\n", pkgType, strings.Join(names, ", "))
+				fmt.Fprintf(w, "  %s: %s\n", pkgType, strings.Join(names, ", "))
 			}
 		}
 	}
 
 	if len(removals) > 0 {
-		fmt.Printf("\n%s TO BE REMOVED (-%d)\n", colorRed("▶"), len(removals))
+		fmt.Fprintf(w, "\n%s TO BE REMOVED (-%d)\n", colorRed("▶"), len(removals))
 		grouped := groupByType(removals)
 		for pkgType, pkgs := range grouped {
 			names := getPkgNames(pkgs)
 			if len(names) > 5 {
-				fmt.Printf("  %s: %s (+%d more)\n", pkgType, strings.Join(names[:5], ", "), len(names)-5)
+				fmt.Fprintf(w, "  %s: %s (+%d more)\n", pkgType, strings.Join(names[:5], ", "), len(names)-5)
 			} else {
-				fmt.Printf("  %s: %s\n", pkgType, strings.Join(names, ", "))
+				fmt.Fprintf(w, "  %s: %s\n", pkgType, strings.Join(names, ", "))
 			}
 		}
 	}
 
-	if len(protectedList) > 0 {
-		fmt.Printf("\n%s PROTECTED (machine-specific/ignored, won't be removed: %d)\n", colorYellow("▶"), len(protectedList))
-		grouped := groupByType(protectedList)
+	if len(plan.Protected) > 0 {
+		fmt.Fprintf(w, "\n%s PROTECTED (machine-specific/pinned, won't be removed: %d)\n", colorYellow("▶"), len(plan.Protected))
+		grouped := groupByType(plan.Protected)
 		for pkgType, pkgs := range grouped {
 			names := getPkgNames(pkgs)
-			fmt.Printf("  %s: %s\n", pkgType, strings.Join(names, ", "))
+			fmt.Fprintf(w, "  %s: %s\n", pkgType, strings.Join(names, ", "))
 		}
 	}
 
-	fmt.Println()
+	if ignored := plan.Ignored(); len(ignored) > 0 {
+		fmt.Fprintf(w, "\n%s IGNORED (skipped: %d)\n", colorYellow("▶"), len(ignored))
+		grouped := groupByType(ignored)
+		for pkgType, pkgs := range grouped {
+			names := getPkgNames(pkgs)
+			fmt.Fprintf(w, "  %s: %s\n", pkgType, strings.Join(names, ", "))
+		}
+	}
 
-	// If preview mode or dry-run, stop here
-	if !syncApply || dryRun {
-		if dryRun {
-			printInfo("Dry-run mode - no changes made")
-		} else {
-			printInfo("Run 'brewsync sync --apply' to execute these changes")
+	if len(plan.Modifications) > 0 {
+		fmt.Fprintf(w, "\n%s OPTIONS DIFFER (not changed by sync: %d)\n", colorYellow("▶"), len(plan.Modifications))
+		grouped := groupByType(plan.Modifications)
+		for pkgType, pkgs := range grouped {
+			names := getPkgNames(pkgs)
+			fmt.Fprintf(w, "  %s: %s\n", pkgType, strings.Join(names, ", "))
 		}
-		return nil
+	}
+
+	fmt.Fprintln(w)
+}
+
+// printSyncDryRun explains what applying the plan would run, ending with hint
+// unless this is a dry run
+func printSyncDryRun(cfg *config.Config, plan *sync.Plan, hint string) {
+	printCaskInstallCommands(cfg, newInstallManager(cfg), plan.Additions)
+	if dryRun {
+		if len(plan.Additions) > 0 {
+			printHookPlan(cfg, plan.CurrentBrewfile, hooks.PreInstall, hooks.PostInstall)
+		}
+		printInfo("Dry-run mode - no changes made")
+	} else if hint != "" {
+		printInfo("%s", hint)
+	}
+}
+
+// applySync confirms and applies a sync plan, then records it. The outcome of
+// each package is also added to result, which is written when it isn't nil.
+func applySync(cfg *config.Config, plan *sync.Plan, result *applyResult) error {
+	changed, err := applyPlan(cfg, plan, result)
+	if err != nil {
+		return err
+	}
+
+	// Refresh the Brewfile so it includes what was just applied
+	if changed {
+		autoDumpAfterApply(cfg, plan.Machine)
+	}
+
+	return writeResult(result)
+}
+
+// applyPlan confirms and applies a sync plan and records it in the history
+// and metadata. It reports whether any package was installed or removed,
+// which is false when the user declines.
+func applyPlan(cfg *config.Config, plan *sync.Plan, result *applyResult) (bool, error) {
+	source, currentMachine := plan.Source, plan.Machine
+	currentBrewfile := plan.CurrentBrewfile
+	additions := plan.Additions
+	removals := plan.Removals
+	mgr := newInstallManager(cfg)
+
+	if err := checkBrewHealth(cfg, additions, removals); err != nil {
+		return false, fmt.Errorf("%w; sync aborted before changing packages", err)
 	}
 
 	// Confirm before applying
@@ -208,24 +407,33 @@ func runSync(cmd *cobra.Command, args []string) error {
 		fmt.Scanln(&response)
 		if response != "y" && response != "Y" {
 			printInfo("Sync cancelled")
-			return nil
+			return false, nil
 		}
 	}
 
+	if !assumeYes {
+		authenticateSudo(additions, removals)
+	}
+
+	if err := runHooks(cfg, currentBrewfile, hooks.PreInstall); err != nil {
+		return false, fmt.Errorf("%w; sync aborted before changing packages", err)
+	}
+
 	// Apply changes
-	mgr := installer.NewManager()
-	var installedCount, removedCount, failedCount int
+	var installs, removes installTally
+	var installedPkgs, removedPkgs brewfile.Packages
 
 	// Install additions first
 	if len(additions) > 0 {
 		printInfo("Installing %d packages...", len(additions))
 		mgr.InstallMany(additions, func(pkg brewfile.Package, i, total int, err error) {
+			installs.record(err)
+			result.record(installer.ActionInstall, pkg, err)
 			if err != nil {
-				printError("[%d/%d] Failed to install %s:%s: %v", i, total, pkg.Type, pkg.Name, err)
-				failedCount++
+				printError("[%d/%d] Failed to install %s:%s%s: %s", i, total, pkg.Type, pkg.Name, retryNote(mgr, pkg), failureReason(err))
 			} else {
-				printInfo("[%d/%d] Installed %s:%s", i, total, pkg.Type, pkg.Name)
-				installedCount++
+				printInfo("[%d/%d] Installed %s:%s%s", i, total, pkg.Type, pkg.Name, retryNote(mgr, pkg))
+				installedPkgs = append(installedPkgs, pkg)
 			}
 		})
 	}
@@ -234,48 +442,67 @@ func runSync(cmd *cobra.Command, args []string) error {
 	if len(removals) > 0 {
 		printInfo("Removing %d packages...", len(removals))
 		mgr.UninstallMany(removals, func(pkg brewfile.Package, i, total int, err error) {
+			removes.record(err)
+			result.record(installer.ActionUninstall, pkg, err)
 			if err != nil {
-				printError("[%d/%d] Failed to remove %s:%s: %v", i, total, pkg.Type, pkg.Name, err)
-				failedCount++
+				printError("[%d/%d] Failed to remove %s:%s: %s", i, total, pkg.Type, pkg.Name, failureReason(err))
 			} else {
 				printInfo("[%d/%d] Removed %s:%s", i, total, pkg.Type, pkg.Name)
-				removedCount++
+				removedPkgs = append(removedPkgs, pkg)
 			}
 		})
 	}
 
-	fmt.Println()
+	runPostHooks(cfg, currentBrewfile, hooks.PostInstall)
+
+	installedCount, removedCount := installs.succeeded, removes.succeeded
+	failedCount := installs.failures() + removes.failures()
+
+	fmt.Fprintln(infoWriter())
 	printInfo("Sync complete: +%d installed, -%d removed, %d failed",
 		installedCount, removedCount, failedCount)
+	if hint := sudoHint(installs.needsSudo + removes.needsSudo); hint != "" {
+		printWarning("%s", hint)
+	}
+	printCaveats(infoWriter(), installedPkgs, mgr.Caveats())
+	notifyFinished(cfg, notify.Summary("Sync", installedCount+removedCount, failedCount))
 
 	// Log to history
-	history.LogSync(currentMachine, source, installedCount, removedCount)
+	history.LogSync(currentMachine, source, installedPkgs.IDs(), removedPkgs.IDs())
 
-	// Auto-dump if enabled and changes were made
-	if (installedCount > 0 || removedCount > 0) && cfg.AutoDump.Enabled && cfg.AutoDump.AfterInstall {
-		printInfo("Auto-dumping Brewfile...")
+	// Record the sync so pending changes are accurate before the next dump
+	if err := brewfile.UpdateSyncMetadata(brewfile.MetadataPath(currentBrewfile), source, installedPkgs, removedPkgs); err != nil {
+		printWarning("Failed to update metadata: %v", err)
+	}
 
-		// Set flags for commit/push based on config
-		oldCommit := dumpCommit
-		oldPush := dumpPush
-		oldMessage := dumpMessage
+	return installedCount > 0 || removedCount > 0, nil
+}
 
-		dumpCommit = cfg.AutoDump.Commit
-		dumpPush = cfg.AutoDump.Push
-		if cfg.AutoDump.CommitMessage != "" {
-			dumpMessage = strings.ReplaceAll(cfg.AutoDump.CommitMessage, "{machine}", currentMachine)
-		}
+// pullBrewfileRepo pulls the git repository containing the Brewfile,
+// protecting uncommitted changes according to sync.pull_strategy
+func pullBrewfileRepo(cfg *config.Config, brewfilePath string) error {
+	strategy, err := git.ParseDirtyStrategy(cfg.Sync.PullStrategy)
+	if err != nil {
+		return err
+	}
 
-		err := runDump(nil, []string{})
+	repo := git.NewRepo(filepath.Dir(brewfilePath))
 
-		// Restore flags
-		dumpCommit = oldCommit
-		dumpPush = oldPush
-		dumpMessage = oldMessage
+	if dryRun {
+		printInfo("[dry-run] Would pull %s (pull strategy: %s)", repo.Dir(), strategy)
+		return nil
+	}
 
-		if err != nil {
-			printWarning("Auto-dump failed: %v", err)
-		}
+	printInfo("Pulling latest Brewfiles...")
+	result, err := repo.Pull(strategy)
+	if err != nil {
+		return err
+	}
+
+	if result.Stashed {
+		printInfo("✓ Pulled (stashed and restored %d local change(s))", len(result.DirtyFiles))
+	} else {
+		printInfo("✓ Pulled")
 	}
 
 	return nil
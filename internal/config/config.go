@@ -1,23 +1,31 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	// ConfigDirName is the directory name under ~/.config
+	// ConfigDirName is the directory name under ~/.config (or $XDG_CONFIG_HOME)
 	ConfigDirName = "brewsync"
 	// ConfigFileName is the config file name without extension
 	ConfigFileName = "config"
 	// ConfigFileType is the config file extension
 	ConfigFileType = "yaml"
+	// ConfigEnvVar is the environment variable that overrides the config file path
+	ConfigEnvVar = "BREWSYNC_CONFIG"
 )
 
+// ErrNotFound is returned by LoadExisting when there is no config file
+var ErrNotFound = errors.New("config file not found")
+
 var (
 	// cfg holds the loaded configuration
 	cfg *Config
@@ -25,60 +33,49 @@ var (
 	configPath string
 )
 
-// configDir returns the config directory path
-func configDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-	return filepath.Join(home, ".config", ConfigDirName), nil
+// CacheDir returns the directory for cached lookups, e.g. package descriptions.
+// 'brewsync clean' expires files in it.
+func CacheDir() string {
+	return filepath.Join(StateDir(), "cache")
 }
 
-// ConfigDir returns the config directory path (exported version)
-func ConfigDir() string {
-	dir, err := configDir()
-	if err != nil {
-		// Fallback to a default (should rarely happen)
-		return filepath.Join(os.Getenv("HOME"), ".config", "brewsync")
+// customConfigPath returns the config path set via SetConfigPath or BREWSYNC_CONFIG,
+// or an empty string if the default location is used
+func customConfigPath() string {
+	if configPath != "" {
+		return configPath
 	}
-	return dir
+	return os.Getenv(ConfigEnvVar)
 }
 
 // ConfigPath returns the full path to the config file
 func ConfigPath() (string, error) {
-	if configPath != "" {
-		return configPath, nil
+	if path := customConfigPath(); path != "" {
+		return path, nil
 	}
-	dir, err := configDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, ConfigFileName+"."+ConfigFileType), nil
+	return filepath.Join(StateDir(), ConfigFileName+"."+ConfigFileType), nil
 }
 
-// SetConfigPath overrides the default config path
+// SetConfigPath overrides the default config path (takes precedence over BREWSYNC_CONFIG)
 func SetConfigPath(path string) {
 	configPath = path
+	// Drop the cached config so the next Load reads the new file
+	cfg = nil
 }
 
 // Init initializes viper with defaults and loads config if it exists
 func Init() error {
-	dir, err := configDir()
-	if err != nil {
-		return err
-	}
-
 	// Set defaults
 	setDefaults()
 
 	// Configure viper
 	viper.SetConfigName(ConfigFileName)
 	viper.SetConfigType(ConfigFileType)
-	viper.AddConfigPath(dir)
+	viper.AddConfigPath(StateDir())
 
-	// Allow override via custom path
-	if configPath != "" {
-		viper.SetConfigFile(configPath)
+	// Allow override via custom path (--config or BREWSYNC_CONFIG)
+	if path := customConfigPath(); path != "" {
+		viper.SetConfigFile(path)
 	}
 
 	// Environment variable support
@@ -118,10 +115,25 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	aliases, err := readExtensionAliases(viper.ConfigFileUsed())
+	if err != nil {
+		return nil, err
+	}
+	cfg.ExtensionAliases = aliases
+
+	// Upgrade configs written by older versions
+	cfg.migrations = migrate(cfg)
+
+	cfg.warnings = duplicateHostnameWarnings(cfg.Machines)
 
 	// Detect current machine if set to "auto"
 	if cfg.CurrentMachine == "auto" || cfg.CurrentMachine == "" {
 		detected, err := DetectMachine(cfg.Machines)
+		var ambiguous *AmbiguousMachineError
+		if errors.As(err, &ambiguous) {
+			cfg.warnings = append(cfg.warnings, ambiguous.Error())
+			err = nil
+		}
 		if err == nil {
 			cfg.CurrentMachine = detected
 		}
@@ -137,6 +149,63 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// readExtensionAliases reads extension_aliases straight from the config file.
+// Viper splits keys on dots, which would turn an extension ID such as
+// ms-vscode.cpptools into nested maps.
+func readExtensionAliases(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var raw struct {
+		ExtensionAliases map[string]string `yaml:"extension_aliases"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse extension_aliases: %w", err)
+	}
+	return raw.ExtensionAliases, nil
+}
+
+// Warnings returns problems Load found in the config that don't prevent using
+// it, such as machines sharing a hostname
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// duplicateHostnameWarnings describes each hostname shared by several machines
+func duplicateHostnameWarnings(machines map[string]Machine) []string {
+	duplicates := DuplicateHostnames(machines)
+	hostnames := make([]string, 0, len(duplicates))
+	for hostname := range duplicates {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	var warnings []string
+	for _, hostname := range hostnames {
+		warnings = append(warnings, fmt.Sprintf("machines %s share hostname %q",
+			strings.Join(duplicates[hostname], ", "), hostname))
+	}
+	return warnings
+}
+
+// LoadExisting is Load for callers that need to tell a never-configured install
+// (ErrNotFound) apart from a config file that has no machines. Load itself falls
+// back to defaults when the file is missing.
+func LoadExisting() (*Config, error) {
+	if !Exists() {
+		return nil, ErrNotFound
+	}
+	return Load()
+}
+
 // Get returns the loaded config, loading it if necessary
 func Get() (*Config, error) {
 	if cfg != nil {
@@ -156,30 +225,28 @@ func Exists() bool {
 }
 
 // EnsureDir creates the config directory if it doesn't exist
+// (the directory of the custom config file when one is set)
 func EnsureDir() error {
-	dir, err := configDir()
+	path, err := ConfigPath()
 	if err != nil {
 		return err
 	}
-	return os.MkdirAll(dir, 0755)
+	return os.MkdirAll(filepath.Dir(path), 0755)
 }
 
 // ProfilesDir returns the path to the profiles directory
 func ProfilesDir() (string, error) {
-	dir, err := configDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, "profiles"), nil
+	return filepath.Join(StateDir(), "profiles"), nil
 }
 
 // HistoryPath returns the path to the history log file
 func HistoryPath() (string, error) {
-	dir, err := configDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(dir, "history.log"), nil
+	return filepath.Join(StateDir(), "history.log"), nil
+}
+
+// AuditPath returns the path to the audit log file
+func AuditPath() string {
+	return filepath.Join(StateDir(), "audit.log")
 }
 
 // Save writes the current config to disk
@@ -195,17 +262,28 @@ func Save(c *Config) error {
 	}
 
 	// Create a saveable version (without internal ignoreFile field)
+	c.SchemaVersion = max(c.SchemaVersion, CurrentSchemaVersion)
 	saveConfig := &saveableConfig{
+		SchemaVersion:      c.SchemaVersion,
 		Machines:           c.Machines,
 		CurrentMachine:     c.CurrentMachine,
 		DefaultSource:      c.DefaultSource,
+		StatusSources:      c.StatusSources,
 		DefaultCategories:  c.DefaultCategories,
 		AutoDump:           c.AutoDump,
 		Dump:               c.Dump,
+		Sync:               c.Sync,
+		Install:            c.Install,
+		Import:             c.Import,
 		MachineSpecific:    c.MachineSpecific,
+		Pinned:             c.Pinned,
+		ExtensionAliases:   c.ExtensionAliases,
 		ConflictResolution: c.ConflictResolution,
+		Keybindings:        c.Keybindings,
 		Output:             c.Output,
 		Hooks:              c.Hooks,
+		Audit:              c.Audit,
+		Defaults:           c.Defaults,
 	}
 
 	// Marshal to YAML
@@ -219,7 +297,8 @@ func Save(c *Config) error {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
-	// Update the cached config
+	// Update the cached config; the file now has the upgraded schema
+	c.migrations = nil
 	cfg = c
 
 	// Reload viper to keep it in sync
@@ -230,14 +309,24 @@ func Save(c *Config) error {
 
 // saveableConfig is the config structure for YAML serialization (without internal fields)
 type saveableConfig struct {
+	SchemaVersion      int                   `yaml:"schema_version"`
 	Machines           map[string]Machine    `yaml:"machines"`
 	CurrentMachine     string                `yaml:"current_machine"`
 	DefaultSource      string                `yaml:"default_source"`
+	StatusSources      []string              `yaml:"status_sources,omitempty"`
 	DefaultCategories  []string              `yaml:"default_categories"`
 	AutoDump           AutoDumpConfig        `yaml:"auto_dump"`
 	Dump               DumpConfig            `yaml:"dump"`
+	Sync               SyncConfig            `yaml:"sync"`
+	Install            InstallConfig         `yaml:"install"`
+	Import             ImportConfig          `yaml:"import"`
 	MachineSpecific    MachineSpecificConfig `yaml:"machine_specific,omitempty"`
+	Pinned             []string              `yaml:"pinned,omitempty"`
+	ExtensionAliases   map[string]string     `yaml:"extension_aliases,omitempty"`
 	ConflictResolution ConflictResolution    `yaml:"conflict_resolution"`
+	Keybindings        map[string]string     `yaml:"keybindings,omitempty"`
 	Output             OutputConfig          `yaml:"output"`
 	Hooks              HooksConfig           `yaml:"hooks,omitempty"`
+	Audit              AuditConfig           `yaml:"audit"`
+	Defaults           FlagDefaults          `yaml:"defaults,omitempty"`
 }
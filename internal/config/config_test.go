@@ -102,6 +102,13 @@ default_source: test
 default_categories:
   - brew
   - cask
+defaults:
+  import:
+    include-machine-specific: true
+  profile install:
+    skip: [mas, go]
+extension_aliases:
+  ms-vscode.cpptools: anysphere.cpptools
 `
 	err := os.WriteFile(configFile, []byte(configContent), 0644)
 	require.NoError(t, err)
@@ -132,6 +139,10 @@ default_categories:
 	assert.Equal(t, "test", loadedCfg.CurrentMachine)
 	assert.Contains(t, loadedCfg.Machines, "test")
 	assert.Equal(t, "test-hostname", loadedCfg.Machines["test"].Hostname)
+	assert.Equal(t, true, loadedCfg.Defaults["import"]["include-machine-specific"])
+	assert.Equal(t, []interface{}{"mas", "go"}, loadedCfg.Defaults["profile install"]["skip"])
+	// Dotted extension IDs survive as keys
+	assert.Equal(t, map[string]string{"ms-vscode.cpptools": "anysphere.cpptools"}, loadedCfg.ExtensionAliases)
 }
 
 func TestLoadWithDefaults(t *testing.T) {
@@ -200,3 +211,124 @@ func TestGet_ReturnsCachedConfig(t *testing.T) {
 
 	assert.Same(t, cfg1, cfg2, "Get should return the same cached instance")
 }
+
+func TestConfigPath_EnvOverride(t *testing.T) {
+	origConfigPath := configPath
+	defer func() { configPath = origConfigPath }()
+	configPath = ""
+
+	envPath := filepath.Join(t.TempDir(), "alt.yaml")
+	t.Setenv(ConfigEnvVar, envPath)
+
+	path, err := ConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, envPath, path)
+
+	// Explicit path (--config) wins over the env var
+	flagPath := filepath.Join(t.TempDir(), "flag.yaml")
+	SetConfigPath(flagPath)
+	path, err = ConfigPath()
+	require.NoError(t, err)
+	assert.Equal(t, flagPath, path)
+}
+
+func TestIgnorePath_FollowsCustomConfig(t *testing.T) {
+	origConfigPath := configPath
+	origIgnorePath := ignorePath
+	defer func() {
+		configPath = origConfigPath
+		ignorePath = origIgnorePath
+	}()
+	ignorePath = ""
+
+	dir := t.TempDir()
+	SetConfigPath(filepath.Join(dir, "work.yaml"))
+	assert.Equal(t, filepath.Join(dir, "ignore.yaml"), IgnorePath())
+
+	configPath = ""
+	t.Setenv(ConfigEnvVar, "")
+	assert.Contains(t, IgnorePath(), filepath.Join(".config", "brewsync", "ignore.yaml"))
+}
+
+func TestLoadAndSave_AlternateConfig(t *testing.T) {
+	viper.Reset()
+	cfg = nil
+	origConfigPath := configPath
+	origIgnorePath := ignorePath
+	t.Setenv("MACHINE", "")
+	defer func() {
+		configPath = origConfigPath
+		ignorePath = origIgnorePath
+		cfg = nil
+		viper.Reset()
+	}()
+	configPath = ""
+	ignorePath = ""
+
+	dir := filepath.Join(t.TempDir(), "fixture")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+machines:
+  work:
+    hostname: "work-host"
+    brewfile: "/tmp/Brewfile.work"
+current_machine: work
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignore.yaml"), []byte(`
+global:
+  categories: [mas]
+`), 0644))
+
+	t.Setenv(ConfigEnvVar, configFile)
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "work", loaded.CurrentMachine)
+	assert.Equal(t, "work-host", loaded.Machines["work"].Hostname)
+	assert.True(t, loaded.IsCategoryIgnored("work", "mas"))
+
+	// Save writes back to the alternate path
+	loaded.DefaultSource = "work"
+	require.NoError(t, Save(loaded))
+
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "default_source: work")
+}
+
+func TestLoadExisting(t *testing.T) {
+	viper.Reset()
+	cfg = nil
+	origConfigPath := configPath
+	origIgnorePath := ignorePath
+	t.Setenv("MACHINE", "")
+	defer func() {
+		configPath = origConfigPath
+		ignorePath = origIgnorePath
+		cfg = nil
+		viper.Reset()
+	}()
+	ignorePath = ""
+
+	dir := t.TempDir()
+
+	t.Run("no config file", func(t *testing.T) {
+		SetConfigPath(filepath.Join(dir, "missing.yaml"))
+		loaded, err := LoadExisting()
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, loaded)
+	})
+
+	t.Run("config without machines", func(t *testing.T) {
+		viper.Reset()
+		configFile := filepath.Join(dir, "empty.yaml")
+		require.NoError(t, os.WriteFile(configFile, []byte("machines: {}\ndefault_source: \"\"\n"), 0644))
+		SetConfigPath(configFile)
+
+		loaded, err := LoadExisting()
+		require.NoError(t, err)
+		require.NotNil(t, loaded)
+		assert.Empty(t, loaded.Machines)
+	})
+}
@@ -1,6 +1,10 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
 
 // DefaultCategories is the default list of package types to sync
 var DefaultCategories = []string{
@@ -11,12 +15,23 @@ var DefaultCategories = []string{
 	"cursor",
 	"antigravity",
 	"go",
+	"npm",
+	"pipx",
 	"mas",
 }
 
 // DefaultCommitMessage is the default git commit message template
 const DefaultCommitMessage = "brewsync: update {machine} Brewfile"
 
+// CommitMessage returns the commit message for a dump of machine's Brewfile:
+// template, or DefaultCommitMessage when it is empty, with {machine} replaced
+func CommitMessage(template, machine string) string {
+	if template == "" {
+		template = DefaultCommitMessage
+	}
+	return strings.ReplaceAll(template, "{machine}", machine)
+}
+
 // setDefaults sets all default values in viper
 func setDefaults() {
 	// Machine detection
@@ -33,7 +48,25 @@ func setDefaults() {
 	viper.SetDefault("auto_dump.commit_message", DefaultCommitMessage)
 
 	// Dump settings
-	viper.SetDefault("dump.use_brew_bundle", true) // Use 'brew bundle dump --describe' by default
+	viper.SetDefault("dump.use_brew_bundle", true)     // Use 'brew bundle dump --describe' by default
+	viper.SetDefault("dump.header", true)              // Self-documenting header on new Brewfiles
+	viper.SetDefault("dump.extension_versions", false) // Leave editor extensions unpinned
+	viper.SetDefault("dump.preserve_comments", false)  // Rewrite the Brewfile in brewsync's own layout
+
+	// Sync settings
+	viper.SetDefault("sync.pull_strategy", "stash") // Stash uncommitted changes before 'sync --pull'
+
+	// Install settings
+	viper.SetDefault("install.cask_no_quarantine", false)
+	viper.SetDefault("install.check_brew", false) // brew doctor takes a few seconds
+	viper.SetDefault("install.retries", 0)
+	viper.SetDefault("install.parallel", 1)
+
+	// Import settings
+	viper.SetDefault("import.remember_deselected", false)
+
+	// Audit settings
+	viper.SetDefault("audit.enabled", false)
 
 	// Conflict resolution
 	viper.SetDefault("conflict_resolution", string(ConflictAsk))
@@ -42,4 +75,7 @@ func setDefaults() {
 	viper.SetDefault("output.color", true)
 	viper.SetDefault("output.verbose", false)
 	viper.SetDefault("output.show_descriptions", true)
+	viper.SetDefault("output.notify", false)
+	viper.SetDefault("output.icons", "emoji")
+	viper.SetDefault("output.pager", true)
 }
@@ -7,13 +7,13 @@ import (
 )
 
 func TestDefaultCategories(t *testing.T) {
-	expected := []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"}
+	expected := []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "npm", "pipx", "mas"}
 	assert.Equal(t, expected, DefaultCategories)
 }
 
 func TestDefaultCategories_ContainsAllTypes(t *testing.T) {
 	// Ensure all expected package types are in defaults
-	expectedTypes := []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"}
+	expectedTypes := []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "npm", "pipx", "mas"}
 
 	for _, expectedType := range expectedTypes {
 		assert.Contains(t, DefaultCategories, expectedType,
@@ -25,3 +25,9 @@ func TestDefaultCommitMessage(t *testing.T) {
 	assert.Equal(t, "brewsync: update {machine} Brewfile", DefaultCommitMessage)
 	assert.Contains(t, DefaultCommitMessage, "{machine}")
 }
+
+func TestCommitMessage(t *testing.T) {
+	assert.Equal(t, "brewsync: update mini Brewfile", CommitMessage("", "mini"))
+	assert.Equal(t, "dump air", CommitMessage("dump {machine}", "air"))
+	assert.Equal(t, "Update packages", CommitMessage("Update packages", "air"))
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DeselectedPath returns the path of the file remembering packages left
+// unselected during import (import.remember_deselected). It lives next to
+// ignore.yaml.
+func DeselectedPath() string {
+	return filepath.Join(filepath.Dir(IgnorePath()), "deselected.yaml")
+}
+
+// LoadDeselected returns the package IDs ("type:name") left unselected in
+// earlier imports, keyed by source. A missing file is empty.
+func LoadDeselected() (map[string][]string, error) {
+	data, err := os.ReadFile(DeselectedPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read deselected packages: %w", err)
+	}
+
+	deselected := map[string][]string{}
+	if err := yaml.Unmarshal(data, &deselected); err != nil {
+		return nil, fmt.Errorf("failed to parse deselected packages: %w", err)
+	}
+	return deselected, nil
+}
+
+// DeselectedSet returns the remembered package IDs for source as a set
+func DeselectedSet(source string) (map[string]bool, error) {
+	deselected, err := LoadDeselected()
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(deselected[source]))
+	for _, id := range deselected[source] {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// RememberDeselected replaces the remembered package IDs for source. Unlike an
+// ignore, this only changes which packages import preselects.
+func RememberDeselected(source string, ids []string) error {
+	deselected, err := LoadDeselected()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		delete(deselected, source)
+	} else {
+		ids = append([]string(nil), ids...)
+		sort.Strings(ids)
+		deselected[source] = ids
+	}
+	return saveDeselected(deselected)
+}
+
+// ClearDeselected forgets the remembered packages for source, or for every
+// source if source is empty
+func ClearDeselected(source string) error {
+	if source == "" {
+		if err := os.Remove(DeselectedPath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove deselected packages: %w", err)
+		}
+		return nil
+	}
+	return RememberDeselected(source, nil)
+}
+
+func saveDeselected(deselected map[string][]string) error {
+	path := DeselectedPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(deselected)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deselected packages: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write deselected packages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeselected_RememberAndClear(t *testing.T) {
+	tmpDir := t.TempDir()
+	SetIgnorePath(filepath.Join(tmpDir, "ignore.yaml"))
+	defer SetIgnorePath("")
+
+	assert.Equal(t, filepath.Join(tmpDir, "deselected.yaml"), DeselectedPath())
+
+	// Nothing remembered yet
+	set, err := DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Empty(t, set)
+
+	require.NoError(t, RememberDeselected("air", []string{"cask:zoom", "brew:htop"}))
+	require.NoError(t, RememberDeselected("mini,air", []string{"brew:wget"}))
+
+	set, err = DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"cask:zoom": true, "brew:htop": true}, set)
+
+	all, err := LoadDeselected()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"air":      {"brew:htop", "cask:zoom"},
+		"mini,air": {"brew:wget"},
+	}, all)
+
+	// A later run replaces the list for that source only
+	require.NoError(t, RememberDeselected("air", []string{"cask:zoom"}))
+	set, err = DeselectedSet("air")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"cask:zoom": true}, set)
+
+	// Clearing one source keeps the others
+	require.NoError(t, ClearDeselected("air"))
+	all, err = LoadDeselected()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"mini,air": {"brew:wget"}}, all)
+
+	// Clearing everything removes the file
+	require.NoError(t, ClearDeselected(""))
+	assert.NoFileExists(t, DeselectedPath())
+	require.NoError(t, ClearDeselected(""))
+}
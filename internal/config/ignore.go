@@ -16,7 +16,11 @@ func IgnorePath() string {
 	if ignorePath != "" {
 		return ignorePath
 	}
-	return filepath.Join(ConfigDir(), "ignore.yaml")
+	// Keep ignore.yaml next to a custom config file
+	if path := customConfigPath(); path != "" {
+		return filepath.Join(filepath.Dir(path), "ignore.yaml")
+	}
+	return filepath.Join(StateDir(), "ignore.yaml")
 }
 
 // SetIgnorePath overrides the ignore file path (for testing)
@@ -34,7 +38,7 @@ func LoadIgnoreFile() (*IgnoreFile, error) {
 		return &IgnoreFile{
 			Global: IgnoreConfig{
 				Categories: []string{},
-				Packages:   PackageIgnoreList{},
+				Packages:   IgnoredPackages{},
 			},
 			Machines: make(map[string]IgnoreConfig),
 		}, nil
@@ -92,15 +96,17 @@ func CreateDefaultIgnoreFile() error {
 	ignoreFile := &IgnoreFile{
 		Global: IgnoreConfig{
 			Categories: []string{},
-			Packages: PackageIgnoreList{
-				Tap:         []string{},
-				Brew:        []string{},
-				Cask:        []string{},
-				VSCode:      []string{},
-				Cursor:      []string{},
-				Antigravity: []string{},
-				Go:          []string{},
-				Mas:         []string{},
+			Packages: IgnoredPackages{
+				Tap:         IgnoreEntries{},
+				Brew:        IgnoreEntries{},
+				Cask:        IgnoreEntries{},
+				VSCode:      IgnoreEntries{},
+				Cursor:      IgnoreEntries{},
+				Antigravity: IgnoreEntries{},
+				Go:          IgnoreEntries{},
+				Npm:         IgnoreEntries{},
+				Pipx:        IgnoreEntries{},
+				Mas:         IgnoreEntries{},
 			},
 		},
 		Machines: make(map[string]IgnoreConfig),
@@ -127,7 +133,7 @@ func AddCategoryIgnore(machine, category string, global bool) error {
 		if !ok {
 			machineIgnore = IgnoreConfig{
 				Categories: []string{},
-				Packages:   PackageIgnoreList{},
+				Packages:   IgnoredPackages{},
 			}
 		}
 
@@ -165,6 +171,13 @@ func RemoveCategoryIgnore(machine, category string, global bool) error {
 // AddPackageIgnore adds a package to the ignore list
 // pkgID format: "type:name" (e.g., "cask:bluestacks")
 func AddPackageIgnore(machine, pkgID string, global bool) error {
+	return AddPackageIgnoreWithReason(machine, pkgID, "", global)
+}
+
+// AddPackageIgnoreWithReason adds a package to the ignore list with a note on why
+// it is ignored. Re-adding an ignored package replaces its reason unless reason
+// is empty.
+func AddPackageIgnoreWithReason(machine, pkgID, reason string, global bool) error {
 	ignoreFile, err := LoadIgnoreFile()
 	if err != nil {
 		return err
@@ -178,18 +191,18 @@ func AddPackageIgnore(machine, pkgID string, global bool) error {
 
 	if global || machine == "" {
 		// Add to global packages
-		addPackageToList(&ignoreFile.Global.Packages, pkgType, pkgName)
+		addIgnoreEntry(&ignoreFile.Global.Packages, pkgType, pkgName, reason)
 	} else {
 		// Add to machine-specific packages
 		machineIgnore, ok := ignoreFile.Machines[machine]
 		if !ok {
 			machineIgnore = IgnoreConfig{
 				Categories: []string{},
-				Packages:   PackageIgnoreList{},
+				Packages:   IgnoredPackages{},
 			}
 		}
 
-		addPackageToList(&machineIgnore.Packages, pkgType, pkgName)
+		addIgnoreEntry(&machineIgnore.Packages, pkgType, pkgName, reason)
 		ignoreFile.Machines[machine] = machineIgnore
 	}
 
@@ -211,11 +224,11 @@ func RemovePackageIgnore(machine, pkgID string, global bool) error {
 
 	if global || machine == "" {
 		// Remove from global packages
-		removePackageFromList(&ignoreFile.Global.Packages, pkgType, pkgName)
+		removeIgnoreEntry(&ignoreFile.Global.Packages, pkgType, pkgName)
 	} else {
 		// Remove from machine-specific packages
 		if machineIgnore, ok := ignoreFile.Machines[machine]; ok {
-			removePackageFromList(&machineIgnore.Packages, pkgType, pkgName)
+			removeIgnoreEntry(&machineIgnore.Packages, pkgType, pkgName)
 			ignoreFile.Machines[machine] = machineIgnore
 		}
 	}
@@ -292,6 +305,14 @@ func addPackageToList(list *PackageIgnoreList, pkgType, pkgName string) {
 		if !contains(list.Go, pkgName) {
 			list.Go = append(list.Go, pkgName)
 		}
+	case "npm":
+		if !contains(list.Npm, pkgName) {
+			list.Npm = append(list.Npm, pkgName)
+		}
+	case "pipx":
+		if !contains(list.Pipx, pkgName) {
+			list.Pipx = append(list.Pipx, pkgName)
+		}
 	case "mas":
 		if !contains(list.Mas, pkgName) {
 			list.Mas = append(list.Mas, pkgName)
@@ -299,23 +320,32 @@ func addPackageToList(list *PackageIgnoreList, pkgType, pkgName string) {
 	}
 }
 
-func removePackageFromList(list *PackageIgnoreList, pkgType, pkgName string) {
-	switch pkgType {
-	case "tap":
-		list.Tap = removeString(list.Tap, pkgName)
-	case "brew":
-		list.Brew = removeString(list.Brew, pkgName)
-	case "cask":
-		list.Cask = removeString(list.Cask, pkgName)
-	case "vscode":
-		list.VSCode = removeString(list.VSCode, pkgName)
-	case "cursor":
-		list.Cursor = removeString(list.Cursor, pkgName)
-	case "antigravity":
-		list.Antigravity = removeString(list.Antigravity, pkgName)
-	case "go":
-		list.Go = removeString(list.Go, pkgName)
-	case "mas":
-		list.Mas = removeString(list.Mas, pkgName)
+func addIgnoreEntry(list *IgnoredPackages, pkgType, pkgName, reason string) {
+	entries := list.entries(pkgType)
+	if entries == nil {
+		return
+	}
+	for i := range *entries {
+		if (*entries)[i].Name == pkgName {
+			if reason != "" {
+				(*entries)[i].Reason = reason
+			}
+			return
+		}
+	}
+	*entries = append(*entries, IgnoreEntry{Name: pkgName, Reason: reason})
+}
+
+func removeIgnoreEntry(list *IgnoredPackages, pkgType, pkgName string) {
+	entries := list.entries(pkgType)
+	if entries == nil {
+		return
+	}
+	result := IgnoreEntries{}
+	for _, e := range *entries {
+		if e.Name != pkgName {
+			result = append(result, e)
+		}
 	}
+	*entries = result
 }
@@ -55,9 +55,9 @@ machines:
 	ignoreFile, err := LoadIgnoreFile()
 	require.NoError(t, err)
 	assert.Equal(t, []string{"mas", "go"}, ignoreFile.Global.Categories)
-	assert.Equal(t, []string{"bluestacks"}, ignoreFile.Global.Packages.Cask)
+	assert.Equal(t, []string{"bluestacks"}, ignoreFile.Global.Packages.Cask.Names())
 	assert.Equal(t, []string{"antigravity"}, ignoreFile.Machines["mini"].Categories)
-	assert.Equal(t, []string{"postgresql"}, ignoreFile.Machines["mini"].Packages.Brew)
+	assert.Equal(t, []string{"postgresql"}, ignoreFile.Machines["mini"].Packages.Brew.Names())
 }
 
 func TestSaveIgnoreFile(t *testing.T) {
@@ -67,15 +67,15 @@ func TestSaveIgnoreFile(t *testing.T) {
 	ignoreFile := &IgnoreFile{
 		Global: IgnoreConfig{
 			Categories: []string{"mas"},
-			Packages: PackageIgnoreList{
-				Cask: []string{"app1", "app2"},
+			Packages: IgnoredPackages{
+				Cask: IgnoreEntries{{Name: "app1"}, {Name: "app2", Reason: "trial"}},
 			},
 		},
 		Machines: map[string]IgnoreConfig{
 			"mini": {
 				Categories: []string{"go"},
-				Packages: PackageIgnoreList{
-					Brew: []string{"tool1"},
+				Packages: IgnoredPackages{
+					Brew: IgnoreEntries{{Name: "tool1"}},
 				},
 			},
 		},
@@ -186,7 +186,7 @@ func TestAddPackageIgnore_Global(t *testing.T) {
 	// Verify
 	loaded, err := LoadIgnoreFile()
 	require.NoError(t, err)
-	assert.Contains(t, loaded.Global.Packages.Cask, "bluestacks")
+	assert.Contains(t, loaded.Global.Packages.Cask.Names(), "bluestacks")
 }
 
 func TestAddPackageIgnore_Machine(t *testing.T) {
@@ -203,7 +203,7 @@ func TestAddPackageIgnore_Machine(t *testing.T) {
 	// Verify
 	loaded, err := LoadIgnoreFile()
 	require.NoError(t, err)
-	assert.Contains(t, loaded.Machines["mini"].Packages.Brew, "postgresql")
+	assert.Contains(t, loaded.Machines["mini"].Packages.Brew.Names(), "postgresql")
 }
 
 func TestRemovePackageIgnore(t *testing.T) {
@@ -223,7 +223,7 @@ func TestRemovePackageIgnore(t *testing.T) {
 	// Verify removed
 	loaded, err := LoadIgnoreFile()
 	require.NoError(t, err)
-	assert.NotContains(t, loaded.Global.Packages.Cask, "bluestacks")
+	assert.NotContains(t, loaded.Global.Packages.Cask.Names(), "bluestacks")
 }
 
 func TestAddPackageIgnore_InvalidFormat(t *testing.T) {
@@ -259,7 +259,7 @@ func TestAddPackageIgnore_Duplicate(t *testing.T) {
 	require.NoError(t, err)
 	count := 0
 	for _, pkg := range loaded.Global.Packages.Cask {
-		if pkg == "app" {
+		if pkg.Name == "app" {
 			count++
 		}
 	}
@@ -291,3 +291,80 @@ func TestAddCategoryIgnore_Duplicate(t *testing.T) {
 	}
 	assert.Equal(t, 1, count, "Should only have one entry")
 }
+
+func TestIgnoreFile_ReasonRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	ignorePath := filepath.Join(tmpDir, "ignore.yaml")
+
+	// Legacy plain names and entries with a reason can be mixed
+	content := `global:
+    categories: []
+    packages:
+        cask:
+            - zoom
+            - name: bluestacks
+              reason: work laptop only
+machines: {}
+`
+	require.NoError(t, os.WriteFile(ignorePath, []byte(content), 0644))
+
+	SetIgnorePath(ignorePath)
+	defer SetIgnorePath("")
+
+	loaded, err := LoadIgnoreFile()
+	require.NoError(t, err)
+	assert.Equal(t, IgnoreEntries{{Name: "zoom"}, {Name: "bluestacks", Reason: "work laptop only"}}, loaded.Global.Packages.Cask)
+	assert.Equal(t, []IgnoredPackage{
+		{ID: "cask:zoom"},
+		{ID: "cask:bluestacks", Reason: "work laptop only"},
+	}, loaded.Global.Packages.All())
+
+	// Saving writes legacy entries back as plain names
+	require.NoError(t, SaveIgnoreFile(loaded))
+	data, err := os.ReadFile(ignorePath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestAddPackageIgnoreWithReason(t *testing.T) {
+	tmpDir := t.TempDir()
+	SetIgnorePath(filepath.Join(tmpDir, "ignore.yaml"))
+	defer SetIgnorePath("")
+
+	require.NoError(t, AddPackageIgnoreWithReason("mini", "cask:bluestacks", "work laptop only", false))
+
+	loaded, err := LoadIgnoreFile()
+	require.NoError(t, err)
+	assert.Equal(t, IgnoreEntries{{Name: "bluestacks", Reason: "work laptop only"}}, loaded.Machines["mini"].Packages.Cask)
+
+	// Re-adding without a reason keeps it; a new reason replaces it
+	require.NoError(t, AddPackageIgnore("mini", "cask:bluestacks", false))
+	loaded, err = LoadIgnoreFile()
+	require.NoError(t, err)
+	assert.Equal(t, "work laptop only", loaded.Machines["mini"].Packages.Cask[0].Reason)
+
+	require.NoError(t, AddPackageIgnoreWithReason("mini", "cask:bluestacks", "Android emulator", false))
+	loaded, err = LoadIgnoreFile()
+	require.NoError(t, err)
+	assert.Equal(t, IgnoreEntries{{Name: "bluestacks", Reason: "Android emulator"}}, loaded.Machines["mini"].Packages.Cask)
+
+	require.NoError(t, RemovePackageIgnore("mini", "cask:bluestacks", false))
+	loaded, err = LoadIgnoreFile()
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Machines["mini"].Packages.Cask)
+}
+
+func TestAddPackageIgnore_SameNameAcrossTypes(t *testing.T) {
+	SetIgnorePath(filepath.Join(t.TempDir(), "ignore.yaml"))
+	defer func() { SetIgnorePath("") }()
+
+	require.NoError(t, AddPackageIgnore("", "brew:docker", true))
+	require.NoError(t, AddPackageIgnore("", "cask:docker", true))
+	require.NoError(t, RemovePackageIgnore("", "brew:docker", true))
+
+	loaded, err := LoadIgnoreFile()
+	require.NoError(t, err)
+	c := &Config{ignoreFile: loaded}
+	assert.True(t, c.IsPackageIgnored("mini", "cask:docker"))
+	assert.False(t, c.IsPackageIgnored("mini", "brew:docker"))
+}
@@ -3,9 +3,13 @@ package config
 import (
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 )
 
+// localHostname looks up the hostname DetectMachine matches against (replaced in tests)
+var localHostname = GetLocalHostname
+
 // GetLocalHostname returns the local hostname using scutil
 func GetLocalHostname() (string, error) {
 	cmd := exec.Command("scutil", "--get", "LocalHostName")
@@ -16,18 +20,60 @@ func GetLocalHostname() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// DetectMachine attempts to detect the current machine based on hostname
+// AmbiguousMachineError is returned by DetectMachine when several machines share
+// the local hostname. DetectMachine still returns the first of Machines.
+type AmbiguousMachineError struct {
+	Hostname string
+	Machines []string // sorted
+}
+
+func (e *AmbiguousMachineError) Error() string {
+	return fmt.Sprintf("hostname %q matches machines %s; using %q (set current_machine to choose)",
+		e.Hostname, strings.Join(e.Machines, ", "), e.Machines[0])
+}
+
+// DetectMachine attempts to detect the current machine based on hostname.
+// When several machines match, it returns the alphabetically first one together
+// with an *AmbiguousMachineError.
 func DetectMachine(machines map[string]Machine) (string, error) {
-	hostname, err := GetLocalHostname()
+	hostname, err := localHostname()
 	if err != nil {
 		return "", err
 	}
 
+	var matches []string
 	for name, machine := range machines {
 		if machine.Hostname == hostname {
-			return name, nil
+			matches = append(matches, name)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no machine found matching hostname %q", hostname)
+	case 1:
+		return matches[0], nil
+	}
+	sort.Strings(matches)
+	return matches[0], &AmbiguousMachineError{Hostname: hostname, Machines: matches}
+}
+
+// DuplicateHostnames returns the hostnames configured on more than one machine,
+// mapped to those machines' names (sorted). Empty hostnames are not compared.
+func DuplicateHostnames(machines map[string]Machine) map[string][]string {
+	byHostname := make(map[string][]string)
+	for name, machine := range machines {
+		if machine.Hostname != "" {
+			byHostname[machine.Hostname] = append(byHostname[machine.Hostname], name)
 		}
 	}
 
-	return "", fmt.Errorf("no machine found matching hostname %q", hostname)
+	duplicates := make(map[string][]string)
+	for hostname, names := range byHostname {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates[hostname] = names
+		}
+	}
+	return duplicates
 }
@@ -1,11 +1,24 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// stubHostname makes DetectMachine see hostname for the rest of the test
+func stubHostname(t *testing.T, hostname string) {
+	t.Helper()
+	orig := localHostname
+	localHostname = func() (string, error) { return hostname, nil }
+	t.Cleanup(func() { localHostname = orig })
+}
+
 func TestDetectMachine(t *testing.T) {
 	// Get actual hostname for testing
 	actualHostname, err := GetLocalHostname()
@@ -63,3 +76,94 @@ func TestGetLocalHostname(t *testing.T) {
 	// Hostname shouldn't contain newlines
 	assert.NotContains(t, hostname, "\n")
 }
+
+func TestDetectMachine_DuplicateHostname(t *testing.T) {
+	stubHostname(t, "shared")
+	machines := map[string]Machine{
+		"studio": {Hostname: "shared"},
+		"air":    {Hostname: "shared"},
+		"mini":   {Hostname: "shared"},
+		"other":  {Hostname: "elsewhere"},
+	}
+
+	for i := 0; i < 20; i++ {
+		name, err := DetectMachine(machines)
+		assert.Equal(t, "air", name)
+
+		var ambiguous *AmbiguousMachineError
+		require.True(t, errors.As(err, &ambiguous))
+		assert.Equal(t, "shared", ambiguous.Hostname)
+		assert.Equal(t, []string{"air", "mini", "studio"}, ambiguous.Machines)
+	}
+}
+
+func TestDuplicateHostnames(t *testing.T) {
+	machines := map[string]Machine{
+		"studio": {Hostname: "shared"},
+		"air":    {Hostname: "shared"},
+		"mini":   {Hostname: "mini-host"},
+		"new1":   {},
+		"new2":   {},
+	}
+
+	assert.Equal(t, map[string][]string{"shared": {"air", "studio"}}, DuplicateHostnames(machines))
+	assert.Empty(t, DuplicateHostnames(map[string]Machine{"mini": {Hostname: "mini-host"}}))
+}
+
+func TestLoad_WarnsDuplicateHostnames(t *testing.T) {
+	stubHostname(t, "shared")
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+machines:
+  studio:
+    hostname: shared
+    brewfile: /tmp/studio/Brewfile
+  air:
+    hostname: shared
+    brewfile: /tmp/air/Brewfile
+current_machine: auto
+`), 0644))
+
+	viper.Reset()
+	cfg = nil
+	origConfigPath := configPath
+	origIgnorePath := ignorePath
+	t.Setenv("MACHINE", "")
+	os.Unsetenv("MACHINE")
+	t.Cleanup(func() {
+		configPath = origConfigPath
+		ignorePath = origIgnorePath
+		cfg = nil
+		viper.Reset()
+	})
+	SetConfigPath(configFile)
+	SetIgnorePath(filepath.Join(dir, "ignore.yaml"))
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "air", loaded.CurrentMachine)
+	assert.Equal(t, []string{
+		`machines air, studio share hostname "shared"`,
+		`hostname "shared" matches machines air, studio; using "air" (set current_machine to choose)`,
+	}, loaded.Warnings())
+}
+
+func TestConfig_UseBrewBundle(t *testing.T) {
+	yes, no := true, false
+	c := &Config{
+		Machines: map[string]Machine{
+			"mini":   {},
+			"studio": {UseBrewBundle: &no},
+			"air":    {UseBrewBundle: &yes},
+		},
+		Dump: DumpConfig{UseBrewBundle: true},
+	}
+	assert.True(t, c.UseBrewBundle("mini"))
+	assert.False(t, c.UseBrewBundle("studio"))
+	assert.True(t, c.UseBrewBundle("unknown"))
+
+	c.Dump.UseBrewBundle = false
+	assert.False(t, c.UseBrewBundle("mini"))
+	assert.True(t, c.UseBrewBundle("air"))
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CurrentSchemaVersion is the config layout this version of brewsync writes.
+// Bump it together with a new entry in migrations.
+const CurrentSchemaVersion = 2
+
+// migrations upgrade a config from schema version i to i+1 and describe what
+// they changed. Older config files (and ones written by earlier setup wizards)
+// may lack fields or carry empty values that later code relies on.
+var migrations = []func(c *Config) []string{
+	migrateV0,
+	migrateV1,
+}
+
+// v1Categories are the package types schema version 1 knew about
+var v1Categories = []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"}
+
+// v2Categories are the package types added in schema version 2
+var v2Categories = []string{"npm", "pipx"}
+
+// migrate upgrades c in memory to CurrentSchemaVersion and returns what changed.
+// Configs from a newer brewsync are left alone.
+func migrate(c *Config) []string {
+	var changes []string
+	for c.SchemaVersion < CurrentSchemaVersion && c.SchemaVersion < len(migrations) {
+		changes = append(changes, migrations[c.SchemaVersion](c)...)
+		c.SchemaVersion++
+	}
+	return changes
+}
+
+// migrateV0 fills settings that unversioned configs left empty. An empty
+// default_categories stays empty: it already means every package type,
+// including ones added later.
+func migrateV0(c *Config) []string {
+	var changes []string
+
+	if c.Machines == nil {
+		c.Machines = make(map[string]Machine)
+	}
+	if c.ConflictResolution == "" {
+		c.ConflictResolution = ConflictAsk
+		changes = append(changes, fmt.Sprintf("conflict_resolution: set to %q", ConflictAsk))
+	}
+	if c.AutoDump.CommitMessage == "" {
+		c.AutoDump.CommitMessage = DefaultCommitMessage
+		changes = append(changes, "auto_dump.commit_message: set to the default message")
+	}
+	if c.Sync.PullStrategy == "" {
+		c.Sync.PullStrategy = "stash"
+		changes = append(changes, `sync.pull_strategy: set to "stash"`)
+	}
+
+	return changes
+}
+
+// migrateV1 adds the package types introduced since schema version 1 to a
+// default_categories list that named every type then (as config init and the
+// setup wizard wrote it), so dump and sync pick them up. A shorter list was
+// picked by hand and is left alone.
+func migrateV1(c *Config) []string {
+	if len(c.DefaultCategories) == 0 {
+		return nil
+	}
+	for _, cat := range v1Categories {
+		if !slices.Contains(c.DefaultCategories, cat) {
+			return nil
+		}
+	}
+
+	categories := slices.Clone(c.DefaultCategories)
+	var added []string
+	for _, cat := range v2Categories {
+		if !slices.Contains(categories, cat) {
+			categories = append(categories, cat)
+			added = append(added, cat)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	c.DefaultCategories = categories
+	return []string{fmt.Sprintf("default_categories: added %s", strings.Join(added, ", "))}
+}
+
+// Migrations returns what Load changed to upgrade an older config file.
+// It is empty when the file was current; Save writes the upgraded config.
+func (c *Config) Migrations() []string {
+	return c.migrations
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_MigratesV0Config(t *testing.T) {
+	viper.Reset()
+	cfg = nil
+	origConfigPath := configPath
+	t.Setenv("MACHINE", "")
+	defer func() {
+		configPath = origConfigPath
+		cfg = nil
+		viper.Reset()
+	}()
+
+	// An unversioned config as written by an old setup wizard, with explicit empties
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+machines:
+  mini:
+    hostname: mini
+    brewfile: /tmp/Brewfile.mini
+current_machine: mini
+default_categories: []
+conflict_resolution: ""
+auto_dump:
+  commit_message: ""
+sync:
+  pull_strategy: ""
+`), 0644))
+	configPath = configFile
+
+	loaded, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, CurrentSchemaVersion, loaded.SchemaVersion)
+	assert.Empty(t, loaded.DefaultCategories)
+	assert.Equal(t, DefaultCategories, loaded.EffectiveCategories())
+	assert.Equal(t, ConflictAsk, loaded.ConflictResolution)
+	assert.Equal(t, DefaultCommitMessage, loaded.AutoDump.CommitMessage)
+	assert.Equal(t, "stash", loaded.Sync.PullStrategy)
+	assert.Len(t, loaded.Migrations(), 3)
+	assert.Contains(t, loaded.Migrations(), `sync.pull_strategy: set to "stash"`)
+
+	// Saving writes the current schema; reloading has nothing left to migrate
+	require.NoError(t, Save(loaded))
+	assert.Empty(t, loaded.Migrations())
+
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "schema_version: 2")
+
+	SetConfigPath(configFile)
+	reloaded, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, reloaded.Migrations())
+	assert.Equal(t, DefaultCategories, reloaded.EffectiveCategories())
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("current config is untouched", func(t *testing.T) {
+		c := &Config{SchemaVersion: CurrentSchemaVersion}
+		assert.Empty(t, migrate(c))
+		assert.Empty(t, c.DefaultCategories)
+	})
+
+	t.Run("newer config is left alone", func(t *testing.T) {
+		c := &Config{SchemaVersion: CurrentSchemaVersion + 1}
+		assert.Empty(t, migrate(c))
+		assert.Equal(t, CurrentSchemaVersion+1, c.SchemaVersion)
+	})
+
+	t.Run("v1 with every type of its time picks up npm and pipx", func(t *testing.T) {
+		c := &Config{SchemaVersion: 1, DefaultCategories: slices.Clone(v1Categories)}
+		assert.Equal(t, []string{"default_categories: added npm, pipx"}, migrate(c))
+		assert.Equal(t, CurrentSchemaVersion, c.SchemaVersion)
+		assert.True(t, c.CategoryEnabled("npm"))
+		assert.True(t, c.CategoryEnabled("pipx"))
+	})
+
+	t.Run("v1 with a hand-picked list keeps it", func(t *testing.T) {
+		c := &Config{SchemaVersion: 1, DefaultCategories: []string{"brew", "cask"}}
+		assert.Empty(t, migrate(c))
+		assert.False(t, c.CategoryEnabled("npm"))
+		assert.False(t, c.CategoryEnabled("pipx"))
+	})
+
+	t.Run("v0 with every setting present only bumps the version", func(t *testing.T) {
+		c := &Config{
+			Machines:           map[string]Machine{},
+			DefaultCategories:  []string{"brew"},
+			ConflictResolution: ConflictSkip,
+			AutoDump:           AutoDumpConfig{CommitMessage: "dump"},
+			Sync:               SyncConfig{PullStrategy: "refuse"},
+		}
+		assert.Empty(t, migrate(c))
+		assert.Equal(t, CurrentSchemaVersion, c.SchemaVersion)
+		assert.Equal(t, []string{"brew"}, c.DefaultCategories)
+	})
+}
+
+func TestLoad_OldConfigPicksUpNewTypes(t *testing.T) {
+	viper.Reset()
+	cfg = nil
+	origConfigPath := configPath
+	t.Setenv("MACHINE", "")
+	defer func() {
+		configPath = origConfigPath
+		cfg = nil
+		viper.Reset()
+	}()
+
+	// As written by config init before npm and pipx existed
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+schema_version: 1
+machines:
+  mini:
+    brewfile: /tmp/Brewfile.mini
+current_machine: mini
+default_categories: [tap, brew, cask, vscode, cursor, antigravity, go, mas]
+`), 0644))
+	configPath = configFile
+
+	loaded, err := Load()
+	require.NoError(t, err)
+	assert.True(t, loaded.CategoryEnabled("npm"))
+	assert.True(t, loaded.CategoryEnabled("pipx"))
+	assert.Equal(t, []string{"default_categories: added npm, pipx"}, loaded.Migrations())
+}
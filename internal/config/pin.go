@@ -0,0 +1,42 @@
+package config
+
+// IsPinned checks if a package is pinned
+// Package ID format: "type:name" (e.g., "brew:postgresql@14")
+func (c *Config) IsPinned(pkgID string) bool {
+	return contains(c.Pinned, pkgID)
+}
+
+// PinnedSet returns pinned package IDs as a lookup map
+func (c *Config) PinnedSet() map[string]bool {
+	result := make(map[string]bool, len(c.Pinned))
+	for _, id := range c.Pinned {
+		result[id] = true
+	}
+	return result
+}
+
+// AddPin pins a package so it is never offered for removal or upgrade.
+// Returns false if the package was already pinned. Call Save to persist.
+func (c *Config) AddPin(pkgID string) (bool, error) {
+	if _, _, err := parsePackageID(pkgID); err != nil {
+		return false, err
+	}
+	if c.IsPinned(pkgID) {
+		return false, nil
+	}
+	c.Pinned = append(c.Pinned, pkgID)
+	return true, nil
+}
+
+// RemovePin unpins a package. Returns false if the package was not pinned.
+// Call Save to persist.
+func (c *Config) RemovePin(pkgID string) (bool, error) {
+	if _, _, err := parsePackageID(pkgID); err != nil {
+		return false, err
+	}
+	if !c.IsPinned(pkgID) {
+		return false, nil
+	}
+	c.Pinned = removeString(c.Pinned, pkgID)
+	return true, nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Pins(t *testing.T) {
+	cfg := &Config{}
+
+	added, err := cfg.AddPin("brew:node@18")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = cfg.AddPin("brew:node@18")
+	require.NoError(t, err)
+	assert.False(t, added, "pinning twice is a no-op")
+
+	_, err = cfg.AddPin("cask:docker")
+	require.NoError(t, err)
+
+	assert.True(t, cfg.IsPinned("brew:node@18"))
+	assert.False(t, cfg.IsPinned("cask:node@18"))
+	assert.Equal(t, map[string]bool{"brew:node@18": true, "cask:docker": true}, cfg.PinnedSet())
+
+	removed, err := cfg.RemovePin("brew:node@18")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	assert.False(t, cfg.IsPinned("brew:node@18"))
+
+	removed, err = cfg.RemovePin("brew:node@18")
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestConfig_AddPin_InvalidID(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.AddPin("node")
+	assert.Error(t, err)
+	assert.Empty(t, cfg.Pinned)
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// HomeEnvVar is the environment variable that overrides the state directory
+	HomeEnvVar = "BREWSYNC_HOME"
+	// XDGConfigEnvVar is the XDG base directory variable for config files
+	XDGConfigEnvVar = "XDG_CONFIG_HOME"
+)
+
+// StateDir returns the directory brewsync keeps its state in: config.yaml,
+// ignore.yaml, history, profiles, caches and backups. It is $BREWSYNC_HOME
+// when set, else $XDG_CONFIG_HOME/brewsync, else ~/.config/brewsync.
+// A config file set with --config or $BREWSYNC_CONFIG still takes precedence
+// for the config file itself (see ConfigPath).
+func StateDir() string {
+	if dir := os.Getenv(HomeEnvVar); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv(XDGConfigEnvVar); dir != "" {
+		return filepath.Join(dir, ConfigDirName)
+	}
+	return LegacyStateDir()
+}
+
+// LegacyStateDir returns ~/.config/brewsync, where brewsync kept its state
+// before $BREWSYNC_HOME and $XDG_CONFIG_HOME were honored
+func LegacyStateDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		// Fallback to $HOME (should rarely happen)
+		home = os.Getenv("HOME")
+	}
+	return filepath.Join(home, ".config", ConfigDirName)
+}
+
+// PendingStateMigration reports whether state should be moved from the legacy
+// directory to StateDir: the legacy directory has files, StateDir is elsewhere
+// and is missing or empty. It returns both directories.
+func PendingStateMigration() (from, to string, pending bool) {
+	from, to = LegacyStateDir(), StateDir()
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return from, to, false
+	}
+	return from, to, !isEmptyDir(from) && isEmptyDir(to)
+}
+
+// isEmptyDir returns true if dir is missing or has no entries
+func isEmptyDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err != nil || len(entries) == 0
+}
+
+// MigrateState copies everything in from into to, creating to as needed.
+// Symlinks, as dotfile managers create for config.yaml or the whole
+// directory, are followed and their targets copied. It fails if there was
+// nothing to copy. The old directory is left in place so nothing is lost if
+// the copy is interrupted; callers tell the user it can be removed.
+func MigrateState(from, to string) error {
+	copied, err := copyDir(from, to)
+	if err == nil && copied == 0 {
+		err = fmt.Errorf("no files to copy")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s to %s: %w", from, to, err)
+	}
+	return nil
+}
+
+// copyDir copies the files in from into to, following symlinks, and returns
+// how many it copied
+func copyDir(from, to string) (int, error) {
+	root, err := filepath.EvalSymlinks(from)
+	if err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(path); err != nil {
+				// A dangling link has nothing to copy
+				return nil
+			}
+			if info.IsDir() {
+				n, err := copyDir(path, target)
+				copied += n
+				return err
+			}
+		}
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, 0755)
+		case info.Mode().IsRegular():
+			copied++
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			// Sockets and the like aren't brewsync state
+			return nil
+		}
+	})
+	return copied, err
+}
+
+// copyFile copies src to dst with the given permissions
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
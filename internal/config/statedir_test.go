@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv(HomeEnvVar, "")
+		t.Setenv(XDGConfigEnvVar, "")
+		assert.Equal(t, filepath.Join(home, ".config", "brewsync"), StateDir())
+	})
+
+	t.Run("XDG_CONFIG_HOME", func(t *testing.T) {
+		t.Setenv(HomeEnvVar, "")
+		t.Setenv(XDGConfigEnvVar, "/xdg")
+		assert.Equal(t, "/xdg/brewsync", StateDir())
+	})
+
+	t.Run("BREWSYNC_HOME wins", func(t *testing.T) {
+		t.Setenv(HomeEnvVar, "/state")
+		t.Setenv(XDGConfigEnvVar, "/xdg")
+		assert.Equal(t, "/state", StateDir())
+	})
+
+	t.Run("state files follow", func(t *testing.T) {
+		origConfigPath, origIgnorePath := configPath, ignorePath
+		defer func() { configPath, ignorePath = origConfigPath, origIgnorePath }()
+		configPath, ignorePath = "", ""
+		t.Setenv(ConfigEnvVar, "")
+		t.Setenv(HomeEnvVar, "/state")
+
+		path, err := ConfigPath()
+		require.NoError(t, err)
+		assert.Equal(t, "/state/config.yaml", path)
+		assert.Equal(t, "/state/ignore.yaml", IgnorePath())
+		assert.Equal(t, "/state/cache", CacheDir())
+
+		history, err := HistoryPath()
+		require.NoError(t, err)
+		assert.Equal(t, "/state/history.log", history)
+
+		profiles, err := ProfilesDir()
+		require.NoError(t, err)
+		assert.Equal(t, "/state/profiles", profiles)
+	})
+}
+
+func TestPendingStateMigration(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(XDGConfigEnvVar, "")
+	legacy := filepath.Join(home, ".config", "brewsync")
+	newDir := filepath.Join(t.TempDir(), "brewsync")
+
+	// Same directory: nothing to do
+	t.Setenv(HomeEnvVar, "")
+	_, _, pending := PendingStateMigration()
+	assert.False(t, pending)
+
+	// Nothing in the old location
+	t.Setenv(HomeEnvVar, newDir)
+	_, _, pending = PendingStateMigration()
+	assert.False(t, pending)
+
+	// Old state and no new state
+	require.NoError(t, os.MkdirAll(filepath.Join(legacy, "cache"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("machines: {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "cache", "descriptions.json"), []byte("{}"), 0600))
+	from, to, pending := PendingStateMigration()
+	assert.True(t, pending)
+	assert.Equal(t, legacy, from)
+	assert.Equal(t, newDir, to)
+
+	require.NoError(t, MigrateState(from, to))
+	data, err := os.ReadFile(filepath.Join(newDir, "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "machines: {}\n", string(data))
+	info, err := os.Stat(filepath.Join(newDir, "cache", "descriptions.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.FileExists(t, filepath.Join(legacy, "config.yaml"))
+
+	// New location now has state
+	_, _, pending = PendingStateMigration()
+	assert.False(t, pending)
+}
+
+func TestMigrateState_Symlinks(t *testing.T) {
+	// A dotfiles repository that config.yaml links into
+	dotfiles := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dotfiles, "config.yaml"), []byte("machines: {}\n"), 0600))
+
+	legacy := filepath.Join(t.TempDir(), "brewsync")
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	require.NoError(t, os.Symlink(filepath.Join(dotfiles, "config.yaml"), filepath.Join(legacy, "config.yaml")))
+	require.NoError(t, os.Symlink(filepath.Join(dotfiles, "missing.yaml"), filepath.Join(legacy, "ignore.yaml")))
+
+	to := filepath.Join(t.TempDir(), "brewsync")
+	require.NoError(t, MigrateState(legacy, to))
+	info, err := os.Lstat(filepath.Join(to, "config.yaml"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(filepath.Join(to, "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "machines: {}\n", string(data))
+	assert.NoFileExists(t, filepath.Join(to, "ignore.yaml"))
+
+	// The legacy directory itself linked into the dotfiles
+	link := filepath.Join(t.TempDir(), "brewsync")
+	require.NoError(t, os.Symlink(dotfiles, link))
+	to = filepath.Join(t.TempDir(), "brewsync")
+	require.NoError(t, MigrateState(link, to))
+	assert.FileExists(t, filepath.Join(to, "config.yaml"))
+
+	// Nothing to copy is an error, not a silent success
+	empty := t.TempDir()
+	require.NoError(t, os.Symlink(filepath.Join(dotfiles, "missing.yaml"), filepath.Join(empty, "config.yaml")))
+	assert.ErrorContains(t, MigrateState(empty, filepath.Join(t.TempDir(), "brewsync")), "no files to copy")
+}
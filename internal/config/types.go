@@ -1,10 +1,13 @@
 package config
 
+import "gopkg.in/yaml.v3"
+
 // Machine represents a macOS machine configuration
 type Machine struct {
-	Hostname    string `yaml:"hostname" mapstructure:"hostname"`
-	Brewfile    string `yaml:"brewfile" mapstructure:"brewfile"`
-	Description string `yaml:"description,omitempty" mapstructure:"description"`
+	Hostname      string `yaml:"hostname" mapstructure:"hostname"`
+	Brewfile      string `yaml:"brewfile" mapstructure:"brewfile"`
+	Description   string `yaml:"description,omitempty" mapstructure:"description"`
+	UseBrewBundle *bool  `yaml:"use_brew_bundle,omitempty" mapstructure:"use_brew_bundle"` // Overrides dump.use_brew_bundle on this machine (unset = inherit)
 }
 
 // AutoDumpConfig configures automatic Brewfile updates
@@ -19,6 +22,38 @@ type AutoDumpConfig struct {
 // DumpConfig configures how dump command works
 type DumpConfig struct {
 	UseBrewBundle bool `yaml:"use_brew_bundle" mapstructure:"use_brew_bundle"` // Use 'brew bundle dump --describe' for Homebrew packages
+	Header        bool `yaml:"header" mapstructure:"header"`                   // Start new Brewfiles with a comment block naming the machine and last dump time
+	// ExtensionVersions pins editor extensions to their installed version (vscode "pub.ext@1.2.3")
+	ExtensionVersions bool `yaml:"extension_versions" mapstructure:"extension_versions"`
+	// PreserveComments keeps hand-written comments, blank lines and package order when rewriting the Brewfile
+	PreserveComments bool `yaml:"preserve_comments" mapstructure:"preserve_comments"`
+}
+
+// SyncConfig configures how sync command works
+type SyncConfig struct {
+	PullStrategy string `yaml:"pull_strategy" mapstructure:"pull_strategy"` // What to do with uncommitted changes before 'sync --pull': stash or refuse
+}
+
+// InstallConfig configures how packages are installed
+type InstallConfig struct {
+	CaskNoQuarantine bool `yaml:"cask_no_quarantine" mapstructure:"cask_no_quarantine"` // Install casks with --no-quarantine (managed Macs, unattended sync)
+	CheckBrew        bool `yaml:"check_brew" mapstructure:"check_brew"`                 // Run 'brew doctor' before sync and import, aborting on errors
+	Retries          int  `yaml:"retries" mapstructure:"retries"`                       // Retry a failed install up to this many times, waiting longer each time
+	Parallel         int  `yaml:"parallel" mapstructure:"parallel"`                     // Install up to this many packages at once (casks and mas apps still one by one)
+}
+
+// ImportConfig configures the import command
+type ImportConfig struct {
+	RememberDeselected bool `yaml:"remember_deselected" mapstructure:"remember_deselected"` // Pre-deselect packages left unselected in earlier imports from the same source
+}
+
+// FlagDefaults maps a command ("import", "profile install") to default values
+// for its flags. Flags given on the command line override them.
+type FlagDefaults map[string]map[string]interface{}
+
+// AuditConfig configures the audit log of package operations
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"` // Append every install/uninstall to audit.log in the state directory
 }
 
 // PackageIgnoreList holds ignored packages by type
@@ -30,13 +65,126 @@ type PackageIgnoreList struct {
 	Cursor      []string `yaml:"cursor,omitempty" mapstructure:"cursor"`
 	Antigravity []string `yaml:"antigravity,omitempty" mapstructure:"antigravity"`
 	Go          []string `yaml:"go,omitempty" mapstructure:"go"`
+	Npm         []string `yaml:"npm,omitempty" mapstructure:"npm"`
+	Pipx        []string `yaml:"pipx,omitempty" mapstructure:"pipx"`
 	Mas         []string `yaml:"mas,omitempty" mapstructure:"mas"`
 }
 
+// IgnoreEntry is an ignored package name with an optional reason. In ignore.yaml
+// it is written as a plain name unless it has a reason:
+//
+//	cask:
+//	  - zoom
+//	  - name: bluestacks
+//	    reason: work laptop only
+type IgnoreEntry struct {
+	Name   string `yaml:"name"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// UnmarshalYAML accepts both a plain name (older ignore files) and a mapping
+func (e *IgnoreEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*e = IgnoreEntry{Name: node.Value}
+		return nil
+	}
+	type plain IgnoreEntry
+	return node.Decode((*plain)(e))
+}
+
+// MarshalYAML writes entries without a reason as a plain name
+func (e IgnoreEntry) MarshalYAML() (interface{}, error) {
+	if e.Reason == "" {
+		return e.Name, nil
+	}
+	type plain IgnoreEntry
+	return plain(e), nil
+}
+
+// IgnoreEntries is a list of ignored package names of one type
+type IgnoreEntries []IgnoreEntry
+
+// Names returns the package names in order
+func (l IgnoreEntries) Names() []string {
+	names := make([]string, len(l))
+	for i, e := range l {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// IgnoredPackages holds ignored packages by type, each with an optional reason
+type IgnoredPackages struct {
+	Tap         IgnoreEntries `yaml:"tap,omitempty"`
+	Brew        IgnoreEntries `yaml:"brew,omitempty"`
+	Cask        IgnoreEntries `yaml:"cask,omitempty"`
+	VSCode      IgnoreEntries `yaml:"vscode,omitempty"`
+	Cursor      IgnoreEntries `yaml:"cursor,omitempty"`
+	Antigravity IgnoreEntries `yaml:"antigravity,omitempty"`
+	Go          IgnoreEntries `yaml:"go,omitempty"`
+	Npm         IgnoreEntries `yaml:"npm,omitempty"`
+	Pipx        IgnoreEntries `yaml:"pipx,omitempty"`
+	Mas         IgnoreEntries `yaml:"mas,omitempty"`
+}
+
+// IgnoredPackage is an ignored package ID ("type:name") and why it is ignored
+type IgnoredPackage struct {
+	ID     string
+	Reason string
+}
+
+// byType returns the entry lists paired with their package type, in display order
+func (p *IgnoredPackages) byType() []struct {
+	pkgType string
+	entries *IgnoreEntries
+} {
+	return []struct {
+		pkgType string
+		entries *IgnoreEntries
+	}{
+		{"tap", &p.Tap},
+		{"brew", &p.Brew},
+		{"cask", &p.Cask},
+		{"vscode", &p.VSCode},
+		{"cursor", &p.Cursor},
+		{"antigravity", &p.Antigravity},
+		{"go", &p.Go},
+		{"npm", &p.Npm},
+		{"pipx", &p.Pipx},
+		{"mas", &p.Mas},
+	}
+}
+
+// entries returns the entry list for a package type, or nil for an unknown type
+func (p *IgnoredPackages) entries(pkgType string) *IgnoreEntries {
+	for _, t := range p.byType() {
+		if t.pkgType == pkgType {
+			return t.entries
+		}
+	}
+	return nil
+}
+
+// All returns every ignored package with its reason, grouped by type
+func (p IgnoredPackages) All() []IgnoredPackage {
+	var result []IgnoredPackage
+	for _, t := range p.byType() {
+		for _, e := range *t.entries {
+			result = append(result, IgnoredPackage{ID: t.pkgType + ":" + e.Name, Reason: e.Reason})
+		}
+	}
+	return result
+}
+
+// IsEmpty returns true if no packages are ignored
+func (p IgnoredPackages) IsEmpty() bool {
+	return len(p.All()) == 0
+}
+
 // IgnoreConfig holds category and package-level ignores
 type IgnoreConfig struct {
-	Categories []string          `yaml:"categories"` // Ignore entire categories (e.g., "mas", "go")
-	Packages   PackageIgnoreList `yaml:"packages"`   // Ignore specific packages within non-ignored categories
+	Categories []string        `yaml:"categories"` // Ignore entire categories (e.g., "mas", "go")
+	Packages   IgnoredPackages `yaml:"packages"`   // Ignore specific packages within non-ignored categories
 }
 
 // IgnoreFile represents the separate ignore.yaml file
@@ -53,6 +201,11 @@ type OutputConfig struct {
 	Color            bool `yaml:"color" mapstructure:"color"`
 	Verbose          bool `yaml:"verbose" mapstructure:"verbose"`
 	ShowDescriptions bool `yaml:"show_descriptions" mapstructure:"show_descriptions"`
+	Notify           bool `yaml:"notify" mapstructure:"notify"` // Bell + desktop notification when sync/import/dump finishes
+	// Icons is how package types are drawn: emoji, nerdfont or ascii
+	Icons string `yaml:"icons" mapstructure:"icons"`
+	// Pager pages long diff, status and list tables through $PAGER in a terminal
+	Pager bool `yaml:"pager" mapstructure:"pager"`
 }
 
 // HooksConfig holds shell commands to run at various points
@@ -75,19 +228,33 @@ const (
 
 // Config is the main configuration structure
 type Config struct {
+	SchemaVersion      int                   `yaml:"schema_version" mapstructure:"schema_version"` // Config layout version; older files are migrated on load
 	Machines           map[string]Machine    `yaml:"machines" mapstructure:"machines"`
 	CurrentMachine     string                `yaml:"current_machine" mapstructure:"current_machine"`
 	DefaultSource      string                `yaml:"default_source" mapstructure:"default_source"`
+	StatusSources      []string              `yaml:"status_sources" mapstructure:"status_sources"` // Sources status compares against (defaults to default_source)
 	DefaultCategories  []string              `yaml:"default_categories" mapstructure:"default_categories"`
 	AutoDump           AutoDumpConfig        `yaml:"auto_dump" mapstructure:"auto_dump"`
 	Dump               DumpConfig            `yaml:"dump" mapstructure:"dump"`
+	Sync               SyncConfig            `yaml:"sync" mapstructure:"sync"`
+	Install            InstallConfig         `yaml:"install" mapstructure:"install"`
+	Import             ImportConfig          `yaml:"import" mapstructure:"import"`
 	MachineSpecific    MachineSpecificConfig `yaml:"machine_specific" mapstructure:"machine_specific"`
+	Pinned             []string              `yaml:"pinned" mapstructure:"pinned"`       // Package IDs (type:name) never offered for removal or upgrade
+	ExtensionAliases   map[string]string     `yaml:"extension_aliases" mapstructure:"-"` // Editor extension ID moves (old -> new), treated as equal in diffs; read by readExtensionAliases
 	ConflictResolution ConflictResolution    `yaml:"conflict_resolution" mapstructure:"conflict_resolution"`
+	Keybindings        map[string]string     `yaml:"keybindings" mapstructure:"keybindings"` // TUI action -> comma-separated keys (e.g. history: "y")
 	Output             OutputConfig          `yaml:"output" mapstructure:"output"`
 	Hooks              HooksConfig           `yaml:"hooks" mapstructure:"hooks"`
+	Audit              AuditConfig           `yaml:"audit" mapstructure:"audit"`
+	Defaults           FlagDefaults          `yaml:"defaults" mapstructure:"defaults"` // Default flag values per command (e.g. import: {include-machine-specific: true})
 
 	// Loaded separately from ignore.yaml (not in YAML)
 	ignoreFile *IgnoreFile
+	// Changes made by Load to upgrade an older schema (see Migrations)
+	migrations []string
+	// Problems Load noticed that don't stop brewsync (see Warnings)
+	warnings []string
 }
 
 // GetMachine returns the machine config for the given name
@@ -101,6 +268,34 @@ func (c *Config) GetCurrentMachine() (Machine, bool) {
 	return c.GetMachine(c.CurrentMachine)
 }
 
+// UseBrewBundle reports whether dump should use 'brew bundle dump' on the
+// named machine: its use_brew_bundle override if set, else dump.use_brew_bundle
+func (c *Config) UseBrewBundle(machine string) bool {
+	if m, ok := c.Machines[machine]; ok && m.UseBrewBundle != nil {
+		return *m.UseBrewBundle
+	}
+	return c.Dump.UseBrewBundle
+}
+
+// EffectiveCategories returns the package types brewsync works with
+// (default_categories, or every type when the list is empty)
+func (c *Config) EffectiveCategories() []string {
+	if len(c.DefaultCategories) == 0 {
+		return DefaultCategories
+	}
+	return c.DefaultCategories
+}
+
+// CategoryEnabled returns true if the package type is in the effective categories
+func (c *Config) CategoryEnabled(category string) bool {
+	for _, cat := range c.EffectiveCategories() {
+		if cat == category {
+			return true
+		}
+	}
+	return false
+}
+
 // GetIgnoredCategories returns all ignored categories for a machine (global + machine-specific)
 func (c *Config) GetIgnoredCategories(machine string) []string {
 	if c.ignoreFile == nil {
@@ -142,25 +337,15 @@ func (c *Config) GetIgnoredPackages(machine string) []string {
 	var result []string
 
 	// Add global ignored packages
-	result = append(result, addPrefix("tap", c.ignoreFile.Global.Packages.Tap)...)
-	result = append(result, addPrefix("brew", c.ignoreFile.Global.Packages.Brew)...)
-	result = append(result, addPrefix("cask", c.ignoreFile.Global.Packages.Cask)...)
-	result = append(result, addPrefix("vscode", c.ignoreFile.Global.Packages.VSCode)...)
-	result = append(result, addPrefix("cursor", c.ignoreFile.Global.Packages.Cursor)...)
-	result = append(result, addPrefix("antigravity", c.ignoreFile.Global.Packages.Antigravity)...)
-	result = append(result, addPrefix("go", c.ignoreFile.Global.Packages.Go)...)
-	result = append(result, addPrefix("mas", c.ignoreFile.Global.Packages.Mas)...)
+	for _, pkg := range c.ignoreFile.Global.Packages.All() {
+		result = append(result, pkg.ID)
+	}
 
 	// Add machine-specific ignored packages
 	if machineIgnore, ok := c.ignoreFile.Machines[machine]; ok {
-		result = append(result, addPrefix("tap", machineIgnore.Packages.Tap)...)
-		result = append(result, addPrefix("brew", machineIgnore.Packages.Brew)...)
-		result = append(result, addPrefix("cask", machineIgnore.Packages.Cask)...)
-		result = append(result, addPrefix("vscode", machineIgnore.Packages.VSCode)...)
-		result = append(result, addPrefix("cursor", machineIgnore.Packages.Cursor)...)
-		result = append(result, addPrefix("antigravity", machineIgnore.Packages.Antigravity)...)
-		result = append(result, addPrefix("go", machineIgnore.Packages.Go)...)
-		result = append(result, addPrefix("mas", machineIgnore.Packages.Mas)...)
+		for _, pkg := range machineIgnore.Packages.All() {
+			result = append(result, pkg.ID)
+		}
 	}
 
 	return result
@@ -180,6 +365,8 @@ func (c *Config) GetMachineSpecificPackages() map[string][]string {
 		ids = append(ids, addPrefix("cursor", pkgs.Cursor)...)
 		ids = append(ids, addPrefix("antigravity", pkgs.Antigravity)...)
 		ids = append(ids, addPrefix("go", pkgs.Go)...)
+		ids = append(ids, addPrefix("npm", pkgs.Npm)...)
+		ids = append(ids, addPrefix("pipx", pkgs.Pipx)...)
 		ids = append(ids, addPrefix("mas", pkgs.Mas)...)
 		result[machine] = ids
 	}
@@ -187,6 +374,22 @@ func (c *Config) GetMachineSpecificPackages() map[string][]string {
 	return result
 }
 
+// AddMachineSpecific marks a package as specific to a machine (call Save to persist)
+// Package ID format: "type:name" (e.g., "brew:postgresql@16")
+func (c *Config) AddMachineSpecific(machine, pkgID string) error {
+	pkgType, pkgName, err := parsePackageID(pkgID)
+	if err != nil {
+		return err
+	}
+	if c.MachineSpecific == nil {
+		c.MachineSpecific = make(MachineSpecificConfig)
+	}
+	list := c.MachineSpecific[machine]
+	addPackageToList(&list, pkgType, pkgName)
+	c.MachineSpecific[machine] = list
+	return nil
+}
+
 // IsCategoryIgnored checks if an entire package category is ignored
 func (c *Config) IsCategoryIgnored(machine, pkgType string) bool {
 	if c.ignoreFile == nil {
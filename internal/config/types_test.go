@@ -68,6 +68,17 @@ func TestConfig_GetCurrentMachine(t *testing.T) {
 	})
 }
 
+func TestConfig_EffectiveCategories(t *testing.T) {
+	cfg := &Config{}
+	assert.Equal(t, DefaultCategories, cfg.EffectiveCategories())
+	assert.True(t, cfg.CategoryEnabled("mas"))
+
+	cfg.DefaultCategories = []string{"brew", "cask"}
+	assert.Equal(t, []string{"brew", "cask"}, cfg.EffectiveCategories())
+	assert.True(t, cfg.CategoryEnabled("cask"))
+	assert.False(t, cfg.CategoryEnabled("mas"))
+}
+
 func TestConflictResolution_Constants(t *testing.T) {
 	assert.Equal(t, ConflictResolution("ask"), ConflictAsk)
 	assert.Equal(t, ConflictResolution("skip"), ConflictSkip)
@@ -143,3 +154,17 @@ func TestHooksConfig(t *testing.T) {
 	assert.Equal(t, "echo pre-dump", hooks.PreDump)
 	assert.Equal(t, "echo post-dump", hooks.PostDump)
 }
+
+func TestConfig_AddMachineSpecific(t *testing.T) {
+	cfg := &Config{}
+
+	assert.NoError(t, cfg.AddMachineSpecific("mini", "brew:postgresql@16"))
+	assert.NoError(t, cfg.AddMachineSpecific("mini", "cask:orbstack"))
+	assert.NoError(t, cfg.AddMachineSpecific("mini", "brew:postgresql@16"))
+	assert.NoError(t, cfg.AddMachineSpecific("air", "brew:ollama"))
+	assert.Error(t, cfg.AddMachineSpecific("air", "ollama"))
+
+	specific := cfg.GetMachineSpecificPackages()
+	assert.Equal(t, []string{"brew:postgresql@16", "cask:orbstack"}, specific["mini"])
+	assert.Equal(t, []string{"brew:ollama"}, specific["air"])
+}
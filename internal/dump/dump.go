@@ -0,0 +1,96 @@
+package dump
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+// Failures holds the error of each installer that failed to list its
+// packages during a dump, by package type
+type Failures map[brewfile.PackageType]error
+
+// CollectExtra appends the packages of the non-Homebrew installers
+// (extensions, Go tools, npm and pipx globals, mas apps) for enabled types,
+// skipping any already present. Installers that fail to list are reported
+// in failures. Extension versions are kept only when dump.extension_versions
+// pins them.
+func CollectExtra(cfg *config.Config, allPackages brewfile.Packages) (brewfile.Packages, Failures) {
+	failures := make(Failures)
+	collect := func(t brewfile.PackageType, inst installer.Installer) {
+		if !cfg.CategoryEnabled(string(t)) || !inst.IsAvailable() {
+			return
+		}
+		pkgs, err := inst.List()
+		if err != nil {
+			debug.Log("CollectExtra: %s list failed: %v", t, err)
+			failures[t] = err
+			return
+		}
+		allPackages = allPackages.AddUnique(pkgs...)
+	}
+
+	collect(brewfile.TypeVSCode, installer.NewVSCodeInstaller())
+	collect(brewfile.TypeCursor, installer.NewCursorInstaller())
+	collect(brewfile.TypeAntigravity, installer.NewAntigravityInstaller())
+	collect(brewfile.TypeGo, installer.NewGoToolsInstaller())
+	collect(brewfile.TypeNpm, installer.NewNpmInstaller())
+	collect(brewfile.TypePipx, installer.NewPipxInstaller())
+	collect(brewfile.TypeMas, installer.NewMasInstaller())
+
+	return Versions(cfg, allPackages), failures
+}
+
+// Versions keeps the installed editor extension versions only when
+// dump.extension_versions pins them
+func Versions(cfg *config.Config, pkgs brewfile.Packages) brewfile.Packages {
+	if cfg.Dump.ExtensionVersions {
+		return pkgs
+	}
+	return pkgs.WithoutVersions()
+}
+
+// Failure is a category whose installer failed to list its packages
+type Failure struct {
+	Type     brewfile.PackageType
+	Err      error
+	Existing int  // Entries of the type in the existing Brewfile
+	Kept     bool // Whether those entries were kept
+}
+
+// KeepFailed keeps the existing Brewfile entries at path of each category
+// whose installer failed to list its packages, so a transient failure (mas
+// not signed in, an editor CLI crashing) doesn't drop them from the Brewfile.
+// With force the failed categories are written as collected, i.e. empty.
+// It returns the failed categories in type order.
+func KeepFailed(path string, pkgs brewfile.Packages, failures Failures, force bool) (brewfile.Packages, []Failure, error) {
+	if len(failures) == 0 {
+		return pkgs, nil, nil
+	}
+	existing := brewfile.Packages{}
+	if _, err := os.Stat(path); err == nil {
+		if existing, err = brewfile.Parse(path); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse existing Brewfile: %w", err)
+		}
+	}
+
+	var keep []brewfile.PackageType
+	var failed []Failure
+	for _, t := range brewfile.AllTypes() {
+		err, ok := failures[t]
+		if !ok {
+			continue
+		}
+		f := Failure{Type: t, Err: err, Existing: len(existing.Filter(t))}
+		if f.Existing > 0 && !force {
+			f.Kept = true
+			keep = append(keep, t)
+		}
+		failed = append(failed, f)
+	}
+	return pkgs.KeepTypes(existing, keep...), failed, nil
+}
@@ -0,0 +1,56 @@
+package dump
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestKeepFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(path, []byte(`brew "git"
+mas "Xcode", id: 497799835
+`), 0644))
+
+	collected := brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}
+	errMas := errors.New("not signed in")
+	errGo := errors.New("go not found")
+	failures := Failures{brewfile.TypeMas: errMas, brewfile.TypeGo: errGo}
+
+	pkgs, failed, err := KeepFailed(path, collected, failures, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git", "mas:Xcode"}, pkgs.IDs())
+	assert.Equal(t, []Failure{
+		{Type: brewfile.TypeGo, Err: errGo},
+		{Type: brewfile.TypeMas, Err: errMas, Existing: 1, Kept: true},
+	}, failed)
+
+	// With force the failed categories are written as collected
+	pkgs, failed, err = KeepFailed(path, collected, failures, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, pkgs.IDs())
+	assert.Equal(t, Failure{Type: brewfile.TypeMas, Err: errMas, Existing: 1}, failed[1])
+
+	// No failures leaves the packages alone without reading the Brewfile
+	pkgs, failed, err = KeepFailed(filepath.Join(t.TempDir(), "missing"), collected, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, collected, pkgs)
+	assert.Empty(t, failed)
+}
+
+func TestVersions(t *testing.T) {
+	pkgs := brewfile.Packages{brewfile.NewPackage(brewfile.TypeVSCode, "golang.go")}
+	pkgs[0].Version = "0.41.0"
+
+	assert.Empty(t, Versions(&config.Config{}, pkgs)[0].Version)
+	cfg := &config.Config{}
+	cfg.Dump.ExtensionVersions = true
+	assert.Equal(t, "0.41.0", Versions(cfg, pkgs)[0].Version)
+}
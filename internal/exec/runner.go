@@ -6,8 +6,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,6 +20,8 @@ const DefaultTimeout = 5 * time.Minute
 type Runner struct {
 	Timeout time.Duration
 	Verbose bool
+	// Trace receives a line per executed command; nil falls back to SetTrace's writer
+	Trace io.Writer
 }
 
 // NewRunner creates a new command runner
@@ -35,22 +39,66 @@ func (r *Runner) Run(name string, args ...string) (string, error) {
 	return r.RunContext(ctx, name, args...)
 }
 
+// RunOutput is Run for commands that print a usable result even when they
+// exit non-zero: it returns their stdout along with the error
+func (r *Runner) RunOutput(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+
+	return r.runOutput(command(ctx, "", nil, name, args))
+}
+
 // RunContext executes a command with the given context
 func (r *Runner) RunContext(ctx context.Context, name string, args ...string) (string, error) {
+	return r.run(command(ctx, "", nil, name, args))
+}
+
+// RunIn executes a command in dir with env (KEY=value entries) added to the
+// inherited environment, and returns its output. An empty dir runs in the
+// current directory.
+func (r *Runner) RunIn(dir string, env []string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+
+	return r.run(command(ctx, dir, env, name, args))
+}
+
+// command builds a command running in dir with env added to the inherited environment
+func command(ctx context.Context, dir string, env []string, name string, args []string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// run runs cmd and returns its stdout, with stderr in the error on failure
+func (r *Runner) run(cmd *exec.Cmd) (string, error) {
+	output, err := r.runOutput(cmd)
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
 
+// runOutput runs cmd and returns its stdout whether or not it failed, with
+// stderr in the error on failure
+func (r *Runner) runOutput(cmd *exec.Cmd) (string, error) {
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	err := cmd.Run()
+	r.trace(cmd, start, err)
 	if err != nil {
 		// Include stderr in error message for debugging
 		errMsg := stderr.String()
 		if errMsg != "" {
-			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(errMsg))
+			return stdout.String(), fmt.Errorf("%w: %s", err, strings.TrimSpace(errMsg))
 		}
-		return "", err
+		return stdout.String(), err
 	}
 
 	return stdout.String(), nil
@@ -110,8 +158,34 @@ func (r *Runner) RunWithOutput(name string, args []string, onOutput func(line st
 
 // RunWithOutputContext executes a command with context and streams output
 func (r *Runner) RunWithOutputContext(ctx context.Context, name string, args []string, onOutput func(line string)) error {
-	cmd := exec.CommandContext(ctx, name, args...)
+	return r.RunStreamingContext(ctx, onOutput, name, args...)
+}
+
+// RunStreaming executes a command, calling onLine for every line of stdout and stderr
+// as it is produced. On failure the error includes the last lines of stderr.
+func (r *Runner) RunStreaming(onLine func(line string), name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
 
+	return r.RunStreamingContext(ctx, onLine, name, args...)
+}
+
+// RunStreamingContext is RunStreaming with a context
+func (r *Runner) RunStreamingContext(ctx context.Context, onLine func(line string), name string, args ...string) error {
+	return r.stream(command(ctx, "", nil, name, args), onLine)
+}
+
+// RunStreamingIn is RunStreaming in dir with env (KEY=value entries) added to
+// the inherited environment
+func (r *Runner) RunStreamingIn(dir string, env []string, onLine func(line string), name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+
+	return r.stream(command(ctx, dir, env, name, args), onLine)
+}
+
+// stream runs cmd, passing each line of its stdout and stderr to onLine
+func (r *Runner) stream(cmd *exec.Cmd, onLine func(line string)) error {
 	// Create pipes for stdout and stderr
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -124,21 +198,65 @@ func (r *Runner) RunWithOutputContext(ctx context.Context, name string, args []s
 	}
 
 	// Start command
+	start := time.Now()
 	if err := cmd.Start(); err != nil {
+		r.trace(cmd, start, err)
 		return err
 	}
 
+	// Keep the tail of stderr for the error message
+	tail := &lineTail{max: StderrTailLines}
+	onStderr := func(line string) {
+		tail.add(line)
+		if onLine != nil {
+			onLine(line)
+		}
+	}
+
 	// Stream both stdout and stderr to the callback
 	done := make(chan error, 2)
-	go streamLines(stdout, onOutput, done)
-	go streamLines(stderr, onOutput, done)
+	go streamLines(stdout, onLine, done)
+	go streamLines(stderr, onStderr, done)
 
 	// Wait for streaming to complete
 	<-done
 	<-done
 
 	// Wait for command to finish
-	return cmd.Wait()
+	err = cmd.Wait()
+	r.trace(cmd, start, err)
+	if err != nil {
+		if lines := tail.String(); lines != "" {
+			return fmt.Errorf("%w: %s", err, lines)
+		}
+		return err
+	}
+	return nil
+}
+
+// StderrTailLines is how many trailing stderr lines streaming errors include
+const StderrTailLines = 20
+
+// lineTail keeps the last max lines written to it
+type lineTail struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+}
+
+func (t *lineTail) add(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+}
+
+func (t *lineTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.TrimSpace(strings.Join(t.lines, "\n"))
 }
 
 // streamLines reads lines from a reader and sends them to the callback
@@ -2,6 +2,10 @@ package exec
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -48,6 +52,12 @@ func TestRunner_Run(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "error")
 	})
+
+	t.Run("output of a failed command", func(t *testing.T) {
+		output, err := runner.RunOutput("sh", "-c", "echo result; echo problem >&2; exit 1")
+		assert.ErrorContains(t, err, "problem")
+		assert.Equal(t, "result\n", output)
+	})
 }
 
 func TestRunner_RunContext(t *testing.T) {
@@ -183,3 +193,83 @@ func TestRunner_RunWithWhitespace(t *testing.T) {
 		assert.Equal(t, "a\tb\nc", output)
 	})
 }
+
+func TestRunner_RunStreaming(t *testing.T) {
+	runner := NewRunner()
+
+	t.Run("multi-line output", func(t *testing.T) {
+		var mu sync.Mutex
+		var lines []string
+		err := runner.RunStreaming(func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines = append(lines, line)
+		}, "sh", "-c", "echo one; echo two; echo three >&2")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"one", "two", "three"}, lines)
+	})
+
+	t.Run("nil callback", func(t *testing.T) {
+		assert.NoError(t, runner.RunStreaming(nil, "echo", "ignored"))
+	})
+
+	t.Run("non-zero exit includes stderr tail", func(t *testing.T) {
+		err := runner.RunStreaming(nil, "sh", "-c", "echo progress; echo 'Error: No formulae found' >&2; exit 1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exit status 1")
+		assert.Contains(t, err.Error(), "Error: No formulae found")
+		assert.NotContains(t, err.Error(), "progress")
+	})
+
+	t.Run("stderr tail is bounded", func(t *testing.T) {
+		script := fmt.Sprintf("for i in $(seq 1 %d); do echo line$i >&2; done; exit 2", StderrTailLines+5)
+		err := runner.RunStreaming(nil, "sh", "-c", script)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "line5\n")
+		assert.Contains(t, err.Error(), "line6")
+		assert.Contains(t, err.Error(), fmt.Sprintf("line%d", StderrTailLines+5))
+	})
+}
+
+func TestRunner_RunIn(t *testing.T) {
+	runner := NewRunner()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	t.Setenv("BREWSYNC_INHERITED", "kept")
+
+	t.Run("dir and env", func(t *testing.T) {
+		output, err := runner.RunIn(dir, []string{"BREWSYNC_EXTRA=added"}, "sh", "-c", `pwd -P; echo "$BREWSYNC_EXTRA $BREWSYNC_INHERITED"`)
+		require.NoError(t, err)
+		assert.Equal(t, dir+"\nadded kept\n", output)
+	})
+
+	t.Run("env overrides inherited values", func(t *testing.T) {
+		output, err := runner.RunIn("", []string{"BREWSYNC_INHERITED=replaced"}, "sh", "-c", `echo "$BREWSYNC_INHERITED"`)
+		require.NoError(t, err)
+		assert.Equal(t, "replaced\n", output)
+	})
+
+	t.Run("empty dir uses the current directory", func(t *testing.T) {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		wd, err = filepath.EvalSymlinks(wd)
+		require.NoError(t, err)
+		output, err := runner.RunIn("", nil, "pwd", "-P")
+		require.NoError(t, err)
+		assert.Equal(t, wd+"\n", output)
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		_, err := runner.RunIn(filepath.Join(dir, "missing"), nil, "pwd")
+		assert.Error(t, err)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		var lines []string
+		err := runner.RunStreamingIn(dir, []string{"BREWSYNC_EXTRA=added"}, func(line string) {
+			lines = append(lines, line)
+		}, "sh", "-c", `pwd -P; echo "$BREWSYNC_EXTRA"`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{dir, "added"}, lines)
+	})
+}
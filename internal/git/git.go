@@ -0,0 +1,189 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// ErrDirty is returned when a pull is refused because of uncommitted changes
+var ErrDirty = errors.New("repository has uncommitted changes")
+
+// DirtyStrategy defines what to do with uncommitted changes before a pull
+type DirtyStrategy string
+
+const (
+	// DirtyStash stashes local changes, pulls, then restores them
+	DirtyStash DirtyStrategy = "stash"
+	// DirtyRefuse aborts the pull when local changes exist
+	DirtyRefuse DirtyStrategy = "refuse"
+)
+
+// ParseDirtyStrategy converts a string to a DirtyStrategy
+func ParseDirtyStrategy(s string) (DirtyStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "stash":
+		return DirtyStash, nil
+	case "refuse":
+		return DirtyRefuse, nil
+	default:
+		return "", fmt.Errorf("unknown pull strategy: %s (expected stash or refuse)", s)
+	}
+}
+
+// Repo wraps git operations on a working directory
+type Repo struct {
+	dir    string
+	runner *exec.Runner
+}
+
+// NewRepo creates a Repo for the given directory
+func NewRepo(dir string) *Repo {
+	return &Repo{
+		dir:    dir,
+		runner: exec.Default,
+	}
+}
+
+// Dir returns the working directory of the repo
+func (r *Repo) Dir() string {
+	return r.dir
+}
+
+func (r *Repo) run(args ...string) (string, error) {
+	return r.runner.RunIn(r.dir, nil, "git", args...)
+}
+
+// IsRepo checks if the directory is inside a git work tree
+func (r *Repo) IsRepo() bool {
+	_, err := r.run("rev-parse", "--git-dir")
+	return err == nil
+}
+
+// DirtyFiles returns the paths with uncommitted changes (untracked files excluded)
+func (r *Repo) DirtyFiles() ([]string, error) {
+	output, err := r.run("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		files = append(files, strings.TrimSpace(line[3:]))
+	}
+	return files, nil
+}
+
+// IsDirty checks if the repo has uncommitted changes to tracked files
+func (r *Repo) IsDirty() (bool, error) {
+	files, err := r.DirtyFiles()
+	if err != nil {
+		return false, err
+	}
+	return len(files) > 0, nil
+}
+
+// Stash stashes local changes with the given message
+func (r *Repo) Stash(message string) error {
+	if _, err := r.run("stash", "push", "-m", message); err != nil {
+		return fmt.Errorf("failed to stash changes: %w", err)
+	}
+	return nil
+}
+
+// StashPop restores the most recent stash
+func (r *Repo) StashPop() error {
+	if _, err := r.run("stash", "pop"); err != nil {
+		return fmt.Errorf("failed to restore stashed changes (run 'git stash pop' in %s): %w", r.dir, err)
+	}
+	return nil
+}
+
+// PullResult describes what happened during a safe pull
+type PullResult struct {
+	Stashed    bool     // Local changes were stashed and restored
+	DirtyFiles []string // Files that were dirty before the pull
+}
+
+// Pull runs 'git pull --ff-only', protecting uncommitted changes according to strategy.
+// With DirtyRefuse, ErrDirty is returned and nothing is pulled.
+// With DirtyStash, changes are stashed before the pull and restored afterwards.
+func (r *Repo) Pull(strategy DirtyStrategy) (*PullResult, error) {
+	if !r.IsRepo() {
+		return nil, fmt.Errorf("not a git repository: %s", r.dir)
+	}
+
+	files, err := r.DirtyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PullResult{DirtyFiles: files}
+
+	if len(files) > 0 {
+		switch strategy {
+		case DirtyRefuse:
+			return result, fmt.Errorf("%w in %s: %s (commit or stash them, or set sync.pull_strategy to 'stash')",
+				ErrDirty, r.dir, strings.Join(files, ", "))
+		case DirtyStash:
+			msg := fmt.Sprintf("brewsync: auto-stash before pull (%s)", time.Now().Format(time.RFC3339))
+			if err := r.Stash(msg); err != nil {
+				return result, err
+			}
+			result.Stashed = true
+		default:
+			return result, fmt.Errorf("unknown pull strategy: %s", strategy)
+		}
+	}
+
+	_, pullErr := r.run("pull", "--ff-only")
+
+	if result.Stashed {
+		if err := r.StashPop(); err != nil {
+			if pullErr != nil {
+				return result, fmt.Errorf("failed to pull: %w; %v", pullErr, err)
+			}
+			return result, err
+		}
+	}
+
+	if pullErr != nil {
+		return result, fmt.Errorf("failed to pull: %w", pullErr)
+	}
+
+	return result, nil
+}
+
+// CommitFile stages and commits the file at path (relative to the repo
+// directory) with the given message. It returns false, without committing,
+// when the file has no changes.
+func (r *Repo) CommitFile(path, message string) (bool, error) {
+	if _, err := r.run("add", "--", path); err != nil {
+		return false, fmt.Errorf("failed to git add: %w", err)
+	}
+	status, err := r.run("status", "--porcelain", "--", path)
+	if err != nil {
+		return false, fmt.Errorf("failed to check git status: %w", err)
+	}
+	if strings.TrimSpace(status) == "" {
+		return false, nil
+	}
+	if _, err := r.run("commit", "-m", message, "--", path); err != nil {
+		return false, fmt.Errorf("failed to commit: %w", err)
+	}
+	return true, nil
+}
+
+// Push runs 'git push' to the current branch's upstream
+func (r *Repo) Push() error {
+	if _, err := r.run("push"); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package git
+
+import (
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := osexec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+// setupRepos creates a bare remote and two clones: local (under test) and other (pushes upstream changes)
+func setupRepos(t *testing.T) (local, other string) {
+	t.Helper()
+	if _, err := osexec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	local = filepath.Join(root, "local")
+	other = filepath.Join(root, "other")
+
+	gitCmd(t, root, "init", "--bare", "-b", "main", remote)
+	gitCmd(t, root, "clone", remote, other)
+	gitCmd(t, other, "checkout", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.mini"), []byte("brew \"git\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.air"), []byte("brew \"git\"\n"), 0644))
+	gitCmd(t, other, "add", ".")
+	gitCmd(t, other, "commit", "-m", "initial")
+	gitCmd(t, other, "push", "-u", "origin", "main")
+	gitCmd(t, root, "clone", remote, local)
+	gitCmd(t, local, "config", "user.name", "test")
+	gitCmd(t, local, "config", "user.email", "test@example.com")
+
+	// Upstream change to another machine's Brewfile
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.air"), []byte("brew \"git\"\nbrew \"jq\"\n"), 0644))
+	gitCmd(t, other, "commit", "-am", "update air")
+	gitCmd(t, other, "push")
+
+	return local, other
+}
+
+func TestParseDirtyStrategy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected DirtyStrategy
+		wantErr  bool
+	}{
+		{"", DirtyStash, false},
+		{"stash", DirtyStash, false},
+		{"REFUSE", DirtyRefuse, false},
+		{"merge", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDirtyStrategy(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRepo_IsRepo(t *testing.T) {
+	local, _ := setupRepos(t)
+
+	assert.True(t, NewRepo(local).IsRepo())
+	assert.False(t, NewRepo(t.TempDir()).IsRepo())
+}
+
+func TestRepo_Pull_Clean(t *testing.T) {
+	local, _ := setupRepos(t)
+	repo := NewRepo(local)
+
+	result, err := repo.Pull(DirtyRefuse)
+	require.NoError(t, err)
+	assert.False(t, result.Stashed)
+
+	data, err := os.ReadFile(filepath.Join(local, "Brewfile.air"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "jq")
+}
+
+func TestRepo_Pull_DirtyRefuse(t *testing.T) {
+	local, _ := setupRepos(t)
+	repo := NewRepo(local)
+
+	localEdit := "brew \"git\"\nbrew \"wget\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(local, "Brewfile.mini"), []byte(localEdit), 0644))
+
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty)
+
+	result, err := repo.Pull(DirtyRefuse)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrDirty)
+	assert.Contains(t, err.Error(), "Brewfile.mini")
+	assert.Equal(t, []string{"Brewfile.mini"}, result.DirtyFiles)
+
+	// Nothing pulled, local edit untouched
+	air, _ := os.ReadFile(filepath.Join(local, "Brewfile.air"))
+	assert.NotContains(t, string(air), "jq")
+	mini, _ := os.ReadFile(filepath.Join(local, "Brewfile.mini"))
+	assert.Equal(t, localEdit, string(mini))
+}
+
+func TestRepo_Pull_DirtyStash(t *testing.T) {
+	local, _ := setupRepos(t)
+	repo := NewRepo(local)
+
+	localEdit := "brew \"git\"\nbrew \"wget\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(local, "Brewfile.mini"), []byte(localEdit), 0644))
+
+	result, err := repo.Pull(DirtyStash)
+	require.NoError(t, err)
+	assert.True(t, result.Stashed)
+
+	// Upstream change pulled, local edit restored
+	air, _ := os.ReadFile(filepath.Join(local, "Brewfile.air"))
+	assert.Contains(t, string(air), "jq")
+	mini, _ := os.ReadFile(filepath.Join(local, "Brewfile.mini"))
+	assert.Equal(t, localEdit, string(mini))
+
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty)
+}
+
+func TestRepo_Pull_NotRepo(t *testing.T) {
+	_, err := NewRepo(t.TempDir()).Pull(DirtyStash)
+	assert.Error(t, err)
+}
+
+func TestRepo_CommitFileAndPush(t *testing.T) {
+	_, other := setupRepos(t)
+	gitCmd(t, other, "config", "user.name", "test")
+	gitCmd(t, other, "config", "user.email", "test@example.com")
+	repo := NewRepo(other)
+
+	// Unchanged file: nothing to commit
+	committed, err := repo.CommitFile("Brewfile.mini", "update mini")
+	require.NoError(t, err)
+	assert.False(t, committed)
+
+	// Only the given file is committed
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.mini"), []byte("brew \"git\"\nbrew \"fd\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "Brewfile.air"), []byte("brew \"fd\"\n"), 0644))
+	committed, err = repo.CommitFile("Brewfile.mini", "update mini")
+	require.NoError(t, err)
+	assert.True(t, committed)
+
+	files, err := repo.DirtyFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Brewfile.air"}, files)
+
+	require.NoError(t, repo.Push())
+	log, err := repo.run("log", "-1", "--format=%s", "origin/main")
+	require.NoError(t, err)
+	assert.Equal(t, "update mini", strings.TrimSpace(log))
+}
@@ -26,6 +26,7 @@ const (
 
 // Entry represents a single history log entry
 type Entry struct {
+	ID        int // Line number in the history log, set by Read
 	Timestamp time.Time
 	Operation Operation
 	Machine   string
@@ -89,13 +90,47 @@ func LogImport(machine, source string, added []string) error {
 	return Log(OpImport, machine, details, summary)
 }
 
-// LogSync logs a sync operation
-func LogSync(machine, source string, added, removed int) error {
-	details := fmt.Sprintf("←%s;+%d,-%d", source, added, removed)
-	summary := "applied"
+// LogSync logs a sync operation with the IDs of the packages it installed
+// and removed, so 'history undo' can reverse it
+func LogSync(machine, source string, added, removed []string) error {
+	details := fmt.Sprintf("←%s;+%s;-%s", source, strings.Join(added, ","), strings.Join(removed, ","))
+	summary := fmt.Sprintf("+%d -%d", len(added), len(removed))
 	return Log(OpSync, machine, details, summary)
 }
 
+// SyncChanges is what a logged sync changed
+type SyncChanges struct {
+	Source  string
+	Added   []string // IDs of the packages installed
+	Removed []string // IDs of the packages removed
+}
+
+// SyncChanges returns the source and packages of a sync entry. ok is false
+// for other operations and for syncs logged before package IDs were recorded,
+// which only have counts.
+func (e Entry) SyncChanges() (changes SyncChanges, ok bool) {
+	if e.Operation != OpSync {
+		return SyncChanges{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(e.Details, "←"), ";")
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "+") || !strings.HasPrefix(parts[2], "-") {
+		return SyncChanges{}, false
+	}
+	return SyncChanges{
+		Source:  parts[0],
+		Added:   splitIDs(parts[1][1:]),
+		Removed: splitIDs(parts[2][1:]),
+	}, true
+}
+
+// splitIDs splits a comma-separated list of package IDs
+func splitIDs(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
 // LogInstall logs a single package install operation
 func LogInstall(machine, pkgID string, success bool) error {
 	summary := "installed"
@@ -132,7 +167,11 @@ func Read(limit int) ([]Entry, error) {
 
 	var entries []Entry
 	scanner := bufio.NewScanner(f)
+	// A sync logs every package ID on one line
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
 	for scanner.Scan() {
+		lineNo++
 		line := scanner.Text()
 		if line == "" {
 			continue
@@ -141,6 +180,7 @@ func Read(limit int) ([]Entry, error) {
 		if err != nil {
 			continue // Skip malformed entries
 		}
+		entry.ID = lineNo
 		entries = append(entries, entry)
 	}
 
@@ -161,6 +201,20 @@ func Read(limit int) ([]Entry, error) {
 	return entries, nil
 }
 
+// Find returns the entry with the given ID
+func Find(id int) (Entry, error) {
+	entries, err := Read(0)
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("no history entry #%d", id)
+}
+
 // formatEntry formats an entry for the log file
 // Format: timestamp|operation|machine|details|summary
 func formatEntry(e Entry) string {
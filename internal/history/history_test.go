@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
 )
 
 func TestFormatEntry(t *testing.T) {
@@ -135,3 +138,41 @@ func TestFormatAndParse_Roundtrip(t *testing.T) {
 	assert.Equal(t, original.Summary, parsed.Summary)
 	assert.WithinDuration(t, original.Timestamp, parsed.Timestamp, time.Second)
 }
+
+func TestLogSync_Undoable(t *testing.T) {
+	t.Setenv(config.HomeEnvVar, t.TempDir())
+
+	require.NoError(t, LogDump("mini", map[string]int{"brew": 2}, false))
+	require.NoError(t, LogSync("mini", "air", []string{"brew:jq", "cask:orbstack"}, []string{"brew:htop"}))
+	require.NoError(t, LogSync("mini", "studio", nil, nil))
+
+	entries, err := Read(0)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []int{3, 2, 1}, []int{entries[0].ID, entries[1].ID, entries[2].ID})
+
+	entry, err := Find(2)
+	require.NoError(t, err)
+	assert.Equal(t, "+2 -1", entry.Summary)
+	changes, ok := entry.SyncChanges()
+	require.True(t, ok)
+	assert.Equal(t, SyncChanges{Source: "air", Added: []string{"brew:jq", "cask:orbstack"}, Removed: []string{"brew:htop"}}, changes)
+
+	changes, ok = entries[0].SyncChanges()
+	require.True(t, ok)
+	assert.Equal(t, SyncChanges{Source: "studio"}, changes)
+
+	_, ok = entries[2].SyncChanges()
+	assert.False(t, ok, "not a sync")
+
+	_, err = Find(7)
+	assert.Error(t, err)
+}
+
+func TestSyncChanges_CountsOnly(t *testing.T) {
+	// Syncs logged before package IDs were recorded can't be undone
+	entry, err := parseEntry("2024-01-15T10:30:00Z|sync|mini|←air;+2,-1|applied")
+	require.NoError(t, err)
+	_, ok := entry.SyncChanges()
+	assert.False(t, ok)
+}
@@ -0,0 +1,99 @@
+// Package hooks resolves and runs the user-configured shell commands that
+// run before and after installs and dumps.
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// Hook names, matching the keys under hooks: in config.yaml
+const (
+	PreInstall  = "pre_install"
+	PostInstall = "post_install"
+	PreDump     = "pre_dump"
+	PostDump    = "post_dump"
+)
+
+// Environment variables hooks receive (and can use as $VAR placeholders)
+const (
+	EnvMachine  = "BREWSYNC_MACHINE"
+	EnvBrewfile = "BREWSYNC_BREWFILE"
+)
+
+// Hook is a configured hook command resolved for one run
+type Hook struct {
+	Name    string
+	Command string // Command with env placeholders expanded
+}
+
+// Env returns the hook environment for a machine and its Brewfile
+func Env(machine, brewfilePath string) map[string]string {
+	return map[string]string{
+		EnvMachine:  machine,
+		EnvBrewfile: brewfilePath,
+	}
+}
+
+// Command returns the configured command for a hook name ("" if unset)
+func Command(cfg config.HooksConfig, name string) string {
+	switch name {
+	case PreInstall:
+		return cfg.PreInstall
+	case PostInstall:
+		return cfg.PostInstall
+	case PreDump:
+		return cfg.PreDump
+	case PostDump:
+		return cfg.PostDump
+	}
+	return ""
+}
+
+// Resolve returns the configured hooks among names, in order, with $VAR and
+// ${VAR} placeholders expanded from env (falling back to the process
+// environment). Hooks without a command are skipped. Nothing is executed.
+func Resolve(cfg config.HooksConfig, env map[string]string, names ...string) []Hook {
+	var resolved []Hook
+	for _, name := range names {
+		command := Command(cfg, name)
+		if command == "" {
+			continue
+		}
+		resolved = append(resolved, Hook{Name: name, Command: expand(command, env)})
+	}
+	return resolved
+}
+
+// expand replaces $VAR / ${VAR} with values from env or the process environment
+func expand(command string, env map[string]string) string {
+	return os.Expand(command, func(key string) string {
+		if value, ok := env[key]; ok {
+			return value
+		}
+		return os.Getenv(key)
+	})
+}
+
+// Run executes a resolved hook with sh -c, with env set in its environment.
+// onLine receives each line of output. A non-zero exit is returned as an error.
+func Run(runner *exec.Runner, hook Hook, env map[string]string, onLine func(line string)) error {
+	if err := runner.RunStreamingIn("", envAssignments(env), onLine, "sh", "-c", hook.Command); err != nil {
+		return fmt.Errorf("%s hook failed: %w", hook.Name, err)
+	}
+	return nil
+}
+
+// envAssignments returns env as sorted KEY=value entries
+func envAssignments(env map[string]string) []string {
+	assignments := make([]string, 0, len(env))
+	for key, value := range env {
+		assignments = append(assignments, key+"="+value)
+	}
+	sort.Strings(assignments)
+	return assignments
+}
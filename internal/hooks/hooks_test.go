@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("BREWSYNC_TEST_DIR", "/tmp/hooks")
+	cfg := config.HooksConfig{
+		PreDump:     "echo dumping $BREWSYNC_MACHINE",
+		PostDump:    "cp ${BREWSYNC_BREWFILE} $BREWSYNC_TEST_DIR/",
+		PostInstall: "brew cleanup",
+	}
+	env := Env("mini", "/dotfiles/Brewfile.mini")
+
+	resolved := Resolve(cfg, env, PreDump, PostDump)
+	require.Len(t, resolved, 2)
+	assert.Equal(t, Hook{Name: PreDump, Command: "echo dumping mini"}, resolved[0])
+	assert.Equal(t, Hook{Name: PostDump, Command: "cp /dotfiles/Brewfile.mini /tmp/hooks/"}, resolved[1])
+
+	// Unconfigured hooks are skipped
+	resolved = Resolve(cfg, env, PreInstall, PostInstall)
+	require.Len(t, resolved, 1)
+	assert.Equal(t, PostInstall, resolved[0].Name)
+}
+
+func TestResolve_DoesNotRunCommands(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	cfg := config.HooksConfig{PreInstall: "touch " + marker}
+
+	resolved := Resolve(cfg, nil, PreInstall)
+	require.Len(t, resolved, 1)
+	assert.Equal(t, "touch "+marker, resolved[0].Command)
+
+	_, err := os.Stat(marker)
+	assert.True(t, os.IsNotExist(err), "resolving a hook must not execute it")
+}
+
+func TestRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	env := Env("mini", "/dotfiles/Brewfile.mini")
+	hook := Hook{Name: PostDump, Command: `echo "$BREWSYNC_MACHINE $BREWSYNC_BREWFILE" > ` + out + `; echo done`}
+
+	var lines []string
+	require.NoError(t, Run(exec.NewRunner(), hook, env, func(line string) { lines = append(lines, line) }))
+	assert.Equal(t, []string{"done"}, lines)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "mini /dotfiles/Brewfile.mini\n", string(data))
+}
+
+func TestRun_Failure(t *testing.T) {
+	hook := Hook{Name: PreInstall, Command: "echo not today >&2; exit 3"}
+
+	err := Run(exec.NewRunner(), hook, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre_install hook failed")
+	assert.Contains(t, err.Error(), "not today")
+}
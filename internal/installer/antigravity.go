@@ -1,8 +1,6 @@
 package installer
 
 import (
-	"strings"
-
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/exec"
 )
@@ -21,27 +19,15 @@ func NewAntigravityInstaller() *AntigravityInstaller {
 
 // List returns all installed Antigravity extensions
 func (a *AntigravityInstaller) List() (brewfile.Packages, error) {
-	lines, err := a.runner.RunLines("agy", "--list-extensions")
-	if err != nil {
-		return nil, err
-	}
-
-	var packages brewfile.Packages
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			packages = append(packages, brewfile.NewPackage(brewfile.TypeAntigravity, line))
-		}
-	}
-	return packages, nil
+	return listExtensions(a.runner, "agy", brewfile.TypeAntigravity)
 }
 
-// Install installs an Antigravity extension
+// Install installs an Antigravity extension, at its pinned version if it has one
 func (a *AntigravityInstaller) Install(pkg brewfile.Package) error {
 	if pkg.Type != brewfile.TypeAntigravity {
 		return nil
 	}
-	_, err := a.runner.Run("agy", "--install-extension", pkg.Name)
+	_, err := a.runner.Run("agy", "--install-extension", pkg.VersionedName())
 	return err
 }
 
@@ -0,0 +1,89 @@
+package installer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/debug"
+)
+
+// notFoundMarkers are the brew info errors for names Homebrew doesn't know
+var notFoundMarkers = []string{
+	"no available formula",
+	"no available cask",
+	"no formulae or casks found",
+	"no cask with this name",
+}
+
+// isNotFound reports whether a brew info error means the name is unknown
+func isNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range notFoundMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// infoEach runs brew info --json=v2 on all names of type t (brew or cask) at
+// once and, only if that fails, on each name on its own, so a formula or cask
+// Homebrew can't find doesn't hide the others. fn, if not nil, is called with
+// the output of each run that succeeded. It returns the names Homebrew can't
+// find.
+func (b *BrewInstaller) infoEach(t brewfile.PackageType, names []string, fn func(output string) error) ([]string, error) {
+	flag := "--formula"
+	if t == brewfile.TypeCask {
+		flag = "--cask"
+	}
+	if fn == nil {
+		fn = func(string) error { return nil }
+	}
+
+	args := append([]string{"info", "--json=v2", flag}, names...)
+	if output, err := b.runner.Run("brew", args...); err == nil {
+		return nil, fn(output)
+	}
+
+	var notFound []string
+	for _, name := range names {
+		output, err := b.runner.Run("brew", "info", "--json=v2", flag, name)
+		switch {
+		case err == nil:
+			if err := fn(output); err != nil {
+				return nil, err
+			}
+		case isNotFound(err):
+			debug.Log("infoEach: %s not found: %v", name, err)
+			notFound = append(notFound, name)
+		default:
+			return nil, fmt.Errorf("brew info %s failed: %w", name, err)
+		}
+	}
+	return notFound, nil
+}
+
+// Unavailable returns the formulae and casks in pkgs that Homebrew can't find,
+// usually because they were renamed or removed, so installing them would fail.
+// Other types are not checked.
+func (b *BrewInstaller) Unavailable(pkgs brewfile.Packages) (brewfile.Packages, error) {
+	var unavailable brewfile.Packages
+	for _, t := range []brewfile.PackageType{brewfile.TypeBrew, brewfile.TypeCask} {
+		checked := pkgs.Filter(t)
+		if len(checked) == 0 {
+			continue
+		}
+		notFound, err := b.infoEach(t, checked.Names(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range checked {
+			if slices.Contains(notFound, pkg.Name) {
+				unavailable = append(unavailable, pkg)
+			}
+		}
+	}
+	return unavailable, nil
+}
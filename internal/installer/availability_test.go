@@ -0,0 +1,42 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+func TestBrewInstaller_Unavailable(t *testing.T) {
+	// brew info fails for the whole batch when one name is unknown
+	stubBrew(t, `for arg; do
+  case "$arg" in
+    bogus-formula) echo "Error: No available formula with the name \"bogus-formula\"." >&2; exit 1 ;;
+    gone-app) echo "Error: Cask 'gone-app' is unavailable: No Cask with this name exists." >&2; exit 1 ;;
+  esac
+done
+echo '{"formulae": [], "casks": []}'`)
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "bogus-formula"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeCask, "gone-app"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "bogus-formula"),
+	}
+	unavailable, err := NewBrewInstaller().Unavailable(pkgs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:bogus-formula", "cask:gone-app"}, unavailable.IDs())
+
+	// All known: one call per type, nothing unavailable
+	unavailable, err = NewBrewInstaller().Unavailable(pkgs[:1])
+	require.NoError(t, err)
+	assert.Empty(t, unavailable)
+
+	// Other failures are errors, not missing packages
+	stubBrew(t, `echo "Error: Failed to download https://formulae.brew.sh/api/formula.jws.json" >&2; exit 1`)
+	_, err = NewBrewInstaller().Unavailable(pkgs[:1])
+	assert.Error(t, err)
+}
@@ -1,15 +1,50 @@
 package installer
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"strings"
+	"sync"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/exec"
 )
 
+// ErrBundleUnavailable is returned when 'brew bundle' is not installed
+var ErrBundleUnavailable = errors.New("brew bundle is not available")
+
+// ErrBrewNotFound is returned when Homebrew packages are wanted but brew is
+// not installed
+var ErrBrewNotFound = errors.New("Homebrew not found; install it from https://brew.sh")
+
+// ErrSudoRequired is returned when a cask needs administrator rights but sudo
+// could not ask for a password (brew runs without a terminal on stdin)
+var ErrSudoRequired = errors.New("requires sudo; run interactively")
+
+// sudoCasks are casks known to run a macOS installer package that asks for an
+// administrator password
+var sudoCasks = map[string]bool{
+	"docker":             true,
+	"karabiner-elements": true,
+	"microsoft-office":   true,
+	"microsoft-teams":    true,
+	"tailscale":          true,
+	"virtualbox":         true,
+	"wireshark":          true,
+	"zoom":               true,
+}
+
 // BrewInstaller handles Homebrew formulae and casks
 type BrewInstaller struct {
 	runner *exec.Runner
+
+	// NoQuarantine installs casks with --no-quarantine (no Gatekeeper prompts)
+	NoQuarantine bool
+
+	// caveats holds the caveats brew printed per installed package ID
+	caveatsMu sync.Mutex
+	caveats   map[string]string
 }
 
 // NewBrewInstaller creates a new Homebrew installer
@@ -39,7 +74,26 @@ func (b *BrewInstaller) ListTaps() (brewfile.Packages, error) {
 // ListFormulae returns all installed formulae (without descriptions)
 // Use 'brew bundle dump --describe' via DumpToFile for descriptions
 func (b *BrewInstaller) ListFormulae() (brewfile.Packages, error) {
-	lines, err := b.runner.RunLines("brew", "list", "--formula", "-1")
+	// --full-name keeps tap formulae qualified (user/tap/formula), matching Brewfiles
+	lines, err := b.runner.RunLines("brew", "list", "--formula", "--full-name", "-1")
+	if err != nil {
+		return nil, err
+	}
+
+	var packages brewfile.Packages
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			packages = append(packages, brewfile.NewPackage(brewfile.TypeBrew, line))
+		}
+	}
+	return packages, nil
+}
+
+// ListLeaves returns the installed formulae no other installed formula depends
+// on ('brew leaves'), leaving out those installed only as dependencies
+func (b *BrewInstaller) ListLeaves() (brewfile.Packages, error) {
+	lines, err := b.runner.RunLines("brew", "leaves")
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +111,7 @@ func (b *BrewInstaller) ListFormulae() (brewfile.Packages, error) {
 // ListCasks returns all installed casks (without descriptions)
 // Use 'brew bundle dump --describe' via DumpToFile for descriptions
 func (b *BrewInstaller) ListCasks() (brewfile.Packages, error) {
-	lines, err := b.runner.RunLines("brew", "list", "--cask", "-1")
+	lines, err := b.runner.RunLines("brew", "list", "--cask", "--full-name", "-1")
 	if err != nil {
 		return nil, err
 	}
@@ -107,29 +161,76 @@ func (b *BrewInstaller) Install(pkg brewfile.Package) error {
 	return b.InstallWithProgress(pkg, nil)
 }
 
-// InstallWithProgress installs a package and streams output to a callback
-func (b *BrewInstaller) InstallWithProgress(pkg brewfile.Package, onOutput func(line string)) error {
-	var args []string
-
+// InstallArgs returns the brew arguments used to install a package
+// (nil for types brew doesn't handle). Formulae and casks are always passed
+// with --formula / --cask, since a formula and a cask may share a name.
+func (b *BrewInstaller) InstallArgs(pkg brewfile.Package) []string {
 	switch pkg.Type {
 	case brewfile.TypeTap:
-		args = []string{"tap", pkg.Name}
+		if pkg.URL != "" {
+			// --custom-remote also repoints a tap already cloned from elsewhere
+			return []string{"tap", "--custom-remote", pkg.Name, pkg.URL}
+		}
+		return []string{"tap", pkg.Name}
 	case brewfile.TypeBrew:
-		args = []string{"install", pkg.Name}
+		return []string{"install", "--formula", pkg.QualifiedName()}
 	case brewfile.TypeCask:
-		args = []string{"install", "--cask", pkg.Name}
+		if b.NoQuarantine {
+			return []string{"install", "--cask", "--no-quarantine", pkg.QualifiedName()}
+		}
+		return []string{"install", "--cask", pkg.QualifiedName()}
 	default:
 		return nil
 	}
+}
 
-	// If no callback provided, use the regular Run method
-	if onOutput == nil {
-		_, err := b.runner.Run("brew", args...)
-		return err
+// InstallWithProgress installs a package and streams output to a callback
+func (b *BrewInstaller) InstallWithProgress(pkg brewfile.Package, onOutput func(line string)) error {
+	args := b.InstallArgs(pkg)
+	if args == nil {
+		return nil
+	}
+
+	// Stream output so failures carry brew's own error lines
+	var caveats caveatCollector
+	err := b.runner.RunStreaming(func(line string) {
+		caveats.add(line)
+		if onOutput != nil {
+			onOutput(line)
+		}
+	}, "brew", args...)
+	if err != nil && pkg.Type == brewfile.TypeCask && isSudoRequired(err) {
+		return fmt.Errorf("%w: %v", ErrSudoRequired, err)
+	}
+	if err == nil {
+		b.recordCaveats(pkg, caveats.String())
 	}
+	return err
+}
 
-	// Use streaming method with callback
-	return b.runner.RunWithOutput("brew", args, onOutput)
+// recordCaveats remembers the caveats brew printed for an installed package
+func (b *BrewInstaller) recordCaveats(pkg brewfile.Package, text string) {
+	if text == "" {
+		return
+	}
+	b.caveatsMu.Lock()
+	defer b.caveatsMu.Unlock()
+	if b.caveats == nil {
+		b.caveats = make(map[string]string)
+	}
+	b.caveats[pkg.ID()] = text
+}
+
+// Caveats returns the caveats brew printed for packages installed so far,
+// keyed by package ID
+func (b *BrewInstaller) Caveats() map[string]string {
+	b.caveatsMu.Lock()
+	defer b.caveatsMu.Unlock()
+	result := make(map[string]string, len(b.caveats))
+	for id, text := range b.caveats {
+		result[id] = text
+	}
+	return result
 }
 
 // Uninstall removes a package
@@ -139,25 +240,146 @@ func (b *BrewInstaller) Uninstall(pkg brewfile.Package) error {
 		_, err := b.runner.Run("brew", "untap", pkg.Name)
 		return err
 	case brewfile.TypeBrew:
-		_, err := b.runner.Run("brew", "uninstall", pkg.Name)
+		_, err := b.runner.Run("brew", "uninstall", "--formula", pkg.QualifiedName())
 		return err
 	case brewfile.TypeCask:
-		_, err := b.runner.Run("brew", "uninstall", "--cask", pkg.Name)
+		_, err := b.runner.Run("brew", "uninstall", "--cask", pkg.QualifiedName())
+		if err != nil && isSudoRequired(err) {
+			return fmt.Errorf("%w: %v", ErrSudoRequired, err)
+		}
 		return err
 	default:
 		return nil
 	}
 }
 
+// Pin runs 'brew pin' so the formula is skipped by 'brew upgrade'.
+// Only formulae can be pinned in Homebrew; other types are a no-op.
+func (b *BrewInstaller) Pin(pkg brewfile.Package) error {
+	if pkg.Type != brewfile.TypeBrew {
+		return nil
+	}
+	_, err := b.runner.Run("brew", "pin", pkg.Name)
+	return err
+}
+
+// Unpin runs 'brew unpin' for a formula (other types are a no-op)
+func (b *BrewInstaller) Unpin(pkg brewfile.Package) error {
+	if pkg.Type != brewfile.TypeBrew {
+		return nil
+	}
+	_, err := b.runner.Run("brew", "unpin", pkg.Name)
+	return err
+}
+
 // IsAvailable checks if brew is available
 func (b *BrewInstaller) IsAvailable() bool {
 	return b.runner.Exists("brew")
 }
 
+// CheckAvailable returns ErrBrewNotFound when brew is missing and types
+// include taps, formulae or casks. Collecting would otherwise quietly find
+// no Homebrew packages at all.
+func (b *BrewInstaller) CheckAvailable(types ...brewfile.PackageType) error {
+	for _, t := range types {
+		if t == brewfile.TypeTap || t == brewfile.TypeBrew || t == brewfile.TypeCask {
+			if !b.IsAvailable() {
+				return ErrBrewNotFound
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
 // DumpToFile runs brew bundle dump to a file with descriptions
 // This uses 'brew bundle dump --describe' which automatically includes
 // package descriptions as comments in the output Brewfile
 func (b *BrewInstaller) DumpToFile(path string) error {
-	_, err := b.runner.Run("brew", "bundle", "dump", "--force", "--describe", "--file="+path)
+	return b.DumpToFileWithProgress(path, nil)
+}
+
+// DumpToFileWithProgress is DumpToFile, streaming brew's output to onOutput
+// as it runs. brew bundle can take a while on large installs; the lines let
+// callers show that it is still making progress.
+func (b *BrewInstaller) DumpToFileWithProgress(path string, onOutput func(line string)) error {
+	err := b.runner.RunStreaming(onOutput, "brew", "bundle", "dump", "--force", "--describe", "--file="+path)
+	if err != nil && isBundleMissing(err) {
+		return fmt.Errorf("%w: %v", ErrBundleUnavailable, err)
+	}
 	return err
 }
+
+// CollectPackages returns all installed taps, formulae, and casks.
+// With useBundle, 'brew bundle dump --describe' is written to tmpFile to capture
+// descriptions. If brew bundle is unavailable, it falls back to 'brew list'
+// and reports fellBack=true so callers can warn the user.
+func (b *BrewInstaller) CollectPackages(useBundle bool, tmpFile string) (pkgs brewfile.Packages, fellBack bool, err error) {
+	return b.CollectPackagesWithProgress(useBundle, tmpFile, nil)
+}
+
+// CollectPackagesWithProgress is CollectPackages, streaming brew bundle
+// dump's output to onOutput (which may be nil)
+func (b *BrewInstaller) CollectPackagesWithProgress(useBundle bool, tmpFile string, onOutput func(line string)) (pkgs brewfile.Packages, fellBack bool, err error) {
+	if !b.IsAvailable() {
+		return nil, false, nil
+	}
+
+	if !useBundle {
+		return b.listLenient(), false, nil
+	}
+
+	err = b.DumpToFileWithProgress(tmpFile, onOutput)
+	defer os.Remove(tmpFile)
+
+	if errors.Is(err, ErrBundleUnavailable) {
+		pkgs, listErr := b.ListAll()
+		if listErr != nil {
+			return nil, true, fmt.Errorf("%w and manual collection failed: %v", ErrBundleUnavailable, listErr)
+		}
+		return pkgs, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("brew bundle dump failed: %w", err)
+	}
+
+	pkgs, err = brewfile.Parse(tmpFile)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse brew bundle output: %w", err)
+	}
+	return pkgs, false, nil
+}
+
+// listLenient collects taps, formulae, and casks, skipping any list that fails
+func (b *BrewInstaller) listLenient() brewfile.Packages {
+	var all brewfile.Packages
+	if taps, err := b.ListTaps(); err == nil {
+		all = append(all, taps...)
+	}
+	if formulae, err := b.ListFormulae(); err == nil {
+		all = append(all, formulae...)
+	}
+	if casks, err := b.ListCasks(); err == nil {
+		all = append(all, casks...)
+	}
+	return all
+}
+
+// NeedsSudo reports whether a package is a cask known to ask for an administrator password
+func NeedsSudo(pkg brewfile.Package) bool {
+	return pkg.Type == brewfile.TypeCask && sudoCasks[strings.ToLower(pkg.Name)]
+}
+
+// isSudoRequired checks if an error comes from sudo being unable to prompt for a password
+func isSudoRequired(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "sudo: a terminal is required") ||
+		strings.Contains(msg, "sudo: no tty present") ||
+		strings.Contains(msg, "sudo: a password is required")
+}
+
+// isBundleMissing checks if an error comes from brew not knowing the bundle command
+func isBundleMissing(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown command") && strings.Contains(msg, "bundle")
+}
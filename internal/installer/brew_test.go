@@ -1,10 +1,14 @@
 package installer
 
 import (
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewBrewInstaller(t *testing.T) {
@@ -93,6 +97,18 @@ func TestBrewInstaller_Install_Uninstall(t *testing.T) {
 	t.Skip("Skipping install/uninstall tests to avoid system modification")
 }
 
+func TestBrewInstaller_InstallArgs_Tap(t *testing.T) {
+	b := NewBrewInstaller()
+
+	assert.Equal(t, []string{"tap", "homebrew/bundle"},
+		b.InstallArgs(brewfile.NewPackage(brewfile.TypeTap, "homebrew/bundle")))
+
+	pkg := brewfile.NewPackage(brewfile.TypeTap, "company/private")
+	pkg.URL = "git@github.com:company/homebrew-private.git"
+	assert.Equal(t, []string{"tap", "--custom-remote", "company/private", "git@github.com:company/homebrew-private.git"},
+		b.InstallArgs(pkg))
+}
+
 func TestBrewInstaller_DumpToFile(t *testing.T) {
 	inst := NewBrewInstaller()
 	if !inst.IsAvailable() {
@@ -108,3 +124,241 @@ func TestBrewInstaller_DumpToFile(t *testing.T) {
 		t.Logf("brew bundle dump failed (may not be installed): %v", err)
 	}
 }
+
+// stubBrew puts a fake brew script first on PATH
+func stubBrew(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "brew")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+const brewNoBundleScript = `case "$1" in
+  bundle) echo "Error: Unknown command: bundle" >&2; exit 1 ;;
+  tap) echo "homebrew/core" ;;
+  list)
+    if [ "$2" = "--cask" ]; then echo "firefox"; else echo "git"; echo "jq"; fi ;;
+esac
+`
+
+func TestBrewInstaller_DumpToFile_BundleMissing(t *testing.T) {
+	stubBrew(t, brewNoBundleScript)
+
+	err := NewBrewInstaller().DumpToFile(filepath.Join(t.TempDir(), "Brewfile"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBundleUnavailable)
+}
+
+func TestBrewInstaller_CollectPackages_FallsBackWithoutBundle(t *testing.T) {
+	stubBrew(t, brewNoBundleScript)
+
+	pkgs, fellBack, err := NewBrewInstaller().CollectPackages(true, filepath.Join(t.TempDir(), "Brewfile.tmp"))
+	require.NoError(t, err)
+	assert.True(t, fellBack)
+	assert.ElementsMatch(t, []string{"tap:homebrew/core", "brew:git", "brew:jq", "cask:firefox"}, packageIDs(pkgs))
+}
+
+func TestBrewInstaller_CollectPackages_FallbackFails(t *testing.T) {
+	stubBrew(t, `case "$1" in
+  bundle) echo "Error: Unknown command: bundle" >&2; exit 1 ;;
+  *) echo "Error: broken" >&2; exit 1 ;;
+esac
+`)
+
+	pkgs, fellBack, err := NewBrewInstaller().CollectPackages(true, filepath.Join(t.TempDir(), "Brewfile.tmp"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBundleUnavailable)
+	assert.True(t, fellBack)
+	assert.Nil(t, pkgs)
+}
+
+func TestBrewInstaller_CollectPackages_Bundle(t *testing.T) {
+	stubBrew(t, `case "$1" in
+  bundle)
+    for arg in "$@"; do
+      case "$arg" in --file=*) file="${arg#--file=}" ;; esac
+    done
+    printf 'tap "homebrew/core"\n# Distributed revision control system\nbrew "git"\ncask "firefox"\n' > "$file" ;;
+  *) echo "unexpected" >&2; exit 1 ;;
+esac
+`)
+
+	tmpFile := filepath.Join(t.TempDir(), "Brewfile.tmp")
+	pkgs, fellBack, err := NewBrewInstaller().CollectPackages(true, tmpFile)
+	require.NoError(t, err)
+	assert.False(t, fellBack)
+	assert.ElementsMatch(t, []string{"tap:homebrew/core", "brew:git", "cask:firefox"}, packageIDs(pkgs))
+
+	// Temp file is cleaned up
+	_, err = os.Stat(tmpFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestBrewInstaller_CollectPackagesWithProgress(t *testing.T) {
+	stubBrew(t, `for arg in "$@"; do
+  case "$arg" in --file=*) file="${arg#--file=}" ;; esac
+done
+echo "==> Fetching taps"
+echo "==> Describing formulae" >&2
+printf 'brew "git"\n' > "$file"
+`)
+
+	var mu sync.Mutex
+	var lines []string
+	pkgs, _, err := NewBrewInstaller().CollectPackagesWithProgress(true, filepath.Join(t.TempDir(), "Brewfile.tmp"), func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, packageIDs(pkgs))
+	assert.ElementsMatch(t, []string{"==> Fetching taps", "==> Describing formulae"}, lines)
+}
+
+func TestBrewInstaller_CollectPackages_BundleError(t *testing.T) {
+	stubBrew(t, `echo "Error: permission denied" >&2; exit 1`)
+
+	_, fellBack, err := NewBrewInstaller().CollectPackages(true, filepath.Join(t.TempDir(), "Brewfile.tmp"))
+	require.Error(t, err)
+	assert.False(t, fellBack)
+	assert.NotErrorIs(t, err, ErrBundleUnavailable)
+}
+
+func packageIDs(pkgs brewfile.Packages) []string {
+	var ids []string
+	for _, p := range pkgs {
+		ids = append(ids, p.ID())
+	}
+	return ids
+}
+
+func TestBrewInstaller_PinUnpin(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "calls")
+	stubBrew(t, `echo "$@" >> `+log)
+
+	inst := NewBrewInstaller()
+	require.NoError(t, inst.Pin(brewfile.NewPackage(brewfile.TypeBrew, "node@18")))
+	require.NoError(t, inst.Pin(brewfile.NewPackage(brewfile.TypeCask, "docker"))) // casks can't be brew-pinned
+	require.NoError(t, inst.Unpin(brewfile.NewPackage(brewfile.TypeBrew, "node@18")))
+
+	calls, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "pin node@18\nunpin node@18\n", string(calls))
+}
+
+func TestBrewInstaller_CaskNoQuarantine(t *testing.T) {
+	argvLog := filepath.Join(t.TempDir(), "argv")
+	stubBrew(t, `echo "$@" >> "`+argvLog+`"`)
+
+	firefox := brewfile.NewPackage(brewfile.TypeCask, "firefox")
+	jq := brewfile.NewPackage(brewfile.TypeBrew, "jq")
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(argvLog, nil, 0644))
+		mgr := NewManager()
+		require.NoError(t, mgr.Install(firefox))
+
+		data, err := os.ReadFile(argvLog)
+		require.NoError(t, err)
+		assert.Equal(t, "install --cask firefox\n", string(data))
+		assert.Equal(t, "brew install --cask firefox", mgr.InstallCommand(firefox))
+	})
+
+	t.Run("enabled applies to casks only", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(argvLog, nil, 0644))
+		mgr := NewManager()
+		mgr.SetCaskNoQuarantine(true)
+		require.NoError(t, mgr.Install(firefox))
+		require.NoError(t, mgr.Install(jq))
+
+		data, err := os.ReadFile(argvLog)
+		require.NoError(t, err)
+		assert.Equal(t, "install --cask --no-quarantine firefox\ninstall --formula jq\n", string(data))
+		assert.Equal(t, "brew install --cask --no-quarantine firefox", mgr.InstallCommand(firefox))
+		assert.Equal(t, "brew install --formula jq", mgr.InstallCommand(jq))
+		assert.Empty(t, mgr.InstallCommand(brewfile.NewPackage(brewfile.TypeVSCode, "golang.go")))
+	})
+}
+
+func TestBrewInstaller_TapQualifiedNames(t *testing.T) {
+	argvLog := filepath.Join(t.TempDir(), "argv")
+	stubBrew(t, `echo "$@" >> "`+argvLog+`"
+[ "$1" = "list" ] && [ "$2" = "--formula" ] && echo "user/tap/formula"
+exit 0
+`)
+
+	inst := NewBrewInstaller()
+	require.NoError(t, inst.Install(brewfile.NewPackage(brewfile.TypeBrew, "user/tap/formula")))
+	require.NoError(t, inst.Install(brewfile.Package{Type: brewfile.TypeCask, Name: "app", Tap: "user/tap"}))
+
+	formulae, err := inst.ListFormulae()
+	require.NoError(t, err)
+	require.Len(t, formulae, 1)
+	assert.Equal(t, "user/tap/formula", formulae[0].Name)
+	assert.Equal(t, "user/tap", formulae[0].Tap)
+
+	data, err := os.ReadFile(argvLog)
+	require.NoError(t, err)
+	assert.Equal(t, "install --formula user/tap/formula\ninstall --cask user/tap/app\nlist --formula --full-name -1\n", string(data))
+}
+
+func TestBrewInstaller_SudoRequired(t *testing.T) {
+	stubBrew(t, `case "$3" in
+  zoom) echo "==> Running installer for zoom; your password may be necessary." >&2
+        echo "sudo: a terminal is required to read the password; either use the -S option to read from standard input or configure an askpass helper" >&2
+        exit 1 ;;
+  broken) echo "Error: Download failed" >&2; exit 1 ;;
+esac
+`)
+	b := NewBrewInstaller()
+
+	err := b.Install(brewfile.NewPackage(brewfile.TypeCask, "zoom"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSudoRequired)
+	assert.Contains(t, err.Error(), "requires sudo; run interactively")
+
+	err = b.Install(brewfile.NewPackage(brewfile.TypeCask, "broken"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrSudoRequired)
+
+	err = b.Uninstall(brewfile.NewPackage(brewfile.TypeCask, "zoom"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSudoRequired)
+}
+
+func TestNeedsSudo(t *testing.T) {
+	assert.True(t, NeedsSudo(brewfile.NewPackage(brewfile.TypeCask, "zoom")))
+	assert.False(t, NeedsSudo(brewfile.NewPackage(brewfile.TypeCask, "firefox")))
+	assert.False(t, NeedsSudo(brewfile.NewPackage(brewfile.TypeBrew, "zoom")))
+}
+
+func TestBrewInstaller_SameNameFormulaAndCask(t *testing.T) {
+	argvLog := filepath.Join(t.TempDir(), "argv")
+	stubBrew(t, `echo "$@" >> "`+argvLog+`"`)
+
+	formula := brewfile.NewPackage(brewfile.TypeBrew, "docker")
+	cask := brewfile.NewPackage(brewfile.TypeCask, "docker")
+	b := NewBrewInstaller()
+	require.NoError(t, b.Install(formula))
+	require.NoError(t, b.Install(cask))
+	require.NoError(t, b.Uninstall(formula))
+	require.NoError(t, b.Uninstall(cask))
+
+	data, err := os.ReadFile(argvLog)
+	require.NoError(t, err)
+	assert.Equal(t, "install --formula docker\ninstall --cask docker\nuninstall --formula docker\nuninstall --cask docker\n", string(data))
+	assert.Equal(t, "brew install --formula docker", NewManager().InstallCommand(formula))
+}
+
+func TestBrewInstaller_CheckAvailable(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // No brew
+	b := NewBrewInstaller()
+
+	assert.ErrorIs(t, b.CheckAvailable(brewfile.AllTypes()...), ErrBrewNotFound)
+	assert.ErrorIs(t, b.CheckAvailable(brewfile.TypeVSCode, brewfile.TypeCask), ErrBrewNotFound)
+	assert.NoError(t, b.CheckAvailable(brewfile.TypeVSCode, brewfile.TypeGo), "only Homebrew types need brew")
+
+	stubBrew(t, "exit 0")
+	assert.NoError(t, b.CheckAvailable(brewfile.AllTypes()...))
+}
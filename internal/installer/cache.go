@@ -0,0 +1,56 @@
+package installer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/debug"
+)
+
+// cacheEntry is an entry of a lookup cache, such as the description and
+// deprecation caches
+type cacheEntry interface {
+	fetchedAt() time.Time
+}
+
+// loadCache reads a JSON lookup cache keyed by package ID, returning an empty
+// cache when it is missing or unreadable. An empty path disables the cache.
+func loadCache[E cacheEntry](path string) map[string]E {
+	cache := make(map[string]E)
+	if path == "" {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		debug.Log("loadCache: ignoring %s: %v", path, err)
+		return make(map[string]E)
+	}
+	return cache
+}
+
+// saveCache writes a JSON lookup cache, dropping entries older than ttl
+func saveCache[E cacheEntry](path string, cache map[string]E, ttl time.Duration) {
+	if path == "" {
+		return
+	}
+	for id, entry := range cache {
+		if time.Since(entry.fetchedAt()) >= ttl {
+			delete(cache, id)
+		}
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		debug.Log("saveCache: %v", err)
+	}
+}
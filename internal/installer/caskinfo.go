@@ -0,0 +1,63 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// caskInfo is the part of 'brew info --cask --json=v2' brewsync reads
+type caskInfo struct {
+	Token     string `json:"token"`
+	FullToken string `json:"full_token"`
+	DependsOn struct {
+		Arch []struct {
+			Type string `json:"type"` // "arm" or "intel"
+			Bits int    `json:"bits"`
+		} `json:"arch"`
+	} `json:"depends_on"`
+}
+
+// ParseCaskArches reads 'brew info --cask --json=v2' output and returns the
+// architectures ("arm64", "amd64", as in runtime.GOARCH) each cask is limited
+// to, keyed by full token. Casks without an arch requirement are omitted.
+func ParseCaskArches(data []byte) (map[string][]string, error) {
+	var info struct {
+		Casks []caskInfo `json:"casks"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info output: %w", err)
+	}
+
+	arches := make(map[string][]string)
+	for _, cask := range info.Casks {
+		token := cask.FullToken
+		if token == "" {
+			token = cask.Token
+		}
+		for _, arch := range cask.DependsOn.Arch {
+			switch arch.Type {
+			case "arm":
+				arches[token] = append(arches[token], "arm64")
+			case "intel":
+				arches[token] = append(arches[token], "amd64")
+			}
+		}
+	}
+	return arches, nil
+}
+
+// CaskArches looks up the architectures casks are limited to with
+// 'brew info --cask --json=v2' (see ParseCaskArches). brew may download its
+// cask index for this, so callers should only use it when network access is ok.
+func (b *BrewInstaller) CaskArches(casks []string) (map[string][]string, error) {
+	if len(casks) == 0 {
+		return map[string][]string{}, nil
+	}
+
+	args := append([]string{"info", "--cask", "--json=v2"}, casks...)
+	output, err := b.runner.Run("brew", args...)
+	if err != nil {
+		return nil, fmt.Errorf("brew info failed: %w", err)
+	}
+	return ParseCaskArches([]byte(output))
+}
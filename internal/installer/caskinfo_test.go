@@ -0,0 +1,72 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleCaskInfo is trimmed 'brew info --cask --json=v2' output
+const sampleCaskInfo = `{
+  "formulae": [],
+  "casks": [
+    {
+      "token": "firefox",
+      "full_token": "firefox",
+      "depends_on": {"macos": {">=": ["10.15"]}}
+    },
+    {
+      "token": "intel-only",
+      "full_token": "intel-only",
+      "depends_on": {"arch": [{"type": "intel", "bits": 64}]}
+    },
+    {
+      "token": "silicon-app",
+      "full_token": "user/tap/silicon-app",
+      "depends_on": {"arch": [{"type": "arm", "bits": 64}]}
+    },
+    {
+      "token": "universal",
+      "full_token": "universal",
+      "depends_on": {"arch": [{"type": "arm", "bits": 64}, {"type": "intel", "bits": 64}]}
+    }
+  ]
+}`
+
+func TestParseCaskArches(t *testing.T) {
+	arches, err := ParseCaskArches([]byte(sampleCaskInfo))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"intel-only":           {"amd64"},
+		"user/tap/silicon-app": {"arm64"},
+		"universal":            {"arm64", "amd64"},
+	}, arches)
+
+	_, err = ParseCaskArches([]byte("Error: not json"))
+	assert.Error(t, err)
+}
+
+func TestBrewInstaller_CaskArches(t *testing.T) {
+	argvLog := filepath.Join(t.TempDir(), "argv")
+	stubBrew(t, `echo "$@" > "`+argvLog+`"
+cat <<'JSON'
+`+sampleCaskInfo+`
+JSON
+`)
+
+	arches, err := NewBrewInstaller().CaskArches([]string{"firefox", "intel-only"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amd64"}, arches["intel-only"])
+	data, err := os.ReadFile(argvLog)
+	require.NoError(t, err)
+	assert.Equal(t, "info --cask --json=v2 firefox intel-only\n", string(data))
+
+	// Nothing to look up runs nothing
+	arches, err = NewBrewInstaller().CaskArches(nil)
+	require.NoError(t, err)
+	assert.Empty(t, arches)
+}
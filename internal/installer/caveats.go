@@ -0,0 +1,74 @@
+package installer
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// caveatsHeader starts the section brew prints with setup steps after an install
+const caveatsHeader = "==> Caveats"
+
+// caveatCollector picks the "==> Caveats" sections out of brew install output
+// as it streams by. brew repeats a formula's caveats at the end of a run, so
+// identical sections are kept once. Lines may arrive from stdout and stderr
+// concurrently.
+type caveatCollector struct {
+	mu      sync.Mutex
+	inside  bool
+	current []string
+	blocks  []string
+}
+
+// add consumes one line of brew output
+func (c *caveatCollector) add(line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "==> ") {
+		if trimmed == caveatsHeader {
+			c.finish()
+			c.inside = true
+			return
+		}
+		// brew heads each package's caveats with "==> name" in its end-of-run recap
+		name := strings.TrimPrefix(trimmed, "==> ")
+		inside := c.inside && name != "Summary" && !strings.Contains(name, " ")
+		c.finish()
+		c.inside = inside
+		return
+	}
+	if c.inside {
+		c.current = append(c.current, strings.TrimRight(line, " \t"))
+	}
+}
+
+// finish closes the current section, if any
+func (c *caveatCollector) finish() {
+	if c.inside {
+		block := strings.TrimSpace(strings.Join(c.current, "\n"))
+		if block != "" && !slices.Contains(c.blocks, block) {
+			c.blocks = append(c.blocks, block)
+		}
+	}
+	c.inside = false
+	c.current = nil
+}
+
+// String returns the collected caveats, separated by blank lines
+func (c *caveatCollector) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.finish()
+	return strings.Join(c.blocks, "\n\n")
+}
+
+// ParseCaveats returns the caveats in brew install output, or "" if there are none
+func ParseCaveats(output string) string {
+	var c caveatCollector
+	for _, line := range strings.Split(output, "\n") {
+		c.add(line)
+	}
+	return c.String()
+}
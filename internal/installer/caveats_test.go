@@ -0,0 +1,67 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const sampleInstallOutput = `==> Fetching postgresql@16
+==> Downloading https://ghcr.io/v2/homebrew/core/postgresql/16/manifests/16.2
+==> Pouring postgresql@16--16.2.arm64_sonoma.bottle.tar.gz
+==> Caveats
+This formula has created a default database cluster with:
+  initdb --locale=C -E UTF-8 /opt/homebrew/var/postgresql@16
+
+To start postgresql@16 now and restart at login:
+  brew services start postgresql@16
+==> Summary
+🍺  /opt/homebrew/Cellar/postgresql@16/16.2: 3,798 files, 67.9MB
+==> Running ` + "`brew cleanup postgresql@16`" + `...
+==> Caveats
+==> postgresql@16
+This formula has created a default database cluster with:
+  initdb --locale=C -E UTF-8 /opt/homebrew/var/postgresql@16
+
+To start postgresql@16 now and restart at login:
+  brew services start postgresql@16
+==> icu4c
+icu4c is keg-only, which means it was not symlinked into /opt/homebrew.
+`
+
+func TestParseCaveats(t *testing.T) {
+	caveats := ParseCaveats(sampleInstallOutput)
+
+	assert.Equal(t, `This formula has created a default database cluster with:
+  initdb --locale=C -E UTF-8 /opt/homebrew/var/postgresql@16
+
+To start postgresql@16 now and restart at login:
+  brew services start postgresql@16
+
+icu4c is keg-only, which means it was not symlinked into /opt/homebrew.`, caveats)
+
+	assert.Empty(t, ParseCaveats("==> Pouring jq.bottle.tar.gz\n==> Summary\n🍺  /opt/homebrew/Cellar/jq/1.7.1\n"))
+	assert.Empty(t, ParseCaveats(""))
+}
+
+func TestBrewInstaller_CollectsCaveats(t *testing.T) {
+	stubBrew(t, `case "$3" in
+  postgresql@16) printf '==> Pouring postgresql@16\n==> Caveats\nTo start postgresql@16:\n  brew services start postgresql@16\n==> Summary\n' ;;
+  fails) printf '==> Caveats\nnever mind\n'; exit 1 ;;
+  *) echo "==> Pouring $3" ;;
+esac
+`)
+
+	b := NewBrewInstaller()
+	var lines []string
+	assert.NoError(t, b.InstallWithProgress(brewfile.NewPackage(brewfile.TypeBrew, "postgresql@16"), func(line string) { lines = append(lines, line) }))
+	assert.NoError(t, b.Install(brewfile.NewPackage(brewfile.TypeBrew, "jq")))
+	assert.Error(t, b.Install(brewfile.NewPackage(brewfile.TypeBrew, "fails")))
+
+	assert.Contains(t, lines, "==> Caveats")
+	assert.Equal(t, map[string]string{
+		"brew:postgresql@16": "To start postgresql@16:\n  brew services start postgresql@16",
+	}, b.Caveats())
+}
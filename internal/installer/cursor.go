@@ -1,8 +1,6 @@
 package installer
 
 import (
-	"strings"
-
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/exec"
 )
@@ -23,24 +21,12 @@ func NewCursorInstaller() *CursorInstaller {
 
 // List returns all installed Cursor extensions
 func (c *CursorInstaller) List() (brewfile.Packages, error) {
-	lines, err := c.runner.RunLines(c.command, "--list-extensions")
-	if err != nil {
-		return nil, err
-	}
-
-	var packages brewfile.Packages
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			packages = append(packages, brewfile.NewPackage(brewfile.TypeCursor, line))
-		}
-	}
-	return packages, nil
+	return listExtensions(c.runner, c.command, brewfile.TypeCursor)
 }
 
-// Install installs a Cursor extension
+// Install installs a Cursor extension, at its pinned version if it has one
 func (c *CursorInstaller) Install(pkg brewfile.Package) error {
-	_, err := c.runner.Run(c.command, "--install-extension", pkg.Name)
+	_, err := c.runner.Run(c.command, "--install-extension", pkg.VersionedName())
 	return err
 }
 
@@ -0,0 +1,208 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const (
+	// DeprecationCacheFile is the name of the deprecation cache in the cache directory
+	DeprecationCacheFile = "deprecations.json"
+	// DeprecationCacheTTL is how long a looked-up deprecation status is reused
+	DeprecationCacheTTL = 24 * time.Hour
+)
+
+// Deprecation describes a formula or cask Homebrew has deprecated or disabled.
+// Disabled packages can no longer be installed; deprecated ones still install
+// with a warning until they are disabled.
+type Deprecation struct {
+	Disabled bool   `json:"disabled,omitempty"`
+	Date     string `json:"date,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// Replacement is the formula or cask Homebrew suggests instead, if any
+	Replacement     string               `json:"replacement,omitempty"`
+	ReplacementType brewfile.PackageType `json:"replacement_type,omitempty"`
+}
+
+// Status returns "disabled" or "deprecated"
+func (d Deprecation) Status() string {
+	if d.Disabled {
+		return "disabled"
+	}
+	return "deprecated"
+}
+
+// deprecationInfo holds the deprecation fields formulae and casks share in
+// 'brew info --json=v2'. Older brew versions have a single replacement field;
+// newer ones say whether the replacement is a formula or a cask.
+type deprecationInfo struct {
+	Deprecated                    bool    `json:"deprecated"`
+	DeprecationDate               *string `json:"deprecation_date"`
+	DeprecationReason             *string `json:"deprecation_reason"`
+	DeprecationReplacement        *string `json:"deprecation_replacement"`
+	DeprecationReplacementFormula *string `json:"deprecation_replacement_formula"`
+	DeprecationReplacementCask    *string `json:"deprecation_replacement_cask"`
+	Disabled                      bool    `json:"disabled"`
+	DisableDate                   *string `json:"disable_date"`
+	DisableReason                 *string `json:"disable_reason"`
+	DisableReplacement            *string `json:"disable_replacement"`
+	DisableReplacementFormula     *string `json:"disable_replacement_formula"`
+	DisableReplacementCask        *string `json:"disable_replacement_cask"`
+}
+
+// deprecation converts the brew fields, returning false for healthy packages.
+// sameType is the package's own type, assumed for untyped replacements.
+func (info deprecationInfo) deprecation(sameType brewfile.PackageType) (Deprecation, bool) {
+	str := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	replacement := func(any, formula, cask *string) (string, brewfile.PackageType) {
+		switch {
+		case str(formula) != "":
+			return str(formula), brewfile.TypeBrew
+		case str(cask) != "":
+			return str(cask), brewfile.TypeCask
+		case str(any) != "":
+			return str(any), sameType
+		}
+		return "", ""
+	}
+
+	switch {
+	case info.Disabled:
+		d := Deprecation{Disabled: true, Date: str(info.DisableDate), Reason: str(info.DisableReason)}
+		d.Replacement, d.ReplacementType = replacement(info.DisableReplacement, info.DisableReplacementFormula, info.DisableReplacementCask)
+		return d, true
+	case info.Deprecated:
+		d := Deprecation{Date: str(info.DeprecationDate), Reason: str(info.DeprecationReason)}
+		d.Replacement, d.ReplacementType = replacement(info.DeprecationReplacement, info.DeprecationReplacementFormula, info.DeprecationReplacementCask)
+		return d, true
+	}
+	return Deprecation{}, false
+}
+
+// ParseDeprecations reads 'brew info --json=v2' output and returns the
+// deprecated and disabled formulae and casks keyed by package ID (brew:git,
+// cask:firefox). Tap packages are keyed by both their short and full name.
+func ParseDeprecations(data []byte) (map[string]Deprecation, error) {
+	var info struct {
+		Formulae []struct {
+			deprecationInfo
+			Name     string `json:"name"`
+			FullName string `json:"full_name"`
+		} `json:"formulae"`
+		Casks []struct {
+			deprecationInfo
+			Token     string `json:"token"`
+			FullToken string `json:"full_token"`
+		} `json:"casks"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info output: %w", err)
+	}
+
+	result := make(map[string]Deprecation)
+	add := func(t brewfile.PackageType, d Deprecation, names ...string) {
+		for _, name := range names {
+			if name != "" {
+				result[brewfile.NewPackage(t, name).ID()] = d
+			}
+		}
+	}
+	for _, f := range info.Formulae {
+		if d, ok := f.deprecation(brewfile.TypeBrew); ok {
+			add(brewfile.TypeBrew, d, f.Name, f.FullName)
+		}
+	}
+	for _, c := range info.Casks {
+		if d, ok := c.deprecation(brewfile.TypeCask); ok {
+			add(brewfile.TypeCask, d, c.Token, c.FullToken)
+		}
+	}
+	return result, nil
+}
+
+// cachedDeprecation is a deprecation cache entry; Deprecation is nil for
+// packages that were looked up and are fine
+type cachedDeprecation struct {
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+	FetchedAt   time.Time    `json:"fetched_at"`
+}
+
+func (c cachedDeprecation) fetchedAt() time.Time { return c.FetchedAt }
+
+// Deprecations returns the deprecated and disabled formulae and casks in pkgs,
+// keyed by package ID. Statuses come from the cache at cachePath when fresh and
+// from 'brew info --json=v2' otherwise; the cache is then updated. brew may
+// download its index for this. An empty cachePath disables the cache.
+func (b *BrewInstaller) Deprecations(pkgs brewfile.Packages, cachePath string) (map[string]Deprecation, error) {
+	now := time.Now()
+	cache := loadCache[cachedDeprecation](cachePath)
+
+	missing := make(map[brewfile.PackageType][]string)
+	for _, pkg := range pkgs {
+		if pkg.Type != brewfile.TypeBrew && pkg.Type != brewfile.TypeCask {
+			continue
+		}
+		if entry, ok := cache[pkg.ID()]; ok && now.Sub(entry.FetchedAt) < DeprecationCacheTTL {
+			continue
+		}
+		missing[pkg.Type] = append(missing[pkg.Type], pkg.Name)
+	}
+
+	for _, t := range []brewfile.PackageType{brewfile.TypeBrew, brewfile.TypeCask} {
+		names := missing[t]
+		if len(names) == 0 {
+			continue
+		}
+		found, err := b.lookupDeprecations(t, names)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			id := brewfile.NewPackage(t, name).ID()
+			entry := cachedDeprecation{FetchedAt: now}
+			if d, ok := found[id]; ok {
+				entry.Deprecation = &d
+			}
+			cache[id] = entry
+		}
+	}
+	if len(missing) > 0 {
+		saveCache(cachePath, cache, DeprecationCacheTTL)
+	}
+
+	result := make(map[string]Deprecation)
+	for _, pkg := range pkgs {
+		if entry, ok := cache[pkg.ID()]; ok && entry.Deprecation != nil {
+			result[pkg.ID()] = *entry.Deprecation
+		}
+	}
+	return result, nil
+}
+
+// lookupDeprecations asks brew info about the deprecation of names of type t,
+// as infoEach does, so a formula or cask removed upstream doesn't hide the
+// others. Names Homebrew can't find are skipped.
+func (b *BrewInstaller) lookupDeprecations(t brewfile.PackageType, names []string) (map[string]Deprecation, error) {
+	found := make(map[string]Deprecation)
+	_, err := b.infoEach(t, names, func(output string) error {
+		one, err := ParseDeprecations([]byte(output))
+		if err != nil {
+			return err
+		}
+		maps.Copy(found, one)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
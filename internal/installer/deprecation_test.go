@@ -0,0 +1,107 @@
+package installer
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const sampleDeprecatedInfo = `{
+  "formulae": [
+    {"name": "git", "full_name": "git", "deprecated": false, "disabled": false},
+    {"name": "youtube-dl", "full_name": "youtube-dl", "deprecated": true, "deprecation_date": "2024-10-05",
+     "deprecation_reason": "does not pass the ruby/python/etc. test suite", "deprecation_replacement_formula": "yt-dlp",
+     "disabled": false},
+    {"name": "python@3.8", "full_name": "python@3.8", "deprecated": true, "disabled": true,
+     "disable_date": "2024-10-14", "disable_reason": "unsupported", "disable_replacement": "python@3.12"},
+    {"name": "oldtool", "full_name": "user/tap/oldtool", "deprecated": true, "deprecation_reason": "repo_archived",
+     "deprecation_replacement": null}
+  ],
+  "casks": [
+    {"token": "firefox", "full_token": "firefox", "deprecated": false, "disabled": false},
+    {"token": "old-app", "full_token": "old-app", "deprecated": true, "deprecation_reason": "discontinued",
+     "deprecation_replacement_cask": "new-app"}
+  ]
+}`
+
+func TestParseDeprecations(t *testing.T) {
+	found, err := ParseDeprecations([]byte(sampleDeprecatedInfo))
+	require.NoError(t, err)
+
+	assert.NotContains(t, found, "brew:git")
+	assert.NotContains(t, found, "cask:firefox")
+
+	ytdl := found["brew:youtube-dl"]
+	assert.False(t, ytdl.Disabled)
+	assert.Equal(t, "deprecated", ytdl.Status())
+	assert.Equal(t, "2024-10-05", ytdl.Date)
+	assert.Equal(t, "yt-dlp", ytdl.Replacement)
+	assert.Equal(t, brewfile.TypeBrew, ytdl.ReplacementType)
+
+	python := found["brew:python@3.8"]
+	assert.True(t, python.Disabled)
+	assert.Equal(t, "disabled", python.Status())
+	assert.Equal(t, "unsupported", python.Reason)
+	assert.Equal(t, "python@3.12", python.Replacement)
+	assert.Equal(t, brewfile.TypeBrew, python.ReplacementType)
+
+	assert.Equal(t, "repo_archived", found["brew:oldtool"].Reason)
+	assert.Equal(t, "repo_archived", found["brew:user/tap/oldtool"].Reason)
+	assert.Empty(t, found["brew:oldtool"].Replacement)
+
+	assert.Equal(t, Deprecation{Reason: "discontinued", Replacement: "new-app", ReplacementType: brewfile.TypeCask}, found["cask:old-app"])
+
+	_, err = ParseDeprecations([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestBrewInstaller_Deprecations(t *testing.T) {
+	stubBrew(t, `echo '`+sampleDeprecatedInfo+`'`)
+	cachePath := filepath.Join(t.TempDir(), "cache", DeprecationCacheFile)
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "youtube-dl"),
+		brewfile.NewPackage(brewfile.TypeCask, "old-app"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "golang.go"),
+	}
+	found, err := NewBrewInstaller().Deprecations(pkgs, cachePath)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, "yt-dlp", found["brew:youtube-dl"].Replacement)
+	assert.Equal(t, "new-app", found["cask:old-app"].Replacement)
+
+	// Later checks are served from the cache, even when brew info fails
+	stubBrew(t, `echo "Error: offline" >&2; exit 1`)
+	found, err = NewBrewInstaller().Deprecations(pkgs, cachePath)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	// Packages not cached yet still need brew
+	_, err = NewBrewInstaller().Deprecations(brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "jq")}, cachePath)
+	assert.Error(t, err)
+}
+
+func TestBrewInstaller_Deprecations_RemovedPackage(t *testing.T) {
+	// brew info fails for the whole batch when one name is unknown
+	stubBrew(t, `for arg; do
+  case "$arg" in
+    gone-formula) echo "Error: No available formula with the name \"gone-formula\"." >&2; exit 1 ;;
+  esac
+done
+echo '`+sampleDeprecatedInfo+`'`)
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "youtube-dl"),
+		brewfile.NewPackage(brewfile.TypeBrew, "gone-formula"),
+	}
+	found, err := NewBrewInstaller().Deprecations(pkgs, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:youtube-dl"}, slices.Collect(maps.Keys(found)))
+}
@@ -0,0 +1,143 @@
+package installer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+)
+
+const (
+	// DescriptionCacheFile is the name of the description cache in the cache directory
+	DescriptionCacheFile = "descriptions.json"
+	// DescriptionCacheTTL is how long a looked-up description is reused
+	DescriptionCacheTTL = 7 * 24 * time.Hour
+	// DescriptionTimeout bounds all brew info lookups of one FillDescriptions call
+	DescriptionTimeout = 60 * time.Second
+)
+
+// brewInfo is the part of 'brew info --json=v2' used for descriptions
+type brewInfo struct {
+	Formulae []struct {
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+		Desc     string `json:"desc"`
+	} `json:"formulae"`
+	Casks []struct {
+		Token     string `json:"token"`
+		FullToken string `json:"full_token"`
+		Desc      string `json:"desc"`
+	} `json:"casks"`
+}
+
+// ParseDescriptions reads 'brew info --json=v2' output and returns each
+// formula's and cask's description keyed by package ID (brew:git,
+// cask:firefox). Tap packages are keyed by both their short and full name.
+func ParseDescriptions(data []byte) (map[string]string, error) {
+	var info brewInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info output: %w", err)
+	}
+
+	descs := make(map[string]string)
+	add := func(t brewfile.PackageType, desc string, names ...string) {
+		for _, name := range names {
+			if name != "" {
+				descs[brewfile.NewPackage(t, name).ID()] = desc
+			}
+		}
+	}
+	for _, f := range info.Formulae {
+		add(brewfile.TypeBrew, f.Desc, f.Name, f.FullName)
+	}
+	for _, c := range info.Casks {
+		add(brewfile.TypeCask, c.Desc, c.Token, c.FullToken)
+	}
+	return descs, nil
+}
+
+// NeedsDescriptions reports whether Homebrew packages were collected with
+// 'brew list' rather than 'brew bundle dump --describe' while
+// output.show_descriptions is on, so descriptions have to be looked up
+func NeedsDescriptions(cfg *config.Config, fellBack bool) bool {
+	return cfg.Output.ShowDescriptions && (fellBack || !cfg.UseBrewBundle(cfg.CurrentMachine))
+}
+
+// DescriptionCachePath is where looked-up descriptions are cached
+func DescriptionCachePath() string {
+	return filepath.Join(config.CacheDir(), DescriptionCacheFile)
+}
+
+// cachedDescription is a description cache entry
+type cachedDescription struct {
+	Description string    `json:"description"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+func (c cachedDescription) fetchedAt() time.Time { return c.FetchedAt }
+
+// FillDescriptions sets the missing descriptions of the formulae and casks in
+// pkgs, the way 'brew bundle dump --describe' would. Descriptions come from
+// the cache at cachePath when fresh and from 'brew info --json=v2' otherwise;
+// the cache is then updated. This is best-effort: lookups that fail or time
+// out just leave descriptions empty. An empty cachePath disables the cache.
+func (b *BrewInstaller) FillDescriptions(pkgs brewfile.Packages, cachePath string) {
+	now := time.Now()
+	cache := loadCache[cachedDescription](cachePath)
+
+	missing := make(map[brewfile.PackageType][]string)
+	for _, pkg := range pkgs {
+		if pkg.Description != "" || (pkg.Type != brewfile.TypeBrew && pkg.Type != brewfile.TypeCask) {
+			continue
+		}
+		if entry, ok := cache[pkg.ID()]; ok && now.Sub(entry.FetchedAt) < DescriptionCacheTTL {
+			continue
+		}
+		missing[pkg.Type] = append(missing[pkg.Type], pkg.Name)
+	}
+
+	if len(missing) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), DescriptionTimeout)
+		defer cancel()
+
+		for _, t := range []brewfile.PackageType{brewfile.TypeBrew, brewfile.TypeCask} {
+			names := missing[t]
+			if len(names) == 0 {
+				continue
+			}
+			flag := "--formula"
+			if t == brewfile.TypeCask {
+				flag = "--cask"
+			}
+			args := append([]string{"info", "--json=v2", flag}, names...)
+			output, err := b.runner.RunContext(ctx, "brew", args...)
+			if err != nil {
+				debug.Log("FillDescriptions: brew info %s failed: %v", flag, err)
+				continue
+			}
+			descs, err := ParseDescriptions([]byte(output))
+			if err != nil {
+				debug.Log("FillDescriptions: %v", err)
+				continue
+			}
+			// Cache packages without a description too, so they aren't looked up every dump
+			for _, name := range names {
+				id := brewfile.NewPackage(t, name).ID()
+				cache[id] = cachedDescription{Description: descs[id], FetchedAt: now}
+			}
+		}
+
+		saveCache(cachePath, cache, DescriptionCacheTTL)
+	}
+
+	for i, pkg := range pkgs {
+		if pkg.Description == "" {
+			pkgs[i].Description = cache[pkg.ID()].Description
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package installer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const sampleBrewInfo = `{
+  "formulae": [
+    {"name": "git", "full_name": "git", "tap": "homebrew/core", "desc": "Distributed revision control system"},
+    {"name": "sk", "full_name": "user/tap/sk", "tap": "user/tap", "desc": "Fuzzy Finder, in Rust"}
+  ],
+  "casks": [
+    {"token": "firefox", "full_token": "firefox", "name": ["Mozilla Firefox"], "desc": "Web browser"},
+    {"token": "nodesc", "full_token": "nodesc", "desc": null}
+  ]
+}`
+
+func TestParseDescriptions(t *testing.T) {
+	descs, err := ParseDescriptions([]byte(sampleBrewInfo))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Distributed revision control system", descs["brew:git"])
+	assert.Equal(t, "Fuzzy Finder, in Rust", descs["brew:user/tap/sk"])
+	assert.Equal(t, "Fuzzy Finder, in Rust", descs["brew:sk"])
+	assert.Equal(t, "Web browser", descs["cask:firefox"])
+	assert.Equal(t, "", descs["cask:nodesc"])
+	assert.NotContains(t, descs, "cask:git")
+
+	_, err = ParseDescriptions([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestBrewInstaller_FillDescriptions(t *testing.T) {
+	stubBrew(t, `case "$3" in
+  --formula) echo '{"formulae": [{"name": "git", "full_name": "git", "desc": "Distributed revision control system"}], "casks": []}' ;;
+  --cask) echo '{"formulae": [], "casks": [{"token": "firefox", "full_token": "firefox", "desc": "Web browser"}]}' ;;
+esac
+`)
+	cachePath := filepath.Join(t.TempDir(), "cache", DescriptionCacheFile)
+
+	described := brewfile.NewPackage(brewfile.TypeBrew, "jq")
+	described.Description = "Lightweight JSON processor"
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		described,
+		brewfile.NewPackage(brewfile.TypeVSCode, "golang.go"),
+	}
+
+	NewBrewInstaller().FillDescriptions(pkgs, cachePath)
+	assert.Equal(t, "Distributed revision control system", pkgs[0].Description)
+	assert.Equal(t, "Web browser", pkgs[1].Description)
+	assert.Equal(t, "Lightweight JSON processor", pkgs[2].Description)
+	assert.Empty(t, pkgs[3].Description)
+
+	// Later dumps are served from the cache, even when brew info fails
+	stubBrew(t, `echo "Error: offline" >&2; exit 1`)
+	again := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeCask, "zoom"),
+	}
+	NewBrewInstaller().FillDescriptions(again, cachePath)
+	assert.Equal(t, "Distributed revision control system", again[0].Description)
+	assert.Empty(t, again[1].Description)
+}
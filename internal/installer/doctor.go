@@ -0,0 +1,102 @@
+package installer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// fatalDoctorWarnings are 'brew doctor' warnings that make most installs fail,
+// so they count as errors
+var fatalDoctorWarnings = []string{
+	"not writable",
+	"No developer tools installed",
+	"Git could not be found",
+}
+
+// DoctorReport is the outcome of 'brew doctor'. Each issue is the first line
+// of its message, without the "Warning: " or "Error: " prefix.
+type DoctorReport struct {
+	Errors   []string
+	Warnings []string
+}
+
+// Healthy returns true if nothing was found that would make installs fail
+func (r DoctorReport) Healthy() bool {
+	return len(r.Errors) == 0
+}
+
+// Summary describes the report in one line ("ready", "2 warnings", "1 error, 3 warnings")
+func (r DoctorReport) Summary() string {
+	if len(r.Errors) == 0 && len(r.Warnings) == 0 {
+		return "ready"
+	}
+	var parts []string
+	if n := len(r.Errors); n > 0 {
+		parts = append(parts, plural(n, "error"))
+	}
+	if n := len(r.Warnings); n > 0 {
+		parts = append(parts, plural(n, "warning"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// plural formats n with word, adding an "s" unless n is 1
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// ParseDoctor classifies 'brew doctor' output. "Error:" lines and the warnings
+// in fatalDoctorWarnings are errors; other warnings are only reported. The
+// explanation following each issue is skipped.
+func ParseDoctor(output string) DoctorReport {
+	var report DoctorReport
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Error:"):
+			report.Errors = append(report.Errors, strings.TrimSpace(strings.TrimPrefix(line, "Error:")))
+		case strings.HasPrefix(line, "Warning:"):
+			issue := strings.TrimSpace(strings.TrimPrefix(line, "Warning:"))
+			if isFatalDoctorWarning(issue) {
+				report.Errors = append(report.Errors, issue)
+			} else {
+				report.Warnings = append(report.Warnings, issue)
+			}
+		}
+	}
+	return report
+}
+
+// isFatalDoctorWarning returns true if a warning is in fatalDoctorWarnings
+func isFatalDoctorWarning(issue string) bool {
+	for _, s := range fatalDoctorWarnings {
+		if strings.Contains(issue, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Doctor runs 'brew doctor' and classifies what it finds. brew doctor exits
+// non-zero whenever it has warnings, so its output decides the result; an
+// error is returned only when brew produced none.
+func (b *BrewInstaller) Doctor() (DoctorReport, error) {
+	// stdout and stderr lines arrive from separate goroutines
+	var mu sync.Mutex
+	var lines []string
+	err := b.runner.RunStreamingIn("", []string{"HOMEBREW_NO_AUTO_UPDATE=1"}, func(line string) {
+		mu.Lock()
+		lines = append(lines, line)
+		mu.Unlock()
+	}, "brew", "doctor")
+
+	report := ParseDoctor(strings.Join(lines, "\n"))
+	if err != nil && len(report.Errors) == 0 && len(report.Warnings) == 0 {
+		return report, fmt.Errorf("brew doctor failed: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,76 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDoctor(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		errors   []string
+		warnings []string
+		healthy  bool
+		summary  string
+	}{
+		{
+			name:    "ready",
+			output:  "Your system is ready to brew.\n",
+			healthy: true,
+			summary: "ready",
+		},
+		{
+			name: "warnings only",
+			output: `Please note that these warnings are just used to help the Homebrew maintainers
+with debugging if you file an issue. If everything you use Homebrew for is
+working fine: please don't worry or file an issue; just ignore this. Thanks!
+
+Warning: Some installed formulae are deprecated or disabled.
+You should find replacements for the following formulae:
+  python@3.8
+
+Warning: A newer Command Line Tools release is available.
+Update them from Software Update in System Settings.
+`,
+			warnings: []string{
+				"Some installed formulae are deprecated or disabled.",
+				"A newer Command Line Tools release is available.",
+			},
+			healthy: true,
+			summary: "2 warnings",
+		},
+		{
+			name: "unwritable directories are fatal",
+			output: `Warning: The following directories are not writable by your user:
+/opt/homebrew/bin
+/opt/homebrew/share
+
+You should change the ownership of these directories to your user.
+  sudo chown -R $(whoami) /opt/homebrew/bin /opt/homebrew/share
+
+Warning: Unbrewed dylibs were found in /usr/local/lib.
+`,
+			errors:   []string{"The following directories are not writable by your user:"},
+			warnings: []string{"Unbrewed dylibs were found in /usr/local/lib."},
+			summary:  "1 error, 1 warning",
+		},
+		{
+			name:    "errors",
+			output:  "Error: No developer tools installed.\nError: Git must be installed and in your PATH!\n",
+			errors:  []string{"No developer tools installed.", "Git must be installed and in your PATH!"},
+			summary: "2 errors",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := ParseDoctor(tt.output)
+			assert.Equal(t, tt.errors, report.Errors)
+			assert.Equal(t, tt.warnings, report.Warnings)
+			assert.Equal(t, tt.healthy, report.Healthy())
+			assert.Equal(t, tt.summary, report.Summary())
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package installer
+
+import (
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// listExtensions lists the extensions of a VS Code-compatible editor CLI.
+// Versions are captured with --show-versions ("pub.ext@1.2.3") when the CLI
+// supports it; CLIs that reject the flag are asked for the plain list.
+func listExtensions(runner *exec.Runner, command string, t brewfile.PackageType) (brewfile.Packages, error) {
+	lines, err := runner.RunLines(command, "--list-extensions", "--show-versions")
+	if err != nil {
+		lines, err = runner.RunLines(command, "--list-extensions")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var packages brewfile.Packages
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, version := brewfile.SplitExtensionVersion(line)
+		pkg := brewfile.NewPackage(t, name)
+		pkg.Version = version
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
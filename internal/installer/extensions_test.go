@@ -0,0 +1,52 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// stubEditor puts a fake editor CLI named command on PATH
+func stubEditor(t *testing.T, command, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, command), []byte("#!/bin/sh\n"+script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestVSCodeInstaller_ListVersions(t *testing.T) {
+	stubEditor(t, "code", `echo "golang.go@0.42.0"; echo "ms-python.python@2024.1.0"`)
+
+	pkgs, err := NewVSCodeInstaller().List()
+	require.NoError(t, err)
+	require.Len(t, pkgs, 2)
+	assert.Equal(t, brewfile.Package{Type: brewfile.TypeVSCode, Name: "golang.go", Version: "0.42.0"}, pkgs[0])
+	assert.Equal(t, "2024.1.0", pkgs[1].Version)
+}
+
+func TestCursorInstaller_ListWithoutShowVersions(t *testing.T) {
+	stubEditor(t, "cursor", `[ "$2" = "--show-versions" ] && { echo "unknown option" >&2; exit 1; }
+echo "golang.go"`)
+
+	pkgs, err := NewCursorInstaller().List()
+	require.NoError(t, err)
+	assert.Equal(t, brewfile.Packages{brewfile.NewPackage(brewfile.TypeCursor, "golang.go")}, pkgs)
+}
+
+func TestVSCodeInstaller_InstallPinned(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	stubEditor(t, "code", `echo "$@" >> "`+argsFile+`"`)
+
+	inst := NewVSCodeInstaller()
+	require.NoError(t, inst.Install(brewfile.Package{Type: brewfile.TypeVSCode, Name: "golang.go", Version: "0.42.0"}))
+	require.NoError(t, inst.Install(brewfile.NewPackage(brewfile.TypeVSCode, "ms-python.python")))
+
+	data, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "--install-extension golang.go@0.42.0\n--install-extension ms-python.python\n", string(data))
+}
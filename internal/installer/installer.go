@@ -2,6 +2,9 @@ package installer
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 )
@@ -22,8 +25,29 @@ type Manager struct {
 	antigravity *AntigravityInstaller
 	mas         *MasInstaller
 	go_         *GoToolsInstaller
+	npm         *NpmInstaller
+	pipx        *PipxInstaller
+
+	hook OperationHook
+
+	retry    RetryPolicy
+	parallel int                 // Packages InstallMany installs at once; below 2 means one by one
+	sleep    func(time.Duration) // Waits between retries; replaced in tests
+	// attempts holds the attempts the last install of each package ID took
+	attemptsMu sync.Mutex
+	attempts   map[string]int
 }
 
+// Actions reported to an OperationHook
+const (
+	ActionInstall   = "install"
+	ActionUninstall = "uninstall"
+)
+
+// OperationHook is called after every install or uninstall the Manager
+// attempts, with its error if it failed
+type OperationHook func(action string, pkg brewfile.Package, err error)
+
 // NewManager creates a new installation manager
 func NewManager() *Manager {
 	return &Manager{
@@ -33,7 +57,51 @@ func NewManager() *Manager {
 		antigravity: NewAntigravityInstaller(),
 		mas:         NewMasInstaller(),
 		go_:         NewGoToolsInstaller(),
+		npm:         NewNpmInstaller(),
+		pipx:        NewPipxInstaller(),
+		sleep:       time.Sleep,
+	}
+}
+
+// SetCaskNoQuarantine makes cask installs pass --no-quarantine (config
+// install.cask_no_quarantine or --no-quarantine). Other types are unaffected.
+func (m *Manager) SetCaskNoQuarantine(enabled bool) {
+	m.brew.NoQuarantine = enabled
+}
+
+// SetRetryPolicy makes installs that fail retry as the policy says. Without
+// one, a failed install isn't retried.
+func (m *Manager) SetRetryPolicy(policy RetryPolicy) {
+	m.retry = policy
+}
+
+// SetParallel makes InstallMany install up to n packages at once (see
+// InstallConcurrently). Below 2, packages are installed one by one.
+func (m *Manager) SetParallel(n int) {
+	m.parallel = n
+}
+
+// SetHook registers a hook called after each install and uninstall
+func (m *Manager) SetHook(hook OperationHook) {
+	m.hook = hook
+}
+
+// report passes an operation's outcome to the hook, if any, and returns err
+func (m *Manager) report(action string, pkg brewfile.Package, err error) error {
+	if m.hook != nil {
+		m.hook(action, pkg, err)
 	}
+	return err
+}
+
+// InstallCommand returns the brew command line that installs a package,
+// or "" for types not installed through brew (used for dry-run output)
+func (m *Manager) InstallCommand(pkg brewfile.Package) string {
+	args := m.brew.InstallArgs(pkg)
+	if args == nil {
+		return ""
+	}
+	return "brew " + strings.Join(args, " ")
 }
 
 // Install installs a package using the appropriate installer
@@ -41,8 +109,15 @@ func (m *Manager) Install(pkg brewfile.Package) error {
 	return m.InstallWithProgress(pkg, nil)
 }
 
-// InstallWithProgress installs a package and streams output to a callback
+// InstallWithProgress installs a package and streams output to a callback.
+// A failed install is retried as the retry policy says, and the retries are
+// announced through the callback.
 func (m *Manager) InstallWithProgress(pkg brewfile.Package, onOutput func(line string)) error {
+	return m.report(ActionInstall, pkg, m.install(pkg, onOutput))
+}
+
+// install installs a package with the installer for its type
+func (m *Manager) install(pkg brewfile.Package, onOutput func(line string)) error {
 	installer, err := m.getInstaller(pkg.Type)
 	if err != nil {
 		return err
@@ -52,17 +127,50 @@ func (m *Manager) InstallWithProgress(pkg brewfile.Package, onOutput func(line s
 		return fmt.Errorf("%s installer not available", pkg.Type)
 	}
 
-	// Use specialized method for brew packages that support streaming
-	if pkg.Type == brewfile.TypeTap || pkg.Type == brewfile.TypeBrew || pkg.Type == brewfile.TypeCask {
-		return m.brew.InstallWithProgress(pkg, onOutput)
+	attempts, err := m.retry.run(func() error {
+		// Use specialized method for brew packages that support streaming
+		if pkg.Type == brewfile.TypeTap || pkg.Type == brewfile.TypeBrew || pkg.Type == brewfile.TypeCask {
+			return m.brew.InstallWithProgress(pkg, onOutput)
+		}
+
+		// Other installers don't support streaming yet, use regular install
+		return installer.Install(pkg)
+	}, m.sleep, onOutput)
+	m.recordAttempts(pkg, attempts)
+	return err
+}
+
+// recordAttempts remembers how many attempts installing a package took
+func (m *Manager) recordAttempts(pkg brewfile.Package, attempts int) {
+	m.attemptsMu.Lock()
+	defer m.attemptsMu.Unlock()
+	if m.attempts == nil {
+		m.attempts = make(map[string]int)
 	}
+	m.attempts[pkg.ID()] = attempts
+}
 
-	// Other installers don't support streaming yet, use regular install
-	return installer.Install(pkg)
+// Attempts returns how many attempts the last install of a package took: 1
+// unless it was retried, and 0 if it wasn't installed through this Manager
+func (m *Manager) Attempts(pkg brewfile.Package) int {
+	m.attemptsMu.Lock()
+	defer m.attemptsMu.Unlock()
+	return m.attempts[pkg.ID()]
+}
+
+// Caveats returns the caveats brew printed for packages installed so far,
+// keyed by package ID
+func (m *Manager) Caveats() map[string]string {
+	return m.brew.Caveats()
 }
 
 // Uninstall removes a package using the appropriate installer
 func (m *Manager) Uninstall(pkg brewfile.Package) error {
+	return m.report(ActionUninstall, pkg, m.uninstall(pkg))
+}
+
+// uninstall removes a package with the installer for its type
+func (m *Manager) uninstall(pkg brewfile.Package) error {
 	installer, err := m.getInstaller(pkg.Type)
 	if err != nil {
 		return err
@@ -80,7 +188,9 @@ func (m *Manager) InstallMany(packages brewfile.Packages, onProgress func(pkg br
 	return m.InstallManyWithOutput(packages, onProgress, nil)
 }
 
-// InstallManyWithOutput installs multiple packages with progress and output streaming
+// InstallManyWithOutput installs multiple packages with progress and output streaming.
+// With SetParallel, packages are installed concurrently and reported in the
+// order they finish; the callbacks are never called at the same time.
 func (m *Manager) InstallManyWithOutput(
 	packages brewfile.Packages,
 	onProgress func(pkg brewfile.Package, i, total int, err error),
@@ -89,6 +199,34 @@ func (m *Manager) InstallManyWithOutput(
 	var lastErr error
 	total := len(packages)
 
+	if m.parallel > 1 {
+		var mu sync.Mutex
+		done := 0
+		InstallConcurrently(packages, m.parallel, func(pkg brewfile.Package) {
+			var err error
+			if onOutput != nil {
+				err = m.InstallWithProgress(pkg, func(line string) {
+					mu.Lock()
+					defer mu.Unlock()
+					onOutput(pkg, line)
+				})
+			} else {
+				err = m.Install(pkg)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if onProgress != nil {
+				onProgress(pkg, done, total, err)
+			}
+			if err != nil {
+				lastErr = err
+			}
+		})
+		return lastErr
+	}
+
 	for i, pkg := range packages {
 		var err error
 
@@ -170,6 +308,24 @@ func (m *Manager) ListAll() (brewfile.Packages, error) {
 		all = append(all, pkgs...)
 	}
 
+	// npm globals
+	if m.npm.IsAvailable() {
+		pkgs, err := m.npm.List()
+		if err != nil {
+			return nil, fmt.Errorf("npm list failed: %w", err)
+		}
+		all = append(all, pkgs...)
+	}
+
+	// pipx apps
+	if m.pipx.IsAvailable() {
+		pkgs, err := m.pipx.List()
+		if err != nil {
+			return nil, fmt.Errorf("pipx list failed: %w", err)
+		}
+		all = append(all, pkgs...)
+	}
+
 	// MAS
 	if m.mas.IsAvailable() {
 		pkgs, err := m.mas.List()
@@ -182,6 +338,33 @@ func (m *Manager) ListAll() (brewfile.Packages, error) {
 	return all, nil
 }
 
+// ListTopLevel is ListAll without the formulae installed as dependencies of
+// others, which a Brewfile doesn't list either
+func (m *Manager) ListTopLevel() (brewfile.Packages, error) {
+	all, err := m.ListAll()
+	if err != nil || !m.brew.IsAvailable() {
+		return all, err
+	}
+
+	leaves, err := m.brew.ListLeaves()
+	if err != nil {
+		return nil, fmt.Errorf("brew leaves failed: %w", err)
+	}
+	isLeaf := make(map[string]bool, len(leaves))
+	for _, pkg := range leaves {
+		isLeaf[pkg.ID()] = true
+	}
+
+	var result brewfile.Packages
+	for _, pkg := range all {
+		if pkg.Type == brewfile.TypeBrew && !isLeaf[pkg.ID()] {
+			continue
+		}
+		result = append(result, pkg)
+	}
+	return result, nil
+}
+
 // IsAvailable checks if the installer for a package type is available
 func (m *Manager) IsAvailable(pkgType brewfile.PackageType) bool {
 	installer, err := m.getInstaller(pkgType)
@@ -206,6 +389,10 @@ func (m *Manager) getInstaller(pkgType brewfile.PackageType) (Installer, error)
 		return m.mas, nil
 	case brewfile.TypeGo:
 		return m.go_, nil
+	case brewfile.TypeNpm:
+		return m.npm, nil
+	case brewfile.TypePipx:
+		return m.pipx, nil
 	default:
 		return nil, fmt.Errorf("unknown package type: %s", pkgType)
 	}
@@ -220,5 +407,7 @@ func (m *Manager) AvailableInstallers() map[string]bool {
 		"antigravity": m.antigravity.IsAvailable(),
 		"mas":         m.mas.IsAvailable(),
 		"go":          m.go_.IsAvailable(),
+		"npm":         m.npm.IsAvailable(),
+		"pipx":        m.pipx.IsAvailable(),
 	}
 }
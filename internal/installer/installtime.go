@@ -0,0 +1,87 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// installInfo is the part of 'brew info --installed --json=v2' used for install times
+type installInfo struct {
+	Formulae []struct {
+		Name      string `json:"name"`
+		FullName  string `json:"full_name"`
+		Installed []struct {
+			Time int64 `json:"time"`
+		} `json:"installed"`
+	} `json:"formulae"`
+	Casks []struct {
+		Token         string `json:"token"`
+		FullToken     string `json:"full_token"`
+		InstalledTime int64  `json:"installed_time"`
+	} `json:"casks"`
+}
+
+// ParseInstallTimes reads 'brew info --installed --json=v2' output and returns
+// when each formula and cask was installed, keyed by package ID (brew:git,
+// cask:firefox). Tap packages are keyed by both their short and full name.
+// A formula with several installed versions uses its latest install; entries
+// without a time (brew leaves it out of old receipts) are omitted.
+func ParseInstallTimes(data []byte) (map[string]time.Time, error) {
+	var info installInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info output: %w", err)
+	}
+
+	times := make(map[string]time.Time)
+	add := func(t brewfile.PackageType, unix int64, names ...string) {
+		if unix <= 0 {
+			return
+		}
+		for _, name := range names {
+			if name != "" {
+				times[brewfile.NewPackage(t, name).ID()] = time.Unix(unix, 0)
+			}
+		}
+	}
+	for _, f := range info.Formulae {
+		var latest int64
+		for _, keg := range f.Installed {
+			if keg.Time > latest {
+				latest = keg.Time
+			}
+		}
+		add(brewfile.TypeBrew, latest, f.Name, f.FullName)
+	}
+	for _, c := range info.Casks {
+		add(brewfile.TypeCask, c.InstalledTime, c.Token, c.FullToken)
+	}
+	return times, nil
+}
+
+// InstallTimes looks up when the installed formulae and casks were installed
+// with 'brew info --installed --json=v2' (see ParseInstallTimes)
+func (b *BrewInstaller) InstallTimes() (map[string]time.Time, error) {
+	output, err := b.runner.Run("brew", "info", "--installed", "--json=v2")
+	if err != nil {
+		return nil, fmt.Errorf("brew info failed: %w", err)
+	}
+	return ParseInstallTimes([]byte(output))
+}
+
+// FillInstallTimes sets InstalledAt on the formulae and casks in pkgs that
+// are installed on this machine. Other packages keep a zero InstalledAt.
+func (b *BrewInstaller) FillInstallTimes(pkgs brewfile.Packages) error {
+	times, err := b.InstallTimes()
+	if err != nil {
+		return err
+	}
+	for i, pkg := range pkgs {
+		if at, ok := times[pkg.ID()]; ok {
+			pkgs[i].InstalledAt = at
+		}
+	}
+	return nil
+}
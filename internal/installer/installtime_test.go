@@ -0,0 +1,58 @@
+package installer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const sampleInstalledInfo = `{
+  "formulae": [
+    {"name": "git", "full_name": "git", "installed": [{"version": "2.44.0", "time": 1700000000}, {"version": "2.45.0", "time": 1760000000}]},
+    {"name": "sk", "full_name": "user/tap/sk", "installed": [{"version": "0.10", "time": 1750000000}]},
+    {"name": "old", "full_name": "old", "installed": [{"version": "1.0", "time": null}]}
+  ],
+  "casks": [
+    {"token": "firefox", "full_token": "firefox", "installed_time": 1755000000},
+    {"token": "notime", "full_token": "notime", "installed_time": null}
+  ]
+}`
+
+func TestParseInstallTimes(t *testing.T) {
+	times, err := ParseInstallTimes([]byte(sampleInstalledInfo))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Unix(1760000000, 0), times["brew:git"])
+	assert.Equal(t, time.Unix(1750000000, 0), times["brew:sk"])
+	assert.Equal(t, time.Unix(1750000000, 0), times["brew:user/tap/sk"])
+	assert.Equal(t, time.Unix(1755000000, 0), times["cask:firefox"])
+	assert.NotContains(t, times, "brew:old")
+	assert.NotContains(t, times, "cask:notime")
+
+	_, err = ParseInstallTimes([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestBrewInstaller_FillInstallTimes(t *testing.T) {
+	stubBrew(t, `echo '`+sampleInstalledInfo+`'`)
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeBrew, "missing"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "golang.go"),
+	}
+	require.NoError(t, NewBrewInstaller().FillInstallTimes(pkgs))
+
+	assert.Equal(t, time.Unix(1760000000, 0), pkgs[0].InstalledAt)
+	assert.Equal(t, time.Unix(1755000000, 0), pkgs[1].InstalledAt)
+	assert.True(t, pkgs[2].InstalledAt.IsZero())
+	assert.True(t, pkgs[3].InstalledAt.IsZero())
+
+	stubBrew(t, `echo "Error: offline" >&2; exit 1`)
+	assert.Error(t, NewBrewInstaller().FillInstallTimes(pkgs))
+}
@@ -0,0 +1,79 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// npmBundled are global packages that ship with Node itself rather than
+// being installed by the user
+var npmBundled = map[string]bool{"npm": true, "corepack": true}
+
+// NpmInstaller handles globally installed npm packages
+type NpmInstaller struct {
+	runner *exec.Runner
+}
+
+// NewNpmInstaller creates a new npm global package installer
+func NewNpmInstaller() *NpmInstaller {
+	return &NpmInstaller{
+		runner: exec.Default,
+	}
+}
+
+// List returns the globally installed npm packages. npm ls exits 1 over
+// peer dependency or extraneous package problems while still listing every
+// package, so its output is used whenever it is valid JSON.
+func (n *NpmInstaller) List() (brewfile.Packages, error) {
+	output, err := n.runner.RunOutput("npm", "ls", "-g", "--depth=0", "--json")
+	if err != nil && !json.Valid([]byte(output)) {
+		return nil, err
+	}
+	return parseNpmList([]byte(output))
+}
+
+// parseNpmList parses the output of 'npm ls -g --json', leaving out the
+// packages bundled with Node
+func parseNpmList(data []byte) (brewfile.Packages, error) {
+	var list struct {
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse npm ls output: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Dependencies))
+	for name := range list.Dependencies {
+		if !npmBundled[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	packages := make(brewfile.Packages, 0, len(names))
+	for _, name := range names {
+		packages = append(packages, brewfile.NewPackage(brewfile.TypeNpm, name))
+	}
+	return packages, nil
+}
+
+// Install installs an npm package globally
+func (n *NpmInstaller) Install(pkg brewfile.Package) error {
+	_, err := n.runner.Run("npm", "i", "-g", pkg.Name)
+	return err
+}
+
+// Uninstall removes a global npm package
+func (n *NpmInstaller) Uninstall(pkg brewfile.Package) error {
+	_, err := n.runner.Run("npm", "uninstall", "-g", pkg.Name)
+	return err
+}
+
+// IsAvailable checks if npm is available
+func (n *NpmInstaller) IsAvailable() bool {
+	return n.runner.Exists("npm")
+}
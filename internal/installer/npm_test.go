@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const sampleNpmList = `{
+  "name": "lib",
+  "dependencies": {
+    "typescript": {"version": "5.3.3", "overridden": false},
+    "@angular/cli": {"version": "17.0.0", "overridden": false},
+    "npm": {"version": "10.2.4", "overridden": false},
+    "corepack": {"version": "0.23.0", "overridden": false},
+    "prettier": {"version": "3.1.1", "overridden": false}
+  }
+}`
+
+func TestParseNpmList(t *testing.T) {
+	pkgs, err := parseNpmList([]byte(sampleNpmList))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"npm:@angular/cli", "npm:prettier", "npm:typescript"}, pkgs.IDs())
+
+	// Nothing installed globally
+	pkgs, err = parseNpmList([]byte(`{"name": "lib"}`))
+	require.NoError(t, err)
+	assert.Empty(t, pkgs)
+
+	_, err = parseNpmList([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestNpmInstaller(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := `#!/bin/sh
+echo "$*" >> ` + calls + `
+[ "$1" = "ls" ] && echo '` + sampleNpmList + `'
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "npm"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	inst := NewNpmInstaller()
+	assert.True(t, inst.IsAvailable())
+
+	pkgs, err := inst.List()
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 3)
+
+	prettier := brewfile.NewPackage(brewfile.TypeNpm, "prettier")
+	require.NoError(t, inst.Install(prettier))
+	require.NoError(t, inst.Uninstall(prettier))
+
+	data, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Equal(t, "ls -g --depth=0 --json\ni -g prettier\nuninstall -g prettier\n", string(data))
+}
+
+func TestNpmInstaller_ListWithProblems(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	writeNpm := func(stdout string) {
+		script := "#!/bin/sh\necho '" + stdout + "'\necho 'npm ERR! peer dep missing' >&2\nexit 1\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "npm"), []byte(script), 0755))
+	}
+
+	// An unmet peer dependency fails the command but the list is complete
+	writeNpm(sampleNpmList)
+	pkgs, err := NewNpmInstaller().List()
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 3)
+
+	// Without a list the failure stands
+	writeNpm("")
+	_, err = NewNpmInstaller().List()
+	assert.ErrorContains(t, err, "peer dep missing")
+}
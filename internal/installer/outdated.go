@@ -0,0 +1,80 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// outdatedInfo holds the fields formulae and casks share in
+// 'brew outdated --json=v2' (only formulae can be pinned)
+type outdatedInfo struct {
+	Name              string   `json:"name"`
+	InstalledVersions []string `json:"installed_versions"`
+	CurrentVersion    string   `json:"current_version"`
+	Pinned            bool     `json:"pinned"`
+}
+
+// ParseOutdated reads 'brew outdated --json=v2' output. When several versions
+// are installed, the newest (last) one is reported. Pinned formulae are left
+// out, as 'brew upgrade' doesn't upgrade them.
+func ParseOutdated(data []byte) ([]brewfile.Outdated, error) {
+	var info struct {
+		Formulae []outdatedInfo `json:"formulae"`
+		Casks    []outdatedInfo `json:"casks"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew outdated output: %w", err)
+	}
+
+	var result []brewfile.Outdated
+	add := func(t brewfile.PackageType, entries []outdatedInfo) {
+		for _, e := range entries {
+			if e.Name == "" || e.Pinned {
+				continue
+			}
+			installed := ""
+			if n := len(e.InstalledVersions); n > 0 {
+				installed = e.InstalledVersions[n-1]
+			}
+			result = append(result, brewfile.Outdated{
+				Package:   brewfile.NewPackage(t, e.Name),
+				Installed: installed,
+				Latest:    e.CurrentVersion,
+			})
+		}
+	}
+	add(brewfile.TypeBrew, info.Formulae)
+	add(brewfile.TypeCask, info.Casks)
+	return result, nil
+}
+
+// Outdated returns the installed formulae and casks that have a newer version
+// available, from 'brew outdated --json=v2'. It uses Homebrew's local index
+// and doesn't update it.
+func (b *BrewInstaller) Outdated() ([]brewfile.Outdated, error) {
+	output, err := b.runner.RunIn("", []string{"HOMEBREW_NO_AUTO_UPDATE=1"}, "brew", "outdated", "--json=v2")
+	if err != nil {
+		return nil, fmt.Errorf("brew outdated failed: %w", err)
+	}
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+	return ParseOutdated([]byte(output))
+}
+
+// MarkOutdated sets diff.Outdated from 'brew outdated' when Homebrew is
+// installed and the diff has formulae or casks in common
+func (b *BrewInstaller) MarkOutdated(diff *brewfile.DiffResult) error {
+	if len(diff.Common.Filter(brewfile.TypeBrew, brewfile.TypeCask)) == 0 || !b.IsAvailable() {
+		return nil
+	}
+	outdated, err := b.Outdated()
+	if err != nil {
+		return err
+	}
+	diff.MarkOutdated(outdated)
+	return nil
+}
@@ -0,0 +1,54 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const sampleOutdated = `{
+  "formulae": [
+    {"name": "git", "installed_versions": ["2.40.0"], "current_version": "2.41.0", "pinned": false, "pinned_version": null},
+    {"name": "python@3.12", "installed_versions": ["3.12.1", "3.12.2"], "current_version": "3.12.3", "pinned": false, "pinned_version": null},
+    {"name": "node@18", "installed_versions": ["18.19.0"], "current_version": "18.20.0", "pinned": true, "pinned_version": "18.19.0"}
+  ],
+  "casks": [
+    {"name": "firefox", "installed_versions": ["120.0"], "current_version": "121.0"}
+  ]
+}`
+
+func TestParseOutdated(t *testing.T) {
+	outdated, err := ParseOutdated([]byte(sampleOutdated))
+	require.NoError(t, err)
+
+	assert.Equal(t, []brewfile.Outdated{
+		{Package: brewfile.NewPackage(brewfile.TypeBrew, "git"), Installed: "2.40.0", Latest: "2.41.0"},
+		{Package: brewfile.NewPackage(brewfile.TypeBrew, "python@3.12"), Installed: "3.12.2", Latest: "3.12.3"},
+		{Package: brewfile.NewPackage(brewfile.TypeCask, "firefox"), Installed: "120.0", Latest: "121.0"},
+	}, outdated)
+
+	_, err = ParseOutdated([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestBrewInstaller_MarkOutdated(t *testing.T) {
+	stubBrew(t, `[ "$1" = outdated ] && echo '`+sampleOutdated+`'`)
+
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "node@18"),
+	}
+	diff := brewfile.Diff(pkgs, pkgs)
+	require.NoError(t, NewBrewInstaller().MarkOutdated(diff))
+	assert.Equal(t, []brewfile.Outdated{
+		{Package: brewfile.NewPackage(brewfile.TypeBrew, "git"), Installed: "2.40.0", Latest: "2.41.0"},
+	}, diff.Outdated)
+
+	// Nothing in common with Homebrew: brew isn't asked
+	stubBrew(t, `exit 1`)
+	diff = brewfile.Diff(brewfile.Packages{brewfile.NewPackage(brewfile.TypeGo, "golang.org/x/tools/gopls")}, nil)
+	assert.NoError(t, NewBrewInstaller().MarkOutdated(diff))
+}
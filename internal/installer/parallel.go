@@ -0,0 +1,65 @@
+package installer
+
+import (
+	"sync"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// serialTypes are installed one at a time even when installing in parallel:
+// brew's cask installs and mas don't cope with concurrent runs
+var serialTypes = map[brewfile.PackageType]bool{
+	brewfile.TypeCask: true,
+	brewfile.TypeMas:  true,
+}
+
+// InstallConcurrently calls install for each package with up to n running at
+// once. Taps go first, one at a time, since formulae and casks may come from
+// them. Casks and Mac App Store apps then run one after another in a single
+// lane, alongside the other packages. Within each group the order of pkgs is
+// kept. It returns once every install has returned.
+func InstallConcurrently(pkgs brewfile.Packages, n int, install func(pkg brewfile.Package)) {
+	var taps, serial, rest brewfile.Packages
+	for _, pkg := range pkgs {
+		switch {
+		case pkg.Type == brewfile.TypeTap:
+			taps = append(taps, pkg)
+		case serialTypes[pkg.Type]:
+			serial = append(serial, pkg)
+		default:
+			rest = append(rest, pkg)
+		}
+	}
+
+	for _, pkg := range taps {
+		install(pkg)
+	}
+
+	// Each job is run by one worker: a package of its own, or the serial lane
+	var jobs []brewfile.Packages
+	if len(serial) > 0 {
+		jobs = append(jobs, serial)
+	}
+	for _, pkg := range rest {
+		jobs = append(jobs, brewfile.Packages{pkg})
+	}
+
+	queue := make(chan brewfile.Packages)
+	var wg sync.WaitGroup
+	for range min(max(n, 1), len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				for _, pkg := range job {
+					install(pkg)
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+}
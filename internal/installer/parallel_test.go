@@ -0,0 +1,90 @@
+package installer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+func TestInstallConcurrently(t *testing.T) {
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeTap, "user/tap"),
+		brewfile.NewPackage(brewfile.TypeBrew, "ripgrep"),
+		brewfile.NewPackage(brewfile.TypeMas, "Xcode"),
+		brewfile.NewPackage(brewfile.TypeCask, "iterm2"),
+		brewfile.NewPackage(brewfile.TypeNpm, "typescript"),
+		brewfile.NewPackage(brewfile.TypeTap, "other/tap"),
+	}
+
+	var mu sync.Mutex
+	var order []string
+	running := map[brewfile.PackageType]int{}
+	maxRunning, maxSerial, total := 0, 0, 0
+	tapsDone := 0
+
+	InstallConcurrently(pkgs, 3, func(pkg brewfile.Package) {
+		mu.Lock()
+		if pkg.Type != brewfile.TypeTap {
+			assert.Equal(t, 2, tapsDone, "taps install before %s", pkg.ID())
+		}
+		running[pkg.Type]++
+		total++
+		maxRunning = max(maxRunning, total)
+		maxSerial = max(maxSerial, running[brewfile.TypeCask]+running[brewfile.TypeMas])
+		order = append(order, pkg.ID())
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running[pkg.Type]--
+		total--
+		if pkg.Type == brewfile.TypeTap {
+			tapsDone++
+		}
+		mu.Unlock()
+	})
+
+	assert.Len(t, order, len(pkgs))
+	assert.Equal(t, []string{"tap:user/tap", "tap:other/tap"}, order[:2])
+	assert.LessOrEqual(t, maxRunning, 3)
+	assert.Greater(t, maxRunning, 1, "installs overlap")
+	assert.Equal(t, 1, maxSerial, "casks and mas apps one at a time")
+
+	// The serial lane keeps its order
+	var serial []string
+	for _, id := range order {
+		if id == "cask:firefox" || id == "mas:Xcode" || id == "cask:iterm2" {
+			serial = append(serial, id)
+		}
+	}
+	assert.Equal(t, []string{"cask:firefox", "mas:Xcode", "cask:iterm2"}, serial)
+}
+
+func TestManager_InstallManyParallel(t *testing.T) {
+	stubBrew(t, "exit 0")
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeBrew, "fd"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+	}
+
+	mgr := NewManager()
+	mgr.SetParallel(4)
+	var counts []int
+	seen := map[string]bool{}
+	assert.NoError(t, mgr.InstallMany(pkgs, func(pkg brewfile.Package, i, total int, err error) {
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		counts = append(counts, i)
+		seen[pkg.ID()] = true
+	}))
+	assert.Equal(t, []int{1, 2, 3}, counts, "numbered in the order they finish")
+	assert.Len(t, seen, 3)
+}
@@ -0,0 +1,72 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// PipxInstaller handles Python apps installed with pipx
+type PipxInstaller struct {
+	runner *exec.Runner
+}
+
+// NewPipxInstaller creates a new pipx installer
+func NewPipxInstaller() *PipxInstaller {
+	return &PipxInstaller{
+		runner: exec.Default,
+	}
+}
+
+// List returns the apps installed with pipx
+func (p *PipxInstaller) List() (brewfile.Packages, error) {
+	output, err := p.runner.Run("pipx", "list", "--json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePipxList([]byte(output))
+}
+
+// parsePipxList parses the output of 'pipx list --json'. Apps are recorded by
+// venv name, which is what 'pipx uninstall' takes; it is the package name
+// unless the app was installed with --suffix.
+func parsePipxList(data []byte) (brewfile.Packages, error) {
+	var list struct {
+		Venvs map[string]json.RawMessage `json:"venvs"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pipx list output: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Venvs))
+	for venv := range list.Venvs {
+		names = append(names, venv)
+	}
+	sort.Strings(names)
+
+	packages := make(brewfile.Packages, 0, len(names))
+	for _, name := range names {
+		packages = append(packages, brewfile.NewPackage(brewfile.TypePipx, name))
+	}
+	return packages, nil
+}
+
+// Install installs an app with pipx
+func (p *PipxInstaller) Install(pkg brewfile.Package) error {
+	_, err := p.runner.Run("pipx", "install", pkg.Name)
+	return err
+}
+
+// Uninstall removes an app installed with pipx
+func (p *PipxInstaller) Uninstall(pkg brewfile.Package) error {
+	_, err := p.runner.Run("pipx", "uninstall", pkg.Name)
+	return err
+}
+
+// IsAvailable checks if pipx is available
+func (p *PipxInstaller) IsAvailable() bool {
+	return p.runner.Exists("pipx")
+}
@@ -0,0 +1,68 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+const samplePipxList = `{
+  "pipx_spec_version": "0.1",
+  "venvs": {
+    "black": {"metadata": {"main_package": {"package": "black", "package_version": "24.1.0"}}},
+    "poetry": {"metadata": {"main_package": {"package": "poetry", "package_version": "1.7.1"}}},
+    "httpie@3": {"metadata": {"main_package": {"package": "httpie", "package_version": "3.2.2", "suffix": "@3"}}}
+  }
+}`
+
+func TestParsePipxList(t *testing.T) {
+	pkgs, err := parsePipxList([]byte(samplePipxList))
+	require.NoError(t, err)
+	// The suffixed venv is what 'pipx uninstall' needs
+	assert.Equal(t, []string{"pipx:black", "pipx:httpie@3", "pipx:poetry"}, pkgs.IDs())
+
+	// Nothing installed
+	pkgs, err = parsePipxList([]byte(`{"pipx_spec_version": "0.1", "venvs": {}}`))
+	require.NoError(t, err)
+	assert.Empty(t, pkgs)
+
+	_, err = parsePipxList([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestPipxInstaller(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := `#!/bin/sh
+echo "$*" >> ` + calls + `
+[ "$1" = "list" ] && echo '` + samplePipxList + `'
+exit 0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pipx"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	inst := NewPipxInstaller()
+	assert.True(t, inst.IsAvailable())
+
+	pkgs, err := inst.List()
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 3)
+
+	black := brewfile.NewPackage(brewfile.TypePipx, "black")
+	require.NoError(t, inst.Install(black))
+	require.NoError(t, inst.Uninstall(black))
+
+	data, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Equal(t, "list --json\ninstall black\nuninstall black\n", string(data))
+}
+
+func TestPipxInstaller_NotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	assert.False(t, NewPipxInstaller().IsAvailable())
+}
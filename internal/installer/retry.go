@@ -0,0 +1,74 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultRetryBackoff is the wait before the first retry of a failed install
+const DefaultRetryBackoff = 2 * time.Second
+
+// KegLockAttempts is how many attempts an install that found a formula locked
+// by another brew process gets, even without a retry policy. Formulae
+// installed in parallel run into this when they share a dependency; the lock
+// is released once the other install finishes.
+const KegLockAttempts = 3
+
+// RetryPolicy decides how often a failed install is tried again, so a network
+// blip during a download doesn't fail the package outright
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts in all, including the first; below 2 means no retries
+	Backoff     time.Duration // Wait before the first retry, doubled before each one after
+}
+
+// Retries returns a policy that retries a failed install up to n times with
+// the default backoff (install.retries or --retries)
+func Retries(n int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: n + 1, Backoff: DefaultRetryBackoff}
+}
+
+// delay returns the wait before retry number n (1 for the first retry)
+func (p RetryPolicy) delay(n int) time.Duration {
+	return p.Backoff << (n - 1)
+}
+
+// retryable returns true if an install that failed with err may succeed when
+// tried again. Missing permissions won't change between attempts.
+func retryable(err error) bool {
+	return !errors.Is(err, ErrSudoRequired)
+}
+
+// isKegLocked checks if an error comes from brew finding a formula locked by
+// another running brew process
+func isKegLocked(err error) bool {
+	return strings.Contains(err.Error(), "has already locked")
+}
+
+// run calls install until it succeeds, fails with an error retrying can't
+// fix, or the policy's attempts are used up. An install failing on another
+// install's lock gets at least KegLockAttempts attempts. Before each retry it
+// waits, telling onRetry how long. It returns the number of attempts made.
+func (p RetryPolicy) run(install func() error, sleep func(time.Duration), onRetry func(line string)) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := install()
+		if err == nil || !retryable(err) {
+			return attempt, err
+		}
+		policy := p
+		if isKegLocked(err) {
+			policy.MaxAttempts = max(policy.MaxAttempts, KegLockAttempts)
+			policy.Backoff = max(policy.Backoff, DefaultRetryBackoff)
+		}
+		attempts := max(policy.MaxAttempts, 1)
+		if attempt >= attempts {
+			return attempt, err
+		}
+		wait := policy.delay(attempt)
+		if onRetry != nil {
+			onRetry(fmt.Sprintf("Install failed (%v); retrying in %s (attempt %d of %d)", err, wait, attempt+1, attempts))
+		}
+		sleep(wait)
+	}
+}
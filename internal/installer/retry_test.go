@@ -0,0 +1,118 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// stubFlakyBrew makes brew install fail the first failures times it runs
+func stubFlakyBrew(t *testing.T, failures int) string {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "count")
+	stubBrew(t, fmt.Sprintf(`[ "$1" = "install" ] || exit 0
+echo x >> "%s"
+if [ "$(wc -l < "%s")" -le %d ]; then
+  echo "curl: (6) Could not resolve host: ghcr.io" >&2
+  exit 1
+fi
+`, counter, counter, failures))
+	return counter
+}
+
+func installCount(t *testing.T, counter string) int {
+	t.Helper()
+	data, err := os.ReadFile(counter)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	require.NoError(t, err)
+	return strings.Count(string(data), "\n")
+}
+
+func TestManager_InstallRetries(t *testing.T) {
+	jq := brewfile.NewPackage(brewfile.TypeBrew, "jq")
+
+	t.Run("no policy fails at once", func(t *testing.T) {
+		counter := stubFlakyBrew(t, 1)
+		mgr := NewManager()
+		require.Error(t, mgr.Install(jq))
+		assert.Equal(t, 1, installCount(t, counter))
+		assert.Equal(t, 1, mgr.Attempts(jq))
+	})
+
+	t.Run("retries with backoff until it succeeds", func(t *testing.T) {
+		counter := stubFlakyBrew(t, 2)
+		mgr := NewManager()
+		mgr.SetRetryPolicy(RetryPolicy{MaxAttempts: 4, Backoff: time.Second})
+		var waits []time.Duration
+		mgr.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+		var retryLines []string
+		err := mgr.InstallWithProgress(jq, func(line string) {
+			if strings.Contains(line, "retrying") {
+				retryLines = append(retryLines, line)
+			}
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, installCount(t, counter))
+		assert.Equal(t, 3, mgr.Attempts(jq))
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits)
+		require.Len(t, retryLines, 2)
+		assert.Contains(t, retryLines[0], "attempt 2 of 4")
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		counter := stubFlakyBrew(t, 5)
+		mgr := NewManager()
+		mgr.SetRetryPolicy(Retries(2))
+		mgr.sleep = func(time.Duration) {}
+
+		var failed error
+		require.Error(t, mgr.InstallMany(brewfile.Packages{jq}, func(pkg brewfile.Package, i, total int, err error) {
+			failed = err
+		}))
+		assert.Error(t, failed)
+		assert.Equal(t, 3, installCount(t, counter))
+		assert.Equal(t, 3, mgr.Attempts(jq))
+	})
+}
+
+func TestRetryPolicy_SkipsSudoErrors(t *testing.T) {
+	calls := 0
+	attempts, err := Retries(3).run(func() error {
+		calls++
+		return fmt.Errorf("install docker: %w", ErrSudoRequired)
+	}, func(time.Duration) { t.Fatal("unexpected wait") }, nil)
+	assert.ErrorIs(t, err, ErrSudoRequired)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestManager_InstallRetriesKegLock(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "count")
+	stubBrew(t, fmt.Sprintf(`[ "$1" = "install" ] || exit 0
+echo x >> "%s"
+if [ "$(wc -l < "%s")" -le 1 ]; then
+  echo "Error: A brew install openssl@3 process has already locked /opt/homebrew/var/homebrew/locks/ca-certificates.formula.lock." >&2
+  exit 1
+fi
+`, counter, counter))
+
+	// Without a retry policy, only the lock collision is retried
+	jq := brewfile.NewPackage(brewfile.TypeBrew, "jq")
+	mgr := NewManager()
+	var waits []time.Duration
+	mgr.sleep = func(d time.Duration) { waits = append(waits, d) }
+	require.NoError(t, mgr.Install(jq))
+	assert.Equal(t, 2, installCount(t, counter))
+	assert.Equal(t, []time.Duration{DefaultRetryBackoff}, waits)
+}
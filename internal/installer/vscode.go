@@ -1,8 +1,6 @@
 package installer
 
 import (
-	"strings"
-
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/exec"
 )
@@ -23,24 +21,12 @@ func NewVSCodeInstaller() *VSCodeInstaller {
 
 // List returns all installed VSCode extensions
 func (v *VSCodeInstaller) List() (brewfile.Packages, error) {
-	lines, err := v.runner.RunLines(v.command, "--list-extensions")
-	if err != nil {
-		return nil, err
-	}
-
-	var packages brewfile.Packages
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			packages = append(packages, brewfile.NewPackage(brewfile.TypeVSCode, line))
-		}
-	}
-	return packages, nil
+	return listExtensions(v.runner, v.command, brewfile.TypeVSCode)
 }
 
-// Install installs a VSCode extension
+// Install installs a VSCode extension, at its pinned version if it has one
 func (v *VSCodeInstaller) Install(pkg brewfile.Package) error {
-	_, err := v.runner.Run(v.command, "--install-extension", pkg.Name)
+	_, err := v.runner.Run(v.command, "--install-extension", pkg.VersionedName())
 	return err
 }
 
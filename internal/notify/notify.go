@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// Title is the notification title used for all brewsync notifications
+const Title = "brewsync"
+
+// Notifier rings the terminal bell and shows a desktop notification when
+// a long operation finishes. Notifiers that aren't installed are skipped.
+type Notifier struct {
+	runner *exec.Runner
+	bell   io.Writer
+}
+
+// NewNotifier creates a notifier that rings the bell on stderr
+func NewNotifier() *Notifier {
+	return &Notifier{
+		runner: exec.Default,
+		bell:   os.Stderr,
+	}
+}
+
+// Summary formats the notification text for a finished operation
+// (e.g. "Sync complete: 5 succeeded" or "Sync finished: 5 succeeded, 1 failed")
+func Summary(operation string, succeeded, failed int) string {
+	if failed > 0 {
+		return fmt.Sprintf("%s finished: %d succeeded, %d failed", operation, succeeded, failed)
+	}
+	return fmt.Sprintf("%s complete: %d succeeded", operation, succeeded)
+}
+
+// Notify rings the terminal bell and sends a desktop notification using
+// terminal-notifier or osascript (macOS), whichever is available first.
+// Returns an error only if a notifier was found but failed.
+func (n *Notifier) Notify(message string) error {
+	if n.bell != nil {
+		fmt.Fprint(n.bell, "\a")
+	}
+
+	switch {
+	case n.runner.Exists("terminal-notifier"):
+		_, err := n.runner.Run("terminal-notifier", "-title", Title, "-message", message)
+		return err
+	case n.runner.Exists("osascript"):
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(Title))
+		_, err := n.runner.Run("osascript", "-e", script)
+		return err
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/exec"
+)
+
+// stubCommand puts a fake command that records its arguments in dir, and returns the log path
+func stubCommand(t *testing.T, dir, name string) string {
+	t.Helper()
+	log := filepath.Join(dir, name+".log")
+	script := "#!/bin/sh\nfor a in \"$@\"; do echo \"$a\" >> " + log + "; done\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+	return log
+}
+
+func newTestNotifier(t *testing.T, dir string) (*Notifier, *bytes.Buffer) {
+	t.Helper()
+	// Only the stubs are visible, so real notifiers never fire during tests
+	t.Setenv("PATH", dir)
+	var bell bytes.Buffer
+	return &Notifier{runner: exec.NewRunner(), bell: &bell}, &bell
+}
+
+func TestSummary(t *testing.T) {
+	assert.Equal(t, "Sync complete: 5 succeeded", Summary("Sync", 5, 0))
+	assert.Equal(t, "Import finished: 3 succeeded, 2 failed", Summary("Import", 3, 2))
+}
+
+func TestNotify_TerminalNotifier(t *testing.T) {
+	dir := t.TempDir()
+	log := stubCommand(t, dir, "terminal-notifier")
+	stubCommand(t, dir, "osascript")
+	n, bell := newTestNotifier(t, dir)
+
+	require.NoError(t, n.Notify(Summary("Sync", 5, 1)))
+
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "-title\nbrewsync\n-message\nSync finished: 5 succeeded, 1 failed\n", string(data))
+	assert.Equal(t, "\a", bell.String())
+}
+
+func TestNotify_OsascriptFallback(t *testing.T) {
+	dir := t.TempDir()
+	log := stubCommand(t, dir, "osascript")
+	n, _ := newTestNotifier(t, dir)
+
+	require.NoError(t, n.Notify(`Dump complete: 42 "packages"`))
+
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "-e\ndisplay notification \"Dump complete: 42 \\\"packages\\\"\" with title \"brewsync\"\n", string(data))
+}
+
+func TestNotify_NoNotifier(t *testing.T) {
+	n, bell := newTestNotifier(t, t.TempDir())
+
+	assert.NoError(t, n.Notify("Sync complete: 1 succeeded"))
+	assert.Equal(t, "\a", bell.String())
+}
@@ -27,6 +27,8 @@ type Packages struct {
 	VSCode []string `yaml:"vscode,omitempty"`
 	Cursor []string `yaml:"cursor,omitempty"`
 	Go     []string `yaml:"go,omitempty"`
+	Npm    []string `yaml:"npm,omitempty"`
+	Pipx   []string `yaml:"pipx,omitempty"`
 	Mas    []string `yaml:"mas,omitempty"`
 }
 
@@ -52,6 +54,12 @@ func (p *Packages) ToBrewfilePackages() brewfile.Packages {
 	for _, name := range p.Go {
 		result = append(result, brewfile.NewPackage(brewfile.TypeGo, name))
 	}
+	for _, name := range p.Npm {
+		result = append(result, brewfile.NewPackage(brewfile.TypeNpm, name))
+	}
+	for _, name := range p.Pipx {
+		result = append(result, brewfile.NewPackage(brewfile.TypePipx, name))
+	}
 	for _, name := range p.Mas {
 		result = append(result, brewfile.NewPackage(brewfile.TypeMas, name))
 	}
@@ -62,7 +70,7 @@ func (p *Packages) ToBrewfilePackages() brewfile.Packages {
 // Count returns the total number of packages
 func (p *Packages) Count() int {
 	return len(p.Tap) + len(p.Brew) + len(p.Cask) +
-		len(p.VSCode) + len(p.Cursor) + len(p.Go) + len(p.Mas)
+		len(p.VSCode) + len(p.Cursor) + len(p.Go) + len(p.Npm) + len(p.Pipx) + len(p.Mas)
 }
 
 // Load loads a profile by name
@@ -260,6 +268,10 @@ func FromBrewfilePackages(packages brewfile.Packages) Packages {
 			p.Cursor = append(p.Cursor, pkg.Name)
 		case brewfile.TypeGo:
 			p.Go = append(p.Go, pkg.Name)
+		case brewfile.TypeNpm:
+			p.Npm = append(p.Npm, pkg.Name)
+		case brewfile.TypePipx:
+			p.Pipx = append(p.Pipx, pkg.Name)
 		case brewfile.TypeMas:
 			p.Mas = append(p.Mas, pkg.Name)
 		}
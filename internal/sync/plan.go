@@ -0,0 +1,255 @@
+// Package sync computes what a sync from another machine would change on the
+// current one. It holds no UI code so the CLI and TUI share the same rules.
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// Options controls how a plan is computed
+type Options struct {
+	// Categories limits the plan to these package types; empty means all types
+	Categories []brewfile.PackageType
+	// Machine is the machine the plan is for: its ignores, machine-specific
+	// packages and pins apply. Empty means the current machine.
+	Machine string
+}
+
+// Plan is the set of changes needed to make the current machine match a source
+type Plan struct {
+	Source          string `json:"source"`
+	Machine         string `json:"machine"`
+	SourceBrewfile  string `json:"source_brewfile,omitempty"`
+	CurrentBrewfile string `json:"current_brewfile,omitempty"`
+
+	// Additions are packages the source has and the current machine lacks
+	Additions brewfile.Packages `json:"additions,omitempty"`
+	// Removals are packages the current machine has and the source lacks
+	Removals brewfile.Packages `json:"removals,omitempty"`
+	// Protected are removals kept because they are machine-specific or pinned
+	Protected brewfile.Packages `json:"protected,omitempty"`
+	// IgnoredAdditions and IgnoredRemovals are changes skipped because the
+	// package or its category is ignored on the current machine
+	IgnoredAdditions brewfile.Packages `json:"ignored_additions,omitempty"`
+	IgnoredRemovals  brewfile.Packages `json:"ignored_removals,omitempty"`
+	// Modifications are packages on both machines whose Brewfile options differ;
+	// the source's entry is listed
+	Modifications brewfile.Packages `json:"modifications,omitempty"`
+	// OtherMachineSpecific are additions left out because they are specific to
+	// another machine (only set by NewWhatIfPlan)
+	OtherMachineSpecific brewfile.Packages `json:"other_machine_specific,omitempty"`
+}
+
+// IsEmpty returns true if there is nothing to install or remove
+func (p *Plan) IsEmpty() bool {
+	return len(p.Additions) == 0 && len(p.Removals) == 0
+}
+
+// Ignored returns all ignored changes, additions first
+func (p *Plan) Ignored() brewfile.Packages {
+	var all brewfile.Packages
+	all = append(all, p.IgnoredAdditions...)
+	all = append(all, p.IgnoredRemovals...)
+	return all
+}
+
+// NewPlan loads both machines' Brewfiles and computes the plan for syncing the
+// current machine from source. A missing current Brewfile counts as empty.
+func NewPlan(cfg *config.Config, source string, opts Options) (*Plan, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no config loaded")
+	}
+
+	current := cfg.CurrentMachine
+	if current == "" {
+		return nil, fmt.Errorf("could not detect current machine; run 'brewsync config init' first")
+	}
+	if source == current {
+		return nil, fmt.Errorf("cannot sync from current machine '%s'", source)
+	}
+
+	currentMachine, ok := cfg.GetMachine(current)
+	if !ok {
+		return nil, fmt.Errorf("current machine '%s' not found in config", current)
+	}
+	sourceMachine, ok := cfg.GetMachine(source)
+	if !ok {
+		return nil, fmt.Errorf("unknown source machine: %s", source)
+	}
+
+	currentPkgs, err := brewfile.Parse(currentMachine.Brewfile)
+	if err != nil {
+		currentPkgs = brewfile.Packages{}
+	}
+
+	sourcePkgs, err := brewfile.Parse(sourceMachine.Brewfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source Brewfile: %w", err)
+	}
+
+	plan := Compute(cfg, sourcePkgs, currentPkgs, opts)
+	plan.Source = source
+	plan.SourceBrewfile = sourceMachine.Brewfile
+	plan.CurrentBrewfile = currentMachine.Brewfile
+	return plan, nil
+}
+
+// NewWhatIfPlan computes the plan for setting up machine from the union of
+// the sources' Brewfiles, without it being the current machine. The machine
+// starts from target when given, else from its configured Brewfile if it has
+// one, else empty; a missing file also counts as empty. machine need not be
+// in the config, in which case only global ignores apply. Additions specific
+// to another machine are left out, as import does.
+func NewWhatIfPlan(cfg *config.Config, machine string, sources []string, target string, opts Options) (*Plan, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no config loaded")
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no source machines given")
+	}
+
+	var sourcePkgs brewfile.Packages
+	for _, source := range sources {
+		if source == machine {
+			return nil, fmt.Errorf("cannot plan machine '%s' from itself", source)
+		}
+		sourceMachine, ok := cfg.GetMachine(source)
+		if !ok {
+			return nil, fmt.Errorf("unknown source machine: %s", source)
+		}
+		pkgs, err := brewfile.Parse(sourceMachine.Brewfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s's Brewfile: %w", source, err)
+		}
+		sourcePkgs = sourcePkgs.AddUnique(pkgs...)
+	}
+
+	if target == "" {
+		if m, ok := cfg.GetMachine(machine); ok {
+			target = m.Brewfile
+		}
+	}
+	currentPkgs := brewfile.Packages{}
+	if target != "" {
+		pkgs, err := brewfile.Parse(target)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to parse target Brewfile: %w", err)
+		}
+		if err == nil {
+			currentPkgs = pkgs
+		}
+	}
+
+	opts.Machine = machine
+	plan := Compute(cfg, sourcePkgs, currentPkgs, opts)
+	plan.Source = strings.Join(sources, ",")
+	plan.CurrentBrewfile = target
+
+	otherSpecific := make(map[string]bool)
+	for other, ids := range cfg.GetMachineSpecificPackages() {
+		if other == machine {
+			continue
+		}
+		for _, id := range ids {
+			otherSpecific[id] = true
+		}
+	}
+	var additions brewfile.Packages
+	for _, pkg := range plan.Additions {
+		if otherSpecific[pkg.ID()] {
+			plan.OtherMachineSpecific = append(plan.OtherMachineSpecific, pkg)
+		} else {
+			additions = append(additions, pkg)
+		}
+	}
+	plan.Additions = additions
+
+	return plan, nil
+}
+
+// Compute builds a plan from already-parsed package lists, applying the current
+// machine's ignores, machine-specific packages and pins from cfg.
+//
+// Protection takes precedence over ignores for removals: a pinned package that is
+// also ignored is reported as protected.
+func Compute(cfg *config.Config, source, current brewfile.Packages, opts Options) *Plan {
+	machine := opts.Machine
+	if machine == "" {
+		machine = cfg.CurrentMachine
+	}
+
+	if len(opts.Categories) > 0 {
+		source = source.Filter(opts.Categories...)
+		current = current.Filter(opts.Categories...)
+	}
+
+	diff := brewfile.DiffWithAliases(source, current, cfg.ExtensionAliases)
+	plan := &Plan{
+		Machine:       machine,
+		Modifications: modifications(source, current, cfg.ExtensionAliases),
+	}
+
+	isIgnored := func(pkg brewfile.Package) bool {
+		return cfg.IsCategoryIgnored(machine, string(pkg.Type)) || cfg.IsPackageIgnored(machine, pkg.ID())
+	}
+
+	for _, pkg := range diff.Additions {
+		if isIgnored(pkg) {
+			plan.IgnoredAdditions = append(plan.IgnoredAdditions, pkg)
+		} else {
+			plan.Additions = append(plan.Additions, pkg)
+		}
+	}
+
+	protected := cfg.PinnedSet()
+	for _, id := range cfg.GetMachineSpecificPackages()[machine] {
+		protected[id] = true
+	}
+
+	// A tap whose URL changed is both added and removed; re-tapping it
+	// replaces the old clone, and untapping afterwards would lose it
+	added := make(map[string]bool, len(diff.Additions))
+	for _, pkg := range diff.Additions {
+		added[pkg.ID()] = true
+	}
+
+	for _, pkg := range diff.Removals {
+		if added[pkg.ID()] {
+			continue
+		}
+		switch {
+		case protected[pkg.ID()]:
+			plan.Protected = append(plan.Protected, pkg)
+		case isIgnored(pkg):
+			plan.IgnoredRemovals = append(plan.IgnoredRemovals, pkg)
+		default:
+			plan.Removals = append(plan.Removals, pkg)
+		}
+	}
+
+	return plan
+}
+
+// modifications returns source packages present on both sides with different options
+func modifications(source, current brewfile.Packages, aliases map[string]string) brewfile.Packages {
+	currentByKey := make(map[string]brewfile.Package, len(current))
+	for _, pkg := range current {
+		currentByKey[pkg.Key(aliases)] = pkg
+	}
+
+	var result brewfile.Packages
+	for _, pkg := range source {
+		other, ok := currentByKey[pkg.Key(aliases)]
+		if ok && !maps.Equal(pkg.Options, other.Options) {
+			result = append(result, pkg)
+		}
+	}
+	return result
+}
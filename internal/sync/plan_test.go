@@ -0,0 +1,268 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// loadConfig writes config.yaml (and ignore.yaml next to it, if given) and loads it
+func loadConfig(t *testing.T, dir, configYAML, ignoreYAML string) *config.Config {
+	t.Helper()
+
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configYAML), 0644))
+	if ignoreYAML != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "ignore.yaml"), []byte(ignoreYAML), 0644))
+	}
+
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	return cfg
+}
+
+func writeBrewfile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func testConfig(t *testing.T, ignoreYAML string) (*config.Config, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := loadConfig(t, dir, `
+machines:
+  mini:
+    hostname: mini
+    brewfile: `+filepath.Join(dir, "Brewfile.mini")+`
+  air:
+    hostname: air
+    brewfile: `+filepath.Join(dir, "Brewfile.air")+`
+current_machine: mini
+pinned:
+  - brew:node@18
+machine_specific:
+  mini:
+    cask:
+      - docker
+`, ignoreYAML)
+	return cfg, dir
+}
+
+func TestNewPlan(t *testing.T) {
+	cfg, dir := testConfig(t, `
+global:
+  categories: [mas]
+machines:
+  mini:
+    packages:
+      brew: [htop, wget]
+`)
+
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+brew "ripgrep"
+brew "htop"
+brew "postgresql@16", restart_service: true
+mas "Xcode", id: 497799835
+cask "firefox"
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.mini"), `brew "git"
+brew "bat"
+brew "wget"
+brew "node@18"
+brew "postgresql@16"
+cask "docker"
+mas "Keynote", id: 409183694
+`)
+
+	plan, err := NewPlan(cfg, "air", Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "air", plan.Source)
+	assert.Equal(t, "mini", plan.Machine)
+	assert.Equal(t, filepath.Join(dir, "Brewfile.air"), plan.SourceBrewfile)
+	assert.Equal(t, filepath.Join(dir, "Brewfile.mini"), plan.CurrentBrewfile)
+
+	assert.Equal(t, []string{"ripgrep", "firefox"}, plan.Additions.Names())
+	assert.Equal(t, []string{"bat"}, plan.Removals.Names())
+	assert.Equal(t, []string{"node@18", "docker"}, plan.Protected.Names())
+	assert.Equal(t, []string{"htop", "Xcode"}, plan.IgnoredAdditions.Names())
+	assert.Equal(t, []string{"wget", "Keynote"}, plan.IgnoredRemovals.Names())
+	assert.Equal(t, []string{"htop", "Xcode", "wget", "Keynote"}, plan.Ignored().Names())
+	assert.Equal(t, []string{"postgresql@16"}, plan.Modifications.Names())
+	assert.False(t, plan.IsEmpty())
+}
+
+func TestNewPlan_Categories(t *testing.T) {
+	cfg, dir := testConfig(t, "")
+
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+cask "firefox"
+vscode "golang.go"
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.mini"), `brew "bat"
+vscode "eamodio.gitlens"
+`)
+
+	plan, err := NewPlan(cfg, "air", Options{Categories: []brewfile.PackageType{brewfile.TypeVSCode}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"golang.go"}, plan.Additions.Names())
+	assert.Equal(t, []string{"eamodio.gitlens"}, plan.Removals.Names())
+}
+
+func TestNewPlan_MissingCurrentBrewfile(t *testing.T) {
+	cfg, dir := testConfig(t, "")
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+`)
+
+	plan, err := NewPlan(cfg, "air", Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"git"}, plan.Additions.Names())
+	assert.Empty(t, plan.Removals)
+}
+
+func TestNewPlan_Errors(t *testing.T) {
+	cfg, _ := testConfig(t, "")
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"self", "mini", "cannot sync from current machine"},
+		{"unknown source", "studio", "unknown source machine"},
+		{"missing source Brewfile", "air", "failed to parse source Brewfile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPlan(cfg, tt.source, Options{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := NewPlan(nil, "air", Options{})
+	assert.Error(t, err)
+}
+
+func TestCompute_ProtectionBeatsIgnore(t *testing.T) {
+	cfg, _ := testConfig(t, `
+global:
+  packages:
+    brew: [node@18]
+`)
+
+	plan := Compute(cfg, nil, brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "node@18")}, Options{})
+
+	assert.Equal(t, []string{"node@18"}, plan.Protected.Names())
+	assert.Empty(t, plan.IgnoredRemovals)
+	assert.True(t, plan.IsEmpty())
+}
+
+func TestCompute_Modifications(t *testing.T) {
+	cfg, _ := testConfig(t, "")
+
+	source := brewfile.Packages{
+		{Type: brewfile.TypeBrew, Name: "postgresql@16", Options: map[string]string{"restart_service": "true"}},
+		{Type: brewfile.TypeBrew, Name: "git"},
+		{Type: brewfile.TypeVSCode, Name: "golang.go"},
+	}
+	current := brewfile.Packages{
+		{Type: brewfile.TypeBrew, Name: "postgresql@16"},
+		{Type: brewfile.TypeBrew, Name: "git", Options: map[string]string{}},
+		{Type: brewfile.TypeVSCode, Name: "ms-vscode.go"},
+	}
+	cfg.ExtensionAliases = map[string]string{"ms-vscode.go": "golang.go"}
+
+	plan := Compute(cfg, source, current, Options{})
+
+	assert.Equal(t, []string{"postgresql@16"}, plan.Modifications.Names())
+	assert.True(t, plan.IsEmpty())
+}
+
+func TestCompute_TapURLChanged(t *testing.T) {
+	cfg, _ := testConfig(t, "")
+
+	source := brewfile.Packages{
+		{Type: brewfile.TypeTap, Name: "company/private", URL: "git@github.com:company/homebrew-private.git"},
+	}
+	current := brewfile.Packages{
+		{Type: brewfile.TypeTap, Name: "company/private"},
+	}
+
+	plan := Compute(cfg, source, current, Options{})
+
+	assert.Equal(t, []string{"tap:company/private"}, plan.Additions.IDs())
+	assert.Empty(t, plan.Removals)
+}
+
+func TestNewWhatIfPlan_EmptyTarget(t *testing.T) {
+	cfg, dir := testConfig(t, `
+global:
+  categories: [mas]
+  packages:
+    brew: [htop]
+`)
+
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+brew "htop"
+cask "firefox"
+mas "Xcode", id: 497799835
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.mini"), `brew "git"
+brew "bat"
+cask "docker"
+`)
+
+	// studio isn't configured yet: everything from both sources is planned
+	plan, err := NewWhatIfPlan(cfg, "studio", []string{"mini", "air"}, "", Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "studio", plan.Machine)
+	assert.Equal(t, "mini,air", plan.Source)
+	assert.Equal(t, []string{"git", "bat", "firefox"}, plan.Additions.Names())
+	assert.Equal(t, []string{"docker"}, plan.OtherMachineSpecific.Names())
+	assert.Equal(t, []string{"htop", "Xcode"}, plan.IgnoredAdditions.Names())
+	assert.Empty(t, plan.Removals)
+
+	// Category filters apply as for a regular plan
+	plan, err = NewWhatIfPlan(cfg, "studio", []string{"air"}, "", Options{Categories: []brewfile.PackageType{brewfile.TypeCask}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"firefox"}, plan.Additions.Names())
+}
+
+func TestNewWhatIfPlan_Target(t *testing.T) {
+	cfg, dir := testConfig(t, "")
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+brew "ripgrep"
+`)
+	target := filepath.Join(dir, "Brewfile.studio")
+	writeBrewfile(t, target, `brew "git"
+`)
+
+	plan, err := NewWhatIfPlan(cfg, "studio", []string{"air"}, target, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ripgrep"}, plan.Additions.Names())
+	assert.Equal(t, target, plan.CurrentBrewfile)
+
+	// A target that doesn't exist yet is empty
+	plan, err = NewWhatIfPlan(cfg, "studio", []string{"air"}, filepath.Join(dir, "missing"), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"git", "ripgrep"}, plan.Additions.Names())
+
+	_, err = NewWhatIfPlan(cfg, "studio", []string{"nope"}, "", Options{})
+	assert.ErrorContains(t, err, "unknown source machine")
+	_, err = NewWhatIfPlan(cfg, "air", []string{"air"}, "", Options{})
+	assert.ErrorContains(t, err, "from itself")
+}
@@ -0,0 +1,180 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// PlanFileVersion is the format version of plan files written by SavePlan
+const PlanFileVersion = 1
+
+// SavedPlan is a plan written to a file for review and applied later. The
+// Brewfile digests record the packages the plan was computed from.
+type SavedPlan struct {
+	Version    int                    `json:"version"`
+	CreatedAt  time.Time              `json:"created_at"`
+	Categories []brewfile.PackageType `json:"categories,omitempty"`
+	// SourceDigest and CurrentDigest are SHA-256 digests of the packages in
+	// the Brewfiles, includes resolved; empty for a Brewfile that did not exist
+	SourceDigest  string `json:"source_digest,omitempty"`
+	CurrentDigest string `json:"current_digest,omitempty"`
+	Plan
+}
+
+// SavePlan writes plan, computed with opts, to path as JSON
+func SavePlan(path string, plan *Plan, opts Options) error {
+	saved := SavedPlan{
+		Version:    PlanFileVersion,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+		Categories: opts.Categories,
+		Plan:       *plan,
+	}
+	var err error
+	if saved.SourceDigest, err = packagesDigest(plan.SourceBrewfile); err != nil {
+		return err
+	}
+	if saved.CurrentDigest, err = packagesDigest(plan.CurrentBrewfile); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	return nil
+}
+
+// LoadPlan reads a plan file written by SavePlan
+func LoadPlan(path string) (*SavedPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	var saved SavedPlan
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file %s: %w", path, err)
+	}
+	if saved.Version != PlanFileVersion {
+		return nil, fmt.Errorf("plan file %s has unsupported version %d", path, saved.Version)
+	}
+	return &saved, nil
+}
+
+// PlanCheck is the result of re-validating a saved plan against the live state
+type PlanCheck struct {
+	// Plan holds the saved additions and removals that still apply
+	Plan *Plan
+	// Done are saved changes that no longer apply, e.g. a package installed
+	// by hand since the plan was made, or one that is now ignored
+	Done brewfile.Packages
+	// Unplanned are changes the live state now needs that the saved plan lacks;
+	// they are not applied
+	Unplanned brewfile.Packages
+	// SourceChanged and CurrentChanged report Brewfiles edited since the plan was made
+	SourceChanged  bool
+	CurrentChanged bool
+}
+
+// Stale reports whether the live state has diverged from when the plan was made
+func (c *PlanCheck) Stale() bool {
+	return c.SourceChanged || c.CurrentChanged || len(c.Done) > 0 || len(c.Unplanned) > 0
+}
+
+// Check recomputes the plan from the live state and keeps only the saved
+// changes that still apply. The plan must be for cfg's current machine.
+func (s *SavedPlan) Check(cfg *config.Config) (*PlanCheck, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no config loaded")
+	}
+	if s.Machine != cfg.CurrentMachine {
+		return nil, fmt.Errorf("plan is for machine '%s', but this is '%s'", s.Machine, cfg.CurrentMachine)
+	}
+
+	live, err := NewPlan(cfg, s.Source, Options{Categories: s.Categories})
+	if err != nil {
+		return nil, err
+	}
+
+	check := &PlanCheck{Plan: live}
+	sourceDigest, err := packagesDigest(live.SourceBrewfile)
+	if err != nil {
+		return nil, err
+	}
+	currentDigest, err := packagesDigest(live.CurrentBrewfile)
+	if err != nil {
+		return nil, err
+	}
+	check.SourceChanged = sourceDigest != s.SourceDigest
+	check.CurrentChanged = currentDigest != s.CurrentDigest
+
+	var additions, removals, unplanned brewfile.Packages
+	additions, check.Done = splitByID(s.Additions, live.Additions)
+	removals, done := splitByID(s.Removals, live.Removals)
+	check.Done = append(check.Done, done...)
+	_, unplanned = splitByID(live.Additions, s.Additions)
+	check.Unplanned = unplanned
+	_, unplanned = splitByID(live.Removals, s.Removals)
+	check.Unplanned = append(check.Unplanned, unplanned...)
+
+	live.Additions = additions
+	live.Removals = removals
+	return check, nil
+}
+
+// splitByID splits pkgs into those whose ID is in other and those whose isn't
+func splitByID(pkgs, other brewfile.Packages) (in, out brewfile.Packages) {
+	ids := make(map[string]bool, len(other))
+	for _, pkg := range other {
+		ids[pkg.ID()] = true
+	}
+	for _, pkg := range pkgs {
+		if ids[pkg.ID()] {
+			in = append(in, pkg)
+		} else {
+			out = append(out, pkg)
+		}
+	}
+	return in, out
+}
+
+// packagesDigest returns the hex SHA-256 of the packages in the Brewfile at
+// path, including those of the Brewfiles it includes, or "" if it does not
+// exist. Order and descriptions don't count, so only a change to what would
+// be installed changes the digest.
+func packagesDigest(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	pkgs, err := brewfile.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	resolved := make(brewfile.Packages, len(pkgs))
+	for i, pkg := range pkgs {
+		pkg.Description = ""
+		resolved[i] = pkg
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].ID() < resolved[j].ID() })
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
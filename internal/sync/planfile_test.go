@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+func TestSavePlan_RoundTrip(t *testing.T) {
+	cfg, dir := testConfig(t, "")
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+brew "ripgrep"
+brew "libpq", link: true
+cask "firefox"
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.mini"), `brew "git"
+brew "bat"
+cask "firefox"
+`)
+
+	opts := Options{Categories: []brewfile.PackageType{brewfile.TypeBrew}}
+	plan, err := NewPlan(cfg, "air", opts)
+	require.NoError(t, err)
+
+	planFile := filepath.Join(dir, "plan.json")
+	require.NoError(t, SavePlan(planFile, plan, opts))
+
+	saved, err := LoadPlan(planFile)
+	require.NoError(t, err)
+	assert.Equal(t, PlanFileVersion, saved.Version)
+	assert.Equal(t, opts.Categories, saved.Categories)
+	assert.Equal(t, *plan, saved.Plan)
+	assert.Equal(t, "true", saved.Additions[1].Options["link"])
+
+	// Nothing changed: the plan applies as saved
+	check, err := saved.Check(cfg)
+	require.NoError(t, err)
+	assert.False(t, check.Stale())
+	assert.Equal(t, []string{"ripgrep", "libpq"}, check.Plan.Additions.Names())
+	assert.Equal(t, []string{"bat"}, check.Plan.Removals.Names())
+
+	// ripgrep was installed by hand and the source gained jq since the plan was made
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+brew "ripgrep"
+brew "libpq", link: true
+brew "jq"
+cask "firefox"
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.mini"), `brew "git"
+brew "bat"
+brew "ripgrep"
+cask "firefox"
+`)
+
+	check, err = saved.Check(cfg)
+	require.NoError(t, err)
+	assert.True(t, check.Stale())
+	assert.True(t, check.SourceChanged)
+	assert.True(t, check.CurrentChanged)
+	assert.Equal(t, []string{"libpq"}, check.Plan.Additions.Names())
+	assert.Equal(t, []string{"bat"}, check.Plan.Removals.Names())
+	assert.Equal(t, []string{"brew:ripgrep"}, check.Done.IDs())
+	assert.Equal(t, []string{"brew:jq"}, check.Unplanned.IDs())
+}
+
+func TestSavedPlan_CheckErrors(t *testing.T) {
+	cfg, dir := testConfig(t, "")
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `brew "git"
+`)
+
+	saved := &SavedPlan{Version: PlanFileVersion, Plan: Plan{Source: "air", Machine: "studio"}}
+	_, err := saved.Check(cfg)
+	assert.ErrorContains(t, err, "plan is for machine 'studio'")
+
+	planFile := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(planFile, []byte(`{"version": 99}`), 0644))
+	_, err = LoadPlan(planFile)
+	assert.ErrorContains(t, err, "unsupported version 99")
+
+	_, err = LoadPlan(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestSavedPlan_CheckIncludes(t *testing.T) {
+	cfg, dir := testConfig(t, "")
+	base := filepath.Join(dir, "Brewfile.base")
+	writeBrewfile(t, base, `brew "git"
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `# brewsync:include Brewfile.base
+brew "ripgrep"
+`)
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.mini"), `brew "git"
+brew "jq"
+`)
+
+	plan, err := NewPlan(cfg, "air", Options{})
+	require.NoError(t, err)
+	planFile := filepath.Join(dir, "plan.json")
+	require.NoError(t, SavePlan(planFile, plan, Options{}))
+	saved, err := LoadPlan(planFile)
+	require.NoError(t, err)
+
+	// Reordering and describing the packages changes nothing that would be installed
+	writeBrewfile(t, filepath.Join(dir, "Brewfile.air"), `# Fast grep
+brew "ripgrep"
+# brewsync:include Brewfile.base
+`)
+	check, err := saved.Check(cfg)
+	require.NoError(t, err)
+	assert.False(t, check.Stale())
+
+	// A change that came in through the included Brewfile is noticed, though
+	// the additions and removals stay the same
+	writeBrewfile(t, base, `brew "git", link: true
+`)
+	check, err = saved.Check(cfg)
+	require.NoError(t, err)
+	assert.True(t, check.Stale())
+	assert.True(t, check.SourceChanged)
+	assert.False(t, check.CurrentChanged)
+	assert.Empty(t, check.Done)
+	assert.Empty(t, check.Unplanned)
+}
@@ -0,0 +1,90 @@
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asamgx/brewsync/internal/tui/styles"
+)
+
+// ConfirmResultMsg is sent when the user answers a Confirm dialog
+type ConfirmResultMsg struct {
+	ID        string // ID of the dialog that was answered
+	Confirmed bool
+}
+
+// Confirm is a yes/no dialog. While open it takes every key: y confirms,
+// n and esc cancel, anything else is ignored. The answer arrives as a
+// ConfirmResultMsg.
+type Confirm struct {
+	id     string
+	prompt string
+	accent lipgloss.Color
+	open   bool
+}
+
+// NewConfirm creates a closed dialog whose answers carry id
+func NewConfirm(id string) Confirm {
+	return Confirm{id: id, accent: styles.CatYellow}
+}
+
+// Open shows the dialog with prompt (which may be pre-styled) and a border in
+// accent, e.g. styles.CatRed for destructive actions
+func (c *Confirm) Open(prompt string, accent lipgloss.Color) {
+	c.prompt = prompt
+	c.accent = accent
+	c.open = true
+}
+
+// Active returns true while the dialog is waiting for an answer
+func (c Confirm) Active() bool {
+	return c.open
+}
+
+// ID returns the ID answers from this dialog carry
+func (c Confirm) ID() string {
+	return c.id
+}
+
+// Update handles a key while the dialog is open. It closes the dialog and
+// returns a command sending the ConfirmResultMsg once the user answers.
+func (c *Confirm) Update(msg tea.KeyMsg) tea.Cmd {
+	if !c.open {
+		return nil
+	}
+
+	var confirmed bool
+	switch msg.String() {
+	case "y", "Y":
+		confirmed = true
+	case "n", "N", "esc":
+		confirmed = false
+	default:
+		return nil
+	}
+
+	c.open = false
+	id := c.id
+	return func() tea.Msg {
+		return ConfirmResultMsg{ID: id, Confirmed: confirmed}
+	}
+}
+
+// View renders the dialog, or nothing when it is closed
+func (c Confirm) View() string {
+	if !c.open {
+		return ""
+	}
+
+	promptStyle := lipgloss.NewStyle().Foreground(styles.CatYellow)
+	keyStyle := lipgloss.NewStyle().Bold(true)
+	help := promptStyle.Render("Press ") + keyStyle.Render("y") + promptStyle.Render(" to confirm, ") +
+		keyStyle.Render("n") + promptStyle.Render(" or ") + keyStyle.Render("esc") + promptStyle.Render(" to cancel")
+
+	dialogStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c.accent).
+		Padding(0, 2)
+
+	return dialogStyle.Render(c.prompt + "\n" + help)
+}
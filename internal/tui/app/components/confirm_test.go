@@ -0,0 +1,57 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/tui/styles"
+)
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestConfirm_Keys(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       tea.KeyMsg
+		confirmed bool
+	}{
+		{"y", runeKey("y"), true},
+		{"Y", runeKey("Y"), true},
+		{"n", runeKey("n"), false},
+		{"N", runeKey("N"), false},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfirm("test")
+			c.Open("Continue?", styles.CatRed)
+			require.True(t, c.Active())
+
+			cmd := c.Update(tt.key)
+			require.NotNil(t, cmd)
+			assert.False(t, c.Active())
+			assert.Equal(t, ConfirmResultMsg{ID: "test", Confirmed: tt.confirmed}, cmd())
+		})
+	}
+}
+
+func TestConfirm_IgnoresOtherKeys(t *testing.T) {
+	c := NewConfirm("test")
+
+	// Closed dialogs don't answer
+	assert.Nil(t, c.Update(runeKey("y")))
+	assert.Empty(t, c.View())
+
+	c.Open("Continue?", styles.CatRed)
+	for _, k := range []tea.KeyMsg{runeKey("j"), runeKey("q"), {Type: tea.KeyEnter}} {
+		assert.Nil(t, c.Update(k))
+		assert.True(t, c.Active())
+	}
+	assert.Contains(t, c.View(), "Continue?")
+	assert.Contains(t, c.View(), "esc")
+}
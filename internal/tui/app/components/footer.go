@@ -86,19 +86,24 @@ func (m FooterModel) View() string {
 
 	content := strings.Join(parts, styles.BorderStyle.Render("  │  "))
 
-	// Add task indicator on the right if running
+	// Add task indicator, or else the status message, on the right
+	indicator := ""
 	if m.taskRunning {
-		taskIndicator := m.renderTaskIndicator()
-		indicatorWidth := lipgloss.Width(taskIndicator)
+		indicator = m.renderTaskIndicator()
+	} else if m.statusMsg != "" {
+		indicator = m.renderStatus()
+	}
+	if indicator != "" {
+		indicatorWidth := lipgloss.Width(indicator)
 		contentWidth := lipgloss.Width(content)
 		// Calculate space: total width minus content minus indicator minus some padding
 		availableSpace := m.width - contentWidth - indicatorWidth - 6
 
 		if availableSpace > 0 {
-			content = content + strings.Repeat(" ", availableSpace) + taskIndicator
+			content = content + strings.Repeat(" ", availableSpace) + indicator
 		} else {
-			// If not enough space, put task indicator after a separator
-			content = content + styles.BorderStyle.Render("  │  ") + taskIndicator
+			// If not enough space, put the indicator after a separator
+			content = content + styles.BorderStyle.Render("  │  ") + indicator
 		}
 	}
 
@@ -121,6 +126,20 @@ func (m FooterModel) renderTaskIndicator() string {
 	return taskStyle.Render(spinner+" "+m.taskAction) + " " + pkgStyle.Render(m.taskPkg)
 }
 
+// renderStatus renders the status message in the color of its type
+func (m FooterModel) renderStatus() string {
+	switch m.statusType {
+	case "success":
+		return styles.CheckmarkStyle.Render("✓ " + m.statusMsg)
+	case "error":
+		return styles.ErrorStyle.Render("✗ " + m.statusMsg)
+	case "warning":
+		return styles.WarningStyle.Render("⚠ " + m.statusMsg)
+	default:
+		return styles.FooterStyle.Render(m.statusMsg)
+	}
+}
+
 // RenderFullFooter renders the complete footer with borders
 func (m FooterModel) RenderFullFooter() string {
 	const (
@@ -183,6 +202,7 @@ func DashboardKeybindings() []KeyBinding {
 	return []KeyBinding{
 		{Key: "1-0/!", Desc: "Screens"},
 		{Key: "H", Desc: "Toggle Ignored"},
+		{Key: "r", Desc: "Refresh"},
 		{Key: "q", Desc: "Quit"},
 	}
 }
@@ -202,7 +222,10 @@ func ListKeybindings() []KeyBinding {
 	return []KeyBinding{
 		{Key: "j/k", Desc: "Navigate"},
 		{Key: "X", Desc: "Uninstall"},
+		{Key: "p", Desc: "Pin"},
+		{Key: "z", Desc: "Collapse"},
 		{Key: "g/G", Desc: "Top/Bottom"},
+		{Key: "r", Desc: "Refresh"},
 		{Key: "Esc", Desc: "Dashboard"},
 	}
 }
@@ -222,6 +245,8 @@ func ImportKeybindings() []KeyBinding {
 func SyncKeybindings() []KeyBinding {
 	return []KeyBinding{
 		{Key: "a", Desc: "Apply"},
+		{Key: "z", Desc: "Collapse"},
+		{Key: "c", Desc: "Compact"},
 		{Key: "Esc", Desc: "Dashboard"},
 		{Key: "q", Desc: "Quit"},
 	}
@@ -234,6 +259,10 @@ func DiffKeybindings() []KeyBinding {
 		{Key: "h/l", Desc: "Columns"},
 		{Key: "i", Desc: "Install"},
 		{Key: "X", Desc: "Uninstall"},
+		{Key: "d", Desc: "Drift"},
+		{Key: "s", Desc: "Summary"},
+		{Key: "z", Desc: "Collapse"},
+		{Key: "r", Desc: "Refresh"},
 		{Key: "Esc", Desc: "Dashboard"},
 	}
 }
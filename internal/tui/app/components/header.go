@@ -15,6 +15,12 @@ type HeaderModel struct {
 	machineName string
 	width       int
 	showIgnored bool
+
+	// Pending changes from the default source; hidden until set
+	source     string
+	adds       int
+	removes    int
+	hasPending bool
 }
 
 // NewHeader creates a new header model
@@ -41,6 +47,37 @@ func (m *HeaderModel) SetShowIgnored(show bool) {
 	m.showIgnored = show
 }
 
+// SetSource updates the default source shown with the pending changes.
+// An empty source hides the pending segment.
+func (m *HeaderModel) SetSource(name string) {
+	m.source = name
+}
+
+// SetPending updates the pending additions and removals from the source
+func (m *HeaderModel) SetPending(adds, removes int) {
+	m.adds = adds
+	m.removes = removes
+	m.hasPending = true
+}
+
+// ClearPending hides the pending segment until the totals are known again
+func (m *HeaderModel) ClearPending() {
+	m.hasPending = false
+}
+
+// pendingSegment returns the source and pending totals, or "" when unset
+func (m HeaderModel) pendingSegment() string {
+	if m.source == "" || !m.hasPending {
+		return ""
+	}
+	return fmt.Sprintf("⇄ %s +%d −%d", m.source, m.adds, m.removes)
+}
+
+// fits reports whether a line of the given width fits the header
+func (m HeaderModel) fits(width int) bool {
+	return m.width <= 0 || width <= m.width
+}
+
 // View renders the header
 func (m HeaderModel) View() string {
 	// Left side: app name and version
@@ -48,15 +85,21 @@ func (m HeaderModel) View() string {
 	ver := styles.SidebarDimmedStyle.Render("v" + version.Version)
 	leftSide := appName + " " + ver
 
-	// Right side: machine name
+	// Right side: machine name, preceded by the pending changes when they fit
 	rightSide := styles.SidebarDimmedStyle.Render("Machine: ") +
 		styles.HeaderStyle.Render("💻 "+m.machineName)
 
 	// Calculate spacing
 	leftWidth := lipgloss.Width(leftSide)
-	rightWidth := lipgloss.Width(rightSide)
 	separator := styles.BorderStyle.Render(" │ ")
 	sepWidth := lipgloss.Width(separator)
+	if pending := m.pendingSegment(); pending != "" {
+		withPending := styles.SidebarDimmedStyle.Render(pending) + separator + rightSide
+		if m.fits(leftWidth + sepWidth + lipgloss.Width(withPending)) {
+			rightSide = withPending
+		}
+	}
+	rightWidth := lipgloss.Width(rightSide)
 
 	// Build the header line
 	totalContent := leftWidth + sepWidth + rightWidth
@@ -96,20 +139,20 @@ func (m HeaderModel) RenderFullHeader() string {
 	sb.WriteString(styles.BorderStyle.Render(topLeft + strings.Repeat(horizontal, innerWidth) + topRight))
 	sb.WriteString("\n")
 
-	// Header content
+	// Header content, after a 2-space indent
 	content := m.View()
 	contentWidth := lipgloss.Width(content)
-	if contentWidth > innerWidth {
-		content = truncateString(content, innerWidth)
-	} else if contentWidth < innerWidth {
-		content = content + strings.Repeat(" ", innerWidth-contentWidth)
+	if contentWidth > innerWidth-2 {
+		content = truncateString(content, max(innerWidth-2, 0))
+		contentWidth = lipgloss.Width(content)
 	}
 
 	sb.WriteString(styles.BorderStyle.Render(vertical))
 	sb.WriteString("  ")
 	sb.WriteString(content)
-	// Adjust for the 2-space padding we added
-	sb.WriteString(strings.Repeat(" ", innerWidth-lipgloss.Width(content)-2))
+	if remaining := innerWidth - contentWidth - 2; remaining > 0 {
+		sb.WriteString(strings.Repeat(" ", remaining))
+	}
 	sb.WriteString(styles.BorderStyle.Render(vertical))
 	sb.WriteString("\n")
 
@@ -125,5 +168,13 @@ func (m HeaderModel) SimpleHeader() string {
 	if m.showIgnored {
 		ignoredStatus = "👁 Shown"
 	}
-	return fmt.Sprintf("📦 BrewSync v%s   │   💻 %s   │   Ignored: %s", version.Version, m.machineName, ignoredStatus)
+	header := fmt.Sprintf("📦 BrewSync v%s   │   💻 %s   │   Ignored: %s", version.Version, m.machineName, ignoredStatus)
+	if pending := m.pendingSegment(); pending != "" {
+		// The app indents the header by two columns
+		withPending := header + "   │   " + pending
+		if m.fits(lipgloss.Width(withPending) + 2) {
+			return withPending
+		}
+	}
+	return header
 }
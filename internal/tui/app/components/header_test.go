@@ -0,0 +1,37 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeader_PendingSegment(t *testing.T) {
+	h := NewHeader("mini", 200)
+	assert.NotContains(t, h.SimpleHeader(), "⇄")
+	assert.NotContains(t, h.View(), "⇄")
+
+	// Totals without a source stay hidden
+	h.SetPending(3, 1)
+	assert.NotContains(t, h.SimpleHeader(), "⇄")
+
+	h.SetSource("air")
+	assert.Contains(t, h.SimpleHeader(), "⇄ air +3 −1")
+	assert.Contains(t, h.View(), "⇄ air +3 −1")
+
+	// Dropped when the terminal is too narrow for it
+	h.SetWidth(60)
+	assert.NotContains(t, h.SimpleHeader(), "⇄")
+	assert.Contains(t, h.SimpleHeader(), "mini")
+
+	h.SetWidth(200)
+	h.ClearPending()
+	assert.NotContains(t, h.SimpleHeader(), "⇄")
+}
+
+func TestHeader_RenderFullHeaderNarrow(t *testing.T) {
+	for _, width := range []int{0, 3, 10, 80} {
+		h := NewHeader("mini", width)
+		assert.NotPanics(t, func() { h.RenderFullHeader() }, "width %d", width)
+	}
+}
@@ -57,7 +57,7 @@ func (m SidebarModel) View() string {
 	for _, item := range m.items {
 		if item.Separator {
 			// Render separator line
-			sep := strings.Repeat("─", m.width-2)
+			sep := strings.Repeat("─", max(m.width-2, 0))
 			sb.WriteString(styles.SeparatorStyle.Render("  " + sep))
 			sb.WriteString("\n")
 			continue
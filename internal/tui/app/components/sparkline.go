@@ -0,0 +1,32 @@
+package components
+
+// sparkBlocks are the bar heights used by Sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled between the
+// series minimum and maximum. A flat series renders as the lowest bar.
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+
+	bars := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if hi > lo {
+			level = (v - lo) * (len(sparkBlocks) - 1) / (hi - lo)
+		}
+		bars[i] = sparkBlocks[level]
+	}
+	return string(bars)
+}
@@ -0,0 +1,28 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"single value", []int{142}, "▁"},
+		{"flat", []int{5, 5, 5}, "▁▁▁"},
+		{"full range", []int{0, 1, 2, 3, 4, 5, 6, 7}, "▁▂▃▄▅▆▇█"},
+		{"growth", []int{134, 136, 139, 142}, "▁▂▅█"},
+		{"dip", []int{140, 120, 140}, "█▁█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Sparkline(tt.values))
+		})
+	}
+}
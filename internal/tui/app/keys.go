@@ -1,12 +1,18 @@
 package app
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/asamgx/brewsync/internal/tui/app/screens"
+)
 
 // KeyMap defines all keybindings for the main TUI
 type KeyMap struct {
 	// Global keys
-	Quit key.Binding
-	Help key.Binding
+	Quit          key.Binding
+	Help          key.Binding
+	ToggleIgnored key.Binding
+	Refresh       key.Binding
 }
 
 // DefaultKeyMap returns the default keybindings for the main TUI
@@ -20,7 +26,41 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
 		),
+		ToggleIgnored: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "toggle ignored"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r", "f5"),
+			key.WithHelp("r", "refresh"),
+		),
+	}
+}
+
+// Bindings returns the remappable global bindings by action name
+func (k *KeyMap) Bindings() screens.KeyBindings {
+	return screens.KeyBindings{
+		"quit":           &k.Quit,
+		"help":           &k.Help,
+		"toggle_ignored": &k.ToggleIgnored,
+		"refresh":        &k.Refresh,
+	}
+}
+
+// LoadKeyMaps returns the global and dashboard keymaps with the keybindings
+// config applied. Invalid overrides (unknown actions, reserved keys, or a key
+// bound to two actions) return the defaults along with the error.
+func LoadKeyMaps(overrides map[string]string) (KeyMap, screens.DashboardKeyMap, error) {
+	keys := DefaultKeyMap()
+	dashKeys := screens.DefaultDashboardKeyMap()
+	if len(overrides) == 0 {
+		return keys, dashKeys, nil
+	}
+
+	if err := screens.ApplyKeybindings(overrides, keys.Bindings(), dashKeys.Bindings()); err != nil {
+		return DefaultKeyMap(), screens.DefaultDashboardKeyMap(), err
 	}
+	return keys, dashKeys, nil
 }
 
 // ShortHelp returns keybindings shown in the mini help view
@@ -32,5 +72,6 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Quit, k.Help},
+		{k.ToggleIgnored, k.Refresh},
 	}
 }
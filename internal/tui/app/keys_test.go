@@ -0,0 +1,93 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/tui/app/screens"
+)
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestLoadKeyMaps_Overrides(t *testing.T) {
+	keys, dashKeys, err := LoadKeyMaps(map[string]string{
+		"history":        "y",
+		"toggle_ignored": "I, ctrl+g",
+		"quit":           "Q",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, key.Matches(runeKey("y"), dashKeys.History))
+	assert.False(t, key.Matches(runeKey("h"), dashKeys.History))
+	assert.Equal(t, "y", dashKeys.History.Help().Key)
+	assert.Equal(t, "history", dashKeys.History.Help().Desc)
+
+	assert.Equal(t, []string{"I", "ctrl+g"}, keys.ToggleIgnored.Keys())
+	assert.Equal(t, "I", keys.ToggleIgnored.Help().Key)
+
+	// Actions shared by both keymaps are remapped together
+	assert.Equal(t, []string{"Q"}, keys.Quit.Keys())
+	assert.Equal(t, []string{"Q"}, dashKeys.Quit.Keys())
+
+	// Untouched bindings keep their defaults
+	assert.Equal(t, []string{"s"}, dashKeys.Sync.Keys())
+}
+
+func TestLoadKeyMaps_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		wantErr   string
+	}{
+		{"duplicate with default", map[string]string{"history": "s"}, `"s" is bound to both`},
+		{"duplicate with global", map[string]string{"list": "H"}, `"H" is bound to both`},
+		{"duplicate overrides", map[string]string{"sync": "x", "diff": "x"}, `"x" is bound to both`},
+		{"unknown action", map[string]string{"teleport": "t"}, `unknown keybinding action "teleport"`},
+		{"reserved key", map[string]string{"history": "1"}, "reserved"},
+		{"no keys", map[string]string{"history": " , "}, "has no keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, dashKeys, err := LoadKeyMaps(tt.overrides)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			// Defaults are kept on error
+			assert.Equal(t, DefaultKeyMap().ToggleIgnored.Keys(), keys.ToggleIgnored.Keys())
+			assert.Equal(t, screens.DefaultDashboardKeyMap().History.Keys(), dashKeys.History.Keys())
+		})
+	}
+}
+
+func TestDefaultKeyMaps_NoConflicts(t *testing.T) {
+	keys := DefaultKeyMap()
+	dashKeys := screens.DefaultDashboardKeyMap()
+	assert.NoError(t, screens.KeyConflicts(keys.Bindings(), dashKeys.Bindings()))
+}
+
+func TestModel_RemappedKeys(t *testing.T) {
+	m := New(&config.Config{
+		Machines:       map[string]config.Machine{"mini": {Brewfile: "/tmp/Brewfile"}},
+		CurrentMachine: "mini",
+		Keybindings:    map[string]string{"history": "y", "toggle_ignored": "I"},
+	})
+
+	// The remapped history key reaches the dashboard and navigates
+	_, cmd := m.Update(runeKey("y"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, screens.Navigate("history"), cmd())
+
+	// The remapped toggle-ignored key is handled globally
+	updated, _ := m.Update(runeKey("I"))
+	assert.True(t, updated.(Model).showIgnored)
+	updated, _ = m.Update(runeKey("H"))
+	assert.False(t, updated.(Model).showIgnored)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -14,6 +15,12 @@ const (
 
 	// MinContentWidth is the minimum width for the content area
 	MinContentWidth = 40
+
+	// MinWidth and MinHeight are the smallest terminal the layout is drawn
+	// in: the sidebar, a minimum-width content area and their three borders,
+	// and the six lines of chrome plus a few lines of content
+	MinWidth  = SidebarWidth + MinContentWidth + 3
+	MinHeight = 12
 )
 
 // Layout handles the main TUI layout rendering
@@ -54,6 +61,31 @@ func (l Layout) SidebarHeight() int {
 	return l.ContentHeight()
 }
 
+// Unsized reports whether the terminal hasn't reported a size yet
+func (l Layout) Unsized() bool {
+	return l.width == 0 || l.height == 0
+}
+
+// TooSmall reports whether the terminal is below the minimum layout size;
+// an unsized terminal isn't too small, it just hasn't said yet
+func (l Layout) TooSmall() bool {
+	if l.Unsized() {
+		return false
+	}
+	return l.width < MinWidth || l.height < MinHeight
+}
+
+// RenderTooSmall renders the notice shown instead of the layout when the
+// terminal is below the minimum size
+func (l Layout) RenderTooSmall() string {
+	msg := fmt.Sprintf("Terminal too small (%dx%d)\nResize to at least %dx%d", l.width, l.height, MinWidth, MinHeight)
+	style := styles.WarningStyle
+	if l.width > 0 {
+		style = style.Width(l.width)
+	}
+	return style.Render(msg)
+}
+
 // Render renders the full layout with header, sidebar, content, and footer
 func (l Layout) Render(header, sidebar, content, footer string) string {
 	const (
@@ -1,9 +1,14 @@
 package app
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/asamgx/brewsync/internal/audit"
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/debug"
@@ -13,6 +18,14 @@ import (
 	"github.com/asamgx/brewsync/internal/tui/app/screens"
 )
 
+// statusTimeout is how long a status message stays in the footer
+const statusTimeout = 4 * time.Second
+
+// clearStatusMsg clears the status message numbered seq, unless a newer one replaced it
+type clearStatusMsg struct {
+	seq int
+}
+
 // Screen represents which screen is currently active
 type Screen int
 
@@ -62,18 +75,23 @@ type Model struct {
 	// State
 	statusMessage string
 	statusType    string // info, success, error, warning
+	statusSeq     int    // Counts status messages, so only the latest is cleared
 	needsSetup    bool
 	showIgnored   bool // Global toggle to show/hide ignored items (default: false)
 
-	keys KeyMap
-	help help.Model
+	keys     KeyMap
+	dashKeys screens.DashboardKeyMap
+	help     help.Model
 }
 
 // New creates a new main TUI model
 func New(cfg *config.Config) Model {
 	debug.Log("App.New: creating model, config=%v", cfg != nil)
-	needsSetup := cfg == nil || len(cfg.Machines) == 0
-	debug.Log("App.New: needsSetup=%v", needsSetup)
+	// No config file at all runs the full setup wizard; a config whose machines
+	// were all removed only needs a machine added on the config screen
+	needsSetup := cfg == nil
+	needsMachine := cfg != nil && len(cfg.Machines) == 0
+	debug.Log("App.New: needsSetup=%v needsMachine=%v", needsSetup, needsMachine)
 
 	// Default dimensions
 	width := 80
@@ -88,6 +106,9 @@ func New(cfg *config.Config) Model {
 	// Initialize layout components
 	sidebar := components.NewSidebar(components.DefaultMenuItems(), SidebarWidth)
 	header := components.NewHeader(machineName, width)
+	if cfg != nil && cfg.DefaultSource != cfg.CurrentMachine {
+		header.SetSource(cfg.DefaultSource)
+	}
 	footer := components.NewFooter(width)
 	footer.SetKeybindings(components.DashboardKeybindings())
 	layout := NewLayout(width, height)
@@ -102,24 +123,56 @@ func New(cfg *config.Config) Model {
 		header:     header,
 		footer:     footer,
 		layout:     layout,
-		keys:       DefaultKeyMap(),
 		help:       help.New(),
 		needsSetup: needsSetup,
 	}
+	m.loadKeyMaps()
 
 	// Create initial screen models here (not in Init) because Init has a value receiver
 	if needsSetup {
 		m.screen = ScreenSetup
 		m.setup = screens.NewSetupModel()
 		debug.Log("App.New: created setup model")
+	} else if needsMachine {
+		m.screen = ScreenConfig
+		m.sidebar.SetActive(int(ScreenConfig))
+		m.updateFooterKeybindings()
+		m.configM = screens.NewConfigModel(cfg)
+		m.configM.StartAddMachine()
+		debug.Log("App.New: created config model for first machine")
 	} else {
-		m.dashboard = screens.NewDashboardModel(cfg)
+		m.dashboard = m.newDashboard()
 		debug.Log("App.New: created dashboard model")
 	}
 
 	return m
 }
 
+// loadKeyMaps applies the keybindings config, keeping the defaults (and
+// reporting the problem in the footer) if the overrides are invalid
+func (m *Model) loadKeyMaps() {
+	var overrides map[string]string
+	if m.config != nil {
+		overrides = m.config.Keybindings
+	}
+
+	var err error
+	m.keys, m.dashKeys, err = LoadKeyMaps(overrides)
+	if err != nil {
+		debug.Log("App.loadKeyMaps: %v", err)
+		m.footer.SetStatus(fmt.Sprintf("Invalid keybindings, using defaults: %v", err), "error")
+	}
+	screens.SetToggleIgnoredKey(m.keys.ToggleIgnored.Help().Key)
+	m.updateFooterKeybindings()
+}
+
+// newDashboard creates a dashboard model using the configured keybindings
+func (m *Model) newDashboard() *screens.DashboardModel {
+	dashboard := screens.NewDashboardModel(m.config)
+	dashboard.SetKeys(m.dashKeys)
+	return dashboard
+}
+
 // Init initializes the model and returns initial commands
 func (m Model) Init() tea.Cmd {
 	debug.Log("App.Init: initializing, needsSetup=%v", m.needsSetup)
@@ -128,6 +181,8 @@ func (m Model) Init() tea.Cmd {
 	if m.needsSetup && m.setup != nil {
 		debug.Log("App.Init: calling setup.Init()")
 		cmds = append(cmds, m.setup.Init())
+	} else if m.screen == ScreenConfig && m.configM != nil {
+		cmds = append(cmds, m.configM.Init())
 	} else if m.dashboard != nil {
 		debug.Log("App.Init: calling dashboard.Init()")
 		cmd := m.dashboard.Init()
@@ -170,8 +225,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.routeToScreen(msg)
 		}
 
-		// q always quits
-		if msg.String() == "q" {
+		// Quit key (q by default) always quits
+		if key.Matches(msg, m.keys.Quit) {
 			return m, tea.Quit
 		}
 
@@ -186,14 +241,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.routeToScreen(msg)
 		}
 
-		// 'H' toggles showing ignored items
-		if msg.String() == "H" {
+		// Toggle-ignored key (H by default) toggles showing ignored items
+		if key.Matches(msg, m.keys.ToggleIgnored) {
 			m.showIgnored = !m.showIgnored
 			m.header.SetShowIgnored(m.showIgnored)
 			// Broadcast to current screen
 			return m.routeToScreen(screens.ShowIgnoredMsg{Show: m.showIgnored})
 		}
 
+		// Refresh key (r / F5 by default) re-runs the load command of data-driven screens
+		if key.Matches(msg, m.keys.Refresh) && m.isRefreshable() {
+			return m.routeToScreen(screens.RefreshMsg{})
+		}
+
 		// Global screen hotkeys (1-9, 0, !)
 		if screen := m.getScreenFromShortcut(msg.String()); screen >= 0 {
 			return m.navigateToScreen(screen)
@@ -211,6 +271,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if err == nil {
 			m.config = cfg
 			m.needsSetup = false
+			m.loadKeyMaps()
 			// Update header with machine name
 			if cfg.CurrentMachine != "" {
 				m.header.SetMachine(cfg.CurrentMachine)
@@ -218,14 +279,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Go to dashboard
 			m.screen = ScreenDashboard
-			m.dashboard = screens.NewDashboardModel(m.config)
+			m.dashboard = m.newDashboard()
 			m.sidebar.SetActive(int(ScreenDashboard))
 			return m, m.dashboard.Init()
 		}
 
+	case screens.PendingMsg:
+		m.header.SetSource(msg.Source)
+		m.header.SetPending(msg.Adds, msg.Removes)
+		return m, nil
+
 	case screens.StatusMsg:
 		m.statusMessage = msg.Message
 		m.statusType = msg.Type
+		m.statusSeq++
+		m.footer.SetStatus(msg.Message, msg.Type)
+		seq := m.statusSeq
+		model, cmd := m.routeToScreen(msg)
+		return model, tea.Batch(cmd, tea.Tick(statusTimeout, func(time.Time) tea.Msg {
+			return clearStatusMsg{seq: seq}
+		}))
+
+	case clearStatusMsg:
+		if msg.seq == m.statusSeq {
+			m.statusMessage = ""
+			m.statusType = ""
+			m.footer.ClearStatus()
+		}
+		return m, nil
+
+	case screens.SyncCompleteMsg:
+		// Drop the cached dashboard so it reloads pending changes
+		m.dashboard = nil
+		m.header.ClearPending()
+		return m, nil
 
 	case screens.PackageActionMsg:
 		// Start background package action
@@ -263,6 +350,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m.routeToScreen(msg)
 }
 
+// isRefreshable returns true if the current screen supports reloading its data
+func (m Model) isRefreshable() bool {
+	switch m.screen {
+	case ScreenDashboard, ScreenList, ScreenDiff, ScreenHistory, ScreenDoctor:
+		return true
+	}
+	return false
+}
+
 // getScreenFromShortcut returns the screen for a shortcut key, or -1 if not a shortcut
 func (m Model) getScreenFromShortcut(key string) Screen {
 	switch key {
@@ -305,7 +401,7 @@ func (m Model) navigateToScreen(screen Screen) (tea.Model, tea.Cmd) {
 	switch screen {
 	case ScreenDashboard:
 		if m.dashboard == nil {
-			m.dashboard = screens.NewDashboardModel(m.config)
+			m.dashboard = m.newDashboard()
 		}
 		return m, m.dashboard.Init()
 
@@ -355,28 +451,55 @@ func (m Model) navigateToScreen(screen Screen) (tea.Model, tea.Cmd) {
 
 // updateFooterKeybindings updates footer based on current screen
 func (m *Model) updateFooterKeybindings() {
+	var bindings []components.KeyBinding
 	switch m.screen {
 	case ScreenDashboard:
-		m.footer.SetKeybindings(components.DashboardKeybindings())
+		bindings = components.DashboardKeybindings()
 	case ScreenList:
-		m.footer.SetKeybindings(components.ListKeybindings())
+		bindings = components.ListKeybindings()
 	case ScreenImport:
-		m.footer.SetKeybindings(components.ImportKeybindings())
+		bindings = components.ImportKeybindings()
 	case ScreenSync:
-		m.footer.SetKeybindings(components.SyncKeybindings())
+		bindings = components.SyncKeybindings()
 	case ScreenDiff:
-		m.footer.SetKeybindings(components.DiffKeybindings())
+		bindings = components.DiffKeybindings()
 	case ScreenDump:
-		m.footer.SetKeybindings(components.DumpKeybindings())
+		bindings = components.DumpKeybindings()
 	case ScreenIgnore:
-		m.footer.SetKeybindings(components.IgnoreKeybindings())
+		bindings = components.IgnoreKeybindings()
 	default:
-		m.footer.SetKeybindings(components.ContentKeybindings())
+		bindings = components.ContentKeybindings()
 	}
+	m.footer.SetKeybindings(m.remapFooterKeys(bindings))
+}
+
+// remapFooterKeys shows the configured keys for remappable global actions
+func (m *Model) remapFooterKeys(bindings []components.KeyBinding) []components.KeyBinding {
+	remapped := map[string]string{
+		"Toggle Ignored": m.keys.ToggleIgnored.Help().Key,
+		"Refresh":        m.keys.Refresh.Help().Key,
+		"Quit":           m.keys.Quit.Help().Key,
+	}
+	for i, b := range bindings {
+		if k, ok := remapped[b.Desc]; ok {
+			bindings[i].Key = k
+		}
+	}
+	return bindings
 }
 
 // View renders the active screen
 func (m Model) View() string {
+	// Nothing can be drawn until the terminal reports a size
+	if m.layout.Unsized() {
+		return ""
+	}
+
+	// The bordered layout can't be drawn in a tiny terminal
+	if m.layout.TooSmall() {
+		return m.layout.RenderTooSmall()
+	}
+
 	// Setup screen uses full screen without sidebar
 	if m.screen == ScreenSetup {
 		if m.setup != nil {
@@ -600,6 +723,11 @@ func (m Model) handlePackageAction(msg screens.PackageActionMsg) (tea.Model, tea
 		func() tea.Msg {
 			// Execute the action
 			mgr := installer.NewManager()
+			if m.config != nil {
+				mgr.SetCaskNoQuarantine(m.config.Install.CaskNoQuarantine)
+				mgr.SetRetryPolicy(installer.Retries(m.config.Install.Retries))
+			}
+			audit.Attach(mgr, m.config, "brewsync tui: "+msg.Action)
 			pkg := brewfile.Package{
 				Type: brewfile.PackageType(msg.PkgType),
 				Name: msg.PkgName,
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/tui/app/screens"
+)
+
+func TestNew_InitialScreen(t *testing.T) {
+	t.Run("no config runs the setup wizard", func(t *testing.T) {
+		m := New(nil)
+		assert.Equal(t, ScreenSetup, m.screen)
+		assert.True(t, m.needsSetup)
+		assert.NotNil(t, m.setup)
+	})
+
+	t.Run("config without machines asks for the first machine", func(t *testing.T) {
+		cfg := &config.Config{}
+		m := New(cfg)
+		assert.Equal(t, ScreenConfig, m.screen)
+		assert.False(t, m.needsSetup)
+		assert.Nil(t, m.setup)
+		require.NotNil(t, m.configM)
+		assert.Contains(t, m.configM.ViewContent(80, 24), "Add Your First Machine")
+
+		// Typing a name creates the machine even though the map was nil
+		m.configM.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("mini")})
+		m.configM.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, cfg.Machines, "mini")
+	})
+
+	t.Run("configured machines open the dashboard", func(t *testing.T) {
+		m := New(&config.Config{
+			Machines:       map[string]config.Machine{"mini": {Brewfile: "/tmp/Brewfile"}},
+			CurrentMachine: "mini",
+		})
+		assert.Equal(t, ScreenDashboard, m.screen)
+		assert.NotNil(t, m.dashboard)
+		assert.Nil(t, m.configM)
+	})
+}
+
+func TestDashboard_HistoryAndShowIgnoredKeys(t *testing.T) {
+	m := New(&config.Config{
+		Machines:       map[string]config.Machine{"mini": {Brewfile: "/tmp/Brewfile"}},
+		CurrentMachine: "mini",
+	})
+	require.Equal(t, ScreenDashboard, m.screen)
+
+	// h reaches the dashboard's history binding instead of being swallowed globally
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	require.NotNil(t, cmd)
+	navigate := cmd()
+	assert.Equal(t, screens.Navigate("history"), navigate)
+	assert.False(t, updated.(Model).showIgnored)
+
+	updated, _ = updated.(Model).Update(navigate)
+	assert.Equal(t, ScreenHistory, updated.(Model).screen)
+
+	// H toggles showing ignored items
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	assert.True(t, updated.(Model).showIgnored)
+	assert.Equal(t, ScreenDashboard, updated.(Model).screen)
+}
+
+func TestView_TinyTerminal(t *testing.T) {
+	for _, cfg := range []*config.Config{
+		nil, // Setup wizard
+		{Machines: map[string]config.Machine{"mini": {Brewfile: "/tmp/Brewfile"}}, CurrentMachine: "mini"},
+	} {
+		var m tea.Model = New(cfg)
+		m, _ = m.Update(tea.WindowSizeMsg{Width: 10, Height: 5})
+
+		var view string
+		require.NotPanics(t, func() { view = m.View() })
+		assert.Contains(t, view, "too small")
+		assert.Contains(t, view, "63x12")
+
+		// Back to a usable size, the layout returns
+		m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+		assert.NotContains(t, m.View(), "too small")
+
+		// A size not yet reported isn't a tiny terminal
+		m, _ = m.Update(tea.WindowSizeMsg{Width: 0, Height: 0})
+		require.NotPanics(t, func() { view = m.View() })
+		assert.NotContains(t, view, "too small")
+	}
+	assert.False(t, Layout{}.TooSmall())
+}
@@ -0,0 +1,75 @@
+package screens
+
+import (
+	"fmt"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+// groupItem is a row of a list grouped by category, as in the diff and sync
+// columns: a category header or a package
+type groupItem struct {
+	isHeader    bool
+	headerType  brewfile.PackageType
+	headerCount int
+	collapsed   bool // Header whose packages are hidden
+	pkg         brewfile.Package
+	isIgnored   bool
+}
+
+// category returns the category of the row: the header's or the package's
+func (i groupItem) category() brewfile.PackageType {
+	if i.isHeader {
+		return i.headerType
+	}
+	return i.pkg.Type
+}
+
+// onHeader reports whether the row at cursor is a category header
+func onHeader(items []groupItem, cursor int) bool {
+	return cursor >= 0 && cursor < len(items) && items[cursor].isHeader
+}
+
+// collapsedGroups records which category groups of an item list are folded
+// down to their header
+type collapsedGroups map[brewfile.PackageType]bool
+
+// toggle folds or unfolds a category
+func (c *collapsedGroups) toggle(t brewfile.PackageType) {
+	if *c == nil {
+		*c = make(collapsedGroups)
+	}
+	if (*c)[t] {
+		delete(*c, t)
+	} else {
+		(*c)[t] = true
+	}
+}
+
+// toggleAt folds or unfolds the category of the row at *cursor, rebuilds the
+// rows into *items with rebuild and leaves the cursor on the category's header
+func (c *collapsedGroups) toggleAt(items *[]groupItem, cursor *int, rebuild func()) {
+	if *cursor < 0 || *cursor >= len(*items) {
+		return
+	}
+	t := (*items)[*cursor].category()
+
+	c.toggle(t)
+	rebuild()
+	for i, item := range *items {
+		if item.isHeader && item.headerType == t {
+			*cursor = i
+			break
+		}
+	}
+}
+
+// groupHeader returns the label of a category header; folded categories are
+// marked with ▸ so it's clear their packages are hidden
+func groupHeader(t brewfile.PackageType, count int, collapsed bool) string {
+	label := fmt.Sprintf("%s %s (%d)", brewfile.Meta(t).Icon, t, count)
+	if collapsed {
+		label += " ▸"
+	}
+	return label
+}
@@ -10,7 +10,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
@@ -25,6 +27,9 @@ const (
 	ConfigSectionOutput
 )
 
+// dumpTypeKeyPrefix prefixes the per-type collection toggles of the Dump section
+const dumpTypeKeyPrefix = "dump.types."
+
 // configItem represents an editable config item
 type configItem struct {
 	key         string
@@ -68,6 +73,7 @@ type ConfigModel struct {
 	machineEditItems  []configItem
 	addingMachine     bool
 	newMachineName    string
+	confirm           components.Confirm // Machine deletion
 
 	// Status
 	statusMessage string
@@ -86,6 +92,7 @@ func NewConfigModel(cfg *config.Config) *ConfigModel {
 		width:     80,
 		height:    24,
 		textInput: ti,
+		confirm:   components.NewConfirm("delete-machine"),
 	}
 
 	m.loadMachines()
@@ -130,9 +137,9 @@ func (m *ConfigModel) buildItems() {
 		{
 			key:         "default_categories",
 			label:       "Default Categories",
-			value:       strings.Join(m.config.DefaultCategories, ", "),
+			value:       strings.Join(m.config.EffectiveCategories(), ", "),
 			itemType:    "categories",
-			options:     []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"},
+			options:     []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "npm", "pipx", "mas"},
 			description: "Package types to include by default",
 		},
 		{
@@ -193,6 +200,29 @@ func (m *ConfigModel) buildItems() {
 			itemType:    "bool",
 			description: "Use 'brew bundle dump --describe' for better output",
 		},
+		{
+			key:         "dump.header",
+			label:       "Brewfile Header",
+			value:       boolToYesNo(m.config.Dump.Header),
+			itemType:    "bool",
+			description: "Start new Brewfiles with a header naming the machine",
+		},
+		{
+			key:         "dump.extension_versions",
+			label:       "Extension Versions",
+			value:       boolToYesNo(m.config.Dump.ExtensionVersions),
+			itemType:    "bool",
+			description: "Pin editor extensions to their installed version",
+		},
+	}
+	for _, t := range brewfile.AllTypes() {
+		m.dumpItems = append(m.dumpItems, configItem{
+			key:         dumpTypeKeyPrefix + string(t),
+			label:       "Collect " + string(t),
+			value:       boolToYesNo(m.config.CategoryEnabled(string(t))),
+			itemType:    "bool",
+			description: fmt.Sprintf("Include %s packages in dumps (default categories)", t),
+		})
 	}
 
 	// Output section items
@@ -218,6 +248,14 @@ func (m *ConfigModel) buildItems() {
 			itemType:    "bool",
 			description: "Show package descriptions in lists",
 		},
+		{
+			key:         "output.icons",
+			label:       "Icons",
+			value:       iconStyleValue(m.config.Output.Icons),
+			itemType:    "select",
+			options:     []string{string(brewfile.IconsEmoji), string(brewfile.IconsNerdFont), string(brewfile.IconsASCII)},
+			description: "How package types are drawn (ascii if emoji show as boxes)",
+		},
 	}
 }
 
@@ -255,6 +293,40 @@ func (m *ConfigModel) buildMachineEditItems() {
 			itemType:    "string",
 			description: "Optional description",
 		},
+		{
+			key:         "use_brew_bundle",
+			label:       "Brew Bundle",
+			value:       overrideToOption(machine.UseBrewBundle),
+			itemType:    "select",
+			options:     []string{"inherit", "yes", "no"},
+			description: "Use 'brew bundle dump' on this machine (inherit follows the Dump setting)",
+		},
+	}
+}
+
+// overrideToOption shows a per-machine bool override as inherit/yes/no
+func overrideToOption(v *bool) string {
+	switch {
+	case v == nil:
+		return "inherit"
+	case *v:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+// optionToOverride is the inverse of overrideToOption
+func optionToOverride(option string) *bool {
+	switch option {
+	case "yes":
+		v := true
+		return &v
+	case "no":
+		v := false
+		return &v
+	default:
+		return nil
 	}
 }
 
@@ -271,10 +343,21 @@ func (m *ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case components.ConfirmResultMsg:
+		if msg.ID == m.confirm.ID() && msg.Confirmed && m.editingMachine {
+			m.deleteSelectedMachine()
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		// Clear status on any key
 		m.statusMessage = ""
 
+		// Handle confirmation dialog
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
+		}
+
 		// Handle text input mode
 		if m.editing && m.editingItem != nil && m.editingItem.itemType == "string" {
 			return m.handleTextInput(msg)
@@ -355,31 +438,29 @@ func (m *ConfigModel) handleSelectInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *ConfigModel) toggleCategory(cat string) {
-	// Check if category is currently enabled
-	enabled := false
-	for _, c := range m.config.DefaultCategories {
-		if c == cat {
-			enabled = true
-			break
-		}
+	m.setCategoryEnabled(cat, !m.config.CategoryEnabled(cat))
+
+	m.hasChanges = true
+	m.buildItems() // Refresh display
+}
+
+// setCategoryEnabled adds or removes a type from the default categories,
+// starting from the effective list when none are configured
+func (m *ConfigModel) setCategoryEnabled(cat string, enabled bool) {
+	if m.config.CategoryEnabled(cat) == enabled {
+		return
 	}
 
-	if enabled {
-		// Remove category
-		var newCats []string
-		for _, c := range m.config.DefaultCategories {
-			if c != cat {
-				newCats = append(newCats, c)
-			}
+	var newCats []string
+	for _, c := range m.config.EffectiveCategories() {
+		if c != cat {
+			newCats = append(newCats, c)
 		}
-		m.config.DefaultCategories = newCats
-	} else {
-		// Add category
-		m.config.DefaultCategories = append(m.config.DefaultCategories, cat)
 	}
-
-	m.hasChanges = true
-	m.buildItems() // Refresh display
+	if enabled {
+		newCats = append(newCats, cat)
+	}
+	m.config.DefaultCategories = newCats
 }
 
 func (m *ConfigModel) handleMachineEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -403,6 +484,16 @@ func (m *ConfigModel) handleMachineEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			item := &m.machineEditItems[m.cursor]
 			m.editing = true
 			m.editingItem = item
+			if item.itemType == "select" {
+				m.selectIdx = 0
+				for i, opt := range item.options {
+					if opt == item.value {
+						m.selectIdx = i
+						break
+					}
+				}
+				return m, nil
+			}
 			m.textInput.SetValue(item.value)
 			m.textInput.Focus()
 		}
@@ -416,16 +507,9 @@ func (m *ConfigModel) handleMachineEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "d", "x":
 		// Delete machine (with confirmation)
 		if m.selectedMachine != m.config.CurrentMachine {
-			delete(m.config.Machines, m.selectedMachine)
-			m.hasChanges = true
-			m.loadMachines()
-			m.buildItems()
-			m.editingMachine = false
-			m.selectedMachine = ""
-			m.machineEditItems = nil
-			m.cursor = 0
-			m.statusMessage = "Machine deleted"
-			m.statusType = "success"
+			prompt := lipgloss.NewStyle().Foreground(styles.CatRed).Bold(true).Render("Delete") + " machine " +
+				lipgloss.NewStyle().Foreground(styles.CatMauve).Bold(true).Render(m.selectedMachine) + "?"
+			m.confirm.Open(prompt, styles.CatRed)
 		} else {
 			m.statusMessage = "Cannot delete current machine"
 			m.statusType = "error"
@@ -434,15 +518,30 @@ func (m *ConfigModel) handleMachineEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// deleteSelectedMachine removes the machine being edited from the config
+func (m *ConfigModel) deleteSelectedMachine() {
+	delete(m.config.Machines, m.selectedMachine)
+	m.hasChanges = true
+	m.loadMachines()
+	m.buildItems()
+	m.editingMachine = false
+	m.selectedMachine = ""
+	m.machineEditItems = nil
+	m.cursor = 0
+	m.statusMessage = "Machine deleted"
+	m.statusType = "success"
+}
+
 func (m *ConfigModel) saveMachineChanges() {
-	if m.selectedMachine == "" || len(m.machineEditItems) < 3 {
+	if m.selectedMachine == "" || len(m.machineEditItems) < 4 {
 		return
 	}
 
 	machine := config.Machine{
-		Hostname:    m.machineEditItems[0].value,
-		Brewfile:    m.machineEditItems[1].value,
-		Description: m.machineEditItems[2].value,
+		Hostname:      m.machineEditItems[0].value,
+		Brewfile:      m.machineEditItems[1].value,
+		Description:   m.machineEditItems[2].value,
+		UseBrewBundle: optionToOverride(m.machineEditItems[3].value),
 	}
 
 	m.config.Machines[m.selectedMachine] = machine
@@ -452,6 +551,15 @@ func (m *ConfigModel) saveMachineChanges() {
 	m.statusType = "success"
 }
 
+// StartAddMachine opens the add-machine prompt on the machines section
+func (m *ConfigModel) StartAddMachine() {
+	m.section = ConfigSectionMachines
+	m.addingMachine = true
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "Enter machine name..."
+	m.textInput.Focus()
+}
+
 func (m *ConfigModel) handleAddMachine(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
@@ -461,6 +569,9 @@ func (m *ConfigModel) handleAddMachine(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.statusMessage = "Machine already exists"
 				m.statusType = "error"
 			} else {
+				if m.config.Machines == nil {
+					m.config.Machines = make(map[string]config.Machine)
+				}
 				m.config.Machines[name] = config.Machine{
 					Hostname:    "",
 					Brewfile:    "",
@@ -534,10 +645,7 @@ func (m *ConfigModel) handleNormalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
 		// Add new machine (only in machines section)
 		if m.section == ConfigSectionMachines {
-			m.addingMachine = true
-			m.textInput.SetValue("")
-			m.textInput.Placeholder = "Enter machine name..."
-			m.textInput.Focus()
+			m.StartAddMachine()
 		}
 
 	case key.Matches(msg, key.NewBinding(key.WithKeys("s"))):
@@ -646,12 +754,19 @@ func (m *ConfigModel) applyChange(key, value string) {
 		m.config.AutoDump.CommitMessage = value
 	case "dump.use_brew_bundle":
 		m.config.Dump.UseBrewBundle = value == "Yes"
+	case "dump.header":
+		m.config.Dump.Header = value == "Yes"
+	case "dump.extension_versions":
+		m.config.Dump.ExtensionVersions = value == "Yes"
 	case "output.color":
 		m.config.Output.Color = value == "Yes"
 	case "output.verbose":
 		m.config.Output.Verbose = value == "Yes"
 	case "output.show_descriptions":
 		m.config.Output.ShowDescriptions = value == "Yes"
+	case "output.icons":
+		m.config.Output.Icons = value
+		_ = brewfile.SetIconStyle(value)
 	// Machine edit fields
 	case "hostname":
 		if m.selectedMachine != "" && len(m.machineEditItems) > 0 {
@@ -665,6 +780,15 @@ func (m *ConfigModel) applyChange(key, value string) {
 		if m.selectedMachine != "" && len(m.machineEditItems) > 2 {
 			m.machineEditItems[2].value = value
 		}
+	case "use_brew_bundle":
+		if m.selectedMachine != "" && len(m.machineEditItems) > 3 {
+			m.machineEditItems[3].value = value
+		}
+	default:
+		// Per-type dump toggles
+		if strings.HasPrefix(key, dumpTypeKeyPrefix) {
+			m.setCategoryEnabled(strings.TrimPrefix(key, dumpTypeKeyPrefix), value == "Yes")
+		}
 	}
 
 	m.hasChanges = true
@@ -764,7 +888,7 @@ func (m *ConfigModel) ViewContent(width, height int) string {
 	}
 	b.WriteString(strings.Join(tabs, " │ "))
 	b.WriteString("\n")
-	b.WriteString(styles.DimmedStyle.Render(strings.Repeat("─", width-4)))
+	b.WriteString(styles.DimmedStyle.Render(strings.Repeat("─", max(width-4, 0))))
 	b.WriteString("\n\n")
 
 	// Render current section
@@ -936,6 +1060,10 @@ func (m *ConfigModel) renderMachineEdit() string {
 	b.WriteString(titleStyle.Render(fmt.Sprintf("Edit Machine: %s", m.selectedMachine)))
 	b.WriteString("\n\n")
 
+	if m.editing && m.editingItem != nil && m.editingItem.itemType == "select" {
+		return m.renderSelectMode()
+	}
+
 	// If editing a text field
 	if m.editing && m.editingItem != nil && m.editingItem.itemType == "string" {
 		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.CatYellow).Render(m.editingItem.label + ":"))
@@ -972,6 +1100,10 @@ func (m *ConfigModel) renderMachineEdit() string {
 	}
 
 	b.WriteString("\n")
+	if m.confirm.Active() {
+		b.WriteString(m.confirm.View())
+		return b.String()
+	}
 	helpStyle := lipgloss.NewStyle().Foreground(styles.CatSubtext0)
 	b.WriteString(helpStyle.Render("Enter"))
 	b.WriteString(styles.DimmedStyle.Render(":edit • "))
@@ -989,8 +1121,15 @@ func (m *ConfigModel) renderAddMachine() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.CatYellow)
-	b.WriteString(titleStyle.Render("Add New Machine"))
-	b.WriteString("\n\n")
+	if len(m.machines) == 0 {
+		b.WriteString(titleStyle.Render("Add Your First Machine"))
+		b.WriteString("\n\n")
+		b.WriteString(styles.DimmedStyle.Render("Your config has no machines yet. Name this machine (e.g. \"mini\") and fill in its details."))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(titleStyle.Render("Add New Machine"))
+		b.WriteString("\n\n")
+	}
 	b.WriteString("Machine Name:\n")
 	b.WriteString(m.textInput.View())
 	b.WriteString("\n\n")
@@ -1026,14 +1165,7 @@ func (m *ConfigModel) renderSelectMode() string {
 		// For categories, show checkbox
 		checkbox := ""
 		if isCategories {
-			enabled := false
-			for _, c := range m.config.DefaultCategories {
-				if c == opt {
-					enabled = true
-					break
-				}
-			}
-			if enabled {
+			if m.config.CategoryEnabled(opt) {
 				checkbox = styles.SelectedStyle.Render("[✓] ")
 			} else {
 				checkbox = styles.DimmedStyle.Render("[ ] ")
@@ -1080,3 +1212,12 @@ func boolToYesNo(b bool) string {
 	}
 	return "No"
 }
+
+// iconStyleValue shows the configured icon style, emoji when unset or unknown
+func iconStyleValue(s string) string {
+	style, err := brewfile.ParseIconStyle(s)
+	if err != nil {
+		return string(brewfile.IconsEmoji)
+	}
+	return string(style)
+}
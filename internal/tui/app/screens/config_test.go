@@ -0,0 +1,107 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestConfigModel_DumpTypeToggle(t *testing.T) {
+	t.Setenv("MACHINE", "")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+machines:
+  mini:
+    hostname: "mini-host"
+    brewfile: "/tmp/Brewfile.mini"
+current_machine: mini
+`), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.True(t, cfg.CategoryEnabled("mas"))
+
+	m := NewConfigModel(cfg)
+	m.section = ConfigSectionDump
+
+	// One toggle per package type after use_brew_bundle, header and extension_versions
+	cursor := -1
+	for i, item := range m.dumpItems {
+		if item.key == dumpTypeKeyPrefix+"mas" {
+			cursor = i
+		}
+	}
+	require.Equal(t, 3+10, len(m.dumpItems))
+	require.NotEqual(t, -1, cursor)
+
+	m.cursor = cursor
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, cfg.CategoryEnabled("mas"))
+	assert.NotContains(t, cfg.EffectiveCategories(), "mas")
+	assert.Equal(t, "No", m.dumpItems[cursor].value)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.False(t, m.hasChanges)
+
+	config.SetConfigPath(configFile)
+	reloaded, err := config.Load()
+	require.NoError(t, err)
+	assert.False(t, reloaded.CategoryEnabled("mas"))
+	assert.True(t, reloaded.CategoryEnabled("brew"))
+
+	// Toggling back re-enables the type
+	m = NewConfigModel(reloaded)
+	m.section = ConfigSectionDump
+	m.cursor = cursor
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, reloaded.CategoryEnabled("mas"))
+}
+
+func TestConfigModel_MachineUseBrewBundle(t *testing.T) {
+	t.Setenv("MACHINE", "")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+machines:
+  mini:
+    hostname: "mini-host"
+    brewfile: "/tmp/Brewfile.mini"
+current_machine: mini
+dump:
+  use_brew_bundle: true
+`), 0644))
+	config.SetConfigPath(configFile)
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.True(t, cfg.UseBrewBundle("mini"))
+
+	m := NewConfigModel(cfg)
+	m.editingMachine = true
+	m.selectedMachine = "mini"
+	m.buildMachineEditItems()
+	require.Len(t, m.machineEditItems, 4)
+	assert.Equal(t, "inherit", m.machineEditItems[3].value)
+
+	// inherit → yes → no
+	m.cursor = 3
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, m.editing)
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "no", m.machineEditItems[3].value)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+
+	require.NotNil(t, cfg.Machines["mini"].UseBrewBundle)
+	assert.False(t, cfg.UseBrewBundle("mini"))
+	assert.Equal(t, "mini-host", cfg.Machines["mini"].Hostname)
+}
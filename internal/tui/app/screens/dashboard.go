@@ -13,23 +13,26 @@ import (
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
 	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/git"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
 // DashboardKeyMap defines keybindings for the dashboard
 type DashboardKeyMap struct {
-	Import  key.Binding
-	Sync    key.Binding
-	Diff    key.Binding
-	Dump    key.Binding
-	List    key.Binding
-	Ignore  key.Binding
-	Config  key.Binding
-	History key.Binding
-	Profile key.Binding
-	Doctor  key.Binding
-	Help    key.Binding
-	Quit    key.Binding
+	Import    key.Binding
+	Sync      key.Binding
+	Diff      key.Binding
+	Dump      key.Binding
+	QuickDump key.Binding
+	List      key.Binding
+	Ignore    key.Binding
+	Config    key.Binding
+	History   key.Binding
+	Profile   key.Binding
+	Doctor    key.Binding
+	Help      key.Binding
+	Quit      key.Binding
 }
 
 // DefaultDashboardKeyMap returns the default dashboard keybindings
@@ -51,6 +54,10 @@ func DefaultDashboardKeyMap() DashboardKeyMap {
 			key.WithKeys("D"),
 			key.WithHelp("D", "dump"),
 		),
+		QuickDump: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "quick dump"),
+		),
 		List: key.NewBinding(
 			key.WithKeys("l"),
 			key.WithHelp("l", "list"),
@@ -101,8 +108,11 @@ type DashboardModel struct {
 	packageCounts   map[string]int
 	totalPackages   int
 	lastDump        time.Time
+	lastSync        brewfile.LastSyncInfo
+	countHistory    []brewfile.CountSnapshot // Package totals recorded by recent dumps
 	ignoredCats     int
 	ignoredPkgs     int
+	brewfileDirty   bool // Brewfile has uncommitted git changes
 
 	// Pending changes - stored by type for breakdown
 	pendingAddsByType    map[string]int // type -> count
@@ -111,9 +121,12 @@ type DashboardModel struct {
 	ignoredRemovesByType map[string]int // type -> count (ignored removals)
 
 	// State
-	loading     bool
-	err         error
-	showIgnored bool
+	loading        bool   // Inventory not loaded yet
+	loadingPending bool   // Pending changes not computed yet
+	loadID         uint64 // Generation of the latest loadData
+	err            error
+	showIgnored    bool
+	dumping        bool // A quick dump is running
 }
 
 // NewDashboardModel creates a new dashboard model
@@ -129,6 +142,7 @@ func NewDashboardModel(cfg *config.Config) *DashboardModel {
 		ignoredAddsByType:    make(map[string]int),
 		ignoredRemovesByType: make(map[string]int),
 		loading:              true,
+		loadingPending:       true,
 	}
 
 	if cfg != nil {
@@ -143,18 +157,43 @@ func NewDashboardModel(cfg *config.Config) *DashboardModel {
 	return m
 }
 
-// loadDataMsg is sent when data loading completes
-type loadDataMsg struct {
-	packageCounts        map[string]int
-	totalPackages        int
-	lastDump             time.Time
+// SetKeys replaces the dashboard keybindings (e.g. with config overrides applied)
+func (m *DashboardModel) SetKeys(keys DashboardKeyMap) {
+	m.keys = keys
+}
+
+// The dashboard loads in three parts so a slow source Brewfile doesn't hold
+// back the rest: the inventory (counts, health) renders as soon as the
+// current Brewfile is parsed, while pending changes and ignore counts fill in
+// as they finish. Each part carries the loadID of the loadData that started it.
+
+// inventoryLoadedMsg is sent when the current machine's Brewfile and
+// metadata have been read
+type inventoryLoadedMsg struct {
+	packageCounts map[string]int
+	totalPackages int
+	lastDump      time.Time
+	lastSync      brewfile.LastSyncInfo
+	countHistory  []brewfile.CountSnapshot
+	brewfileDirty bool
+	err           error
+	loadID        uint64
+}
+
+// pendingLoadedMsg is sent when the diff against the default source is done
+type pendingLoadedMsg struct {
 	pendingAddsByType    map[string]int
 	pendingRemovesByType map[string]int
 	ignoredAddsByType    map[string]int
 	ignoredRemovesByType map[string]int
-	ignoredCats          int
-	ignoredPkgs          int
-	err                  error
+	loadID               uint64
+}
+
+// ignoredLoadedMsg is sent when the ignore counts have been read
+type ignoredLoadedMsg struct {
+	ignoredCats int
+	ignoredPkgs int
+	loadID      uint64
 }
 
 // Init initializes the dashboard and loads data
@@ -162,104 +201,171 @@ func (m *DashboardModel) Init() tea.Cmd {
 	return m.loadData()
 }
 
-// loadData loads dashboard data in background
+// loadData starts the dashboard's background loads
 func (m *DashboardModel) loadData() tea.Cmd {
-	debug.Log("Dashboard.loadData: starting background data load")
+	id := nextLoadID()
+	m.loadID = id
+	m.loadingPending = true
+	debug.Log("Dashboard.loadData: starting background data load %d", id)
+	return tea.Batch(m.loadInventory(id), m.loadPending(id), m.loadIgnored(id))
+}
+
+// loadInventory reads the current Brewfile, its metadata and git status
+func (m *DashboardModel) loadInventory(id uint64) tea.Cmd {
 	return func() tea.Msg {
-		debug.Log("Dashboard.loadData: inside tea.Cmd function")
-		result := loadDataMsg{
-			packageCounts:        make(map[string]int),
-			pendingAddsByType:    make(map[string]int),
-			pendingRemovesByType: make(map[string]int),
-			ignoredAddsByType:    make(map[string]int),
-			ignoredRemovesByType: make(map[string]int),
+		result := inventoryLoadedMsg{
+			loadID:        id,
+			packageCounts: make(map[string]int),
 		}
 
 		if m.config == nil {
-			debug.Log("Dashboard.loadData: config is nil")
+			debug.Log("Dashboard.loadInventory: config is nil")
 			result.err = fmt.Errorf("no config loaded")
 			return result
 		}
 
-		debug.Log("Dashboard.loadData: config loaded, current machine: %s", m.config.CurrentMachine)
+		debug.Log("Dashboard.loadInventory: config loaded, current machine: %s", m.config.CurrentMachine)
 
 		machine, ok := m.config.GetCurrentMachine()
 		if !ok {
-			debug.Log("Dashboard.loadData: current machine not found in config")
+			debug.Log("Dashboard.loadInventory: current machine not found in config")
 			result.err = fmt.Errorf("current machine not found in config")
 			return result
 		}
 
-		debug.Log("Dashboard.loadData: got machine, brewfile path: %s", machine.Brewfile)
-
 		// Load package counts from Brewfile
-		debug.Log("Dashboard.loadData: parsing brewfile...")
+		debug.Log("Dashboard.loadInventory: parsing brewfile: %s", machine.Brewfile)
 		packages, err := brewfile.Parse(machine.Brewfile)
 		if err != nil {
-			debug.Log("Dashboard.loadData: brewfile parse error: %v", err)
+			debug.Log("Dashboard.loadInventory: brewfile parse error: %v", err)
 		} else {
-			debug.Log("Dashboard.loadData: parsed %d packages", len(packages))
-			for _, pkg := range packages {
-				result.packageCounts[string(pkg.Type)]++
-				result.totalPackages++
-			}
+			debug.Log("Dashboard.loadInventory: parsed %d packages", len(packages))
+			stats := packages.Stats()
+			result.packageCounts = stats.Counts()
+			result.totalPackages = stats.Total
 		}
 
 		// Load metadata for last dump time
 		metaPath := filepath.Join(filepath.Dir(machine.Brewfile), ".brewsync-meta")
-		debug.Log("Dashboard.loadData: loading metadata from: %s", metaPath)
+		debug.Log("Dashboard.loadInventory: loading metadata from: %s", metaPath)
 		meta, err := brewfile.LoadMetadata(metaPath)
 		if err != nil {
-			debug.Log("Dashboard.loadData: metadata load error (non-fatal): %v", err)
+			debug.Log("Dashboard.loadInventory: metadata load error (non-fatal): %v", err)
 		} else if meta != nil {
 			result.lastDump = meta.LastDump
-			debug.Log("Dashboard.loadData: last dump: %v", meta.LastDump)
+			result.lastSync = meta.LastSync
+			debug.Log("Dashboard.loadInventory: last dump: %v, last sync: %v", meta.LastDump, meta.LastSync.At)
 		}
 
-		// Calculate pending changes if default source is different
-		if m.config.DefaultSource != "" && m.config.DefaultSource != m.config.CurrentMachine {
-			debug.Log("Dashboard.loadData: calculating pending changes from source: %s", m.config.DefaultSource)
-			if sourceMachine, ok := m.config.GetMachine(m.config.DefaultSource); ok {
-				sourcePackages, err := brewfile.Parse(sourceMachine.Brewfile)
-				if err != nil {
-					debug.Log("Dashboard.loadData: source brewfile parse error: %v", err)
-				} else {
-					diff := brewfile.Diff(sourcePackages, packages)
-
-					// Categorize additions by type, separating ignored
-					for _, pkg := range diff.Additions {
-						pkgType := string(pkg.Type)
-						isIgnored := m.config.IsCategoryIgnored(m.config.CurrentMachine, pkgType) ||
-							m.config.IsPackageIgnored(m.config.CurrentMachine, pkg.ID())
-						if isIgnored {
-							result.ignoredAddsByType[pkgType]++
-						} else {
-							result.pendingAddsByType[pkgType]++
-						}
+		// Load package count trend
+		history, err := brewfile.LoadCountHistory(brewfile.CountHistoryPath(machine.Brewfile))
+		if err != nil {
+			debug.Log("Dashboard.loadInventory: count history load error (non-fatal): %v", err)
+		}
+		result.countHistory = history
+
+		// Check for uncommitted Brewfile changes (dotfiles repo)
+		repo := git.NewRepo(filepath.Dir(machine.Brewfile))
+		if repo.IsRepo() {
+			if files, err := repo.DirtyFiles(); err == nil {
+				for _, file := range files {
+					if filepath.Base(file) == filepath.Base(machine.Brewfile) {
+						result.brewfileDirty = true
+						break
 					}
+				}
+			}
+		}
 
-					// Categorize removals by type, separating ignored
-					for _, pkg := range diff.Removals {
-						pkgType := string(pkg.Type)
-						isIgnored := m.config.IsCategoryIgnored(m.config.CurrentMachine, pkgType) ||
-							m.config.IsPackageIgnored(m.config.CurrentMachine, pkg.ID())
-						if isIgnored {
-							result.ignoredRemovesByType[pkgType]++
-						} else {
-							result.pendingRemovesByType[pkgType]++
-						}
-					}
+		debug.Log("Dashboard.loadInventory: completed, total=%d, err=%v", result.totalPackages, result.err)
+		return result
+	}
+}
 
-					debug.Log("Dashboard.loadData: pending adds=%v, removes=%v", result.pendingAddsByType, result.pendingRemovesByType)
-				}
+// loadPending diffs the current Brewfile against the default source's. It
+// reports no changes when there is no source or a Brewfile can't be read;
+// the inventory load reports config errors.
+func (m *DashboardModel) loadPending(id uint64) tea.Cmd {
+	return func() tea.Msg {
+		result := pendingLoadedMsg{
+			loadID:               id,
+			pendingAddsByType:    make(map[string]int),
+			pendingRemovesByType: make(map[string]int),
+			ignoredAddsByType:    make(map[string]int),
+			ignoredRemovesByType: make(map[string]int),
+		}
+
+		if m.config == nil || m.config.DefaultSource == "" || m.config.DefaultSource == m.config.CurrentMachine {
+			return result
+		}
+		machine, ok := m.config.GetCurrentMachine()
+		if !ok {
+			return result
+		}
+		sourceMachine, ok := m.config.GetMachine(m.config.DefaultSource)
+		if !ok {
+			return result
+		}
+
+		debug.Log("Dashboard.loadPending: calculating pending changes from source: %s", m.config.DefaultSource)
+		packages, err := brewfile.Parse(machine.Brewfile)
+		if err != nil {
+			debug.Log("Dashboard.loadPending: brewfile parse error: %v", err)
+			return result
+		}
+		sourcePackages, err := brewfile.Parse(sourceMachine.Brewfile)
+		if err != nil {
+			debug.Log("Dashboard.loadPending: source brewfile parse error: %v", err)
+			return result
+		}
+		diff := brewfile.DiffWithAliases(sourcePackages, packages, m.config.ExtensionAliases)
+
+		// Packages applied by a sync since the last dump are no longer pending
+		meta, err := brewfile.LoadMetadata(brewfile.MetadataPath(machine.Brewfile))
+		if err != nil {
+			debug.Log("Dashboard.loadPending: metadata load error (non-fatal): %v", err)
+		}
+		resolved := meta.SyncResolved(m.config.DefaultSource)
+		diff.Additions = diff.Additions.Exclude(resolved)
+		diff.Removals = diff.Removals.Exclude(resolved)
+
+		// Categorize additions by type, separating ignored
+		for _, pkg := range diff.Additions {
+			pkgType := string(pkg.Type)
+			isIgnored := m.config.IsCategoryIgnored(m.config.CurrentMachine, pkgType) ||
+				m.config.IsPackageIgnored(m.config.CurrentMachine, pkg.ID())
+			if isIgnored {
+				result.ignoredAddsByType[pkgType]++
+			} else {
+				result.pendingAddsByType[pkgType]++
 			}
 		}
 
-		// Count ignored items
-		result.ignoredCats = len(m.config.GetIgnoredCategories(m.config.CurrentMachine))
-		result.ignoredPkgs = len(m.config.GetIgnoredPackages(m.config.CurrentMachine))
+		// Categorize removals by type, separating ignored
+		for _, pkg := range diff.Removals {
+			pkgType := string(pkg.Type)
+			isIgnored := m.config.IsCategoryIgnored(m.config.CurrentMachine, pkgType) ||
+				m.config.IsPackageIgnored(m.config.CurrentMachine, pkg.ID())
+			if isIgnored {
+				result.ignoredRemovesByType[pkgType]++
+			} else {
+				result.pendingRemovesByType[pkgType]++
+			}
+		}
+
+		debug.Log("Dashboard.loadPending: pending adds=%v, removes=%v", result.pendingAddsByType, result.pendingRemovesByType)
+		return result
+	}
+}
 
-		debug.Log("Dashboard.loadData: completed, total=%d, err=%v", result.totalPackages, result.err)
+// loadIgnored counts the ignored categories and packages for this machine
+func (m *DashboardModel) loadIgnored(id uint64) tea.Cmd {
+	return func() tea.Msg {
+		result := ignoredLoadedMsg{loadID: id}
+		if m.config != nil {
+			result.ignoredCats = len(m.config.GetIgnoredCategories(m.config.CurrentMachine))
+			result.ignoredPkgs = len(m.config.GetIgnoredPackages(m.config.CurrentMachine))
+		}
 		return result
 	}
 }
@@ -275,17 +381,38 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
-	case loadDataMsg:
-		debug.Log("Dashboard.Update: received loadDataMsg, err=%v, total=%d", msg.err, msg.totalPackages)
+	case inventoryLoadedMsg:
+		debug.Log("Dashboard.Update: received inventoryLoadedMsg, err=%v, total=%d", msg.err, msg.totalPackages)
+		if msg.loadID != m.loadID {
+			debug.Log("Dashboard.Update: dropping stale load %d (current %d)", msg.loadID, m.loadID)
+			return m, nil
+		}
 		m.loading = false
 		m.err = msg.err
 		m.packageCounts = msg.packageCounts
 		m.totalPackages = msg.totalPackages
 		m.lastDump = msg.lastDump
+		m.lastSync = msg.lastSync
+		m.countHistory = msg.countHistory
+		m.brewfileDirty = msg.brewfileDirty
+		return m, nil
+
+	case pendingLoadedMsg:
+		if msg.loadID != m.loadID {
+			debug.Log("Dashboard.Update: dropping stale pending load %d (current %d)", msg.loadID, m.loadID)
+			return m, nil
+		}
+		m.loadingPending = false
 		m.pendingAddsByType = msg.pendingAddsByType
 		m.pendingRemovesByType = msg.pendingRemovesByType
 		m.ignoredAddsByType = msg.ignoredAddsByType
 		m.ignoredRemovesByType = msg.ignoredRemovesByType
+		return m, m.pendingCmd()
+
+	case ignoredLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil
+		}
 		m.ignoredCats = msg.ignoredCats
 		m.ignoredPkgs = msg.ignoredPkgs
 		return m, nil
@@ -294,6 +421,15 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.showIgnored = msg.Show
 		return m, nil
 
+	case RefreshMsg:
+		m.loading = true
+		return m, m.loadData()
+
+	case quickDumpDoneMsg:
+		m.dumping = false
+		m.loading = true
+		return m, tea.Batch(func() tea.Msg { return msg.status }, m.loadData())
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.keys.Import):
@@ -304,6 +440,12 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg { return Navigate("diff") }
 		case key.Matches(msg, m.keys.Dump):
 			return m, func() tea.Msg { return Navigate("dump") }
+		case key.Matches(msg, m.keys.QuickDump):
+			if m.dumping {
+				return m, nil
+			}
+			m.dumping = true
+			return m, tea.Batch(func() tea.Msg { return StatusInfo("Dumping packages...") }, m.quickDump())
 		case key.Matches(msg, m.keys.List):
 			return m, func() tea.Msg { return Navigate("list") }
 		case key.Matches(msg, m.keys.Ignore):
@@ -339,6 +481,13 @@ func (m *DashboardModel) View() string {
 func (m *DashboardModel) ViewContent(width, height int) string {
 	var b strings.Builder
 
+	// Recommended next step
+	if hint := m.recommendedAction(time.Now()); hint != "" {
+		hintStyle := lipgloss.NewStyle().Foreground(styles.CatYellow)
+		b.WriteString(hintStyle.Render("💡 " + hint))
+		b.WriteString("\n\n")
+	}
+
 	// System Health section
 	healthBox := m.renderHealthSection(width - 4)
 	b.WriteString(healthBox)
@@ -375,11 +524,29 @@ func (m *DashboardModel) renderHealthSection(width int) string {
 	}
 	content.WriteString("\n")
 
-	// Git Status (placeholder for now)
+	// Last Sync
+	content.WriteString(labelStyle.Render("Last Sync"))
+	if !m.lastSync.At.IsZero() {
+		content.WriteString(valueStyle.Render(fmt.Sprintf("%s from %s", formatTimeAgo(m.lastSync.At), m.lastSync.From)))
+		if m.lastSync.Added > 0 || m.lastSync.Removed > 0 {
+			content.WriteString(styles.DimmedStyle.Render(fmt.Sprintf(" (+%d/-%d)", m.lastSync.Added, m.lastSync.Removed)))
+		}
+	} else {
+		content.WriteString(valueStyle.Render("Never"))
+	}
+	content.WriteString("\n")
+
+	// Git Status
 	content.WriteString(labelStyle.Render("Git Status"))
-	content.WriteString(valueStyle.Render("Clean"))
-	content.WriteString("  ")
-	content.WriteString(okStyle.Render("🟢 OK"))
+	if m.brewfileDirty {
+		content.WriteString(valueStyle.Render("Uncommitted changes"))
+		content.WriteString("  ")
+		content.WriteString(lipgloss.NewStyle().Foreground(styles.CatYellow).Render("🟡 Commit"))
+	} else {
+		content.WriteString(valueStyle.Render("Clean"))
+		content.WriteString("  ")
+		content.WriteString(okStyle.Render("🟢 OK"))
+	}
 	content.WriteString("\n")
 
 	// Brewfile path
@@ -389,6 +556,63 @@ func (m *DashboardModel) renderHealthSection(width int) string {
 	return renderBox("System Health", content.String(), width)
 }
 
+// pendingTotals returns the pending additions and removals, ignored ones excluded
+func (m *DashboardModel) pendingTotals() (adds, removes int) {
+	for _, count := range m.pendingAddsByType {
+		adds += count
+	}
+	for _, count := range m.pendingRemovesByType {
+		removes += count
+	}
+	return adds, removes
+}
+
+// pendingCmd reports the pending totals to the app for the header
+func (m *DashboardModel) pendingCmd() tea.Cmd {
+	if m.err != nil {
+		return nil
+	}
+	source := m.defaultSource
+	if source == m.machineName {
+		source = ""
+	}
+	adds, removes := m.pendingTotals()
+	return func() tea.Msg {
+		return PendingMsg{Source: source, Adds: adds, Removes: removes}
+	}
+}
+
+// staleDumpAge is how old the last dump may get before the dashboard suggests a new one
+const staleDumpAge = 7 * 24 * time.Hour
+
+// recommendedAction returns the single most useful next step for the loaded
+// dashboard state, or "" when there is nothing to do. Checks run in priority order.
+func (m *DashboardModel) recommendedAction(now time.Time) string {
+	if m.loading || m.loadingPending || m.err != nil {
+		return ""
+	}
+
+	adds, removes := m.pendingTotals()
+	hasSource := m.defaultSource != "" && m.defaultSource != m.machineName
+
+	switch {
+	case m.lastDump.IsZero() && m.totalPackages == 0:
+		return fmt.Sprintf("Press %s to dump this machine's packages to its Brewfile", m.keys.Dump.Help().Key)
+	case adds > 0:
+		return fmt.Sprintf("Press %s to import %d package(s) from %s", m.keys.Import.Help().Key, adds, m.defaultSource)
+	case !m.lastDump.IsZero() && now.Sub(m.lastDump) > staleDumpAge:
+		days := int(now.Sub(m.lastDump).Hours() / 24)
+		return fmt.Sprintf("Last dump was %d days ago — press %s to dump", days, m.keys.Dump.Help().Key)
+	case m.brewfileDirty:
+		return "Uncommitted Brewfile changes — commit and push them to share with other machines"
+	case removes > 0:
+		return fmt.Sprintf("Press %s to sync: %d package(s) not in %s", m.keys.Sync.Help().Key, removes, m.defaultSource)
+	case !hasSource:
+		return fmt.Sprintf("Press %s to set a default source machine", m.keys.Config.Help().Key)
+	}
+	return ""
+}
+
 // renderInventorySection renders the Inventory box
 func (m *DashboardModel) renderInventorySection(width int) string {
 	var content strings.Builder
@@ -409,14 +633,16 @@ func (m *DashboardModel) renderInventorySection(width int) string {
 		name  string
 		count int
 	}{
-		{"🚰", "Taps", m.packageCounts["tap"]},
-		{"📦", "Casks", m.packageCounts["cask"]},
-		{"🍺", "Brews", m.packageCounts["brew"]},
-		{"💻", "VSCode", m.packageCounts["vscode"]},
-		{"✏️", "Cursor", m.packageCounts["cursor"]},
-		{"🔷", "Go", m.packageCounts["go"]},
-		{"🚀", "Antigrav", m.packageCounts["antigravity"]},
-		{"🍎", "MAS", m.packageCounts["mas"]},
+		{brewfile.Meta(brewfile.TypeTap).Icon, "Taps", m.packageCounts["tap"]},
+		{brewfile.Meta(brewfile.TypeCask).Icon, "Casks", m.packageCounts["cask"]},
+		{brewfile.Meta(brewfile.TypeBrew).Icon, "Brews", m.packageCounts["brew"]},
+		{brewfile.Meta(brewfile.TypeVSCode).Icon, "VSCode", m.packageCounts["vscode"]},
+		{brewfile.Meta(brewfile.TypeCursor).Icon, "Cursor", m.packageCounts["cursor"]},
+		{brewfile.Meta(brewfile.TypeGo).Icon, "Go", m.packageCounts["go"]},
+		{brewfile.Meta(brewfile.TypeNpm).Icon, "npm", m.packageCounts["npm"]},
+		{brewfile.Meta(brewfile.TypePipx).Icon, "pipx", m.packageCounts["pipx"]},
+		{brewfile.Meta(brewfile.TypeAntigravity).Icon, "Antigrav", m.packageCounts["antigravity"]},
+		{brewfile.Meta(brewfile.TypeMas).Icon, "MAS", m.packageCounts["mas"]},
 	}
 
 	// Two columns
@@ -449,16 +675,43 @@ func (m *DashboardModel) renderInventorySection(width int) string {
 	// Total
 	content.WriteString("\n")
 	totalStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.CatText)
-	content.WriteString(strings.Repeat(" ", colWidth))
+	content.WriteString(strings.Repeat(" ", max(colWidth, 0)))
 	content.WriteString(totalStyle.Render(fmt.Sprintf("Total: %d packages", m.totalPackages)))
+	if trend := m.renderCountTrend(time.Now()); trend != "" {
+		content.WriteString(" ")
+		content.WriteString(trend)
+	}
 
 	return renderBox("Inventory", content.String(), width)
 }
 
+// renderCountTrend renders a sparkline of recent dump totals and the change
+// over the last week, e.g. "▁▂▃▅ +8 this week". Empty with fewer than two dumps.
+func (m *DashboardModel) renderCountTrend(now time.Time) string {
+	if len(m.countHistory) < 2 {
+		return ""
+	}
+
+	trend := styles.DimmedStyle.Render(components.Sparkline(brewfile.CountTotals(m.countHistory)))
+	change := brewfile.CountChangeSince(m.countHistory, now.AddDate(0, 0, -7))
+	switch {
+	case change > 0:
+		trend += " " + styles.AddedStyle.Render(fmt.Sprintf("+%d this week", change))
+	case change < 0:
+		trend += " " + styles.RemovedStyle.Render(fmt.Sprintf("%d this week", change))
+	}
+	return trend
+}
+
 // renderPendingSection renders the Pending Changes box
 func (m *DashboardModel) renderPendingSection(width int) string {
 	var content strings.Builder
 
+	if m.loadingPending {
+		content.WriteString(styles.DimmedStyle.Render("Computing..."))
+		return renderBox("Pending Changes", content.String(), width)
+	}
+
 	// Calculate totals
 	totalAdds := 0
 	totalRemoves := 0
@@ -495,7 +748,7 @@ func (m *DashboardModel) renderPendingSection(width int) string {
 		// Show ignored count if any
 		if !m.showIgnored && (totalIgnoredAdds > 0 || totalIgnoredRemoves > 0) {
 			content.WriteString("\n")
-			content.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored, press h to show)", totalIgnoredAdds+totalIgnoredRemoves)))
+			content.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored, press %s to show)", totalIgnoredAdds+totalIgnoredRemoves, toggleIgnoredKey)))
 		}
 	} else {
 		labelStyle := lipgloss.NewStyle().Foreground(styles.MutedColor)
@@ -522,7 +775,7 @@ func (m *DashboardModel) renderPendingSection(width int) string {
 		// Show ignored hint
 		if !m.showIgnored && (totalIgnoredAdds > 0 || totalIgnoredRemoves > 0) {
 			content.WriteString("\n")
-			content.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored, press h to show)", totalIgnoredAdds+totalIgnoredRemoves)))
+			content.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored, press %s to show)", totalIgnoredAdds+totalIgnoredRemoves, toggleIgnoredKey)))
 		}
 	}
 
@@ -531,30 +784,16 @@ func (m *DashboardModel) renderPendingSection(width int) string {
 
 // renderBreakdown renders a breakdown of counts by type
 func (m *DashboardModel) renderBreakdown(content *strings.Builder, byType, ignoredByType map[string]int, prefix string, style lipgloss.Style) {
-	types := []struct {
-		key  string
-		icon string
-	}{
-		{"tap", "🚰"},
-		{"brew", "🍺"},
-		{"cask", "📦"},
-		{"vscode", "💻"},
-		{"cursor", "✏️"},
-		{"antigravity", "🚀"},
-		{"go", "🔷"},
-		{"mas", "🍎"},
-	}
-
-	for _, t := range types {
-		count := byType[t.key]
-		ignoredCount := ignoredByType[t.key]
+	for _, t := range brewfile.AllTypes() {
+		count := byType[string(t)]
+		ignoredCount := ignoredByType[string(t)]
 
 		if m.showIgnored {
 			count += ignoredCount
 		}
 
 		if count > 0 {
-			content.WriteString(fmt.Sprintf("    %s %s: %d", t.icon, t.key, count))
+			content.WriteString(fmt.Sprintf("    %s %s: %d", brewfile.Meta(t).Icon, t, count))
 			if m.showIgnored && ignoredCount > 0 {
 				content.WriteString(styles.DimmedStyle.Render(fmt.Sprintf(" (%d ignored)", ignoredCount)))
 			}
@@ -672,14 +911,16 @@ func (m *DashboardModel) renderPackagesSection() string {
 			name  string
 			count int
 		}{
-			{"🚰", "tap", m.packageCounts["tap"]},
-			{"🍺", "brew", m.packageCounts["brew"]},
-			{"📦", "cask", m.packageCounts["cask"]},
-			{"💻", "vscode", m.packageCounts["vscode"]},
-			{"✏️", "cursor", m.packageCounts["cursor"]},
-			{"🚀", "antigravity", m.packageCounts["antigravity"]},
-			{"🔷", "go", m.packageCounts["go"]},
-			{"🍎", "mas", m.packageCounts["mas"]},
+			{brewfile.Meta(brewfile.TypeTap).Icon, "tap", m.packageCounts["tap"]},
+			{brewfile.Meta(brewfile.TypeBrew).Icon, "brew", m.packageCounts["brew"]},
+			{brewfile.Meta(brewfile.TypeCask).Icon, "cask", m.packageCounts["cask"]},
+			{brewfile.Meta(brewfile.TypeVSCode).Icon, "vscode", m.packageCounts["vscode"]},
+			{brewfile.Meta(brewfile.TypeCursor).Icon, "cursor", m.packageCounts["cursor"]},
+			{brewfile.Meta(brewfile.TypeAntigravity).Icon, "antigravity", m.packageCounts["antigravity"]},
+			{brewfile.Meta(brewfile.TypeGo).Icon, "go", m.packageCounts["go"]},
+			{brewfile.Meta(brewfile.TypeNpm).Icon, "npm", m.packageCounts["npm"]},
+			{brewfile.Meta(brewfile.TypePipx).Icon, "pipx", m.packageCounts["pipx"]},
+			{brewfile.Meta(brewfile.TypeMas).Icon, "mas", m.packageCounts["mas"]},
 		}
 
 		var line1, line2 []string
@@ -790,23 +1031,24 @@ func (m *DashboardModel) renderActionsSection() string {
 		key   string
 		label string
 	}{
-		{"i", "Import"},
-		{"s", "Sync"},
-		{"d", "Diff"},
-		{"D", "Dump"},
-		{"l", "List"},
-		{"g", "Ignore"},
-		{"c", "Config"},
-		{"h", "History"},
-		{"p", "Profiles"},
-		{"!", "Doctor"},
+		{m.keys.Import.Help().Key, "Import"},
+		{m.keys.Sync.Help().Key, "Sync"},
+		{m.keys.Diff.Help().Key, "Diff"},
+		{m.keys.Dump.Help().Key, "Dump"},
+		{m.keys.QuickDump.Help().Key, "Quick Dump"},
+		{m.keys.List.Help().Key, "List"},
+		{m.keys.Ignore.Help().Key, "Ignore"},
+		{m.keys.Config.Help().Key, "Config"},
+		{m.keys.History.Help().Key, "History"},
+		{m.keys.Profile.Help().Key, "Profiles"},
+		{m.keys.Doctor.Help().Key, "Doctor"},
 	}
 
 	// Render in two rows
 	var row1, row2 []string
 	for i, a := range actions {
 		item := fmt.Sprintf("[%s] %s", keyStyle.Render(a.key), labelStyle.Render(a.label))
-		if i < 5 {
+		if i < 6 {
 			row1 = append(row1, item)
 		} else {
 			row2 = append(row2, item)
@@ -0,0 +1,213 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestDashboard_PendingExcludesSyncedPackages(t *testing.T) {
+	root := t.TempDir()
+	writeBrewfile := func(machine, content string) string {
+		path := filepath.Join(root, "_brew_"+machine, "Brewfile")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	miniBrewfile := writeBrewfile("mini", "brew \"git\"\nbrew \"jq\"\ncask \"firefox\"\n")
+	airBrewfile := writeBrewfile("air", "brew \"git\"\nbrew \"wget\"\n")
+
+	cfg := &config.Config{
+		Machines: map[string]config.Machine{
+			"mini": {Brewfile: miniBrewfile},
+			"air":  {Brewfile: airBrewfile},
+		},
+		CurrentMachine: "air",
+		DefaultSource:  "mini",
+	}
+
+	load := func() pendingLoadedMsg {
+		msg, ok := NewDashboardModel(cfg).loadPending(0)().(pendingLoadedMsg)
+		require.True(t, ok)
+		return msg
+	}
+
+	before := load()
+	assert.Equal(t, map[string]int{"brew": 1, "cask": 1}, before.pendingAddsByType)
+	assert.Equal(t, map[string]int{"brew": 1}, before.pendingRemovesByType)
+
+	// Sync from mini installs jq and firefox and removes wget, without a dump
+	metaPath := brewfile.MetadataPath(airBrewfile)
+	require.NoError(t, brewfile.UpdateMetadata(metaPath, "air", nil, "dev"))
+	require.NoError(t, brewfile.UpdateSyncMetadata(metaPath, "mini",
+		brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "jq"), brewfile.NewPackage(brewfile.TypeCask, "firefox")},
+		brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "wget")}))
+
+	after := load()
+	assert.Empty(t, after.pendingAddsByType)
+	assert.Empty(t, after.pendingRemovesByType)
+
+	inventory, ok := NewDashboardModel(cfg).loadInventory(0)().(inventoryLoadedMsg)
+	require.True(t, ok)
+	require.NoError(t, inventory.err)
+	assert.Equal(t, "mini", inventory.lastSync.From)
+	assert.Equal(t, 2, inventory.lastSync.Added)
+	assert.Equal(t, 1, inventory.lastSync.Removed)
+}
+
+func TestSync_DoneSendsSyncComplete(t *testing.T) {
+	m := NewSyncModel(&config.Config{DefaultSource: "mini"})
+
+	_, cmd := m.Update(syncDoneMsg{installed: 2, removed: 1})
+	require.NotNil(t, cmd)
+
+	// Sent alongside the re-plan
+	batch, ok := cmd().(tea.BatchMsg)
+	require.True(t, ok)
+	require.NotEmpty(t, batch)
+	assert.Equal(t, SyncCompleteMsg{Source: "mini", Installed: 2, Removed: 1}, batch[0]())
+}
+
+func TestDashboard_RecommendedAction(t *testing.T) {
+	now := time.Now()
+	loaded := func(mutate func(m *DashboardModel)) *DashboardModel {
+		m := NewDashboardModel(&config.Config{CurrentMachine: "air", DefaultSource: "mini"})
+		m.loading, m.loadingPending = false, false
+		m.totalPackages = 10
+		m.lastDump = now.Add(-time.Hour)
+		mutate(m)
+		return m
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(m *DashboardModel)
+		want   string
+	}{
+		{
+			name:   "in sync",
+			mutate: func(m *DashboardModel) {},
+			want:   "",
+		},
+		{
+			name:   "still loading",
+			mutate: func(m *DashboardModel) { m.loading = true; m.pendingAddsByType["brew"] = 3 },
+			want:   "",
+		},
+		{
+			name:   "pending changes still computing",
+			mutate: func(m *DashboardModel) { m.loadingPending = true; m.brewfileDirty = true },
+			want:   "",
+		},
+		{
+			name:   "never dumped",
+			mutate: func(m *DashboardModel) { m.totalPackages = 0; m.lastDump = time.Time{} },
+			want:   "Press D to dump this machine's packages to its Brewfile",
+		},
+		{
+			name:   "pending additions",
+			mutate: func(m *DashboardModel) { m.pendingAddsByType["brew"] = 2; m.pendingAddsByType["cask"] = 1 },
+			want:   "Press i to import 3 package(s) from mini",
+		},
+		{
+			name: "additions outrank stale dump and dirty git",
+			mutate: func(m *DashboardModel) {
+				m.pendingAddsByType["brew"] = 1
+				m.lastDump = now.Add(-30 * 24 * time.Hour)
+				m.brewfileDirty = true
+			},
+			want: "Press i to import 1 package(s) from mini",
+		},
+		{
+			name:   "stale dump",
+			mutate: func(m *DashboardModel) { m.lastDump = now.Add(-10 * 24 * time.Hour); m.brewfileDirty = true },
+			want:   "Last dump was 10 days ago — press D to dump",
+		},
+		{
+			name:   "dirty git",
+			mutate: func(m *DashboardModel) { m.brewfileDirty = true; m.pendingRemovesByType["brew"] = 1 },
+			want:   "Uncommitted Brewfile changes — commit and push them to share with other machines",
+		},
+		{
+			name:   "pending removals",
+			mutate: func(m *DashboardModel) { m.pendingRemovesByType["cask"] = 2 },
+			want:   "Press s to sync: 2 package(s) not in mini",
+		},
+		{
+			name:   "ignored changes don't count",
+			mutate: func(m *DashboardModel) { m.ignoredAddsByType["brew"] = 4 },
+			want:   "",
+		},
+		{
+			name:   "no default source",
+			mutate: func(m *DashboardModel) { m.defaultSource = "" },
+			want:   "Press c to set a default source machine",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, loaded(tt.mutate).recommendedAction(now))
+		})
+	}
+}
+
+func TestDashboard_CountTrend(t *testing.T) {
+	now := time.Now()
+	m := NewDashboardModel(&config.Config{})
+
+	m.countHistory = []brewfile.CountSnapshot{{At: now, Total: 142}}
+	assert.Empty(t, m.renderCountTrend(now), "a single dump has no trend")
+
+	m.countHistory = []brewfile.CountSnapshot{
+		{At: now.AddDate(0, 0, -14), Total: 134},
+		{At: now.AddDate(0, 0, -3), Total: 139},
+		{At: now.AddDate(0, 0, -1), Total: 142},
+	}
+	trend := m.renderCountTrend(now)
+	assert.Contains(t, trend, "▁▅█")
+	assert.Contains(t, trend, "+8 this week")
+}
+
+func TestDashboard_RendersLoadsAsTheyArrive(t *testing.T) {
+	m := NewDashboardModel(&config.Config{CurrentMachine: "air", DefaultSource: "mini"})
+	m.loadData()
+
+	view := m.ViewContent(100, 40)
+	assert.Contains(t, view, "Loading...")
+	assert.Contains(t, view, "Computing...")
+
+	// The inventory shows while pending changes are still computing
+	_, cmd := m.Update(inventoryLoadedMsg{loadID: m.loadID, packageCounts: map[string]int{"brew": 7}, totalPackages: 7})
+	assert.Nil(t, cmd)
+	view = m.ViewContent(100, 40)
+	assert.NotContains(t, view, "Loading...")
+	assert.Contains(t, view, "Computing...")
+	assert.Empty(t, m.recommendedAction(time.Now()))
+
+	// Pending changes fill in and are reported for the header
+	_, cmd = m.Update(pendingLoadedMsg{
+		loadID:               m.loadID,
+		pendingAddsByType:    map[string]int{"cask": 2},
+		pendingRemovesByType: map[string]int{},
+	})
+	require.NotNil(t, cmd)
+	assert.Equal(t, PendingMsg{Source: "mini", Adds: 2}, cmd())
+	view = m.ViewContent(100, 40)
+	assert.NotContains(t, view, "Computing...")
+	assert.Contains(t, view, "Press i to import 2 package(s) from mini")
+
+	// Ignore counts arrive independently
+	m.Update(ignoredLoadedMsg{loadID: m.loadID, ignoredCats: 1, ignoredPkgs: 3})
+	assert.Equal(t, 1, m.ignoredCats)
+	assert.Equal(t, 3, m.ignoredPkgs)
+}
@@ -5,11 +5,15 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
@@ -21,14 +25,13 @@ const (
 	DiffColumnRemovals
 )
 
-// diffItem represents a displayable item in a diff column (header or package)
-type diffItem struct {
-	isHeader    bool
-	headerType  brewfile.PackageType
-	headerCount int
-	pkg         brewfile.Package
-	isIgnored   bool
-}
+// DiffMode selects what the diff screen compares
+type DiffMode int
+
+const (
+	DiffModeSource DiffMode = iota // Source machine's Brewfile against the current Brewfile
+	DiffModeDrift                  // Current Brewfile against what is actually installed
+)
 
 // DiffModel is the model for the diff screen
 type DiffModel struct {
@@ -36,21 +39,28 @@ type DiffModel struct {
 	width        int
 	height       int
 	source       string
+	mode         DiffMode
+	spinner      spinner.Model
 	additions    brewfile.Packages
 	removals     brewfile.Packages
-	addItems     []diffItem // Flattened additions with headers
-	remItems     []diffItem // Flattened removals with headers
+	outdated     []brewfile.Outdated // Packages both sides have with an upgrade available here
+	addItems     []groupItem // Flattened additions with headers
+	remItems     []groupItem // Flattened removals with headers
+	addCollapsed collapsedGroups // Folded categories in additions
+	remCollapsed collapsedGroups // Folded categories in removals
 	column       DiffColumn // Current column focus
 	addCursor    int        // Cursor in additions
 	remCursor    int        // Cursor in removals
 	addOffset    int        // Scroll offset for additions
 	remOffset    int        // Scroll offset for removals
 	loading      bool
+	loadID       uint64 // Generation of the latest load
 	err          error
 	showIgnored  bool
+	summary      bool // Show per-type counts instead of the package columns
 
 	// Confirmation dialog
-	showConfirm   bool
+	confirm       components.Confirm
 	confirmAction string // "install" or "uninstall"
 	confirmPkg    brewfile.Package
 
@@ -64,57 +74,145 @@ func NewDiffModel(cfg *config.Config) *DiffModel {
 	if cfg != nil {
 		source = cfg.DefaultSource
 	}
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(styles.CatMauve)
+
 	return &DiffModel{
 		config:  cfg,
 		width:   80,
 		height:  24,
 		source:  source,
+		spinner: s,
 		loading: true,
+		confirm: components.NewConfirm("package-action"),
 	}
 }
 
 type diffLoadedMsg struct {
 	additions brewfile.Packages
 	removals  brewfile.Packages
+	outdated  []brewfile.Outdated
 	err       error
+	loadID    uint64
 }
 
 // Init initializes the diff model
 func (m *DiffModel) Init() tea.Cmd {
+	id := nextLoadID()
+	m.loadID = id
+	load := m.loadDiff
+	if m.mode == DiffModeDrift {
+		load = m.loadDrift
+	}
 	return func() tea.Msg {
-		if m.config == nil {
-			return diffLoadedMsg{err: fmt.Errorf("no config loaded")}
-		}
+		msg := load()
+		msg.loadID = id
+		return msg
+	}
+}
 
-		currentMachine, ok := m.config.GetCurrentMachine()
-		if !ok {
-			return diffLoadedMsg{err: fmt.Errorf("current machine not found")}
-		}
+// reload re-runs the load for the current mode. Collecting installed packages for
+// the drift view is slow, so it animates a spinner while it runs.
+func (m *DiffModel) reload() tea.Cmd {
+	m.loading = true
+	if m.mode == DiffModeDrift {
+		return tea.Batch(m.spinner.Tick, m.Init())
+	}
+	return m.Init()
+}
 
-		sourceMachine, ok := m.config.GetMachine(m.source)
-		if !ok {
-			return diffLoadedMsg{err: fmt.Errorf("source machine %q not found", m.source)}
-		}
+// loadDiff parses both Brewfiles and computes the diff
+func (m *DiffModel) loadDiff() diffLoadedMsg {
+	if m.config == nil {
+		return diffLoadedMsg{err: fmt.Errorf("no config loaded")}
+	}
 
-		// Parse both Brewfiles
-		currentPkgs, err := brewfile.Parse(currentMachine.Brewfile)
-		if err != nil {
-			return diffLoadedMsg{err: fmt.Errorf("failed to parse current Brewfile: %w", err)}
-		}
+	currentMachine, ok := m.config.GetCurrentMachine()
+	if !ok {
+		return diffLoadedMsg{err: fmt.Errorf("current machine not found")}
+	}
 
-		sourcePkgs, err := brewfile.Parse(sourceMachine.Brewfile)
-		if err != nil {
-			return diffLoadedMsg{err: fmt.Errorf("failed to parse source Brewfile: %w", err)}
-		}
+	sourceMachine, ok := m.config.GetMachine(m.source)
+	if !ok {
+		return diffLoadedMsg{err: fmt.Errorf("source machine %q not found", m.source)}
+	}
 
-		diff := brewfile.Diff(sourcePkgs, currentPkgs)
-		return diffLoadedMsg{
-			additions: diff.Additions,
-			removals:  diff.Removals,
-		}
+	// Parse both Brewfiles
+	currentPkgs, err := brewfile.Parse(currentMachine.Brewfile)
+	if err != nil {
+		return diffLoadedMsg{err: fmt.Errorf("failed to parse current Brewfile: %w", err)}
+	}
+
+	sourcePkgs, err := brewfile.Parse(sourceMachine.Brewfile)
+	if err != nil {
+		return diffLoadedMsg{err: fmt.Errorf("failed to parse source Brewfile: %w", err)}
+	}
+
+	diff := brewfile.DiffWithAliases(sourcePkgs, currentPkgs, m.config.ExtensionAliases)
+	if err := installer.NewBrewInstaller().MarkOutdated(diff); err != nil {
+		debug.Log("DiffModel: could not check for outdated packages: %v", err)
+	}
+	return diffLoadedMsg{
+		additions: diff.Additions,
+		removals:  diff.Removals,
+		outdated:  diff.Outdated,
 	}
 }
 
+// loadDrift collects the installed packages (as dump would) and compares them
+// with the current machine's Brewfile
+func (m *DiffModel) loadDrift() diffLoadedMsg {
+	if m.config == nil {
+		return diffLoadedMsg{err: fmt.Errorf("no config loaded")}
+	}
+
+	currentMachine, ok := m.config.GetCurrentMachine()
+	if !ok {
+		return diffLoadedMsg{err: fmt.Errorf("current machine not found")}
+	}
+
+	brewfilePkgs, err := brewfile.Parse(currentMachine.Brewfile)
+	if err != nil {
+		return diffLoadedMsg{err: fmt.Errorf("failed to parse current Brewfile: %w", err)}
+	}
+
+	installed, _, _, err := collectAllPackages(m.config, currentMachine.Brewfile)
+	if err != nil {
+		return diffLoadedMsg{err: fmt.Errorf("failed to collect installed packages: %w", err)}
+	}
+
+	diff := driftDiff(m.config, brewfilePkgs, installed)
+	return diffLoadedMsg{
+		additions: diff.Additions,
+		removals:  diff.Removals,
+	}
+}
+
+// driftDiff compares a Brewfile with the installed packages. Additions are Brewfile
+// entries that are not installed (installable), removals are installed packages the
+// Brewfile doesn't track (uninstallable). Types that dump skips are left out.
+func driftDiff(cfg *config.Config, brewfilePkgs, installed brewfile.Packages) *brewfile.DiffResult {
+	types := brewfile.EnabledTypes(cfg.CategoryEnabled)
+	return brewfile.DiffByTypeWithAliases(brewfilePkgs, installed, types, cfg.ExtensionAliases)
+}
+
+// toggleMode switches between the source and drift comparisons and reloads
+func (m *DiffModel) toggleMode() tea.Cmd {
+	if m.mode == DiffModeSource {
+		m.mode = DiffModeDrift
+	} else {
+		m.mode = DiffModeSource
+	}
+	m.additions, m.removals, m.outdated = nil, nil, nil
+	m.addItems, m.remItems = nil, nil
+	m.column = DiffColumnAdditions
+	m.addCursor, m.remCursor = 0, 0
+	m.addOffset, m.remOffset = 0, 0
+	m.err = nil
+	return m.reload()
+}
+
 // Update handles messages
 func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -123,10 +221,22 @@ func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
 	case diffLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil // Superseded by a newer load
+		}
 		m.loading = false
 		m.additions = msg.additions
 		m.removals = msg.removals
+		m.outdated = msg.outdated
 		m.err = msg.err
 		m.buildItems()
 		// Start in additions if available, otherwise removals
@@ -140,6 +250,18 @@ func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.buildItems() // Rebuild to update visibility
 		return m, nil
 
+	case RefreshMsg:
+		if m.confirm.Active() {
+			return m, nil
+		}
+		return m, m.reload()
+
+	case components.ConfirmResultMsg:
+		if msg.ID == m.confirm.ID() && msg.Confirmed {
+			return m, requestPackageAction(m.confirmAction, m.confirmPkg)
+		}
+		return m, nil
+
 	case PackageActionStartMsg:
 		m.taskRunning = true
 		return m, nil
@@ -147,12 +269,12 @@ func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case PackageActionDoneMsg:
 		m.taskRunning = false
 		// Reload diff after action
-		return m, m.Init()
+		return m, m.reload()
 
 	case tea.KeyMsg:
 		// Handle confirmation dialog
-		if m.showConfirm {
-			return m.handleConfirmInput(msg)
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
 		}
 
 		switch {
@@ -168,24 +290,36 @@ func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.jumpToTop()
 		case key.Matches(msg, key.NewBinding(key.WithKeys("G"))):
 			m.jumpToBottom()
+		case key.Matches(msg, key.NewBinding(key.WithKeys("z"))):
+			if !m.summary {
+				m.toggleCollapse()
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter", " "))) && !m.summary && m.onHeader():
+			m.toggleCollapse()
+		case key.Matches(msg, key.NewBinding(key.WithKeys("d"))):
+			if !m.taskRunning {
+				return m, m.toggleMode()
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("s"))):
+			m.summary = !m.summary
 		case key.Matches(msg, key.NewBinding(key.WithKeys("i"))):
 			// Install package (only from additions column)
-			if !m.taskRunning && m.column == DiffColumnAdditions {
+			if !m.taskRunning && !m.summary && m.column == DiffColumnAdditions {
 				pkg := m.getCurrentPackage()
 				if pkg != nil {
 					m.confirmPkg = *pkg
 					m.confirmAction = "install"
-					m.showConfirm = true
+					openPackageActionConfirm(&m.confirm, m.confirmAction, m.confirmPkg)
 				}
 			}
 		case key.Matches(msg, key.NewBinding(key.WithKeys("X"))):
 			// Uninstall package (only from removals column - packages that exist locally)
-			if !m.taskRunning && m.column == DiffColumnRemovals {
+			if !m.taskRunning && !m.summary && m.column == DiffColumnRemovals {
 				pkg := m.getCurrentPackage()
 				if pkg != nil {
 					m.confirmPkg = *pkg
 					m.confirmAction = "uninstall"
-					m.showConfirm = true
+					openPackageActionConfirm(&m.confirm, m.confirmAction, m.confirmPkg)
 				}
 			}
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
@@ -196,27 +330,9 @@ func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleConfirmInput handles input in the confirmation dialog
-func (m *DiffModel) handleConfirmInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		m.showConfirm = false
-		return m, func() tea.Msg {
-			return PackageActionMsg{
-				PkgType: string(m.confirmPkg.Type),
-				PkgName: m.confirmPkg.Name,
-				Action:  m.confirmAction,
-			}
-		}
-	case "n", "N", "esc":
-		m.showConfirm = false
-	}
-	return m, nil
-}
-
 // getCurrentPackage returns the package at the current cursor position in the active column
 func (m *DiffModel) getCurrentPackage() *brewfile.Package {
-	var items []diffItem
+	var items []groupItem
 	var cursor int
 
 	if m.column == DiffColumnAdditions {
@@ -239,13 +355,13 @@ func (m *DiffModel) getCurrentPackage() *brewfile.Package {
 
 // buildItems creates flattened lists with category headers
 func (m *DiffModel) buildItems() {
-	m.addItems = m.buildItemsForPackages(m.additions)
-	m.remItems = m.buildItemsForPackages(m.removals)
+	m.addItems = m.buildItemsForPackages(m.additions, m.addCollapsed)
+	m.remItems = m.buildItemsForPackages(m.removals, m.remCollapsed)
 }
 
 // buildItemsForPackages creates a flattened list with headers for a package list
-func (m *DiffModel) buildItemsForPackages(pkgs brewfile.Packages) []diffItem {
-	var items []diffItem
+func (m *DiffModel) buildItemsForPackages(pkgs brewfile.Packages, collapsed collapsedGroups) []groupItem {
+	var items []groupItem
 	byType := pkgs.ByType()
 	types := []brewfile.PackageType{
 		brewfile.TypeTap,
@@ -255,6 +371,8 @@ func (m *DiffModel) buildItemsForPackages(pkgs brewfile.Packages) []diffItem {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
@@ -289,27 +407,51 @@ func (m *DiffModel) buildItemsForPackages(pkgs brewfile.Packages) []diffItem {
 		}
 
 		// Add header
-		items = append(items, diffItem{
+		items = append(items, groupItem{
 			isHeader:    true,
 			headerType:  t,
 			headerCount: headerCount,
+			collapsed:   collapsed[t],
 		})
+		if collapsed[t] {
+			continue
+		}
 
 		// Add visible packages first
 		for _, pkg := range visiblePkgs {
-			items = append(items, diffItem{pkg: pkg, isIgnored: false})
+			items = append(items, groupItem{pkg: pkg, isIgnored: false})
 		}
 
 		// Add ignored packages if showing ignored
 		if m.showIgnored {
 			for _, pkg := range ignoredPkgs {
-				items = append(items, diffItem{pkg: pkg, isIgnored: true})
+				items = append(items, groupItem{pkg: pkg, isIgnored: true})
 			}
 		}
 	}
 	return items
 }
 
+// toggleCollapse folds or unfolds the category under the cursor in the
+// current column, leaving the cursor on its header
+func (m *DiffModel) toggleCollapse() {
+	if m.column == DiffColumnAdditions {
+		m.addCollapsed.toggleAt(&m.addItems, &m.addCursor, m.buildItems)
+		m.adjustAddOffset()
+	} else {
+		m.remCollapsed.toggleAt(&m.remItems, &m.remCursor, m.buildItems)
+		m.adjustRemOffset()
+	}
+}
+
+// onHeader reports whether the cursor is on a category header
+func (m *DiffModel) onHeader() bool {
+	if m.column == DiffColumnRemovals {
+		return onHeader(m.remItems, m.remCursor)
+	}
+	return onHeader(m.addItems, m.addCursor)
+}
+
 // moveUp moves cursor up in current column
 func (m *DiffModel) moveUp() {
 	if m.column == DiffColumnAdditions {
@@ -404,9 +546,9 @@ func (m *DiffModel) adjustRemOffset() {
 
 // getColumnHeight returns the visible height for each column
 func (m *DiffModel) getColumnHeight() int {
-	h := m.height - 4 // Just title and scroll indicator
+	h := m.height - 4 - m.outdatedHeight() // Title, scroll indicator and outdated section
 	// Reserve space for confirmation dialog if showing
-	if m.showConfirm {
+	if m.confirm.Active() {
 		h -= 5
 	}
 	if h < 1 {
@@ -432,11 +574,21 @@ func (m *DiffModel) ViewContent(width, height int) string {
 
 	// Title showing source -> target
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.CatMauve)
-	b.WriteString(titleStyle.Render(fmt.Sprintf("Diff: %s → %s", m.source, m.config.CurrentMachine)))
+	addTitle, remTitle := "TO IMPORT", "NOT IN SOURCE"
+	if m.mode == DiffModeDrift {
+		b.WriteString(titleStyle.Render(fmt.Sprintf("Drift: %s Brewfile ↔ installed", m.config.CurrentMachine)))
+		addTitle, remTitle = "NOT INSTALLED", "UNTRACKED"
+	} else {
+		b.WriteString(titleStyle.Render(fmt.Sprintf("Diff: %s → %s", m.source, m.config.CurrentMachine)))
+	}
 	b.WriteString("\n\n")
 
 	if m.loading {
-		b.WriteString(styles.DimmedStyle.Render("Computing diff..."))
+		if m.mode == DiffModeDrift {
+			b.WriteString(m.spinner.View() + " " + styles.DimmedStyle.Render("Collecting installed packages..."))
+		} else {
+			b.WriteString(styles.DimmedStyle.Render("Computing diff..."))
+		}
 		return b.String()
 	}
 
@@ -448,8 +600,18 @@ func (m *DiffModel) ViewContent(width, height int) string {
 	// No changes
 	if len(m.additions) == 0 && len(m.removals) == 0 {
 		b.WriteString(styles.SelectedStyle.Render("✓ "))
-		b.WriteString("Machines are in sync!")
+		if m.mode == DiffModeDrift {
+			b.WriteString("Brewfile matches installed packages!")
+		} else {
+			b.WriteString("Machines are in sync!")
+		}
 		b.WriteString("\n")
+		b.WriteString(m.renderOutdated(width))
+		return b.String()
+	}
+
+	if m.summary {
+		b.WriteString(m.renderSummary(addTitle, remTitle))
 		return b.String()
 	}
 
@@ -463,7 +625,7 @@ func (m *DiffModel) ViewContent(width, height int) string {
 	// Build left column (additions)
 	leftLines := m.renderColumn(
 		m.addItems,
-		fmt.Sprintf("TO IMPORT (+%d)", len(m.additions)),
+		fmt.Sprintf("%s (+%d)", addTitle, len(m.additions)),
 		"+",
 		colWidth,
 		visibleHeight,
@@ -476,7 +638,7 @@ func (m *DiffModel) ViewContent(width, height int) string {
 	// Build right column (removals)
 	rightLines := m.renderColumn(
 		m.remItems,
-		fmt.Sprintf("NOT IN SOURCE (-%d)", len(m.removals)),
+		fmt.Sprintf("%s (-%d)", remTitle, len(m.removals)),
 		"−",
 		colWidth,
 		visibleHeight,
@@ -516,45 +678,113 @@ func (m *DiffModel) ViewContent(width, height int) string {
 		b.WriteString(right)
 		b.WriteString("\n")
 	}
+	b.WriteString(m.renderOutdated(width))
 
 	// Confirmation dialog overlay
-	if m.showConfirm {
+	if m.confirm.Active() {
 		b.WriteString("\n")
-		b.WriteString(m.renderConfirmDialog())
+		b.WriteString(m.confirm.View())
 	}
 
 	return b.String()
 }
 
-// renderConfirmDialog renders the confirmation dialog
-func (m *DiffModel) renderConfirmDialog() string {
-	actionLabel := "Uninstall"
-	actionColor := styles.CatRed
-	if m.confirmAction == "install" {
-		actionLabel = "Install"
-		actionColor = styles.CatGreen
+// maxOutdatedLines caps the packages listed in the outdated section
+const maxOutdatedLines = 5
+
+// outdatedHeight returns the number of lines renderOutdated takes
+func (m *DiffModel) outdatedHeight() int {
+	if len(m.outdated) == 0 {
+		return 0
 	}
+	lines := min(len(m.outdated), maxOutdatedLines)
+	if len(m.outdated) > maxOutdatedLines {
+		lines++ // "… and N more"
+	}
+	return lines + 2 // Blank line and title
+}
 
-	// Build dialog content
-	actionStyle := lipgloss.NewStyle().Foreground(actionColor).Bold(true)
-	pkgStyle := lipgloss.NewStyle().Foreground(styles.CatMauve).Bold(true)
-	promptStyle := lipgloss.NewStyle().Foreground(styles.CatYellow)
+// renderOutdated renders the packages both machines have that Homebrew can
+// upgrade here, below the columns
+func (m *DiffModel) renderOutdated(width int) string {
+	if len(m.outdated) == 0 {
+		return ""
+	}
 
-	line1 := actionStyle.Render(actionLabel) + " " + pkgStyle.Render(fmt.Sprintf("%s:%s", m.confirmPkg.Type, m.confirmPkg.Name)) + "?"
-	line2 := promptStyle.Render("Press ") + lipgloss.NewStyle().Bold(true).Render("y") + promptStyle.Render(" to confirm, ") + lipgloss.NewStyle().Bold(true).Render("n") + promptStyle.Render(" to cancel")
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.CatLavender).Render(fmt.Sprintf("OUTDATED (~%d)", len(m.outdated))))
+	b.WriteString("\n")
+	for i, o := range m.outdated {
+		if i == maxOutdatedLines {
+			b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("    … and %d more (brewsync diff lists them all)", len(m.outdated)-i)))
+			b.WriteString("\n")
+			break
+		}
+		name := truncate(o.Package.Name, max(width/2, 10))
+		b.WriteString("    " + styles.GetCategoryStyle(string(o.Package.Type)).Render("~ "+name))
+		b.WriteString(" " + styles.DimmedStyle.Render(o.Installed+" → "+o.Latest))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
 
-	// Create bordered dialog box
-	dialogStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(actionColor).
-		Padding(0, 2)
+// summaryCounts returns the per-type counts shown in the headers of items
+func summaryCounts(items []groupItem) map[brewfile.PackageType]int {
+	counts := make(map[brewfile.PackageType]int)
+	for _, item := range items {
+		if item.isHeader {
+			counts[item.headerType] = item.headerCount
+		}
+	}
+	return counts
+}
 
-	return dialogStyle.Render(line1 + "\n" + line2)
+// renderSummary renders one line of counts per type, for a quick look at
+// the shape of a large diff before drilling into the columns
+func (m *DiffModel) renderSummary(addTitle, remTitle string) string {
+	var b strings.Builder
+	adds, rems := summaryCounts(m.addItems), summaryCounts(m.remItems)
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.CatLavender)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-16s %14s %14s", "TYPE", addTitle, remTitle)))
+	b.WriteString("\n")
+	b.WriteString(styles.DimmedStyle.Render("  " + strings.Repeat("─", 46)))
+	b.WriteString("\n")
+
+	var totalAdds, totalRems int
+	for _, t := range brewfile.AllTypes() {
+		added, removed := adds[t], rems[t]
+		if added == 0 && removed == 0 {
+			continue
+		}
+		totalAdds += added
+		totalRems += removed
+
+		label := styles.GetCategoryStyle(string(t)).Width(16).Render(brewfile.Meta(t).Icon + " " + string(t))
+		b.WriteString("  " + label + " ")
+		b.WriteString(styles.AddedStyle.Render(fmt.Sprintf("%14s", fmt.Sprintf("+%d", added))) + " ")
+		b.WriteString(styles.RemovedStyle.Render(fmt.Sprintf("%14s", fmt.Sprintf("-%d", removed))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(styles.DimmedStyle.Render("  " + strings.Repeat("─", 46)))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-16s %s %s\n", "total",
+		styles.AddedStyle.Bold(true).Render(fmt.Sprintf("%14s", fmt.Sprintf("+%d", totalAdds))),
+		styles.RemovedStyle.Bold(true).Render(fmt.Sprintf("%14s", fmt.Sprintf("-%d", totalRems)))))
+	if len(m.outdated) > 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(styles.CatLavender).Render(fmt.Sprintf("  %d outdated", len(m.outdated))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.DimmedStyle.Render("Press s for the detailed view"))
+	return b.String()
 }
 
 // renderColumn renders a single column with categorized packages
 func (m *DiffModel) renderColumn(
-	items []diffItem,
+	items []groupItem,
 	title string,
 	prefix string,
 	width int,
@@ -572,7 +802,7 @@ func (m *DiffModel) renderColumn(
 		headerStyle = headerStyle.Underline(true)
 	}
 	lines = append(lines, headerStyle.Render(title))
-	lines = append(lines, styles.DimmedStyle.Render(strings.Repeat("─", width-2)))
+	lines = append(lines, styles.DimmedStyle.Render(strings.Repeat("─", max(width-2, 0))))
 
 	if len(items) == 0 {
 		lines = append(lines, styles.DimmedStyle.Render("(none)"))
@@ -591,13 +821,12 @@ func (m *DiffModel) renderColumn(
 
 		if item.isHeader {
 			// Category header with icon
-			icon := getTypeIcon(item.headerType)
 			catStyle := styles.GetCategoryStyle(string(item.headerType)).Bold(true)
 			linePrefix := "  "
 			if isCursor {
 				linePrefix = styles.CursorStyle.Render("> ")
 			}
-			line := linePrefix + catStyle.Render(fmt.Sprintf("%s %s (%d)", icon, item.headerType, item.headerCount))
+			line := linePrefix + catStyle.Render(groupHeader(item.headerType, item.headerCount, item.collapsed))
 			lines = append(lines, line)
 		} else {
 			// Package line
@@ -608,10 +837,7 @@ func (m *DiffModel) renderColumn(
 
 			// Truncate name to fit column
 			maxNameLen := width - 12 // Extra space for ignored marker
-			name := item.pkg.Name
-			if len(name) > maxNameLen {
-				name = name[:maxNameLen-3] + "..."
-			}
+			name := truncate(item.pkg.Name, maxNameLen)
 
 			var line string
 			if item.isIgnored {
@@ -624,6 +850,9 @@ func (m *DiffModel) renderColumn(
 					nameStyle = lipgloss.NewStyle().Foreground(styles.CatMauve).Bold(true)
 				}
 				line = linePrefix + nameStyle.Render(prefix+" "+name)
+				if m.config != nil && m.config.IsPinned(item.pkg.ID()) {
+					line += " " + lipgloss.NewStyle().Foreground(styles.CatYellow).Render("📌")
+				}
 			}
 			lines = append(lines, line)
 		}
@@ -0,0 +1,190 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+// itemNames returns the package names of the non-header items
+func itemNames(items []groupItem) []string {
+	var names []string
+	for _, item := range items {
+		if !item.isHeader {
+			names = append(names, item.pkg.Name)
+		}
+	}
+	return names
+}
+
+func TestDriftDiff(t *testing.T) {
+	cfg := &config.Config{
+		CurrentMachine:    "mini",
+		DefaultCategories: []string{"tap", "brew", "cask", "vscode"},
+		ExtensionAliases:  map[string]string{"ms-vscode.go": "golang.go"},
+	}
+
+	brewfilePkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "wget"), // uninstalled by hand
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "ms-vscode.go"),
+		brewfile.NewPackage(brewfile.TypeMas, "Xcode"), // mas isn't dumped
+	}
+	installed := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "htop"), // installed but never dumped
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "golang.go"),
+	}
+
+	diff := driftDiff(cfg, brewfilePkgs, installed)
+	assert.Equal(t, []string{"wget"}, diff.Additions.Names())
+	assert.Equal(t, []string{"htop"}, diff.Removals.Names())
+
+	// The drift result feeds the same two columns as a source diff
+	m := NewDiffModel(cfg)
+	m.mode = DiffModeDrift
+	m.Update(diffLoadedMsg{loadID: m.loadID, additions: diff.Additions, removals: diff.Removals})
+
+	require.False(t, m.loading)
+	assert.Equal(t, []string{"wget"}, itemNames(m.addItems))
+	assert.Equal(t, []string{"htop"}, itemNames(m.remItems))
+
+	view := m.ViewContent(100, 20)
+	assert.Contains(t, view, "Drift: mini Brewfile ↔ installed")
+	assert.Contains(t, view, "NOT INSTALLED (+1)")
+	assert.Contains(t, view, "UNTRACKED (-1)")
+}
+
+func TestDiffModel_ToggleDrift(t *testing.T) {
+	m := NewDiffModel(&config.Config{CurrentMachine: "mini"})
+	m.Update(diffLoadedMsg{loadID: m.loadID, additions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}})
+	require.Len(t, m.addItems, 2)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, DiffModeDrift, m.mode)
+	assert.True(t, m.loading)
+	assert.Empty(t, m.addItems) // Source results are not shown as drift
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Equal(t, DiffModeSource, m.mode)
+}
+
+func TestDiffModel_Summary(t *testing.T) {
+	cfg := &config.Config{CurrentMachine: "mini", DefaultSource: "air"}
+	m := NewDiffModel(cfg)
+	additions := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		brewfile.NewPackage(brewfile.TypeVSCode, "golang.go"),
+	}
+	removals := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "wget"),
+		brewfile.NewPackage(brewfile.TypeMas, "Xcode"),
+	}
+	m.Update(diffLoadedMsg{loadID: m.loadID, additions: additions, removals: removals})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	require.True(t, m.summary)
+
+	// The summary counts match the packages listed in the detailed columns
+	for _, items := range [][]groupItem{m.addItems, m.remItems} {
+		listed := make(map[brewfile.PackageType]int)
+		for _, item := range items {
+			if !item.isHeader {
+				listed[item.pkg.Type]++
+			}
+		}
+		assert.Equal(t, listed, summaryCounts(items))
+	}
+	assert.Equal(t, map[brewfile.PackageType]int{brewfile.TypeBrew: 2, brewfile.TypeCask: 1, brewfile.TypeVSCode: 1}, summaryCounts(m.addItems))
+
+	view := m.ViewContent(100, 30)
+	assert.Contains(t, view, "+2")
+	assert.Contains(t, view, "+4")
+	assert.Contains(t, view, "-2")
+	assert.NotContains(t, view, "firefox")
+
+	// Package actions are off in the summary; s returns to the columns
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	assert.Nil(t, cmd)
+	assert.False(t, m.confirm.Active())
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.Contains(t, m.ViewContent(100, 30), "firefox")
+}
+
+func TestDiffModel_Outdated(t *testing.T) {
+	m := NewDiffModel(&config.Config{CurrentMachine: "mini", DefaultSource: "air"})
+	m.SetSize(100, 30)
+	var outdated []brewfile.Outdated
+	for _, name := range []string{"git", "jq", "node", "python", "ruby", "wget", "zsh"} {
+		outdated = append(outdated, brewfile.Outdated{Package: brewfile.NewPackage(brewfile.TypeBrew, name), Installed: "1.0", Latest: "1.1"})
+	}
+
+	// Outdated packages show even when the machines are otherwise in sync
+	m.Update(diffLoadedMsg{loadID: m.loadID, outdated: outdated[:1]})
+	view := m.ViewContent(100, 30)
+	assert.Contains(t, view, "Machines are in sync!")
+	assert.Contains(t, view, "OUTDATED (~1)")
+	assert.Contains(t, view, "~ git 1.0 → 1.1")
+
+	// A long list is capped below the columns, which give up the space
+	m.Update(diffLoadedMsg{loadID: m.loadID, additions: brewfile.Packages{brewfile.NewPackage(brewfile.TypeCask, "firefox")}, outdated: outdated})
+	view = m.ViewContent(100, 30)
+	assert.Contains(t, view, "firefox")
+	assert.Contains(t, view, "OUTDATED (~7)")
+	assert.Contains(t, view, "~ ruby")
+	assert.NotContains(t, view, "~ wget")
+	assert.Contains(t, view, "and 2 more")
+	assert.Equal(t, 30-4-8, m.getColumnHeight())
+
+	// Drift compares against installed packages, so the section goes away
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	assert.Empty(t, m.outdated)
+}
+
+func TestDiffModel_CollapseGroupPerColumn(t *testing.T) {
+	m := NewDiffModel(&config.Config{CurrentMachine: "mini"})
+	m.Update(diffLoadedMsg{loadID: m.loadID,
+		additions: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeBrew, "git"),
+			brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+			brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		},
+		removals: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeBrew, "htop"),
+		},
+	})
+	collapse := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")}
+
+	// Fold brew in the removals column; additions keep theirs
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	m.Update(collapse)
+	assert.Empty(t, itemNames(m.remItems))
+	assert.Equal(t, []string{"git", "jq", "firefox"}, itemNames(m.addItems))
+	assert.Equal(t, 0, m.remCursor)
+
+	// In additions, the cursor lands on the folded header and can move past it
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m.addCursor = 2 // jq
+	m.Update(collapse)
+	assert.Equal(t, []string{"firefox"}, itemNames(m.addItems))
+	assert.Equal(t, 0, m.addCursor)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	require.NotNil(t, m.getCurrentPackage())
+	assert.Equal(t, "firefox", m.getCurrentPackage().Name)
+
+	// Space on the header unfolds it
+	m.addCursor = 0
+	m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	assert.Equal(t, []string{"git", "jq", "firefox"}, itemNames(m.addItems))
+}
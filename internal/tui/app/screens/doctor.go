@@ -132,6 +132,10 @@ func (m *DoctorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.checks = msg.checks
 		return m, nil
 
+	case RefreshMsg:
+		m.loading = true
+		return m, m.Init()
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc", "b"))):
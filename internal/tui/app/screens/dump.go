@@ -1,19 +1,28 @@
 package screens
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/dump"
+	"github.com/asamgx/brewsync/internal/exec"
+	"github.com/asamgx/brewsync/internal/hooks"
 	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
+	"github.com/asamgx/brewsync/pkg/version"
 )
 
 // DumpModel is the model for the dump screen
@@ -29,6 +38,8 @@ type DumpModel struct {
 	err      error
 	counts   map[string]int
 	total    int
+	warnings []string
+	confirm  components.Confirm // Asks before overwriting manual changes
 }
 
 // NewDumpModel creates a new dump model
@@ -45,6 +56,7 @@ func NewDumpModel(cfg *config.Config) *DumpModel {
 		step:    "Initializing...",
 		steps:   []string{},
 		counts:  make(map[string]int),
+		confirm: components.NewConfirm("overwrite-edits"),
 	}
 }
 
@@ -53,9 +65,10 @@ type dumpStepMsg struct {
 }
 
 type dumpCompleteMsg struct {
-	counts map[string]int
-	total  int
-	err    error
+	counts   map[string]int
+	total    int
+	warnings []string
+	err      error
 }
 
 // Init initializes the dump model
@@ -64,114 +77,185 @@ func (m *DumpModel) Init() tea.Cmd {
 	return nil
 }
 
-func (m *DumpModel) runDump() tea.Cmd {
-	return func() tea.Msg {
-		if m.config == nil {
-			return dumpCompleteMsg{err: fmt.Errorf("no config loaded")}
-		}
+// start runs the dump, overwriting manual changes to the Brewfile only when
+// overwriteEdits is set
+func (m *DumpModel) start(overwriteEdits bool) tea.Cmd {
+	m.started = true
+	m.step = "Starting dump..."
+	cfg := m.config
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return dumpBrewfile(cfg, overwriteEdits)
+	})
+}
 
-		machine, ok := m.config.GetCurrentMachine()
-		if !ok {
-			return dumpCompleteMsg{err: fmt.Errorf("current machine not configured")}
-		}
+// editedSinceDump reports whether the current machine's Brewfile was changed
+// after the last dump, which the dump would overwrite
+func (m *DumpModel) editedSinceDump() bool {
+	if m.config == nil {
+		return false
+	}
+	machine, ok := m.config.GetCurrentMachine()
+	if !ok {
+		return false
+	}
+	edited, err := brewfile.EditedSinceDump(machine.Brewfile)
+	if err != nil {
+		debug.Log("DumpModel: could not check %s for manual edits: %v", machine.Brewfile, err)
+	}
+	return edited
+}
 
-		brewfilePath := machine.Brewfile
-		if brewfilePath == "" {
-			return dumpCompleteMsg{err: fmt.Errorf("no Brewfile path configured")}
-		}
+// collectPackages collects the installed packages to dump; a variable so
+// tests can stub it
+var collectPackages = collectAllPackages
+
+// errEditedSinceDump is returned by dumpBrewfile when the Brewfile was changed
+// after the last dump and overwriting it wasn't asked for
+var errEditedSinceDump = errors.New("the Brewfile was modified after the last dump")
+
+// dumpBrewfile collects the installed packages and writes the current
+// machine's Brewfile and dump metadata, as the dump screen and the
+// dashboard's quick dump do, between the pre_dump and post_dump hooks.
+// Unless overwriteEdits is set it refuses with errEditedSinceDump to
+// overwrite a Brewfile changed since the last dump.
+func dumpBrewfile(cfg *config.Config, overwriteEdits bool) dumpCompleteMsg {
+	if cfg == nil {
+		return dumpCompleteMsg{err: fmt.Errorf("no config loaded")}
+	}
 
-		// Ensure directory exists
-		dir := filepath.Dir(brewfilePath)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return dumpCompleteMsg{err: fmt.Errorf("failed to create directory: %w", err)}
-		}
+	machine, ok := cfg.GetCurrentMachine()
+	if !ok {
+		return dumpCompleteMsg{err: fmt.Errorf("current machine not configured")}
+	}
 
-		// Collect all packages
-		allPackages, err := collectAllPackages(m.config, brewfilePath)
-		if err != nil {
-			return dumpCompleteMsg{err: err}
+	brewfilePath := machine.Brewfile
+	if brewfilePath == "" {
+		return dumpCompleteMsg{err: fmt.Errorf("no Brewfile path configured")}
+	}
+	if !overwriteEdits {
+		if edited, err := brewfile.EditedSinceDump(brewfilePath); err != nil {
+			debug.Log("dumpBrewfile: could not check %s for manual edits: %v", brewfilePath, err)
+		} else if edited {
+			return dumpCompleteMsg{err: errEditedSinceDump}
 		}
+	}
 
-		// Write Brewfile
-		writer := brewfile.NewWriter(allPackages)
-		if err := writer.Write(brewfilePath); err != nil {
-			return dumpCompleteMsg{err: fmt.Errorf("failed to write Brewfile: %w", err)}
-		}
+	// Ensure directory exists
+	dir := filepath.Dir(brewfilePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return dumpCompleteMsg{err: fmt.Errorf("failed to create directory: %w", err)}
+	}
 
-		// Count by type
-		counts := make(map[string]int)
-		for _, pkg := range allPackages {
-			counts[string(pkg.Type)]++
-		}
+	if err := runHooks(cfg, brewfilePath, hooks.PreDump); err != nil {
+		return dumpCompleteMsg{err: fmt.Errorf("%w; dump aborted", err)}
+	}
 
-		return dumpCompleteMsg{
-			counts: counts,
-			total:  len(allPackages),
-		}
+	// Collect all packages
+	allPackages, failures, warnings, err := collectPackages(cfg, brewfilePath)
+	if err != nil {
+		return dumpCompleteMsg{err: err}
 	}
-}
+	allPackages, kept, err := keepFailedCategories(brewfilePath, allPackages, failures)
+	if err != nil {
+		return dumpCompleteMsg{err: err}
+	}
+	warnings = append(warnings, kept...)
 
-// collectAllPackages collects all installed packages
-func collectAllPackages(cfg *config.Config, brewfilePath string) (brewfile.Packages, error) {
-	var allPackages brewfile.Packages
-	brewInst := installer.NewBrewInstaller()
+	// Write Brewfile
+	writer := brewfile.NewWriter(allPackages).PreserveComments(cfg.Dump.PreserveComments)
+	if cfg.Dump.Header {
+		writer.WithHeader(brewfile.Header(cfg.CurrentMachine, time.Now()))
+	}
+	if err := writer.Write(brewfilePath); err != nil {
+		return dumpCompleteMsg{err: fmt.Errorf("failed to write Brewfile: %w", err)}
+	}
 
-	// Use brew bundle dump if configured (default), otherwise collect manually
-	if cfg.Dump.UseBrewBundle && brewInst.IsAvailable() {
-		// Create temp file for brew bundle dump
-		tmpFile := brewfilePath + ".brewbundle.tmp"
-		if err := brewInst.DumpToFile(tmpFile); err == nil {
-			// Parse the brew bundle output (includes taps, formulae, casks with descriptions)
-			if brewPkgs, err := brewfile.Parse(tmpFile); err == nil {
-				allPackages = append(allPackages, brewPkgs...)
-			}
-			os.Remove(tmpFile)
-		}
-	} else if brewInst.IsAvailable() {
-		// Manual collection
-		if taps, err := brewInst.ListTaps(); err == nil {
-			allPackages = append(allPackages, taps...)
-		}
-		if formulae, err := brewInst.ListFormulae(); err == nil {
-			allPackages = append(allPackages, formulae...)
-		}
-		if casks, err := brewInst.ListCasks(); err == nil {
-			allPackages = append(allPackages, casks...)
-		}
+	// Record the dump (last dump time, counts and count history)
+	if err := brewfile.UpdateMetadata(brewfile.MetadataPath(brewfilePath), cfg.CurrentMachine, allPackages, version.Version); err != nil {
+		debug.Log("dumpBrewfile: failed to update metadata: %v", err)
 	}
 
-	// Collect extensions
-	if vscodeInst := installer.NewVSCodeInstaller(); vscodeInst.IsAvailable() {
-		if extensions, err := vscodeInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
+	// The Brewfile is written; a failing post_dump hook is only a warning
+	if err := runHooks(cfg, brewfilePath, hooks.PostDump); err != nil {
+		warnings = append(warnings, err.Error())
 	}
 
-	if cursorInst := installer.NewCursorInstaller(); cursorInst.IsAvailable() {
-		if extensions, err := cursorInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
+	stats := allPackages.Stats()
+	return dumpCompleteMsg{
+		counts:   stats.Counts(),
+		total:    stats.Total,
+		warnings: warnings,
 	}
+}
 
-	if antigravityInst := installer.NewAntigravityInstaller(); antigravityInst.IsAvailable() {
-		if extensions, err := antigravityInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
+// runHooks runs the configured hooks among names in order, logging their
+// output, and stops at the first one that fails
+func runHooks(cfg *config.Config, brewfilePath string, names ...string) error {
+	env := hooks.Env(cfg.CurrentMachine, brewfilePath)
+	for _, hook := range hooks.Resolve(cfg.Hooks, env, names...) {
+		debug.Log("runHooks: running %s hook: %s", hook.Name, hook.Command)
+		err := hooks.Run(exec.Default, hook, env, func(line string) {
+			debug.Log("runHooks: %s: %s", hook.Name, line)
+		})
+		if err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	if goInst := installer.NewGoToolsInstaller(); goInst.IsAvailable() {
-		if tools, err := goInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(tools...)
-		}
+// bundleFallbackWarning is shown when Homebrew packages were collected with
+// 'brew list' because 'brew bundle' isn't available
+const bundleFallbackWarning = "'brew bundle' is not available; collected Homebrew packages with 'brew list'"
+
+// collectAllPackages collects all installed packages of the enabled categories.
+// Installers that fail to list their packages are returned in failures, and
+// anything the user should know about the collection in warnings.
+func collectAllPackages(cfg *config.Config, brewfilePath string) (brewfile.Packages, dump.Failures, []string, error) {
+	var allPackages brewfile.Packages
+	brewInst := installer.NewBrewInstaller()
+	if err := brewInst.CheckAvailable(brewfile.EnabledTypes(cfg.CategoryEnabled)...); err != nil {
+		return nil, nil, nil, err
 	}
 
-	if masInst := installer.NewMasInstaller(); masInst.IsAvailable() {
-		if apps, err := masInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(apps...)
-		}
+	// Use brew bundle dump if configured (default), otherwise collect manually
+	brewPkgs, fellBack, err := brewInst.CollectPackages(cfg.UseBrewBundle(cfg.CurrentMachine), brewfilePath+".brewbundle.tmp")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var warnings []string
+	if fellBack {
+		debug.Log("collectAllPackages: brew bundle unavailable, fell back to brew list")
+		warnings = append(warnings, bundleFallbackWarning)
 	}
+	brewPkgs = brewPkgs.Filter(brewfile.EnabledTypes(cfg.CategoryEnabled)...)
+	// brew list has no descriptions; look them up like brew bundle --describe would
+	if installer.NeedsDescriptions(cfg, fellBack) {
+		brewInst.FillDescriptions(brewPkgs, installer.DescriptionCachePath())
+	}
+	allPackages = append(allPackages, brewPkgs...)
+
+	allPackages, failures := dump.CollectExtra(cfg, allPackages)
+	return allPackages, failures, warnings, nil
+}
 
-	return allPackages, nil
+// keepFailedCategories keeps the existing Brewfile entries of each category
+// whose installer failed to list its packages (see dump.KeepFailed) and
+// describes each failure
+func keepFailedCategories(path string, pkgs brewfile.Packages, failures dump.Failures) (brewfile.Packages, []string, error) {
+	pkgs, failed, err := dump.KeepFailed(path, pkgs, failures, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var warnings []string
+	for _, f := range failed {
+		if f.Kept {
+			warnings = append(warnings, fmt.Sprintf("Could not list %s packages (%v); kept its %d existing entries", f.Type, f.Err, f.Existing))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("Could not list %s packages: %v", f.Type, f.Err))
+		}
+	}
+	return pkgs, warnings, nil
 }
 
 // Update handles messages
@@ -199,6 +283,7 @@ func (m *DumpModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.done = true
 		m.counts = msg.counts
 		m.total = msg.total
+		m.warnings = msg.warnings
 		m.err = msg.err
 		if m.err == nil {
 			m.steps = append(m.steps, m.step)
@@ -206,14 +291,27 @@ func (m *DumpModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case components.ConfirmResultMsg:
+		if msg.ID == m.confirm.ID() && msg.Confirmed {
+			return m, m.start(true)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
+		}
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("enter", "d"))):
 			// Start dump if not started, or go back if done
 			if !m.started {
-				m.started = true
-				m.step = "Starting dump..."
-				return m, tea.Batch(m.spinner.Tick, m.runDump())
+				if m.editedSinceDump() {
+					prompt := lipgloss.NewStyle().Foreground(styles.CatRed).Bold(true).
+						Render("The Brewfile was modified after the last dump. Overwrite the manual changes?")
+					m.confirm.Open(prompt, styles.CatRed)
+					return m, nil
+				}
+				return m, m.start(false)
 			}
 			if m.done {
 				return m, func() tea.Msg { return Navigate("dashboard") }
@@ -249,7 +347,11 @@ func (m *DumpModel) ViewContent(width, height int) string {
 		b.WriteString("  • VSCode and Cursor extensions\n")
 		b.WriteString("  • Go tools\n")
 		b.WriteString("  • Mac App Store apps\n\n")
-		b.WriteString(styles.SelectedStyle.Render("Press Enter or 'd' to start dump"))
+		if m.confirm.Active() {
+			b.WriteString(m.confirm.View())
+		} else {
+			b.WriteString(styles.SelectedStyle.Render("Press Enter or 'd' to start dump"))
+		}
 		return b.String()
 	}
 
@@ -276,6 +378,13 @@ func (m *DumpModel) ViewContent(width, height int) string {
 		b.WriteString("\n\n")
 		b.WriteString(styles.SelectedStyle.Render(fmt.Sprintf("✓ Dumped %d packages to Brewfile", m.total)))
 		b.WriteString("\n\n")
+		for _, warning := range m.warnings {
+			b.WriteString(styles.WarningStyle.Render("⚠ " + warning))
+			b.WriteString("\n")
+		}
+		if len(m.warnings) > 0 {
+			b.WriteString("\n")
+		}
 		b.WriteString("Press Enter to return to dashboard")
 	}
 
@@ -284,23 +393,9 @@ func (m *DumpModel) ViewContent(width, height int) string {
 
 func (m *DumpModel) renderCounts() string {
 	var parts []string
-	types := []struct {
-		name string
-		icon string
-	}{
-		{"tap", "🚰"},
-		{"brew", "🍺"},
-		{"cask", "📦"},
-		{"vscode", "💻"},
-		{"cursor", "✏️"},
-		{"antigravity", "🚀"},
-		{"go", "🔷"},
-		{"mas", "🍎"},
-	}
-
-	for _, t := range types {
-		if count, ok := m.counts[t.name]; ok && count > 0 {
-			parts = append(parts, fmt.Sprintf("%s %s: %d", t.icon, t.name, count))
+	for _, t := range brewfile.AllTypes() {
+		if count, ok := m.counts[string(t)]; ok && count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s: %d", brewfile.Meta(t).Icon, t, count))
 		}
 	}
 
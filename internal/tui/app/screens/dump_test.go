@@ -0,0 +1,98 @@
+package screens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/dump"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
+)
+
+func TestDumpModel_EditedSinceDump(t *testing.T) {
+	original := collectPackages
+	collectPackages = func(cfg *config.Config, path string) (brewfile.Packages, dump.Failures, []string, error) {
+		return brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}, nil, nil, nil
+	}
+	t.Cleanup(func() { collectPackages = original })
+
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"wget\" # added by hand\n"), 0644))
+	require.NoError(t, brewfile.SaveMetadata(brewfile.MetadataPath(brewfilePath), &brewfile.Metadata{LastDump: time.Now().Add(-time.Hour)}))
+	cfg := &config.Config{
+		Machines:       map[string]config.Machine{"mini": {Brewfile: brewfilePath}},
+		CurrentMachine: "mini",
+	}
+
+	// Without being asked to, the dump leaves the hand-edited Brewfile alone
+	result := dumpBrewfile(cfg, false)
+	require.ErrorIs(t, result.err, errEditedSinceDump)
+	data, err := os.ReadFile(brewfilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "wget")
+
+	// The dump screen asks first
+	m := NewDumpModel(cfg)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+	assert.False(t, m.started)
+	assert.True(t, m.confirm.Active())
+	assert.Contains(t, m.View(), "Overwrite the manual changes?")
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	require.NotNil(t, cmd)
+	assert.Equal(t, components.ConfirmResultMsg{ID: "overwrite-edits", Confirmed: true}, cmd())
+	_, cmd = m.Update(cmd())
+	assert.NotNil(t, cmd)
+	assert.True(t, m.started)
+
+	result = dumpBrewfile(cfg, true)
+	require.NoError(t, result.err)
+	pkgs, err := brewfile.Parse(brewfilePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew:git"}, pkgs.IDs())
+}
+
+func TestDumpBrewfile_Hooks(t *testing.T) {
+	original := collectPackages
+	collectPackages = func(cfg *config.Config, path string) (brewfile.Packages, dump.Failures, []string, error) {
+		return brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}, nil, nil, nil
+	}
+	t.Cleanup(func() { collectPackages = original })
+
+	dir := t.TempDir()
+	brewfilePath := filepath.Join(dir, "Brewfile")
+	log := filepath.Join(dir, "hooks.log")
+	cfg := &config.Config{
+		Machines:       map[string]config.Machine{"mini": {Brewfile: brewfilePath}},
+		CurrentMachine: "mini",
+		Hooks: config.HooksConfig{
+			PreDump:  "echo pre $BREWSYNC_MACHINE >> " + log,
+			PostDump: "echo post >> " + log + "; exit 1",
+		},
+	}
+
+	// A failing post_dump hook is a warning; the Brewfile is written
+	result := dumpBrewfile(cfg, false)
+	require.NoError(t, result.err)
+	assert.Equal(t, []string{"post_dump hook failed: exit status 1"}, result.warnings)
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "pre mini\npost\n", string(data))
+	assert.FileExists(t, brewfilePath)
+
+	// A failing pre_dump hook aborts the dump
+	require.NoError(t, os.Remove(brewfilePath))
+	cfg.Hooks.PreDump = "exit 2"
+	result = dumpBrewfile(cfg, false)
+	require.Error(t, result.err)
+	assert.Contains(t, result.err.Error(), "pre_dump hook failed")
+	assert.NoFileExists(t, brewfilePath)
+}
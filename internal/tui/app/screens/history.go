@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -57,8 +58,15 @@ func (m *HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		m.entries = msg.entries
 		m.err = msg.err
+		if m.cursor >= len(m.entries) {
+			m.cursor = 0
+		}
 		return m, nil
 
+	case RefreshMsg:
+		m.loading = true
+		return m, m.Init()
+
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc", "b"))):
@@ -117,10 +125,40 @@ func (m *HistoryModel) ViewContent(width, height int) string {
 		}
 
 		// Format entry
-		line := prefix + entry.Format(false)
+		line := prefix + fmt.Sprintf("#%-4d ", entry.ID) + entry.Format(false)
 		b.WriteString(line)
 		b.WriteString("\n")
 	}
 
+	// What the selected sync changed, and how to reverse it
+	if m.cursor < len(m.entries) {
+		b.WriteString(renderSyncChanges(m.entries[m.cursor]))
+	}
+
+	return b.String()
+}
+
+// renderSyncChanges lists the packages a sync entry installed and removed,
+// or returns "" for other entries
+func renderSyncChanges(entry history.Entry) string {
+	changes, ok := entry.SyncChanges()
+	if !ok || len(changes.Added)+len(changes.Removed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("Sync #%d from %s", entry.ID, changes.Source)))
+	b.WriteString("\n")
+	for _, id := range changes.Added {
+		b.WriteString(styles.AddedStyle.Render("  + " + id))
+		b.WriteString("\n")
+	}
+	for _, id := range changes.Removed {
+		b.WriteString(styles.RemovedStyle.Render("  - " + id))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("Undo with: brewsync history undo %d", entry.ID)))
+	b.WriteString("\n")
 	return b.String()
 }
@@ -0,0 +1,31 @@
+package screens
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/asamgx/brewsync/internal/history"
+)
+
+func TestHistoryModel_ShowsSyncChanges(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := NewHistoryModel(nil)
+	m.Update(historyLoadedMsg{entries: []history.Entry{
+		{ID: 4, Timestamp: at, Operation: history.OpSync, Machine: "mini", Details: "←air;+brew:jq;-cask:zoom", Summary: "+1 -1"},
+		{ID: 2, Timestamp: at, Operation: history.OpDump, Machine: "mini", Details: "brew:3", Summary: "dumped"},
+	}})
+
+	view := m.ViewContent(80, 24)
+	assert.Contains(t, view, "#4")
+	assert.Contains(t, view, "Sync #4 from air")
+	assert.Contains(t, view, "+ brew:jq")
+	assert.Contains(t, view, "- cask:zoom")
+	assert.Contains(t, view, "brewsync history undo 4")
+
+	// A dump has nothing to undo
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.NotContains(t, m.ViewContent(80, 24), "history undo")
+}
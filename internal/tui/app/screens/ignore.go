@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
@@ -32,6 +33,7 @@ const (
 // ignoreItem represents an item in the ignore list
 type ignoreItem struct {
 	value    string
+	reason   string // Why a package is ignored, if recorded
 	isGlobal bool
 }
 
@@ -58,21 +60,22 @@ type IgnoreModel struct {
 	typeMenuIdx  int
 
 	// Confirmation
-	showConfirm   bool
+	confirm       components.Confirm
 	confirmAction string
 	confirmItem   ignoreItem
 
 	// Status
 	loading       bool
+	loadID        uint64 // Generation of the latest load
 	statusMessage string
 	statusType    string // "success", "error"
 }
 
 // Available package types for adding
-var packageTypes = []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"}
+var packageTypes = []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "npm", "pipx", "mas"}
 
 // Available categories (same as package types)
-var categoryTypes = []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"}
+var categoryTypes = []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "npm", "pipx", "mas"}
 
 // NewIgnoreModel creates a new ignore model
 func NewIgnoreModel(cfg *config.Config) *IgnoreModel {
@@ -87,12 +90,14 @@ func NewIgnoreModel(cfg *config.Config) *IgnoreModel {
 		height:    24,
 		loading:   true,
 		textInput: ti,
+		confirm:   components.NewConfirm("delete-ignore"),
 	}
 }
 
 type ignoreLoadedMsg struct {
 	categories []ignoreItem
 	packages   []ignoreItem
+	loadID     uint64
 }
 
 type ignoreActionMsg struct {
@@ -102,10 +107,21 @@ type ignoreActionMsg struct {
 
 // Init initializes the ignore model
 func (m *IgnoreModel) Init() tea.Cmd {
-	return m.loadIgnores
+	return m.load()
 }
 
-func (m *IgnoreModel) loadIgnores() tea.Msg {
+// load starts a background reload of the ignore lists
+func (m *IgnoreModel) load() tea.Cmd {
+	id := nextLoadID()
+	m.loadID = id
+	return func() tea.Msg {
+		msg := m.loadIgnores()
+		msg.loadID = id
+		return msg
+	}
+}
+
+func (m *IgnoreModel) loadIgnores() ignoreLoadedMsg {
 	result := ignoreLoadedMsg{
 		categories: []ignoreItem{},
 		packages:   []ignoreItem{},
@@ -131,17 +147,15 @@ func (m *IgnoreModel) loadIgnores() tea.Msg {
 	}
 
 	// Get global packages
-	globalPkgs := flattenPackageList(&ignoreFile.Global.Packages)
-	for _, pkg := range globalPkgs {
-		result.packages = append(result.packages, ignoreItem{value: pkg, isGlobal: true})
+	for _, pkg := range ignoreFile.Global.Packages.All() {
+		result.packages = append(result.packages, ignoreItem{value: pkg.ID, reason: pkg.Reason, isGlobal: true})
 	}
 
 	// Get machine-specific packages
 	if m.config != nil {
 		if machineIgnore, ok := ignoreFile.Machines[m.config.CurrentMachine]; ok {
-			machinePkgs := flattenPackageList(&machineIgnore.Packages)
-			for _, pkg := range machinePkgs {
-				result.packages = append(result.packages, ignoreItem{value: pkg, isGlobal: false})
+			for _, pkg := range machineIgnore.Packages.All() {
+				result.packages = append(result.packages, ignoreItem{value: pkg.ID, reason: pkg.Reason, isGlobal: false})
 			}
 		}
 	}
@@ -149,36 +163,6 @@ func (m *IgnoreModel) loadIgnores() tea.Msg {
 	return result
 }
 
-// flattenPackageList converts PackageIgnoreList to a flat list of "type:name" strings
-func flattenPackageList(list *config.PackageIgnoreList) []string {
-	var result []string
-	for _, v := range list.Tap {
-		result = append(result, "tap:"+v)
-	}
-	for _, v := range list.Brew {
-		result = append(result, "brew:"+v)
-	}
-	for _, v := range list.Cask {
-		result = append(result, "cask:"+v)
-	}
-	for _, v := range list.VSCode {
-		result = append(result, "vscode:"+v)
-	}
-	for _, v := range list.Cursor {
-		result = append(result, "cursor:"+v)
-	}
-	for _, v := range list.Antigravity {
-		result = append(result, "antigravity:"+v)
-	}
-	for _, v := range list.Go {
-		result = append(result, "go:"+v)
-	}
-	for _, v := range list.Mas {
-		result = append(result, "mas:"+v)
-	}
-	return result
-}
-
 // Update handles messages
 func (m *IgnoreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -188,11 +172,20 @@ func (m *IgnoreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case ignoreLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil // Superseded by a newer load
+		}
 		m.loading = false
 		m.categories = msg.categories
 		m.packages = msg.packages
 		return m, nil
 
+	case components.ConfirmResultMsg:
+		if msg.ID == m.confirm.ID() && msg.Confirmed {
+			return m, m.executeDelete()
+		}
+		return m, nil
+
 	case ignoreActionMsg:
 		if msg.success {
 			m.statusMessage = msg.message
@@ -202,15 +195,15 @@ func (m *IgnoreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusType = "error"
 		}
 		// Reload data
-		return m, m.loadIgnores
+		return m, m.load()
 
 	case tea.KeyMsg:
 		// Clear status message on any key
 		m.statusMessage = ""
 
 		// Handle confirmation dialog
-		if m.showConfirm {
-			return m.handleConfirmInput(msg)
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
 		}
 
 		// Handle type menu selection
@@ -230,17 +223,6 @@ func (m *IgnoreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *IgnoreModel) handleConfirmInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		m.showConfirm = false
-		return m, m.executeDelete()
-	case "n", "N", "esc":
-		m.showConfirm = false
-	}
-	return m, nil
-}
-
 func (m *IgnoreModel) handleTypeMenuInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	types := categoryTypes
 	if m.inputType == "package" {
@@ -359,7 +341,7 @@ func (m *IgnoreModel) handleNormalInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if item != nil {
 			m.confirmItem = *item
 			m.confirmAction = "delete"
-			m.showConfirm = true
+			m.confirm.Open(m.deletePrompt(), styles.CatRed)
 		}
 	}
 
@@ -565,9 +547,9 @@ func (m *IgnoreModel) ViewContent(width, height int) string {
 	} else if m.inputMode {
 		b.WriteString("\n")
 		b.WriteString(m.renderTextInput())
-	} else if m.showConfirm {
+	} else if m.confirm.Active() {
 		b.WriteString("\n")
-		b.WriteString(m.renderConfirmDialog())
+		b.WriteString(m.confirm.View())
 	} else {
 		// Help line
 		b.WriteString("\n")
@@ -596,7 +578,7 @@ func (m *IgnoreModel) renderSection(title string, items []ignoreItem, width, vis
 		headerStyle = headerStyle.Foreground(styles.CatMauve).Underline(true)
 	}
 	lines = append(lines, headerStyle.Render(fmt.Sprintf("%s (%d)", title, len(items))))
-	lines = append(lines, styles.DimmedStyle.Render(strings.Repeat("─", width-2)))
+	lines = append(lines, styles.DimmedStyle.Render(strings.Repeat("─", max(width-2, 0))))
 
 	if len(items) == 0 {
 		lines = append(lines, styles.DimmedStyle.Render("  (none)"))
@@ -642,12 +624,15 @@ func (m *IgnoreModel) renderSection(title string, items []ignoreItem, width, vis
 
 		// Truncate if needed
 		maxLen := width - 15
-		value := item.value
-		if len(value) > maxLen {
-			value = value[:maxLen-3] + "..."
-		}
+		value := truncate(item.value, maxLen)
 
 		line := prefix + valueStyle.Render(value) + scopeLabel
+		if item.reason != "" {
+			reasonWidth := width - lipgloss.Width(line) - 6
+			if reasonWidth > 10 {
+				line += styles.DimmedStyle.Render(" — " + truncate(item.reason, reasonWidth))
+			}
+		}
 		lines = append(lines, line)
 	}
 
@@ -713,21 +698,15 @@ func (m *IgnoreModel) renderTextInput() string {
 	return b.String()
 }
 
-func (m *IgnoreModel) renderConfirmDialog() string {
-	var b strings.Builder
-
+// deletePrompt asks whether to remove confirmItem from the ignore list
+func (m *IgnoreModel) deletePrompt() string {
 	scopeLabel := "global"
 	if !m.confirmItem.isGlobal {
 		scopeLabel = "machine"
 	}
 
-	confirmStyle := lipgloss.NewStyle().
-		Background(styles.CatSurface0).
-		Foreground(styles.CatYellow).
-		Padding(0, 1).
-		Bold(true)
-
-	b.WriteString(confirmStyle.Render(fmt.Sprintf("Delete '%s' (%s)? (y/n)", m.confirmItem.value, scopeLabel)))
-
-	return b.String()
+	actionStyle := lipgloss.NewStyle().Foreground(styles.CatRed).Bold(true)
+	valueStyle := lipgloss.NewStyle().Foreground(styles.CatMauve).Bold(true)
+	return actionStyle.Render("Delete") + " " + valueStyle.Render(m.confirmItem.value) +
+		styles.DimmedStyle.Render(fmt.Sprintf(" (%s)", scopeLabel)) + "?"
 }
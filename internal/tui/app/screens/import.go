@@ -9,6 +9,7 @@ import (
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
 	"github.com/asamgx/brewsync/internal/tui/selection"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
@@ -94,7 +95,14 @@ func (m *ImportModel) Init() tea.Cmd {
 		}
 
 		// Get packages to import (in source but not in current)
-		diff := brewfile.Diff(sourcePkgs, currentPkgs)
+		diff := brewfile.DiffWithAliases(sourcePkgs, currentPkgs, m.config.ExtensionAliases)
+
+		// Don't re-offer what an interrupted import already installed
+		if !(m.config.AutoDump.Enabled && m.config.AutoDump.AfterInstall) {
+			if installed, err := installer.NewManager().ListAll(); err == nil {
+				diff, _ = diff.FilterInstalledAdditions(installed)
+			}
+		}
 		return importLoadedMsg{packages: diff.Additions}
 	}
 }
@@ -212,7 +220,7 @@ func (m *ImportModel) ViewContent(width, height int) string {
 			b.WriteString(styles.SelectedStyle.Render("✓ "))
 			b.WriteString("Already in sync with " + m.source + "!")
 			b.WriteString("\n")
-			b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored packages, press h to show)", len(m.allPackages))))
+			b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored packages, press %s to show)", len(m.allPackages), toggleIgnoredKey)))
 		} else {
 			b.WriteString(styles.SelectedStyle.Render(fmt.Sprintf("✓ Installed %d packages", m.installed)))
 			if m.failed > 0 {
@@ -0,0 +1,122 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyBindings maps keybinding action names (the keys under keybindings: in
+// config.yaml) to the bindings they control
+type KeyBindings map[string]*key.Binding
+
+// toggleIgnoredKey is the global show-ignored key named in screen hints
+var toggleIgnoredKey = "H"
+
+// SetToggleIgnoredKey sets the show-ignored key that screen hints mention
+// ("press H to show"), so they follow the keybindings config
+func SetToggleIgnoredKey(k string) {
+	toggleIgnoredKey = k
+}
+
+// ReservedKeys can't be remapped to actions: the app always handles them itself
+var ReservedKeys = []string{"ctrl+c", "esc", "1", "2", "3", "4", "5", "6", "7", "8", "9", "0"}
+
+// Bindings returns the remappable dashboard bindings by action name
+func (k *DashboardKeyMap) Bindings() KeyBindings {
+	return KeyBindings{
+		"import":     &k.Import,
+		"sync":       &k.Sync,
+		"diff":       &k.Diff,
+		"dump":       &k.Dump,
+		"quick_dump": &k.QuickDump,
+		"list":       &k.List,
+		"ignore":     &k.Ignore,
+		"config":     &k.Config,
+		"history":    &k.History,
+		"profile":    &k.Profile,
+		"doctor":     &k.Doctor,
+		"help":       &k.Help,
+		"quit":       &k.Quit,
+	}
+}
+
+// ApplyKeybindings remaps bindings from config overrides (action -> comma-separated
+// keys) across all sets, then validates the result. Unknown actions, empty or
+// reserved keys, and a key bound to two different actions are errors.
+// An action may appear in several sets (e.g. quit); those bindings are remapped together.
+func ApplyKeybindings(overrides map[string]string, sets ...KeyBindings) error {
+	actions := make([]string, 0, len(overrides))
+	for action := range overrides {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		keys := splitKeys(overrides[action])
+		if len(keys) == 0 {
+			return fmt.Errorf("keybinding %q has no keys", action)
+		}
+		for _, k := range keys {
+			for _, reserved := range ReservedKeys {
+				if k == reserved {
+					return fmt.Errorf("keybinding %q: key %q is reserved", action, k)
+				}
+			}
+		}
+
+		found := false
+		for _, set := range sets {
+			if binding, ok := set[action]; ok {
+				binding.SetKeys(keys...)
+				binding.SetHelp(keys[0], binding.Help().Desc)
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown keybinding action %q", action)
+		}
+	}
+
+	return KeyConflicts(sets...)
+}
+
+// KeyConflicts returns an error if any key is bound to more than one action
+func KeyConflicts(sets ...KeyBindings) error {
+	owner := make(map[string]string)
+	var conflicts []string
+	for _, set := range sets {
+		actions := make([]string, 0, len(set))
+		for action := range set {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+
+		for _, action := range actions {
+			for _, k := range set[action].Keys() {
+				if prev, ok := owner[k]; ok && prev != action {
+					conflicts = append(conflicts, fmt.Sprintf("%q is bound to both %s and %s", k, prev, action))
+					continue
+				}
+				owner[k] = action
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("conflicting keybindings: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// splitKeys parses a comma-separated key list ("y, ctrl+h")
+func splitKeys(value string) []string {
+	var keys []string
+	for _, k := range strings.Split(value, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
@@ -18,23 +19,25 @@ type listItem struct {
 	isHeader    bool
 	headerType  brewfile.PackageType
 	headerCount int
+	collapsed   bool // Header whose packages are hidden
 	pkg         brewfile.Package
 }
 
 // ListModel is the model for the package list screen
 type ListModel struct {
-	config   *config.Config
-	width    int
-	height   int
-	packages brewfile.Packages
-	items    []listItem // Flattened list for navigation
-	cursor   int
-	offset   int // For scrolling
-	loading  bool
-	err      error
+	config    *config.Config
+	width     int
+	height    int
+	packages  brewfile.Packages
+	items     []listItem // Flattened list for navigation
+	collapsed collapsedGroups
+	cursor    int
+	offset    int // For scrolling
+	loading   bool
+	err       error
 
 	// Confirmation dialog
-	showConfirm   bool
+	confirm       components.Confirm
 	confirmAction string // "uninstall"
 	confirmPkg    brewfile.Package
 
@@ -49,6 +52,7 @@ func NewListModel(cfg *config.Config) *ListModel {
 		width:   80,
 		height:  24,
 		loading: true,
+		confirm: components.NewConfirm("package-action"),
 	}
 }
 
@@ -89,6 +93,19 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.buildItems()
 		return m, nil
 
+	case RefreshMsg:
+		if m.confirm.Active() {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.Init()
+
+	case components.ConfirmResultMsg:
+		if msg.ID == m.confirm.ID() && msg.Confirmed {
+			return m, requestPackageAction(m.confirmAction, m.confirmPkg)
+		}
+		return m, nil
+
 	case PackageActionStartMsg:
 		m.taskRunning = true
 		return m, nil
@@ -100,8 +117,8 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		// Handle confirmation dialog
-		if m.showConfirm {
-			return m.handleConfirmInput(msg)
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
 		}
 
 		switch {
@@ -119,6 +136,13 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor = len(m.items) - 1
 				m.adjustOffset()
 			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("z"))):
+			m.toggleCollapse()
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter", " "))):
+			// Enter/space on a header fold it too
+			if m.cursor < len(m.items) && m.items[m.cursor].isHeader {
+				m.toggleCollapse()
+			}
 		case key.Matches(msg, key.NewBinding(key.WithKeys("X"))):
 			// Uninstall current package
 			if !m.taskRunning {
@@ -126,9 +150,14 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if pkg != nil {
 					m.confirmPkg = *pkg
 					m.confirmAction = "uninstall"
-					m.showConfirm = true
+					openPackageActionConfirm(&m.confirm, m.confirmAction, m.confirmPkg)
 				}
 			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("p"))):
+			// Toggle pin on current package
+			if pkg := m.getCurrentPackage(); pkg != nil {
+				return m, m.togglePin(*pkg)
+			}
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
 			return m, func() tea.Msg { return Navigate("dashboard") }
 		}
@@ -137,23 +166,32 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleConfirmInput handles input in the confirmation dialog
-func (m *ListModel) handleConfirmInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		m.showConfirm = false
-		// Send package action message
-		return m, func() tea.Msg {
-			return PackageActionMsg{
-				PkgType: string(m.confirmPkg.Type),
-				PkgName: m.confirmPkg.Name,
-				Action:  m.confirmAction,
-			}
+// togglePin pins or unpins a package and saves the config
+func (m *ListModel) togglePin(pkg brewfile.Package) tea.Cmd {
+	if m.config == nil {
+		return nil
+	}
+
+	var err error
+	pinned := !m.config.IsPinned(pkg.ID())
+	if pinned {
+		_, err = m.config.AddPin(pkg.ID())
+	} else {
+		_, err = m.config.RemovePin(pkg.ID())
+	}
+	if err == nil {
+		err = config.Save(m.config)
+	}
+
+	return func() tea.Msg {
+		if err != nil {
+			return StatusError(fmt.Sprintf("Failed to update pins: %v", err))
+		}
+		if pinned {
+			return StatusSuccess(fmt.Sprintf("Pinned %s", pkg.ID()))
 		}
-	case "n", "N", "esc":
-		m.showConfirm = false
+		return StatusSuccess(fmt.Sprintf("Unpinned %s", pkg.ID()))
 	}
-	return m, nil
 }
 
 // getCurrentPackage returns the package at the current cursor position
@@ -180,6 +218,8 @@ func (m *ListModel) buildItems() {
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
@@ -193,7 +233,11 @@ func (m *ListModel) buildItems() {
 			isHeader:    true,
 			headerType:  t,
 			headerCount: len(pkgs),
+			collapsed:   m.collapsed[t],
 		})
+		if m.collapsed[t] {
+			continue
+		}
 		// Add packages
 		for _, pkg := range pkgs {
 			m.items = append(m.items, listItem{pkg: pkg})
@@ -201,6 +245,28 @@ func (m *ListModel) buildItems() {
 	}
 }
 
+// toggleCollapse folds or unfolds the category under the cursor, leaving the
+// cursor on its header
+func (m *ListModel) toggleCollapse() {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return
+	}
+	t := m.items[m.cursor].pkg.Type
+	if m.items[m.cursor].isHeader {
+		t = m.items[m.cursor].headerType
+	}
+
+	m.collapsed.toggle(t)
+	m.buildItems()
+	for i, item := range m.items {
+		if item.isHeader && item.headerType == t {
+			m.cursor = i
+			break
+		}
+	}
+	m.adjustOffset()
+}
+
 // moveUp moves cursor up
 func (m *ListModel) moveUp() {
 	if m.cursor > 0 {
@@ -266,7 +332,7 @@ func (m *ListModel) ViewContent(width, height int) string {
 
 	visibleHeight := height - 2
 	// Reserve space for confirmation dialog if showing (4 lines: 2 blank + bordered dialog ~2 lines)
-	if m.showConfirm {
+	if m.confirm.Active() {
 		visibleHeight -= 5
 	}
 	if visibleHeight < 1 {
@@ -285,14 +351,13 @@ func (m *ListModel) ViewContent(width, height int) string {
 
 		if item.isHeader {
 			// Type header with icon
-			icon := getTypeIcon(item.headerType)
 			headerStyle := styles.GetCategoryStyle(string(item.headerType)).Bold(true)
 			prefix := "  "
 			if isCursor {
 				prefix = styles.CursorStyle.Render("> ")
 			}
 			b.WriteString(prefix)
-			b.WriteString(headerStyle.Render(fmt.Sprintf("%s %s (%d)", icon, item.headerType, item.headerCount)))
+			b.WriteString(headerStyle.Render(groupHeader(item.headerType, item.headerCount, item.collapsed)))
 		} else {
 			// Package line
 			prefix := "    "
@@ -306,6 +371,9 @@ func (m *ListModel) ViewContent(width, height int) string {
 			}
 
 			line := prefix + nameStyle.Render(item.pkg.Name)
+			if m.config != nil && m.config.IsPinned(item.pkg.ID()) {
+				line += " " + lipgloss.NewStyle().Foreground(styles.CatYellow).Render("📌")
+			}
 
 			if item.pkg.Description != "" {
 				descWidth := width - lipgloss.Width(line) - 6
@@ -327,66 +395,10 @@ func (m *ListModel) ViewContent(width, height int) string {
 	}
 
 	// Confirmation dialog overlay
-	if m.showConfirm {
+	if m.confirm.Active() {
 		b.WriteString("\n\n")
-		b.WriteString(m.renderConfirmDialog())
+		b.WriteString(m.confirm.View())
 	}
 
 	return b.String()
 }
-
-// renderConfirmDialog renders the confirmation dialog
-func (m *ListModel) renderConfirmDialog() string {
-	actionLabel := "Uninstall"
-	actionColor := styles.CatRed
-	if m.confirmAction == "install" {
-		actionLabel = "Install"
-		actionColor = styles.CatGreen
-	}
-
-	// Build dialog content
-	actionStyle := lipgloss.NewStyle().Foreground(actionColor).Bold(true)
-	pkgStyle := lipgloss.NewStyle().Foreground(styles.CatMauve).Bold(true)
-	promptStyle := lipgloss.NewStyle().Foreground(styles.CatYellow)
-
-	line1 := actionStyle.Render(actionLabel) + " " + pkgStyle.Render(fmt.Sprintf("%s:%s", m.confirmPkg.Type, m.confirmPkg.Name)) + "?"
-	line2 := promptStyle.Render("Press ") + lipgloss.NewStyle().Bold(true).Render("y") + promptStyle.Render(" to confirm, ") + lipgloss.NewStyle().Bold(true).Render("n") + promptStyle.Render(" to cancel")
-
-	// Create bordered dialog box
-	dialogStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(actionColor).
-		Padding(0, 2)
-
-	return dialogStyle.Render(line1 + "\n" + line2)
-}
-
-func getTypeIcon(t brewfile.PackageType) string {
-	switch t {
-	case brewfile.TypeTap:
-		return "🚰"
-	case brewfile.TypeBrew:
-		return "🍺"
-	case brewfile.TypeCask:
-		return "📦"
-	case brewfile.TypeVSCode:
-		return "💻"
-	case brewfile.TypeCursor:
-		return "✏️"
-	case brewfile.TypeAntigravity:
-		return "🚀"
-	case brewfile.TypeGo:
-		return "🔷"
-	case brewfile.TypeMas:
-		return "🍎"
-	default:
-		return "•"
-	}
-}
-
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
-	}
-	return s[:max-3] + "..."
-}
@@ -0,0 +1,114 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/brewfile/brewfiletest"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestListModel_RendersVisibleWindow(t *testing.T) {
+	m := NewListModel(&config.Config{})
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 12})
+	m.Update(listLoadedMsg{packages: brewfiletest.ManyPackages(5000)})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	view := m.ViewContent(100, 12)
+
+	assert.Contains(t, view, "5003/5003")
+	assert.Contains(t, view, "> ") // The cursor row is on screen
+	assert.Contains(t, view, "pkg-4997")
+	assert.NotContains(t, view, "pkg-0000")
+	assert.LessOrEqual(t, strings.Count(view, "\n"), 12)
+}
+
+func BenchmarkListModel_Navigate(b *testing.B) {
+	for _, n := range []int{500, 5000} {
+		b.Run(fmt.Sprintf("%d items", n), func(b *testing.B) {
+			m := NewListModel(&config.Config{Pinned: []string{"brew:pkg-0000"}})
+			m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			m.Update(listLoadedMsg{packages: brewfiletest.ManyPackages(n)})
+			down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if m.cursor == len(m.items)-1 {
+					m.cursor, m.offset = 0, 0
+				}
+				m.Update(down)
+				_ = m.ViewContent(120, 40)
+			}
+		})
+	}
+}
+
+func TestListModel_ConfirmUninstall(t *testing.T) {
+	m := NewListModel(&config.Config{})
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	m.Update(listLoadedMsg{packages: brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "jq")}})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}) // Past the header
+	uninstall := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")}
+
+	// esc cancels without an action
+	m.Update(uninstall)
+	require.True(t, m.confirm.Active())
+	assert.Contains(t, m.ViewContent(100, 20), "brew:jq")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	assert.False(t, m.confirm.Active())
+	_, cmd = m.Update(cmd())
+	assert.Nil(t, cmd)
+
+	// y requests the uninstall
+	m.Update(uninstall)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	require.NotNil(t, cmd)
+	_, cmd = m.Update(cmd())
+	require.NotNil(t, cmd)
+	assert.Equal(t, PackageActionMsg{PkgType: "brew", PkgName: "jq", Action: "uninstall"}, cmd())
+}
+
+func TestListModel_CollapseGroup(t *testing.T) {
+	m := NewListModel(&config.Config{})
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	m.Update(listLoadedMsg{packages: brewfiletest.ManyPackages(6)}) // 2 brew, 2 cask, 2 vscode
+	require.Len(t, m.items, 9)
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}
+	collapse := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")}
+
+	// z on a package folds its group and moves the cursor to the header
+	m.Update(down)
+	m.Update(down)
+	m.Update(collapse)
+	require.Len(t, m.items, 7)
+	assert.Equal(t, 0, m.cursor)
+	assert.True(t, m.items[0].isHeader)
+	assert.True(t, m.items[0].collapsed)
+	assert.Contains(t, m.ViewContent(100, 20), "brew (2) ▸")
+	assert.NotContains(t, m.ViewContent(100, 20), "pkg-0000")
+
+	// Navigation goes straight to the next header
+	m.Update(down)
+	assert.True(t, m.items[m.cursor].isHeader)
+	assert.Equal(t, brewfile.TypeCask, m.items[m.cursor].headerType)
+
+	// Enter on a header folds it too; the state survives a reload
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(listLoadedMsg{packages: brewfiletest.ManyPackages(6)})
+	assert.Len(t, m.items, 5)
+	assert.Nil(t, m.getCurrentPackage())
+
+	// Folding again expands
+	m.cursor = 0
+	m.Update(collapse)
+	assert.Len(t, m.items, 7)
+	assert.Equal(t, 0, m.cursor)
+	assert.False(t, m.items[0].collapsed)
+}
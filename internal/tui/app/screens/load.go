@@ -0,0 +1,14 @@
+package screens
+
+import "sync/atomic"
+
+// loadSeq hands out background load generation ids. It is package-wide so a
+// screen rebuilt on navigation never reuses an id of its predecessor's loads.
+var loadSeq atomic.Uint64
+
+// nextLoadID returns a new load generation id. Screens remember the id of
+// their latest load and drop loaded messages carrying any other id, so late
+// results of superseded loads can't overwrite the current state.
+func nextLoadID() uint64 {
+	return loadSeq.Add(1)
+}
@@ -0,0 +1,92 @@
+package screens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+func TestNextLoadID_Increases(t *testing.T) {
+	first := nextLoadID()
+	assert.Greater(t, nextLoadID(), first)
+}
+
+func TestStaleLoadsAreDiscarded(t *testing.T) {
+	stale := brewfile.Packages{{Type: brewfile.TypeBrew, Name: "stale"}}
+
+	t.Run("dashboard", func(t *testing.T) {
+		m := NewDashboardModel(nil)
+		staleID := m.loadID
+		m.loadData()
+		require.NotEqual(t, staleID, m.loadID)
+
+		m.Update(inventoryLoadedMsg{loadID: staleID, err: errors.New("stale")})
+		m.Update(pendingLoadedMsg{loadID: staleID})
+		assert.True(t, m.loading)
+		assert.True(t, m.loadingPending)
+		assert.Nil(t, m.err)
+
+		m.Update(inventoryLoadedMsg{loadID: m.loadID, totalPackages: 3})
+		assert.False(t, m.loading)
+		assert.Equal(t, 3, m.totalPackages)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		m := NewDiffModel(nil)
+		staleID := m.loadID
+		m.Init()
+		require.NotEqual(t, staleID, m.loadID)
+
+		m.Update(diffLoadedMsg{loadID: staleID, additions: stale})
+		assert.True(t, m.loading)
+		assert.Empty(t, m.additions)
+
+		m.Update(diffLoadedMsg{loadID: m.loadID, err: errors.New("current")})
+		assert.False(t, m.loading)
+		assert.EqualError(t, m.err, "current")
+	})
+
+	t.Run("diff from a previous screen instance", func(t *testing.T) {
+		previous := NewDiffModel(nil)
+		cmd := previous.Init()
+
+		m := NewDiffModel(nil)
+		m.Init()
+		m.Update(cmd())
+		assert.True(t, m.loading)
+		assert.Nil(t, m.err)
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		m := NewSyncModel(nil)
+		staleID := nextLoadID()
+		m.Init()
+
+		m.Update(syncLoadedMsg{loadID: staleID, additions: stale})
+		assert.Equal(t, SyncPhaseLoading, m.phase)
+		assert.Empty(t, m.allAdditions)
+
+		m.Update(syncLoadedMsg{loadID: m.loadID, additions: stale})
+		assert.Equal(t, SyncPhasePreview, m.phase)
+		assert.Len(t, m.allAdditions, 1)
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		m := NewIgnoreModel(nil)
+		staleID := nextLoadID()
+		m.Init()
+
+		m.Update(ignoreLoadedMsg{loadID: staleID, categories: []ignoreItem{{value: "mas", isGlobal: true}}})
+		assert.True(t, m.loading)
+		assert.Empty(t, m.categories)
+
+		m.Update(ignoreLoadedMsg{loadID: m.loadID, categories: []ignoreItem{{value: "go", isGlobal: true}}})
+		assert.False(t, m.loading)
+		require.Len(t, m.categories, 1)
+		assert.Equal(t, "go", m.categories[0].value)
+	})
+}
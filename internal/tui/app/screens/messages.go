@@ -15,7 +15,7 @@ type StatusMsg struct {
 // SetupCompleteMsg is sent when initial setup is complete
 type SetupCompleteMsg struct{}
 
-// RefreshMsg is sent to refresh the current screen's data
+// RefreshMsg is sent to refresh the current screen's data (re-runs its load command)
 type RefreshMsg struct{}
 
 // Navigate creates a NavigateMsg to the target screen
@@ -80,3 +80,18 @@ type PackageActionDoneMsg struct {
 	Success bool
 	Error   error
 }
+
+// SyncCompleteMsg is sent after a sync finishes so other screens can reload
+type SyncCompleteMsg struct {
+	Source    string
+	Installed int
+	Removed   int
+}
+
+// PendingMsg is sent when the dashboard has counted the changes pending from
+// the default source, so the header can show them
+type PendingMsg struct {
+	Source  string
+	Adds    int
+	Removes int
+}
@@ -0,0 +1,39 @@
+package screens
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
+	"github.com/asamgx/brewsync/internal/tui/styles"
+)
+
+// openPackageActionConfirm asks to install or uninstall pkg
+func openPackageActionConfirm(confirm *components.Confirm, action string, pkg brewfile.Package) {
+	actionLabel := "Uninstall"
+	actionColor := styles.CatRed
+	if action == "install" {
+		actionLabel = "Install"
+		actionColor = styles.CatGreen
+	}
+
+	actionStyle := lipgloss.NewStyle().Foreground(actionColor).Bold(true)
+	pkgStyle := lipgloss.NewStyle().Foreground(styles.CatMauve).Bold(true)
+	prompt := actionStyle.Render(actionLabel) + " " + pkgStyle.Render(fmt.Sprintf("%s:%s", pkg.Type, pkg.Name)) + "?"
+
+	confirm.Open(prompt, actionColor)
+}
+
+// requestPackageAction asks the app to run action on pkg in the background
+func requestPackageAction(action string, pkg brewfile.Package) tea.Cmd {
+	return func() tea.Msg {
+		return PackageActionMsg{
+			PkgType: string(pkg.Type),
+			PkgName: pkg.Name,
+			Action:  action,
+		}
+	}
+}
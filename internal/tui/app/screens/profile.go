@@ -133,8 +133,7 @@ func (m *ProfileModel) ViewContent(width, height int) string {
 		}
 
 		// Count packages
-		total := len(p.Packages.Tap) + len(p.Packages.Brew) + len(p.Packages.Cask) +
-			len(p.Packages.VSCode) + len(p.Packages.Cursor) + len(p.Packages.Go) + len(p.Packages.Mas)
+		total := p.Packages.Count()
 
 		line := fmt.Sprintf("%s▸ %s (%d pkgs)", prefix, p.Name, total)
 		b.WriteString(line)
@@ -0,0 +1,81 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/git"
+)
+
+// quickDumpDoneMsg is sent when a quick dump started from the dashboard finishes
+type quickDumpDoneMsg struct {
+	status StatusMsg
+}
+
+// quickDump runs the dump in the background, without the dump screen, and
+// reports the result as a status message
+func (m *DashboardModel) quickDump() tea.Cmd {
+	cfg := m.config
+	return func() tea.Msg {
+		return quickDumpDoneMsg{status: runQuickDump(cfg)}
+	}
+}
+
+// runQuickDump writes the current machine's Brewfile as the dump screen does,
+// then commits and pushes it as auto_dump.commit and auto_dump.push say. A
+// Brewfile modified after the last dump is left alone with a warning.
+func runQuickDump(cfg *config.Config) StatusMsg {
+	result := dumpBrewfile(cfg, false)
+	if errors.Is(result.err, errEditedSinceDump) {
+		return StatusWarning("Not dumped: the Brewfile was modified after the last dump; dump from the Dump screen to overwrite it")
+	}
+	if result.err != nil {
+		debug.Log("runQuickDump: dump failed: %v", result.err)
+		return StatusError(fmt.Sprintf("Dump failed: %v", result.err))
+	}
+
+	summary := fmt.Sprintf("Dumped %d packages", result.total)
+	if len(result.warnings) > 0 {
+		summary += fmt.Sprintf(" (%d warnings, see the Dump screen)", len(result.warnings))
+	}
+	if !cfg.AutoDump.Commit {
+		return quickDumpStatus(summary, result.warnings)
+	}
+
+	machine, _ := cfg.GetCurrentMachine()
+	repo := git.NewRepo(filepath.Dir(machine.Brewfile))
+	if !repo.IsRepo() {
+		return StatusError(fmt.Sprintf("%s, but not committed: %s is not a git repository", summary, repo.Dir()))
+	}
+
+	message := config.CommitMessage(cfg.AutoDump.CommitMessage, cfg.CurrentMachine)
+	committed, err := repo.CommitFile(filepath.Base(machine.Brewfile), message)
+	if err != nil {
+		return StatusError(fmt.Sprintf("%s, but %v", summary, err))
+	}
+	if !committed {
+		return quickDumpStatus(summary+", no changes to commit", result.warnings)
+	}
+	if !cfg.AutoDump.Push {
+		return quickDumpStatus(summary+" and committed", result.warnings)
+	}
+
+	if err := repo.Push(); err != nil {
+		return StatusError(fmt.Sprintf("%s and committed, but %v", summary, err))
+	}
+	return quickDumpStatus(summary+", committed and pushed", result.warnings)
+}
+
+// quickDumpStatus reports a successful dump, as a warning if some categories
+// could not be listed
+func quickDumpStatus(message string, warnings []string) StatusMsg {
+	if len(warnings) > 0 {
+		return StatusWarning(message)
+	}
+	return StatusSuccess(message)
+}
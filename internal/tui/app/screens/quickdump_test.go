@@ -0,0 +1,155 @@
+package screens
+
+import (
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/dump"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := osexec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestRunQuickDump(t *testing.T) {
+	if _, err := osexec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	collected := 0
+	original := collectPackages
+	collectPackages = func(cfg *config.Config, path string) (brewfile.Packages, dump.Failures, []string, error) {
+		collected++
+		return brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeBrew, "git"),
+			brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		}, nil, nil, nil
+	}
+	t.Cleanup(func() { collectPackages = original })
+
+	tests := []struct {
+		name       string
+		autoDump   config.AutoDumpConfig
+		wantStatus string
+		wantCommit string // Subject of the last local commit
+		wantPushed bool
+	}{
+		{
+			name:       "dump only",
+			wantStatus: "Dumped 2 packages",
+			wantCommit: "initial",
+		},
+		{
+			name:       "commit",
+			autoDump:   config.AutoDumpConfig{Commit: true, CommitMessage: "update {machine}"},
+			wantStatus: "Dumped 2 packages and committed",
+			wantCommit: "update mini",
+		},
+		{
+			name:       "commit and push",
+			autoDump:   config.AutoDumpConfig{Commit: true, Push: true, CommitMessage: "update {machine}"},
+			wantStatus: "Dumped 2 packages, committed and pushed",
+			wantCommit: "update mini",
+			wantPushed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			remote := filepath.Join(root, "remote.git")
+			local := filepath.Join(root, "dotfiles")
+			gitOutput(t, root, "init", "--bare", "-b", "main", remote)
+			gitOutput(t, root, "clone", remote, local)
+			gitOutput(t, local, "checkout", "-b", "main")
+			gitOutput(t, local, "config", "user.name", "test")
+			gitOutput(t, local, "config", "user.email", "test@example.com")
+			brewfilePath := filepath.Join(local, "Brewfile")
+			require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"git\"\n"), 0644))
+			gitOutput(t, local, "add", ".")
+			gitOutput(t, local, "commit", "-m", "initial")
+			gitOutput(t, local, "push", "-u", "origin", "main")
+
+			cfg := &config.Config{
+				Machines:       map[string]config.Machine{"mini": {Brewfile: brewfilePath}},
+				CurrentMachine: "mini",
+				AutoDump:       tt.autoDump,
+			}
+
+			before := collected
+			status := runQuickDump(cfg)
+			assert.Equal(t, before+1, collected)
+			assert.Equal(t, StatusSuccess(tt.wantStatus), status)
+
+			pkgs, err := brewfile.Parse(brewfilePath)
+			require.NoError(t, err)
+			assert.Len(t, pkgs, 2)
+
+			assert.Equal(t, tt.wantCommit, gitOutput(t, local, "log", "-1", "--format=%s"))
+			pushed := gitOutput(t, remote, "log", "-1", "--format=%s", "main")
+			if tt.wantPushed {
+				assert.Equal(t, tt.wantCommit, pushed)
+			} else {
+				assert.Equal(t, "initial", pushed)
+			}
+		})
+	}
+}
+
+func TestRunQuickDump_NotARepo(t *testing.T) {
+	original := collectPackages
+	collectPackages = func(cfg *config.Config, path string) (brewfile.Packages, dump.Failures, []string, error) {
+		return brewfile.Packages{brewfile.NewPackage(brewfile.TypeBrew, "git")}, nil, nil, nil
+	}
+	t.Cleanup(func() { collectPackages = original })
+
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	cfg := &config.Config{
+		Machines:       map[string]config.Machine{"mini": {Brewfile: brewfilePath}},
+		CurrentMachine: "mini",
+		AutoDump:       config.AutoDumpConfig{Commit: true},
+	}
+
+	status := runQuickDump(cfg)
+	assert.Equal(t, "error", status.Type)
+	assert.Contains(t, status.Message, "not a git repository")
+	assert.FileExists(t, brewfilePath)
+}
+
+func TestRunQuickDump_EditedSinceDump(t *testing.T) {
+	original := collectPackages
+	collectPackages = func(cfg *config.Config, path string) (brewfile.Packages, dump.Failures, []string, error) {
+		t.Fatal("collected packages for a hand-edited Brewfile")
+		return nil, nil, nil, nil
+	}
+	t.Cleanup(func() { collectPackages = original })
+
+	brewfilePath := filepath.Join(t.TempDir(), "Brewfile")
+	require.NoError(t, os.WriteFile(brewfilePath, []byte("brew \"wget\"\n"), 0644))
+	require.NoError(t, brewfile.SaveMetadata(brewfile.MetadataPath(brewfilePath), &brewfile.Metadata{LastDump: time.Now().Add(-time.Hour)}))
+	cfg := &config.Config{
+		Machines:       map[string]config.Machine{"mini": {Brewfile: brewfilePath}},
+		CurrentMachine: "mini",
+	}
+
+	status := runQuickDump(cfg)
+	assert.Equal(t, "warning", status.Type)
+	assert.Contains(t, status.Message, "modified after the last dump")
+}
@@ -0,0 +1,53 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/tui/styles"
+)
+
+func TestRefreshMsg_RerunsLoad(t *testing.T) {
+	t.Run("dashboard", func(t *testing.T) {
+		m := NewDashboardModel(nil)
+		m.loading = false
+
+		_, cmd := m.Update(RefreshMsg{})
+		require.NotNil(t, cmd)
+		assert.True(t, m.loading)
+		assert.IsType(t, tea.BatchMsg{}, cmd())
+	})
+
+	t.Run("list", func(t *testing.T) {
+		m := NewListModel(nil)
+		m.loading = false
+
+		_, cmd := m.Update(RefreshMsg{})
+		require.NotNil(t, cmd)
+		assert.True(t, m.loading)
+		assert.IsType(t, listLoadedMsg{}, cmd())
+	})
+
+	t.Run("list ignores refresh while confirming", func(t *testing.T) {
+		m := NewListModel(nil)
+		m.loading = false
+		m.confirm.Open("Uninstall brew:jq?", styles.CatRed)
+
+		_, cmd := m.Update(RefreshMsg{})
+		assert.Nil(t, cmd)
+		assert.False(t, m.loading)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		m := NewDiffModel(nil)
+		m.loading = false
+
+		_, cmd := m.Update(RefreshMsg{})
+		require.NotNil(t, cmd)
+		assert.True(t, m.loading)
+		assert.IsType(t, diffLoadedMsg{}, cmd())
+	})
+}
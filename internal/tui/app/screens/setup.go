@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -15,6 +16,8 @@ import (
 
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/dump"
 	"github.com/asamgx/brewsync/internal/installer"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
@@ -54,9 +57,10 @@ type setupConfigResultMsg struct {
 
 // setupDumpResultMsg is sent when dump completes
 type setupDumpResultMsg struct {
-	err    error
-	counts map[string]int
-	total  int
+	err      error
+	counts   map[string]int
+	total    int
+	warnings []string
 }
 
 // SetupModel is the model for the first-time setup wizard
@@ -85,14 +89,16 @@ type SetupModel struct {
 
 	// Detected values
 	detectedHostname string
+	brewMissing      bool // Homebrew isn't installed, so a dump finds nothing
 
 	// Progress tracking
 	spinner         spinner.Model
 	progressMessage string
 
 	// Dump results (when createBrewfile is true)
-	dumpCounts map[string]int
-	dumpTotal  int
+	dumpCounts   map[string]int
+	dumpTotal    int
+	dumpWarnings []string
 }
 
 // NewSetupModel creates a new setup model
@@ -157,6 +163,7 @@ func NewSetupModel() *SetupModel {
 		sourceHostname:     sourceHostnameInput,
 		sourceBrewfile:     sourceBrewfileInput,
 		detectedHostname:   hostname,
+		brewMissing:        !installer.NewBrewInstaller().IsAvailable(),
 		addSourceMachine:   false,
 		setSourceAsDefault: false,
 		spinner:            s,
@@ -228,6 +235,7 @@ func (m *SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.dumpCounts = msg.counts
 			m.dumpTotal = msg.total
+			m.dumpWarnings = msg.warnings
 		}
 		m.step = SetupStepDone
 		return m, nil
@@ -491,6 +499,7 @@ func (m *SetupModel) createConfig() tea.Cmd {
 
 		// Build initial config with all default settings
 		initialConfig := map[string]interface{}{
+			"schema_version":      config.CurrentSchemaVersion,
 			"machines":            machines,
 			"current_machine":     "auto",
 			"default_source":      defaultSource,
@@ -505,6 +514,7 @@ func (m *SetupModel) createConfig() tea.Cmd {
 			},
 			"dump": map[string]interface{}{
 				"use_brew_bundle": true,
+				"header":          true,
 			},
 			"machine_specific": map[string]interface{}{},
 			"output": map[string]interface{}{
@@ -563,91 +573,57 @@ func (m *SetupModel) runDump() tea.Cmd {
 		}
 
 		// Collect all packages
-		allPackages, err := collectPackagesForSetup(cfg, brewfilePath)
+		allPackages, warnings, err := collectPackagesForSetup(cfg, brewfilePath)
 		if err != nil {
 			return setupDumpResultMsg{err: err}
 		}
 
 		// Write Brewfile
 		writer := brewfile.NewWriter(allPackages)
+		if cfg.Dump.Header {
+			writer.WithHeader(brewfile.Header(cfg.CurrentMachine, time.Now()))
+		}
 		if err := writer.Write(brewfilePath); err != nil {
 			return setupDumpResultMsg{err: fmt.Errorf("failed to write Brewfile: %w", err)}
 		}
 
-		// Count by type
-		counts := make(map[string]int)
-		for _, pkg := range allPackages {
-			counts[string(pkg.Type)]++
-		}
-
+		stats := allPackages.Stats()
 		return setupDumpResultMsg{
-			counts: counts,
-			total:  len(allPackages),
+			counts:   stats.Counts(),
+			total:    stats.Total,
+			warnings: warnings,
 		}
 	}
 }
 
-// collectPackagesForSetup collects all installed packages (similar to dump screen)
-func collectPackagesForSetup(cfg *config.Config, brewfilePath string) (brewfile.Packages, error) {
+// collectPackagesForSetup collects all installed packages (similar to dump
+// screen), with warnings about the collection
+func collectPackagesForSetup(cfg *config.Config, brewfilePath string) (brewfile.Packages, []string, error) {
 	var allPackages brewfile.Packages
 	brewInst := installer.NewBrewInstaller()
-
-	// Use brew bundle dump if configured (default), otherwise collect manually
-	if cfg.Dump.UseBrewBundle && brewInst.IsAvailable() {
-		// Create temp file for brew bundle dump
-		tmpFile := brewfilePath + ".brewbundle.tmp"
-		if err := brewInst.DumpToFile(tmpFile); err == nil {
-			// Parse the brew bundle output (includes taps, formulae, casks with descriptions)
-			if brewPkgs, err := brewfile.Parse(tmpFile); err == nil {
-				allPackages = append(allPackages, brewPkgs...)
-			}
-			os.Remove(tmpFile)
-		}
-	} else if brewInst.IsAvailable() {
-		// Manual collection
-		if taps, err := brewInst.ListTaps(); err == nil {
-			allPackages = append(allPackages, taps...)
-		}
-		if formulae, err := brewInst.ListFormulae(); err == nil {
-			allPackages = append(allPackages, formulae...)
-		}
-		if casks, err := brewInst.ListCasks(); err == nil {
-			allPackages = append(allPackages, casks...)
-		}
+	if err := brewInst.CheckAvailable(brewfile.AllTypes()...); err != nil {
+		return nil, nil, err
 	}
 
-	// Collect extensions
-	if vscodeInst := installer.NewVSCodeInstaller(); vscodeInst.IsAvailable() {
-		if extensions, err := vscodeInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
-	}
-
-	if cursorInst := installer.NewCursorInstaller(); cursorInst.IsAvailable() {
-		if extensions, err := cursorInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
-	}
-
-	if antigravityInst := installer.NewAntigravityInstaller(); antigravityInst.IsAvailable() {
-		if extensions, err := antigravityInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(extensions...)
-		}
+	// Use brew bundle dump if configured (default), otherwise collect manually
+	brewPkgs, fellBack, err := brewInst.CollectPackages(cfg.UseBrewBundle(cfg.CurrentMachine), brewfilePath+".brewbundle.tmp")
+	if err != nil {
+		return nil, nil, err
 	}
-
-	if goInst := installer.NewGoToolsInstaller(); goInst.IsAvailable() {
-		if tools, err := goInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(tools...)
-		}
+	var warnings []string
+	if fellBack {
+		debug.Log("collectPackagesForSetup: brew bundle unavailable, fell back to brew list")
+		warnings = append(warnings, bundleFallbackWarning)
 	}
+	allPackages = append(allPackages, brewPkgs...)
 
-	if masInst := installer.NewMasInstaller(); masInst.IsAvailable() {
-		if apps, err := masInst.List(); err == nil {
-			allPackages = allPackages.AddUnique(apps...)
+	allPackages, failures := dump.CollectExtra(cfg, allPackages)
+	for _, t := range brewfile.AllTypes() {
+		if err, failed := failures[t]; failed {
+			warnings = append(warnings, fmt.Sprintf("Could not list %s packages: %v", t, err))
 		}
 	}
-
-	return allPackages, nil
+	return allPackages, warnings, nil
 }
 
 // View renders the setup wizard
@@ -694,7 +670,11 @@ func (m *SetupModel) View() string {
 		b.WriteString("\n\n")
 		b.WriteString("How would you like to set up your Brewfile?\n\n")
 		b.WriteString("  " + styles.SelectedStyle.Render("[1]") + " Create a new Brewfile (captures currently installed packages)\n")
-		b.WriteString("      " + styles.DimmedStyle.Render("Will save to ~/Brewfile and run dump after setup") + "\n\n")
+		b.WriteString("      " + styles.DimmedStyle.Render("Will save to ~/Brewfile and run dump after setup") + "\n")
+		if m.brewMissing {
+			b.WriteString("      " + styles.WarningStyle.Render("⚠ Homebrew not found — install it from https://brew.sh first") + "\n")
+		}
+		b.WriteString("\n")
 		b.WriteString("  " + styles.SelectedStyle.Render("[2]") + " Enter path to existing Brewfile\n")
 		b.WriteString("      " + styles.DimmedStyle.Render("Use an existing Brewfile from your dotfiles") + "\n")
 		b.WriteString("\n\n")
@@ -815,13 +795,17 @@ func (m *SetupModel) View() string {
 
 				// Show counts by type
 				b.WriteString(styles.DimmedStyle.Render("Packages by type:") + "\n")
-				typeOrder := []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "mas"}
+				typeOrder := []string{"tap", "brew", "cask", "vscode", "cursor", "antigravity", "go", "npm", "pipx", "mas"}
 				for _, t := range typeOrder {
 					if count, ok := m.dumpCounts[t]; ok && count > 0 {
 						b.WriteString(fmt.Sprintf("  %s: %d\n", t, count))
 					}
 				}
 				b.WriteString("\n")
+				for _, warning := range m.dumpWarnings {
+					b.WriteString(styles.WarningStyle.Render("⚠ " + warning))
+					b.WriteString("\n")
+				}
 				b.WriteString(fmt.Sprintf("Brewfile saved to: %s\n", m.brewfile.Value()))
 			} else if !m.runDumpAfterSetup {
 				b.WriteString("\nNext steps:\n")
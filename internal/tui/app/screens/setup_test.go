@@ -0,0 +1,34 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/installer"
+)
+
+func TestSetupModel_NoBrew(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // No brew
+
+	m := NewSetupModel()
+	m.step = SetupStepBrewfileChoice
+	assert.Contains(t, m.View(), "Homebrew not found — install it from https://brew.sh first")
+
+	// Creating the Brewfile via dump fails instead of writing an empty one
+	pkgs, _, err := collectPackagesForSetup(&config.Config{CurrentMachine: "mini"}, t.TempDir()+"/Brewfile")
+	require.ErrorIs(t, err, installer.ErrBrewNotFound)
+	assert.Empty(t, pkgs)
+}
+
+func TestSetupModel_DumpWarnings(t *testing.T) {
+	m := NewSetupModel()
+	m.step = SetupStepDumping
+	m.runDumpAfterSetup = true
+
+	m.Update(setupDumpResultMsg{counts: map[string]int{"brew": 2}, total: 2, warnings: []string{bundleFallbackWarning}})
+	assert.Equal(t, SetupStepDone, m.step)
+	assert.Contains(t, m.View(), "⚠ "+bundleFallbackWarning)
+}
@@ -9,9 +9,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/asamgx/brewsync/internal/audit"
 	"github.com/asamgx/brewsync/internal/brewfile"
 	"github.com/asamgx/brewsync/internal/config"
+	"github.com/asamgx/brewsync/internal/debug"
+	"github.com/asamgx/brewsync/internal/history"
 	"github.com/asamgx/brewsync/internal/installer"
+	"github.com/asamgx/brewsync/internal/sync"
+	"github.com/asamgx/brewsync/internal/tui/app/components"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
@@ -34,15 +39,6 @@ const (
 	SyncColumnRemovals
 )
 
-// syncItem represents a displayable item in a sync column (header or package)
-type syncItem struct {
-	isHeader    bool
-	headerType  brewfile.PackageType
-	headerCount int
-	pkg         brewfile.Package
-	isIgnored   bool
-}
-
 // SyncModel is the model for the sync screen
 type SyncModel struct {
 	config       *config.Config
@@ -50,24 +46,29 @@ type SyncModel struct {
 	height       int
 	source       string
 	phase        SyncPhase
+	loadID       uint64            // Generation of the latest load
 	allAdditions brewfile.Packages // All additions including ignored
 	allRemovals  brewfile.Packages // All removals including ignored
 	additions    brewfile.Packages // Filtered additions
 	removals     brewfile.Packages // Filtered removals
 	protected    brewfile.Packages
-	addItems     []syncItem // Flattened additions with headers
-	remItems     []syncItem // Flattened removals with headers
-	column       SyncColumn // Current column focus
-	addCursor    int        // Cursor in additions
-	remCursor    int        // Cursor in removals
-	addOffset    int        // Scroll offset for additions
-	remOffset    int        // Scroll offset for removals
+	addItems     []groupItem     // Flattened additions with headers
+	remItems     []groupItem     // Flattened removals with headers
+	addCollapsed collapsedGroups // Folded categories in additions
+	remCollapsed collapsedGroups // Folded categories in removals
+	column       SyncColumn      // Current column focus
+	addCursor    int             // Cursor in additions
+	remCursor    int             // Cursor in removals
+	addOffset    int             // Scroll offset for additions
+	remOffset    int             // Scroll offset for removals
 	err          error
 	installed    int
 	removed      int
 	failed       int
-	showConfirm  bool
+	confirm      components.Confirm
 	showIgnored  bool
+	compact      bool // Only category headers with their counts are listed
+	applied      bool // The preview was re-planned after applying a sync
 
 	// Execution state
 	spinner       spinner.Model
@@ -79,10 +80,29 @@ type SyncModel struct {
 }
 
 type syncResult struct {
-	pkg     brewfile.Package
-	action  string // "installed" or "removed"
-	success bool
-	err     error
+	pkg      brewfile.Package
+	action   string // "installed" or "removed"
+	success  bool
+	err      error
+	caveats  string // Setup notes brew printed while installing
+	attempts int    // Install attempts made, more than 1 if it was retried
+}
+
+// retryNote describes the retries an install took ("installed after 2
+// retries"), or returns "" if it wasn't retried
+func (r syncResult) retryNote() string {
+	retries := r.attempts - 1
+	if retries <= 0 {
+		return ""
+	}
+	word := "retries"
+	if retries == 1 {
+		word = "retry"
+	}
+	if r.success {
+		return fmt.Sprintf("%s after %d %s", r.action, retries, word)
+	}
+	return fmt.Sprintf("failed after %d %s", retries, word)
 }
 
 // NewSyncModel creates a new sync model
@@ -103,6 +123,7 @@ func NewSyncModel(cfg *config.Config) *SyncModel {
 		source:  source,
 		phase:   SyncPhaseLoading,
 		spinner: s,
+		confirm: components.NewConfirm("apply-sync"),
 	}
 }
 
@@ -111,6 +132,7 @@ type syncLoadedMsg struct {
 	removals  brewfile.Packages
 	protected brewfile.Packages
 	err       error
+	loadID    uint64
 }
 
 type syncProgressMsg struct {
@@ -131,63 +153,41 @@ type syncDoneMsg struct {
 
 // Init initializes the sync model
 func (m *SyncModel) Init() tea.Cmd {
+	id := nextLoadID()
+	m.loadID = id
 	return tea.Batch(
 		m.spinner.Tick,
 		func() tea.Msg {
-			if m.config == nil {
-				return syncLoadedMsg{err: fmt.Errorf("no config loaded")}
-			}
-
-			currentMachine, ok := m.config.GetCurrentMachine()
-			if !ok {
-				return syncLoadedMsg{err: fmt.Errorf("current machine not found")}
-			}
-
-			sourceMachine, ok := m.config.GetMachine(m.source)
-			if !ok {
-				return syncLoadedMsg{err: fmt.Errorf("source machine %q not found", m.source)}
-			}
-
-			// Parse both Brewfiles
-			currentPkgs, err := brewfile.Parse(currentMachine.Brewfile)
-			if err != nil {
-				currentPkgs = brewfile.Packages{}
-			}
-
-			sourcePkgs, err := brewfile.Parse(sourceMachine.Brewfile)
-			if err != nil {
-				return syncLoadedMsg{err: fmt.Errorf("failed to parse source Brewfile: %w", err)}
-			}
-
-			diff := brewfile.Diff(sourcePkgs, currentPkgs)
+			msg := m.loadSync()
+			msg.loadID = id
+			return msg
+		},
+	)
+}
 
-			// Filter out machine-specific packages from removals
-			var removals, protected brewfile.Packages
-			machineSpecific := m.config.GetMachineSpecificPackages()
-			currentMachineSpecific := machineSpecific[m.config.CurrentMachine]
+// loadSync computes the sync plan. Ignored changes are kept so they can be shown
+// with the show-ignored toggle; filterPackages hides them by default. Changes
+// applied by a sync since the last dump are left out, as the Brewfile doesn't
+// reflect them yet.
+func (m *SyncModel) loadSync() syncLoadedMsg {
+	plan, err := sync.NewPlan(m.config, m.source, sync.Options{})
+	if err != nil {
+		return syncLoadedMsg{err: err}
+	}
 
-			for _, pkg := range diff.Removals {
-				isProtected := false
-				for _, ms := range currentMachineSpecific {
-					if pkg.ID() == ms {
-						isProtected = true
-						break
-					}
-				}
-				if isProtected {
-					protected = append(protected, pkg)
-				} else {
-					removals = append(removals, pkg)
-				}
-			}
+	additions := append(plan.Additions, plan.IgnoredAdditions...)
+	removals := append(plan.Removals, plan.IgnoredRemovals...)
+	if meta, err := brewfile.LoadMetadata(brewfile.MetadataPath(plan.CurrentBrewfile)); err == nil {
+		resolved := meta.SyncResolved(m.source)
+		additions = additions.Exclude(resolved)
+		removals = removals.Exclude(resolved)
+	}
 
-			return syncLoadedMsg{
-				additions: diff.Additions,
-				removals:  removals,
-				protected: protected,
-			}
-		},
-	)
+	return syncLoadedMsg{
+		additions: additions,
+		removals:  removals,
+		protected: plan.Protected,
+	}
 }
 
 // Update handles messages
@@ -207,7 +207,14 @@ func (m *SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case syncLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil // Superseded by a newer load
+		}
 		m.err = msg.err
+		if m.err != nil && m.applied {
+			m.phase = SyncPhaseDone // Show the results even though re-planning failed
+			return m, nil
+		}
 		m.allAdditions = msg.additions
 		m.allRemovals = msg.removals
 		m.protected = msg.protected
@@ -255,27 +262,36 @@ func (m *SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case components.ConfirmResultMsg:
+		if msg.ID == m.confirm.ID() && msg.Confirmed {
+			m.phase = SyncPhaseExecuting
+			return m, tea.Batch(m.spinner.Tick, m.executeSync())
+		}
+		return m, nil
+
 	case syncDoneMsg:
-		m.phase = SyncPhaseDone
 		m.installed = msg.installed
 		m.removed = msg.removed
 		m.failed = msg.failed
 		m.results = msg.results
-		return m, nil
+
+		// Re-plan and return to the preview, which shows the results above
+		// whatever is left to do
+		m.phase = SyncPhaseLoading
+		m.applied = true
+		m.column = SyncColumnAdditions
+		m.addCursor, m.remCursor, m.addOffset, m.remOffset = 0, 0, 0, 0
+		return m, tea.Batch(
+			func() tea.Msg {
+				return SyncCompleteMsg{Source: m.source, Installed: msg.installed, Removed: msg.removed}
+			},
+			m.Init(),
+		)
 
 	case tea.KeyMsg:
 		// Handle confirmation dialog
-		if m.showConfirm {
-			switch msg.String() {
-			case "y", "Y":
-				m.showConfirm = false
-				m.phase = SyncPhaseExecuting
-				return m, tea.Batch(m.spinner.Tick, m.executeSync())
-			case "n", "N", "esc":
-				m.showConfirm = false
-				return m, nil
-			}
-			return m, nil
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
 		}
 
 		// Preview phase navigation
@@ -293,9 +309,17 @@ func (m *SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.jumpToTop()
 			case key.Matches(msg, key.NewBinding(key.WithKeys("G"))):
 				m.jumpToBottom()
+			case key.Matches(msg, key.NewBinding(key.WithKeys("z"))):
+				m.toggleCollapse()
+			case key.Matches(msg, key.NewBinding(key.WithKeys("c"))):
+				m.toggleCompact()
+			case key.Matches(msg, key.NewBinding(key.WithKeys("enter", " "))) && m.onHeader():
+				m.toggleCollapse()
 			case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
 				if len(m.additions) > 0 || len(m.removals) > 0 {
-					m.showConfirm = true
+					prompt := lipgloss.NewStyle().Foreground(styles.CatYellow).Bold(true).
+						Render(fmt.Sprintf("Apply %d changes?", len(m.additions)+len(m.removals)))
+					m.confirm.Open(prompt, styles.CatYellow)
 				}
 			case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
 				return m, func() tea.Msg { return Navigate("dashboard") }
@@ -319,18 +343,21 @@ func (m *SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *SyncModel) executeSync() tea.Cmd {
 	return func() tea.Msg {
 		mgr := installer.NewManager()
+		mgr.SetCaskNoQuarantine(m.config.Install.CaskNoQuarantine)
+		mgr.SetRetryPolicy(installer.Retries(m.config.Install.Retries))
+		mgr.SetParallel(m.config.Install.Parallel)
+		audit.Attach(mgr, m.config, "brewsync tui: sync")
 		var results []syncResult
 		var installed, removed, failed int
 
-		// Install additions
-		for _, pkg := range m.additions {
-			// We can't send messages from here directly, so we'll just execute
-			err := mgr.Install(pkg)
+		// Install additions, install.parallel at a time
+		mgr.InstallMany(m.additions, func(pkg brewfile.Package, i, total int, err error) {
 			result := syncResult{
-				pkg:     pkg,
-				action:  "installed",
-				success: err == nil,
-				err:     err,
+				pkg:      pkg,
+				action:   "installed",
+				success:  err == nil,
+				err:      err,
+				attempts: mgr.Attempts(pkg),
 			}
 			results = append(results, result)
 			if err != nil {
@@ -338,7 +365,7 @@ func (m *SyncModel) executeSync() tea.Cmd {
 			} else {
 				installed++
 			}
-		}
+		})
 
 		// Remove removals
 		for _, pkg := range m.removals {
@@ -357,6 +384,30 @@ func (m *SyncModel) executeSync() tea.Cmd {
 			}
 		}
 
+		// Record the sync so the dashboard's pending changes are accurate before the next dump
+		var installedPkgs, removedPkgs brewfile.Packages
+		for _, r := range results {
+			if !r.success {
+				continue
+			}
+			if r.action == "installed" {
+				installedPkgs = append(installedPkgs, r.pkg)
+			} else {
+				removedPkgs = append(removedPkgs, r.pkg)
+			}
+		}
+		history.LogSync(m.config.CurrentMachine, m.source, installedPkgs.IDs(), removedPkgs.IDs())
+		if machine, ok := m.config.GetCurrentMachine(); ok {
+			if err := brewfile.UpdateSyncMetadata(brewfile.MetadataPath(machine.Brewfile), m.source, installedPkgs, removedPkgs); err != nil {
+				debug.Log("Sync.executeSync: failed to update metadata: %v", err)
+			}
+		}
+
+		caveats := mgr.Caveats()
+		for i := range results {
+			results[i].caveats = caveats[results[i].pkg.ID()]
+		}
+
 		return syncDoneMsg{
 			installed: installed,
 			removed:   removed,
@@ -373,8 +424,8 @@ func (m *SyncModel) buildItems() {
 }
 
 // buildItemsForPackages creates a flattened list with headers for a package list
-func (m *SyncModel) buildItemsForPackages(pkgs brewfile.Packages, isAdditions bool) []syncItem {
-	var items []syncItem
+func (m *SyncModel) buildItemsForPackages(pkgs brewfile.Packages, isAdditions bool) []groupItem {
+	var items []groupItem
 	byType := pkgs.ByType()
 	types := []brewfile.PackageType{
 		brewfile.TypeTap,
@@ -384,30 +435,22 @@ func (m *SyncModel) buildItemsForPackages(pkgs brewfile.Packages, isAdditions bo
 		brewfile.TypeCursor,
 		brewfile.TypeAntigravity,
 		brewfile.TypeGo,
+		brewfile.TypeNpm,
+		brewfile.TypePipx,
 		brewfile.TypeMas,
 	}
 
-	// Get all packages for ignore checking
-	var allPkgs brewfile.Packages
-	if isAdditions {
-		allPkgs = m.allAdditions
-	} else {
-		allPkgs = m.allRemovals
+	collapsed := m.addCollapsed
+	if !isAdditions {
+		collapsed = m.remCollapsed
 	}
 
 	for _, t := range types {
-		typePkgs := byType[t]
-		if len(typePkgs) == 0 {
-			continue
-		}
-
-		// Count visible and ignored packages
+		// Split the column's own packages so the header counts exactly what
+		// is listed under it
 		var visiblePkgs []brewfile.Package
 		var ignoredPkgs []brewfile.Package
-
-		// Check all packages of this type from the full list
-		allByType := allPkgs.ByType()
-		for _, pkg := range allByType[t] {
+		for _, pkg := range byType[t] {
 			isIgnored := m.config != nil && (m.config.IsCategoryIgnored(m.config.CurrentMachine, string(pkg.Type)) ||
 				m.config.IsPackageIgnored(m.config.CurrentMachine, pkg.ID()))
 			if isIgnored {
@@ -417,39 +460,72 @@ func (m *SyncModel) buildItemsForPackages(pkgs brewfile.Packages, isAdditions bo
 			}
 		}
 
-		// Skip this category if no visible items and not showing ignored
-		if !m.showIgnored && len(visiblePkgs) == 0 {
-			continue
-		}
-
-		// Calculate header count based on what's shown
 		headerCount := len(visiblePkgs)
 		if m.showIgnored {
-			headerCount = len(visiblePkgs) + len(ignoredPkgs)
+			headerCount += len(ignoredPkgs)
+		}
+		// Categories with nothing to show get no header
+		if headerCount == 0 {
+			continue
 		}
 
-		// Add header
-		items = append(items, syncItem{
+		// In compact mode categories start folded and collapsed records the
+		// ones unfolded, so the same toggle works in both modes
+		folded := collapsed[t] != m.compact
+		items = append(items, groupItem{
 			isHeader:    true,
 			headerType:  t,
 			headerCount: headerCount,
+			collapsed:   folded,
 		})
+		if folded {
+			continue
+		}
 
 		// Add visible packages first
 		for _, pkg := range visiblePkgs {
-			items = append(items, syncItem{pkg: pkg, isIgnored: false})
+			items = append(items, groupItem{pkg: pkg, isIgnored: false})
 		}
 
 		// Add ignored packages if showing ignored
 		if m.showIgnored {
 			for _, pkg := range ignoredPkgs {
-				items = append(items, syncItem{pkg: pkg, isIgnored: true})
+				items = append(items, groupItem{pkg: pkg, isIgnored: true})
 			}
 		}
 	}
 	return items
 }
 
+// toggleCompact switches between listing every package and listing only the
+// headers of the categories with changes. Folds made in either mode are reset.
+func (m *SyncModel) toggleCompact() {
+	m.compact = !m.compact
+	m.addCollapsed, m.remCollapsed = nil, nil
+	m.addCursor, m.remCursor, m.addOffset, m.remOffset = 0, 0, 0, 0
+	m.buildItems()
+}
+
+// toggleCollapse folds or unfolds the category under the cursor in the
+// current column, leaving the cursor on its header
+func (m *SyncModel) toggleCollapse() {
+	if m.column == SyncColumnAdditions {
+		m.addCollapsed.toggleAt(&m.addItems, &m.addCursor, m.buildItems)
+		m.adjustAddOffset()
+	} else {
+		m.remCollapsed.toggleAt(&m.remItems, &m.remCursor, m.buildItems)
+		m.adjustRemOffset()
+	}
+}
+
+// onHeader reports whether the cursor is on a category header
+func (m *SyncModel) onHeader() bool {
+	if m.column == SyncColumnRemovals {
+		return onHeader(m.remItems, m.remCursor)
+	}
+	return onHeader(m.addItems, m.addCursor)
+}
+
 // Navigation methods
 func (m *SyncModel) moveUp() {
 	if m.column == SyncColumnAdditions {
@@ -595,16 +671,26 @@ func (m *SyncModel) renderPreview(width, height int) string {
 	b.WriteString(titleStyle.Render(fmt.Sprintf("Sync: %s → %s", m.source, m.config.CurrentMachine)))
 	b.WriteString("\n\n")
 
+	// Results of the sync just applied
+	if m.applied {
+		m.renderResults(&b)
+		b.WriteString("\n")
+	}
+
 	// No changes
 	if len(m.additions) == 0 && len(m.removals) == 0 {
 		b.WriteString(styles.SelectedStyle.Render("✓ "))
-		b.WriteString("Already in sync!")
+		if m.applied {
+			b.WriteString("Now in sync!")
+		} else {
+			b.WriteString("Already in sync!")
+		}
 
 		// Show ignored count
 		ignoredCount := len(m.allAdditions) - len(m.additions) + len(m.allRemovals) - len(m.removals)
 		if !m.showIgnored && ignoredCount > 0 {
 			b.WriteString("\n")
-			b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored changes, press H to show)", ignoredCount)))
+			b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("(%d ignored changes, press %s to show)", ignoredCount, toggleIgnoredKey)))
 		}
 
 		// Show protected
@@ -686,19 +772,14 @@ func (m *SyncModel) renderPreview(width, height int) string {
 	// Show protected packages if any
 	if len(m.protected) > 0 {
 		b.WriteString("\n")
-		b.WriteString(styles.WarningStyle.Render(fmt.Sprintf("⚠ %d protected packages will not be removed (machine-specific)", len(m.protected))))
+		b.WriteString(styles.WarningStyle.Render(fmt.Sprintf("⚠ %d protected packages will not be removed (machine-specific/pinned)", len(m.protected))))
 		b.WriteString("\n")
 	}
 
 	// Action bar
 	b.WriteString("\n")
-	if m.showConfirm {
-		confirmStyle := lipgloss.NewStyle().
-			Background(styles.CatSurface0).
-			Foreground(styles.CatYellow).
-			Padding(0, 1).
-			Bold(true)
-		b.WriteString(confirmStyle.Render(fmt.Sprintf("Apply %d changes? (y/n)", len(m.additions)+len(m.removals))))
+	if m.confirm.Active() {
+		b.WriteString(m.confirm.View())
 	} else {
 		actionStyle := lipgloss.NewStyle().Foreground(styles.CatSubtext0)
 		b.WriteString(actionStyle.Render("Press "))
@@ -716,7 +797,7 @@ func (m *SyncModel) renderPreview(width, height int) string {
 }
 
 func (m *SyncModel) renderColumn(
-	items []syncItem,
+	items []groupItem,
 	title string,
 	prefix string,
 	width int,
@@ -734,7 +815,7 @@ func (m *SyncModel) renderColumn(
 		headerStyle = headerStyle.Underline(true)
 	}
 	lines = append(lines, headerStyle.Render(title))
-	lines = append(lines, styles.DimmedStyle.Render(strings.Repeat("─", width-2)))
+	lines = append(lines, styles.DimmedStyle.Render(strings.Repeat("─", max(width-2, 0))))
 
 	if len(items) == 0 {
 		lines = append(lines, styles.DimmedStyle.Render("(none)"))
@@ -753,13 +834,12 @@ func (m *SyncModel) renderColumn(
 
 		if item.isHeader {
 			// Category header with icon
-			icon := getTypeIcon(item.headerType)
 			catStyle := styles.GetCategoryStyle(string(item.headerType)).Bold(true)
 			linePrefix := "  "
 			if isCursor {
 				linePrefix = styles.CursorStyle.Render("> ")
 			}
-			line := linePrefix + catStyle.Render(fmt.Sprintf("%s %s (%d)", icon, item.headerType, item.headerCount))
+			line := linePrefix + catStyle.Render(groupHeader(item.headerType, item.headerCount, item.collapsed))
 			lines = append(lines, line)
 		} else {
 			// Package line
@@ -770,10 +850,7 @@ func (m *SyncModel) renderColumn(
 
 			// Truncate name to fit column
 			maxNameLen := width - 12
-			name := item.pkg.Name
-			if len(name) > maxNameLen {
-				name = name[:maxNameLen-3] + "..."
-			}
+			name := truncate(item.pkg.Name, maxNameLen)
 
 			var line string
 			if item.isIgnored {
@@ -848,6 +925,16 @@ func (m *SyncModel) renderDone() string {
 	b.WriteString(titleStyle.Render(fmt.Sprintf("Sync: %s → %s", m.source, m.config.CurrentMachine)))
 	b.WriteString("\n\n")
 
+	m.renderResults(&b)
+
+	b.WriteString("\n")
+	b.WriteString(styles.DimmedStyle.Render("Press enter to continue"))
+
+	return b.String()
+}
+
+// renderResults writes the outcome of the last sync: counts, failures and caveats
+func (m *SyncModel) renderResults(b *strings.Builder) {
 	// Summary
 	if m.failed == 0 {
 		b.WriteString(styles.SelectedStyle.Render("✓ Sync complete!"))
@@ -881,16 +968,50 @@ func (m *SyncModel) renderDone() string {
 				if r.err != nil {
 					errMsg = ": " + r.err.Error()
 				}
+				if note := r.retryNote(); note != "" {
+					errMsg = " (" + note + ")" + errMsg
+				}
 				b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("  • %s:%s%s", r.pkg.Type, r.pkg.Name, errMsg)))
 				b.WriteString("\n")
 			}
 		}
 	}
 
-	b.WriteString("\n")
-	b.WriteString(styles.DimmedStyle.Render("Press enter to continue"))
+	// Show packages that only installed after retrying, a sign of a flaky network
+	retriedShown := false
+	for _, r := range m.results {
+		note := r.retryNote()
+		if !r.success || note == "" {
+			continue
+		}
+		if !retriedShown {
+			b.WriteString("\n")
+			b.WriteString(styles.WarningStyle.Render("Retried:"))
+			b.WriteString("\n")
+			retriedShown = true
+		}
+		b.WriteString(styles.DimmedStyle.Render(fmt.Sprintf("  • %s:%s %s", r.pkg.Type, r.pkg.Name, note)))
+		b.WriteString("\n")
+	}
 
-	return b.String()
+	// Show caveats so setup steps aren't missed
+	headerShown := false
+	for _, r := range m.results {
+		if r.caveats == "" {
+			continue
+		}
+		if !headerShown {
+			b.WriteString("\n")
+			b.WriteString(styles.WarningStyle.Render("Caveats:"))
+			b.WriteString("\n")
+			headerShown = true
+		}
+		b.WriteString(fmt.Sprintf("  %s:%s\n", r.pkg.Type, r.pkg.Name))
+		for _, line := range strings.Split(r.caveats, "\n") {
+			b.WriteString(styles.DimmedStyle.Render("    " + line))
+			b.WriteString("\n")
+		}
+	}
 }
 
 // filterPackages filters packages based on showIgnored setting
@@ -0,0 +1,233 @@
+package screens
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/config"
+)
+
+func TestSyncModel_CollapseGroup(t *testing.T) {
+	m := NewSyncModel(&config.Config{CurrentMachine: "mini"})
+	additions := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeTap, "user/tap"),
+		brewfile.NewPackage(brewfile.TypeBrew, "git"),
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+	}
+	m.Update(syncLoadedMsg{loadID: m.loadID, additions: additions})
+	assert.Len(t, m.addItems, 5)
+
+	// Folding the tap group leaves the brew group reachable right below it
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, m.addItems, 4)
+	assert.True(t, m.addItems[0].collapsed)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Equal(t, brewfile.TypeBrew, m.addItems[m.addCursor].headerType)
+	assert.Contains(t, m.ViewContent(100, 20), "tap (1) ▸")
+
+	// Enter on a package doesn't fold anything
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, m.addItems, 4)
+
+	// Folded groups are still applied
+	assert.Len(t, m.additions, 3)
+}
+
+// writeSyncMachines writes Brewfiles for mini and air and a config syncing
+// mini from air
+func writeSyncMachines(t *testing.T, mini, air string) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	miniBrewfile := filepath.Join(dir, "mini", "Brewfile")
+	airBrewfile := filepath.Join(dir, "air", "Brewfile")
+	require.NoError(t, os.MkdirAll(filepath.Dir(miniBrewfile), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(airBrewfile), 0755))
+	require.NoError(t, os.WriteFile(miniBrewfile, []byte(mini), 0644))
+	require.NoError(t, os.WriteFile(airBrewfile, []byte(air), 0644))
+	return &config.Config{
+		CurrentMachine: "mini",
+		DefaultSource:  "air",
+		Machines: map[string]config.Machine{
+			"mini": {Brewfile: miniBrewfile},
+			"air":  {Brewfile: airBrewfile},
+		},
+	}
+}
+
+func TestSyncModel_ReplansAfterApply(t *testing.T) {
+	cfg := writeSyncMachines(t, "brew \"git\"\nbrew \"wget\"\n", "brew \"git\"\nbrew \"jq\"\nbrew \"ripgrep\"\n")
+	m := NewSyncModel(cfg)
+	m.Init()
+	loaded := m.loadSync()
+	loaded.loadID = m.loadID
+	m.Update(loaded)
+	require.Equal(t, SyncPhasePreview, m.phase)
+	assert.Equal(t, []string{"brew:jq", "brew:ripgrep"}, m.additions.IDs())
+	assert.Equal(t, []string{"brew:wget"}, m.removals.IDs())
+
+	// The sync applies everything but ripgrep, recording it in the metadata as executeSync does
+	jq := brewfile.NewPackage(brewfile.TypeBrew, "jq")
+	ripgrep := brewfile.NewPackage(brewfile.TypeBrew, "ripgrep")
+	wget := brewfile.NewPackage(brewfile.TypeBrew, "wget")
+	require.NoError(t, brewfile.UpdateSyncMetadata(brewfile.MetadataPath(cfg.Machines["mini"].Brewfile), "air",
+		brewfile.Packages{jq}, brewfile.Packages{wget}))
+	_, cmd := m.Update(syncDoneMsg{installed: 1, removed: 1, failed: 1, results: []syncResult{
+		{pkg: jq, action: "installed", success: true},
+		{pkg: ripgrep, action: "installed", err: errors.New("download failed")},
+		{pkg: wget, action: "removed", success: true},
+	}})
+	require.NotNil(t, cmd)
+	assert.Equal(t, SyncPhaseLoading, m.phase, "re-plans instead of stopping at a done screen")
+
+	loaded = m.loadSync()
+	loaded.loadID = m.loadID
+	m.Update(loaded)
+	require.Equal(t, SyncPhasePreview, m.phase)
+	assert.Equal(t, []string{"brew:ripgrep"}, m.additions.IDs(), "only the failed install remains")
+	assert.Empty(t, m.removals)
+	view := m.ViewContent(100, 30)
+	assert.Contains(t, view, "Sync completed with errors")
+	assert.Contains(t, view, "download failed")
+
+	// Once the rest is applied, the preview shows the machine in sync
+	require.NoError(t, brewfile.UpdateSyncMetadata(brewfile.MetadataPath(cfg.Machines["mini"].Brewfile), "air",
+		brewfile.Packages{jq, ripgrep}, brewfile.Packages{wget}))
+	m.Update(syncDoneMsg{installed: 1, results: []syncResult{{pkg: ripgrep, action: "installed", success: true}}})
+	loaded = m.loadSync()
+	loaded.loadID = m.loadID
+	m.Update(loaded)
+	require.Equal(t, SyncPhasePreview, m.phase)
+	assert.Empty(t, m.additions)
+	assert.Empty(t, m.removals)
+	assert.Contains(t, m.ViewContent(100, 30), "Now in sync!")
+}
+
+// loadIgnoringConfig loads a config for mini whose ignore file hides the cask
+// category and brew:htop
+func loadIgnoringConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	ignoreFile := filepath.Join(dir, "ignore.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("current_machine: mini\n"), 0644))
+	require.NoError(t, os.WriteFile(ignoreFile, []byte(`machines:
+  mini:
+    categories: [cask]
+    packages:
+      brew: [htop]
+`), 0644))
+	config.SetConfigPath(configFile)
+	config.SetIgnorePath(ignoreFile)
+	t.Cleanup(func() {
+		config.SetConfigPath("")
+		config.SetIgnorePath("")
+	})
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	return cfg
+}
+
+func headerCounts(items []groupItem) map[brewfile.PackageType]int {
+	counts := make(map[brewfile.PackageType]int)
+	for _, item := range items {
+		if item.isHeader {
+			counts[item.headerType] = item.headerCount
+		}
+	}
+	return counts
+}
+
+func TestSyncModel_EmptyCategoriesOmitted(t *testing.T) {
+	m := NewSyncModel(loadIgnoringConfig(t))
+	m.Update(syncLoadedMsg{
+		loadID: m.loadID,
+		additions: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeBrew, "git"),
+			brewfile.NewPackage(brewfile.TypeBrew, "htop"),
+			brewfile.NewPackage(brewfile.TypeCask, "firefox"),
+		},
+		removals: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeCask, "slack"),
+		},
+	})
+
+	// Hidden ignored packages leave no header behind in either column
+	assert.Equal(t, map[brewfile.PackageType]int{brewfile.TypeBrew: 1}, headerCounts(m.addItems))
+	assert.Empty(t, m.remItems)
+
+	// Showing them brings back their categories with matching counts
+	m.Update(ShowIgnoredMsg{Show: true})
+	assert.Equal(t, map[brewfile.PackageType]int{brewfile.TypeBrew: 2, brewfile.TypeCask: 1}, headerCounts(m.addItems))
+	assert.Equal(t, map[brewfile.PackageType]int{brewfile.TypeCask: 1}, headerCounts(m.remItems))
+	assert.Len(t, m.addItems, 5)
+	assert.Len(t, m.remItems, 2)
+
+	m.Update(ShowIgnoredMsg{Show: false})
+	assert.Equal(t, map[brewfile.PackageType]int{brewfile.TypeBrew: 1}, headerCounts(m.addItems))
+	assert.Empty(t, m.remItems)
+}
+
+func TestSyncModel_Compact(t *testing.T) {
+	m := NewSyncModel(&config.Config{CurrentMachine: "mini"})
+	m.Update(syncLoadedMsg{
+		loadID: m.loadID,
+		additions: brewfile.Packages{
+			brewfile.NewPackage(brewfile.TypeTap, "user/tap"),
+			brewfile.NewPackage(brewfile.TypeBrew, "git"),
+			brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		},
+		removals: brewfile.Packages{brewfile.NewPackage(brewfile.TypeCask, "slack")},
+	})
+	require.Len(t, m.addItems, 5)
+
+	// Compact mode lists only the headers of categories with changes
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	require.Len(t, m.addItems, 2)
+	assert.Len(t, m.remItems, 1)
+	for _, item := range append(m.addItems, m.remItems...) {
+		assert.True(t, item.isHeader)
+		assert.True(t, item.collapsed)
+	}
+	view := m.ViewContent(100, 20)
+	assert.Contains(t, view, "brew (2) ▸")
+	assert.NotContains(t, view, "jq")
+
+	// A category can still be unfolded to look inside
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Len(t, m.addItems, 4)
+	assert.False(t, m.addItems[1].collapsed)
+
+	// Leaving compact mode lists everything again
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	assert.Len(t, m.addItems, 5)
+	assert.Len(t, m.remItems, 2)
+	assert.Len(t, m.additions, 3, "folded categories are still applied")
+}
+
+func TestSyncModel_ResultsShowRetries(t *testing.T) {
+	m := NewSyncModel(&config.Config{CurrentMachine: "mini"})
+	m.installed, m.failed = 2, 1
+	m.results = []syncResult{
+		{pkg: brewfile.NewPackage(brewfile.TypeBrew, "git"), action: "installed", success: true, attempts: 1},
+		{pkg: brewfile.NewPackage(brewfile.TypeBrew, "jq"), action: "installed", success: true, attempts: 3},
+		{pkg: brewfile.NewPackage(brewfile.TypeCask, "docker"), action: "installed", err: errors.New("download failed"), attempts: 2},
+	}
+
+	var b strings.Builder
+	m.renderResults(&b)
+	view := b.String()
+	assert.Contains(t, view, "brew:jq installed after 2 retries")
+	assert.Contains(t, view, "cask:docker (failed after 1 retry): download failed")
+	assert.NotContains(t, view, "brew:git installed after")
+}
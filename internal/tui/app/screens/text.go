@@ -0,0 +1,41 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ellipsis marks truncated text
+const ellipsis = "..."
+
+// truncate shortens s to at most max terminal columns, ending with "..." when cut.
+// It counts display width (wide CJK/emoji runes take two columns), never splits
+// a rune, and is safe for tiny or negative widths.
+func truncate(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	if runewidth.StringWidth(s) <= max {
+		return s
+	}
+
+	// Too narrow for an ellipsis: just cut
+	tail := ellipsis
+	if max <= len(ellipsis) {
+		tail = ""
+	}
+	limit := max - len(tail)
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		w := runewidth.RuneWidth(r)
+		if width+w > limit {
+			break
+		}
+		b.WriteRune(r)
+		width += w
+	}
+	return b.String() + tail
+}
@@ -0,0 +1,41 @@
+package screens
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		max   int
+		want  string
+	}{
+		{"fits", "git", 10, "git"},
+		{"exact fit", "ripgrep", 7, "ripgrep"},
+		{"ascii cut", "visual-studio-code", 10, "visual-..."},
+		{"multi-byte runes", "café-über-app", 8, "café-..."},
+		{"wide runes count double", "日本語エディタ", 9, "日本語..."},
+		{"width five", "ms-ceintl.vscode-language-pack-ja", 5, "ms..."},
+		{"width five multi-byte", "ñandú-tools", 5, "ña..."},
+		{"narrower than ellipsis", "postgresql", 3, "pos"},
+		{"wide rune never split", "日本", 3, "日"},
+		{"zero width", "git", 0, ""},
+		{"negative width", "git", -4, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.input, tt.max)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, utf8.ValidString(got))
+			if tt.max > 0 {
+				assert.LessOrEqual(t, runewidth.StringWidth(got), tt.max)
+			}
+		})
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 
 	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/installer"
 	"github.com/asamgx/brewsync/internal/tui/styles"
 )
 
@@ -33,6 +35,11 @@ type InstallMsg struct {
 	Error   error
 }
 
+// InstallStartMsg is sent when an install starts, when installing in parallel
+type InstallStartMsg struct {
+	Package brewfile.Package
+}
+
 // OutputLineMsg is sent when a line of output is received from the installer
 type OutputLineMsg struct {
 	Package brewfile.Package
@@ -64,6 +71,18 @@ type Model struct {
 	outputLines     []string          // Recent output lines
 	maxOutputLines  int               // Max lines to keep
 	currentPkg      *brewfile.Package // Current package being installed
+
+	// Parallel installs (see SetParallel)
+	parallel int
+	events   chan tea.Msg     // Messages from the installs running in the background
+	stop     chan struct{}    // Closed when the model quits, so the installs stop sending
+	running  []runningInstall // Installs in progress, in the order they started
+}
+
+// runningInstall is an install in progress and the last line it printed
+type runningInstall struct {
+	pkg  brewfile.Package
+	line string
 }
 
 // New creates a new progress model
@@ -112,8 +131,35 @@ func NewWithOutput(title string, packages brewfile.Packages, installOutputFn Ins
 	}
 }
 
+// SetParallel makes the model install up to n packages at once, scheduled as
+// installer.InstallConcurrently does, with a line for each install in
+// progress. Below 2, packages are installed one by one.
+func (m *Model) SetParallel(n int) {
+	m.parallel = n
+	if n > 1 {
+		m.events = make(chan tea.Msg, 64)
+		m.stop = make(chan struct{})
+	}
+}
+
+// quit stops the installs running in the background from sending further
+// messages, which nothing would receive, and quits
+func (m Model) quit() tea.Cmd {
+	if m.stop != nil {
+		select {
+		case <-m.stop:
+		default:
+			close(m.stop)
+		}
+	}
+	return tea.Quit
+}
+
 // Init starts the installation process
 func (m Model) Init() tea.Cmd {
+	if m.parallel > 1 {
+		return tea.Batch(m.spinner.Tick, m.installParallel())
+	}
 	return tea.Batch(
 		m.spinner.Tick,
 		m.installNext(),
@@ -135,7 +181,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
-			return m, tea.Quit
+			return m, m.quit()
 		}
 
 	case spinner.TickMsg:
@@ -143,7 +189,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	case InstallStartMsg:
+		m.running = append(m.running, runningInstall{pkg: msg.Package})
+		return m, m.nextEvent()
+
 	case OutputLineMsg:
+		if m.parallel > 1 {
+			for i := range m.running {
+				if m.running[i].pkg.ID() == msg.Package.ID() {
+					m.running[i].line = msg.Line
+				}
+			}
+			return m, m.nextEvent()
+		}
+
 		// Add line to output buffer
 		m.outputLines = append(m.outputLines, msg.Line)
 
@@ -166,6 +225,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.installed++
 		}
 
+		if m.parallel > 1 {
+			for i := range m.running {
+				if m.running[i].pkg.ID() == msg.Package.ID() {
+					m.running = append(m.running[:i], m.running[i+1:]...)
+					break
+				}
+			}
+			m.current++
+			if m.current >= len(m.packages) {
+				m.done = true
+				return m, tea.Quit
+			}
+			return m, m.nextEvent()
+		}
+
 		m.current = msg.Index + 1
 		m.currentPkg = nil
 		m.outputLines = []string{} // Clear output for next package
@@ -214,6 +288,57 @@ func (m *Model) installNext() tea.Cmd {
 	}
 }
 
+// installParallel starts installing every package in the background and
+// returns a command delivering the first of the messages the installs send
+func (m *Model) installParallel() tea.Cmd {
+	events, stop := m.events, m.stop
+	packages := m.packages
+	parallel := m.parallel
+	installFn, installOutputFn := m.installFn, m.installOutputFn
+	install := func(pkg brewfile.Package, onOutput func(line string)) error {
+		if installOutputFn != nil {
+			return installOutputFn(pkg, onOutput)
+		}
+		return installFn(pkg)
+	}
+
+	// send delivers a message unless the model has quit, returning false then
+	send := func(msg tea.Msg) bool {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		select {
+		case events <- msg:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	go installer.InstallConcurrently(packages, parallel, func(pkg brewfile.Package) {
+		if !send(InstallStartMsg{Package: pkg}) {
+			return
+		}
+		err := install(pkg, func(line string) {
+			if line = strings.TrimSpace(line); line != "" {
+				send(OutputLineMsg{Package: pkg, Line: line})
+			}
+		})
+		send(InstallMsg{Package: pkg, Total: len(packages), Error: err})
+	})
+	return m.nextEvent()
+}
+
+// nextEvent returns a command waiting for the next message from the installs
+// running in the background
+func (m *Model) nextEvent() tea.Cmd {
+	events := m.events
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
 // streamingInstall performs installation with output capture (for future use)
 func (m *Model) streamingInstall(pkg brewfile.Package, idx, total int) tea.Cmd {
 	return func() tea.Msg {
@@ -248,7 +373,9 @@ func (m Model) View() string {
 	b.WriteString("\n\n")
 
 	// Current status
-	if !m.done && m.current < len(m.packages) {
+	if m.parallel > 1 {
+		m.renderRunning(&b)
+	} else if !m.done && m.current < len(m.packages) {
 		pkg := m.packages[m.current]
 		b.WriteString(m.spinner.View())
 		b.WriteString(" Installing ")
@@ -296,6 +423,27 @@ func (m Model) View() string {
 	return b.String()
 }
 
+// renderRunning writes a line for each install in progress, with the last
+// line of output it printed
+func (m Model) renderRunning(b *strings.Builder) {
+	if m.done {
+		return
+	}
+	b.WriteString(fmt.Sprintf("Installing %d at once (%d/%d done)\n", len(m.running), m.current, len(m.packages)))
+	for _, r := range m.running {
+		b.WriteString(m.spinner.View())
+		b.WriteString(" ")
+		b.WriteString(styles.GetCategoryStyle(string(r.pkg.Type)).Render(string(r.pkg.Type)))
+		b.WriteString(": ")
+		b.WriteString(lipgloss.NewStyle().Bold(true).Render(r.pkg.Name))
+		if r.line != "" {
+			b.WriteString("  ")
+			b.WriteString(styles.DimmedStyle.Render(runewidth.Truncate(r.line, max(m.width-runewidth.StringWidth(r.pkg.ID())-8, 10), "…")))
+		}
+		b.WriteString("\n")
+	}
+}
+
 // Results returns the installation results
 func (m Model) Results() []InstallResult {
 	return m.results
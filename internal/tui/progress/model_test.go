@@ -0,0 +1,115 @@
+package progress
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+)
+
+func TestModel_Parallel(t *testing.T) {
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeBrew, "fd"),
+		brewfile.NewPackage(brewfile.TypeBrew, "broken"),
+		brewfile.NewPackage(brewfile.TypeNpm, "typescript"),
+	}
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	release := make(chan struct{})
+	m := NewWithOutput("Installing", pkgs, func(pkg brewfile.Package, onOutput func(line string)) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		onOutput("==> Downloading " + pkg.Name)
+		<-release
+		if pkg.Name == "broken" {
+			return errors.New("download failed")
+		}
+		return nil
+	})
+	m.SetParallel(2)
+
+	cmd := m.installParallel()
+	sawTwoRunning := false
+	for !m.Done() {
+		msg := cmd()
+		if _, ok := msg.(OutputLineMsg); ok && len(m.running) == 2 && !sawTwoRunning {
+			sawTwoRunning = true
+			view := m.View()
+			assert.Contains(t, view, "Installing 2 at once")
+			close(release)
+		}
+		model, next := m.Update(msg)
+		m = model.(Model)
+		cmd = next
+		if m.Done() {
+			break
+		}
+		require.NotNil(t, cmd)
+	}
+
+	assert.True(t, sawTwoRunning)
+	assert.Equal(t, 2, peak)
+	assert.Equal(t, 3, m.Installed())
+	assert.Equal(t, 1, m.Failed())
+	assert.Len(t, m.Results(), 4)
+	assert.Empty(t, m.running)
+}
+
+func TestModel_ParallelQuit(t *testing.T) {
+	pkgs := brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+		brewfile.NewPackage(brewfile.TypeBrew, "fd"),
+		brewfile.NewPackage(brewfile.TypeBrew, "ripgrep"),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	started, release := make(chan struct{}, len(pkgs)), make(chan struct{})
+	m := NewWithOutput("Installing", pkgs, func(pkg brewfile.Package, onOutput func(line string)) error {
+		defer wg.Done()
+		started <- struct{}{}
+		<-release
+		// More output than the event buffer holds, with nothing reading it
+		for range 100 {
+			onOutput("==> Pouring " + pkg.Name)
+		}
+		return nil
+	})
+	m.SetParallel(2)
+	m.installParallel()
+	<-started
+	<-started
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+	close(release)
+
+	// Once quit, the running installs finish instead of blocking on a send,
+	// and the third never starts
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("installs still blocked after quit")
+	}
+}
@@ -27,6 +27,7 @@ type KeyMap struct {
 	TabAntigravity  key.Binding
 	TabGo           key.Binding
 	TabMas          key.Binding
+	TabNpm          key.Binding
 	TabAll          key.Binding
 	Help            key.Binding
 	PageUp          key.Binding
@@ -128,6 +129,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("8"),
 			key.WithHelp("8", "mas"),
 		),
+		TabNpm: key.NewBinding(
+			key.WithKeys("9"),
+			key.WithHelp("9", "npm"),
+		),
 		TabAll: key.NewBinding(
 			key.WithKeys("0"),
 			key.WithHelp("0", "all"),
@@ -158,7 +163,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down, k.Left, k.Right, k.PageUp, k.PageDown},
 		{k.Toggle, k.SelectAll, k.SelectNone, k.Ignore, k.IgnoreCategory, k.ToggleShowIgnored},
 		{k.TabAll, k.TabTap, k.TabBrew, k.TabCask, k.TabVSCode},
-		{k.TabCursor, k.TabAntigravity, k.TabGo, k.TabMas},
+		{k.TabCursor, k.TabAntigravity, k.TabGo, k.TabMas, k.TabNpm},
 		{k.Search, k.Confirm, k.Quit, k.Help},
 	}
 }
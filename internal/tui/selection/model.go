@@ -25,6 +25,8 @@ const (
 	CategoryAntigravity Category = "antigravity"
 	CategoryGo          Category = "go"
 	CategoryMas         Category = "mas"
+	CategoryNpm         Category = "npm"
+	CategoryPipx        Category = "pipx"
 )
 
 // AllCategories returns all available categories in order
@@ -39,6 +41,8 @@ func AllCategories() []Category {
 		CategoryAntigravity,
 		CategoryGo,
 		CategoryMas,
+		CategoryNpm,
+		CategoryPipx,
 	}
 }
 
@@ -54,6 +58,11 @@ func (i Item) FilterValue() string {
 	return i.Package.Name
 }
 
+// tally counts the items of one category. selected excludes ignored items.
+type tally struct {
+	total, selected, ignored int
+}
+
 // Model is the Bubble Tea model for package selection
 type Model struct {
 	title             string
@@ -62,7 +71,8 @@ type Model struct {
 	category          Category
 	searching         bool
 	searchText        textinput.Model
-	filtered          []int // indices into items that match current filter
+	filtered          []int         // indices into items that match current filter
+	matched           map[int][]int // byte offsets in each filtered item's name matching the search
 	keys              KeyMap
 	help              help.Model
 	showHelp          bool
@@ -72,6 +82,10 @@ type Model struct {
 	cancelled         bool
 	confirmed         bool
 	ignoredCategories map[string]bool // Track categories marked for ignoring
+
+	// counts is kept up to date by setState so rendering a frame doesn't walk
+	// every item; lists can hold thousands of editor extensions
+	counts map[Category]tally
 }
 
 // New creates a new selection model
@@ -103,8 +117,12 @@ func New(title string, packages brewfile.Packages) Model {
 		width:             80,
 		height:            24,
 		ignoredCategories: make(map[string]bool),
+		counts:            make(map[Category]tally),
 	}
 
+	for _, item := range items {
+		m.count(item, 1)
+	}
 	m.updateFiltered()
 	return m
 }
@@ -114,7 +132,7 @@ func (m *Model) SetIgnored(ignored map[string]bool) {
 	for i := range m.items {
 		key := m.items[i].Package.ID()
 		if ignored[key] {
-			m.items[i].Ignored = true
+			m.setState(i, m.items[i].Selected, true)
 		}
 	}
 }
@@ -124,7 +142,7 @@ func (m *Model) SetSelected(selected map[string]bool) {
 	for i := range m.items {
 		key := m.items[i].Package.ID()
 		if selected[key] {
-			m.items[i].Selected = true
+			m.setState(i, true, m.items[i].Ignored)
 		}
 	}
 }
@@ -245,6 +263,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.setCategory(CategoryGo)
 		case key.Matches(msg, m.keys.TabMas):
 			m.setCategory(CategoryMas)
+		case key.Matches(msg, m.keys.TabNpm):
+			m.setCategory(CategoryNpm)
 		}
 	}
 
@@ -300,6 +320,7 @@ func (m Model) Confirmed() bool {
 // updateFiltered updates the filtered list based on category, search, and showIgnored
 func (m *Model) updateFiltered() {
 	m.filtered = nil
+	m.matched = nil
 
 	// First filter by category and ignored state
 	var categoryFiltered []int
@@ -328,8 +349,11 @@ func (m *Model) updateFiltered() {
 
 	// Fuzzy search
 	matches := fuzzy.Find(searchTerm, names)
+	m.matched = make(map[int][]int, len(matches))
 	for _, match := range matches {
-		m.filtered = append(m.filtered, categoryFiltered[match.Index])
+		idx := categoryFiltered[match.Index]
+		m.filtered = append(m.filtered, idx)
+		m.matched[idx] = match.MatchedIndexes
 	}
 }
 
@@ -356,7 +380,7 @@ func (m *Model) toggleCurrent() {
 
 	idx := m.filtered[m.cursor]
 	if !m.items[idx].Ignored {
-		m.items[idx].Selected = !m.items[idx].Selected
+		m.setState(idx, !m.items[idx].Selected, false)
 	}
 }
 
@@ -367,10 +391,8 @@ func (m *Model) toggleIgnoreCurrent() {
 	}
 
 	idx := m.filtered[m.cursor]
-	m.items[idx].Ignored = !m.items[idx].Ignored
-	if m.items[idx].Ignored {
-		m.items[idx].Selected = false
-	}
+	ignored := !m.items[idx].Ignored
+	m.setState(idx, m.items[idx].Selected && !ignored, ignored)
 }
 
 // toggleIgnoreCurrentCategory toggles ignoring the entire current category
@@ -392,10 +414,8 @@ func (m *Model) toggleIgnoreCurrentCategory() {
 	// Update all packages of this category
 	for i := range m.items {
 		if string(m.items[i].Package.Type) == categoryType {
-			m.items[i].Ignored = m.ignoredCategories[categoryType]
-			if m.items[i].Ignored {
-				m.items[i].Selected = false
-			}
+			ignored := m.ignoredCategories[categoryType]
+			m.setState(i, m.items[i].Selected && !ignored, ignored)
 		}
 	}
 }
@@ -404,7 +424,7 @@ func (m *Model) toggleIgnoreCurrentCategory() {
 func (m *Model) selectAllVisible(selected bool) {
 	for _, idx := range m.filtered {
 		if !m.items[idx].Ignored {
-			m.items[idx].Selected = selected
+			m.setState(idx, selected, false)
 		}
 	}
 }
@@ -444,27 +464,29 @@ func (m *Model) nextCategory() {
 	}
 }
 
-// countByCategory returns counts of items by category
-func (m *Model) countByCategory() map[Category]struct{ total, selected int } {
-	counts := make(map[Category]struct{ total, selected int })
-
-	for _, item := range m.items {
-		cat := Category(item.Package.Type)
-		c := counts[cat]
-		c.total++
-		if item.Selected && !item.Ignored {
-			c.selected++
-		}
-		counts[cat] = c
+// setState updates an item's selected and ignored flags and the counts
+func (m *Model) setState(idx int, selected, ignored bool) {
+	m.count(m.items[idx], -1)
+	m.items[idx].Selected = selected
+	m.items[idx].Ignored = ignored
+	m.count(m.items[idx], 1)
+}
 
-		// Also count in "all"
-		c = counts[CategoryAll]
-		c.total++
-		if item.Selected && !item.Ignored {
-			c.selected++
+// count adds (sign 1) or removes (sign -1) item from its category and "all"
+func (m *Model) count(item Item, sign int) {
+	for _, cat := range []Category{Category(item.Package.Type), CategoryAll} {
+		c := m.counts[cat]
+		c.total += sign
+		if item.Ignored {
+			c.ignored += sign
+		} else if item.Selected {
+			c.selected += sign
 		}
-		counts[CategoryAll] = c
+		m.counts[cat] = c
 	}
+}
 
-	return counts
+// countByCategory returns counts of items by category, including CategoryAll
+func (m *Model) countByCategory() map[Category]tally {
+	return m.counts
 }
@@ -0,0 +1,154 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/asamgx/brewsync/internal/brewfile"
+	"github.com/asamgx/brewsync/internal/brewfile/brewfiletest"
+)
+
+// recount computes the category counts from scratch
+func recount(m Model) map[Category]tally {
+	counts := make(map[Category]tally)
+	for _, item := range m.items {
+		for _, cat := range []Category{Category(item.Package.Type), CategoryAll} {
+			c := counts[cat]
+			c.total++
+			if item.Ignored {
+				c.ignored++
+			} else if item.Selected {
+				c.selected++
+			}
+			counts[cat] = c
+		}
+	}
+	return counts
+}
+
+func keyMsg(s string) tea.KeyMsg {
+	if s == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModel_CountsTrackChanges(t *testing.T) {
+	pkgs := brewfiletest.ManyPackages(30)
+	m := New("Import", pkgs)
+	m.SetSelected(map[string]bool{pkgs[0].ID(): true, pkgs[1].ID(): true, pkgs[2].ID(): true})
+	m.SetIgnored(map[string]bool{pkgs[1].ID(): true})
+	assert.Equal(t, recount(m), m.counts)
+
+	var tm tea.Model = m
+	for _, k := range []string{" ", "j", "i", "a", "j", " ", "n", "3", "I", "H", "i", "a"} {
+		tm, _ = tm.Update(keyMsg(k))
+		assert.Equal(t, recount(tm.(Model)), tm.(Model).counts, "after %q", k)
+	}
+
+	m = tm.(Model)
+	all := m.counts[CategoryAll]
+	assert.Equal(t, 30, all.total)
+	assert.Len(t, m.Selected(), all.selected)
+	assert.Len(t, m.Ignored(), all.ignored)
+}
+
+func BenchmarkModel_Navigate(b *testing.B) {
+	for _, n := range []int{500, 5000} {
+		b.Run(fmt.Sprintf("%d items", n), func(b *testing.B) {
+			var m tea.Model = New("Import", brewfiletest.ManyPackages(n))
+			m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			down := keyMsg("j")
+			toggle := keyMsg(" ")
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m, _ = m.Update(down)
+				m, _ = m.Update(toggle)
+				_ = m.View()
+			}
+		})
+	}
+}
+
+func TestModel_SameNameAcrossTypes(t *testing.T) {
+	formula := brewfile.NewPackage(brewfile.TypeBrew, "docker")
+	cask := brewfile.NewPackage(brewfile.TypeCask, "docker")
+	m := New("Import", brewfile.Packages{formula, cask, brewfile.NewPackage(brewfile.TypeBrew, "jq")})
+	m.SetIgnored(map[string]bool{cask.ID(): true})
+	m.showIgnored = true
+	m.updateFiltered()
+
+	var tm tea.Model = m
+	for _, k := range []string{"/", "d", "o", "c", "k", "e", "r", "enter"} {
+		if k == "enter" {
+			tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			continue
+		}
+		tm, _ = tm.Update(keyMsg(k))
+	}
+	m = tm.(Model)
+
+	// Both packages match the search and stay separate items
+	require.Len(t, m.filtered, 2)
+	var found []string
+	for _, idx := range m.filtered {
+		found = append(found, m.items[idx].Package.ID())
+	}
+	assert.ElementsMatch(t, []string{"brew:docker", "cask:docker"}, found)
+
+	// Ignoring the cask leaves the formula selectable on its own
+	m.selectAllVisible(true)
+	assert.Equal(t, []string{"brew:docker"}, m.Selected().IDs())
+	assert.Equal(t, []string{"cask:docker"}, m.Ignored().IDs())
+}
+
+func TestModel_SearchHighlightsMatches(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	m := New("Import", brewfile.Packages{
+		brewfile.NewPackage(brewfile.TypeBrew, "ripgrep"),
+		brewfile.NewPackage(brewfile.TypeBrew, "jq"),
+	})
+	var tm tea.Model = m
+	for _, k := range []string{"/", "r", "g"} {
+		tm, _ = tm.Update(keyMsg(k))
+	}
+	m = tm.(Model)
+
+	require.Len(t, m.filtered, 1)
+	idx := m.filtered[0]
+	assert.Equal(t, []int{0, 3}, m.matched[idx])
+
+	plain := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	match := plain.Bold(true).Underline(true)
+	assert.Equal(t,
+		match.Render("r")+plain.Render("ip")+match.Render("g")+plain.Render("rep"),
+		highlightMatches("ripgrep", m.matched[idx], plain))
+
+	// The matched characters are bold and underlined in the rendered row
+	row := m.renderItem(m.items[idx], false, m.matched[idx])
+	assert.Contains(t, row, lipgloss.NewStyle().Bold(true).Underline(true).Render("r"))
+	assert.Contains(t, row, "ip")
+
+	// Matches cut off a long name don't highlight the "..."
+	long := brewfile.NewPackage(brewfile.TypeBrew, strings.Repeat("a", 47)+"xyz-tail")
+	row = m.renderItem(Item{Package: long}, false, []int{0, 47, 48, 49})
+	assert.Contains(t, row, lipgloss.NewStyle().Render("..."))
+	assert.NotContains(t, row, lipgloss.NewStyle().Bold(true).Underline(true).Render("..."))
+	assert.NotContains(t, row, lipgloss.NewStyle().Bold(true).Underline(true).Render("."))
+
+	// Clearing the search drops the highlights
+	m.searchText.SetValue("")
+	m.updateFiltered()
+	assert.Nil(t, m.matched)
+}
@@ -2,6 +2,7 @@ package selection
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -165,7 +166,7 @@ func (m Model) renderList() string {
 	for i := start; i < end; i++ {
 		idx := m.filtered[i]
 		item := m.items[idx]
-		lines = append(lines, m.renderItem(item, i == m.cursor))
+		lines = append(lines, m.renderItem(item, i == m.cursor, m.matched[idx]))
 	}
 
 	// Show scroll indicator at bottom
@@ -176,8 +177,9 @@ func (m Model) renderList() string {
 	return strings.Join(lines, "\n")
 }
 
-// renderItem renders a single package item
-func (m Model) renderItem(item Item, isCursor bool) string {
+// renderItem renders a single package item, highlighting the characters of
+// its name at the matched byte offsets
+func (m Model) renderItem(item Item, isCursor bool, matched []int) string {
 	var b strings.Builder
 
 	// Cursor
@@ -207,33 +209,70 @@ func (m Model) renderItem(item Item, isCursor bool) string {
 	name := item.Package.Name
 	if len(name) > 50 {
 		name = name[:47] + "..."
+		// Matches in the cut-off part mustn't highlight the "..."
+		matched = slices.DeleteFunc(slices.Clone(matched), func(offset int) bool {
+			return offset >= 47
+		})
 	}
 
+	nameStyle := lipgloss.NewStyle()
 	if item.Ignored {
-		b.WriteString(styles.IgnoredStyle.Render(name))
+		nameStyle = styles.IgnoredStyle
 	} else if isCursor {
-		b.WriteString(styles.CursorStyle.Render(name))
+		nameStyle = styles.CursorStyle
 	} else if item.Selected {
-		b.WriteString(styles.SelectedStyle.Render(name))
-	} else {
-		b.WriteString(name)
+		nameStyle = styles.SelectedStyle
 	}
+	b.WriteString(highlightMatches(name, matched, nameStyle))
 
 	return b.String()
 }
 
-// renderStatus renders the status line
-func (m Model) renderStatus() string {
-	selected := 0
-	ignored := 0
-	for _, item := range m.items {
-		if item.Selected && !item.Ignored {
-			selected++
+// highlightMatches renders name in style, with the characters starting at
+// the matched byte offsets also bold and underlined. Offsets past the end of
+// a truncated name are ignored.
+func highlightMatches(name string, matched []int, style lipgloss.Style) string {
+	if len(matched) == 0 {
+		return style.Render(name)
+	}
+
+	isMatch := make(map[int]bool, len(matched))
+	for _, offset := range matched {
+		isMatch[offset] = true
+	}
+	matchStyle := style.Bold(true).Underline(true)
+
+	// Render runs of matched and unmatched characters as one piece each
+	var b strings.Builder
+	var run strings.Builder
+	runMatched := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
 		}
-		if item.Ignored {
-			ignored++
+		if runMatched {
+			b.WriteString(matchStyle.Render(run.String()))
+		} else {
+			b.WriteString(style.Render(run.String()))
 		}
+		run.Reset()
 	}
+	for offset, r := range name {
+		if isMatch[offset] != runMatched {
+			flush()
+			runMatched = isMatch[offset]
+		}
+		run.WriteRune(r)
+	}
+	flush()
+
+	return b.String()
+}
+
+// renderStatus renders the status line
+func (m Model) renderStatus() string {
+	all := m.counts[CategoryAll]
+	selected, ignored := all.selected, all.ignored
 
 	status := fmt.Sprintf("Selected: %d | Ignored: %d | Total: %d",
 		selected, ignored, len(m.items))
@@ -190,6 +190,8 @@ var CategoryColors = map[string]lipgloss.Color{
 	"cursor":      lipgloss.Color("135"), // Light purple
 	"antigravity": lipgloss.Color("205"), // Pink
 	"go":          lipgloss.Color("39"),  // Cyan
+	"npm":         lipgloss.Color("167"), // Salmon
+	"pipx":        lipgloss.Color("220"), // Gold
 	"mas":         lipgloss.Color("196"), // Red
 }
 